@@ -0,0 +1,146 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package antiquary
+
+import (
+	"context"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/lightclient_utils"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/persistence/beacon_indicies"
+	"github.com/erigontech/erigon/cl/phase1/core/state"
+	"github.com/erigontech/erigon/turbo/snapshotsync/freezeblocks"
+)
+
+// lightClientUpdatesCollector produces the best light client update of each sync committee period while the
+// historical states are replayed, so that the light clients can sync from genesis.
+type lightClientUpdatesCollector struct {
+	cfg    *clparams.BeaconChainConfig
+	reader freezeblocks.BeaconSnapshotReader
+
+	attestedBlock  *cltypes.SignedBeaconBlock // the last processed block, parent of the next one
+	finalizedRoot  common.Hash
+	finalizedBlock *cltypes.SignedBeaconBlock
+	updates        map[uint64]*cltypes.LightClientUpdate
+}
+
+func newLightClientUpdatesCollector(cfg *clparams.BeaconChainConfig, reader freezeblocks.BeaconSnapshotReader) *lightClientUpdatesCollector {
+	return &lightClientUpdatesCollector{
+		cfg:     cfg,
+		reader:  reader,
+		updates: make(map[uint64]*cltypes.LightClientUpdate),
+	}
+}
+
+// collect creates the update signed by block, attestedState must be the state right after its parent.
+func (l *lightClientUpdatesCollector) collect(ctx context.Context, tx kv.Tx, attestedState *state.CachingBeaconState, block *cltypes.SignedBeaconBlock) error {
+	attestedBlock := l.attestedBlock
+	l.attestedBlock = block
+	if block.Version() < clparams.AltairVersion || attestedState.Version() < clparams.AltairVersion {
+		return nil
+	}
+	var err error
+	if attestedBlock == nil || attestedBlock.Block.Slot != attestedState.LatestBlockHeader().Slot {
+		if attestedBlock, err = l.reader.ReadBlockByRoot(ctx, tx, block.Block.ParentRoot); err != nil {
+			return err
+		}
+		if attestedBlock == nil {
+			return nil
+		}
+	}
+	if attestedBlock.Version() < clparams.AltairVersion {
+		return nil
+	}
+
+	period := l.cfg.SyncCommitteePeriod(attestedState.Slot())
+	if _, ok := l.updates[period]; !ok {
+		// start from the update stored by a previous run
+		if l.updates[period], err = beacon_indicies.ReadLightClientUpdate(tx, period); err != nil {
+			return err
+		}
+	}
+	if best := l.updates[period]; best != nil && !l.mayBeBetter(best, block) {
+		return nil
+	}
+
+	finalizedCheckpoint := attestedState.FinalizedCheckpoint()
+	if l.finalizedRoot != finalizedCheckpoint.Root {
+		if l.finalizedBlock, err = l.reader.ReadBlockByRoot(ctx, tx, finalizedCheckpoint.Root); err != nil {
+			return err
+		}
+		l.finalizedRoot = finalizedCheckpoint.Root
+	}
+	nextSyncCommitteeBranch, err := attestedState.NextSyncCommitteeBranch()
+	if err != nil {
+		return err
+	}
+	finalityBranch, err := attestedState.FinalityRootBranch()
+	if err != nil {
+		return err
+	}
+	update, err := lightclient_utils.CreateLightClientUpdate(l.cfg, block, l.finalizedBlock, attestedBlock, attestedState.Slot(),
+		attestedState.NextSyncCommittee().Copy(), finalizedCheckpoint, toHashVector(nextSyncCommitteeBranch), toHashVector(finalityBranch))
+	if err != nil {
+		// not enough participants, the block is simply not usable by light clients
+		return nil
+	}
+	l.updates[period] = lightclient_utils.BestLightClientUpdate(l.cfg, l.updates[period], update)
+	return nil
+}
+
+// mayBeBetter tells whether an update signed by block could beat best, without computing the proofs: once best has
+// every property looked at by the light clients, a later update only wins with more participants.
+func (l *lightClientUpdatesCollector) mayBeBetter(best *cltypes.LightClientUpdate, block *cltypes.SignedBeaconBlock) bool {
+	attestedPeriod := l.cfg.SyncCommitteePeriod(best.AttestedHeader.Beacon.Slot)
+	complete := best.IsSyncCommitteeUpdate() && best.IsFinalityUpdate() &&
+		attestedPeriod == l.cfg.SyncCommitteePeriod(best.SignatureSlot) &&
+		attestedPeriod == l.cfg.SyncCommitteePeriod(best.FinalizedHeader.Beacon.Slot)
+	return !complete || block.Block.Body.SyncAggregate.Sum() > best.SyncAggregate.Sum()
+}
+
+// flush writes the collected updates which are better than the stored ones.
+func (l *lightClientUpdatesCollector) flush(tx kv.RwTx) error {
+	for period, update := range l.updates {
+		if update == nil {
+			continue
+		}
+		stored, err := beacon_indicies.ReadLightClientUpdate(tx, period)
+		if err != nil {
+			return err
+		}
+		if stored != nil && !lightclient_utils.IsBetterUpdate(l.cfg, update, stored) {
+			continue
+		}
+		if err := beacon_indicies.WriteLightClientUpdate(tx, period, update); err != nil {
+			return err
+		}
+	}
+	clear(l.updates)
+	return nil
+}
+
+func toHashVector(in [][32]byte) solid.HashVectorSSZ {
+	out := solid.NewHashVector(len(in))
+	for i, v := range in {
+		out.Set(i, common.Hash(v))
+	}
+	return out
+}
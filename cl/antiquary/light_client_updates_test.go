@@ -0,0 +1,75 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package antiquary
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon/cl/antiquary/tests"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/persistence/beacon_indicies"
+)
+
+func TestLightClientUpdatesCollector(t *testing.T) {
+	blocks, preState, postState := tests.GetCapellaRandom()
+	db := runTest(t, blocks, preState, postState)
+	cfg := &clparams.MainnetBeaconConfig
+
+	tx, err := db.BeginRw(context.Background())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	// the parent of the first block is not known, so the only update is the one signed by the last block
+	period := cfg.SyncCommitteePeriod(blocks[0].Block.Slot)
+	lowest, ok, err := beacon_indicies.ReadLowestLightClientUpdatePeriod(tx)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, period, lowest)
+	update, err := beacon_indicies.ReadLightClientUpdate(tx, period)
+	require.NoError(t, err)
+	require.NotNil(t, update)
+	last := blocks[len(blocks)-1]
+	require.Equal(t, last.Block.Slot, update.SignatureSlot)
+	require.Equal(t, blocks[len(blocks)-2].Block.Slot, update.AttestedHeader.Beacon.Slot)
+	require.Equal(t, last.Block.Body.SyncAggregate.Sum(), update.SyncAggregate.Sum())
+	require.True(t, update.IsSyncCommitteeUpdate())
+
+	// a stored update with more participants is kept
+	better, err := beacon_indicies.ReadLightClientUpdate(tx, period)
+	require.NoError(t, err)
+	for i := range better.SyncAggregate.SyncCommiteeBits {
+		better.SyncAggregate.SyncCommiteeBits[i] = 0xff
+	}
+	require.NoError(t, beacon_indicies.WriteLightClientUpdate(tx, period, better))
+	collector := newLightClientUpdatesCollector(cfg, nil)
+	collector.updates[period] = update
+	require.NoError(t, collector.flush(tx))
+	stored, err := beacon_indicies.ReadLightClientUpdate(tx, period)
+	require.NoError(t, err)
+	require.Equal(t, better.SyncAggregate.Sum(), stored.SyncAggregate.Sum())
+
+	// and a worse one is replaced
+	require.NoError(t, beacon_indicies.WriteLightClientUpdate(tx, period, update))
+	collector.updates[period] = better
+	require.NoError(t, collector.flush(tx))
+	stored, err = beacon_indicies.ReadLightClientUpdate(tx, period)
+	require.NoError(t, err)
+	require.Equal(t, better.SyncAggregate.Sum(), stored.SyncAggregate.Sum())
+}
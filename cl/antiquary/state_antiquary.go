@@ -219,6 +219,7 @@ func (s *Antiquary) IncrementBeaconState(ctx context.Context, to uint64) error {
 
 	stateAntiquaryCollector := newBeaconStatesCollector(s.cfg, s.dirs.Tmp, s.logger)
 	defer stateAntiquaryCollector.close()
+	lightClientUpdatesCollector := newLightClientUpdatesCollector(s.cfg, s.snReader)
 
 	if err := s.initializeStateAntiquaryIfNeeded(ctx, tx); err != nil {
 		return err
@@ -399,6 +400,10 @@ func (s *Antiquary) IncrementBeaconState(ctx context.Context, to uint64) error {
 		fullValidation := slot%1000 == 0 || first
 		blockRewardsCollector := &eth2.BlockRewardsCollector{}
 
+		// the state still is the one of the parent block, the one the light client update attests
+		if err := lightClientUpdatesCollector.collect(ctx, tx, s.currentState, block); err != nil {
+			return err
+		}
 		stateAntiquaryCollector.preStateTransitionHook(s.currentState)
 		// We sanity check the state every 1k slots or when we start.
 		if err := transition.TransitionState(s.currentState, block, blockRewardsCollector, fullValidation); err != nil {
@@ -500,6 +505,9 @@ func (s *Antiquary) IncrementBeaconState(ctx context.Context, to uint64) error {
 	if err := stateAntiquaryCollector.flush(ctx, rwTx); err != nil {
 		return err
 	}
+	if err := lightClientUpdatesCollector.flush(rwTx); err != nil {
+		return err
+	}
 
 	if err := state_accessors.SetStateProcessingProgress(rwTx, s.currentState.Slot()); err != nil {
 		return err
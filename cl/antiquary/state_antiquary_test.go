@@ -35,7 +35,7 @@ import (
 	"github.com/erigontech/erigon/cl/phase1/core/state"
 )
 
-func runTest(t *testing.T, blocks []*cltypes.SignedBeaconBlock, preState, postState *state.CachingBeaconState) {
+func runTest(t *testing.T, blocks []*cltypes.SignedBeaconBlock, preState, postState *state.CachingBeaconState) kv.RwDB {
 	db := memdb.NewTestDB(t, kv.ChainDB)
 	reader := tests.LoadChain(blocks, postState, db, t)
 	sn := synced_data.NewSyncedDataManager(&clparams.MainnetBeaconConfig, true)
@@ -44,6 +44,7 @@ func runTest(t *testing.T, blocks []*cltypes.SignedBeaconBlock, preState, postSt
 	vt := state_accessors.NewStaticValidatorTable()
 	a := NewAntiquary(ctx, nil, preState, vt, &clparams.MainnetBeaconConfig, datadir.New("/tmp"), nil, db, nil, nil, reader, sn, log.New(), true, true, true, false, nil)
 	require.NoError(t, a.IncrementBeaconState(ctx, blocks[len(blocks)-1].Block.Slot+33))
+	return db
 }
 
 func TestStateAntiquaryElectra(t *testing.T) {
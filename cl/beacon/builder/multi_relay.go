@@ -0,0 +1,377 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon-lib/metrics"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/fork"
+	"github.com/erigontech/erigon/cl/merkle_tree"
+	"github.com/erigontech/erigon/cl/utils"
+	"github.com/erigontech/erigon/cl/utils/bls"
+	"github.com/erigontech/erigon/turbo/engineapi/engine_types"
+)
+
+var _ BuilderClient = &multiRelayClient{}
+
+var (
+	// ErrNoBid is returned when no relay served a valid bid, the block must be built locally.
+	ErrNoBid = errors.New("no valid bid from the relays")
+	// ErrBidBelowMinimum is returned when the best bid is lower than the minimum bid, the block must be built locally.
+	ErrBidBelowMinimum = errors.New("best bid is below the minimum bid")
+)
+
+// DefaultRelayTimeout is the time the relays have to serve a bid, late bids are ignored.
+const DefaultRelayTimeout = 950 * time.Millisecond
+
+// relay is one of the relays of the multi relay client, along with its metrics.
+type relay struct {
+	name   string // host of the relay, for logs and metrics
+	client BuilderClient
+	// pubkey is the key the relay signs its bids with, when given in the url
+	pubkey *common.Bytes48
+
+	getHeaderTime  metrics.Summary
+	failures       metrics.Counter
+	timeouts       metrics.Counter
+	invalidHeaders metrics.Counter
+	wins           metrics.Counter
+}
+
+func newRelay(name string, client BuilderClient, pubkey *common.Bytes48) *relay {
+	return &relay{
+		name:           name,
+		client:         client,
+		pubkey:         pubkey,
+		getHeaderTime:  metrics.GetOrCreateSummary(fmt.Sprintf(`caplin_mev_relay_get_header_seconds{relay="%s"}`, name)),
+		failures:       metrics.GetOrCreateCounter(fmt.Sprintf(`caplin_mev_relay_failures{relay="%s"}`, name)),
+		timeouts:       metrics.GetOrCreateCounter(fmt.Sprintf(`caplin_mev_relay_timeouts{relay="%s"}`, name)),
+		invalidHeaders: metrics.GetOrCreateCounter(fmt.Sprintf(`caplin_mev_relay_invalid_headers{relay="%s"}`, name)),
+		wins:           metrics.GetOrCreateCounter(fmt.Sprintf(`caplin_mev_relay_wins{relay="%s"}`, name)),
+	}
+}
+
+type bidKey struct {
+	slot      uint64
+	blockHash common.Hash
+}
+
+// multiRelayClient queries several relays in parallel, in the way of mev-boost: the highest valid bid wins, and the
+// blinded block is then submitted to the relays which served it.
+type multiRelayClient struct {
+	relays       []*relay
+	beaconConfig *clparams.BeaconChainConfig
+	timeout      time.Duration
+	minBid       *big.Int
+
+	mu sync.Mutex
+	// bidRelays are the relays which served each winning bid, they are the only ones able to reveal its payload.
+	bidRelays map[bidKey][]*relay
+}
+
+// NewMultiRelayClient creates a builder client for all the relay urls. The url of a relay may carry the public key
+// of the relay as user (https://0xpubkey@relay.example), in which case the bids must be signed with it.
+func NewMultiRelayClient(relayUrls []string, beaconConfig *clparams.BeaconChainConfig, timeout time.Duration, minBid *big.Int) (BuilderClient, error) {
+	if len(relayUrls) == 0 {
+		return nil, errors.New("no relay url")
+	}
+	relays := make([]*relay, 0, len(relayUrls))
+	for _, relayUrl := range relayUrls {
+		u, err := url.Parse(strings.TrimSpace(relayUrl))
+		if err != nil {
+			return nil, fmt.Errorf("invalid relay url %s: %w", relayUrl, err)
+		}
+		var pubkey *common.Bytes48
+		if u.User != nil {
+			pubkey = new(common.Bytes48)
+			if err := pubkey.UnmarshalText([]byte(u.User.Username())); err != nil {
+				return nil, fmt.Errorf("invalid public key of relay %s: %w", u.Host, err)
+			}
+			u.User = nil
+		}
+		client := &builderClient{httpClient: &http.Client{}, url: u, beaconConfig: beaconConfig}
+		if err := client.GetStatus(context.Background()); err != nil {
+			// the relay may come back later
+			log.Warn("[mev builder] relay is not available", "relay", u.Host, "err", err)
+		}
+		relays = append(relays, newRelay(u.Host, client, pubkey))
+	}
+	log.Info("Builder client is ready", "relays", len(relays), "timeout", timeout, "minBid", minBid)
+	return newMultiRelayClient(relays, beaconConfig, timeout, minBid), nil
+}
+
+func newMultiRelayClient(relays []*relay, beaconConfig *clparams.BeaconChainConfig, timeout time.Duration, minBid *big.Int) *multiRelayClient {
+	if timeout == 0 {
+		timeout = DefaultRelayTimeout
+	}
+	if minBid == nil {
+		minBid = new(big.Int)
+	}
+	return &multiRelayClient{
+		relays:       relays,
+		beaconConfig: beaconConfig,
+		timeout:      timeout,
+		minBid:       minBid,
+		bidRelays:    make(map[bidKey][]*relay),
+	}
+}
+
+// RegisterValidator sends the registrations to all the relays, it fails only if no relay accepted them.
+func (m *multiRelayClient) RegisterValidator(ctx context.Context, registers []*cltypes.ValidatorRegistration) error {
+	errs := make([]error, len(m.relays))
+	var wg sync.WaitGroup
+	for i, r := range m.relays {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := r.client.RegisterValidator(ctx, registers); err != nil {
+				r.failures.Inc()
+				errs[i] = fmt.Errorf("relay %s: %w", r.name, err)
+			}
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err == nil {
+			return nil
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type relayBid struct {
+	relay  *relay
+	header *ExecutionHeader
+	value  *big.Int
+}
+
+// GetHeader asks all the relays for a bid, and returns the highest valid one.
+func (m *multiRelayClient) GetHeader(ctx context.Context, slot int64, parentHash common.Hash, pubKey common.Bytes48) (*ExecutionHeader, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	bids := make([]*relayBid, len(m.relays))
+	var wg sync.WaitGroup
+	for i, r := range m.relays {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			header, err := r.client.GetHeader(ctx, slot, parentHash, pubKey)
+			r.getHeaderTime.ObserveDuration(start)
+			switch {
+			case errors.Is(err, ErrNoContent):
+				// no bid for this slot
+				return
+			case errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded):
+				r.timeouts.Inc()
+				log.Debug("[mev builder] relay timed out", "relay", r.name, "slot", slot)
+				return
+			case err != nil:
+				r.failures.Inc()
+				log.Debug("[mev builder] relay failed to serve a bid", "relay", r.name, "slot", slot, "err", err)
+				return
+			}
+			value, err := m.validateBid(r, header, parentHash)
+			if err != nil {
+				r.invalidHeaders.Inc()
+				log.Warn("[mev builder] invalid bid", "relay", r.name, "slot", slot, "err", err)
+				return
+			}
+			bids[i] = &relayBid{relay: r, header: header, value: value}
+		}()
+	}
+	wg.Wait()
+
+	var best *relayBid
+	for _, bid := range bids {
+		if bid != nil && (best == nil || bid.value.Cmp(best.value) > 0) {
+			best = bid
+		}
+	}
+	if best == nil {
+		return nil, ErrNoBid
+	}
+	if best.value.Cmp(m.minBid) < 0 {
+		log.Info("[mev builder] best bid is below the minimum bid", "slot", slot, "value", best.value, "minBid", m.minBid)
+		return nil, ErrBidBelowMinimum
+	}
+
+	// every relay which served the same block can reveal its payload
+	blockHash := best.header.Data.Message.Header.BlockHash
+	var winners []*relay
+	for _, bid := range bids {
+		if bid != nil && bid.header.Data.Message.Header.BlockHash == blockHash {
+			bid.relay.wins.Inc()
+			winners = append(winners, bid.relay)
+		}
+	}
+	m.mu.Lock()
+	for key := range m.bidRelays {
+		if key.slot+m.beaconConfig.SlotsPerEpoch < uint64(slot) {
+			delete(m.bidRelays, key)
+		}
+	}
+	m.bidRelays[bidKey{slot: uint64(slot), blockHash: blockHash}] = winners
+	m.mu.Unlock()
+	log.Debug("[mev builder] selected bid", "slot", slot, "relay", best.relay.name, "value", best.value, "relays", len(winners))
+	return best.header, nil
+}
+
+// validateBid checks the bid is for the requested parent, carries a value, and is signed by the builder it claims.
+func (m *multiRelayClient) validateBid(r *relay, header *ExecutionHeader, parentHash common.Hash) (*big.Int, error) {
+	if header == nil || header.Data.Message.Header == nil {
+		return nil, errors.New("empty header")
+	}
+	message := header.Data.Message
+	if message.Header.ParentHash != parentHash {
+		return nil, fmt.Errorf("parent hash %s, expected %s", message.Header.ParentHash, parentHash)
+	}
+	if message.Header.BlockHash == (common.Hash{}) {
+		return nil, errors.New("empty block hash")
+	}
+	value := header.BlockValue()
+	if value == nil || value.Sign() <= 0 || value.BitLen() > 256 {
+		return nil, fmt.Errorf("invalid value %q", message.Value)
+	}
+	if r.pubkey != nil && message.PubKey != *r.pubkey {
+		return nil, fmt.Errorf("bid signed by %s, expected the relay key %s", message.PubKey, r.pubkey)
+	}
+	version, err := clparams.StringToClVersion(header.Version)
+	if err != nil {
+		return nil, err
+	}
+	signingRoot, err := builderBidSigningRoot(m.beaconConfig, version, &message, value)
+	if err != nil {
+		return nil, err
+	}
+	valid, err := bls.Verify(header.Data.Signature[:], signingRoot[:], message.PubKey[:])
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, errors.New("invalid signature")
+	}
+	return value, nil
+}
+
+// builderBidSigningRoot is the root the builders sign their bids with, in the builder domain.
+func builderBidSigningRoot(beaconConfig *clparams.BeaconChainConfig, version clparams.StateVersion, message *ExecutionHeaderMessage, value *big.Int) (common.Hash, error) {
+	// the value is an uint256, little endian
+	var valueLE [32]byte
+	value.FillBytes(valueLE[:])
+	for i, j := 0, len(valueLE)-1; i < j; i, j = i+1, j-1 {
+		valueLE[i], valueLE[j] = valueLE[j], valueLE[i]
+	}
+	var (
+		root [32]byte
+		err  error
+	)
+	switch {
+	case version >= clparams.ElectraVersion:
+		root, err = merkle_tree.HashTreeRoot(message.Header, message.BlobKzgCommitments, message.ExecutionRequests, valueLE[:], message.PubKey[:])
+	case version >= clparams.DenebVersion:
+		root, err = merkle_tree.HashTreeRoot(message.Header, message.BlobKzgCommitments, valueLE[:], message.PubKey[:])
+	default:
+		root, err = merkle_tree.HashTreeRoot(message.Header, valueLE[:], message.PubKey[:])
+	}
+	if err != nil {
+		return common.Hash{}, err
+	}
+	// bids are not bound to any fork
+	domain, err := fork.ComputeDomain(beaconConfig.DomainApplicationBuilder[:], utils.Uint32ToBytes4(uint32(beaconConfig.GenesisForkVersion)), [32]byte{})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return utils.Sha256(root[:], domain), nil
+}
+
+// SubmitBlindedBlocks submits the block to the relays which served its bid, and returns the first payload revealed.
+func (m *multiRelayClient) SubmitBlindedBlocks(ctx context.Context, block *cltypes.SignedBlindedBeaconBlock) (*cltypes.Eth1Block, *engine_types.BlobsBundleV1, *cltypes.ExecutionRequests, error) {
+	relays := m.relays
+	if payload := block.Block.Body.ExecutionPayload; payload != nil {
+		m.mu.Lock()
+		if winners, ok := m.bidRelays[bidKey{slot: block.Block.Slot, blockHash: payload.BlockHash}]; ok {
+			relays = winners
+		}
+		m.mu.Unlock()
+	}
+
+	type result struct {
+		block             *cltypes.Eth1Block
+		blobsBundle       *engine_types.BlobsBundleV1
+		executionRequests *cltypes.ExecutionRequests
+		err               error
+	}
+	// buffered so that the relays answering after the first one do not block
+	results := make(chan result, len(relays))
+	for _, r := range relays {
+		go func() {
+			eth1Block, blobsBundle, executionRequests, err := r.client.SubmitBlindedBlocks(ctx, block)
+			if err != nil {
+				r.failures.Inc()
+				err = fmt.Errorf("relay %s: %w", r.name, err)
+			}
+			results <- result{block: eth1Block, blobsBundle: blobsBundle, executionRequests: executionRequests, err: err}
+		}()
+	}
+	var errs []error
+	for range relays {
+		res := <-results
+		if res.err == nil {
+			return res.block, res.blobsBundle, res.executionRequests, nil
+		}
+		errs = append(errs, res.err)
+	}
+	return nil, nil, nil, errors.Join(errs...)
+}
+
+// GetStatus succeeds if any relay is available.
+func (m *multiRelayClient) GetStatus(ctx context.Context) error {
+	var errs []error
+	for _, r := range m.relays {
+		err := r.client.GetStatus(ctx)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("relay %s: %w", r.name, err))
+	}
+	return errors.Join(errs...)
+}
+
+// ParseEther parses an amount of ether, as "0.05", into wei.
+func ParseEther(s string) (*big.Int, error) {
+	value, ok := new(big.Float).SetPrec(256).SetString(strings.TrimSpace(s))
+	if !ok || value.Sign() < 0 {
+		return nil, fmt.Errorf("invalid ether amount %q", s)
+	}
+	wei, _ := value.Mul(value, new(big.Float).SetPrec(256).SetInt(big.NewInt(1e18))).Int(nil)
+	return wei, nil
+}
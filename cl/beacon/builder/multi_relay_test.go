@@ -0,0 +1,206 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package builder
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/utils/bls"
+	"github.com/erigontech/erigon/turbo/engineapi/engine_types"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRelay serves a fixed bid, or a fixed error.
+type fakeRelay struct {
+	header    *ExecutionHeader
+	err       error
+	delay     time.Duration
+	registers atomic.Int32
+	submits   atomic.Int32
+}
+
+func (f *fakeRelay) RegisterValidator(ctx context.Context, registers []*cltypes.ValidatorRegistration) error {
+	f.registers.Add(1)
+	return f.err
+}
+
+func (f *fakeRelay) GetHeader(ctx context.Context, slot int64, parentHash common.Hash, pubKey common.Bytes48) (*ExecutionHeader, error) {
+	if f.delay > 0 {
+		select {
+		case <-time.After(f.delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.header == nil {
+		return nil, ErrNoContent
+	}
+	return f.header, nil
+}
+
+func (f *fakeRelay) SubmitBlindedBlocks(ctx context.Context, block *cltypes.SignedBlindedBeaconBlock) (*cltypes.Eth1Block, *engine_types.BlobsBundleV1, *cltypes.ExecutionRequests, error) {
+	f.submits.Add(1)
+	if f.err != nil {
+		return nil, nil, nil, f.err
+	}
+	return cltypes.NewEth1Block(clparams.DenebVersion, &clparams.MainnetBeaconConfig), nil, nil, nil
+}
+
+func (f *fakeRelay) GetStatus(ctx context.Context) error {
+	return f.err
+}
+
+func signedBid(t *testing.T, key *bls.PrivateKey, parentHash, blockHash common.Hash, value int64) *ExecutionHeader {
+	header := cltypes.NewEth1Header(clparams.DenebVersion)
+	header.ParentHash = parentHash
+	header.BlockHash = blockHash
+	message := ExecutionHeaderMessage{
+		Header:             header,
+		BlobKzgCommitments: solid.NewStaticListSSZ[*cltypes.KZGCommitment](cltypes.MaxBlobsCommittmentsPerBlock, 48),
+		Value:              big.NewInt(value).String(),
+	}
+	copy(message.PubKey[:], bls.CompressPublicKey(key.PublicKey()))
+	signingRoot, err := builderBidSigningRoot(&clparams.MainnetBeaconConfig, clparams.DenebVersion, &message, big.NewInt(value))
+	require.NoError(t, err)
+	bid := &ExecutionHeader{Version: clparams.DenebVersion.String()}
+	bid.Data.Message = message
+	copy(bid.Data.Signature[:], key.Sign(signingRoot[:]).Bytes())
+	return bid
+}
+
+func newTestMultiRelay(minBid int64, relays ...*fakeRelay) *multiRelayClient {
+	wrapped := make([]*relay, len(relays))
+	for i, r := range relays {
+		wrapped[i] = newRelay(string(rune('a'+i)), r, nil)
+	}
+	return newMultiRelayClient(wrapped, &clparams.MainnetBeaconConfig, 100*time.Millisecond, big.NewInt(minBid))
+}
+
+func TestMultiRelayGetHeader(t *testing.T) {
+	key, err := bls.GenerateKey()
+	require.NoError(t, err)
+	parentHash := common.HexToHash("0x01")
+
+	low := &fakeRelay{header: signedBid(t, key, parentHash, common.HexToHash("0xa1"), 100)}
+	high := &fakeRelay{header: signedBid(t, key, parentHash, common.HexToHash("0xa2"), 300)}
+	wrongParent := &fakeRelay{header: signedBid(t, key, common.HexToHash("0x02"), common.HexToHash("0xa3"), 1000)}
+	badSignature := &fakeRelay{header: signedBid(t, key, parentHash, common.HexToHash("0xa4"), 1000)}
+	badSignature.header.Data.Message.Value = "2000"
+	overflow := &fakeRelay{header: signedBid(t, key, parentHash, common.HexToHash("0xa6"), 1000)}
+	overflow.header.Data.Message.Value = new(big.Int).Lsh(big.NewInt(1), 256).String() // doesn't fit the signed uint256
+	slow := &fakeRelay{header: signedBid(t, key, parentHash, common.HexToHash("0xa5"), 5000), delay: time.Second}
+	failing := &fakeRelay{err: errors.New("relay down")}
+	noBid := &fakeRelay{}
+
+	client := newTestMultiRelay(0, low, high, wrongParent, badSignature, overflow, slow, failing, noBid)
+	header, err := client.GetHeader(context.Background(), 10, parentHash, common.Bytes48{})
+	require.NoError(t, err)
+	require.Equal(t, common.HexToHash("0xa2"), header.Data.Message.Header.BlockHash)
+	require.Equal(t, big.NewInt(300), header.BlockValue())
+}
+
+func TestMultiRelayMinBid(t *testing.T) {
+	key, err := bls.GenerateKey()
+	require.NoError(t, err)
+	parentHash := common.HexToHash("0x01")
+
+	client := newTestMultiRelay(500, &fakeRelay{header: signedBid(t, key, parentHash, common.HexToHash("0xa1"), 300)})
+	_, err = client.GetHeader(context.Background(), 10, parentHash, common.Bytes48{})
+	require.ErrorIs(t, err, ErrBidBelowMinimum)
+
+	client = newTestMultiRelay(0, &fakeRelay{}, &fakeRelay{err: errors.New("relay down")})
+	_, err = client.GetHeader(context.Background(), 10, parentHash, common.Bytes48{})
+	require.ErrorIs(t, err, ErrNoBid)
+}
+
+func TestMultiRelayRelayPubkey(t *testing.T) {
+	key, err := bls.GenerateKey()
+	require.NoError(t, err)
+	other, err := bls.GenerateKey()
+	require.NoError(t, err)
+	parentHash := common.HexToHash("0x01")
+
+	var relayKey common.Bytes48
+	copy(relayKey[:], bls.CompressPublicKey(other.PublicKey()))
+	r := newRelay("a", &fakeRelay{header: signedBid(t, key, parentHash, common.HexToHash("0xa1"), 300)}, &relayKey)
+	client := newMultiRelayClient([]*relay{r}, &clparams.MainnetBeaconConfig, 100*time.Millisecond, nil)
+	_, err = client.GetHeader(context.Background(), 10, parentHash, common.Bytes48{})
+	require.ErrorIs(t, err, ErrNoBid)
+}
+
+func TestMultiRelaySubmitToWinners(t *testing.T) {
+	key, err := bls.GenerateKey()
+	require.NoError(t, err)
+	parentHash := common.HexToHash("0x01")
+	blockHash := common.HexToHash("0xa2")
+
+	winner := &fakeRelay{header: signedBid(t, key, parentHash, blockHash, 300)}
+	sameBlock := &fakeRelay{header: signedBid(t, key, parentHash, blockHash, 300)}
+	loser := &fakeRelay{header: signedBid(t, key, parentHash, common.HexToHash("0xa1"), 100)}
+	client := newTestMultiRelay(0, winner, loser, sameBlock)
+	_, err = client.GetHeader(context.Background(), 10, parentHash, common.Bytes48{})
+	require.NoError(t, err)
+
+	block := cltypes.NewSignedBlindedBeaconBlock(&clparams.MainnetBeaconConfig, clparams.DenebVersion)
+	block.Block.Slot = 10
+	block.Block.Body.ExecutionPayload = winner.header.Data.Message.Header
+	_, _, _, err = client.SubmitBlindedBlocks(context.Background(), block)
+	require.NoError(t, err)
+	// the payload is revealed by one of the relays holding it, the other may still be running
+	require.Eventually(t, func() bool {
+		return winner.submits.Load() == 1 && sameBlock.submits.Load() == 1
+	}, time.Second, 10*time.Millisecond)
+	require.Zero(t, loser.submits.Load())
+}
+
+func TestMultiRelayRegisterValidator(t *testing.T) {
+	up := &fakeRelay{}
+	down := &fakeRelay{err: errors.New("relay down")}
+	client := newTestMultiRelay(0, up, down)
+	registers := []*cltypes.ValidatorRegistration{{}}
+	require.NoError(t, client.RegisterValidator(context.Background(), registers))
+	require.Equal(t, int32(1), up.registers.Load())
+	require.Equal(t, int32(1), down.registers.Load())
+
+	client = newTestMultiRelay(0, down)
+	require.Error(t, client.RegisterValidator(context.Background(), registers))
+}
+
+func TestParseEther(t *testing.T) {
+	wei, err := ParseEther("0.05")
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(5e16), wei)
+	wei, err = ParseEther("0")
+	require.NoError(t, err)
+	require.Zero(t, wei.Sign())
+	_, err = ParseEther("-1")
+	require.Error(t, err)
+	_, err = ParseEther("abc")
+	require.Error(t, err)
+}
@@ -396,7 +396,12 @@ func (a *ApiHandler) produceBlock(
 		defer wg.Done()
 		if a.routerCfg.Builder && a.builderClient != nil {
 			builderHeader, builderErr = a.getBuilderPayload(ctx, baseBlock, baseState, targetSlot)
-			if builderErr != nil && builderErr != errBuilderNotEnabled {
+			switch {
+			case builderErr == nil, errors.Is(builderErr, errBuilderNotEnabled):
+			case errors.Is(builderErr, builder.ErrNoBid), errors.Is(builderErr, builder.ErrBidBelowMinimum):
+				// the local payload is used
+				log.Info("No builder payload, using the local one", "slot", targetSlot, "reason", builderErr)
+			default:
 				log.Warn("Failed to get builder payload", "err", builderErr)
 			}
 		}
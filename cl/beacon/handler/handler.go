@@ -38,6 +38,7 @@ import (
 	"github.com/erigontech/erigon/cl/cltypes/solid"
 	"github.com/erigontech/erigon/cl/persistence/blob_storage"
 	"github.com/erigontech/erigon/cl/persistence/state/historical_states_reader"
+	"github.com/erigontech/erigon/cl/phase1/core/deposit_tree"
 	"github.com/erigontech/erigon/cl/phase1/core/state/lru"
 	"github.com/erigontech/erigon/cl/phase1/execution_client"
 	"github.com/erigontech/erigon/cl/phase1/forkchoice"
@@ -110,6 +111,7 @@ type ApiHandler struct {
 	proposerSlashingService          services.ProposerSlashingService
 	builderClient                    builder.BuilderClient
 	enableMemoizedHeadState          bool
+	depositTracker                   *deposit_tree.Tracker
 }
 
 func NewApiHandler(
@@ -145,6 +147,7 @@ func NewApiHandler(
 	builderClient builder.BuilderClient,
 	caplinStateSnapshots *snapshotsync.CaplinStateSnapshots,
 	enableMemoizedHeadState bool,
+	depositTracker *deposit_tree.Tracker,
 ) *ApiHandler {
 	blobBundles, err := lru.New[common.Bytes48, BlobBundle]("blobs", maxBlobBundleCacheSize)
 	if err != nil {
@@ -193,6 +196,7 @@ func NewApiHandler(
 		proposerSlashingService:          proposerSlashingService,
 		builderClient:                    builderClient,
 		enableMemoizedHeadState:          enableMemoizedHeadState,
+		depositTracker:                   depositTracker,
 	}
 }
 
@@ -235,6 +239,7 @@ func (a *ApiHandler) init() {
 
 			if a.routerCfg.Debug {
 				r.Get("/debug/fork_choice", a.GetEthV1DebugBeaconForkChoice)
+				r.Get("/debug/beacon/state_diff", beaconhttp.HandleEndpointFunc(a.GetEthV1DebugBeaconStateDiff))
 			}
 			if a.routerCfg.Config {
 				r.Route("/config", func(r chi.Router) {
@@ -285,6 +290,7 @@ func (a *ApiHandler) init() {
 						r.Get("/updates", a.GetEthV1BeaconLightClientUpdates)
 					})
 					r.Get("/blob_sidecars/{block_id}", beaconhttp.HandleEndpointFunc(a.GetEthV1BeaconBlobSidecars))
+					r.Get("/deposit_snapshot", beaconhttp.HandleEndpointFunc(a.GetEthV1BeaconDepositSnapshot))
 					r.Route("/states", func(r chi.Router) {
 						r.Route("/{state_id}", func(r chi.Router) {
 							r.Get("/randao", beaconhttp.HandleEndpointFunc(a.getRandao))
@@ -298,6 +304,9 @@ func (a *ApiHandler) init() {
 							r.Get("/validator_balances", a.GetEthV1BeaconValidatorsBalances)
 							r.Post("/validator_balances", a.PostEthV1BeaconValidatorsBalances)
 							r.Get("/validators/{validator_id}", beaconhttp.HandleEndpointFunc(a.GetEthV1BeaconStatesValidator))
+							r.Get("/pending_deposits", beaconhttp.HandleEndpointFunc(a.GetEthV1BeaconStatesPendingDeposits))
+							r.Get("/pending_partial_withdrawals", beaconhttp.HandleEndpointFunc(a.GetEthV1BeaconStatesPendingPartialWithdrawals))
+							r.Get("/pending_consolidations", beaconhttp.HandleEndpointFunc(a.GetEthV1BeaconStatesPendingConsolidations))
 							r.Get("/validator_identities", beaconhttp.HandleEndpointFunc(a.GetEthV1ValidatorIdentities))
 						})
 					})
@@ -19,11 +19,15 @@ package handler
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 
+	"github.com/erigontech/erigon-lib/kv"
 	"github.com/erigontech/erigon/cl/beacon/beaconhttp"
 	"github.com/erigontech/erigon/cl/clparams"
 	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/lightclient_utils"
+	"github.com/erigontech/erigon/cl/persistence/beacon_indicies"
 )
 
 func (a *ApiHandler) GetEthV1BeaconLightClientBootstrap(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
@@ -99,19 +103,42 @@ func (a *ApiHandler) GetEthV1BeaconLightClientUpdates(w http.ResponseWriter, r *
 		return
 	}
 
+	tx, err := a.indiciesDB.BeginRo(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	// the updates are stored from the period the node started in, or from Altair on archive nodes which produce them
+	// while replaying the historical states
+	lowestPeriod, ok, err := beacon_indicies.ReadLowestLightClientUpdatePeriod(tx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if ok && *startPeriod < lowestPeriod {
+		http.Error(w, fmt.Sprintf("light client updates are only available from period %d", lowestPeriod), http.StatusNotFound)
+		return
+	}
+
 	resp := []interface{}{}
 	endPeriod := *startPeriod + *count
 	currentSlot := a.ethClock.GetCurrentSlot()
 	if endPeriod > a.beaconChainCfg.SyncCommitteePeriod(currentSlot) {
-		endPeriod = a.beaconChainCfg.SyncCommitteePeriod(currentSlot)
+		endPeriod = a.beaconChainCfg.SyncCommitteePeriod(currentSlot) + 1
 	}
 
 	notFoundPrev := false
-	// Fetch from [start_period, start_period + count]
-	for i := *startPeriod; i <= endPeriod; i++ {
+	// Fetch from [start_period, start_period + count)
+	for i := *startPeriod; i < endPeriod; i++ {
 		respUpdate := map[string]interface{}{}
-		update, has := a.forkchoiceStore.GetLightClientUpdate(i)
-		if !has {
+		update, err := a.lightClientUpdate(tx, i)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if update == nil {
 			notFoundPrev = true
 			continue
 		}
@@ -129,3 +156,13 @@ func (a *ApiHandler) GetEthV1BeaconLightClientUpdates(w http.ResponseWriter, r *
 		return
 	}
 }
+
+// lightClientUpdate returns the best update of the period among the one of the fork choice and the stored one.
+func (a *ApiHandler) lightClientUpdate(tx kv.Tx, period uint64) (*cltypes.LightClientUpdate, error) {
+	stored, err := beacon_indicies.ReadLightClientUpdate(tx, period)
+	if err != nil {
+		return nil, err
+	}
+	update, _ := a.forkchoiceStore.GetLightClientUpdate(period)
+	return lightclient_utils.BestLightClientUpdate(a.beaconChainCfg, stored, update), nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/kv/memdb"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/persistence/beacon_indicies"
+	mock_services2 "github.com/erigontech/erigon/cl/phase1/forkchoice/mock_services"
+	"github.com/erigontech/erigon/cl/utils/eth_clock"
+)
+
+func TestGetLightClientUpdates(t *testing.T) {
+	cfg := clparams.MainnetBeaconConfig
+	periodDuration := cfg.SlotsPerEpoch * cfg.EpochsPerSyncCommitteePeriod * cfg.SecondsPerSlot
+	// the clock is in period 5
+	genesisTime := uint64(time.Now().Unix()) - 5*periodDuration - cfg.SecondsPerSlot
+	db := memdb.NewTestDB(t, kv.CaplinDB)
+	h := &ApiHandler{
+		indiciesDB:      db,
+		beaconChainCfg:  &cfg,
+		ethClock:        eth_clock.NewEthereumClock(genesisTime, common.Hash{}, &cfg),
+		forkchoiceStore: mock_services2.NewForkChoiceStorageMock(t),
+	}
+
+	tx, err := db.BeginRw(context.Background())
+	require.NoError(t, err)
+	defer tx.Rollback()
+	// period 4 was missed
+	for _, period := range []uint64{2, 3, 5} {
+		update := cltypes.NewLightClientUpdate(clparams.CapellaVersion)
+		update.SignatureSlot = period * cfg.SlotsPerEpoch * cfg.EpochsPerSyncCommitteePeriod
+		require.NoError(t, beacon_indicies.WriteLightClientUpdate(tx, period, update))
+	}
+	require.NoError(t, tx.Commit())
+
+	get := func(startPeriod, count uint64) (int, []uint64) {
+		r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/eth/v1/beacon/light_client/updates?start_period=%d&count=%d", startPeriod, count), nil)
+		w := httptest.NewRecorder()
+		h.GetEthV1BeaconLightClientUpdates(w, r)
+		if w.Code != http.StatusOK {
+			return w.Code, nil
+		}
+		var resp []struct {
+			Data struct {
+				SignatureSlot uint64 `json:"signature_slot,string"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		periods := []uint64{}
+		for _, update := range resp {
+			periods = append(periods, cfg.SyncCommitteePeriod(update.Data.SignatureSlot))
+		}
+		return w.Code, periods
+	}
+
+	// the range is [start_period, start_period+count)
+	code, periods := get(2, 2)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, []uint64{2, 3}, periods)
+	code, periods = get(3, 1)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, []uint64{3}, periods)
+	// the range stops at the current period, and only the updates after a missing one are returned
+	code, periods = get(3, 100)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, []uint64{5}, periods)
+	// the updates before the first stored one are not available
+	code, _ = get(1, 2)
+	require.Equal(t, http.StatusNotFound, code)
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/erigontech/erigon/cl/beacon/beaconhttp"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/persistence/beacon_indicies"
+	state_accessors "github.com/erigontech/erigon/cl/persistence/state"
+	"github.com/erigontech/erigon/cl/phase1/core/state"
+)
+
+func (a *ApiHandler) GetEthV1BeaconStatesPendingDeposits(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	return getPendingQueue(a, r, (*state.CachingBeaconState).GetPendingDeposits, a.stateReader.ReadPendingDeposits)
+}
+
+func (a *ApiHandler) GetEthV1BeaconStatesPendingPartialWithdrawals(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	return getPendingQueue(a, r, (*state.CachingBeaconState).GetPendingPartialWithdrawals, a.stateReader.ReadPendingPartialWithdrawals)
+}
+
+func (a *ApiHandler) GetEthV1BeaconStatesPendingConsolidations(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	return getPendingQueue(a, r, (*state.CachingBeaconState).GetPendingConsolidations, a.stateReader.ReadPendingConsolidations)
+}
+
+// getPendingQueue serves one of the Electra pending queues of a state: the head state and the states still
+// held by the fork choice are read directly, older ones are reconstructed from the archive.
+func getPendingQueue[T solid.EncodableHashableSSZ](
+	a *ApiHandler,
+	r *http.Request,
+	fromState func(*state.CachingBeaconState) *solid.ListSSZ[T],
+	fromArchive func(state_accessors.GetValFn, uint64) (*solid.ListSSZ[T], error),
+) (*beaconhttp.BeaconResponse, error) {
+	ctx := r.Context()
+
+	tx, err := a.indiciesDB.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	blockId, err := beaconhttp.StateIdFromRequest(r)
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err)
+	}
+
+	blockRoot, httpStatus, err := a.blockRootFromStateId(ctx, tx, blockId)
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(httpStatus, err)
+	}
+	isOptimistic := a.forkchoiceStore.IsRootOptimistic(blockRoot)
+
+	slot, err := beacon_indicies.ReadBlockSlotByBlockRoot(tx, blockRoot)
+	if err != nil {
+		return nil, err
+	}
+	if slot == nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Errorf("could not read block slot: %x", blockRoot))
+	}
+	version := a.beaconChainCfg.GetCurrentStateVersion(*slot / a.beaconChainCfg.SlotsPerEpoch)
+	if version < clparams.ElectraVersion {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Errorf("state at slot %d predates electra", *slot))
+	}
+
+	var queue *solid.ListSSZ[T]
+	if blockId.Head() { // Lets see if we point to head, if yes then we need to look at the head state we always keep.
+		if err := a.syncedData.ViewHeadState(func(s *state.CachingBeaconState) error {
+			// the head state is mutated once we return, so take a deep copy
+			headQueue := fromState(s)
+			encoded, err := headQueue.EncodeSSZ(nil)
+			if err != nil {
+				return err
+			}
+			queue = headQueue.Clone().(*solid.ListSSZ[T])
+			return queue.DecodeSSZ(encoded, int(version))
+		}); err != nil {
+			return nil, beaconhttp.NewEndpointError(http.StatusServiceUnavailable, errors.New("node is not synced"))
+		}
+		return newBeaconResponse(queue).
+			WithFinalized(false).
+			WithVersion(version).
+			WithOptimistic(isOptimistic), nil
+	}
+
+	if *slot >= a.forkchoiceStore.LowestAvailableSlot() {
+		s, err := a.forkchoiceStore.GetStateAtBlockRoot(blockRoot, true)
+		if err != nil {
+			return nil, err
+		}
+		if s != nil {
+			return newBeaconResponse(fromState(s)).
+				WithFinalized(*slot <= a.forkchoiceStore.FinalizedSlot()).
+				WithVersion(version).
+				WithOptimistic(isOptimistic), nil
+		}
+	}
+
+	// check if the block is canonical
+	canonicalRoot, err := beacon_indicies.ReadCanonicalBlockRoot(tx, *slot)
+	if err != nil {
+		return nil, err
+	}
+	if canonicalRoot != blockRoot {
+		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Errorf("could not read state: %x", blockRoot))
+	}
+	snRoTx := a.caplinStateSnapshots.View()
+	defer snRoTx.Close()
+
+	queue, err = fromArchive(state_accessors.GetValFnTxAndSnapshot(tx, snRoTx), *slot)
+	if err != nil {
+		return nil, err
+	}
+	if queue == nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Errorf("could not read state: %x, node may not be running in archival mode", blockRoot))
+	}
+	return newBeaconResponse(queue).
+		WithFinalized(true).
+		WithVersion(version).
+		WithOptimistic(isOptimistic), nil
+}
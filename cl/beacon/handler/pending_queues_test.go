@@ -0,0 +1,150 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/kv/memdb"
+	"github.com/erigontech/erigon/cl/beacon/beaconhttp"
+	"github.com/erigontech/erigon/cl/beacon/synced_data"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/persistence/beacon_indicies"
+	"github.com/erigontech/erigon/cl/phase1/core/state"
+	mock_services2 "github.com/erigontech/erigon/cl/phase1/forkchoice/mock_services"
+)
+
+// electraStateWithQueues makes an Electra state with n entries in each of its pending queues.
+func electraStateWithQueues(cfg *clparams.BeaconChainConfig, slot uint64, n int) *state.CachingBeaconState {
+	s := state.New(cfg)
+	s.SetVersion(clparams.ElectraVersion)
+	s.SetSlot(slot)
+	s.SetLatestExecutionPayloadHeader(cltypes.NewEth1Header(clparams.ElectraVersion))
+	for i := 0; i < n; i++ {
+		s.AppendPendingDeposit(&solid.PendingDeposit{
+			PubKey:                common.Bytes48{byte(i + 1)},
+			WithdrawalCredentials: common.Hash{byte(i + 2)},
+			Amount:                cfg.MaxEffectiveBalance,
+			Signature:             common.Bytes96{byte(i + 3)},
+			Slot:                  slot - 1,
+		})
+		s.AppendPendingPartialWithdrawal(&solid.PendingPartialWithdrawal{Index: uint64(i), Amount: 1_000_000_000, WithdrawableEpoch: uint64(i + 5)})
+		s.AppendPendingConsolidation(&solid.PendingConsolidation{SourceIndex: uint64(i), TargetIndex: uint64(i + 10)})
+	}
+	return s
+}
+
+func checkPendingQueue[T solid.EncodableHashableSSZ](t *testing.T, url string, expected *solid.ListSSZ[T]) {
+	t.Helper()
+	expectedSSZ, err := expected.EncodeSSZ(nil)
+	require.NoError(t, err)
+
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var body struct {
+		Data    json.RawMessage `json:"data"`
+		Version string          `json:"version"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Equal(t, clparams.ElectraVersion.String(), body.Version)
+	queue := expected.Clone().(*solid.ListSSZ[T])
+	require.NoError(t, json.Unmarshal(body.Data, queue))
+	require.Equal(t, expected.Len(), queue.Len())
+	queueSSZ, err := queue.EncodeSSZ(nil)
+	require.NoError(t, err)
+	require.Equal(t, expectedSSZ, queueSSZ)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "application/octet-stream")
+	sszResp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer sszResp.Body.Close()
+	require.Equal(t, http.StatusOK, sszResp.StatusCode)
+	encoded, err := io.ReadAll(sszResp.Body)
+	require.NoError(t, err)
+	require.Equal(t, expectedSSZ, encoded)
+}
+
+func TestGetStatePendingQueuesElectra(t *testing.T) {
+	cfg := clparams.MainnetBeaconConfig
+	cfg.AltairForkEpoch, cfg.BellatrixForkEpoch, cfg.CapellaForkEpoch, cfg.DenebForkEpoch, cfg.ElectraForkEpoch = 0, 0, 0, 0, 0
+	cfg.InitializeForkSchedule()
+
+	// the head is served from the head state, its parent from the states held by the fork choice
+	headState, parentState := electraStateWithQueues(&cfg, 10, 2), electraStateWithQueues(&cfg, 9, 1)
+	headRoot, err := headState.BlockRoot()
+	require.NoError(t, err)
+	parentRoot := common.Hash{9}
+
+	db := memdb.NewTestDB(t, kv.CaplinDB)
+	tx, err := db.BeginRw(context.Background())
+	require.NoError(t, err)
+	defer tx.Rollback()
+	for root, slot := range map[common.Hash]uint64{headRoot: headState.Slot(), parentRoot: parentState.Slot()} {
+		require.NoError(t, beacon_indicies.WriteHeaderSlot(tx, root, slot))
+		require.NoError(t, beacon_indicies.MarkRootCanonical(context.Background(), tx, slot, root))
+	}
+	require.NoError(t, tx.Commit())
+
+	syncedData := synced_data.NewSyncedDataManager(&cfg, true)
+	require.NoError(t, syncedData.OnHeadState(headState))
+	forkchoiceStore := mock_services2.NewForkChoiceStorageMock(t)
+	forkchoiceStore.StateAtBlockRootVal[parentRoot] = parentState
+	h := &ApiHandler{
+		indiciesDB:              db,
+		beaconChainCfg:          &cfg,
+		forkchoiceStore:         forkchoiceStore,
+		syncedData:              syncedData,
+		enableMemoizedHeadState: true,
+	}
+
+	mux := chi.NewRouter()
+	mux.Get("/eth/v1/beacon/states/{state_id}/pending_deposits", beaconhttp.HandleEndpointFunc(h.GetEthV1BeaconStatesPendingDeposits))
+	mux.Get("/eth/v1/beacon/states/{state_id}/pending_partial_withdrawals", beaconhttp.HandleEndpointFunc(h.GetEthV1BeaconStatesPendingPartialWithdrawals))
+	mux.Get("/eth/v1/beacon/states/{state_id}/pending_consolidations", beaconhttp.HandleEndpointFunc(h.GetEthV1BeaconStatesPendingConsolidations))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	for stateId, s := range map[string]*state.CachingBeaconState{
+		"head": headState,
+		strconv.FormatUint(parentState.Slot(), 10): parentState,
+	} {
+		t.Run(stateId, func(t *testing.T) {
+			url := server.URL + "/eth/v1/beacon/states/" + stateId
+			require.NotZero(t, s.GetPendingDeposits().Len())
+			checkPendingQueue(t, url+"/pending_deposits", s.GetPendingDeposits())
+			checkPendingQueue(t, url+"/pending_partial_withdrawals", s.GetPendingPartialWithdrawals())
+			checkPendingQueue(t, url+"/pending_consolidations", s.GetPendingConsolidations())
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/erigontech/erigon/cl/beacon/beaconhttp"
+	"github.com/erigontech/erigon/cl/persistence/state/historical_states_reader"
+)
+
+// GetEthV1DebugBeaconStateDiff serves the difference between the archived states at the from and to slots.
+func (a *ApiHandler) GetEthV1DebugBeaconStateDiff(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	ctx := r.Context()
+
+	fromSlot, err := beaconhttp.Uint64FromQueryParams(r, "from")
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err)
+	}
+	toSlot, err := beaconhttp.Uint64FromQueryParams(r, "to")
+	if err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err)
+	}
+	if fromSlot == nil || toSlot == nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, errors.New("from and to slots are required"))
+	}
+	if *fromSlot > *toSlot {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Errorf("from slot %d is after to slot %d", *fromSlot, *toSlot))
+	}
+
+	tx, err := a.indiciesDB.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	diff, err := a.stateReader.ReadStateDiff(ctx, tx, *fromSlot, *toSlot)
+	if errors.Is(err, historical_states_reader.ErrStateNotAvailable) {
+		return nil, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Errorf("%w, node may not be archive or it still processing historical states", err))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newBeaconResponse(diff).WithFinalized(true), nil
+}
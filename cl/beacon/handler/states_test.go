@@ -554,3 +554,43 @@ func TestGetRandao(t *testing.T) {
 		})
 	}
 }
+
+func TestGetStatePendingQueuesPreElectra(t *testing.T) {
+	_, blocks, _, _, postState, handler, _, _, fcu, _ := setupTestingHandler(t, clparams.Phase0Version, log.Root(), false)
+
+	var err error
+	fcu.HeadVal, err = blocks[len(blocks)-1].Block.HashSSZ()
+	require.NoError(t, err)
+	fcu.HeadSlotVal = blocks[len(blocks)-1].Block.Slot
+
+	server := httptest.NewServer(handler.mux)
+	defer server.Close()
+
+	cases := []struct {
+		blockID string
+		code    int
+	}{
+		{
+			blockID: "head",
+			code:    http.StatusBadRequest,
+		},
+		{
+			blockID: strconv.FormatInt(int64(postState.Slot()), 10),
+			code:    http.StatusBadRequest,
+		},
+		{
+			blockID: "0x" + common.Bytes2Hex(make([]byte, 32)),
+			code:    http.StatusNotFound,
+		},
+	}
+	for _, queue := range []string{"pending_deposits", "pending_partial_withdrawals", "pending_consolidations"} {
+		for _, c := range cases {
+			t.Run(queue+"/"+c.blockID, func(t *testing.T) {
+				resp, err := http.Get(server.URL + "/eth/v1/beacon/states/" + c.blockID + "/" + queue)
+				require.NoError(t, err)
+				defer resp.Body.Close()
+				require.Equal(t, c.code, resp.StatusCode)
+			})
+		}
+	}
+}
@@ -175,6 +175,7 @@ func setupTestingHandler(t *testing.T, v clparams.StateVersion, logger log.Logge
 		nil,
 		nil,
 		false,
+		nil,
 	) // TODO: add tests
 	h.Init()
 	return
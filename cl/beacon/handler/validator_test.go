@@ -75,6 +75,7 @@ func (t *validatorTestSuite) SetupTest() {
 		nil,
 		nil,
 		false,
+		nil,
 	)
 	t.gomockCtrl = gomockCtrl
 }
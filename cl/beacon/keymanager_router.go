@@ -0,0 +1,56 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package beacon
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon/rpc"
+)
+
+// ListenAndServeKeymanager serves the keymanager API on its own listener, apart from the beacon API: it manages the
+// keys of the validators, so every request must carry a JWT signed with jwtSecret.
+func ListenAndServeKeymanager(keymanagerHandler http.Handler, addr string, jwtSecret []byte) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Warn("[Keymanager API] Failed to start listening", "addr", addr, "err", err)
+		return err
+	}
+	defer listener.Close()
+
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !rpc.CheckJwtSecret(w, r, jwtSecret) {
+				log.Debug("[Keymanager API] Unauthorized request", "method", r.Method, "path", r.URL.Path)
+				return
+			}
+			start := time.Now()
+			keymanagerHandler.ServeHTTP(w, r)
+			log.Trace("[Keymanager API] Request", "method", r.Method, "path", r.URL.Path, "time", time.Since(start))
+		}),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	log.Info("[Keymanager API] Listening", "addr", addr)
+	if err := server.Serve(listener); err != nil {
+		log.Warn("[Keymanager API] failed to start serving", "addr", addr, "err", err)
+		return err
+	}
+	return nil
+}
@@ -27,6 +27,7 @@ import (
 	"os"
 	"path"
 	"strconv"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v2"
@@ -54,11 +55,30 @@ type CaplinConfig struct {
 	NetworkId NetworkType
 	// DisableCheckpointSync is optional and is used to disable checkpoint sync used by default in the node
 	DisabledCheckpointSync bool
+	// CheckpointSyncStateFile and CheckpointSyncBlockFile are a checkpoint state and its block (.ssz or .ssz_snappy)
+	// to start from instead of a checkpoint sync endpoint, they must match CheckpointSyncBlockRoot.
+	CheckpointSyncStateFile string
+	CheckpointSyncBlockFile string
+	CheckpointSyncBlockRoot common.Hash
 	// CaplinMeVRelayUrl is optional and is used to connect to the external builder service.
-	// If it's set, the node will start in builder mode
+	// If it's set, the node will start in builder mode. It may be a comma separated list of relays.
 	MevRelayUrl string
+	// MevRelayTimeout is the time the relays have to serve a bid, MevMinBid the lowest bid (in ether) taken over the
+	// local payload
+	MevRelayTimeout time.Duration
+	MevMinBid       string
 	// EnableValidatorMonitor is used to enable the validator monitor metrics and corresponding logs
 	EnableValidatorMonitor bool
+	// Built-in validator client, enabled when ValidatorKeystoresDir, ValidatorWeb3SignerUrl or KeymanagerAddr is set
+	ValidatorKeystoresDir   string // EIP-2335 keystores to load
+	ValidatorPasswordsPath  string // password file shared by all the keystores, or directory with one <keystore>.txt each
+	ValidatorWeb3SignerUrl  string // remote signer holding the keys
+	ValidatorWeb3SignerKeys string // comma separated public keys to use from the remote signer, all of them if empty
+	ValidatorFeeRecipient   common.Address
+	ValidatorGraffiti       string
+	// Keymanager API of the built-in validator client, served when KeymanagerAddr is set
+	KeymanagerAddr          string
+	KeymanagerJwtSecretPath string // generated in the validator directory if empty
 
 	// Devnets config
 	CustomConfigPath       string
@@ -96,10 +116,30 @@ func (c CaplinConfig) HaveInvalidDevnetParams() bool {
 	return c.CustomConfigPath == "" || c.CustomGenesisStatePath == ""
 }
 
+// CheckpointSyncFromFile returns whether the checkpoint is read from local files.
+func (c CaplinConfig) CheckpointSyncFromFile() bool {
+	return c.CheckpointSyncStateFile != ""
+}
+
 func (c CaplinConfig) RelayUrlExist() bool {
 	return c.MevRelayUrl != ""
 }
 
+// RelayUrls returns the urls of all the relays.
+func (c CaplinConfig) RelayUrls() []string {
+	var urls []string
+	for _, u := range strings.Split(c.MevRelayUrl, ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
+func (c CaplinConfig) ValidatorClientEnabled() bool {
+	return c.ValidatorKeystoresDir != "" || c.ValidatorWeb3SignerUrl != "" || c.KeymanagerAddr != ""
+}
+
 type NetworkType int
 
 const CustomNetwork NetworkType = -1
@@ -209,6 +249,7 @@ type NetworkConfig struct {
 	Eth2key                    string `yaml:"-" json:"-"` // ETH2Key is the ENR key of the Ethereum consensus object in an enr.
 	AttSubnetKey               string `yaml:"-" json:"-"` // AttSubnetKey is the ENR key of the subnet bitfield in the enr.
 	SyncCommsSubnetKey         string `yaml:"-" json:"-"` // SyncCommsSubnetKey is the ENR key of the sync committee subnet bitfield in the enr.
+	CustodyGroupCountKey       string `yaml:"-" json:"-"` // CustodyGroupCountKey is the ENR key of the custody group count in the enr.
 	MinimumPeersInSubnetSearch uint64 `yaml:"-" json:"-"` // PeersInSubnetSearch is the required amount of peers that we need to be able to lookup in a subnet search.
 
 	BootNodes   []string `yaml:"-" json:"-"`
@@ -231,6 +272,7 @@ var NetworkConfigs map[NetworkType]NetworkConfig = map[NetworkType]NetworkConfig
 		Eth2key:                         "eth2",
 		AttSubnetKey:                    "attnets",
 		SyncCommsSubnetKey:              "syncnets",
+		CustodyGroupCountKey:            "cgc",
 		MinimumPeersInSubnetSearch:      20,
 		BootNodes:                       MainnetBootstrapNodes,
 	},
@@ -250,6 +292,7 @@ var NetworkConfigs map[NetworkType]NetworkConfig = map[NetworkType]NetworkConfig
 		Eth2key:                         "eth2",
 		AttSubnetKey:                    "attnets",
 		SyncCommsSubnetKey:              "syncnets",
+		CustodyGroupCountKey:            "cgc",
 		MinimumPeersInSubnetSearch:      20,
 		BootNodes:                       SepoliaBootstrapNodes,
 	},
@@ -269,6 +312,7 @@ var NetworkConfigs map[NetworkType]NetworkConfig = map[NetworkType]NetworkConfig
 		Eth2key:                         "eth2",
 		AttSubnetKey:                    "attnets",
 		SyncCommsSubnetKey:              "syncnets",
+		CustodyGroupCountKey:            "cgc",
 		MinimumPeersInSubnetSearch:      20,
 		BootNodes:                       GnosisBootstrapNodes,
 	},
@@ -288,6 +332,7 @@ var NetworkConfigs map[NetworkType]NetworkConfig = map[NetworkType]NetworkConfig
 		Eth2key:                         "eth2",
 		AttSubnetKey:                    "attnets",
 		SyncCommsSubnetKey:              "syncnets",
+		CustodyGroupCountKey:            "cgc",
 		MinimumPeersInSubnetSearch:      20,
 		BootNodes:                       ChiadoBootstrapNodes,
 	},
@@ -307,6 +352,7 @@ var NetworkConfigs map[NetworkType]NetworkConfig = map[NetworkType]NetworkConfig
 		Eth2key:                         "eth2",
 		AttSubnetKey:                    "attnets",
 		SyncCommsSubnetKey:              "syncnets",
+		CustodyGroupCountKey:            "cgc",
 		MinimumPeersInSubnetSearch:      20,
 		BootNodes:                       HoleskyBootstrapNodes,
 	},
@@ -326,6 +372,7 @@ var NetworkConfigs map[NetworkType]NetworkConfig = map[NetworkType]NetworkConfig
 		Eth2key:                         "eth2",
 		AttSubnetKey:                    "attnets",
 		SyncCommsSubnetKey:              "syncnets",
+		CustodyGroupCountKey:            "cgc",
 		MinimumPeersInSubnetSearch:      20,
 		BootNodes:                       HoodiBootstrapNodes,
 	},
@@ -377,6 +424,11 @@ func (b *BeaconChainConfig) MinSlotsForBlobsSidecarsRequest() uint64 {
 	return b.MinEpochsForBlobSidecarsRequests * b.SlotsPerEpoch
 }
 
+// MinSlotsForDataColumnSidecarsRequest equal to MIN_EPOCHS_FOR_DATA_COLUMN_SIDECARS_REQUESTS * SLOTS_PER_EPOCH
+func (b *BeaconChainConfig) MinSlotsForDataColumnSidecarsRequest() uint64 {
+	return b.MinEpochsForDataColumnSidecarsRequests * b.SlotsPerEpoch
+}
+
 type ConfigDurationSec time.Duration
 
 func (d *ConfigDurationSec) MarshalJSON() ([]byte, error) {
@@ -614,13 +666,15 @@ type BeaconChainConfig struct {
 	WhiskProposerSelectionGap    uint64 `yaml:"WHISK_PROPOSER_SELECTION_GAP" spec:"true" json:"WHISK_PROPOSER_SELECTION_GAP,string"`         // WhiskProposerSelectionGap defines the proposer selection gap.
 
 	// EIP7594
-	NumberOfColumns              uint64 `yaml:"NUMBER_OF_COLUMNS" spec:"true" json:"NUMBER_OF_COLUMNS,string"`                               // NumberOfColumns defines the number of columns in the extended matrix.
-	MaxCellsInExtendedMatrix     uint64 `yaml:"MAX_CELLS_IN_EXTENDED_MATRIX" spec:"true" json:"MAX_CELLS_IN_EXTENDED_MATRIX,string"`         // MaxCellsInExtendedMatrix defines the maximum number of cells in the extended matrix.
-	DataColumnSidecarSubnetCount uint64 `yaml:"DATA_COLUMN_SIDECAR_SUBNET_COUNT" spec:"true" json:"DATA_COLUMN_SIDECAR_SUBNET_COUNT,string"` // DataColumnSidecarSubnetCount defines the number of sidecars in the data column subnet.
-	MaxRequestDataColumnSidecars uint64 `yaml:"MAX_REQUEST_DATA_COLUMN_SIDECARS" spec:"true" json:"MAX_REQUEST_DATA_COLUMN_SIDECARS,string"` // MaxRequestDataColumnSidecars defines the maximum number of data column sidecars that can be requested.
-	SamplesPerSlot               uint64 `yaml:"SAMPLES_PER_SLOT" spec:"true" json:"SAMPLES_PER_SLOT,string"`                                 // SamplesPerSlot defines the number of samples per slot.
-	CustodyRequirement           uint64 `yaml:"CUSTODY_REQUIREMENT" spec:"true" json:"CUSTODY_REQUIREMENT,string"`                           // CustodyRequirement defines the custody requirement.
-	TargetNumberOfPeers          uint64 `yaml:"TARGET_NUMBER_OF_PEERS" spec:"true" json:"TARGET_NUMBER_OF_PEERS,string"`                     // TargetNumberOfPeers defines the target number of peers.
+	NumberOfColumns                        uint64 `yaml:"NUMBER_OF_COLUMNS" spec:"true" json:"NUMBER_OF_COLUMNS,string"`                                                       // NumberOfColumns defines the number of columns in the extended matrix.
+	MaxCellsInExtendedMatrix               uint64 `yaml:"MAX_CELLS_IN_EXTENDED_MATRIX" spec:"true" json:"MAX_CELLS_IN_EXTENDED_MATRIX,string"`                                 // MaxCellsInExtendedMatrix defines the maximum number of cells in the extended matrix.
+	NumberOfCustodyGroups                  uint64 `yaml:"NUMBER_OF_CUSTODY_GROUPS" spec:"true" json:"NUMBER_OF_CUSTODY_GROUPS,string"`                                         // NumberOfCustodyGroups defines the number of custody groups the columns are split into.
+	DataColumnSidecarSubnetCount           uint64 `yaml:"DATA_COLUMN_SIDECAR_SUBNET_COUNT" spec:"true" json:"DATA_COLUMN_SIDECAR_SUBNET_COUNT,string"`                         // DataColumnSidecarSubnetCount defines the number of sidecars in the data column subnet.
+	MaxRequestDataColumnSidecars           uint64 `yaml:"MAX_REQUEST_DATA_COLUMN_SIDECARS" spec:"true" json:"MAX_REQUEST_DATA_COLUMN_SIDECARS,string"`                         // MaxRequestDataColumnSidecars defines the maximum number of data column sidecars that can be requested.
+	SamplesPerSlot                         uint64 `yaml:"SAMPLES_PER_SLOT" spec:"true" json:"SAMPLES_PER_SLOT,string"`                                                         // SamplesPerSlot defines the number of samples per slot.
+	MinEpochsForDataColumnSidecarsRequests uint64 `yaml:"MIN_EPOCHS_FOR_DATA_COLUMN_SIDECARS_REQUESTS" spec:"true" json:"MIN_EPOCHS_FOR_DATA_COLUMN_SIDECARS_REQUESTS,string"` // MinEpochsForDataColumnSidecarsRequests defines the number of epochs data column sidecars are served for.
+	CustodyRequirement                     uint64 `yaml:"CUSTODY_REQUIREMENT" spec:"true" json:"CUSTODY_REQUIREMENT,string"`                                                   // CustodyRequirement defines the custody requirement.
+	TargetNumberOfPeers                    uint64 `yaml:"TARGET_NUMBER_OF_PEERS" spec:"true" json:"TARGET_NUMBER_OF_PEERS,string"`                                             // TargetNumberOfPeers defines the target number of peers.
 
 	// Electra
 	MinPerEpochChurnLimitElectra          uint64 `yaml:"MIN_PER_EPOCH_CHURN_LIMIT_ELECTRA" spec:"true" json:"MIN_PER_EPOCH_CHURN_LIMIT_ELECTRA,string"`                   // MinPerEpochChurnLimitElectra defines the minimum per epoch churn limit for Electra.
@@ -906,13 +960,15 @@ var MainnetBeaconConfig BeaconChainConfig = BeaconChainConfig{
 	WhiskEpochsPerShufflingPhase: 256,
 	WhiskProposerSelectionGap:    2,
 
-	NumberOfColumns:              128,
-	MaxCellsInExtendedMatrix:     768,
-	DataColumnSidecarSubnetCount: 32,
-	MaxRequestDataColumnSidecars: 16384,
-	SamplesPerSlot:               8,
-	CustodyRequirement:           1,
-	TargetNumberOfPeers:          70,
+	NumberOfColumns:                        128,
+	MaxCellsInExtendedMatrix:               768,
+	NumberOfCustodyGroups:                  128,
+	DataColumnSidecarSubnetCount:           32,
+	MaxRequestDataColumnSidecars:           16384,
+	SamplesPerSlot:                         8,
+	MinEpochsForDataColumnSidecarsRequests: 4096,
+	CustodyRequirement:                     1,
+	TargetNumberOfPeers:                    70,
 
 	// Electra
 	MinPerEpochChurnLimitElectra:          128_000_000_000,
@@ -0,0 +1,197 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package cltypes
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon-lib/common/length"
+	"github.com/erigontech/erigon-lib/types/clonable"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/merkle_tree"
+	ssz2 "github.com/erigontech/erigon/cl/ssz"
+	"github.com/erigontech/erigon/cl/utils"
+)
+
+const (
+	// BYTES_PER_CELL = FIELD_ELEMENTS_PER_CELL * BYTES_PER_FIELD_ELEMENT
+	BYTES_PER_CELL = 64 * BYTES_PER_FIELD_ELEMENT
+	// KzgCommitmentsInclusionProofDepth is the depth of the proof of the blob kzg commitments in the block body
+	KzgCommitmentsInclusionProofDepth = 4
+	// maxColumnsPerRequest is NUMBER_OF_COLUMNS, the limit of the column lists of the requests
+	maxColumnsPerRequest = 128
+)
+
+var (
+	cellT = reflect.TypeOf(Cell{})
+
+	_ ssz2.SizedObjectSSZ        = (*Cell)(nil)
+	_ solid.EncodableHashableSSZ = (*DataColumnSidecar)(nil)
+	_ ssz2.SizedObjectSSZ        = (*DataColumnSidecar)(nil)
+	_ solid.EncodableHashableSSZ = (*DataColumnsByRootIdentifier)(nil)
+)
+
+// Cell is a cell of the extended blob, the unit of data availability sampling.
+type Cell [BYTES_PER_CELL]byte
+
+func (c *Cell) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hexutil.Bytes(c[:]))
+}
+
+func (c *Cell) UnmarshalJSON(in []byte) error {
+	return hexutil.UnmarshalFixedJSON(cellT, in, c[:])
+}
+
+func (c *Cell) Clone() clonable.Clonable {
+	return &Cell{}
+}
+
+func (c *Cell) DecodeSSZ(buf []byte, version int) error {
+	return ssz2.UnmarshalSSZ(buf, version, c[:])
+}
+
+func (c *Cell) EncodeSSZ(buf []byte) ([]byte, error) {
+	return append(buf, c[:]...), nil
+}
+
+func (c *Cell) EncodingSizeSSZ() int {
+	return BYTES_PER_CELL
+}
+
+func (c *Cell) Static() bool {
+	return true
+}
+
+func (c *Cell) HashSSZ() ([32]byte, error) {
+	return merkle_tree.BytesRoot(c[:])
+}
+
+// DataColumnSidecar is a column of the extended blobs matrix of a block, along with the proofs of its cells.
+type DataColumnSidecar struct {
+	Index                        uint64                         `json:"index,string"`
+	Column                       *solid.ListSSZ[*Cell]          `json:"column"`
+	KzgCommitments               *solid.ListSSZ[*KZGCommitment] `json:"kzg_commitments"`
+	KzgProofs                    *solid.ListSSZ[*KZGProof]      `json:"kzg_proofs"`
+	SignedBlockHeader            *SignedBeaconBlockHeader       `json:"signed_block_header"`
+	KzgCommitmentsInclusionProof solid.HashVectorSSZ            `json:"kzg_commitments_inclusion_proof"`
+}
+
+func NewDataColumnSidecar() *DataColumnSidecar {
+	return &DataColumnSidecar{
+		Column:                       solid.NewStaticListSSZ[*Cell](MaxBlobsCommittmentsPerBlock, BYTES_PER_CELL),
+		KzgCommitments:               solid.NewStaticListSSZ[*KZGCommitment](MaxBlobsCommittmentsPerBlock, length.Bytes48),
+		KzgProofs:                    solid.NewStaticListSSZ[*KZGProof](MaxBlobsCommittmentsPerBlock, length.Bytes48),
+		SignedBlockHeader:            &SignedBeaconBlockHeader{Header: &BeaconBlockHeader{}},
+		KzgCommitmentsInclusionProof: solid.NewHashVector(KzgCommitmentsInclusionProofDepth),
+	}
+}
+
+func (d *DataColumnSidecar) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, d.getSchema()...)
+}
+
+func (d *DataColumnSidecar) DecodeSSZ(buf []byte, version int) error {
+	*d = *NewDataColumnSidecar()
+	return ssz2.UnmarshalSSZ(buf, version, d.getSchema()...)
+}
+
+func (d *DataColumnSidecar) EncodingSizeSSZ() int {
+	return length.BlockNum + 3*4 + d.Column.EncodingSizeSSZ() + d.KzgCommitments.EncodingSizeSSZ() + d.KzgProofs.EncodingSizeSSZ() +
+		d.SignedBlockHeader.EncodingSizeSSZ() + KzgCommitmentsInclusionProofDepth*length.Hash
+}
+
+func (d *DataColumnSidecar) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(d.getSchema()...)
+}
+
+func (d *DataColumnSidecar) Clone() clonable.Clonable {
+	return NewDataColumnSidecar()
+}
+
+func (d *DataColumnSidecar) Static() bool {
+	return false
+}
+
+func (d *DataColumnSidecar) UnmarshalJSON(buf []byte) error {
+	type dataColumnSidecar DataColumnSidecar
+	tmp := dataColumnSidecar(*NewDataColumnSidecar())
+	if err := json.Unmarshal(buf, &tmp); err != nil {
+		return err
+	}
+	*d = DataColumnSidecar(tmp)
+	return nil
+}
+
+func (d *DataColumnSidecar) getSchema() []interface{} {
+	return []interface{}{&d.Index, d.Column, d.KzgCommitments, d.KzgProofs, d.SignedBlockHeader, d.KzgCommitmentsInclusionProof}
+}
+
+// VerifyDataColumnSidecarInclusionProof checks that the kzg commitments of the sidecar are the ones of the block
+// body committed to by its header.
+func VerifyDataColumnSidecarInclusionProof(sidecar *DataColumnSidecar) bool {
+	if sidecar.KzgCommitmentsInclusionProof == nil || sidecar.KzgCommitmentsInclusionProof.Length() != KzgCommitmentsInclusionProofDepth {
+		return false
+	}
+	leaf, err := sidecar.KzgCommitments.HashSSZ()
+	if err != nil {
+		return false
+	}
+	branch := make([]common.Hash, KzgCommitmentsInclusionProofDepth)
+	for i := range branch {
+		branch[i] = sidecar.KzgCommitmentsInclusionProof.Get(i)
+	}
+	// blob_kzg_commitments is the field 11 of the body
+	return utils.IsValidMerkleBranch(leaf, branch, KzgCommitmentsInclusionProofDepth, 11, sidecar.SignedBlockHeader.Header.BodyRoot)
+}
+
+// DataColumnsByRootIdentifier requests some columns of a block.
+type DataColumnsByRootIdentifier struct {
+	BlockRoot common.Hash         `json:"block_root"`
+	Columns   solid.Uint64ListSSZ `json:"columns"`
+}
+
+func NewDataColumnsByRootIdentifier() *DataColumnsByRootIdentifier {
+	return &DataColumnsByRootIdentifier{Columns: solid.NewUint64ListSSZ(maxColumnsPerRequest)}
+}
+
+func (d *DataColumnsByRootIdentifier) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, d.BlockRoot[:], d.Columns)
+}
+
+func (d *DataColumnsByRootIdentifier) DecodeSSZ(buf []byte, version int) error {
+	d.Columns = solid.NewUint64ListSSZ(maxColumnsPerRequest)
+	return ssz2.UnmarshalSSZ(buf, version, d.BlockRoot[:], d.Columns)
+}
+
+func (d *DataColumnsByRootIdentifier) EncodingSizeSSZ() int {
+	return length.Hash + 4 + d.Columns.EncodingSizeSSZ()
+}
+
+func (d *DataColumnsByRootIdentifier) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(d.BlockRoot[:], d.Columns)
+}
+
+func (*DataColumnsByRootIdentifier) Clone() clonable.Clonable {
+	return NewDataColumnsByRootIdentifier()
+}
+
+func (*DataColumnsByRootIdentifier) Static() bool {
+	return false
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package cltypes
+
+import (
+	"encoding/json"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/length"
+	"github.com/erigontech/erigon-lib/types/clonable"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/merkle_tree"
+	ssz2 "github.com/erigontech/erigon/cl/ssz"
+)
+
+// DepositTreeDepth is the depth of the deposit contract merkle tree (DEPOSIT_CONTRACT_TREE_DEPTH)
+const DepositTreeDepth = 32
+
+var (
+	_ solid.EncodableHashableSSZ = (*DepositSnapshot)(nil)
+	_ ssz2.SizedObjectSSZ        = (*DepositSnapshot)(nil)
+)
+
+// DepositSnapshot is the EIP-4881 snapshot of the finalized part of the deposit tree.
+//
+// class DepositTreeSnapshot(Container):
+//
+//	finalized: List[Hash32, DEPOSIT_CONTRACT_DEPTH]
+//	deposit_root: Hash32
+//	deposit_count: uint64
+//	execution_block_hash: Hash32
+//	execution_block_height: uint64
+type DepositSnapshot struct {
+	Finalized            solid.HashListSSZ `json:"finalized"`
+	DepositRoot          common.Hash       `json:"deposit_root"`
+	DepositCount         uint64            `json:"deposit_count,string"`
+	ExecutionBlockHash   common.Hash       `json:"execution_block_hash"`
+	ExecutionBlockHeight uint64            `json:"execution_block_height,string"`
+}
+
+func NewDepositSnapshot() *DepositSnapshot {
+	return &DepositSnapshot{Finalized: solid.NewHashList(DepositTreeDepth)}
+}
+
+func (d *DepositSnapshot) EncodingSizeSSZ() int {
+	return 4 + 2*length.Hash + 2*8 + d.Finalized.EncodingSizeSSZ()
+}
+
+func (d *DepositSnapshot) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, d.Finalized, d.DepositRoot[:], d.DepositCount, d.ExecutionBlockHash[:], d.ExecutionBlockHeight)
+}
+
+func (d *DepositSnapshot) DecodeSSZ(buf []byte, version int) error {
+	d.Finalized = solid.NewHashList(DepositTreeDepth)
+	return ssz2.UnmarshalSSZ(buf, version, d.Finalized, d.DepositRoot[:], &d.DepositCount, d.ExecutionBlockHash[:], &d.ExecutionBlockHeight)
+}
+
+func (d *DepositSnapshot) Clone() clonable.Clonable {
+	return NewDepositSnapshot()
+}
+
+func (d *DepositSnapshot) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(d.Finalized, d.DepositRoot[:], d.DepositCount, d.ExecutionBlockHash[:], d.ExecutionBlockHeight)
+}
+
+func (d *DepositSnapshot) Static() bool {
+	return false
+}
+
+func (d *DepositSnapshot) UnmarshalJSON(b []byte) error {
+	type depositSnapshot DepositSnapshot
+	c := depositSnapshot{Finalized: solid.NewHashList(DepositTreeDepth)}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return err
+	}
+	*d = DepositSnapshot(c)
+	return nil
+}
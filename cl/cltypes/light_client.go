@@ -17,6 +17,7 @@
 package cltypes
 
 import (
+	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/types/clonable"
 	"github.com/erigontech/erigon/cl/clparams"
 	"github.com/erigontech/erigon/cl/cltypes/solid"
@@ -104,6 +105,25 @@ func (l *LightClientHeader) Clone() clonable.Clonable {
 	return NewLightClientHeader(l.version)
 }
 
+// Upgrade converts the header to the format of a later fork, all the headers of an update must share the fork of the
+// attested header.
+func (l *LightClientHeader) Upgrade(version clparams.StateVersion) {
+	if version <= l.version {
+		return
+	}
+	if version >= clparams.CapellaVersion {
+		if l.version < clparams.CapellaVersion {
+			// the execution payload is not known before capella, it is left empty
+			l.ExecutionPayloadHeader = NewEth1Header(version)
+			l.ExecutionBranch = solid.NewHashVector(ExecutionBranchSize)
+		} else {
+			l.ExecutionPayloadHeader = l.ExecutionPayloadHeader.Copy()
+			l.ExecutionPayloadHeader.SetVersion(version)
+		}
+	}
+	l.version = version
+}
+
 func (l *LightClientHeader) getSchema() []interface{} {
 	schema := []interface{}{
 		l.Beacon,
@@ -128,7 +148,7 @@ func NewLightClientUpdate(version clparams.StateVersion) *LightClientUpdate {
 	return &LightClientUpdate{
 		AttestedHeader:          NewLightClientHeader(version),
 		NextSyncCommittee:       &solid.SyncCommittee{},
-		NextSyncCommitteeBranch: solid.NewHashVector(getCurrentSyncCommitteeBranchSize(version)),
+		NextSyncCommitteeBranch: solid.NewHashVector(getSyncCommitteeBranchSize(version)),
 		FinalizedHeader:         NewLightClientHeader(version),
 		FinalityBranch:          solid.NewHashVector(getFinalizedBranchSize(version)),
 		SyncAggregate:           &SyncAggregate{},
@@ -142,7 +162,7 @@ func (l *LightClientUpdate) EncodeSSZ(buf []byte) ([]byte, error) {
 func (l *LightClientUpdate) DecodeSSZ(buf []byte, version int) error {
 	l.AttestedHeader = NewLightClientHeader(clparams.StateVersion(version))
 	l.NextSyncCommittee = &solid.SyncCommittee{}
-	l.NextSyncCommitteeBranch = solid.NewHashVector(getCurrentSyncCommitteeBranchSize(clparams.StateVersion(version)))
+	l.NextSyncCommitteeBranch = solid.NewHashVector(getSyncCommitteeBranchSize(clparams.StateVersion(version)))
 	l.FinalizedHeader = NewLightClientHeader(clparams.StateVersion(version))
 	l.FinalityBranch = solid.NewHashVector(getFinalizedBranchSize(clparams.StateVersion(version)))
 	l.SyncAggregate = &SyncAggregate{}
@@ -170,6 +190,16 @@ func (l *LightClientUpdate) HashSSZ() ([32]byte, error) {
 	return merkle_tree.HashTreeRoot(l.AttestedHeader, l.NextSyncCommittee, l.NextSyncCommitteeBranch, l.FinalizedHeader, l.FinalityBranch, l.SyncAggregate, &l.SignatureSlot)
 }
 
+// IsSyncCommitteeUpdate returns whether the update carries the next sync committee.
+func (l *LightClientUpdate) IsSyncCommitteeUpdate() bool {
+	return !isZeroBranch(l.NextSyncCommitteeBranch)
+}
+
+// IsFinalityUpdate returns whether the update carries a finalized header.
+func (l *LightClientUpdate) IsFinalityUpdate() bool {
+	return !isZeroBranch(l.FinalityBranch)
+}
+
 func (l *LightClientUpdate) Clone() clonable.Clonable {
 	v := clparams.Phase0Version
 	if l.AttestedHeader != nil {
@@ -346,3 +376,15 @@ func getSyncCommitteeBranchSize(version clparams.StateVersion) int {
 	}
 	return SyncCommitteeBranchSize
 }
+
+func isZeroBranch(branch solid.HashVectorSSZ) bool {
+	if branch == nil {
+		return true
+	}
+	for i := 0; i < branch.Length(); i++ {
+		if branch.Get(i) != (common.Hash{}) {
+			return false
+		}
+	}
+	return true
+}
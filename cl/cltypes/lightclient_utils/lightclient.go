@@ -86,7 +86,8 @@ func CreateLightClientUpdate(cfg *clparams.BeaconChainConfig, block *cltypes.Sig
 	}
 	updateAttestedPeriod := cfg.SyncCommitteePeriod(attestedBlock.Block.Slot)
 
-	update := cltypes.NewLightClientUpdate(block.Version())
+	// the branches are proofs against the attested state, so the update takes the format of its fork
+	update := cltypes.NewLightClientUpdate(attestedBlock.Version())
 	update.AttestedHeader, err = BlockToLightClientHeader(attestedBlock)
 	if err != nil {
 		return nil, err
@@ -101,6 +102,7 @@ func CreateLightClientUpdate(cfg *clparams.BeaconChainConfig, block *cltypes.Sig
 			if err != nil {
 				return nil, err
 			}
+			update.FinalizedHeader.Upgrade(attestedBlock.Version())
 			finalizedBeaconRoot, err := update.FinalizedHeader.Beacon.HashSSZ()
 			if err != nil {
 				return nil, err
@@ -118,6 +120,72 @@ func CreateLightClientUpdate(cfg *clparams.BeaconChainConfig, block *cltypes.Sig
 	return update, nil
 }
 
+// IsBetterUpdate implements the specs (is_better_update) to pick the best light client update of a sync committee
+// period.
+func IsBetterUpdate(cfg *clparams.BeaconChainConfig, newUpdate, oldUpdate *cltypes.LightClientUpdate) bool {
+	// Compare supermajority (> 2/3) sync committee participation
+	maxActiveParticipants := len(newUpdate.SyncAggregate.SyncCommiteeBits) * 8
+	newActiveParticipants := newUpdate.SyncAggregate.Sum()
+	oldActiveParticipants := oldUpdate.SyncAggregate.Sum()
+	newHasSupermajority := newActiveParticipants*3 >= maxActiveParticipants*2
+	oldHasSupermajority := oldActiveParticipants*3 >= maxActiveParticipants*2
+	if newHasSupermajority != oldHasSupermajority {
+		return newHasSupermajority
+	}
+	if !newHasSupermajority && newActiveParticipants != oldActiveParticipants {
+		return newActiveParticipants > oldActiveParticipants
+	}
+
+	// Compare presence of relevant sync committee
+	hasRelevantSyncCommittee := func(update *cltypes.LightClientUpdate) bool {
+		return update.IsSyncCommitteeUpdate() &&
+			cfg.SyncCommitteePeriod(update.AttestedHeader.Beacon.Slot) == cfg.SyncCommitteePeriod(update.SignatureSlot)
+	}
+	newHasRelevantSyncCommittee := hasRelevantSyncCommittee(newUpdate)
+	if newHasRelevantSyncCommittee != hasRelevantSyncCommittee(oldUpdate) {
+		return newHasRelevantSyncCommittee
+	}
+
+	// Compare indication of any finality
+	newHasFinality := newUpdate.IsFinalityUpdate()
+	if newHasFinality != oldUpdate.IsFinalityUpdate() {
+		return newHasFinality
+	}
+
+	// Compare sync committee finality
+	if newHasFinality {
+		hasSyncCommitteeFinality := func(update *cltypes.LightClientUpdate) bool {
+			return cfg.SyncCommitteePeriod(update.FinalizedHeader.Beacon.Slot) == cfg.SyncCommitteePeriod(update.AttestedHeader.Beacon.Slot)
+		}
+		newHasSyncCommitteeFinality := hasSyncCommitteeFinality(newUpdate)
+		if newHasSyncCommitteeFinality != hasSyncCommitteeFinality(oldUpdate) {
+			return newHasSyncCommitteeFinality
+		}
+	}
+
+	// Tiebreaker 1: Sync committee participation beyond supermajority
+	if newActiveParticipants != oldActiveParticipants {
+		return newActiveParticipants > oldActiveParticipants
+	}
+	// Tiebreaker 2: Prefer older data (fewer changes to best)
+	if newUpdate.AttestedHeader.Beacon.Slot != oldUpdate.AttestedHeader.Beacon.Slot {
+		return newUpdate.AttestedHeader.Beacon.Slot < oldUpdate.AttestedHeader.Beacon.Slot
+	}
+	// Tiebreaker 3: Prefer updates with earlier signature slots
+	return newUpdate.SignatureSlot < oldUpdate.SignatureSlot
+}
+
+// BestLightClientUpdate returns the better of two updates of the same period, either of which may be nil.
+func BestLightClientUpdate(cfg *clparams.BeaconChainConfig, a, b *cltypes.LightClientUpdate) *cltypes.LightClientUpdate {
+	if a == nil {
+		return b
+	}
+	if b == nil || !IsBetterUpdate(cfg, b, a) {
+		return a
+	}
+	return b
+}
+
 // def block_to_light_client_header(block: SignedBeaconBlock) -> LightClientHeader:
 //
 //	return LightClientHeader(
@@ -0,0 +1,87 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package lightclient_utils
+
+import (
+	"testing"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/stretchr/testify/require"
+)
+
+func testUpdate(participants int, attestedSlot, signatureSlot uint64, syncCommittee, finality bool) *cltypes.LightClientUpdate {
+	update := cltypes.NewLightClientUpdate(clparams.DenebVersion)
+	for i := 0; i < participants; i++ {
+		update.SyncAggregate.SyncCommiteeBits[i/8] |= 1 << (i % 8)
+	}
+	update.AttestedHeader.Beacon.Slot = attestedSlot
+	update.SignatureSlot = signatureSlot
+	if syncCommittee {
+		update.NextSyncCommitteeBranch.Set(0, common.Hash{1})
+	}
+	if finality {
+		update.FinalityBranch.Set(0, common.Hash{1})
+		update.FinalizedHeader.Beacon.Slot = attestedSlot - 64
+	}
+	return update
+}
+
+func TestIsBetterUpdate(t *testing.T) {
+	cfg := &clparams.MainnetBeaconConfig
+	period := cfg.SlotsPerEpoch * cfg.EpochsPerSyncCommitteePeriod
+	slot := 10*period + 100
+
+	supermajority := testUpdate(400, slot, slot+1, true, true)
+	minority := testUpdate(300, slot, slot+1, true, true)
+	require.True(t, IsBetterUpdate(cfg, supermajority, minority))
+	require.False(t, IsBetterUpdate(cfg, minority, supermajority))
+
+	// without supermajority, more participants win
+	require.True(t, IsBetterUpdate(cfg, testUpdate(300, slot, slot+1, false, false), testUpdate(200, slot, slot+1, true, true)))
+
+	// the next sync committee is only relevant when signed in the same period
+	withCommittee := testUpdate(400, slot, slot+1, true, false)
+	require.True(t, IsBetterUpdate(cfg, withCommittee, testUpdate(500, slot, slot+1, false, true)))
+	require.False(t, IsBetterUpdate(cfg, testUpdate(400, 11*period-1, 11*period, true, false), withCommittee))
+
+	// then finality
+	require.True(t, IsBetterUpdate(cfg, supermajority, withCommittee))
+
+	// then participation, then older data
+	require.True(t, IsBetterUpdate(cfg, testUpdate(450, slot+10, slot+11, true, true), supermajority))
+	require.True(t, IsBetterUpdate(cfg, supermajority, testUpdate(400, slot+10, slot+11, true, true)))
+	require.False(t, IsBetterUpdate(cfg, supermajority, supermajority))
+
+	require.Equal(t, supermajority, BestLightClientUpdate(cfg, nil, supermajority))
+	require.Equal(t, supermajority, BestLightClientUpdate(cfg, supermajority, minority))
+	require.Equal(t, supermajority, BestLightClientUpdate(cfg, minority, supermajority))
+	require.Nil(t, BestLightClientUpdate(cfg, nil, nil))
+}
+
+func TestLightClientHeaderUpgrade(t *testing.T) {
+	header := cltypes.NewLightClientHeader(clparams.BellatrixVersion)
+	header.Beacon.Slot = 42
+	header.Upgrade(clparams.ElectraVersion)
+	require.Equal(t, clparams.ElectraVersion, header.Version())
+	require.NotNil(t, header.ExecutionPayloadHeader)
+	require.Equal(t, cltypes.ExecutionBranchSize, header.ExecutionBranch.Length())
+
+	// the upgraded header has the size of the electra one
+	require.Equal(t, cltypes.NewLightClientHeader(clparams.ElectraVersion).EncodingSizeSSZ(), header.EncodingSizeSSZ())
+}
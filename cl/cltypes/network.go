@@ -25,26 +25,34 @@ import (
 	"github.com/erigontech/erigon-lib/types/clonable"
 	"github.com/erigontech/erigon-lib/types/ssz"
 
+	"github.com/erigontech/erigon/cl/cltypes/solid"
 	ssz2 "github.com/erigontech/erigon/cl/ssz"
 )
 
 type Metadata struct {
-	SeqNumber uint64
-	Attnets   [8]byte
-	Syncnets  *[1]byte
+	SeqNumber         uint64
+	Attnets           [8]byte
+	Syncnets          *[1]byte
+	CustodyGroupCount *uint64 // from metadata v3 (Fulu) onwards
 }
 
 func (m *Metadata) EncodeSSZ(buf []byte) ([]byte, error) {
 	if m.Syncnets == nil {
 		return ssz2.MarshalSSZ(buf, m.SeqNumber, m.Attnets[:])
 	}
-	return ssz2.MarshalSSZ(buf, m.SeqNumber, m.Attnets[:], m.Syncnets[:])
+	if m.CustodyGroupCount == nil {
+		return ssz2.MarshalSSZ(buf, m.SeqNumber, m.Attnets[:], m.Syncnets[:])
+	}
+	return ssz2.MarshalSSZ(buf, m.SeqNumber, m.Attnets[:], m.Syncnets[:], *m.CustodyGroupCount)
 }
 
 func (m *Metadata) EncodingSizeSSZ() (ret int) {
 	ret = 8 * 2
 	if m.Syncnets != nil {
 		ret += 1
+		if m.CustodyGroupCount != nil {
+			ret += 8
+		}
 	}
 	return
 }
@@ -60,6 +68,11 @@ func (m *Metadata) DecodeSSZ(buf []byte, _ int) error {
 	}
 	m.Syncnets = new([1]byte)
 	copy(m.Syncnets[:], buf[16:17])
+	if len(buf) < 25 {
+		return nil
+	}
+	custodyGroupCount := ssz.UnmarshalUint64SSZ(buf[17:])
+	m.CustodyGroupCount = &custodyGroupCount
 	return nil
 }
 
@@ -71,6 +84,9 @@ func (m *Metadata) MarshalJSON() ([]byte, error) {
 	if m.Syncnets != nil {
 		out["syncnets"] = hexutil.Bytes(m.Syncnets[:])
 	}
+	if m.CustodyGroupCount != nil {
+		out["custody_group_count"] = strconv.FormatUint(*m.CustodyGroupCount, 10)
+	}
 	// Attnets and syncnets are hex encoded
 	return json.Marshal(out)
 }
@@ -197,3 +213,31 @@ func (l *BlobsByRangeRequest) EncodingSizeSSZ() int {
 func (*BlobsByRangeRequest) Clone() clonable.Clonable {
 	return &BlobsByRangeRequest{}
 }
+
+// DataColumnSidecarsByRangeRequest requests some columns of the blocks in a range of slots.
+type DataColumnSidecarsByRangeRequest struct {
+	StartSlot uint64
+	Count     uint64
+	Columns   solid.Uint64ListSSZ
+}
+
+func NewDataColumnSidecarsByRangeRequest() *DataColumnSidecarsByRangeRequest {
+	return &DataColumnSidecarsByRangeRequest{Columns: solid.NewUint64ListSSZ(maxColumnsPerRequest)}
+}
+
+func (l *DataColumnSidecarsByRangeRequest) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, &l.StartSlot, &l.Count, l.Columns)
+}
+
+func (l *DataColumnSidecarsByRangeRequest) DecodeSSZ(buf []byte, _ int) error {
+	l.Columns = solid.NewUint64ListSSZ(maxColumnsPerRequest)
+	return ssz2.UnmarshalSSZ(buf, 0, &l.StartSlot, &l.Count, l.Columns)
+}
+
+func (l *DataColumnSidecarsByRangeRequest) EncodingSizeSSZ() int {
+	return 20 + l.Columns.EncodingSizeSSZ()
+}
+
+func (*DataColumnSidecarsByRangeRequest) Clone() clonable.Clonable {
+	return NewDataColumnSidecarsByRangeRequest()
+}
@@ -32,6 +32,15 @@ var testMetadata = &cltypes.Metadata{
 	Attnets:   [8]byte{1, 2, 3, 4, 5, 6, 7, 8},
 }
 
+var testCustodyGroupCount = uint64(4)
+
+var testMetadataV3 = &cltypes.Metadata{
+	SeqNumber:         99,
+	Attnets:           [8]byte{1, 2, 3, 4, 5, 6, 7, 8},
+	Syncnets:          &[1]byte{9},
+	CustodyGroupCount: &testCustodyGroupCount,
+}
+
 var testPing = &cltypes.Ping{
 	Id: 420,
 }
@@ -73,6 +82,7 @@ var testBlobRequestByRange = &cltypes.BlobsByRangeRequest{
 func TestMarshalNetworkTypes(t *testing.T) {
 	cases := []ssz.EncodableSSZ{
 		testMetadata,
+		testMetadataV3,
 		testPing,
 		testBlockRangeRequest,
 		testStatus,
@@ -82,6 +92,7 @@ func TestMarshalNetworkTypes(t *testing.T) {
 	}
 
 	unmarshalDestinations := []ssz.EncodableSSZ{
+		&cltypes.Metadata{},
 		&cltypes.Metadata{},
 		&cltypes.Ping{},
 		&cltypes.BeaconBlocksByRangeRequest{},
@@ -96,4 +107,5 @@ func TestMarshalNetworkTypes(t *testing.T) {
 		require.Equal(t, len(marshalledBytes), tc.EncodingSizeSSZ())
 		require.NoError(t, unmarshalDestinations[i].DecodeSSZ(marshalledBytes, int(clparams.CapellaVersion)))
 	}
+	require.Equal(t, testMetadataV3, unmarshalDestinations[1])
 }
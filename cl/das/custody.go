@@ -0,0 +1,120 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+// Package das implements the custody rules of PeerDAS (EIP-7594): which columns of the extended blobs matrix a
+// node is responsible for storing and serving.
+package das
+
+import (
+	"encoding/binary"
+	"fmt"
+	"slices"
+
+	"github.com/holiman/uint256"
+
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/utils"
+)
+
+// CustodyGroupCount returns the number of custody groups of the node, a supernode custodies all of them.
+func CustodyGroupCount(cfg *clparams.BeaconChainConfig, supernode bool) uint64 {
+	if supernode {
+		return cfg.NumberOfCustodyGroups
+	}
+	return cfg.CustodyRequirement
+}
+
+// GetCustodyGroups returns the sorted custody groups of the node nodeID, custodying custodyGroupCount groups.
+func GetCustodyGroups(cfg *clparams.BeaconChainConfig, nodeID [32]byte, custodyGroupCount uint64) ([]uint64, error) {
+	if custodyGroupCount > cfg.NumberOfCustodyGroups {
+		return nil, fmt.Errorf("custody group count %d is greater than the number of custody groups %d", custodyGroupCount, cfg.NumberOfCustodyGroups)
+	}
+	groups := make([]uint64, 0, custodyGroupCount)
+	if custodyGroupCount == cfg.NumberOfCustodyGroups {
+		for i := uint64(0); i < custodyGroupCount; i++ {
+			groups = append(groups, i)
+		}
+		return groups, nil
+	}
+
+	seen := make(map[uint64]struct{}, custodyGroupCount)
+	// the node id is a big endian uint256, incremented (and wrapping around) until enough groups are found.
+	currentID := new(uint256.Int).SetBytes32(nodeID[:])
+	one := uint256.NewInt(1)
+	for uint64(len(groups)) < custodyGroupCount {
+		idBytes := currentID.Bytes32()
+		slices.Reverse(idBytes[:]) // uint_to_bytes is little endian
+		hash := utils.Sha256(idBytes[:])
+		group := binary.LittleEndian.Uint64(hash[:8]) % cfg.NumberOfCustodyGroups
+		if _, ok := seen[group]; !ok {
+			seen[group] = struct{}{}
+			groups = append(groups, group)
+		}
+		currentID.Add(currentID, one)
+	}
+	slices.Sort(groups)
+	return groups, nil
+}
+
+// ComputeColumnsForCustodyGroup returns the columns of the custody group.
+func ComputeColumnsForCustodyGroup(cfg *clparams.BeaconChainConfig, custodyGroup uint64) ([]uint64, error) {
+	if custodyGroup >= cfg.NumberOfCustodyGroups {
+		return nil, fmt.Errorf("custody group %d out of range", custodyGroup)
+	}
+	columnsPerGroup := cfg.NumberOfColumns / cfg.NumberOfCustodyGroups
+	columns := make([]uint64, 0, columnsPerGroup)
+	for i := uint64(0); i < columnsPerGroup; i++ {
+		columns = append(columns, cfg.NumberOfCustodyGroups*i+custodyGroup)
+	}
+	return columns, nil
+}
+
+// CustodyColumns returns the sorted columns custodied by the node nodeID.
+func CustodyColumns(cfg *clparams.BeaconChainConfig, nodeID [32]byte, custodyGroupCount uint64) ([]uint64, error) {
+	groups, err := GetCustodyGroups(cfg, nodeID, custodyGroupCount)
+	if err != nil {
+		return nil, err
+	}
+	var columns []uint64
+	for _, group := range groups {
+		groupColumns, err := ComputeColumnsForCustodyGroup(cfg, group)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, groupColumns...)
+	}
+	slices.Sort(columns)
+	return columns, nil
+}
+
+// ComputeSubnetForDataColumnSidecar returns the gossip subnet on which the sidecar of the column is published.
+func ComputeSubnetForDataColumnSidecar(cfg *clparams.BeaconChainConfig, columnIndex uint64) uint64 {
+	return columnIndex % cfg.DataColumnSidecarSubnetCount
+}
+
+// CustodySubnets returns the sorted gossip subnets of the columns custodied by the node nodeID.
+func CustodySubnets(cfg *clparams.BeaconChainConfig, nodeID [32]byte, custodyGroupCount uint64) ([]uint64, error) {
+	columns, err := CustodyColumns(cfg, nodeID, custodyGroupCount)
+	if err != nil {
+		return nil, err
+	}
+	subnets := make([]uint64, 0, len(columns))
+	for _, column := range columns {
+		subnets = append(subnets, ComputeSubnetForDataColumnSidecar(cfg, column))
+	}
+	slices.Sort(subnets)
+	return slices.Compact(subnets), nil
+}
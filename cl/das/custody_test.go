@@ -0,0 +1,90 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package das
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon/cl/clparams"
+)
+
+func TestGetCustodyGroups(t *testing.T) {
+	cfg := &clparams.MainnetBeaconConfig
+	nodeID := [32]byte{0xde, 0xad, 0xbe, 0xef}
+
+	for _, count := range []uint64{0, 1, 4, 64, 127} {
+		groups, err := GetCustodyGroups(cfg, nodeID, count)
+		require.NoError(t, err)
+		require.Len(t, groups, int(count))
+		require.True(t, slices.IsSorted(groups))
+		require.Len(t, slices.Compact(slices.Clone(groups)), int(count))
+		for _, group := range groups {
+			require.Less(t, group, cfg.NumberOfCustodyGroups)
+		}
+		// the groups of a smaller custody are a subset of the bigger ones
+		if count > 0 {
+			smaller, err := GetCustodyGroups(cfg, nodeID, count-1)
+			require.NoError(t, err)
+			for _, group := range smaller {
+				require.Contains(t, groups, group)
+			}
+		}
+	}
+
+	all, err := GetCustodyGroups(cfg, nodeID, cfg.NumberOfCustodyGroups)
+	require.NoError(t, err)
+	require.Len(t, all, int(cfg.NumberOfCustodyGroups))
+
+	_, err = GetCustodyGroups(cfg, nodeID, cfg.NumberOfCustodyGroups+1)
+	require.Error(t, err)
+
+	// the node id wraps around at the max uint256
+	var maxID [32]byte
+	for i := range maxID {
+		maxID[i] = 0xff
+	}
+	groups, err := GetCustodyGroups(cfg, maxID, 8)
+	require.NoError(t, err)
+	require.Len(t, groups, 8)
+}
+
+func TestCustodyColumns(t *testing.T) {
+	cfg := &clparams.MainnetBeaconConfig
+
+	columns, err := ComputeColumnsForCustodyGroup(cfg, 3)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{3}, columns)
+	_, err = ComputeColumnsForCustodyGroup(cfg, cfg.NumberOfCustodyGroups)
+	require.Error(t, err)
+
+	nodeID := [32]byte{1}
+	columns, err = CustodyColumns(cfg, nodeID, cfg.CustodyRequirement*4)
+	require.NoError(t, err)
+	require.Len(t, columns, 4)
+	subnets, err := CustodySubnets(cfg, nodeID, cfg.CustodyRequirement*4)
+	require.NoError(t, err)
+	for _, column := range columns {
+		require.Contains(t, subnets, ComputeSubnetForDataColumnSidecar(cfg, column))
+	}
+
+	subnets, err = CustodySubnets(cfg, nodeID, cfg.NumberOfCustodyGroups)
+	require.NoError(t, err)
+	require.Len(t, subnets, int(cfg.DataColumnSidecarSubnetCount))
+}
@@ -448,3 +448,43 @@ func AddBlockRootToParentRootsIndex(tx kv.RwTx, parentRoot, blockRoot common.Has
 	roots = append(common.Copy(roots), blockRoot[:]...)
 	return tx.Put(kv.ParentRootToBlockRoots, parentRoot[:], roots)
 }
+
+// WriteLightClientUpdate stores the light client update of a sync committee period, prefixed by its version.
+func WriteLightClientUpdate(tx kv.RwTx, period uint64, update *cltypes.LightClientUpdate) error {
+	encoded, err := update.EncodeSSZ([]byte{byte(update.AttestedHeader.Version())})
+	if err != nil {
+		return err
+	}
+	return tx.Put(kv.LightClientUpdates, base_encoding.Encode64ToBytes4(period), encoded)
+}
+
+// ReadLowestLightClientUpdatePeriod returns the lowest sync committee period with a stored light client update.
+func ReadLowestLightClientUpdatePeriod(tx kv.Tx) (period uint64, ok bool, err error) {
+	cursor, err := tx.Cursor(kv.LightClientUpdates)
+	if err != nil {
+		return 0, false, err
+	}
+	defer cursor.Close()
+	k, _, err := cursor.First()
+	if err != nil || k == nil {
+		return 0, false, err
+	}
+	return base_encoding.Decode64FromBytes4(k), true, nil
+}
+
+// ReadLightClientUpdate reads the light client update of a sync committee period, nil if there is none.
+func ReadLightClientUpdate(tx kv.Tx, period uint64) (*cltypes.LightClientUpdate, error) {
+	encoded, err := tx.GetOne(kv.LightClientUpdates, base_encoding.Encode64ToBytes4(period))
+	if err != nil {
+		return nil, err
+	}
+	if len(encoded) == 0 {
+		return nil, nil
+	}
+	version := clparams.StateVersion(encoded[0])
+	update := cltypes.NewLightClientUpdate(version)
+	if err := update.DecodeSSZ(encoded[1:], int(version)); err != nil {
+		return nil, fmt.Errorf("corrupt light client update of period %d: %w", period, err)
+	}
+	return update, nil
+}
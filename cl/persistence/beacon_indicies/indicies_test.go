@@ -188,3 +188,40 @@ func TestWriteExecutionBlockHash(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, tHash2, tHash3)
 }
+
+func TestWriteLightClientUpdate(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	tx, _ := db.BeginRw(context.Background())
+	defer tx.Rollback()
+
+	update, err := ReadLightClientUpdate(tx, 3)
+	require.NoError(t, err)
+	require.Nil(t, update)
+	_, ok, err := ReadLowestLightClientUpdatePeriod(tx)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	for _, version := range []clparams.StateVersion{clparams.AltairVersion, clparams.DenebVersion, clparams.ElectraVersion} {
+		expected := cltypes.NewLightClientUpdate(version)
+		expected.SignatureSlot = 1234
+		expected.AttestedHeader.Beacon.Slot = 1233
+		expected.FinalityBranch.Set(0, common.Hash{1})
+		require.NoError(t, WriteLightClientUpdate(tx, 3, expected))
+
+		update, err = ReadLightClientUpdate(tx, 3)
+		require.NoError(t, err)
+		require.Equal(t, version, update.AttestedHeader.Version())
+		expectedRoot, err := expected.HashSSZ()
+		require.NoError(t, err)
+		root, err := update.HashSSZ()
+		require.NoError(t, err)
+		require.Equal(t, expectedRoot, root)
+	}
+
+	require.NoError(t, WriteLightClientUpdate(tx, 300, cltypes.NewLightClientUpdate(clparams.ElectraVersion)))
+	lowest, ok, err := ReadLowestLightClientUpdatePeriod(tx)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, uint64(3), lowest)
+}
@@ -49,6 +49,12 @@ type BlobStorage interface {
 	ReadBlobSidecars(ctx context.Context, slot uint64, blockRoot common.Hash) (out []*cltypes.BlobSidecar, found bool, err error)
 	WriteStream(w io.Writer, slot uint64, blockRoot common.Hash, idx uint64) error // Used for P2P networking
 	KzgCommitmentsCount(ctx context.Context, blockRoot common.Hash) (uint32, error)
+	// From Fulu onwards, blobs are split in columns (PeerDAS) of which only the custodied ones are stored.
+	WriteDataColumnSidecars(ctx context.Context, blockRoot common.Hash, sidecars []*cltypes.DataColumnSidecar) error
+	ReadDataColumnSidecar(ctx context.Context, slot uint64, blockRoot common.Hash, index uint64) (sidecar *cltypes.DataColumnSidecar, found bool, err error)
+	HasDataColumnSidecar(slot uint64, blockRoot common.Hash, index uint64) (bool, error)
+	WriteDataColumnStream(w io.Writer, slot uint64, blockRoot common.Hash, index uint64) error // Used for P2P networking
+	RemoveDataColumnSidecars(ctx context.Context, slot uint64, blockRoot common.Hash) error
 	Prune() error
 }
 
@@ -170,6 +176,7 @@ func (bs *BlobStore) Prune() error {
 	for i := startPrune; i < currentSlot; i += subdivisionSlot {
 		bs.fs.RemoveAll(strconv.FormatUint(i/subdivisionSlot, 10))
 	}
+	bs.pruneDataColumns(currentSlot)
 	return nil
 }
 
@@ -66,3 +66,87 @@ func TestBlobDB(t *testing.T) {
 	require.Equal(t, s1.SignedBlockHeader, sidecars[0].SignedBlockHeader)
 	require.Equal(t, s2.SignedBlockHeader, sidecars[1].SignedBlockHeader)
 }
+
+func TestDataColumnDB(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	newSidecar := func(index uint64) *cltypes.DataColumnSidecar {
+		s := cltypes.NewDataColumnSidecar()
+		s.Index = index
+		s.Column.Append(&cltypes.Cell{byte(index)})
+		s.KzgCommitments.Append(&cltypes.KZGCommitment{2})
+		s.KzgProofs.Append(&cltypes.KZGProof{3})
+		s.SignedBlockHeader.Header.Slot = 1
+		return s
+	}
+	s1, s2 := newSidecar(4), newSidecar(100)
+
+	bs := NewBlobStore(db, afero.NewMemMapFs(), 12, &clparams.MainnetBeaconConfig, nil)
+	blockRoot := common.Hash{1}
+	require.NoError(t, bs.WriteDataColumnSidecars(context.Background(), blockRoot, []*cltypes.DataColumnSidecar{s1, s2}))
+
+	for _, expected := range []*cltypes.DataColumnSidecar{s1, s2} {
+		has, err := bs.HasDataColumnSidecar(1, blockRoot, expected.Index)
+		require.NoError(t, err)
+		require.True(t, has)
+		sidecar, found, err := bs.ReadDataColumnSidecar(context.Background(), 1, blockRoot, expected.Index)
+		require.NoError(t, err)
+		require.True(t, found)
+		expectedRoot, err := expected.HashSSZ()
+		require.NoError(t, err)
+		root, err := sidecar.HashSSZ()
+		require.NoError(t, err)
+		require.Equal(t, expectedRoot, root)
+	}
+	_, found, err := bs.ReadDataColumnSidecar(context.Background(), 1, blockRoot, 5)
+	require.NoError(t, err)
+	require.False(t, found)
+
+	require.NoError(t, bs.RemoveDataColumnSidecars(context.Background(), 1, blockRoot))
+	has, err := bs.HasDataColumnSidecar(1, blockRoot, s1.Index)
+	require.NoError(t, err)
+	require.False(t, has)
+}
+
+func TestVerifyAgainstIdentifiersAndInsertDataColumns(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	bs := NewBlobStore(db, afero.NewMemMapFs(), 12, &clparams.MainnetBeaconConfig, nil)
+
+	sidecar := cltypes.NewDataColumnSidecar()
+	sidecar.Index = 4
+	sidecar.Column.Append(&cltypes.Cell{1})
+	sidecar.KzgCommitments.Append(&cltypes.KZGCommitment{2})
+	sidecar.KzgProofs.Append(&cltypes.KZGProof{3})
+	sidecar.SignedBlockHeader.Header.Slot = 1
+	blockRoot, err := sidecar.SignedBlockHeader.Header.HashSSZ()
+	require.NoError(t, err)
+
+	newIdentifiers := func(columns ...uint64) *solid.ListSSZ[*cltypes.DataColumnsByRootIdentifier] {
+		ids := solid.NewDynamicListSSZ[*cltypes.DataColumnsByRootIdentifier](8)
+		id := cltypes.NewDataColumnsByRootIdentifier()
+		id.BlockRoot = blockRoot
+		for _, column := range columns {
+			id.Columns.Append(column)
+		}
+		ids.Append(id)
+		return ids
+	}
+
+	// nothing received, nothing inserted
+	inserted, err := VerifyAgainstIdentifiersAndInsertDataColumns(context.Background(), bs, &clparams.MainnetBeaconConfig, newIdentifiers(4), nil, nil)
+	require.NoError(t, err)
+	require.Zero(t, inserted)
+
+	// a column which was not asked for
+	_, err = VerifyAgainstIdentifiersAndInsertDataColumns(context.Background(), bs, &clparams.MainnetBeaconConfig, newIdentifiers(5), []*cltypes.DataColumnSidecar{sidecar}, nil)
+	require.ErrorContains(t, err, "was not requested")
+
+	// a requested column which does not verify
+	_, err = VerifyAgainstIdentifiersAndInsertDataColumns(context.Background(), bs, &clparams.MainnetBeaconConfig, newIdentifiers(4), []*cltypes.DataColumnSidecar{sidecar}, nil)
+	require.ErrorContains(t, err, "inclusion proof")
+	has, err := bs.HasDataColumnSidecar(1, blockRoot, 4)
+	require.NoError(t, err)
+	require.False(t, has)
+}
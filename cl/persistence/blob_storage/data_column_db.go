@@ -0,0 +1,217 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package blob_storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+	"github.com/spf13/afero"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/crypto/kzg"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/sentinel/communication/ssz_snappy"
+)
+
+/*
+file system layout: columns/<slot/subdivisionSlot>/<blockRoot>_<column index>
+columns are not indexed in mdbx: a node only custodies some of them, so their presence is checked on disk.
+*/
+const dataColumnsDir = "columns"
+
+func dataColumnSidecarFilePath(slot, index uint64, blockRoot common.Hash) (folderpath, filepath string) {
+	folderpath = fmt.Sprintf("%s/%d", dataColumnsDir, slot/subdivisionSlot)
+	filepath = fmt.Sprintf("%s/%s_%d", folderpath, blockRoot.String(), index)
+	return
+}
+
+// WriteDataColumnSidecars writes the column sidecars of the block blockRoot on disk.
+func (bs *BlobStore) WriteDataColumnSidecars(ctx context.Context, blockRoot common.Hash, sidecars []*cltypes.DataColumnSidecar) error {
+	for _, sidecar := range sidecars {
+		if err := bs.writeDataColumnSidecar(blockRoot, sidecar); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (bs *BlobStore) writeDataColumnSidecar(blockRoot common.Hash, sidecar *cltypes.DataColumnSidecar) error {
+	folderPath, filePath := dataColumnSidecarFilePath(sidecar.SignedBlockHeader.Header.Slot, sidecar.Index, blockRoot)
+	if err := bs.fs.MkdirAll(folderPath, 0755); err != nil {
+		return err
+	}
+	file, err := bs.fs.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := ssz_snappy.EncodeAndWrite(file, sidecar); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// ReadDataColumnSidecar reads the sidecar of the column index of the block blockRoot, found is false if it is not stored.
+func (bs *BlobStore) ReadDataColumnSidecar(ctx context.Context, slot uint64, blockRoot common.Hash, index uint64) (*cltypes.DataColumnSidecar, bool, error) {
+	_, filePath := dataColumnSidecarFilePath(slot, index, blockRoot)
+	file, err := bs.fs.Open(filePath)
+	if err != nil {
+		if errors.Is(err, afero.ErrFileNotFound) || errors.Is(err, os.ErrNotExist) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	defer file.Close()
+	sidecar := cltypes.NewDataColumnSidecar()
+	if err := ssz_snappy.DecodeAndReadNoForkDigest(file, sidecar, clparams.ElectraVersion); err != nil {
+		return nil, false, err
+	}
+	return sidecar, true, nil
+}
+
+// HasDataColumnSidecar tells whether the sidecar of the column index of the block blockRoot is stored.
+func (bs *BlobStore) HasDataColumnSidecar(slot uint64, blockRoot common.Hash, index uint64) (bool, error) {
+	_, filePath := dataColumnSidecarFilePath(slot, index, blockRoot)
+	return afero.Exists(bs.fs, filePath)
+}
+
+// WriteDataColumnStream copies the stored sidecar of the column index of the block blockRoot to w, used for P2P networking.
+func (bs *BlobStore) WriteDataColumnStream(w io.Writer, slot uint64, blockRoot common.Hash, index uint64) error {
+	_, filePath := dataColumnSidecarFilePath(slot, index, blockRoot)
+	file, err := bs.fs.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(w, file)
+	return err
+}
+
+// RemoveDataColumnSidecars removes all the stored column sidecars of the block blockRoot.
+func (bs *BlobStore) RemoveDataColumnSidecars(ctx context.Context, slot uint64, blockRoot common.Hash) error {
+	for i := uint64(0); i < bs.beaconChainConfig.NumberOfColumns; i++ {
+		_, filePath := dataColumnSidecarFilePath(slot, i, blockRoot)
+		if err := bs.fs.Remove(filePath); err != nil && !errors.Is(err, afero.ErrFileNotFound) && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneDataColumns deletes the folders of columns older than the serving window, up to currentSlot.
+func (bs *BlobStore) pruneDataColumns(currentSlot uint64) {
+	var startPrune uint64
+	minSlotsForDataColumnSidecarRequest := bs.beaconChainConfig.MinSlotsForDataColumnSidecarsRequest()
+	if currentSlot >= minSlotsForDataColumnSidecarRequest {
+		startPrune = currentSlot - minSlotsForDataColumnSidecarRequest
+	}
+	for i := startPrune; i < currentSlot; i += subdivisionSlot {
+		bs.fs.RemoveAll(fmt.Sprintf("%s/%s", dataColumnsDir, strconv.FormatUint(i/subdivisionSlot, 10)))
+	}
+}
+
+// VerifyDataColumnSidecar does all the validation of a column sidecar which does not depend on the chain: the
+// consistency of its fields, its inclusion in the block of its header and the kzg proofs of its cells.
+func VerifyDataColumnSidecar(beaconChainConfig *clparams.BeaconChainConfig, sidecar *cltypes.DataColumnSidecar) error {
+	if sidecar.Index >= beaconChainConfig.NumberOfColumns {
+		return fmt.Errorf("column index %d out of range", sidecar.Index)
+	}
+	if sidecar.KzgCommitments.Len() == 0 {
+		return errors.New("column sidecar without commitments")
+	}
+	if sidecar.Column.Len() != sidecar.KzgCommitments.Len() || sidecar.KzgProofs.Len() != sidecar.KzgCommitments.Len() {
+		return fmt.Errorf("column sidecar has %d cells and %d proofs for %d commitments", sidecar.Column.Len(), sidecar.KzgProofs.Len(), sidecar.KzgCommitments.Len())
+	}
+	if !cltypes.VerifyDataColumnSidecarInclusionProof(sidecar) {
+		return errors.New("could not verify column's inclusion proof")
+	}
+	return VerifyDataColumnSidecarKZGProofs(sidecar)
+}
+
+// VerifyDataColumnSidecarKZGProofs verifies the cells of the column against the blob commitments.
+func VerifyDataColumnSidecarKZGProofs(sidecar *cltypes.DataColumnSidecar) error {
+	n := sidecar.Column.Len()
+	commitments := make([]gokzg4844.KZGCommitment, n)
+	cellIndices := make([]uint64, n)
+	cells := make([][]byte, n)
+	proofs := make([]gokzg4844.KZGProof, n)
+	for i := 0; i < n; i++ {
+		commitments[i] = gokzg4844.KZGCommitment(*sidecar.KzgCommitments.Get(i))
+		cellIndices[i] = sidecar.Index
+		cells[i] = sidecar.Column.Get(i)[:]
+		proofs[i] = gokzg4844.KZGProof(*sidecar.KzgProofs.Get(i))
+	}
+	if err := kzg.VerifyCells(commitments, cellIndices, cells, proofs); err != nil {
+		return fmt.Errorf("column KZG proof verification failed: %w", err)
+	}
+	return nil
+}
+
+// VerifyAgainstIdentifiersAndInsertDataColumns verifies column sidecars received for identifiers and stores them,
+// it returns the number of inserted sidecars. A sidecar which was not requested or fails verification is an error.
+func VerifyAgainstIdentifiersAndInsertDataColumns(ctx context.Context, storage BlobStorage, beaconChainConfig *clparams.BeaconChainConfig, identifiers *solid.ListSSZ[*cltypes.DataColumnsByRootIdentifier], sidecars []*cltypes.DataColumnSidecar, verifySignatureFn verifyHeaderSignatureFn) (uint64, error) {
+	requested := make(map[common.Hash]map[uint64]struct{}, identifiers.Len())
+	identifiers.Range(func(_ int, id *cltypes.DataColumnsByRootIdentifier, _ int) bool {
+		columns := make(map[uint64]struct{}, id.Columns.Length())
+		id.Columns.Range(func(_ int, column uint64, _ int) bool {
+			columns[column] = struct{}{}
+			return true
+		})
+		requested[id.BlockRoot] = columns
+		return true
+	})
+
+	byBlockRoot := make(map[common.Hash][]*cltypes.DataColumnSidecar)
+	for _, sidecar := range sidecars {
+		blockRoot, err := sidecar.SignedBlockHeader.Header.HashSSZ()
+		if err != nil {
+			return 0, err
+		}
+		if _, ok := requested[blockRoot][sidecar.Index]; !ok {
+			return 0, fmt.Errorf("column %d of block %x was not requested", sidecar.Index, blockRoot)
+		}
+		// a column is only answered once
+		delete(requested[blockRoot], sidecar.Index)
+		if err := VerifyDataColumnSidecar(beaconChainConfig, sidecar); err != nil {
+			return 0, err
+		}
+		if verifySignatureFn != nil {
+			// verify the signature of the sidecar head, we leave this step up to the caller to define
+			if err := verifySignatureFn(sidecar.SignedBlockHeader); err != nil {
+				return 0, err
+			}
+		}
+		byBlockRoot[blockRoot] = append(byBlockRoot[blockRoot], sidecar)
+	}
+
+	inserted := uint64(0)
+	for blockRoot, blockSidecars := range byBlockRoot {
+		if err := storage.WriteDataColumnSidecars(ctx, blockRoot, blockSidecars); err != nil {
+			return inserted, err
+		}
+		inserted += uint64(len(blockSidecars))
+	}
+	return inserted, nil
+}
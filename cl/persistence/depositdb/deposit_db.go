@@ -0,0 +1,85 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package depositdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/utils"
+	"github.com/spf13/afero"
+)
+
+const depositSnapshotFileName = "deposit_snapshot.ssz_snappy"
+
+/*
+* DepositDB only keeps track of one file
+* deposit_snapshot.ssz_snappy, the slot (8 bytes big endian) followed by the EIP-4881 snapshot.
+* It is rewritten every time the deposit tree is finalized further.
+ */
+type depositDB struct {
+	fs afero.Fs // Use afero to make it easier to test.
+}
+
+func NewDepositDB(depositDBPath string) DepositDB {
+	return NewDepositDBWithFs(afero.NewBasePathFs(afero.NewOsFs(), depositDBPath))
+}
+
+func NewDepositDBWithFs(fs afero.Fs) DepositDB {
+	return &depositDB{fs: fs}
+}
+
+func (d *depositDB) ReadSnapshot() (*cltypes.DepositSnapshot, uint64, error) {
+	enc, err := afero.ReadFile(d.fs, depositSnapshotFileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	decompressedEnc, err := utils.DecompressSnappy(enc, false)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(decompressedEnc) < 8 {
+		return nil, 0, fmt.Errorf("deposit snapshot is too short: %d bytes", len(decompressedEnc))
+	}
+	snapshot := cltypes.NewDepositSnapshot()
+	if err := snapshot.DecodeSSZ(decompressedEnc[8:], 0); err != nil {
+		return nil, 0, fmt.Errorf("could not deserialize deposit snapshot: %s", err)
+	}
+	return snapshot, binary.BigEndian.Uint64(decompressedEnc[:8]), nil
+}
+
+func (d *depositDB) WriteSnapshot(snapshot *cltypes.DepositSnapshot, slot uint64) error {
+	enc, err := snapshot.EncodeSSZ(binary.BigEndian.AppendUint64(nil, slot))
+	if err != nil {
+		return err
+	}
+	if err := d.fs.MkdirAll("/", 0755); err != nil {
+		return err
+	}
+	// write and rename, so that a crash never leaves a torn snapshot behind
+	tmpFileName := depositSnapshotFileName + ".tmp"
+	if err := afero.WriteFile(d.fs, tmpFileName, utils.CompressSnappy(enc), 0644); err != nil {
+		return err
+	}
+	return d.fs.Rename(tmpFileName, depositSnapshotFileName)
+}
@@ -0,0 +1,29 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package depositdb
+
+import "github.com/erigontech/erigon/cl/cltypes"
+
+type DepositDB interface {
+	// ReadSnapshot returns the last written deposit snapshot and the slot of the finalized block it was taken at,
+	// a nil snapshot if none was written yet.
+	ReadSnapshot() (*cltypes.DepositSnapshot, uint64, error)
+
+	// WriteSnapshot replaces the deposit snapshot, slot is the one of the finalized block whose state has exactly
+	// snapshot.DepositCount deposits processed.
+	WriteSnapshot(snapshot *cltypes.DepositSnapshot, slot uint64) error
+}
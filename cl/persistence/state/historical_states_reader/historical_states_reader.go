@@ -940,6 +940,39 @@ func (r *HistoricalStatesReader) ReadValidatorsBalances(tx kv.Tx, kvGetter state
 	return balancesList, balancesList.DecodeSSZ(balances, 0)
 }
 
+// ReadPendingDeposits reads the pending deposits queue of an Electra state, nil if the state is not found.
+func (r *HistoricalStatesReader) ReadPendingDeposits(kvGetter state_accessors.GetValFn, slot uint64) (*solid.ListSSZ[*solid.PendingDeposit], error) {
+	return readPendingQueue(r, kvGetter, slot, kv.PendingDepositsDump, kv.PendingDeposits, solid.NewPendingDepositList(r.cfg))
+}
+
+// ReadPendingPartialWithdrawals reads the pending partial withdrawals queue of an Electra state, nil if the state is not found.
+func (r *HistoricalStatesReader) ReadPendingPartialWithdrawals(kvGetter state_accessors.GetValFn, slot uint64) (*solid.ListSSZ[*solid.PendingPartialWithdrawal], error) {
+	return readPendingQueue(r, kvGetter, slot, kv.PendingPartialWithdrawalsDump, kv.PendingPartialWithdrawals, solid.NewPendingWithdrawalList(r.cfg))
+}
+
+// ReadPendingConsolidations reads the pending consolidations queue of an Electra state, nil if the state is not found.
+func (r *HistoricalStatesReader) ReadPendingConsolidations(kvGetter state_accessors.GetValFn, slot uint64) (*solid.ListSSZ[*solid.PendingConsolidation], error) {
+	return readPendingQueue(r, kvGetter, slot, kv.PendingConsolidationsDump, kv.PendingConsolidations, solid.NewPendingConsolidationList(r.cfg))
+}
+
+func readPendingQueue[T solid.EncodableHashableSSZ](r *HistoricalStatesReader, kvGetter state_accessors.GetValFn, slot uint64, dumpTable, diffsTable string, out *solid.ListSSZ[T]) (*solid.ListSSZ[T], error) {
+	sd, err := state_accessors.ReadSlotData(kvGetter, slot, r.cfg)
+	if err != nil {
+		return nil, err
+	}
+	// State not found
+	if sd == nil {
+		return nil, nil
+	}
+	if r.cfg.GetCurrentStateVersion(slot/r.cfg.SlotsPerEpoch) < clparams.ElectraVersion {
+		return nil, fmt.Errorf("state at slot %d predates electra", slot)
+	}
+	if err := readQueueSSZ(kvGetter, slot, dumpTable, diffsTable, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (r *HistoricalStatesReader) ReadRandaoMixBySlotAndIndex(tx kv.Tx, kvGetter state_accessors.GetValFn, slot, index uint64) (common.Hash, error) {
 	epoch := slot / r.cfg.SlotsPerEpoch
 	epochSubIndex := epoch % r.cfg.EpochsPerHistoricalVector
@@ -0,0 +1,348 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package historical_states_reader
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/phase1/core/state"
+)
+
+var ErrStateNotAvailable = errors.New("state is not available in the archive")
+
+// stateFieldNames are the names of the BeaconState fields in the ssz schema, phase0 has the pending attestations
+// in place of the participations.
+var stateFieldNames = []string{
+	"genesis_time", "genesis_validators_root", "slot", "fork", "latest_block_header", "block_roots", "state_roots",
+	"historical_roots", "eth1_data", "eth1_data_votes", "eth1_deposit_index", "validators", "balances",
+	"randao_mixes", "slashings", "previous_epoch_participation", "current_epoch_participation",
+	"justification_bits", "previous_justified_checkpoint", "current_justified_checkpoint", "finalized_checkpoint",
+	// Altair
+	"inactivity_scores", "current_sync_committee", "next_sync_committee",
+	// Bellatrix
+	"latest_execution_payload_header",
+	// Capella
+	"next_withdrawal_index", "next_withdrawal_validator_index", "historical_summaries",
+	// Electra
+	"deposit_requests_start_index", "deposit_balance_to_consume", "exit_balance_to_consume", "earliest_exit_epoch",
+	"consolidation_balance_to_consume", "earliest_consolidation_epoch", "pending_deposits",
+	"pending_partial_withdrawals", "pending_consolidations",
+}
+
+// StateDiff is the difference between two beacon states: the top level fields whose root changed, and the
+// element-wise changes of the large lists, so that epoch transitions can be analysed without the full states.
+type StateDiff struct {
+	FromSlot    uint64 `json:"from_slot,string"`
+	ToSlot      uint64 `json:"to_slot,string"`
+	FromVersion string `json:"from_version"`
+	ToVersion   string `json:"to_version"`
+
+	Fields                     []FieldDiff         `json:"fields"`
+	Validators                 []ValueDiff         `json:"validators"`
+	Balances                   []Uint64Diff        `json:"balances"`
+	InactivityScores           []Uint64Diff        `json:"inactivity_scores"`
+	PreviousEpochParticipation []ParticipationDiff `json:"previous_epoch_participation"`
+	CurrentEpochParticipation  []ParticipationDiff `json:"current_epoch_participation"`
+
+	// Electra queues
+	PendingDeposits           *QueueDiff[*solid.PendingDeposit]           `json:"pending_deposits,omitempty"`
+	PendingPartialWithdrawals *QueueDiff[*solid.PendingPartialWithdrawal] `json:"pending_partial_withdrawals,omitempty"`
+	PendingConsolidations     *QueueDiff[*solid.PendingConsolidation]     `json:"pending_consolidations,omitempty"`
+}
+
+// FieldDiff is a top level field of the state whose hash tree root changed, the root is zero on the side where
+// the field does not exist.
+type FieldDiff struct {
+	Path             string      `json:"path"`
+	GeneralizedIndex uint64      `json:"generalized_index,string"`
+	FromRoot         common.Hash `json:"from_root"`
+	ToRoot           common.Hash `json:"to_root"`
+}
+
+// ValueDiff is a changed leaf of the state identified by its ssz path, e.g. validators[12].exit_epoch.
+type ValueDiff struct {
+	Path string `json:"path"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type Uint64Diff struct {
+	Index uint64 `json:"index,string"`
+	From  uint64 `json:"from,string"`
+	To    uint64 `json:"to,string"`
+}
+
+type ParticipationDiff struct {
+	Index uint64 `json:"index,string"`
+	From  byte   `json:"from"`
+	To    byte   `json:"to"`
+}
+
+// QueueDiff patches a FIFO queue: the target queue is the source one without its first Processed elements,
+// followed by Appended.
+type QueueDiff[T solid.EncodableHashableSSZ] struct {
+	FromLength int `json:"from_length"`
+	ToLength   int `json:"to_length"`
+	Processed  int `json:"processed"`
+	Appended   []T `json:"appended"`
+}
+
+// ReadStateDiff reconstructs the states at fromSlot and toSlot and returns their difference.
+func (r *HistoricalStatesReader) ReadStateDiff(ctx context.Context, tx kv.Tx, fromSlot, toSlot uint64) (*StateDiff, error) {
+	from, err := r.ReadHistoricalState(ctx, tx, fromSlot)
+	if err != nil {
+		return nil, err
+	}
+	if from == nil {
+		return nil, fmt.Errorf("%w: slot %d", ErrStateNotAvailable, fromSlot)
+	}
+	to, err := r.ReadHistoricalState(ctx, tx, toSlot)
+	if err != nil {
+		return nil, err
+	}
+	if to == nil {
+		return nil, fmt.Errorf("%w: slot %d", ErrStateNotAvailable, toSlot)
+	}
+	return ComputeStateDiff(from, to)
+}
+
+// ComputeStateDiff returns the difference between the states from and to, which may belong to different forks.
+func ComputeStateDiff(from, to *state.CachingBeaconState) (*StateDiff, error) {
+	diff := &StateDiff{
+		FromSlot:    from.Slot(),
+		ToSlot:      to.Slot(),
+		FromVersion: from.Version().String(),
+		ToVersion:   to.Version().String(),
+	}
+	var err error
+	if diff.Fields, err = diffFields(from, to); err != nil {
+		return nil, err
+	}
+	diff.Validators = diffValidators(from.ValidatorSet(), to.ValidatorSet())
+	diff.Balances = diffUint64Lists(from.Balances(), to.Balances())
+	if from.Version() >= clparams.AltairVersion && to.Version() >= clparams.AltairVersion {
+		diff.InactivityScores = diffUint64Lists(from.InactivityScores(), to.InactivityScores())
+		diff.PreviousEpochParticipation = diffParticipations(from.PreviousEpochParticipation(), to.PreviousEpochParticipation())
+		diff.CurrentEpochParticipation = diffParticipations(from.CurrentEpochParticipation(), to.CurrentEpochParticipation())
+	}
+	if from.Version() >= clparams.ElectraVersion && to.Version() >= clparams.ElectraVersion {
+		if diff.PendingDeposits, err = diffQueues(from.PendingDeposits(), to.PendingDeposits()); err != nil {
+			return nil, err
+		}
+		if diff.PendingPartialWithdrawals, err = diffQueues(from.PendingPartialWithdrawals(), to.PendingPartialWithdrawals()); err != nil {
+			return nil, err
+		}
+		if diff.PendingConsolidations, err = diffQueues(from.PendingConsolidations(), to.PendingConsolidations()); err != nil {
+			return nil, err
+		}
+	}
+	return diff, nil
+}
+
+func diffFields(from, to *state.CachingBeaconState) ([]FieldDiff, error) {
+	fromRoots, err := from.FieldRoots()
+	if err != nil {
+		return nil, err
+	}
+	toRoots, err := to.FieldRoots()
+	if err != nil {
+		return nil, err
+	}
+	// generalized indices are the ones of the target state, Electra doubled the width of the tree
+	depth := 5
+	if to.Version() >= clparams.ElectraVersion {
+		depth = 6
+	}
+	diffs := []FieldDiff{}
+	for i := 0; i < max(len(fromRoots), len(toRoots)); i++ {
+		var fromRoot, toRoot common.Hash
+		if i < len(fromRoots) {
+			fromRoot = fromRoots[i]
+		}
+		if i < len(toRoots) {
+			toRoot = toRoots[i]
+		}
+		if fromRoot == toRoot {
+			continue
+		}
+		diffs = append(diffs, FieldDiff{
+			Path:             stateFieldName(i, to.Version()),
+			GeneralizedIndex: uint64(1)<<depth + uint64(i),
+			FromRoot:         fromRoot,
+			ToRoot:           toRoot,
+		})
+	}
+	return diffs, nil
+}
+
+func stateFieldName(index int, version clparams.StateVersion) string {
+	if version == clparams.Phase0Version {
+		switch index {
+		case 15:
+			return "previous_epoch_attestations"
+		case 16:
+			return "current_epoch_attestations"
+		}
+	}
+	return stateFieldNames[index]
+}
+
+// diffValidators lists the changed fields of every validator, all the fields of the new validators are reported.
+func diffValidators(from, to *solid.ValidatorSet) []ValueDiff {
+	diffs := []ValueDiff{}
+	for i := 0; i < to.Length(); i++ {
+		current := to.Get(i)
+		var previous solid.Validator
+		if i < from.Length() {
+			previous = from.Get(i)
+			if bytes.Equal(previous, current) {
+				continue
+			}
+		}
+		diffs = append(diffs, diffValidator(i, previous, current)...)
+	}
+	return diffs
+}
+
+func diffValidator(index int, from, to solid.Validator) []ValueDiff {
+	fields := []struct {
+		name  string
+		value func(v solid.Validator) string
+	}{
+		{"pubkey", func(v solid.Validator) string { return common.Bytes48(v.PublicKey()).Hex() }},
+		{"withdrawal_credentials", func(v solid.Validator) string { return v.WithdrawalCredentials().Hex() }},
+		{"effective_balance", func(v solid.Validator) string { return strconv.FormatUint(v.EffectiveBalance(), 10) }},
+		{"slashed", func(v solid.Validator) string { return strconv.FormatBool(v.Slashed()) }},
+		{"activation_eligibility_epoch", func(v solid.Validator) string { return strconv.FormatUint(v.ActivationEligibilityEpoch(), 10) }},
+		{"activation_epoch", func(v solid.Validator) string { return strconv.FormatUint(v.ActivationEpoch(), 10) }},
+		{"exit_epoch", func(v solid.Validator) string { return strconv.FormatUint(v.ExitEpoch(), 10) }},
+		{"withdrawable_epoch", func(v solid.Validator) string { return strconv.FormatUint(v.WithdrawableEpoch(), 10) }},
+	}
+	var diffs []ValueDiff
+	for _, field := range fields {
+		var previous string
+		if from != nil {
+			previous = field.value(from)
+		}
+		if current := field.value(to); previous != current {
+			diffs = append(diffs, ValueDiff{
+				Path: fmt.Sprintf("validators[%d].%s", index, field.name),
+				From: previous,
+				To:   current,
+			})
+		}
+	}
+	return diffs
+}
+
+// diffUint64Lists compares two lists element-wise, a missing element counts as zero.
+func diffUint64Lists(from, to solid.Uint64ListSSZ) []Uint64Diff {
+	diffs := []Uint64Diff{}
+	for i := 0; i < max(from.Length(), to.Length()); i++ {
+		var previous, current uint64
+		if i < from.Length() {
+			previous = from.Get(i)
+		}
+		if i < to.Length() {
+			current = to.Get(i)
+		}
+		if previous != current {
+			diffs = append(diffs, Uint64Diff{Index: uint64(i), From: previous, To: current})
+		}
+	}
+	return diffs
+}
+
+func diffParticipations(from, to *solid.ParticipationBitList) []ParticipationDiff {
+	diffs := []ParticipationDiff{}
+	for i := 0; i < max(from.Length(), to.Length()); i++ {
+		var previous, current byte
+		if i < from.Length() {
+			previous = from.Get(i)
+		}
+		if i < to.Length() {
+			current = to.Get(i)
+		}
+		if previous != current {
+			diffs = append(diffs, ParticipationDiff{Index: uint64(i), From: previous, To: current})
+		}
+	}
+	return diffs
+}
+
+// diffQueues finds the smallest number of elements processed from the head of from such that the rest of it is
+// a prefix of to, the remaining elements of to were appended.
+func diffQueues[T solid.EncodableHashableSSZ](from, to *solid.ListSSZ[T]) (*QueueDiff[T], error) {
+	fromRoots, err := elementRoots(from)
+	if err != nil {
+		return nil, err
+	}
+	toRoots, err := elementRoots(to)
+	if err != nil {
+		return nil, err
+	}
+	processed := len(fromRoots)
+	for k := range fromRoots {
+		remaining := fromRoots[k:]
+		if len(remaining) > len(toRoots) || remaining[0] != toRoots[0] {
+			continue
+		}
+		if isPrefix(remaining, toRoots) {
+			processed = k
+			break
+		}
+	}
+	diff := &QueueDiff[T]{
+		FromLength: from.Len(),
+		ToLength:   to.Len(),
+		Processed:  processed,
+		Appended:   []T{},
+	}
+	for i := len(fromRoots) - processed; i < to.Len(); i++ {
+		diff.Appended = append(diff.Appended, to.Get(i))
+	}
+	return diff, nil
+}
+
+func elementRoots[T solid.EncodableHashableSSZ](list *solid.ListSSZ[T]) ([]common.Hash, error) {
+	roots := make([]common.Hash, list.Len())
+	for i := range roots {
+		root, err := list.Get(i).HashSSZ()
+		if err != nil {
+			return nil, err
+		}
+		roots[i] = root
+	}
+	return roots, nil
+}
+
+func isPrefix(prefix, list []common.Hash) bool {
+	for i := range prefix {
+		if prefix[i] != list[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package historical_states_reader_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon/cl/antiquary/tests"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/persistence/state/historical_states_reader"
+)
+
+func TestComputeStateDiff(t *testing.T) {
+	_, _, from := tests.GetElectraRandom()
+	require.Equal(t, clparams.ElectraVersion, from.Version())
+
+	deposits := []*solid.PendingDeposit{{Amount: 1, Slot: 1}, {Amount: 2, Slot: 2}, {Amount: 3, Slot: 3}}
+	fromDeposits := solid.NewPendingDepositList(from.BeaconConfig())
+	fromDeposits.Append(deposits[0])
+	fromDeposits.Append(deposits[1])
+	from.SetPendingDeposits(fromDeposits)
+
+	to, err := from.Copy()
+	require.NoError(t, err)
+
+	diff, err := historical_states_reader.ComputeStateDiff(from, to)
+	require.NoError(t, err)
+	require.Empty(t, diff.Fields)
+	require.Empty(t, diff.Validators)
+	require.Empty(t, diff.Balances)
+	require.Zero(t, diff.PendingDeposits.Processed)
+	require.Empty(t, diff.PendingDeposits.Appended)
+
+	balance, err := from.ValidatorBalance(1)
+	require.NoError(t, err)
+	require.NoError(t, to.SetValidatorBalance(1, balance+5))
+	exitEpoch, err := from.ValidatorExitEpoch(2)
+	require.NoError(t, err)
+	to.SetExitEpochForValidatorAtIndex(2, 10)
+	participation := from.EpochParticipationForValidatorIndex(true, 3)
+	to.SetEpochParticipationForValidatorIndex(true, 3, participation^1)
+	toDeposits := solid.NewPendingDepositList(from.BeaconConfig())
+	toDeposits.Append(deposits[1])
+	toDeposits.Append(deposits[2])
+	to.SetPendingDeposits(toDeposits)
+
+	diff, err = historical_states_reader.ComputeStateDiff(from, to)
+	require.NoError(t, err)
+
+	paths := make([]string, len(diff.Fields))
+	for i, field := range diff.Fields {
+		paths[i] = field.Path
+		require.NotEqual(t, field.FromRoot, field.ToRoot)
+	}
+	require.Equal(t, []string{"validators", "balances", "current_epoch_participation", "pending_deposits"}, paths)
+	// electra states have 64 leaves, validators is the 12th field
+	require.Equal(t, uint64(64+11), diff.Fields[0].GeneralizedIndex)
+
+	require.Equal(t, []historical_states_reader.ValueDiff{{
+		Path: "validators[2].exit_epoch",
+		From: strconv.FormatUint(exitEpoch, 10),
+		To:   "10",
+	}}, diff.Validators)
+	require.Equal(t, []historical_states_reader.Uint64Diff{{Index: 1, From: balance, To: balance + 5}}, diff.Balances)
+	require.Equal(t, []historical_states_reader.ParticipationDiff{{Index: 3, From: byte(participation), To: byte(participation ^ 1)}}, diff.CurrentEpochParticipation)
+	require.Empty(t, diff.PreviousEpochParticipation)
+
+	require.Equal(t, 2, diff.PendingDeposits.FromLength)
+	require.Equal(t, 2, diff.PendingDeposits.ToLength)
+	require.Equal(t, 1, diff.PendingDeposits.Processed)
+	require.Equal(t, []*solid.PendingDeposit{deposits[2]}, diff.PendingDeposits.Appended)
+	require.Zero(t, diff.PendingConsolidations.Processed)
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/spf13/afero"
@@ -12,9 +14,15 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/erigontech/erigon-lib/chain/networkid"
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/datadir"
 	"github.com/erigontech/erigon/cl/antiquary/tests"
 	"github.com/erigontech/erigon/cl/clparams"
 	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/persistence/depositdb"
+	"github.com/erigontech/erigon/cl/persistence/genesisdb"
+	"github.com/erigontech/erigon/cl/phase1/core/deposit_tree"
+	"github.com/erigontech/erigon/cl/phase1/core/state"
 	"github.com/erigontech/erigon/cl/utils"
 )
 
@@ -89,3 +97,150 @@ func TestLocalCheckpointSyncFromGenesis(t *testing.T) {
 
 	assert.Equal(t, wantRoot, haveRoot)
 }
+
+func TestFileCheckpointSync(t *testing.T) {
+	blocks, _, st := tests.GetPhase0Random()
+	dir := t.TempDir()
+	enc, err := st.EncodeSSZ(nil)
+	require.NoError(t, err)
+	statePath := filepath.Join(dir, "state.ssz_snappy")
+	require.NoError(t, os.WriteFile(statePath, utils.CompressSnappy(enc), 0644))
+
+	checkpoint := blocks[len(blocks)-1]
+	enc, err = checkpoint.EncodeSSZ(nil)
+	require.NoError(t, err)
+	blockPath := filepath.Join(dir, "block.ssz")
+	require.NoError(t, os.WriteFile(blockPath, enc, 0644))
+	blockRoot, err := checkpoint.Block.HashSSZ()
+	require.NoError(t, err)
+
+	syncer := NewFileCheckpointSyncer(&clparams.MainnetBeaconConfig, statePath, blockPath, blockRoot)
+	bs, err := syncer.GetLatestBeaconState(context.Background())
+	require.NoError(t, err)
+	haveRoot, err := st.HashSSZ()
+	require.NoError(t, err)
+	wantRoot, err := bs.HashSSZ()
+	require.NoError(t, err)
+	assert.Equal(t, wantRoot, haveRoot)
+
+	// the block is not the trusted one
+	syncer = NewFileCheckpointSyncer(&clparams.MainnetBeaconConfig, statePath, blockPath, common.HexToHash("0x01"))
+	_, err = syncer.GetLatestBeaconState(context.Background())
+	require.ErrorIs(t, err, ErrCheckpointRootMismatch)
+
+	// a tampered state carrying the header of the trusted block
+	writeState := func(s *state.CachingBeaconState) string {
+		enc, err := s.EncodeSSZ(nil)
+		require.NoError(t, err)
+		path := filepath.Join(t.TempDir(), "tampered.ssz")
+		require.NoError(t, os.WriteFile(path, enc, 0644))
+		return path
+	}
+	tampered, err := st.Copy()
+	require.NoError(t, err)
+	require.NoError(t, tampered.SetValidatorBalance(0, 1))
+	syncer = NewFileCheckpointSyncer(&clparams.MainnetBeaconConfig, writeState(tampered), blockPath, blockRoot)
+	_, err = syncer.GetLatestBeaconState(context.Background())
+	require.ErrorIs(t, err, ErrCheckpointRootMismatch)
+
+	// the same, after an empty slot filled the state root of the header
+	header := tampered.LatestBlockHeader()
+	header.Root = checkpoint.Block.StateRoot
+	tampered.SetLatestBlockHeader(&header)
+	tampered.SetSlot(st.Slot() + 1)
+	headerRoot, err := header.HashSSZ()
+	require.NoError(t, err)
+	require.Equal(t, blockRoot, headerRoot)
+	syncer = NewFileCheckpointSyncer(&clparams.MainnetBeaconConfig, writeState(tampered), blockPath, blockRoot)
+	_, err = syncer.GetLatestBeaconState(context.Background())
+	require.ErrorIs(t, err, ErrCheckpointRootMismatch)
+
+	// the state is not the one of the trusted block
+	enc, err = blocks[0].EncodeSSZ(nil)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(blockPath, enc, 0644))
+	blockRoot, err = blocks[0].Block.HashSSZ()
+	require.NoError(t, err)
+	syncer = NewFileCheckpointSyncer(&clparams.MainnetBeaconConfig, statePath, blockPath, blockRoot)
+	_, err = syncer.GetLatestBeaconState(context.Background())
+	require.ErrorIs(t, err, ErrCheckpointRootMismatch)
+}
+
+func TestReadOrFetchLatestBeaconStatePrefersLocalState(t *testing.T) {
+	blocks, genesisState, st := tests.GetPhase0Random()
+	dirs := datadir.New(t.TempDir())
+	genesisDB := genesisdb.NewGenesisDB(&clparams.MainnetBeaconConfig, dirs.CaplinGenesis)
+	require.NoError(t, genesisDB.Initialize(genesisState))
+
+	enc, err := st.EncodeSSZ(nil)
+	require.NoError(t, err)
+	statePath := filepath.Join(t.TempDir(), "state.ssz_snappy")
+	require.NoError(t, os.WriteFile(statePath, utils.CompressSnappy(enc), 0644))
+	checkpoint := blocks[len(blocks)-1]
+	enc, err = checkpoint.EncodeSSZ(nil)
+	require.NoError(t, err)
+	blockPath := filepath.Join(t.TempDir(), "block.ssz")
+	require.NoError(t, os.WriteFile(blockPath, enc, 0644))
+	blockRoot, err := checkpoint.Block.HashSSZ()
+	require.NoError(t, err)
+
+	caplinConfig := clparams.CaplinConfig{
+		CheckpointSyncStateFile: statePath,
+		CheckpointSyncBlockFile: blockPath,
+		CheckpointSyncBlockRoot: blockRoot,
+	}
+	depositDB := depositdb.NewDepositDB(t.TempDir())
+
+	// without a local state the node starts from the checkpoint files
+	bs, err := ReadOrFetchLatestBeaconState(context.Background(), dirs, &clparams.MainnetBeaconConfig, caplinConfig, genesisDB, depositDB)
+	require.NoError(t, err)
+	require.Equal(t, st.Slot(), bs.Slot())
+
+	// once the node has progressed its local state wins over the checkpoint files
+	local, err := st.Copy()
+	require.NoError(t, err)
+	local.SetSlot(st.Slot() + 1)
+	enc, err = local.EncodeSSZ(nil)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dirs.CaplinLatest, clparams.LatestStateFileName), utils.CompressSnappy(enc), 0644))
+
+	bs, err = ReadOrFetchLatestBeaconState(context.Background(), dirs, &clparams.MainnetBeaconConfig, caplinConfig, genesisDB, depositDB)
+	require.NoError(t, err)
+	require.Equal(t, local.Slot(), bs.Slot())
+}
+
+func TestReadOrFetchLatestBeaconStateBootstrapsDepositTree(t *testing.T) {
+	_, genesisState, _ := tests.GetPhase0Random()
+	dirs := datadir.New(t.TempDir())
+	caplinConfig := clparams.CaplinConfig{DisabledCheckpointSync: true}
+
+	// the deposits of the genesis state are not known, the tree can't be bootstrapped
+	genesisDB := genesisdb.NewGenesisDB(&clparams.MainnetBeaconConfig, dirs.CaplinGenesis)
+	require.NoError(t, genesisDB.Initialize(genesisState))
+	depositDB := depositdb.NewDepositDB(t.TempDir())
+	_, err := ReadOrFetchLatestBeaconState(context.Background(), dirs, &clparams.MainnetBeaconConfig, caplinConfig, genesisDB, depositDB)
+	require.NoError(t, err)
+	snapshot, _, err := depositDB.ReadSnapshot()
+	require.NoError(t, err)
+	require.Nil(t, snapshot)
+
+	// without genesis deposits the tree starts empty at genesis
+	noDeposits, err := genesisState.Copy()
+	require.NoError(t, err)
+	noDeposits.SetEth1DepositIndex(0)
+	noDeposits.SetEth1Data(&cltypes.Eth1Data{BlockHash: common.Hash{1}})
+	dirs = datadir.New(t.TempDir())
+	genesisDB = genesisdb.NewGenesisDB(&clparams.MainnetBeaconConfig, dirs.CaplinGenesis)
+	require.NoError(t, genesisDB.Initialize(noDeposits))
+	_, err = ReadOrFetchLatestBeaconState(context.Background(), dirs, &clparams.MainnetBeaconConfig, caplinConfig, genesisDB, depositDB)
+	require.NoError(t, err)
+	snapshot, slot, err := depositDB.ReadSnapshot()
+	require.NoError(t, err)
+	require.NotNil(t, snapshot)
+	require.Equal(t, noDeposits.Slot(), slot)
+	require.Zero(t, snapshot.DepositCount)
+	require.Equal(t, common.Hash{1}, snapshot.ExecutionBlockHash)
+	tree, err := deposit_tree.FromSnapshot(snapshot)
+	require.NoError(t, err)
+	require.Equal(t, deposit_tree.New().Root(), tree.Root())
+}
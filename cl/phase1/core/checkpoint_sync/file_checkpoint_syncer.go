@@ -0,0 +1,114 @@
+package checkpoint_sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/phase1/core/state"
+	"github.com/erigontech/erigon/cl/utils"
+)
+
+var ErrCheckpointRootMismatch = errors.New("checkpoint does not match the trusted block root")
+
+// FileCheckpointSyncer is a CheckpointSyncer that reads the checkpoint state and its block from local files, for
+// setups which cannot reach a checkpoint sync endpoint. Both are verified against a trusted block root.
+type FileCheckpointSyncer struct {
+	beaconConfig *clparams.BeaconChainConfig
+	statePath    string
+	blockPath    string
+	trustedRoot  common.Hash
+}
+
+// NewFileCheckpointSyncer creates a syncer for the state and block files, which may be plain ssz (.ssz) or snappy
+// compressed (.ssz_snappy).
+func NewFileCheckpointSyncer(beaconConfig *clparams.BeaconChainConfig, statePath, blockPath string, trustedRoot common.Hash) CheckpointSyncer {
+	return &FileCheckpointSyncer{
+		beaconConfig: beaconConfig,
+		statePath:    statePath,
+		blockPath:    blockPath,
+		trustedRoot:  trustedRoot,
+	}
+}
+
+func (f *FileCheckpointSyncer) GetLatestBeaconState(ctx context.Context) (*state.CachingBeaconState, error) {
+	if f.trustedRoot == (common.Hash{}) || f.blockPath == "" {
+		return nil, errors.New("checkpoint sync from file requires the checkpoint block and its trusted root")
+	}
+	block, err := ReadBeaconBlockFile(f.beaconConfig, f.blockPath)
+	if err != nil {
+		return nil, err
+	}
+	blockRoot, err := block.Block.HashSSZ()
+	if err != nil {
+		return nil, err
+	}
+	if blockRoot != f.trustedRoot {
+		return nil, fmt.Errorf("%w: block root %x, trusted %x", ErrCheckpointRootMismatch, blockRoot, f.trustedRoot)
+	}
+
+	encoded, err := readSSZFile(f.statePath)
+	if err != nil {
+		return nil, err
+	}
+	slot, err := utils.ExtractSlotFromSerializedBeaconState(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("could not deserialize state slot: %s", err)
+	}
+	bs := state.New(f.beaconConfig)
+	if err := bs.DecodeSSZ(encoded, int(f.beaconConfig.GetCurrentStateVersion(slot/f.beaconConfig.SlotsPerEpoch))); err != nil {
+		return nil, fmt.Errorf("could not deserialize state: %s", err)
+	}
+
+	// the state must be the post-state of the trusted block, a state which went through empty slots since can't be
+	// checked against it.
+	if bs.Slot() != block.Block.Slot {
+		return nil, fmt.Errorf("%w: state slot %d, block slot %d", ErrCheckpointRootMismatch, bs.Slot(), block.Block.Slot)
+	}
+	stateRoot, err := bs.HashSSZ()
+	if err != nil {
+		return nil, err
+	}
+	if stateRoot != block.Block.StateRoot {
+		return nil, fmt.Errorf("%w: state root %x, block state root %x", ErrCheckpointRootMismatch, stateRoot, block.Block.StateRoot)
+	}
+	log.Info("[Checkpoint Sync] Loaded checkpoint from file", "slot", bs.Slot(), "blockRoot", f.trustedRoot)
+	return bs, nil
+}
+
+// ReadBeaconBlockFile reads a signed beacon block from a .ssz or .ssz_snappy file.
+func ReadBeaconBlockFile(beaconConfig *clparams.BeaconChainConfig, path string) (*cltypes.SignedBeaconBlock, error) {
+	encoded, err := readSSZFile(path)
+	if err != nil {
+		return nil, err
+	}
+	slot, err := utils.ExtractSlotFromSerializedBeaconBlock(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("could not deserialize block slot: %s", err)
+	}
+	version := beaconConfig.GetCurrentStateVersion(slot / beaconConfig.SlotsPerEpoch)
+	block := cltypes.NewSignedBeaconBlock(beaconConfig, version)
+	if err := block.DecodeSSZ(encoded, int(version)); err != nil {
+		return nil, fmt.Errorf("could not deserialize block: %s", err)
+	}
+	return block, nil
+}
+
+func readSSZFile(path string) ([]byte, error) {
+	encoded, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasSuffix(path, ".ssz_snappy") {
+		if encoded, err = utils.DecompressSnappy(encoded, false); err != nil {
+			return nil, fmt.Errorf("%s is corrupt: %s", path, err)
+		}
+	}
+	return encoded, nil
+}
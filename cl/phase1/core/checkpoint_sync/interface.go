@@ -3,9 +3,16 @@ package checkpoint_sync
 import (
 	"context"
 
+	"github.com/erigontech/erigon/cl/cltypes"
 	"github.com/erigontech/erigon/cl/phase1/core/state"
 )
 
 type CheckpointSyncer interface {
 	GetLatestBeaconState(ctx context.Context) (*state.CachingBeaconState, error)
 }
+
+// DepositSnapshotSyncer is implemented by the checkpoint syncers which can also provide the EIP-4881 deposit snapshot
+// matching the state they returned.
+type DepositSnapshotSyncer interface {
+	GetDepositSnapshot(ctx context.Context) (*cltypes.DepositSnapshot, error)
+}
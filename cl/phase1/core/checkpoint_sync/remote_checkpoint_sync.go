@@ -2,21 +2,31 @@ package checkpoint_sync
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/erigontech/erigon-lib/log/v3"
 	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
 	"github.com/erigontech/erigon/cl/phase1/core/state"
 	"github.com/erigontech/erigon/cl/utils"
 )
 
+// depositSnapshotTimeout bounds the deposit snapshot request, the node starts without serving the snapshot past it.
+const depositSnapshotTimeout = 30 * time.Second
+
 // RemoteCheckpointSync is a CheckpointSyncer that fetches the checkpoint state from a remote endpoint.
 type RemoteCheckpointSync struct {
 	beaconConfig *clparams.BeaconChainConfig
 	net          clparams.NetworkType
+
+	uri string // the uri the state was fetched from
 }
 
 func NewRemoteCheckpointSync(beaconConfig *clparams.BeaconChainConfig, net clparams.NetworkType) CheckpointSyncer {
@@ -78,10 +88,49 @@ func (r *RemoteCheckpointSync) GetLatestBeaconState(ctx context.Context) (*state
 	for _, uri := range uris {
 		beaconState, err = fetchBeaconState(uri)
 		if err == nil {
+			r.uri = uri
 			return beaconState, nil
 		}
 		log.Warn("[Checkpoint Sync] Failed to fetch beacon state", "uri", uri, "err", err)
 	}
 	return nil, err
+}
 
+// GetDepositSnapshot fetches the EIP-4881 deposit snapshot from the beacon node which served the state.
+func (r *RemoteCheckpointSync) GetDepositSnapshot(ctx context.Context) (*cltypes.DepositSnapshot, error) {
+	if r.uri == "" {
+		return nil, errors.New("no beacon state was fetched")
+	}
+	u, err := url.Parse(r.uri)
+	if err != nil {
+		return nil, err
+	}
+	// the state is served by the debug namespace of the beacon API, the snapshot by the beacon one
+	if idx := strings.Index(u.Path, "/eth/"); idx >= 0 {
+		u.Path = u.Path[:idx]
+	}
+	u.Path += "/eth/v1/beacon/deposit_snapshot"
+	u.RawQuery = ""
+
+	log.Info("[Checkpoint Sync] Requesting deposit snapshot", "uri", u.String())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := (&http.Client{Timeout: depositSnapshotTimeout}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("deposit snapshot request failed, bad status code %d", resp.StatusCode)
+	}
+	snapshot := struct {
+		Data *cltypes.DepositSnapshot `json:"data"`
+	}{Data: cltypes.NewDepositSnapshot()}
+	if err := json.NewDecoder(resp.Body).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("deposit snapshot decode failed %s", err)
+	}
+	return snapshot.Data, nil
 }
@@ -5,27 +5,98 @@ import (
 	"fmt"
 
 	"github.com/erigontech/erigon-lib/common/datadir"
+	"github.com/erigontech/erigon-lib/log/v3"
 	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/persistence/depositdb"
 	"github.com/erigontech/erigon/cl/persistence/genesisdb"
+	"github.com/erigontech/erigon/cl/phase1/core/deposit_tree"
 	"github.com/erigontech/erigon/cl/phase1/core/state"
 	"github.com/spf13/afero"
 )
 
-// ReadOrFetchLatestBeaconState reads the latest beacon state from disk or fetches it from the network.
-func ReadOrFetchLatestBeaconState(ctx context.Context, dirs datadir.Dirs, beaconCfg *clparams.BeaconChainConfig, caplinConfig clparams.CaplinConfig, genesisDB genesisdb.GenesisDB) (*state.CachingBeaconState, error) {
+// ReadOrFetchLatestBeaconState reads the latest beacon state from disk or fetches it from the network. The checkpoint
+// files are only used to start a node without a local state, so that restarting with the flags set does not roll it back.
+func ReadOrFetchLatestBeaconState(ctx context.Context, dirs datadir.Dirs, beaconCfg *clparams.BeaconChainConfig, caplinConfig clparams.CaplinConfig, genesisDB genesisdb.GenesisDB, depositDB depositdb.DepositDB) (*state.CachingBeaconState, error) {
 	var syncer CheckpointSyncer
 	remoteSync := !caplinConfig.DisabledCheckpointSync && !caplinConfig.IsDevnet()
+	localFs := afero.NewBasePathFs(afero.NewOsFs(), dirs.CaplinLatest)
 
-	if remoteSync {
+	fromFile := caplinConfig.CheckpointSyncFromFile()
+	if fromFile {
+		hasLocalState, err := afero.Exists(localFs, clparams.LatestStateFileName)
+		if err != nil {
+			return nil, err
+		}
+		if hasLocalState {
+			log.Info("[Checkpoint Sync] Local state found, ignoring the checkpoint files")
+			fromFile, remoteSync = false, false
+		}
+	}
+
+	if fromFile {
+		syncer = NewFileCheckpointSyncer(beaconCfg, caplinConfig.CheckpointSyncStateFile, caplinConfig.CheckpointSyncBlockFile, caplinConfig.CheckpointSyncBlockRoot)
+	} else if remoteSync {
 		syncer = NewRemoteCheckpointSync(beaconCfg, caplinConfig.NetworkId)
 	} else {
-		aferoFs := afero.NewOsFs()
-
 		genesisState, err := genesisDB.ReadGenesisState()
 		if err != nil {
 			return nil, fmt.Errorf("could not read genesis state: %w", err)
 		}
-		syncer = NewLocalCheckpointSyncer(genesisState, afero.NewBasePathFs(aferoFs, dirs.CaplinLatest))
+		syncer = NewLocalCheckpointSyncer(genesisState, localFs)
+	}
+	bs, err := syncer.GetLatestBeaconState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if snapshotSyncer, ok := syncer.(DepositSnapshotSyncer); ok {
+		if err := fetchDepositSnapshot(ctx, snapshotSyncer, bs, depositDB); err != nil {
+			log.Warn("[Checkpoint Sync] Could not use deposit snapshot, the deposit tree will not be served", "err", err)
+		}
+	} else if err := bootstrapDepositSnapshot(bs, depositDB); err != nil {
+		log.Warn("[Checkpoint Sync] Could not bootstrap the deposit tree, it will not be served", "err", err)
+	}
+	return bs, nil
+}
+
+// fetchDepositSnapshot replaces the deposit snapshot by the one of the checkpoint sync server, as the deposits of the
+// blocks before the checkpoint are not known. It must match the deposits processed by the checkpoint state.
+func fetchDepositSnapshot(ctx context.Context, syncer DepositSnapshotSyncer, bs *state.CachingBeaconState, depositDB depositdb.DepositDB) error {
+	snapshot, err := syncer.GetDepositSnapshot(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := deposit_tree.FromSnapshot(snapshot); err != nil {
+		return err
+	}
+	if snapshot.DepositCount != bs.Eth1DepositIndex() {
+		return fmt.Errorf("deposit snapshot has %d deposits, while the checkpoint state processed %d", snapshot.DepositCount, bs.Eth1DepositIndex())
+	}
+	return depositDB.WriteSnapshot(snapshot, bs.Slot())
+}
+
+// bootstrapDepositSnapshot writes the empty deposit snapshot of a state which processed no deposit yet, such as the
+// genesis state of a network without genesis deposits, if the deposit tree was not bootstrapped before. The deposits of
+// any other state are unknown without a checkpoint sync server.
+func bootstrapDepositSnapshot(bs *state.CachingBeaconState, depositDB depositdb.DepositDB) error {
+	snapshot, _, err := depositDB.ReadSnapshot()
+	if err != nil {
+		return err
+	}
+	if snapshot != nil {
+		return nil
+	}
+	eth1Data := bs.Eth1Data()
+	if bs.Eth1DepositIndex() != 0 || eth1Data.DepositCount != 0 {
+		return fmt.Errorf("the %d deposits of the state at slot %d are unknown", eth1Data.DepositCount, bs.Slot())
+	}
+	// the height of the execution block is not known to the beacon state, it is set by the first finalized deposit
+	tree := deposit_tree.New()
+	if err := tree.Finalize(&cltypes.Eth1Data{BlockHash: eth1Data.BlockHash}, 0); err != nil {
+		return err
+	}
+	if snapshot, err = tree.Snapshot(); err != nil {
+		return err
 	}
-	return syncer.GetLatestBeaconState(ctx)
+	return depositDB.WriteSnapshot(snapshot, bs.Slot())
 }
@@ -0,0 +1,274 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package deposit_tree
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/merkle_tree"
+	"github.com/erigontech/erigon/cl/utils"
+)
+
+const depth = cltypes.DepositTreeDepth
+
+var errTreeFull = errors.New("deposit tree is full")
+
+/*
+ * The deposit tree is the EIP-4881 sparse representation of the deposit contract merkle tree: the
+ * subtrees which are fully finalized are collapsed into their root, so that only the finalized
+ * branch plus the deposits which came after it are kept in memory.
+ */
+type merkleTree interface {
+	root() common.Hash
+	isFull() bool
+	pushLeaf(leaf common.Hash, level int) (merkleTree, error)
+	finalize(depositsToFinalize uint64, level int) merkleTree
+	// finalized appends the roots of the finalized subtrees to result, and returns the number of deposits they hold
+	finalized(result []common.Hash) ([]common.Hash, uint64)
+}
+
+// finalizedNode is a fully finalized subtree, collapsed into its root
+type finalizedNode struct {
+	depositCount uint64
+	hash         common.Hash
+}
+
+func (n *finalizedNode) root() common.Hash { return n.hash }
+func (n *finalizedNode) isFull() bool      { return true }
+func (n *finalizedNode) pushLeaf(common.Hash, int) (merkleTree, error) {
+	return nil, errTreeFull
+}
+func (n *finalizedNode) finalize(uint64, int) merkleTree { return n }
+func (n *finalizedNode) finalized(result []common.Hash) ([]common.Hash, uint64) {
+	return append(result, n.hash), n.depositCount
+}
+
+type leafNode struct {
+	hash common.Hash
+}
+
+func (n *leafNode) root() common.Hash { return n.hash }
+func (n *leafNode) isFull() bool      { return true }
+func (n *leafNode) pushLeaf(common.Hash, int) (merkleTree, error) {
+	return nil, errTreeFull
+}
+func (n *leafNode) finalize(uint64, int) merkleTree {
+	return &finalizedNode{depositCount: 1, hash: n.hash}
+}
+func (n *leafNode) finalized(result []common.Hash) ([]common.Hash, uint64) {
+	return result, 0
+}
+
+type innerNode struct {
+	left, right merkleTree
+}
+
+func (n *innerNode) root() common.Hash {
+	left, right := n.left.root(), n.right.root()
+	return utils.Sha256(left[:], right[:])
+}
+
+func (n *innerNode) isFull() bool { return n.right.isFull() }
+
+func (n *innerNode) pushLeaf(leaf common.Hash, level int) (merkleTree, error) {
+	var err error
+	if !n.left.isFull() {
+		n.left, err = n.left.pushLeaf(leaf, level-1)
+	} else {
+		n.right, err = n.right.pushLeaf(leaf, level-1)
+	}
+	return n, err
+}
+
+func (n *innerNode) finalize(depositsToFinalize uint64, level int) merkleTree {
+	deposits := uint64(1) << level
+	if deposits <= depositsToFinalize {
+		return &finalizedNode{depositCount: deposits, hash: n.root()}
+	}
+	n.left = n.left.finalize(depositsToFinalize, level-1)
+	if depositsToFinalize > deposits/2 {
+		n.right = n.right.finalize(depositsToFinalize-deposits/2, level-1)
+	}
+	return n
+}
+
+func (n *innerNode) finalized(result []common.Hash) ([]common.Hash, uint64) {
+	result, left := n.left.finalized(result)
+	result, right := n.right.finalized(result)
+	return result, left + right
+}
+
+type zeroNode struct {
+	level int
+}
+
+func (n *zeroNode) root() common.Hash { return merkle_tree.ZeroHashes[n.level] }
+func (n *zeroNode) isFull() bool      { return false }
+func (n *zeroNode) pushLeaf(leaf common.Hash, level int) (merkleTree, error) {
+	return create([]common.Hash{leaf}, level), nil
+}
+func (n *zeroNode) finalize(uint64, int) merkleTree { return n }
+func (n *zeroNode) finalized(result []common.Hash) ([]common.Hash, uint64) {
+	return result, 0
+}
+
+func create(leaves []common.Hash, level int) merkleTree {
+	if len(leaves) == 0 {
+		return &zeroNode{level: level}
+	}
+	if level == 0 {
+		return &leafNode{hash: leaves[0]}
+	}
+	split := min(uint64(1)<<(level-1), uint64(len(leaves)))
+	return &innerNode{
+		left:  create(leaves[:split], level-1),
+		right: create(leaves[split:], level-1),
+	}
+}
+
+func fromSnapshotParts(finalized []common.Hash, depositCount uint64, level int) merkleTree {
+	if len(finalized) == 0 || depositCount == 0 {
+		return &zeroNode{level: level}
+	}
+	if depositCount == uint64(1)<<level {
+		return &finalizedNode{depositCount: depositCount, hash: finalized[0]}
+	}
+	leftSubtree := uint64(1) << (level - 1)
+	if depositCount <= leftSubtree {
+		return &innerNode{
+			left:  fromSnapshotParts(finalized, depositCount, level-1),
+			right: &zeroNode{level: level - 1},
+		}
+	}
+	return &innerNode{
+		left:  &finalizedNode{depositCount: leftSubtree, hash: finalized[0]},
+		right: fromSnapshotParts(finalized[1:], depositCount-leftSubtree, level-1),
+	}
+}
+
+// mixInLength returns the deposit root as exposed by the deposit contract, i.e. the tree root mixed in with its size
+func mixInLength(root common.Hash, depositCount uint64) common.Hash {
+	var size [32]byte
+	binary.LittleEndian.PutUint64(size[:], depositCount)
+	return utils.Sha256(root[:], size[:])
+}
+
+// DepositTree is the deposit contract tree, of which only the branch of the last finalization is kept.
+type DepositTree struct {
+	tree                 merkleTree
+	depositCount         uint64
+	executionBlockHash   common.Hash
+	executionBlockHeight uint64
+	finalizedOnce        bool
+}
+
+func New() *DepositTree {
+	return &DepositTree{tree: &zeroNode{level: depth}}
+}
+
+// FromSnapshot restores the tree from its EIP-4881 snapshot, after checking that the snapshot is consistent.
+func FromSnapshot(snapshot *cltypes.DepositSnapshot) (*DepositTree, error) {
+	finalized := make([]common.Hash, 0, snapshot.Finalized.Length())
+	for i := 0; i < snapshot.Finalized.Length(); i++ {
+		finalized = append(finalized, snapshot.Finalized.Get(i))
+	}
+	if root := calculateSnapshotRoot(finalized, snapshot.DepositCount); root != snapshot.DepositRoot {
+		return nil, fmt.Errorf("deposit snapshot root mismatch: expected %x, got %x", snapshot.DepositRoot, root)
+	}
+	return &DepositTree{
+		tree:                 fromSnapshotParts(finalized, snapshot.DepositCount, depth),
+		depositCount:         snapshot.DepositCount,
+		executionBlockHash:   snapshot.ExecutionBlockHash,
+		executionBlockHeight: snapshot.ExecutionBlockHeight,
+		finalizedOnce:        true,
+	}, nil
+}
+
+func calculateSnapshotRoot(finalized []common.Hash, depositCount uint64) common.Hash {
+	size := depositCount
+	index := len(finalized)
+	root := common.Hash(merkle_tree.ZeroHashes[0])
+	for i := 0; i < depth; i++ {
+		if size&1 == 1 {
+			if index == 0 {
+				// not enough finalized roots for the deposit count, this can't match any root
+				return common.Hash{}
+			}
+			index--
+			root = utils.Sha256(finalized[index][:], root[:])
+		} else {
+			root = utils.Sha256(root[:], merkle_tree.ZeroHashes[i][:])
+		}
+		size >>= 1
+	}
+	return mixInLength(root, depositCount)
+}
+
+// PushLeaf appends the hash tree root of the data of the next deposit.
+func (t *DepositTree) PushLeaf(leaf common.Hash) error {
+	tree, err := t.tree.pushLeaf(leaf, depth)
+	if err != nil {
+		return err
+	}
+	t.tree = tree
+	t.depositCount++
+	return nil
+}
+
+// Finalize collapses the deposits up to eth1Data.DepositCount, which were included by a finalized state, into the
+// finalized branch. executionBlockHeight is the number of the execution block eth1Data.BlockHash.
+func (t *DepositTree) Finalize(eth1Data *cltypes.Eth1Data, executionBlockHeight uint64) error {
+	if eth1Data.DepositCount > t.depositCount {
+		return fmt.Errorf("cannot finalize %d deposits, only %d are known", eth1Data.DepositCount, t.depositCount)
+	}
+	t.tree = t.tree.finalize(eth1Data.DepositCount, depth)
+	t.executionBlockHash = eth1Data.BlockHash
+	t.executionBlockHeight = executionBlockHeight
+	t.finalizedOnce = true
+	return nil
+}
+
+// Root is the deposit root, as returned by the deposit contract.
+func (t *DepositTree) Root() common.Hash {
+	return mixInLength(t.tree.root(), t.depositCount)
+}
+
+// DepositCount is the number of deposits in the tree, finalized or not.
+func (t *DepositTree) DepositCount() uint64 {
+	return t.depositCount
+}
+
+// Snapshot returns the EIP-4881 snapshot of the finalized part of the tree.
+func (t *DepositTree) Snapshot() (*cltypes.DepositSnapshot, error) {
+	if !t.finalizedOnce {
+		return nil, errors.New("deposit tree was never finalized")
+	}
+	finalized, depositCount := t.tree.finalized(nil)
+	snapshot := cltypes.NewDepositSnapshot()
+	for _, root := range finalized {
+		snapshot.Finalized.Append(root)
+	}
+	snapshot.DepositRoot = calculateSnapshotRoot(finalized, depositCount)
+	snapshot.DepositCount = depositCount
+	snapshot.ExecutionBlockHash = t.executionBlockHash
+	snapshot.ExecutionBlockHeight = t.executionBlockHeight
+	return snapshot, nil
+}
@@ -0,0 +1,171 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package deposit_tree
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/merkle_tree"
+	"github.com/erigontech/erigon/cl/persistence/depositdb"
+	"github.com/erigontech/erigon/cl/utils"
+)
+
+// naiveDepositRoot computes the deposit contract root of leaves layer by layer
+func naiveDepositRoot(leaves []common.Hash) common.Hash {
+	layer := append([]common.Hash{}, leaves...)
+	for level := 0; level < depth; level++ {
+		if len(layer)%2 == 1 {
+			layer = append(layer, merkle_tree.ZeroHashes[level])
+		}
+		next := make([]common.Hash, 0, len(layer)/2)
+		for i := 0; i < len(layer); i += 2 {
+			next = append(next, utils.Sha256(layer[i][:], layer[i+1][:]))
+		}
+		if len(next) == 0 {
+			next = append(next, utils.Sha256(merkle_tree.ZeroHashes[level][:], merkle_tree.ZeroHashes[level][:]))
+		}
+		layer = next
+	}
+	return mixInLength(layer[0], uint64(len(leaves)))
+}
+
+func testDepositData(i int) *cltypes.DepositData {
+	return &cltypes.DepositData{PubKey: common.Bytes48{byte(i)}, Amount: uint64(i) * 1_000_000_000}
+}
+
+func testLeaves(t *testing.T, n int) []common.Hash {
+	leaves := make([]common.Hash, n)
+	for i := range leaves {
+		leaf, err := testDepositData(i).HashSSZ()
+		require.NoError(t, err)
+		leaves[i] = leaf
+	}
+	return leaves
+}
+
+func TestDepositTreeFinalizeAndSnapshot(t *testing.T) {
+	leaves := testLeaves(t, 100)
+	tree := New()
+	require.Equal(t, naiveDepositRoot(nil), tree.Root())
+	for i, leaf := range leaves {
+		require.NoError(t, tree.PushLeaf(leaf))
+		require.Equal(t, naiveDepositRoot(leaves[:i+1]), tree.Root())
+	}
+	_, err := tree.Snapshot()
+	require.Error(t, err)
+
+	eth1Data := &cltypes.Eth1Data{Root: naiveDepositRoot(leaves[:37]), DepositCount: 37, BlockHash: common.Hash{1}}
+	require.NoError(t, tree.Finalize(eth1Data, 1234))
+	// finalization doesn't change the root
+	require.Equal(t, naiveDepositRoot(leaves), tree.Root())
+
+	snapshot, err := tree.Snapshot()
+	require.NoError(t, err)
+	require.Equal(t, uint64(37), snapshot.DepositCount)
+	require.Equal(t, eth1Data.Root, snapshot.DepositRoot)
+	require.Equal(t, eth1Data.BlockHash, snapshot.ExecutionBlockHash)
+	require.Equal(t, uint64(1234), snapshot.ExecutionBlockHeight)
+	// one root per bit set in the deposit count
+	require.Equal(t, 3, snapshot.Finalized.Length())
+
+	// a tree restored from the snapshot continues as the original one
+	restored, err := FromSnapshot(snapshot)
+	require.NoError(t, err)
+	for _, leaf := range leaves[37:] {
+		require.NoError(t, restored.PushLeaf(leaf))
+	}
+	require.Equal(t, tree.Root(), restored.Root())
+
+	// snapshots survive the encodings of the beacon API
+	enc, err := snapshot.EncodeSSZ(nil)
+	require.NoError(t, err)
+	require.Len(t, enc, snapshot.EncodingSizeSSZ())
+	decoded := cltypes.NewDepositSnapshot()
+	require.NoError(t, decoded.DecodeSSZ(enc, 0))
+	require.Equal(t, snapshot, decoded)
+
+	js, err := json.Marshal(snapshot)
+	require.NoError(t, err)
+	decoded = cltypes.NewDepositSnapshot()
+	require.NoError(t, json.Unmarshal(js, decoded))
+	require.Equal(t, snapshot, decoded)
+
+	snapshot.DepositCount++
+	_, err = FromSnapshot(snapshot)
+	require.Error(t, err)
+}
+
+func TestTracker(t *testing.T) {
+	leaves := testLeaves(t, 8)
+	db := depositdb.NewDepositDBWithFs(afero.NewMemMapFs())
+
+	// not bootstrapped
+	tracker, err := NewTracker(db)
+	require.NoError(t, err)
+	require.Nil(t, tracker.Snapshot())
+	_, ok := tracker.Slot()
+	require.False(t, ok)
+
+	tree := New()
+	for _, leaf := range leaves[:5] {
+		require.NoError(t, tree.PushLeaf(leaf))
+	}
+	require.NoError(t, tree.Finalize(&cltypes.Eth1Data{Root: tree.Root(), DepositCount: 5}, 1))
+	snapshot, err := tree.Snapshot()
+	require.NoError(t, err)
+	require.NoError(t, db.WriteSnapshot(snapshot, 10))
+
+	tracker, err = NewTracker(db)
+	require.NoError(t, err)
+	slot, ok := tracker.Slot()
+	require.True(t, ok)
+	require.Equal(t, uint64(10), slot)
+
+	block := func(slot uint64, deposits ...int) *cltypes.SignedBeaconBlock {
+		b := cltypes.NewSignedBeaconBlock(&clparams.MainnetBeaconConfig, clparams.DenebVersion)
+		b.Block.Slot = slot
+		for _, i := range deposits {
+			b.Block.Body.Deposits.Append(&cltypes.Deposit{Data: testDepositData(i)})
+		}
+		return b
+	}
+	blocks := []*cltypes.SignedBeaconBlock{block(11, 5, 6), block(12), block(13, 7)}
+	eth1Data := &cltypes.Eth1Data{Root: naiveDepositRoot(leaves), DepositCount: 8, BlockHash: common.Hash{2}}
+
+	// deposits not matching the state are rejected, and the tracker goes back to its snapshot
+	require.Error(t, tracker.OnFinalized(blocks, eth1Data, 9, 42))
+	slot, _ = tracker.Slot()
+	require.Equal(t, uint64(10), slot)
+
+	require.NoError(t, tracker.OnFinalized(blocks, eth1Data, 8, 42))
+	require.Equal(t, uint64(8), tracker.Snapshot().DepositCount)
+	require.Equal(t, eth1Data.Root, tracker.Snapshot().DepositRoot)
+
+	// the finalization is persisted
+	tracker, err = NewTracker(db)
+	require.NoError(t, err)
+	slot, _ = tracker.Slot()
+	require.Equal(t, uint64(13), slot)
+	require.Equal(t, uint64(42), tracker.Snapshot().ExecutionBlockHeight)
+}
@@ -0,0 +1,127 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package deposit_tree
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/persistence/depositdb"
+)
+
+// Tracker maintains the deposit tree of the finalized chain out of the deposits included in the finalized blocks.
+// It needs to be bootstrapped from a snapshot, as the deposits of the genesis state and of the blocks before the
+// checkpoint sync anchor are not known.
+type Tracker struct {
+	db depositdb.DepositDB
+
+	mu           sync.RWMutex
+	tree         *DepositTree
+	slot         uint64 // slot of the last block whose deposits are in the tree
+	snapshot     *cltypes.DepositSnapshot
+	snapshotSlot uint64
+}
+
+// NewTracker creates a tracker resuming from the snapshot persisted in db, if any.
+func NewTracker(db depositdb.DepositDB) (*Tracker, error) {
+	t := &Tracker{db: db}
+	snapshot, slot, err := db.ReadSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	if snapshot == nil {
+		return t, nil
+	}
+	if t.tree, err = FromSnapshot(snapshot); err != nil {
+		return nil, err
+	}
+	t.slot, t.snapshot, t.snapshotSlot = slot, snapshot, slot
+	return t, nil
+}
+
+// Snapshot returns the snapshot of the last finalization of the tree, nil if the tracker is not bootstrapped.
+func (t *Tracker) Snapshot() *cltypes.DepositSnapshot {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.snapshot
+}
+
+// Slot returns the slot of the last block whose deposits were added, false if the tracker is not bootstrapped.
+func (t *Tracker) Slot() (uint64, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.slot, t.tree != nil
+}
+
+// OnFinalized adds the deposits of blocks, the finalized blocks following Slot() in ascending order, and finalizes
+// the tree at the eth1 data of the state of the last one. eth1DepositIndex is the deposit index of that state and
+// executionBlockHeight the number of the execution block eth1Data.BlockHash.
+func (t *Tracker) OnFinalized(blocks []*cltypes.SignedBeaconBlock, eth1Data *cltypes.Eth1Data, eth1DepositIndex, executionBlockHeight uint64) (err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.tree == nil || len(blocks) == 0 {
+		return nil
+	}
+	defer func() {
+		// the tree is in an unknown state, start over from the last snapshot
+		if err != nil {
+			t.tree, _ = FromSnapshot(t.snapshot)
+			t.slot = t.snapshotSlot
+		}
+	}()
+	for _, block := range blocks {
+		if block.Block.Slot <= t.slot {
+			return fmt.Errorf("block at slot %d is not after the deposit tree slot %d", block.Block.Slot, t.slot)
+		}
+		block.Block.Body.Deposits.Range(func(_ int, deposit *cltypes.Deposit, _ int) bool {
+			var leaf [32]byte
+			if leaf, err = deposit.Data.HashSSZ(); err != nil {
+				return false
+			}
+			err = t.tree.PushLeaf(leaf)
+			return err == nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	t.slot = blocks[len(blocks)-1].Block.Slot
+	if t.tree.DepositCount() != eth1DepositIndex {
+		return fmt.Errorf("deposit tree has %d deposits, while the finalized state processed %d", t.tree.DepositCount(), eth1DepositIndex)
+	}
+	// Only the deposits the eth1 data commits to can be finalized, which are all known once they have all been
+	// processed. Until then the tree grows without being finalized further.
+	if eth1DepositIndex != eth1Data.DepositCount || eth1Data.DepositCount == t.snapshot.DepositCount {
+		return nil
+	}
+	if root := t.tree.Root(); root != eth1Data.Root {
+		return fmt.Errorf("deposit tree root mismatch: expected %x, got %x", eth1Data.Root, root)
+	}
+	if err := t.tree.Finalize(eth1Data, executionBlockHeight); err != nil {
+		return err
+	}
+	snapshot, err := t.tree.Snapshot()
+	if err != nil {
+		return err
+	}
+	if err := t.db.WriteSnapshot(snapshot, t.slot); err != nil {
+		return err
+	}
+	t.snapshot, t.snapshotSlot = snapshot, t.slot
+	return nil
+}
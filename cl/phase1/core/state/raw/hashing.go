@@ -247,3 +247,17 @@ func (b *BeaconState) markLeaf(idxs ...StateLeafIndex) {
 		b.touchedLeaves[idx].Store(LeafDirtyValue)
 	}
 }
+
+// FieldRoots returns the hash tree root of every field of the state, in the order of the schema of its version.
+func (b *BeaconState) FieldRoots() ([]common.Hash, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.computeDirtyLeaves(); err != nil {
+		return nil, err
+	}
+	roots := make([]common.Hash, len(b.getSchema()))
+	for i := range roots {
+		copy(roots[i][:], b.leaves[i*32:(i+1)*32])
+	}
+	return roots, nil
+}
@@ -182,6 +182,10 @@ func (cc *ExecutionClientDirect) HasBlock(ctx context.Context, hash common.Hash)
 	return cc.chainRW.HasBlock(ctx, hash)
 }
 
+func (cc *ExecutionClientDirect) HeaderNumber(ctx context.Context, hash common.Hash) (*uint64, error) {
+	return cc.chainRW.HeaderNumber(ctx, hash)
+}
+
 func (cc *ExecutionClientDirect) GetAssembledBlock(_ context.Context, idBytes []byte) (*cltypes.Eth1Block, *engine_types.BlobsBundleV1, *typesproto.RequestsBundle, *big.Int, error) {
 	return cc.chainRW.GetAssembledBlock(binary.LittleEndian.Uint64(idBytes))
 }
@@ -271,6 +271,21 @@ func (cc *ExecutionClientRpc) HasBlock(ctx context.Context, hash common.Hash) (b
 	panic("unimplemented")
 }
 
+// HeaderNumber returns the number of the block with given hash, nil if it is unknown
+func (cc *ExecutionClientRpc) HeaderNumber(ctx context.Context, hash common.Hash) (*uint64, error) {
+	var result *struct {
+		Number hexutil.Uint64 `json:"number"`
+	}
+	if err := cc.client.CallContext(ctx, &result, rpc_helper.GetBlockByHash, hash, false); err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, nil
+	}
+	number := uint64(result.Number)
+	return &number, nil
+}
+
 // Block production
 
 func (cc *ExecutionClientRpc) GetAssembledBlock(ctx context.Context, id []byte) (*cltypes.Eth1Block, *engine_types.BlobsBundleV1, *typesproto.RequestsBundle, *big.Int, error) {
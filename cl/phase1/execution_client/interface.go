@@ -47,6 +47,7 @@ type ExecutionEngine interface {
 	GetBodiesByRange(ctx context.Context, start, count uint64) ([]*types.RawBody, error)
 	GetBodiesByHashes(ctx context.Context, hashes []common.Hash) ([]*types.RawBody, error)
 	HasBlock(ctx context.Context, hash common.Hash) (bool, error)
+	HeaderNumber(ctx context.Context, hash common.Hash) (*uint64, error)
 	// Snapshots
 	FrozenBlocks(ctx context.Context) uint64
 	HasGapInSnapshots(ctx context.Context) bool
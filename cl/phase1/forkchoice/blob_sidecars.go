@@ -54,3 +54,61 @@ func (f *ForkChoiceStore) AddPreverifiedBlobSidecar(blobSidecar *cltypes.BlobSid
 
 	return nil
 }
+
+func (f *ForkChoiceStore) AddPreverifiedDataColumnSidecar(columnSidecar *cltypes.DataColumnSidecar) error {
+	blockRoot, err := columnSidecar.SignedBlockHeader.Header.HashSSZ()
+	if err != nil {
+		return err
+	}
+
+	// operation is not thread safe from here.
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, sidecar := range f.hotColumnSidecars[blockRoot] {
+		if sidecar.Index == columnSidecar.Index {
+			return nil // ignore if we already have it
+		}
+	}
+	f.hotColumnSidecars[blockRoot] = append(f.hotColumnSidecars[blockRoot], columnSidecar)
+
+	columnsMaxAge := 4 // same as blobs, a slot can live for up to 4 slots in the pool of hot sidecars.
+	currentSlot := f.highestSeen.Load()
+	var pruneSlot uint64
+	if currentSlot > uint64(columnsMaxAge) {
+		pruneSlot = currentSlot - uint64(columnsMaxAge)
+	}
+	for blockRoot, sidecars := range f.hotColumnSidecars {
+		if len(sidecars) == 0 || sidecars[0].SignedBlockHeader.Header.Slot < pruneSlot {
+			delete(f.hotColumnSidecars, blockRoot)
+		}
+	}
+	return nil
+}
+
+// SetCustodyColumns sets the columns the node custodies, which have to be available for blocks to be imported
+// from Fulu onwards. Until it is set, all the columns are required.
+func (f *ForkChoiceStore) SetCustodyColumns(columns []uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.custodyColumns = columns
+}
+
+// CustodyColumns returns the columns which have to be available for blocks to be imported from Fulu onwards.
+func (f *ForkChoiceStore) CustodyColumns() []uint64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.requiredColumns()
+}
+
+// requiredColumns returns the custodied columns, or all of them until they are set.
+func (f *ForkChoiceStore) requiredColumns() []uint64 {
+	if f.custodyColumns != nil {
+		return f.custodyColumns
+	}
+	columns := make([]uint64, f.beaconCfg.NumberOfColumns)
+	for i := range columns {
+		columns[i] = uint64(i)
+	}
+	return columns
+}
@@ -233,10 +233,12 @@ func (f *forkGraphDisk) AddChainSegment(signedBlock *cltypes.SignedBeaconBlock,
 		} else {
 			f.newestLightClientUpdate.Store(lcUpdate)
 			period := f.beaconCfg.SyncCommitteePeriod(newState.Slot())
-			_, hasPeriod := f.lightClientUpdates.Load(period)
+			best, hasPeriod := f.lightClientUpdates.Load(period)
 			if !hasPeriod {
 				log.Info("Adding light client update", "period", period)
 				f.lightClientUpdates.Store(period, lcUpdate)
+			} else if lightclient_utils.IsBetterUpdate(f.beaconCfg, lcUpdate, best.(*cltypes.LightClientUpdate)) {
+				f.lightClientUpdates.Store(period, lcUpdate)
 			}
 			// light client events
 			f.emitter.State().SendLightClientFinalityUpdate(&beaconevents.LightClientFinalityUpdateData{
@@ -92,7 +92,9 @@ type ForkChoiceStore struct {
 	genesisValidatorsRoot    common.Hash
 	weights                  map[common.Hash]uint64
 	headSet                  map[common.Hash]struct{}
-	hotSidecars              map[common.Hash][]*cltypes.BlobSidecar // Set of sidecars that are not yet processed.
+	hotSidecars              map[common.Hash][]*cltypes.BlobSidecar       // Set of sidecars that are not yet processed.
+	hotColumnSidecars        map[common.Hash][]*cltypes.DataColumnSidecar // Set of column sidecars that are not yet processed.
+	custodyColumns           []uint64                                     // Columns which have to be available for a block to be imported.
 	verifiedExecutionPayload *lru.Cache[common.Hash, struct{}]
 	// childrens
 	childrens sync.Map
@@ -250,6 +252,7 @@ func NewForkChoiceStore(
 		nextBlockProposers:       nextBlockProposers,
 		genesisValidatorsRoot:    anchorState.GenesisValidatorsRoot(),
 		hotSidecars:              make(map[common.Hash][]*cltypes.BlobSidecar),
+		hotColumnSidecars:        make(map[common.Hash][]*cltypes.DataColumnSidecar),
 		blobStorage:              blobStorage,
 		ethClock:                 ethClock,
 		optimisticStore:          optimistic.NewOptimisticStore(),
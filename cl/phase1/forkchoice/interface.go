@@ -89,6 +89,7 @@ type ForkChoiceStorageWriter interface {
 		checkDataAvaibility bool,
 	) error
 	AddPreverifiedBlobSidecar(blobSidecar *cltypes.BlobSidecar) error
+	AddPreverifiedDataColumnSidecar(sidecar *cltypes.DataColumnSidecar) error
 	OnTick(time uint64)
 	SetSynced(synced bool)
 	ProcessAttestingIndicies(attestation *solid.Attestation, attestionIndicies []uint64)
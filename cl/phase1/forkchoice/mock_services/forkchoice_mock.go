@@ -349,6 +349,10 @@ func (f *ForkChoiceStorageMock) GetPublicKeyForValidator(
 func (f *ForkChoiceStorageMock) AddPreverifiedBlobSidecar(msg *cltypes.BlobSidecar) error {
 	return nil
 }
+
+func (f *ForkChoiceStorageMock) AddPreverifiedDataColumnSidecar(msg *cltypes.DataColumnSidecar) error {
+	return nil
+}
 func (f *ForkChoiceStorageMock) ValidateOnAttestation(attestation *solid.Attestation) error {
 	panic("implement me")
 }
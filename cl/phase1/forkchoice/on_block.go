@@ -277,6 +277,9 @@ func (f *ForkChoiceStore) isDataAvailable(ctx context.Context, slot uint64, bloc
 	if f.blobStorage == nil {
 		return nil
 	}
+	if slot/f.beaconCfg.SlotsPerEpoch >= f.beaconCfg.FuluForkEpoch {
+		return f.isDataColumnsAvailable(ctx, slot, blockRoot, blobKzgCommitments)
+	}
 
 	commitmentsLeftToCheck := map[common.Bytes48]struct{}{}
 	blobKzgCommitments.Range(func(index int, value *cltypes.KZGCommitment, length int) bool {
@@ -312,3 +315,50 @@ func (f *ForkChoiceStore) isDataAvailable(ctx context.Context, slot uint64, bloc
 	}
 	return nil
 }
+
+// isDataColumnsAvailable checks that the custodied columns of the block are available, which is what the data
+// availability of a block means from Fulu onwards (PeerDAS).
+func (f *ForkChoiceStore) isDataColumnsAvailable(ctx context.Context, slot uint64, blockRoot common.Hash, blobKzgCommitments *solid.ListSSZ[*cltypes.KZGCommitment]) error {
+	if blobKzgCommitments.Len() == 0 {
+		return nil
+	}
+	columns := f.requiredColumns()
+	commitmentsRoot, err := blobKzgCommitments.HashSSZ()
+	if err != nil {
+		return err
+	}
+	hotSidecars := make(map[uint64]*cltypes.DataColumnSidecar, len(f.hotColumnSidecars[blockRoot]))
+	for _, sidecar := range f.hotColumnSidecars[blockRoot] {
+		hotSidecars[sidecar.Index] = sidecar
+	}
+
+	var sidecarsToWrite []*cltypes.DataColumnSidecar
+	for _, column := range columns {
+		onDisk, err := f.blobStorage.HasDataColumnSidecar(slot, blockRoot, column)
+		if err != nil {
+			return fmt.Errorf("cannot check data avaiability. failed to read column sidecars: %v", err)
+		}
+		if onDisk {
+			continue
+		}
+		sidecar, ok := hotSidecars[column]
+		if !ok {
+			return ErrEIP4844DataNotAvailable // This should then schedule the block for reprocessing
+		}
+		// Columns are preverified so we skip verification, we just need to check if commitments checks out.
+		sidecarCommitmentsRoot, err := sidecar.KzgCommitments.HashSSZ()
+		if err != nil {
+			return err
+		}
+		if sidecarCommitmentsRoot != commitmentsRoot {
+			return ErrEIP4844DataNotAvailable
+		}
+		sidecarsToWrite = append(sidecarsToWrite, sidecar)
+	}
+	if len(sidecarsToWrite) > 0 {
+		if err := f.blobStorage.WriteDataColumnSidecars(ctx, blockRoot, sidecarsToWrite); err != nil {
+			return fmt.Errorf("failed to write column sidecars: %v", err)
+		}
+	}
+	return nil
+}
@@ -17,6 +17,8 @@
 package network
 
 import (
+	"errors"
+	"fmt"
 	"math"
 	"sync"
 	"sync/atomic"
@@ -37,6 +39,9 @@ import (
 	"github.com/erigontech/erigon/turbo/snapshotsync/freezeblocks"
 )
 
+// ErrTrustedCheckpointMismatch is returned when the backfilled chain does not go through the trusted checkpoint.
+var ErrTrustedCheckpointMismatch = errors.New("backfilled chain does not link up to the trusted checkpoint")
+
 // Whether the reverse downloader arrived at expected height or condition.
 type OnNewBlock func(blk *cltypes.SignedBeaconBlock) (finished bool, err error)
 
@@ -52,6 +57,9 @@ type BackwardBeaconDownloader struct {
 	db             kv.RwDB
 	sn             *freezeblocks.CaplinSnapshots
 	neverSkip      bool
+	// trustedRoot is the block the backfilled chain must go through at trustedSlot, zero once it was reached.
+	trustedRoot common.Hash
+	trustedSlot uint64
 
 	mu sync.Mutex
 }
@@ -87,6 +95,29 @@ func (b *BackwardBeaconDownloader) SetExpectedRoot(root common.Hash) {
 	b.expectedRoot = root
 }
 
+// SetTrustedCheckpoint sets a block the backfilled chain must go through, the download fails otherwise.
+func (b *BackwardBeaconDownloader) SetTrustedCheckpoint(root common.Hash, slot uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trustedRoot = root
+	b.trustedSlot = slot
+}
+
+// checkTrustedCheckpoint checks the first block at or below the slot of the trusted checkpoint is the trusted one.
+func (b *BackwardBeaconDownloader) checkTrustedCheckpoint(root common.Hash, slot uint64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.trustedRoot == (common.Hash{}) || slot > b.trustedSlot {
+		return nil
+	}
+	if root != b.trustedRoot {
+		return fmt.Errorf("%w: got block %x at slot %d, expected %x at slot %d", ErrTrustedCheckpointMismatch, root, slot, b.trustedRoot, b.trustedSlot)
+	}
+	// the rest of the chain links up through the parent roots
+	b.trustedRoot = common.Hash{}
+	return nil
+}
+
 // SetExpectedRoot sets the expected root we expect to download.
 func (b *BackwardBeaconDownloader) SetNeverSkip(neverSkip bool) {
 	b.mu.Lock()
@@ -182,6 +213,9 @@ Loop:
 			log.Debug("Gotten unexpected root", "got", common.Hash(blockRoot), "expected", b.expectedRoot)
 			continue
 		}
+		if err := b.checkTrustedCheckpoint(blockRoot, segment.Block.Slot); err != nil {
+			return err
+		}
 		// Yes? then go for the callback.
 		finished, err := b.onNewBlock(segment)
 		b.finished.Store(finished)
@@ -267,6 +301,9 @@ Loop:
 		if *slot <= clFrozenBlocks {
 			break
 		}
+		if err := b.checkTrustedCheckpoint(b.expectedRoot, *slot); err != nil {
+			return err
+		}
 		b.slotToDownload.Store(*slot - 1)
 		if err := beacon_indicies.MarkRootCanonical(b.ctx, tx, *slot, b.expectedRoot); err != nil {
 			return err
@@ -41,6 +41,10 @@ func BlobsIdentifiersFromBlocks(blocks []*cltypes.SignedBeaconBlock, cfg *clpara
 		if block.Version() < clparams.DenebVersion {
 			continue
 		}
+		// from Fulu onwards blobs are only available as data columns
+		if block.Block.Slot/cfg.SlotsPerEpoch >= cfg.FuluForkEpoch {
+			continue
+		}
 		blockRoot, err := block.Block.HashSSZ()
 		if err != nil {
 			return nil, err
@@ -65,6 +69,10 @@ func BlobsIdentifiersFromBlindedBlocks(blocks []*cltypes.SignedBlindedBeaconBloc
 		if block.Version() < clparams.DenebVersion {
 			continue
 		}
+		// from Fulu onwards blobs are only available as data columns
+		if block.Block.Slot/cfg.SlotsPerEpoch >= cfg.FuluForkEpoch {
+			continue
+		}
 		blockRoot, err := block.Block.HashSSZ()
 		if err != nil {
 			return nil, err
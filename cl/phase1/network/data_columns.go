@@ -0,0 +1,155 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package network
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/persistence/blob_storage"
+	"github.com/erigontech/erigon/cl/rpc"
+)
+
+// DataColumnsIdentifiersFromBlocks returns the identifiers of the given columns of the Fulu blocks which are not stored yet.
+func DataColumnsIdentifiersFromBlocks(blocks []*cltypes.SignedBeaconBlock, cfg *clparams.BeaconChainConfig, storage blob_storage.BlobStorage, columns []uint64) (*solid.ListSSZ[*cltypes.DataColumnsByRootIdentifier], error) {
+	ids := solid.NewDynamicListSSZ[*cltypes.DataColumnsByRootIdentifier](int(cfg.MaxRequestBlocksDeneb))
+	requested := 0
+	for _, block := range blocks {
+		blockRoot, err := block.Block.HashSSZ()
+		if err != nil {
+			return nil, err
+		}
+		full, err := appendDataColumnsIdentifier(ids, &requested, cfg, storage, block.Block.Slot, blockRoot, block.Block.Body.BlobKzgCommitments.Len(), columns)
+		if err != nil {
+			return nil, err
+		}
+		if full {
+			break
+		}
+	}
+	return ids, nil
+}
+
+// DataColumnsIdentifiersFromBlindedBlocks is DataColumnsIdentifiersFromBlocks for blinded blocks.
+func DataColumnsIdentifiersFromBlindedBlocks(blocks []*cltypes.SignedBlindedBeaconBlock, cfg *clparams.BeaconChainConfig, storage blob_storage.BlobStorage, columns []uint64) (*solid.ListSSZ[*cltypes.DataColumnsByRootIdentifier], error) {
+	ids := solid.NewDynamicListSSZ[*cltypes.DataColumnsByRootIdentifier](int(cfg.MaxRequestBlocksDeneb))
+	requested := 0
+	for _, block := range blocks {
+		blockRoot, err := block.Block.HashSSZ()
+		if err != nil {
+			return nil, err
+		}
+		full, err := appendDataColumnsIdentifier(ids, &requested, cfg, storage, block.Block.Slot, blockRoot, block.Block.Body.BlobKzgCommitments.Len(), columns)
+		if err != nil {
+			return nil, err
+		}
+		if full {
+			break
+		}
+	}
+	return ids, nil
+}
+
+// appendDataColumnsIdentifier appends the identifier of the missing columns of a block, full is true once the
+// request cannot take them.
+func appendDataColumnsIdentifier(ids *solid.ListSSZ[*cltypes.DataColumnsByRootIdentifier], requested *int, cfg *clparams.BeaconChainConfig, storage blob_storage.BlobStorage, slot uint64, blockRoot common.Hash, kzgCommitments int, columns []uint64) (full bool, err error) {
+	if slot/cfg.SlotsPerEpoch < cfg.FuluForkEpoch || kzgCommitments == 0 {
+		return false, nil
+	}
+	id := cltypes.NewDataColumnsByRootIdentifier()
+	id.BlockRoot = blockRoot
+	for _, column := range columns {
+		has, err := storage.HasDataColumnSidecar(slot, blockRoot, column)
+		if err != nil {
+			return false, err
+		}
+		if !has {
+			id.Columns.Append(column)
+		}
+	}
+	if id.Columns.Length() == 0 {
+		return false, nil
+	}
+	if ids.Len() >= int(cfg.MaxRequestBlocksDeneb) || *requested+id.Columns.Length() > int(cfg.MaxRequestDataColumnSidecars) {
+		return true, nil
+	}
+	ids.Append(id)
+	*requested += id.Columns.Length()
+	return false, nil
+}
+
+type PeerAndColumnSidecars struct {
+	Peer      string
+	Responses []*cltypes.DataColumnSidecar
+}
+
+// RequestDataColumnsFrantically requests data columns from the network frantically, peers only serve the columns
+// they custody so the response may cover part of the request.
+func RequestDataColumnsFrantically(ctx context.Context, r *rpc.BeaconRpcP2P, req *solid.ListSSZ[*cltypes.DataColumnsByRootIdentifier]) (*PeerAndColumnSidecars, error) {
+	var atomicResp atomic.Value
+
+	atomicResp.Store(&PeerAndColumnSidecars{})
+	timer := time.NewTimer(requestBlobBatchExpiration)
+	defer timer.Stop()
+	reqInterval := time.NewTicker(100 * time.Millisecond)
+	defer reqInterval.Stop()
+Loop:
+	for {
+		select {
+		case <-reqInterval.C:
+			go func() {
+				if len(atomicResp.Load().(*PeerAndColumnSidecars).Responses) > 0 {
+					return
+				}
+				responses, pid, err := r.SendDataColumnSidecarsByRootReq(ctx, req)
+				if err != nil {
+					log.Trace("RequestDataColumnsFrantically: error", "err", err, "peer", pid)
+					return
+				}
+				if len(responses) == 0 {
+					log.Trace("RequestDataColumnsFrantically: response is empty", "peer", pid)
+					return
+				}
+				if len(atomicResp.Load().(*PeerAndColumnSidecars).Responses) > 0 {
+					return
+				}
+				atomicResp.Store(&PeerAndColumnSidecars{
+					Peer:      pid,
+					Responses: responses,
+				})
+			}()
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer.C:
+			log.Trace("RequestDataColumnsFrantically: timeout")
+			return nil, errors.New("timeout")
+		default:
+			if len(atomicResp.Load().(*PeerAndColumnSidecars).Responses) > 0 {
+				break Loop
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	return atomicResp.Load().(*PeerAndColumnSidecars), nil
+}
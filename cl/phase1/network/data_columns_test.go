@@ -0,0 +1,80 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package network
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/kv/memdb"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/persistence/blob_storage"
+)
+
+func TestDataColumnsIdentifiers(t *testing.T) {
+	cfg := clparams.MainnetBeaconConfig
+	cfg.FuluForkEpoch = 1
+	storage := blob_storage.NewBlobStore(memdb.NewTestDB(t, kv.ChainDB), afero.NewMemMapFs(), 12, &cfg, nil)
+	fuluSlot := cfg.SlotsPerEpoch
+	partialRoot, missingRoot := common.Hash{1}, common.Hash{2}
+
+	// the first custodied column of a block is already stored
+	stored := cltypes.NewDataColumnSidecar()
+	stored.Index = 3
+	stored.SignedBlockHeader.Header.Slot = fuluSlot + 1
+	require.NoError(t, storage.WriteDataColumnSidecars(context.Background(), partialRoot, []*cltypes.DataColumnSidecar{stored}))
+
+	columns := []uint64{3, 70}
+	ids := solid.NewDynamicListSSZ[*cltypes.DataColumnsByRootIdentifier](int(cfg.MaxRequestBlocksDeneb))
+	requested := 0
+	for _, block := range []struct {
+		slot        uint64
+		root        common.Hash
+		commitments int
+	}{
+		{fuluSlot - 1, common.Hash{3}, 2}, // before Fulu blobs are requested instead
+		{fuluSlot, common.Hash{4}, 0},     // no blobs
+		{fuluSlot + 1, partialRoot, 1},
+		{fuluSlot + 2, missingRoot, 1},
+	} {
+		full, err := appendDataColumnsIdentifier(ids, &requested, &cfg, storage, block.slot, block.root, block.commitments, columns)
+		require.NoError(t, err)
+		require.False(t, full)
+	}
+	require.Equal(t, 2, ids.Len())
+	require.Equal(t, 3, requested)
+
+	require.Equal(t, partialRoot, ids.Get(0).BlockRoot)
+	require.Equal(t, 1, ids.Get(0).Columns.Length())
+	require.Equal(t, uint64(70), ids.Get(0).Columns.Get(0))
+	require.Equal(t, missingRoot, ids.Get(1).BlockRoot)
+	require.Equal(t, 2, ids.Get(1).Columns.Length())
+
+	// the request is bounded by the number of sidecars which can be requested at once
+	cfg.MaxRequestDataColumnSidecars = 4
+	full, err := appendDataColumnsIdentifier(ids, &requested, &cfg, storage, fuluSlot+3, common.Hash{5}, 1, columns)
+	require.NoError(t, err)
+	require.True(t, full)
+	require.Equal(t, 2, ids.Len())
+}
@@ -58,6 +58,7 @@ type GossipManager struct {
 	// Services for processing messages from the network
 	blockService                 services.BlockService
 	blobService                  services.BlobSidecarsService
+	dataColumnService            services.DataColumnSidecarsService
 	syncCommitteeMessagesService services.SyncCommitteeMessagesService
 	syncContributionService      services.SyncContributionService
 	aggregateAndProofService     services.AggregateAndProofService
@@ -78,6 +79,7 @@ func NewGossipReceiver(
 	comitteeSub *committee_subscription.CommitteeSubscribeMgmt,
 	blockService services.BlockService,
 	blobService services.BlobSidecarsService,
+	dataColumnService services.DataColumnSidecarsService,
 	syncCommitteeMessagesService services.SyncCommitteeMessagesService,
 	syncContributionService services.SyncContributionService,
 	aggregateAndProofService services.AggregateAndProofService,
@@ -96,6 +98,7 @@ func NewGossipReceiver(
 		committeeSub:                 comitteeSub,
 		blockService:                 blockService,
 		blobService:                  blobService,
+		dataColumnService:            dataColumnService,
 		syncCommitteeMessagesService: syncCommitteeMessagesService,
 		syncContributionService:      syncContributionService,
 		aggregateAndProofService:     aggregateAndProofService,
@@ -238,6 +241,13 @@ func (g *GossipManager) routeAndProcess(ctx context.Context, data *sentinel.Goss
 			defer log.Debug("Received blob sidecar via gossip", "index", *data.SubnetId, "size", datasize.ByteSize(len(blobSideCar.Blob)))
 			// The background checks above are enough for now.
 			return g.blobService.ProcessMessage(ctx, data.SubnetId, blobSideCar)
+		case gossip.IsTopicDataColumnSidecar(data.Name):
+			columnSidecar := cltypes.NewDataColumnSidecar()
+			if err := columnSidecar.DecodeSSZ(data.Data, int(version)); err != nil {
+				return err
+			}
+			defer log.Debug("Received data column sidecar via gossip", "index", columnSidecar.Index, "slot", columnSidecar.SignedBlockHeader.Header.Slot)
+			return g.dataColumnService.ProcessMessage(ctx, data.SubnetId, columnSidecar)
 		case gossip.IsTopicSyncCommittee(data.Name):
 			obj := &services.SyncCommitteeMessageForGossip{
 				Receiver:             copyOfPeerData(data),
@@ -318,7 +328,7 @@ func (g *GossipManager) Start(ctx context.Context) {
 
 	sendOrDrop := func(ch chan<- *sentinel.GossipData, data *sentinel.GossipData) {
 		// Skip processing the received data if the node is not ready to process operations.
-		if !g.isReadyToProcessOperations() && data.Name != gossip.TopicNameBeaconBlock && !gossip.IsTopicBlobSidecar(data.Name) {
+		if !g.isReadyToProcessOperations() && data.Name != gossip.TopicNameBeaconBlock && !gossip.IsTopicBlobSidecar(data.Name) && !gossip.IsTopicDataColumnSidecar(data.Name) {
 			return
 		}
 		select {
@@ -355,7 +365,7 @@ Reconnect:
 			switch {
 			case data.Name == gossip.TopicNameBeaconBlock:
 				sendOrDrop(blocksCh, data)
-			case gossip.IsTopicBlobSidecar(data.Name):
+			case gossip.IsTopicBlobSidecar(data.Name) || gossip.IsTopicDataColumnSidecar(data.Name):
 				sendOrDrop(blobsCh, data)
 			case gossip.IsTopicSyncCommittee(data.Name) || data.Name == gossip.TopicNameSyncCommitteeContributionAndProof:
 				sendOrDrop(syncCommitteesCh, data)
@@ -158,12 +158,17 @@ func (b *blobSidecarService) verifyAndStoreBlobSidecar(msg *cltypes.BlobSidecar)
 }
 
 func (b *blobSidecarService) verifySidecarsSignature(header *cltypes.SignedBeaconBlockHeader) error {
-	parentHeader, ok := b.forkchoiceStore.GetHeader(header.Header.ParentRoot)
+	return verifySidecarHeaderSignature(b.beaconCfg, b.forkchoiceStore, b.syncedDataManager, header)
+}
+
+// verifySidecarHeaderSignature verifies the proposer signature of the block header carried by blob and column sidecars.
+func verifySidecarHeaderSignature(beaconCfg *clparams.BeaconChainConfig, forkchoiceStore forkchoice.ForkChoiceStorage, syncedDataManager *synced_data.SyncedDataManager, header *cltypes.SignedBeaconBlockHeader) error {
+	parentHeader, ok := forkchoiceStore.GetHeader(header.Header.ParentRoot)
 	if !ok {
 		return errors.New("parent header not found")
 	}
-	currentVersion := b.beaconCfg.GetCurrentStateVersion(parentHeader.Slot / b.beaconCfg.SlotsPerEpoch)
-	forkVersion := b.beaconCfg.GetForkVersionByVersion(currentVersion)
+	currentVersion := beaconCfg.GetCurrentStateVersion(parentHeader.Slot / beaconCfg.SlotsPerEpoch)
+	forkVersion := beaconCfg.GetForkVersionByVersion(currentVersion)
 
 	var (
 		domain []byte
@@ -171,8 +176,8 @@ func (b *blobSidecarService) verifySidecarsSignature(header *cltypes.SignedBeaco
 		err    error
 	)
 	// Load head state
-	if err := b.syncedDataManager.ViewHeadState(func(headState *state.CachingBeaconState) error {
-		domain, err = fork.ComputeDomain(b.beaconCfg.DomainBeaconProposer[:], utils.Uint32ToBytes4(forkVersion), headState.GenesisValidatorsRoot())
+	if err := syncedDataManager.ViewHeadState(func(headState *state.CachingBeaconState) error {
+		domain, err = fork.ComputeDomain(beaconCfg.DomainBeaconProposer[:], utils.Uint32ToBytes4(forkVersion), headState.GenesisValidatorsRoot())
 		if err != nil {
 			return err
 		}
@@ -195,7 +200,7 @@ func (b *blobSidecarService) verifySidecarsSignature(header *cltypes.SignedBeaco
 		return err
 	}
 	if !ok {
-		return errors.New("blob signature validation: signature not valid")
+		return errors.New("sidecar signature validation: signature not valid")
 	}
 	return nil
 }
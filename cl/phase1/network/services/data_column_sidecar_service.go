@@ -0,0 +1,175 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/erigontech/erigon/cl/beacon/synced_data"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/das"
+	"github.com/erigontech/erigon/cl/monitor"
+	"github.com/erigontech/erigon/cl/persistence/blob_storage"
+	"github.com/erigontech/erigon/cl/phase1/forkchoice"
+	"github.com/erigontech/erigon/cl/utils/eth_clock"
+)
+
+type dataColumnSidecarService struct {
+	forkchoiceStore   forkchoice.ForkChoiceStorage
+	beaconCfg         *clparams.BeaconChainConfig
+	syncedDataManager *synced_data.SyncedDataManager
+	ethClock          eth_clock.EthereumClock
+
+	seenMu           sync.Mutex
+	seenSidecars     map[uint64]map[seenDataColumnSidecar]struct{} // slot -> (proposer, index) of the sidecars already received
+	seenPrunedToSlot uint64                                        // finalized slot the seen sidecars were last pruned at
+	test             bool
+}
+
+type seenDataColumnSidecar struct {
+	proposerIndex, index uint64
+}
+
+// NewDataColumnSidecarService creates a new data column sidecar service
+func NewDataColumnSidecarService(
+	beaconCfg *clparams.BeaconChainConfig,
+	forkchoiceStore forkchoice.ForkChoiceStorage,
+	syncedDataManager *synced_data.SyncedDataManager,
+	ethClock eth_clock.EthereumClock,
+	test bool,
+) DataColumnSidecarsService {
+	return &dataColumnSidecarService{
+		beaconCfg:         beaconCfg,
+		forkchoiceStore:   forkchoiceStore,
+		syncedDataManager: syncedDataManager,
+		ethClock:          ethClock,
+		seenSidecars:      make(map[uint64]map[seenDataColumnSidecar]struct{}),
+		test:              test,
+	}
+}
+
+// ProcessMessage processes a data column sidecar message
+func (d *dataColumnSidecarService) ProcessMessage(ctx context.Context, subnetId *uint64, msg *cltypes.DataColumnSidecar) error {
+	if d.test {
+		return d.verifyAndStoreDataColumnSidecar(msg)
+	}
+	header := msg.SignedBlockHeader.Header
+	// [IGNORE] Columns are only gossiped from Fulu onwards.
+	if header.Slot/d.beaconCfg.SlotsPerEpoch < d.beaconCfg.FuluForkEpoch {
+		return ErrIgnore
+	}
+	// [REJECT] The sidecar's index is consistent with NUMBER_OF_COLUMNS -- i.e. data_column_sidecar.index < NUMBER_OF_COLUMNS.
+	if msg.Index >= d.beaconCfg.NumberOfColumns {
+		return errors.New("column index out of range")
+	}
+	// [REJECT] The sidecar is for the correct subnet -- i.e. compute_subnet_for_data_column_sidecar(data_column_sidecar.index) == subnet_id.
+	if subnetId == nil || das.ComputeSubnetForDataColumnSidecar(d.beaconCfg, msg.Index) != *subnetId {
+		return errors.New("column sidecar on the wrong subnet")
+	}
+	// [REJECT] The number of commitments is within the blob limit of the block.
+	if uint64(msg.KzgCommitments.Len()) > d.beaconCfg.MaxBlobsPerBlockByVersion(clparams.ElectraVersion) {
+		return ErrInvalidCommitmentsCount
+	}
+	// [IGNORE] The sidecar is not from a future slot (with a MAXIMUM_GOSSIP_CLOCK_DISPARITY allowance).
+	if d.ethClock.GetCurrentSlot() < header.Slot && !d.ethClock.IsSlotCurrentSlotWithMaximumClockDisparity(header.Slot) {
+		return ErrIgnore
+	}
+	// [IGNORE] The sidecar is from a slot greater than the latest finalized slot.
+	if d.forkchoiceStore.FinalizedSlot() >= header.Slot {
+		return ErrIgnore
+	}
+	// [IGNORE] The sidecar is the first sidecar for the tuple (block_header.slot, block_header.proposer_index, data_column_sidecar.index) with valid header signature, sidecar inclusion proof, and kzg proof.
+	seenKey := seenDataColumnSidecar{proposerIndex: header.ProposerIndex, index: msg.Index}
+	if d.isSeenSidecar(header.Slot, seenKey) {
+		return ErrIgnore
+	}
+
+	blockRoot, err := header.HashSSZ()
+	if err != nil {
+		return err
+	}
+	// Do not bother with blocks processed by fork choice already.
+	if _, has := d.forkchoiceStore.GetHeader(blockRoot); has {
+		return ErrIgnore
+	}
+	// [IGNORE] The sidecar's block's parent has been seen.
+	parentHeader, has := d.forkchoiceStore.GetHeader(header.ParentRoot)
+	if !has {
+		return ErrIgnore
+	}
+	// [REJECT] The sidecar is from a higher slot than the sidecar's block's parent.
+	if header.Slot <= parentHeader.Slot {
+		return ErrInvalidSidecarSlot
+	}
+
+	if err := d.verifyAndStoreDataColumnSidecar(msg); err != nil {
+		return err
+	}
+	d.markSeenSidecar(header.Slot, seenKey)
+	return nil
+}
+
+func (d *dataColumnSidecarService) verifyAndStoreDataColumnSidecar(msg *cltypes.DataColumnSidecar) error {
+	start := time.Now()
+	if d.test {
+		// the test sidecars carry no valid inclusion proof nor signature
+		if err := blob_storage.VerifyDataColumnSidecarKZGProofs(msg); err != nil {
+			return err
+		}
+	} else {
+		if err := blob_storage.VerifyDataColumnSidecar(d.beaconCfg, msg); err != nil {
+			return fmt.Errorf("invalid column sidecar: %w", err)
+		}
+		if err := verifySidecarHeaderSignature(d.beaconCfg, d.forkchoiceStore, d.syncedDataManager, msg.SignedBlockHeader); err != nil {
+			return err
+		}
+	}
+	monitor.ObserveBlobVerificationTime(start)
+	// operation is not thread safe from here.
+	return d.forkchoiceStore.AddPreverifiedDataColumnSidecar(msg)
+}
+
+func (d *dataColumnSidecarService) isSeenSidecar(slot uint64, key seenDataColumnSidecar) bool {
+	d.seenMu.Lock()
+	defer d.seenMu.Unlock()
+	_, seen := d.seenSidecars[slot][key]
+	return seen
+}
+
+// markSeenSidecar records a received sidecar. The sidecars of the finalized slots, which are ignored anyway, are
+// forgotten whenever the finalized slot moves.
+func (d *dataColumnSidecarService) markSeenSidecar(slot uint64, key seenDataColumnSidecar) {
+	d.seenMu.Lock()
+	defer d.seenMu.Unlock()
+	if finalizedSlot := d.forkchoiceStore.FinalizedSlot(); finalizedSlot > d.seenPrunedToSlot {
+		for seenSlot := range d.seenSidecars {
+			if seenSlot <= finalizedSlot {
+				delete(d.seenSidecars, seenSlot)
+			}
+		}
+		d.seenPrunedToSlot = finalizedSlot
+	}
+	if _, ok := d.seenSidecars[slot]; !ok {
+		d.seenSidecars[slot] = make(map[seenDataColumnSidecar]struct{})
+	}
+	d.seenSidecars[slot][key] = struct{}{}
+}
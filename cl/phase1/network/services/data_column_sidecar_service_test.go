@@ -0,0 +1,128 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/erigontech/erigon-lib/crypto/kzg"
+	"github.com/erigontech/erigon/cl/beacon/synced_data"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/phase1/forkchoice/mock_services"
+	"github.com/erigontech/erigon/cl/utils/eth_clock"
+)
+
+func getDataColumnSidecarForTests(t *testing.T, index uint64) *cltypes.DataColumnSidecar {
+	_, block, blobSidecar := getObjectsForBlobSidecarServiceTests(t)
+	cells, proofs, err := kzg.ComputeCellsAndProofs(blobSidecar.Blob[:])
+	require.NoError(t, err)
+
+	sidecar := cltypes.NewDataColumnSidecar()
+	sidecar.Index = index
+	sidecar.Column.Append((*cltypes.Cell)(cells[index]))
+	sidecar.KzgCommitments.Append(block.Block.Body.BlobKzgCommitments.Get(0))
+	sidecar.KzgProofs.Append((*cltypes.KZGProof)(&proofs[index]))
+	sidecar.SignedBlockHeader = block.SignedBeaconBlockHeader()
+	return sidecar
+}
+
+func setupDataColumnSidecarService(t *testing.T, ctrl *gomock.Controller, cfg *clparams.BeaconChainConfig, test bool) (DataColumnSidecarsService, *eth_clock.MockEthereumClock, *mock_services.ForkChoiceStorageMock) {
+	syncedDataManager := synced_data.NewSyncedDataManager(cfg, true)
+	ethClock := eth_clock.NewMockEthereumClock(ctrl)
+	forkchoiceMock := mock_services.NewForkChoiceStorageMock(t)
+	return NewDataColumnSidecarService(cfg, forkchoiceMock, syncedDataManager, ethClock, test), ethClock, forkchoiceMock
+}
+
+func TestDataColumnSidecarServiceKZG(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, _ := setupDataColumnSidecarService(t, ctrl, &clparams.MainnetBeaconConfig, true)
+	sidecar := getDataColumnSidecarForTests(t, 5)
+	subnet := uint64(5)
+	require.NoError(t, service.ProcessMessage(context.Background(), &subnet, sidecar))
+
+	// a cell which does not match its proof is rejected
+	sidecar.Column.Get(0)[0] ^= 1
+	require.Error(t, service.ProcessMessage(context.Background(), &subnet, sidecar))
+}
+
+func TestDataColumnSidecarServiceBeforeFulu(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, _ := setupDataColumnSidecarService(t, ctrl, &clparams.MainnetBeaconConfig, false)
+	subnet := uint64(5)
+	require.ErrorIs(t, service.ProcessMessage(context.Background(), &subnet, getDataColumnSidecarForTests(t, 5)), ErrIgnore)
+}
+
+func TestDataColumnSidecarServiceInvalidSidecars(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := clparams.MainnetBeaconConfig
+	cfg.FuluForkEpoch = 0
+	service, ethClock, fcu := setupDataColumnSidecarService(t, ctrl, &cfg, false)
+	ethClock.EXPECT().GetCurrentSlot().Return(uint64(0)).AnyTimes()
+	ethClock.EXPECT().IsSlotCurrentSlotWithMaximumClockDisparity(gomock.Any()).Return(true).AnyTimes()
+
+	sidecar := getDataColumnSidecarForTests(t, 5)
+	ctx := context.Background()
+
+	// wrong subnet
+	subnet := uint64(6)
+	require.Error(t, service.ProcessMessage(ctx, &subnet, sidecar))
+
+	// index out of range
+	subnet = 5
+	outOfRange := getDataColumnSidecarForTests(t, 5)
+	outOfRange.Index = cfg.NumberOfColumns + 5
+	require.Error(t, service.ProcessMessage(ctx, &subnet, outOfRange))
+
+	// unknown parent
+	require.ErrorIs(t, service.ProcessMessage(ctx, &subnet, sidecar), ErrIgnore)
+
+	// the sidecar is not from a higher slot than its parent
+	fcu.Headers[sidecar.SignedBlockHeader.Header.ParentRoot] = sidecar.SignedBlockHeader.Header.Copy()
+	require.ErrorIs(t, service.ProcessMessage(ctx, &subnet, sidecar), ErrInvalidSidecarSlot)
+}
+
+func TestDataColumnSidecarServiceSeenPruning(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _, forkchoiceMock := setupDataColumnSidecarService(t, ctrl, &clparams.MainnetBeaconConfig, false)
+	d := service.(*dataColumnSidecarService)
+	for slot := uint64(1); slot <= 4; slot++ {
+		d.markSeenSidecar(slot, seenDataColumnSidecar{proposerIndex: slot, index: 7})
+	}
+	require.Len(t, d.seenSidecars, 4)
+	require.True(t, d.isSeenSidecar(2, seenDataColumnSidecar{proposerIndex: 2, index: 7}))
+	require.False(t, d.isSeenSidecar(2, seenDataColumnSidecar{proposerIndex: 2, index: 8}))
+
+	// the slots up to the new finalized one are dropped on the next sidecar
+	forkchoiceMock.FinalizedSlotVal = 2
+	d.markSeenSidecar(5, seenDataColumnSidecar{proposerIndex: 5, index: 7})
+	require.Len(t, d.seenSidecars, 3)
+	require.False(t, d.isSeenSidecar(2, seenDataColumnSidecar{proposerIndex: 2, index: 7}))
+	require.True(t, d.isSeenSidecar(3, seenDataColumnSidecar{proposerIndex: 3, index: 7}))
+}
@@ -99,6 +99,18 @@ func fetchBlocksFromReqResp(ctx context.Context, cfg *Cfg, from uint64, count ui
 		}
 	}
 
+	// Loop until all the custodied columns are inserted into the blob store
+	for complete := false; !complete; {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+		if complete, err = downloadAndProcessDataColumns(ctx, cfg, blocks); err != nil {
+			return nil, fmt.Errorf("failed to download data columns: %w", err)
+		}
+	}
+
 	// Return the blocks and the peer ID wrapped in a PeeredObject
 	return &peers.PeeredObject[[]*cltypes.SignedBeaconBlock]{
 		Data: blocks,
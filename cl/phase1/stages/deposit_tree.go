@@ -0,0 +1,84 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package stages
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon/cl/cltypes"
+)
+
+// updateDepositTree feeds the deposit tracker with the blocks finalized since its last update, and finalizes the
+// deposit tree at the finalized state.
+func updateDepositTree(ctx context.Context, tx kv.Tx, logger log.Logger, cfg *Cfg) error {
+	if cfg.depositTracker == nil || cfg.executionClient == nil {
+		return nil
+	}
+	trackerSlot, ok := cfg.depositTracker.Slot()
+	finalized := cfg.forkChoice.FinalizedCheckpoint()
+	if !ok || cfg.forkChoice.FinalizedSlot() <= trackerSlot {
+		return nil
+	}
+
+	// walk back the finalized chain, down to the last block of the tracker
+	var blocks []*cltypes.SignedBeaconBlock
+	for root := finalized.Root; ; {
+		block, err := cfg.blockReader.ReadBlockByRoot(ctx, tx, root)
+		if err != nil {
+			return err
+		}
+		if block == nil {
+			// the history of the chain is not downloaded yet, try again on the next finalization
+			logger.Debug("[Caplin] Deposit tree update postponed, missing block", "root", root)
+			return nil
+		}
+		if block.Block.Slot <= trackerSlot {
+			break
+		}
+		blocks = append(blocks, block)
+		root = block.Block.ParentRoot
+	}
+	if len(blocks) == 0 {
+		return nil
+	}
+	slices.Reverse(blocks)
+
+	finalizedState, err := cfg.forkChoice.GetStateAtBlockRoot(finalized.Root, false)
+	if err != nil {
+		return err
+	}
+	if finalizedState == nil {
+		return nil
+	}
+	eth1Data := finalizedState.Eth1Data()
+	executionBlockHeight, err := cfg.executionClient.HeaderNumber(ctx, eth1Data.BlockHash)
+	if err != nil {
+		return err
+	}
+	if executionBlockHeight == nil {
+		logger.Debug("[Caplin] Deposit tree update postponed, unknown execution block", "hash", eth1Data.BlockHash)
+		return nil
+	}
+	if err := cfg.depositTracker.OnFinalized(blocks, eth1Data, finalizedState.Eth1DepositIndex(), *executionBlockHeight); err != nil {
+		return fmt.Errorf("failed to update deposit tree: %w", err)
+	}
+	return nil
+}
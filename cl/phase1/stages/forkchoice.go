@@ -366,6 +366,14 @@ func doForkchoiceRoutine(ctx context.Context, logger log.Logger, cfg *Cfg, args
 		return fmt.Errorf("failed to post forkchoice operations: %w", err)
 	}
 
+	if err := updateDepositTree(ctx, tx, logger, cfg); err != nil {
+		logger.Warn("Could not update deposit tree", "err", err)
+	}
+
+	if err := persistLightClientUpdates(tx, cfg, headSlot); err != nil {
+		return fmt.Errorf("failed to persist light client updates: %w", err)
+	}
+
 	var m runtime.MemStats
 	dbg.ReadMemStats(&m)
 	logger.Debug("Imported chain segment",
@@ -101,6 +101,34 @@ func downloadAndProcessEip4844DA(ctx context.Context, logger log.Logger, cfg *Cf
 	return highestProcessed - 1, err
 }
 
+// downloadAndProcessDataColumns downloads the missing custodied columns of the Fulu blocks and stores them.
+// Peers only serve the columns they custody, so it tells whether all the columns are now available.
+func downloadAndProcessDataColumns(ctx context.Context, cfg *Cfg, blocks []*cltypes.SignedBeaconBlock) (complete bool, err error) {
+	custodyColumns := cfg.forkChoice.CustodyColumns()
+	ids, err := network2.DataColumnsIdentifiersFromBlocks(blocks, cfg.beaconCfg, cfg.blobStore, custodyColumns)
+	if err != nil {
+		return false, fmt.Errorf("failed to get data column identifiers: %w", err)
+	}
+	if ids.Len() == 0 {
+		return true, nil
+	}
+
+	columns, err := network2.RequestDataColumnsFrantically(ctx, cfg.rpc, ids)
+	if err != nil {
+		return false, fmt.Errorf("failed to get data columns: %w", err)
+	}
+	if _, err := blob_storage.VerifyAgainstIdentifiersAndInsertDataColumns(ctx, cfg.blobStore, cfg.beaconCfg, ids, columns.Responses, nil); err != nil {
+		cfg.rpc.BanPeer(columns.Peer)
+		return false, fmt.Errorf("failed to verify data columns: %w", err)
+	}
+
+	// check whether the request was fully answered, the identifiers only cover what is still missing
+	if ids, err = network2.DataColumnsIdentifiersFromBlocks(blocks, cfg.beaconCfg, cfg.blobStore, custodyColumns); err != nil {
+		return false, fmt.Errorf("failed to get data column identifiers: %w", err)
+	}
+	return ids.Len() == 0, nil
+}
+
 // processDownloadedBlockBatches processes a batch of downloaded blocks.
 // It takes the highest block processed, a flag to determine if insertion is needed, and a list of signed beacon blocks as input.
 // It returns the new highest block processed and an error if any.
@@ -121,6 +149,12 @@ func processDownloadedBlockBatches(ctx context.Context, logger log.Logger, cfg *
 			logger.Trace("[Caplin] Failed to process blobs", "err", err)
 			return highestBlockProcessed, nil
 		}
+		// from Fulu onwards the data is available as columns, the batch is retried until all of them are stored
+		complete, err := downloadAndProcessDataColumns(ctx, cfg, blocks)
+		if err != nil || !complete {
+			logger.Trace("[Caplin] Failed to process data columns", "err", err, "complete", complete)
+			return highestBlockProcessed, nil
+		}
 	}
 	// Iterate over each block in the sorted list
 	for _, block := range blocks {
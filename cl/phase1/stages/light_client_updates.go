@@ -0,0 +1,55 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package stages
+
+import (
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes/lightclient_utils"
+	"github.com/erigontech/erigon/cl/persistence/beacon_indicies"
+)
+
+// persistLightClientUpdates stores the best light client updates seen by the fork choice for the periods around the
+// head, so that they are still served after a restart.
+func persistLightClientUpdates(tx kv.RwTx, cfg *Cfg, headSlot uint64) error {
+	if cfg.beaconCfg.GetCurrentStateVersion(headSlot/cfg.beaconCfg.SlotsPerEpoch) < clparams.AltairVersion {
+		return nil
+	}
+	headPeriod := cfg.beaconCfg.SyncCommitteePeriod(headSlot)
+	periods := []uint64{headPeriod}
+	if headPeriod > 0 {
+		// the update of the previous period may still have improved until its last slot
+		periods = append(periods, headPeriod-1)
+	}
+	for _, period := range periods {
+		update, ok := cfg.forkChoice.GetLightClientUpdate(period)
+		if !ok {
+			continue
+		}
+		stored, err := beacon_indicies.ReadLightClientUpdate(tx, period)
+		if err != nil {
+			return err
+		}
+		if stored != nil && !lightclient_utils.IsBetterUpdate(cfg.beaconCfg, update, stored) {
+			continue
+		}
+		if err := beacon_indicies.WriteLightClientUpdate(tx, period, update); err != nil {
+			return err
+		}
+	}
+	return nil
+}
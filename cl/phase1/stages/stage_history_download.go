@@ -30,6 +30,7 @@ import (
 	"github.com/erigontech/erigon/cl/antiquary"
 	"github.com/erigontech/erigon/cl/persistence/beacon_indicies"
 	"github.com/erigontech/erigon/cl/persistence/blob_storage"
+	"github.com/erigontech/erigon/cl/phase1/core/checkpoint_sync"
 	"github.com/erigontech/erigon/cl/phase1/execution_client"
 	"github.com/erigontech/erigon/cl/phase1/execution_client/block_collector"
 	"github.com/erigontech/erigon/cl/phase1/network"
@@ -56,11 +57,12 @@ type StageHistoryReconstructionCfg struct {
 	backfillingThrottling    time.Duration
 	blockReader              freezeblocks.BeaconSnapshotReader
 	blobStorage              blob_storage.BlobStorage
+	custodyColumns           []uint64 // columns backfilled for Fulu blocks
 }
 
 const logIntervalTime = 30 * time.Second
 
-func StageHistoryReconstruction(downloader *network.BackwardBeaconDownloader, antiquary *antiquary.Antiquary, sn *freezeblocks.CaplinSnapshots, indiciesDB kv.RwDB, engine execution_client.ExecutionEngine, beaconCfg *clparams.BeaconChainConfig, caplinConfig clparams.CaplinConfig, waitForAllRoutines bool, startingRoot common.Hash, startinSlot uint64, tmpdir string, backfillingThrottling time.Duration, executionBlocksCollector block_collector.BlockCollector, blockReader freezeblocks.BeaconSnapshotReader, blobStorage blob_storage.BlobStorage, logger log.Logger) StageHistoryReconstructionCfg {
+func StageHistoryReconstruction(downloader *network.BackwardBeaconDownloader, antiquary *antiquary.Antiquary, sn *freezeblocks.CaplinSnapshots, indiciesDB kv.RwDB, engine execution_client.ExecutionEngine, beaconCfg *clparams.BeaconChainConfig, caplinConfig clparams.CaplinConfig, waitForAllRoutines bool, startingRoot common.Hash, startinSlot uint64, tmpdir string, backfillingThrottling time.Duration, executionBlocksCollector block_collector.BlockCollector, blockReader freezeblocks.BeaconSnapshotReader, blobStorage blob_storage.BlobStorage, custodyColumns []uint64, logger log.Logger) StageHistoryReconstructionCfg {
 	return StageHistoryReconstructionCfg{
 		beaconCfg:                beaconCfg,
 		downloader:               downloader,
@@ -78,6 +80,7 @@ func StageHistoryReconstruction(downloader *network.BackwardBeaconDownloader, an
 		executionBlocksCollector: executionBlocksCollector,
 		blockReader:              blockReader,
 		blobStorage:              blobStorage,
+		custodyColumns:           custodyColumns,
 	}
 }
 
@@ -98,6 +101,14 @@ func SpawnStageHistoryDownload(cfg StageHistoryReconstructionCfg, ctx context.Co
 	// Setup slot and block root
 	cfg.downloader.SetSlotToDownload(currentSlot)
 	cfg.downloader.SetExpectedRoot(blockRoot)
+	if cfg.caplinConfig.CheckpointSyncFromFile() {
+		// the chain we backfill must go through the checkpoint we started from
+		checkpoint, err := checkpoint_sync.ReadBeaconBlockFile(cfg.beaconCfg, cfg.caplinConfig.CheckpointSyncBlockFile)
+		if err != nil {
+			return fmt.Errorf("could not read checkpoint block: %w", err)
+		}
+		cfg.downloader.SetTrustedCheckpoint(cfg.caplinConfig.CheckpointSyncBlockRoot, checkpoint.Block.Slot)
+	}
 
 	var initialBeaconBlock *cltypes.SignedBeaconBlock
 
@@ -277,9 +288,15 @@ func SpawnStageHistoryDownload(cfg StageHistoryReconstructionCfg, ctx context.Co
 		}
 	}()
 
+	backfillErrCh := make(chan error, 1)
 	go func() {
 		for !cfg.downloader.Finished() {
 			if err := cfg.downloader.RequestMore(ctx); err != nil {
+				if errors.Is(err, network.ErrTrustedCheckpointMismatch) {
+					logger.Error("Backfilling stopped", "err", err)
+					backfillErrCh <- err
+					return
+				}
 				log.Debug("closing backfilling routine", "err", err)
 				return
 			}
@@ -316,6 +333,8 @@ func SpawnStageHistoryDownload(cfg StageHistoryReconstructionCfg, ctx context.Co
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
+		case err := <-backfillErrCh:
+			return err
 		case <-time.After(5 * time.Second):
 		}
 	}
@@ -378,12 +397,11 @@ func downloadBlobHistoryWorker(cfg StageHistoryReconstructionCfg, ctx context.Co
 			if err != nil {
 				return err
 			}
-			blobsCount, err := cfg.blobStorage.KzgCommitmentsCount(ctx, blockRoot)
+			complete, err := hasBlockData(ctx, cfg, block, blockRoot)
 			if err != nil {
 				return err
 			}
-
-			if block.Block.Body.BlobKzgCommitments.Len() == int(blobsCount) {
+			if complete {
 				continue
 			}
 			batch = append(batch, block)
@@ -409,20 +427,8 @@ func downloadBlobHistoryWorker(cfg StageHistoryReconstructionCfg, ctx context.Co
 			logger.Info("[Blobs-Downloader] Downloading blobs backwards", "slot", currentSlot, "blks/sec", blkSecStr)
 		default:
 		}
-		// Generate the request
-		req, err := network.BlobsIdentifiersFromBlindedBlocks(batch, cfg.beaconCfg)
-		if err != nil {
-			cfg.logger.Debug("Error generating blob identifiers", "err", err)
-			continue
-		}
-		// Request the blobs
-		blobs, err := network.RequestBlobsFrantically(ctx, rpc, req)
-		if err != nil {
-			cfg.logger.Debug("Error requesting blobs", "err", err)
-			continue
-		}
-		_, _, err = blob_storage.VerifyAgainstIdentifiersAndInsertIntoTheBlobStore(ctx, cfg.blobStorage, req, blobs.Responses, func(header *cltypes.SignedBeaconBlockHeader) error {
-			// The block is preverified so just check that the signature is correct against the block
+		// The block is preverified so just check that the signature is correct against the block
+		verifySignatureFn := func(header *cltypes.SignedBeaconBlockHeader) error {
 			for _, block := range batch {
 				if block.Block.Slot != header.Header.Slot {
 					continue
@@ -433,12 +439,44 @@ func downloadBlobHistoryWorker(cfg StageHistoryReconstructionCfg, ctx context.Co
 				return nil
 			}
 			return errors.New("block not in batch")
-		})
+		}
+		// Generate the request
+		req, err := network.BlobsIdentifiersFromBlindedBlocks(batch, cfg.beaconCfg)
 		if err != nil {
-			rpc.BanPeer(blobs.Peer)
-			cfg.logger.Warn("Error verifying blobs", "err", err)
+			cfg.logger.Debug("Error generating blob identifiers", "err", err)
 			continue
 		}
+		if req.Len() > 0 {
+			// Request the blobs
+			blobs, err := network.RequestBlobsFrantically(ctx, rpc, req)
+			if err != nil {
+				cfg.logger.Debug("Error requesting blobs", "err", err)
+				continue
+			}
+			if _, _, err = blob_storage.VerifyAgainstIdentifiersAndInsertIntoTheBlobStore(ctx, cfg.blobStorage, req, blobs.Responses, verifySignatureFn); err != nil {
+				rpc.BanPeer(blobs.Peer)
+				cfg.logger.Warn("Error verifying blobs", "err", err)
+				continue
+			}
+		}
+		// from Fulu onwards the blobs are backfilled as the custodied columns
+		columnsReq, err := network.DataColumnsIdentifiersFromBlindedBlocks(batch, cfg.beaconCfg, cfg.blobStorage, cfg.custodyColumns)
+		if err != nil {
+			cfg.logger.Debug("Error generating data column identifiers", "err", err)
+			continue
+		}
+		if columnsReq.Len() > 0 {
+			columns, err := network.RequestDataColumnsFrantically(ctx, rpc, columnsReq)
+			if err != nil {
+				cfg.logger.Debug("Error requesting data columns", "err", err)
+				continue
+			}
+			if _, err = blob_storage.VerifyAgainstIdentifiersAndInsertDataColumns(ctx, cfg.blobStorage, cfg.beaconCfg, columnsReq, columns.Responses, verifySignatureFn); err != nil {
+				rpc.BanPeer(columns.Peer)
+				cfg.logger.Warn("Error verifying data columns", "err", err)
+				continue
+			}
+		}
 	}
 	if shouldLog {
 		logger.Info("[Blobs-Downloader] Blob history download finished successfully")
@@ -446,3 +484,24 @@ func downloadBlobHistoryWorker(cfg StageHistoryReconstructionCfg, ctx context.Co
 	cfg.antiquary.NotifyBlobBackfilled()
 	return nil
 }
+
+// hasBlockData tells whether the blobs of the block, or its custodied columns from Fulu onwards, are stored.
+func hasBlockData(ctx context.Context, cfg StageHistoryReconstructionCfg, block *cltypes.SignedBlindedBeaconBlock, blockRoot common.Hash) (bool, error) {
+	if block.Block.Slot/cfg.beaconCfg.SlotsPerEpoch < cfg.beaconCfg.FuluForkEpoch {
+		blobsCount, err := cfg.blobStorage.KzgCommitmentsCount(ctx, blockRoot)
+		if err != nil {
+			return false, err
+		}
+		return block.Block.Body.BlobKzgCommitments.Len() == int(blobsCount), nil
+	}
+	if block.Block.Body.BlobKzgCommitments.Len() == 0 {
+		return true, nil
+	}
+	for _, column := range cfg.custodyColumns {
+		has, err := cfg.blobStorage.HasDataColumnSidecar(block.Block.Slot, blockRoot, column)
+		if err != nil || !has {
+			return false, err
+		}
+	}
+	return true, nil
+}
@@ -37,6 +37,7 @@ import (
 	"github.com/erigontech/erigon-lib/gointerfaces"
 	sentinel "github.com/erigontech/erigon-lib/gointerfaces/sentinelproto"
 	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon-lib/types/ssz"
 
 	"github.com/erigontech/erigon/cl/clparams"
 	"github.com/erigontech/erigon/cl/cltypes"
@@ -144,8 +145,13 @@ func (b *BeaconRpcP2P) sendBlocksRequest(ctx context.Context, topic string, reqD
 }
 
 func (b *BeaconRpcP2P) sendBlobsSidecar(ctx context.Context, topic string, reqData []byte, count uint64) ([]*cltypes.BlobSidecar, string, error) {
+	return sendSidecarsRequest(ctx, b, topic, reqData, count, func() *cltypes.BlobSidecar { return &cltypes.BlobSidecar{} })
+}
+
+// sendSidecarsRequest sends a request and decodes up to count fork-digest prefixed response chunks.
+func sendSidecarsRequest[T ssz.Unmarshaler](ctx context.Context, b *BeaconRpcP2P, topic string, reqData []byte, count uint64, newChunk func() T) ([]T, string, error) {
 	// Prepare output slice.
-	responsePacket := []*cltypes.BlobSidecar{}
+	responsePacket := []T{}
 
 	ctx, cn := context.WithTimeout(ctx, time.Second*2)
 	defer cn()
@@ -204,7 +210,7 @@ func (b *BeaconRpcP2P) sendBlobsSidecar(ctx context.Context, topic string, reqDa
 		if err != nil {
 			return nil, message.Peer.Pid, err
 		}
-		responseChunk := &cltypes.BlobSidecar{}
+		responseChunk := newChunk()
 
 		if err = responseChunk.DecodeSSZ(raw, int(version)); err != nil {
 			return nil, message.Peer.Pid, err
@@ -242,6 +248,39 @@ func (b *BeaconRpcP2P) SendBlobsSidecarByRangerReq(ctx context.Context, start, c
 	return b.sendBlobsSidecar(ctx, communication.BlobSidecarByRangeProtocolV1, data, count*b.beaconConfig.MaxBlobsPerBlock)
 }
 
+// SendDataColumnSidecarsByRootReq retrieves data column sidecars by block root and column index.
+func (b *BeaconRpcP2P) SendDataColumnSidecarsByRootReq(ctx context.Context, req *solid.ListSSZ[*cltypes.DataColumnsByRootIdentifier]) ([]*cltypes.DataColumnSidecar, string, error) {
+	var buffer buffer.Buffer
+	if err := ssz_snappy.EncodeAndWrite(&buffer, req); err != nil {
+		return nil, "", err
+	}
+	count := uint64(0)
+	req.Range(func(_ int, id *cltypes.DataColumnsByRootIdentifier, _ int) bool {
+		count += uint64(id.Columns.Length())
+		return true
+	})
+
+	data := common.CopyBytes(buffer.Bytes())
+	return sendSidecarsRequest(ctx, b, communication.DataColumnSidecarsByRootProtocolV1, data, min(count, b.beaconConfig.MaxRequestDataColumnSidecars), cltypes.NewDataColumnSidecar)
+}
+
+// SendDataColumnSidecarsByRangeReq retrieves the given columns of the blocks in [start, start+count).
+func (b *BeaconRpcP2P) SendDataColumnSidecarsByRangeReq(ctx context.Context, start, count uint64, columns []uint64) ([]*cltypes.DataColumnSidecar, string, error) {
+	req := cltypes.NewDataColumnSidecarsByRangeRequest()
+	req.StartSlot = start
+	req.Count = count
+	for _, column := range columns {
+		req.Columns.Append(column)
+	}
+	var buffer buffer.Buffer
+	if err := ssz_snappy.EncodeAndWrite(&buffer, req); err != nil {
+		return nil, "", err
+	}
+
+	data := common.CopyBytes(buffer.Bytes())
+	return sendSidecarsRequest(ctx, b, communication.DataColumnSidecarsByRangeProtocolV1, data, min(count*uint64(len(columns)), b.beaconConfig.MaxRequestDataColumnSidecars), cltypes.NewDataColumnSidecar)
+}
+
 // SendBeaconBlocksByRangeReq retrieves blocks range from beacon chain.
 func (b *BeaconRpcP2P) SendBeaconBlocksByRangeReq(ctx context.Context, start, count uint64) ([]*cltypes.SignedBeaconBlock, string, error) {
 	req := &cltypes.BeaconBlocksByRangeRequest{
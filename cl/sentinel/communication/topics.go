@@ -24,6 +24,7 @@ const EncodingProtocol = "/ssz_snappy"
 // request and response versions
 const Schema1 = "/1"
 const Schema2 = "/2"
+const Schema3 = "/3"
 
 // Request and Response topics
 const MetadataTopic = "/metadata"
@@ -34,6 +35,8 @@ const BeaconBlocksByRangeTopic = "/beacon_blocks_by_range"
 const BeaconBlocksByRootTopic = "/beacon_blocks_by_root"
 const BlobSidecarByRootTopic = "/blob_sidecars_by_root"
 const BlobSidecarByRangeTopic = "/blob_sidecars_by_range"
+const DataColumnSidecarsByRootTopic = "/data_column_sidecars_by_root"
+const DataColumnSidecarsByRangeTopic = "/data_column_sidecars_by_range"
 const LightClientOptimisticUpdateTopic = "/light_client_optimistic_update"
 const LightClientFinalityUpdateTopic = "/light_client_finality_update"
 const LightClientBootstrapTopic = "/light_client_bootstrap"
@@ -46,6 +49,7 @@ var (
 
 	MetadataProtocolV1 = ProtocolPrefix + MetadataTopic + Schema1 + EncodingProtocol
 	MetadataProtocolV2 = ProtocolPrefix + MetadataTopic + Schema2 + EncodingProtocol
+	MetadataProtocolV3 = ProtocolPrefix + MetadataTopic + Schema3 + EncodingProtocol
 
 	StatusProtocolV1 = ProtocolPrefix + StatusTopic + Schema1 + EncodingProtocol
 
@@ -59,6 +63,9 @@ var (
 
 	BlobSidecarByRangeProtocolV1 = ProtocolPrefix + BlobSidecarByRangeTopic + Schema1 + EncodingProtocol
 
+	DataColumnSidecarsByRootProtocolV1  = ProtocolPrefix + DataColumnSidecarsByRootTopic + Schema1 + EncodingProtocol
+	DataColumnSidecarsByRangeProtocolV1 = ProtocolPrefix + DataColumnSidecarsByRangeTopic + Schema1 + EncodingProtocol
+
 	LightClientOptimisticUpdateProtocolV1 = ProtocolPrefix + LightClientOptimisticUpdateTopic + Schema1 + EncodingProtocol
 	LightClientFinalityUpdateProtocolV1   = ProtocolPrefix + LightClientFinalityUpdateTopic + Schema1 + EncodingProtocol
 	LightClientBootstrapProtocolV1        = ProtocolPrefix + LightClientBootstrapTopic + Schema1 + EncodingProtocol
@@ -19,6 +19,7 @@ package sentinel
 import (
 	"context"
 	"errors"
+	"math"
 	"time"
 
 	"github.com/libp2p/go-libp2p/core/network"
@@ -28,6 +29,7 @@ import (
 
 	"github.com/erigontech/erigon-lib/log/v3"
 	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/das"
 	"github.com/erigontech/erigon/p2p/enode"
 	"github.com/erigontech/erigon/p2p/enr"
 	"golang.org/x/sync/semaphore"
@@ -173,6 +175,9 @@ func (s *Sentinel) setupENR(
 	node.Set(enr.WithEntry(s.cfg.NetworkConfig.Eth2key, forkId))
 	node.Set(enr.WithEntry(s.cfg.NetworkConfig.AttSubnetKey, bitfield.NewBitvector64().Bytes()))
 	node.Set(enr.WithEntry(s.cfg.NetworkConfig.SyncCommsSubnetKey, bitfield.Bitvector4{byte(0x00)}.Bytes()))
+	if s.cfg.BeaconConfig.FuluForkEpoch != math.MaxUint64 {
+		node.Set(enr.WithEntry(s.cfg.NetworkConfig.CustodyGroupCountKey, das.CustodyGroupCount(s.cfg.BeaconConfig, s.cfg.SubscribeAllTopics)))
+	}
 	return node, nil
 }
 
@@ -261,7 +261,7 @@ func (s *Sentinel) Unsubscribe(topic GossipTopic, opts ...pubsub.TopicOpt) (err
 
 func (s *Sentinel) topicScoreParams(topic string) *pubsub.TopicScoreParams {
 	switch {
-	case strings.Contains(topic, gossip.TopicNameBeaconBlock) || gossip.IsTopicBlobSidecar(topic):
+	case strings.Contains(topic, gossip.TopicNameBeaconBlock) || gossip.IsTopicBlobSidecar(topic) || gossip.IsTopicDataColumnSidecar(topic):
 		return s.defaultBlockTopicParams()
 	case strings.Contains(topic, gossip.TopicNameVoluntaryExit):
 		return s.defaultVoluntaryExitTopicParams()
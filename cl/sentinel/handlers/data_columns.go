@@ -0,0 +1,138 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package handlers
+
+import (
+	"io"
+
+	"github.com/libp2p/go-libp2p/core/network"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/persistence/beacon_indicies"
+	"github.com/erigontech/erigon/cl/sentinel/communication/ssz_snappy"
+	"github.com/erigontech/erigon/cl/utils"
+)
+
+// maxDataColumnSlotsPerRequest bounds the number of slots walked by a single range request, as blobs do.
+const maxDataColumnSlotsPerRequest = 32
+
+func (c *ConsensusHandlers) dataColumnSidecarsByRangeHandler(s network.Stream) error {
+	req := cltypes.NewDataColumnSidecarsByRangeRequest()
+	if err := ssz_snappy.DecodeAndReadNoForkDigest(s, req, clparams.ElectraVersion); err != nil {
+		return err
+	}
+
+	tx, err := c.indiciesDB.BeginRo(c.ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	written := uint64(0)
+	for slot := req.StartSlot; slot < req.StartSlot+min(req.Count, maxDataColumnSlotsPerRequest); slot++ {
+		if slot/c.beaconConfig.SlotsPerEpoch < c.beaconConfig.FuluForkEpoch {
+			continue
+		}
+		blockRoot, err := beacon_indicies.ReadCanonicalBlockRoot(tx, slot)
+		if err != nil {
+			return err
+		}
+		if blockRoot == (common.Hash{}) {
+			continue
+		}
+		var writeErr error
+		req.Columns.Range(func(_ int, column uint64, _ int) bool {
+			if written >= c.beaconConfig.MaxRequestDataColumnSidecars {
+				return false
+			}
+			var ok bool
+			if ok, writeErr = c.writeDataColumnSidecar(s, slot, blockRoot, column); ok {
+				written++
+			}
+			return writeErr == nil
+		})
+		if writeErr != nil {
+			return writeErr
+		}
+	}
+	return nil
+}
+
+func (c *ConsensusHandlers) dataColumnSidecarsByRootHandler(s network.Stream) error {
+	req := solid.NewDynamicListSSZ[*cltypes.DataColumnsByRootIdentifier](int(c.beaconConfig.MaxRequestBlocksDeneb))
+	if err := ssz_snappy.DecodeAndReadNoForkDigest(s, req, clparams.ElectraVersion); err != nil {
+		return err
+	}
+
+	tx, err := c.indiciesDB.BeginRo(c.ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	written := uint64(0)
+	for i := 0; i < req.Len() && written < c.beaconConfig.MaxRequestDataColumnSidecars; i++ {
+		id := req.Get(i)
+		slot, err := beacon_indicies.ReadBlockSlotByBlockRoot(tx, id.BlockRoot)
+		if err != nil {
+			return err
+		}
+		if slot == nil {
+			continue
+		}
+		var writeErr error
+		id.Columns.Range(func(_ int, column uint64, _ int) bool {
+			if written >= c.beaconConfig.MaxRequestDataColumnSidecars {
+				return false
+			}
+			var ok bool
+			if ok, writeErr = c.writeDataColumnSidecar(s, *slot, id.BlockRoot, column); ok {
+				written++
+			}
+			return writeErr == nil
+		})
+		if writeErr != nil {
+			return writeErr
+		}
+	}
+	return nil
+}
+
+// writeDataColumnSidecar writes the response chunk of the stored sidecar of the column, if it is custodied.
+func (c *ConsensusHandlers) writeDataColumnSidecar(w io.Writer, slot uint64, blockRoot common.Hash, column uint64) (bool, error) {
+	has, err := c.blobsStorage.HasDataColumnSidecar(slot, blockRoot, column)
+	if err != nil || !has {
+		return false, err
+	}
+	forkDigest, err := c.ethClock.ComputeForkDigestForVersion(utils.Uint32ToBytes4(uint32(c.beaconConfig.FuluForkVersion)))
+	if err != nil {
+		return false, err
+	}
+	if _, err := w.Write([]byte{SuccessfulResponsePrefix}); err != nil {
+		return false, err
+	}
+	if _, err := w.Write(forkDigest[:]); err != nil {
+		return false, err
+	}
+	if err := c.blobsStorage.WriteDataColumnStream(w, slot, blockRoot, column); err != nil {
+		return false, err
+	}
+	return true, nil
+}
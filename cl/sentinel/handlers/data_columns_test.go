@@ -0,0 +1,160 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/kv/memdb"
+	"github.com/erigontech/erigon/cl/antiquary/tests"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/persistence/blob_storage"
+	"github.com/erigontech/erigon/cl/phase1/forkchoice/mock_services"
+	"github.com/erigontech/erigon/cl/sentinel/communication"
+	"github.com/erigontech/erigon/cl/sentinel/communication/ssz_snappy"
+	"github.com/erigontech/erigon/cl/sentinel/peers"
+)
+
+func getTestDataColumnSidecars(blockHeader *cltypes.SignedBeaconBlockHeader, indices ...uint64) []*cltypes.DataColumnSidecar {
+	out := []*cltypes.DataColumnSidecar{}
+	for _, index := range indices {
+		sidecar := cltypes.NewDataColumnSidecar()
+		sidecar.Index = index
+		sidecar.Column.Append(&cltypes.Cell{byte(index)})
+		sidecar.KzgCommitments.Append(&cltypes.KZGCommitment{byte(index)})
+		sidecar.KzgProofs.Append(&cltypes.KZGProof{byte(index)})
+		sidecar.SignedBlockHeader = blockHeader
+		out = append(out, sidecar)
+	}
+	return out
+}
+
+func TestDataColumnSidecarsByRootHandler(t *testing.T) {
+	ctx := context.Background()
+
+	listenAddrHost := "/ip4/127.0.0.1/tcp/6127"
+	host, err := libp2p.New(libp2p.ListenAddrStrings(listenAddrHost))
+	require.NoError(t, err)
+
+	listenAddrHost1 := "/ip4/127.0.0.1/tcp/6352"
+	host1, err := libp2p.New(libp2p.ListenAddrStrings(listenAddrHost1))
+	require.NoError(t, err)
+
+	err = host.Connect(ctx, peer.AddrInfo{
+		ID:    host1.ID(),
+		Addrs: host1.Addrs(),
+	})
+	require.NoError(t, err)
+
+	peersPool := peers.NewPool()
+	blobDb := memdb.NewTestDB(t, kv.ChainDB)
+	_, indiciesDB := setupStore(t)
+	store := tests.NewMockBlockReader()
+
+	tx, _ := indiciesDB.BeginRw(ctx)
+	defer indiciesDB.Close()
+	defer tx.Rollback()
+
+	ethClock := getEthClock(t)
+	expBlocks := populateDatabaseWithBlocks(t, store, tx, 100, 1)
+	h := expBlocks[0].SignedBeaconBlockHeader()
+	sidecars := getTestDataColumnSidecars(h, 3, 7)
+	_, beaconCfg := clparams.GetConfigsByNetwork(1)
+	blobStorage := blob_storage.NewBlobStore(blobDb, afero.NewMemMapFs(), math.MaxUint64, beaconCfg, ethClock)
+	r, _ := h.Header.HashSSZ()
+	require.NoError(t, blobStorage.WriteDataColumnSidecars(ctx, r, sidecars))
+
+	tx.Commit()
+
+	c := NewConsensusHandlers(
+		ctx,
+		store,
+		indiciesDB,
+		host,
+		peersPool,
+		&clparams.NetworkConfig{},
+		nil,
+		beaconCfg,
+		ethClock,
+		nil, &mock_services.ForkChoiceStorageMock{}, blobStorage, true,
+	)
+	c.Start()
+
+	// column 5 is not custodied and is skipped
+	id := cltypes.NewDataColumnsByRootIdentifier()
+	id.BlockRoot = r
+	id.Columns.Append(3)
+	id.Columns.Append(5)
+	id.Columns.Append(7)
+	req := solid.NewDynamicListSSZ[*cltypes.DataColumnsByRootIdentifier](int(beaconCfg.MaxRequestBlocksDeneb))
+	req.Append(id)
+
+	var reqBuf bytes.Buffer
+	require.NoError(t, ssz_snappy.EncodeAndWrite(&reqBuf, req))
+
+	stream, err := host1.NewStream(ctx, host.ID(), protocol.ID(communication.DataColumnSidecarsByRootProtocolV1))
+	require.NoError(t, err)
+
+	_, err = stream.Write(common.CopyBytes(reqBuf.Bytes()))
+	require.NoError(t, err)
+
+	for i := 0; i < len(sidecars); i++ {
+		prefix := make([]byte, 1)
+		_, err = stream.Read(prefix)
+		require.NoError(t, err)
+		require.Equal(t, byte(0), prefix[0])
+
+		forkDigest := make([]byte, 4)
+		_, err = io.ReadFull(stream, forkDigest)
+		require.NoError(t, err)
+
+		encodedLn, _, err := ssz_snappy.ReadUvarint(stream)
+		require.NoError(t, err)
+
+		raw := make([]byte, encodedLn)
+		_, err = io.ReadFull(snappy.NewReader(stream), raw)
+		require.NoError(t, err)
+
+		sidecar := cltypes.NewDataColumnSidecar()
+		require.NoError(t, sidecar.DecodeSSZ(raw, int(clparams.ElectraVersion)))
+		expRoot, err := sidecars[i].HashSSZ()
+		require.NoError(t, err)
+		root, err := sidecar.HashSSZ()
+		require.NoError(t, err)
+		require.Equal(t, expRoot, root)
+	}
+
+	_, err = stream.Read(make([]byte, 1))
+	if err != io.EOF {
+		t.Fatal("Stream is not empty")
+	}
+}
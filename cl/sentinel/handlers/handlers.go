@@ -103,6 +103,7 @@ func NewConsensusHandlers(ctx context.Context, db freezeblocks.BeaconSnapshotRea
 		communication.StatusProtocolV1:                      c.statusHandler,
 		communication.MetadataProtocolV1:                    c.metadataV1Handler,
 		communication.MetadataProtocolV2:                    c.metadataV2Handler,
+		communication.MetadataProtocolV3:                    c.metadataV3Handler,
 		communication.LightClientOptimisticUpdateProtocolV1: c.optimisticLightClientUpdateHandler,
 		communication.LightClientFinalityUpdateProtocolV1:   c.finalityLightClientUpdateHandler,
 		communication.LightClientBootstrapProtocolV1:        c.lightClientBootstrapHandler,
@@ -114,6 +115,8 @@ func NewConsensusHandlers(ctx context.Context, db freezeblocks.BeaconSnapshotRea
 		hm[communication.BeaconBlocksByRootProtocolV2] = c.beaconBlocksByRootHandler
 		hm[communication.BlobSidecarByRangeProtocolV1] = c.blobsSidecarsByRangeHandlerDeneb
 		hm[communication.BlobSidecarByRootProtocolV1] = c.blobsSidecarsByIdsHandlerDeneb
+		hm[communication.DataColumnSidecarsByRangeProtocolV1] = c.dataColumnSidecarsByRangeHandler
+		hm[communication.DataColumnSidecarsByRootProtocolV1] = c.dataColumnSidecarsByRootHandler
 	}
 
 	c.handlers = map[protocol.ID]network.StreamHandler{}
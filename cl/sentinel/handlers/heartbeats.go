@@ -86,6 +86,32 @@ func (c *ConsensusHandlers) metadataV2Handler(s network.Stream) error {
 	}, SuccessfulResponsePrefix)
 }
 
+func (c *ConsensusHandlers) metadataV3Handler(s network.Stream) error {
+	subnetField := [8]byte{}
+	syncnetField := [1]byte{}
+	var custodyGroupCount uint64
+	attSubEnr := enr.WithEntry(c.netCfg.AttSubnetKey, &subnetField)
+	syncNetEnr := enr.WithEntry(c.netCfg.SyncCommsSubnetKey, &syncnetField)
+	custodyGroupCountEnr := enr.WithEntry(c.netCfg.CustodyGroupCountKey, &custodyGroupCount)
+	if err := c.me.Node().Load(attSubEnr); err != nil {
+		return err
+	}
+	if err := c.me.Node().Load(syncNetEnr); err != nil {
+		return err
+	}
+	// the custody group count is only advertised once Fulu is scheduled
+	if err := c.me.Node().Load(custodyGroupCountEnr); err != nil && !enr.IsNotFound(err) {
+		return err
+	}
+
+	return ssz_snappy.EncodeAndWrite(s, &cltypes.Metadata{
+		SeqNumber:         c.me.Seq(),
+		Attnets:           subnetField,
+		Syncnets:          &syncnetField,
+		CustodyGroupCount: &custodyGroupCount,
+	}, SuccessfulResponsePrefix)
+}
+
 // TODO: Actually respond with proper status
 func (c *ConsensusHandlers) statusHandler(s network.Stream) error {
 	return ssz_snappy.EncodeAndWrite(s, c.hs.Status(), SuccessfulResponsePrefix)
@@ -239,6 +239,68 @@ func TestMetadataV2(t *testing.T) {
 	require.Equal(t, &syncnetsTestVal, p.Syncnets)
 }
 
+func TestMetadataV3(t *testing.T) {
+	ctx := context.Background()
+
+	listenAddrHost := "/ip4/127.0.0.1/tcp/7520"
+	host, err := libp2p.New(libp2p.ListenAddrStrings(listenAddrHost))
+	require.NoError(t, err)
+
+	listenAddrHost1 := "/ip4/127.0.0.1/tcp/7521"
+	host1, err := libp2p.New(libp2p.ListenAddrStrings(listenAddrHost1))
+	require.NoError(t, err)
+
+	err = host.Connect(ctx, peer.AddrInfo{
+		ID:    host1.ID(),
+		Addrs: host1.Addrs(),
+	})
+	require.NoError(t, err)
+
+	peersPool := peers.NewPool()
+	beaconDB, indiciesDB := setupStore(t)
+
+	f := mock_services.NewForkChoiceStorageMock(t)
+	ethClock := getEthClock(t)
+	nc := clparams.NetworkConfigs[networkid.MainnetChainID]
+	_, beaconCfg := clparams.GetConfigsByNetwork(1)
+	localNode := testLocalNode()
+	localNode.Set(enr.WithEntry(nc.CustodyGroupCountKey, uint64(8)))
+	c := NewConsensusHandlers(
+		ctx,
+		beaconDB,
+		indiciesDB,
+		host,
+		peersPool,
+		&nc,
+		localNode,
+		beaconCfg,
+		ethClock,
+		nil, f, nil, true,
+	)
+	c.Start()
+
+	stream, err := host1.NewStream(ctx, host.ID(), protocol.ID(communication.MetadataProtocolV3))
+	require.NoError(t, err)
+
+	_, err = stream.Write(nil)
+	require.NoError(t, err)
+
+	firstByte := make([]byte, 1)
+	_, err = stream.Read(firstByte)
+	require.NoError(t, err)
+	require.Equal(t, firstByte[0], byte(0))
+
+	p := &cltypes.Metadata{}
+
+	err = ssz_snappy.DecodeAndReadNoForkDigest(stream, p, clparams.Phase0Version)
+	require.NoError(t, err)
+
+	require.Equal(t, attnetsTestVal, p.Attnets)
+	require.Equal(t, &syncnetsTestVal, p.Syncnets)
+	require.NotNil(t, p.CustodyGroupCount)
+	require.Equal(t, uint64(8), *p.CustodyGroupCount)
+}
+
 func TestMetadataV1(t *testing.T) {
 	ctx := context.Background()
 
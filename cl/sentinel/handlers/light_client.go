@@ -19,6 +19,8 @@ package handlers
 import (
 	"github.com/erigontech/erigon/cl/clparams"
 	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/lightclient_utils"
+	"github.com/erigontech/erigon/cl/persistence/beacon_indicies"
 	"github.com/erigontech/erigon/cl/sentinel/communication/ssz_snappy"
 	"github.com/erigontech/erigon/cl/utils"
 	"github.com/libp2p/go-libp2p/core/network"
@@ -100,11 +102,22 @@ func (c *ConsensusHandlers) lightClientUpdatesByRangeHandler(s network.Stream) e
 		endPeriod = c.beaconConfig.SyncCommitteePeriod(currentSlot) + 1
 	}
 
+	tx, err := c.indiciesDB.BeginRo(c.ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	notFoundPrev := false
 	// Fetch from [start_period, start_period + count]
 	for i := req.StartPeriod; i < endPeriod; i++ {
-		update, has := c.forkChoiceReader.GetLightClientUpdate(i)
-		if !has {
+		stored, err := beacon_indicies.ReadLightClientUpdate(tx, i)
+		if err != nil {
+			return err
+		}
+		fromForkChoice, _ := c.forkChoiceReader.GetLightClientUpdate(i)
+		update := lightclient_utils.BestLightClientUpdate(c.beaconConfig, stored, fromForkChoice)
+		if update == nil {
 			notFoundPrev = true
 			continue
 		}
@@ -566,9 +566,18 @@ func (s *Sentinel) Identity() (pid, enrStr string, p2pAddresses, discoveryAddres
 		Attnets:   [8]byte(subnetField),
 		Syncnets:  (*[1]byte)(syncnetField),
 	}
+	var custodyGroupCount uint64
+	if err := s.listener.LocalNode().Node().Load(enr.WithEntry(s.cfg.NetworkConfig.CustodyGroupCountKey, &custodyGroupCount)); err == nil {
+		metadata.CustodyGroupCount = &custodyGroupCount
+	}
 	return
 }
 
+// NodeID is the discovery id of the node, from which its custody columns are derived.
+func (s *Sentinel) NodeID() enode.ID {
+	return s.listener.LocalNode().ID()
+}
+
 func (s *Sentinel) Host() host.Host {
 	return s.host
 }
@@ -123,6 +123,11 @@ func (s *SentinelServer) PublishGossip(_ context.Context, msg *sentinelrpc.Gossi
 				return nil, errors.New("subnetId is required for blob sidecar")
 			}
 			subscription = manager.GetMatchingSubscription(gossip.TopicNameBlobSidecar(*msg.SubnetId))
+		case gossip.IsTopicDataColumnSidecar(msg.Name):
+			if msg.SubnetId == nil {
+				return nil, errors.New("subnetId is required for data column sidecar")
+			}
+			subscription = manager.GetMatchingSubscription(gossip.TopicNameDataColumnSidecar(*msg.SubnetId))
 		case gossip.IsTopicSyncCommittee(msg.Name):
 			if msg.SubnetId == nil {
 				return nil, errors.New("subnetId is required for sync_committee")
@@ -416,6 +421,7 @@ func (s *SentinelServer) handleGossipPacket(pkt *sentinel.GossipMessage) error {
 	default:
 		// case for:
 		// TopicNamePrefixBlobSidecar
+		// TopicNamePrefixDataColumnSidecar
 		// TopicNamePrefixBeaconAttestation
 		// TopicNamePrefixSyncCommittee
 		subnet := extractSubnetIndexByGossipTopic(gossipTopic)
@@ -34,6 +34,7 @@ import (
 	"github.com/erigontech/erigon-lib/log/v3"
 
 	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/das"
 	"github.com/erigontech/erigon/cl/gossip"
 	"github.com/erigontech/erigon/cl/persistence/blob_storage"
 	"github.com/erigontech/erigon/cl/phase1/forkchoice"
@@ -123,6 +124,20 @@ func createSentinel(
 			int(cfg.BeaconConfig.MaxBlobsPerBlockElectra),
 		)...)
 
+	// Subscribe to the subnets of the custodied columns, once Fulu is scheduled.
+	if cfg.BeaconConfig.FuluForkEpoch != math.MaxUint64 {
+		subnets, err := das.CustodySubnets(cfg.BeaconConfig, sent.NodeID(), das.CustodyGroupCount(cfg.BeaconConfig, cfg.SubscribeAllTopics))
+		if err != nil {
+			return nil, err
+		}
+		for _, subnet := range subnets {
+			gossipTopics = append(gossipTopics, sentinel.GossipTopic{
+				Name:     gossip.TopicNameDataColumnSidecar(subnet),
+				CodecStr: sentinel.SSZSnappyCodec,
+			})
+		}
+	}
+
 	attestationSubnetTopics := generateSubnetsTopics(
 		gossip.TopicNamePrefixBeaconAttestation,
 		int(cfg.NetworkConfig.AttestationSubnetCount),
@@ -222,3 +222,16 @@ func ExtractSlotFromSerializedBeaconState(beaconState []byte) (uint64, error) {
 	}
 	return binary.LittleEndian.Uint64(beaconState[40:48]), nil
 }
+
+// ExtractSlotFromSerializedBeaconBlock reads the slot of a serialized signed beacon block, the first field of the
+// message whose offset opens the block.
+func ExtractSlotFromSerializedBeaconBlock(beaconBlock []byte) (uint64, error) {
+	if len(beaconBlock) < 4 {
+		return 0, errors.New("beacon block too short")
+	}
+	offset := uint64(binary.LittleEndian.Uint32(beaconBlock))
+	if uint64(len(beaconBlock)) < offset+8 {
+		return 0, errors.New("beacon block too short")
+	}
+	return binary.LittleEndian.Uint64(beaconBlock[offset : offset+8]), nil
+}
@@ -17,10 +17,12 @@
 package utils_test
 
 import (
+	"encoding/binary"
 	"testing"
 
 	"github.com/erigontech/erigon-lib/common"
 
+	"github.com/erigontech/erigon/cl/antiquary/tests"
 	"github.com/erigontech/erigon/cl/cltypes"
 	"github.com/erigontech/erigon/cl/utils"
 	"github.com/stretchr/testify/require"
@@ -52,3 +54,17 @@ func TestLiteralConverters(t *testing.T) {
 	require.Equal(t, [4]byte{10, 23, 56, 7}, utils.BytesToBytes4([]byte{10, 23, 56, 7, 8, 5}))
 	require.Equal(t, []byte{0x58, 0x2, 0x0, 0x0, 0x0, 0x0, 0x00, 0x00}, utils.Uint64ToLE(600))
 }
+
+func TestExtractSlotFromSerializedBeaconBlock(t *testing.T) {
+	blocks, _, _ := tests.GetElectraRandom()
+	encoded, err := blocks[0].EncodeSSZ(nil)
+	require.NoError(t, err)
+	slot, err := utils.ExtractSlotFromSerializedBeaconBlock(encoded)
+	require.NoError(t, err)
+	require.Equal(t, blocks[0].Block.Slot, slot)
+
+	// the message offset points past the end
+	binary.LittleEndian.PutUint32(encoded, uint32(len(encoded)))
+	_, err = utils.ExtractSlotFromSerializedBeaconBlock(encoded)
+	require.Error(t, err)
+}
@@ -0,0 +1,569 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package keymanager
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon/cl/beacon/beaconhttp"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/validator/validator_client"
+)
+
+// statuses of the operations on keys
+const (
+	statusImported  = "imported"
+	statusDuplicate = "duplicate"
+	statusDeleted   = "deleted"
+	statusNotActive = "not_active"
+	statusNotFound  = "not_found"
+	statusError     = "error"
+)
+
+var errReadonly = errors.New("validator is readonly, it was not added through the keymanager API")
+
+type keystoreInfo struct {
+	ValidatingPubkey common.Bytes48 `json:"validating_pubkey"`
+	DerivationPath   string         `json:"derivation_path,omitempty"`
+	Readonly         bool           `json:"readonly"`
+}
+
+type remoteKeyInfo struct {
+	Pubkey   common.Bytes48 `json:"pubkey"`
+	Url      string         `json:"url"`
+	Readonly bool           `json:"readonly"`
+}
+
+type operationStatus struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+func statusOf(err error) operationStatus {
+	return operationStatus{Status: statusError, Message: err.Error()}
+}
+
+type importKeystoresRequest struct {
+	Keystores          []string `json:"keystores"`
+	Passwords          []string `json:"passwords"`
+	SlashingProtection string   `json:"slashing_protection,omitempty"`
+}
+
+type importRemoteKeysRequest struct {
+	RemoteKeys []struct {
+		Pubkey common.Bytes48 `json:"pubkey"`
+		Url    string         `json:"url"`
+	} `json:"remote_keys"`
+}
+
+type deleteKeysRequest struct {
+	Pubkeys []common.Bytes48 `json:"pubkeys"`
+}
+
+/*
+KeymanagerAPI serves the standard keymanager API for the validators of the built-in validator client:
+  - local keystores can be listed, imported along with their slashing protection records, and deleted.
+  - remote keys can be listed, imported and deleted.
+  - the fee recipient, gas limit and graffiti can be set for each validator.
+
+Everything set through the API is persisted, and comes back on restart. The validators configured from the command
+line are readonly: they can not be deleted through the API, their settings can still be changed.
+
+ref: https://ethereum.github.io/keymanager-APIs/
+*/
+type KeymanagerAPI struct {
+	logger                log.Logger
+	beaconCfg             *clparams.BeaconChainConfig
+	genesisValidatorsRoot common.Hash
+	vc                    *validator_client.ValidatorClient
+	slashingProtection    *validator_client.SlashingProtection
+	store                 *validator_client.ValidatorStore
+	// defaultRemoteUrl is the remote signer of the remote keys imported without url
+	defaultRemoteUrl string
+
+	mu  sync.Mutex // serializes the changes to the set of validators
+	mux *chi.Mux
+}
+
+func NewKeymanagerAPI(
+	logger log.Logger,
+	beaconCfg *clparams.BeaconChainConfig,
+	genesisValidatorsRoot common.Hash,
+	vc *validator_client.ValidatorClient,
+	slashingProtection *validator_client.SlashingProtection,
+	store *validator_client.ValidatorStore,
+	defaultRemoteUrl string,
+) *KeymanagerAPI {
+	k := &KeymanagerAPI{
+		logger:                logger,
+		beaconCfg:             beaconCfg,
+		genesisValidatorsRoot: genesisValidatorsRoot,
+		vc:                    vc,
+		slashingProtection:    slashingProtection,
+		store:                 store,
+		defaultRemoteUrl:      defaultRemoteUrl,
+	}
+	k.init()
+	return k
+}
+
+func (k *KeymanagerAPI) init() {
+	r := chi.NewRouter()
+	r.Route("/eth/v1", func(r chi.Router) {
+		r.Get("/keystores", beaconhttp.HandleEndpointFunc(k.listKeystores))
+		r.Post("/keystores", beaconhttp.HandleEndpointFunc(k.importKeystores))
+		r.Delete("/keystores", beaconhttp.HandleEndpointFunc(k.deleteKeystores))
+		r.Get("/remotekeys", beaconhttp.HandleEndpointFunc(k.listRemoteKeys))
+		r.Post("/remotekeys", beaconhttp.HandleEndpointFunc(k.importRemoteKeys))
+		r.Delete("/remotekeys", beaconhttp.HandleEndpointFunc(k.deleteRemoteKeys))
+		r.Route("/validator/{pubkey}", func(r chi.Router) {
+			r.Get("/feerecipient", beaconhttp.HandleEndpointFunc(k.getFeeRecipient))
+			r.Post("/feerecipient", k.setFeeRecipient)
+			r.Delete("/feerecipient", k.deleteSetting(func(s *validator_client.ValidatorSettings) { s.FeeRecipient = nil }))
+			r.Get("/gas_limit", beaconhttp.HandleEndpointFunc(k.getGasLimit))
+			r.Post("/gas_limit", k.setGasLimit)
+			r.Delete("/gas_limit", k.deleteSetting(func(s *validator_client.ValidatorSettings) { s.GasLimit = nil }))
+			r.Get("/graffiti", beaconhttp.HandleEndpointFunc(k.getGraffiti))
+			r.Post("/graffiti", k.setGraffiti)
+			r.Delete("/graffiti", k.deleteSetting(func(s *validator_client.ValidatorSettings) { s.Graffiti = nil }))
+		})
+	})
+	k.mux = r
+}
+
+func (k *KeymanagerAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	k.mux.ServeHTTP(w, r)
+}
+
+// signers returns the local and the remote signers of the validator client, sorted by public key.
+func (k *KeymanagerAPI) signers() (local []validator_client.Signer, remote []validator_client.RemoteSigner) {
+	signers := k.vc.Signers()
+	slices.SortFunc(signers, func(a, b validator_client.Signer) int {
+		pa, pb := a.PublicKey(), b.PublicKey()
+		return bytes.Compare(pa[:], pb[:])
+	})
+	for _, signer := range signers {
+		if remoteSigner, ok := signer.(validator_client.RemoteSigner); ok {
+			remote = append(remote, remoteSigner)
+			continue
+		}
+		local = append(local, signer)
+	}
+	return local, remote
+}
+
+func (k *KeymanagerAPI) listKeystores(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	stored, err := k.store.Keystores()
+	if err != nil {
+		return nil, err
+	}
+	local, _ := k.signers()
+	out := make([]keystoreInfo, 0, len(local))
+	for _, signer := range local {
+		info := keystoreInfo{ValidatingPubkey: signer.PublicKey(), Readonly: true}
+		if keystore, ok := stored[signer.PublicKey()]; ok {
+			info.Readonly = false
+			info.DerivationPath = keystore.Path
+		}
+		out = append(out, info)
+	}
+	return beaconhttp.NewBeaconResponse(out), nil
+}
+
+func (k *KeymanagerAPI) importKeystores(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	var req importKeystoresRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err)
+	}
+	if len(req.Keystores) != len(req.Passwords) {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Errorf("got %d keystores and %d passwords", len(req.Keystores), len(req.Passwords)))
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	// the records must be in before the keys can sign anything
+	if req.SlashingProtection != "" {
+		if err := k.slashingProtection.ImportInterchange(r.Context(), strings.NewReader(req.SlashingProtection), k.genesisValidatorsRoot); err != nil {
+			return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err)
+		}
+	}
+	statuses := make([]operationStatus, 0, len(req.Keystores))
+	for i, keystoreJson := range req.Keystores {
+		statuses = append(statuses, k.importKeystore(keystoreJson, req.Passwords[i]))
+	}
+	return beaconhttp.NewBeaconResponse(statuses), nil
+}
+
+func (k *KeymanagerAPI) importKeystore(keystoreJson, password string) operationStatus {
+	keystore, err := validator_client.ParseKeystore([]byte(keystoreJson))
+	if err != nil {
+		return statusOf(err)
+	}
+	pubkey, err := keystore.PublicKey()
+	if err != nil {
+		return statusOf(err)
+	}
+	if _, ok := k.signerOf(pubkey); ok {
+		return operationStatus{Status: statusDuplicate}
+	}
+	key, err := keystore.Decrypt(password)
+	if err != nil {
+		return statusOf(err)
+	}
+	if err := k.store.PutKeystore(pubkey, []byte(keystoreJson), password); err != nil {
+		return statusOf(err)
+	}
+	k.vc.AddSigner(validator_client.NewLocalSigner(key))
+	k.logger.Info("[Keymanager] Imported keystore", "pubkey", pubkey)
+	return operationStatus{Status: statusImported}
+}
+
+func (k *KeymanagerAPI) deleteKeystores(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	var req deleteKeysRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err)
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	stored, err := k.store.Keystores()
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]operationStatus, 0, len(req.Pubkeys))
+	exported := make([]common.Bytes48, 0, len(req.Pubkeys))
+	for _, pubkey := range req.Pubkeys {
+		if _, ok := stored[pubkey]; ok {
+			// stop signing first, so that the exported records are the last ones
+			k.vc.RemoveSigner(pubkey)
+			if err := k.store.DeleteKeystore(pubkey); err != nil {
+				statuses = append(statuses, statusOf(err))
+				continue
+			}
+			delete(stored, pubkey)
+			k.logger.Info("[Keymanager] Deleted keystore", "pubkey", pubkey)
+			statuses = append(statuses, operationStatus{Status: statusDeleted})
+			exported = append(exported, pubkey)
+			continue
+		}
+		if signer, ok := k.signerOf(pubkey); ok {
+			if _, remote := signer.(validator_client.RemoteSigner); !remote {
+				statuses = append(statuses, statusOf(errReadonly))
+				continue
+			}
+		}
+		hasRecords, err := k.slashingProtection.HasRecords(r.Context(), pubkey)
+		if err != nil {
+			statuses = append(statuses, statusOf(err))
+			continue
+		}
+		if hasRecords {
+			statuses = append(statuses, operationStatus{Status: statusNotActive})
+			exported = append(exported, pubkey)
+			continue
+		}
+		statuses = append(statuses, operationStatus{Status: statusNotFound})
+	}
+	slashingProtection, err := k.exportInterchange(r.Context(), exported)
+	if err != nil {
+		return nil, err
+	}
+	return beaconhttp.NewBeaconResponse(statuses).With("slashing_protection", slashingProtection), nil
+}
+
+// exportInterchange returns the interchange file of pubkeys only, empty if there are none.
+func (k *KeymanagerAPI) exportInterchange(ctx context.Context, pubkeys []common.Bytes48) (string, error) {
+	if len(pubkeys) == 0 {
+		interchange, err := json.Marshal(validator_client.Interchange{
+			Metadata: validator_client.InterchangeMetadata{
+				InterchangeFormatVersion: validator_client.InterchangeFormatVersion,
+				GenesisValidatorsRoot:    k.genesisValidatorsRoot,
+			},
+			Data: []validator_client.InterchangeData{},
+		})
+		return string(interchange), err
+	}
+	var out bytes.Buffer
+	if err := k.slashingProtection.ExportInterchange(ctx, &out, k.genesisValidatorsRoot, pubkeys...); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+func (k *KeymanagerAPI) listRemoteKeys(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	stored, err := k.store.RemoteKeys(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	_, remote := k.signers()
+	out := make([]remoteKeyInfo, 0, len(remote))
+	for _, signer := range remote {
+		_, ok := stored[signer.PublicKey()]
+		out = append(out, remoteKeyInfo{Pubkey: signer.PublicKey(), Url: signer.URL(), Readonly: !ok})
+	}
+	return beaconhttp.NewBeaconResponse(out), nil
+}
+
+func (k *KeymanagerAPI) importRemoteKeys(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	var req importRemoteKeysRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err)
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	statuses := make([]operationStatus, 0, len(req.RemoteKeys))
+	for _, remoteKey := range req.RemoteKeys {
+		if _, ok := k.signerOf(remoteKey.Pubkey); ok {
+			statuses = append(statuses, operationStatus{Status: statusDuplicate})
+			continue
+		}
+		url := remoteKey.Url
+		if url == "" {
+			url = k.defaultRemoteUrl
+		}
+		if url == "" {
+			statuses = append(statuses, statusOf(errors.New("missing remote signer url")))
+			continue
+		}
+		signer, err := validator_client.NewWeb3Signer(url, k.beaconCfg, remoteKey.Pubkey)
+		if err != nil {
+			statuses = append(statuses, statusOf(err))
+			continue
+		}
+		if err := k.store.PutRemoteKey(r.Context(), remoteKey.Pubkey, url); err != nil {
+			statuses = append(statuses, statusOf(err))
+			continue
+		}
+		k.vc.AddSigner(signer)
+		k.logger.Info("[Keymanager] Imported remote key", "pubkey", remoteKey.Pubkey, "url", url)
+		statuses = append(statuses, operationStatus{Status: statusImported})
+	}
+	return beaconhttp.NewBeaconResponse(statuses), nil
+}
+
+func (k *KeymanagerAPI) deleteRemoteKeys(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	var req deleteKeysRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, beaconhttp.NewEndpointError(http.StatusBadRequest, err)
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	stored, err := k.store.RemoteKeys(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]operationStatus, 0, len(req.Pubkeys))
+	for _, pubkey := range req.Pubkeys {
+		if _, ok := stored[pubkey]; ok {
+			k.vc.RemoveSigner(pubkey)
+			if err := k.store.DeleteRemoteKey(r.Context(), pubkey); err != nil {
+				statuses = append(statuses, statusOf(err))
+				continue
+			}
+			delete(stored, pubkey)
+			k.logger.Info("[Keymanager] Deleted remote key", "pubkey", pubkey)
+			statuses = append(statuses, operationStatus{Status: statusDeleted})
+			continue
+		}
+		if signer, ok := k.signerOf(pubkey); ok {
+			if _, remote := signer.(validator_client.RemoteSigner); remote {
+				statuses = append(statuses, statusOf(errReadonly))
+				continue
+			}
+		}
+		statuses = append(statuses, operationStatus{Status: statusNotFound})
+	}
+	return beaconhttp.NewBeaconResponse(statuses), nil
+}
+
+func (k *KeymanagerAPI) signerOf(pubkey common.Bytes48) (validator_client.Signer, bool) {
+	for _, signer := range k.vc.Signers() {
+		if signer.PublicKey() == pubkey {
+			return signer, true
+		}
+	}
+	return nil, false
+}
+
+// validatorFromRequest returns the public key of the path, which must be one of the validators.
+func (k *KeymanagerAPI) validatorFromRequest(r *http.Request) (common.Bytes48, error) {
+	var pubkey common.Bytes48
+	if err := pubkey.UnmarshalText([]byte(chi.URLParam(r, "pubkey"))); err != nil {
+		return pubkey, beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Errorf("invalid pubkey: %w", err))
+	}
+	if _, ok := k.signerOf(pubkey); !ok {
+		return pubkey, beaconhttp.NewEndpointError(http.StatusNotFound, fmt.Errorf("validator %s not found", pubkey))
+	}
+	return pubkey, nil
+}
+
+// updateSettings persists the change of the settings of the validator, and applies it.
+func (k *KeymanagerAPI) updateSettings(ctx context.Context, pubkey common.Bytes48, update func(*validator_client.ValidatorSettings)) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	settings := k.vc.Settings(pubkey)
+	update(&settings)
+	if err := k.store.PutSettings(ctx, pubkey, settings); err != nil {
+		return err
+	}
+	k.vc.SetSettings(pubkey, settings)
+	return nil
+}
+
+// handleUpdate runs a settings update, answering with status on success.
+func handleUpdate(w http.ResponseWriter, status int, update func() error) {
+	if err := update(); err != nil {
+		var endpointError *beaconhttp.EndpointError
+		if !errors.As(err, &endpointError) {
+			endpointError = beaconhttp.WrapEndpointError(err)
+		}
+		endpointError.WriteTo(w)
+		return
+	}
+	w.WriteHeader(status)
+}
+
+func (k *KeymanagerAPI) deleteSetting(reset func(*validator_client.ValidatorSettings)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		handleUpdate(w, http.StatusNoContent, func() error {
+			pubkey, err := k.validatorFromRequest(r)
+			if err != nil {
+				return err
+			}
+			return k.updateSettings(r.Context(), pubkey, reset)
+		})
+	}
+}
+
+func (k *KeymanagerAPI) getFeeRecipient(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	pubkey, err := k.validatorFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	return beaconhttp.NewBeaconResponse(map[string]any{
+		"pubkey":     pubkey,
+		"ethaddress": k.vc.FeeRecipient(pubkey),
+	}), nil
+}
+
+func (k *KeymanagerAPI) setFeeRecipient(w http.ResponseWriter, r *http.Request) {
+	handleUpdate(w, http.StatusAccepted, func() error {
+		pubkey, err := k.validatorFromRequest(r)
+		if err != nil {
+			return err
+		}
+		var req struct {
+			EthAddress common.Address `json:"ethaddress"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return beaconhttp.NewEndpointError(http.StatusBadRequest, err)
+		}
+		return k.updateSettings(r.Context(), pubkey, func(s *validator_client.ValidatorSettings) { s.FeeRecipient = &req.EthAddress })
+	})
+}
+
+func (k *KeymanagerAPI) getGasLimit(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	pubkey, err := k.validatorFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	return beaconhttp.NewBeaconResponse(map[string]any{
+		"pubkey":    pubkey,
+		"gas_limit": strconv.FormatUint(k.vc.GasLimit(pubkey), 10),
+	}), nil
+}
+
+func (k *KeymanagerAPI) setGasLimit(w http.ResponseWriter, r *http.Request) {
+	handleUpdate(w, http.StatusAccepted, func() error {
+		pubkey, err := k.validatorFromRequest(r)
+		if err != nil {
+			return err
+		}
+		var req struct {
+			GasLimit uint64 `json:"gas_limit,string"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return beaconhttp.NewEndpointError(http.StatusBadRequest, err)
+		}
+		return k.updateSettings(r.Context(), pubkey, func(s *validator_client.ValidatorSettings) { s.GasLimit = &req.GasLimit })
+	})
+}
+
+func (k *KeymanagerAPI) getGraffiti(w http.ResponseWriter, r *http.Request) (*beaconhttp.BeaconResponse, error) {
+	pubkey, err := k.validatorFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	graffiti := k.vc.Graffiti(pubkey)
+	return beaconhttp.NewBeaconResponse(map[string]any{
+		"pubkey":   pubkey,
+		"graffiti": string(bytes.TrimRight(graffiti[:], "\x00")),
+	}), nil
+}
+
+func (k *KeymanagerAPI) setGraffiti(w http.ResponseWriter, r *http.Request) {
+	handleUpdate(w, http.StatusAccepted, func() error {
+		pubkey, err := k.validatorFromRequest(r)
+		if err != nil {
+			return err
+		}
+		var req struct {
+			Graffiti string `json:"graffiti"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return beaconhttp.NewEndpointError(http.StatusBadRequest, err)
+		}
+		if len(req.Graffiti) > len(common.Hash{}) {
+			return beaconhttp.NewEndpointError(http.StatusBadRequest, fmt.Errorf("graffiti is longer than %d bytes", len(common.Hash{})))
+		}
+		return k.updateSettings(r.Context(), pubkey, func(s *validator_client.ValidatorSettings) { s.Graffiti = &req.Graffiti })
+	})
+}
+
+// ObtainJWTSecret reads the secret the API clients sign their tokens with, it is generated on first use.
+func ObtainJWTSecret(path string) ([]byte, error) {
+	if data, err := os.ReadFile(path); err == nil {
+		jwtSecret := common.FromHex(strings.TrimSpace(string(data)))
+		if len(jwtSecret) != 32 {
+			return nil, fmt.Errorf("invalid JWT secret at %s, invalid size", path)
+		}
+		return jwtSecret, nil
+	}
+	jwtSecret := make([]byte, 32)
+	if _, err := rand.Read(jwtSecret); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, []byte(hexutil.Encode(jwtSecret)), 0600); err != nil {
+		return nil, err
+	}
+	log.Info("[Keymanager] Generated JWT secret", "path", path)
+	return jwtSecret, nil
+}
@@ -0,0 +1,274 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package keymanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/kv/memdb"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/utils/bls"
+	"github.com/erigontech/erigon/cl/validator/validator_client"
+)
+
+// test vector of EIP-2335
+const (
+	testKeystorePassword = "𝔱𝔢𝔰𝔱𝔭𝔞𝔰𝔰𝔴𝔬𝔯𝔡🔑"
+	testKeystorePubkey   = "0x9612d7a727c9d0a22e185a1c768478dfe919cada9266988cb32359c11f2b7b27f4ae4040902382ae2910c15e2b420d07"
+	testKeystore         = `{
+    "crypto": {
+        "kdf": {
+            "function": "pbkdf2",
+            "params": {
+                "dklen": 32,
+                "c": 262144,
+                "prf": "hmac-sha256",
+                "salt": "d4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3"
+            },
+            "message": ""
+        },
+        "checksum": {
+            "function": "sha256",
+            "params": {},
+            "message": "8a9f5d9912ed7e75ea794bc5a89bca5f193721d30868ade6f73043c6ea6febf1"
+        },
+        "cipher": {
+            "function": "aes-128-ctr",
+            "params": {
+                "iv": "264daa3f303d7259501c93d997d84fe6"
+            },
+            "message": "cee03fde2af33149775b7223e7845e4fb2c8ae1792e5f99fe9ecf474cc8c16ad"
+        }
+    },
+    "description": "This is a test keystore that uses PBKDF2 to secure the secret.",
+    "pubkey": "9612d7a727c9d0a22e185a1c768478dfe919cada9266988cb32359c11f2b7b27f4ae4040902382ae2910c15e2b420d07",
+    "path": "m/12381/60/0/0",
+    "uuid": "64625def-3331-4eea-ab6f-782f3ed16a83",
+    "version": 4
+}`
+)
+
+type testKeymanager struct {
+	t                  *testing.T
+	server             *httptest.Server
+	vc                 *validator_client.ValidatorClient
+	store              *validator_client.ValidatorStore
+	storeDir           string
+	slashingProtection *validator_client.SlashingProtection
+	readonly           common.Bytes48
+	gvr                common.Hash
+}
+
+func setupKeymanager(t *testing.T) *testKeymanager {
+	ctx := context.Background()
+	db := memdb.NewTestDB(t, kv.ValidatorDB)
+	key, err := bls.GenerateKey()
+	require.NoError(t, err)
+	readonly := validator_client.NewLocalSigner(key)
+
+	gvr := common.Hash{0xaa}
+	slashingProtection := validator_client.NewSlashingProtection(db)
+	storeDir := t.TempDir()
+	store := validator_client.NewValidatorStore(db, storeDir)
+	vc := validator_client.NewValidatorClient(ctx, log.New(), &clparams.MainnetBeaconConfig, nil, nil, slashingProtection,
+		[]validator_client.Signer{readonly}, common.Address{1}, common.Hash{}, false)
+	server := httptest.NewServer(NewKeymanagerAPI(log.New(), &clparams.MainnetBeaconConfig, gvr, vc, slashingProtection, store, ""))
+	t.Cleanup(server.Close)
+	return &testKeymanager{t: t, server: server, vc: vc, store: store, storeDir: storeDir, slashingProtection: slashingProtection, readonly: readonly.PublicKey(), gvr: gvr}
+}
+
+func (k *testKeymanager) do(method, path string, body any, out any) int {
+	var reqBody bytes.Buffer
+	if body != nil {
+		require.NoError(k.t, json.NewEncoder(&reqBody).Encode(body))
+	}
+	req, err := http.NewRequest(method, k.server.URL+path, &reqBody)
+	require.NoError(k.t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(k.t, err)
+	defer resp.Body.Close()
+	if out != nil && resp.StatusCode == http.StatusOK {
+		require.NoError(k.t, json.NewDecoder(resp.Body).Decode(out))
+	}
+	return resp.StatusCode
+}
+
+type statusesResponse struct {
+	Data               []operationStatus `json:"data"`
+	SlashingProtection string            `json:"slashing_protection"`
+}
+
+func statuses(resp statusesResponse) []string {
+	out := make([]string, 0, len(resp.Data))
+	for _, s := range resp.Data {
+		out = append(out, s.Status)
+	}
+	return out
+}
+
+func TestKeymanagerKeystores(t *testing.T) {
+	ctx := context.Background()
+	k := setupKeymanager(t)
+	var pubkey common.Bytes48
+	require.NoError(t, pubkey.UnmarshalText([]byte(testKeystorePubkey)))
+
+	var list struct {
+		Data []keystoreInfo `json:"data"`
+	}
+	require.Equal(t, http.StatusOK, k.do(http.MethodGet, "/eth/v1/keystores", nil, &list))
+	require.Equal(t, []keystoreInfo{{ValidatingPubkey: k.readonly, Readonly: true}}, list.Data)
+
+	interchange := `{"metadata": {"interchange_format_version": "5", "genesis_validators_root": "` + k.gvr.Hex() + `"},
+		"data": [{"pubkey": "` + testKeystorePubkey + `", "signed_blocks": [{"slot": "100"}], "signed_attestations": []}]}`
+	var imported statusesResponse
+	require.Equal(t, http.StatusOK, k.do(http.MethodPost, "/eth/v1/keystores", importKeystoresRequest{
+		Keystores:          []string{testKeystore, testKeystore, "{}"},
+		Passwords:          []string{testKeystorePassword, testKeystorePassword, ""},
+		SlashingProtection: interchange,
+	}, &imported))
+	require.Equal(t, []string{statusImported, statusDuplicate, statusError}, statuses(imported))
+	require.ErrorIs(t, k.slashingProtection.CheckAndRecordBlock(ctx, pubkey, 100, common.Hash{1}), validator_client.ErrSlashableBlock)
+
+	require.Equal(t, http.StatusOK, k.do(http.MethodGet, "/eth/v1/keystores", nil, &list))
+	require.Len(t, list.Data, 2)
+	require.Contains(t, list.Data, keystoreInfo{ValidatingPubkey: pubkey, DerivationPath: "m/12381/60/0/0"})
+
+	// the password is kept out of the database, readable by the owner only
+	passwordFile := filepath.Join(k.storeDir, "passwords", pubkey.Hex()+".txt")
+	info, err := os.Stat(passwordFile)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	password, err := os.ReadFile(passwordFile)
+	require.NoError(t, err)
+	require.Equal(t, testKeystorePassword, string(password))
+
+	// the imported keystore comes back on restart
+	signers, err := k.store.Signers(ctx, &clparams.MainnetBeaconConfig)
+	require.NoError(t, err)
+	require.Len(t, signers, 1)
+	require.Equal(t, pubkey, signers[0].PublicKey())
+
+	var deleted statusesResponse
+	require.Equal(t, http.StatusOK, k.do(http.MethodDelete, "/eth/v1/keystores", deleteKeysRequest{
+		Pubkeys: []common.Bytes48{pubkey, k.readonly, {1}, pubkey},
+	}, &deleted))
+	// once deleted, only the slashing protection records remain
+	require.Equal(t, []string{statusDeleted, statusError, statusNotFound, statusNotActive}, statuses(deleted))
+	var exported validator_client.Interchange
+	require.NoError(t, json.Unmarshal([]byte(deleted.SlashingProtection), &exported))
+	require.Equal(t, k.gvr, exported.Metadata.GenesisValidatorsRoot)
+	require.Len(t, exported.Data, 1)
+	require.Equal(t, pubkey, exported.Data[0].Pubkey)
+	require.Len(t, exported.Data[0].SignedBlocks, 1)
+
+	require.Len(t, k.vc.Signers(), 1)
+	signers, err = k.store.Signers(ctx, &clparams.MainnetBeaconConfig)
+	require.NoError(t, err)
+	require.Empty(t, signers)
+	require.NoFileExists(t, passwordFile)
+}
+
+func TestKeymanagerRemoteKeys(t *testing.T) {
+	k := setupKeymanager(t)
+	var imported statusesResponse
+	require.Equal(t, http.StatusOK, k.do(http.MethodPost, "/eth/v1/remotekeys", map[string]any{
+		"remote_keys": []map[string]any{
+			{"pubkey": common.Bytes48{1}, "url": "http://signer:9000"},
+			{"pubkey": common.Bytes48{2}},
+			{"pubkey": k.readonly, "url": "http://signer:9000"},
+		},
+	}, &imported))
+	// there is no default remote signer
+	require.Equal(t, []string{statusImported, statusError, statusDuplicate}, statuses(imported))
+
+	var list struct {
+		Data []remoteKeyInfo `json:"data"`
+	}
+	require.Equal(t, http.StatusOK, k.do(http.MethodGet, "/eth/v1/remotekeys", nil, &list))
+	require.Equal(t, []remoteKeyInfo{{Pubkey: common.Bytes48{1}, Url: "http://signer:9000"}}, list.Data)
+
+	var deleted statusesResponse
+	require.Equal(t, http.StatusOK, k.do(http.MethodDelete, "/eth/v1/remotekeys", deleteKeysRequest{
+		Pubkeys: []common.Bytes48{{1}, {2}},
+	}, &deleted))
+	require.Equal(t, []string{statusDeleted, statusNotFound}, statuses(deleted))
+	require.Equal(t, http.StatusOK, k.do(http.MethodGet, "/eth/v1/remotekeys", nil, &list))
+	require.Empty(t, list.Data)
+}
+
+func TestKeymanagerSettings(t *testing.T) {
+	ctx := context.Background()
+	k := setupKeymanager(t)
+	path := "/eth/v1/validator/" + k.readonly.Hex()
+
+	var feeRecipient struct {
+		Data struct {
+			Pubkey     common.Bytes48 `json:"pubkey"`
+			EthAddress common.Address `json:"ethaddress"`
+		} `json:"data"`
+	}
+	require.Equal(t, http.StatusOK, k.do(http.MethodGet, path+"/feerecipient", nil, &feeRecipient))
+	require.Equal(t, common.Address{1}, feeRecipient.Data.EthAddress)
+	require.Equal(t, http.StatusAccepted, k.do(http.MethodPost, path+"/feerecipient", map[string]any{"ethaddress": common.Address{2}}, nil))
+	require.Equal(t, http.StatusOK, k.do(http.MethodGet, path+"/feerecipient", nil, &feeRecipient))
+	require.Equal(t, common.Address{2}, feeRecipient.Data.EthAddress)
+
+	require.Equal(t, http.StatusAccepted, k.do(http.MethodPost, path+"/gas_limit", map[string]any{"gas_limit": "30000000"}, nil))
+	require.Equal(t, uint64(30_000_000), k.vc.GasLimit(k.readonly))
+	require.Equal(t, http.StatusAccepted, k.do(http.MethodPost, path+"/graffiti", map[string]any{"graffiti": "erigon"}, nil))
+	var graffiti struct {
+		Data struct {
+			Graffiti string `json:"graffiti"`
+		} `json:"data"`
+	}
+	require.Equal(t, http.StatusOK, k.do(http.MethodGet, path+"/graffiti", nil, &graffiti))
+	require.Equal(t, "erigon", graffiti.Data.Graffiti)
+	require.Equal(t, http.StatusBadRequest, k.do(http.MethodPost, path+"/graffiti", map[string]any{"graffiti": strings.Repeat("a", 33)}, nil))
+
+	// the settings come back on restart
+	settings, err := k.store.Settings(ctx)
+	require.NoError(t, err)
+	require.Equal(t, common.Address{2}, *settings[k.readonly].FeeRecipient)
+	require.Equal(t, uint64(30_000_000), *settings[k.readonly].GasLimit)
+	require.Equal(t, "erigon", *settings[k.readonly].Graffiti)
+
+	require.Equal(t, http.StatusNoContent, k.do(http.MethodDelete, path+"/feerecipient", nil, nil))
+	require.Equal(t, http.StatusNoContent, k.do(http.MethodDelete, path+"/gas_limit", nil, nil))
+	require.Equal(t, http.StatusNoContent, k.do(http.MethodDelete, path+"/graffiti", nil, nil))
+	require.Equal(t, common.Address{1}, k.vc.FeeRecipient(k.readonly))
+	require.Equal(t, uint64(validator_client.DefaultGasLimit), k.vc.GasLimit(k.readonly))
+	settings, err = k.store.Settings(ctx)
+	require.NoError(t, err)
+	require.Empty(t, settings)
+
+	// unknown validators
+	require.Equal(t, http.StatusNotFound, k.do(http.MethodGet, "/eth/v1/validator/"+common.Bytes48{1}.Hex()+"/feerecipient", nil, nil))
+	require.Equal(t, http.StatusBadRequest, k.do(http.MethodGet, "/eth/v1/validator/0x01/feerecipient", nil, nil))
+}
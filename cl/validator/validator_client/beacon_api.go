@@ -0,0 +1,315 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package validator_client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+)
+
+// beaconAPI calls the beacon API of the node in process: requests never leave memory, but they go through the very
+// same code paths as the ones of an external validator client.
+type beaconAPI struct {
+	handler http.Handler
+}
+
+// responseRecorder is the minimal http.ResponseWriter needed to collect the responses of the handler.
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newResponseRecorder() *responseRecorder {
+	return &responseRecorder{header: make(http.Header), status: http.StatusOK}
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+}
+
+// apiResponse is the envelope of the beacon API responses.
+type apiResponse[T any] struct {
+	Data                    T      `json:"data"`
+	Version                 string `json:"version"`
+	ExecutionPayloadBlinded bool   `json:"execution_payload_blinded"`
+}
+
+type validatorsRequest struct {
+	Ids      []string `json:"ids"`
+	Statuses []string `json:"statuses"`
+}
+
+type validatorData struct {
+	Index     uint64 `json:"index,string"`
+	Status    string `json:"status"`
+	Validator struct {
+		Pubkey common.Bytes48 `json:"pubkey"`
+	} `json:"validator"`
+}
+
+type proposerDuty struct {
+	Pubkey         common.Bytes48 `json:"pubkey"`
+	ValidatorIndex uint64         `json:"validator_index,string"`
+	Slot           uint64         `json:"slot,string"`
+}
+
+type attesterDuty struct {
+	Pubkey                  common.Bytes48 `json:"pubkey"`
+	ValidatorIndex          uint64         `json:"validator_index,string"`
+	CommitteeIndex          uint64         `json:"committee_index,string"`
+	CommitteeLength         uint64         `json:"committee_length,string"`
+	ValidatorCommitteeIndex uint64         `json:"validator_committee_index,string"`
+	CommitteesAtSlot        uint64         `json:"committees_at_slot,string"`
+	Slot                    uint64         `json:"slot,string"`
+}
+
+type syncDuty struct {
+	Pubkey                         common.Bytes48 `json:"pubkey"`
+	ValidatorIndex                 uint64         `json:"validator_index,string"`
+	ValidatorSyncCommitteeIndicies []string       `json:"validator_sync_committee_indices"`
+}
+
+type syncCommitteeSubscription struct {
+	ValidatorIndex        uint64   `json:"validator_index,string"`
+	SyncCommitteeIndicies []string `json:"sync_committee_indices"`
+	UntilEpoch            uint64   `json:"until_epoch,string"`
+}
+
+type proposerPreparation struct {
+	ValidatorIndex uint64         `json:"validator_index,string"`
+	FeeRecipient   common.Address `json:"fee_recipient"`
+}
+
+func (b *beaconAPI) do(ctx context.Context, method, path string, query url.Values, header http.Header, body any, out any) error {
+	var reqBody bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reqBody).Encode(body); err != nil {
+			return err
+		}
+	}
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, path, &reqBody)
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp := newResponseRecorder()
+	b.handler.ServeHTTP(resp, req)
+	if resp.status < 200 || resp.status >= 300 {
+		return fmt.Errorf("%s %s: status %d: %s", method, path, resp.status, bytes.TrimSpace(resp.body.Bytes()))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(resp.body.Bytes(), out)
+}
+
+func consensusVersionHeader(version clparams.StateVersion) http.Header {
+	return http.Header{"Eth-Consensus-Version": []string{version.String()}}
+}
+
+func (b *beaconAPI) validators(ctx context.Context, pubkeys []common.Bytes48) ([]validatorData, error) {
+	req := validatorsRequest{Ids: make([]string, 0, len(pubkeys)), Statuses: []string{}}
+	for _, pubkey := range pubkeys {
+		req.Ids = append(req.Ids, pubkey.Hex())
+	}
+	var resp apiResponse[[]validatorData]
+	if err := b.do(ctx, http.MethodPost, "/eth/v1/beacon/states/head/validators", nil, nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+func (b *beaconAPI) headBlockRoot(ctx context.Context) (common.Hash, error) {
+	var resp apiResponse[struct {
+		Root common.Hash `json:"root"`
+	}]
+	if err := b.do(ctx, http.MethodGet, "/eth/v1/beacon/blocks/head/root", nil, nil, nil, &resp); err != nil {
+		return common.Hash{}, err
+	}
+	return resp.Data.Root, nil
+}
+
+func indiciesBody(indicies []uint64) []string {
+	out := make([]string, 0, len(indicies))
+	for _, idx := range indicies {
+		out = append(out, strconv.FormatUint(idx, 10))
+	}
+	return out
+}
+
+func (b *beaconAPI) proposerDuties(ctx context.Context, epoch uint64) ([]proposerDuty, error) {
+	var resp apiResponse[[]proposerDuty]
+	if err := b.do(ctx, http.MethodGet, "/eth/v1/validator/duties/proposer/"+strconv.FormatUint(epoch, 10), nil, nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+func (b *beaconAPI) attesterDuties(ctx context.Context, epoch uint64, indicies []uint64) ([]attesterDuty, error) {
+	var resp apiResponse[[]attesterDuty]
+	if err := b.do(ctx, http.MethodPost, "/eth/v1/validator/duties/attester/"+strconv.FormatUint(epoch, 10), nil, nil, indiciesBody(indicies), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+func (b *beaconAPI) syncDuties(ctx context.Context, epoch uint64, indicies []uint64) ([]syncDuty, error) {
+	var resp apiResponse[[]syncDuty]
+	if err := b.do(ctx, http.MethodPost, "/eth/v1/validator/duties/sync/"+strconv.FormatUint(epoch, 10), nil, nil, indiciesBody(indicies), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+func (b *beaconAPI) attestationData(ctx context.Context, slot, committeeIndex uint64) (*solid.AttestationData, error) {
+	query := url.Values{
+		"slot":            []string{strconv.FormatUint(slot, 10)},
+		"committee_index": []string{strconv.FormatUint(committeeIndex, 10)},
+	}
+	var resp apiResponse[*solid.AttestationData]
+	if err := b.do(ctx, http.MethodGet, "/eth/v1/validator/attestation_data", query, nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+func (b *beaconAPI) submitAttestations(ctx context.Context, version clparams.StateVersion, attestations []*solid.Attestation) error {
+	return b.do(ctx, http.MethodPost, "/eth/v2/beacon/pool/attestations", nil, consensusVersionHeader(version), attestations, nil)
+}
+
+func (b *beaconAPI) submitSingleAttestations(ctx context.Context, version clparams.StateVersion, attestations []*solid.SingleAttestation) error {
+	return b.do(ctx, http.MethodPost, "/eth/v2/beacon/pool/attestations", nil, consensusVersionHeader(version), attestations, nil)
+}
+
+func (b *beaconAPI) aggregateAttestation(ctx context.Context, attestationDataRoot common.Hash, slot, committeeIndex uint64) (*solid.Attestation, error) {
+	query := url.Values{
+		"attestation_data_root": []string{attestationDataRoot.Hex()},
+		"slot":                  []string{strconv.FormatUint(slot, 10)},
+		"committee_index":       []string{strconv.FormatUint(committeeIndex, 10)},
+	}
+	var resp apiResponse[*solid.Attestation]
+	if err := b.do(ctx, http.MethodGet, "/eth/v2/validator/aggregate_attestation", query, nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+func (b *beaconAPI) submitAggregateAndProofs(ctx context.Context, aggregates []*cltypes.SignedAggregateAndProof) error {
+	return b.do(ctx, http.MethodPost, "/eth/v1/validator/aggregate_and_proofs", nil, nil, aggregates, nil)
+}
+
+// produceBlock returns either a full block, along with its blobs, or a blinded one when the builder won.
+func (b *beaconAPI) produceBlock(ctx context.Context, cfg *clparams.BeaconChainConfig, slot uint64, randaoReveal common.Bytes96, graffiti common.Hash) (*cltypes.DenebBeaconBlock, *cltypes.BlindedBeaconBlock, error) {
+	query := url.Values{
+		"randao_reveal": []string{randaoReveal.String()},
+		"graffiti":      []string{graffiti.Hex()},
+	}
+	var resp apiResponse[json.RawMessage]
+	if err := b.do(ctx, http.MethodGet, "/eth/v3/validator/blocks/"+strconv.FormatUint(slot, 10), query, nil, nil, &resp); err != nil {
+		return nil, nil, err
+	}
+	version, err := clparams.StringToClVersion(resp.Version)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.ExecutionPayloadBlinded {
+		block := cltypes.NewBlindedBeaconBlock(cfg, version)
+		if err := json.Unmarshal(resp.Data, block); err != nil {
+			return nil, nil, err
+		}
+		return nil, block, nil
+	}
+	block := cltypes.NewDenebBeaconBlock(cfg, version)
+	if err := json.Unmarshal(resp.Data, block); err != nil {
+		return nil, nil, err
+	}
+	return block, nil, nil
+}
+
+func (b *beaconAPI) publishBlock(ctx context.Context, block *cltypes.DenebSignedBeaconBlock) error {
+	return b.do(ctx, http.MethodPost, "/eth/v2/beacon/blocks", nil, consensusVersionHeader(block.SignedBlock.Version()), block, nil)
+}
+
+func (b *beaconAPI) publishBlindedBlock(ctx context.Context, block *cltypes.SignedBlindedBeaconBlock) error {
+	return b.do(ctx, http.MethodPost, "/eth/v1/beacon/blinded_blocks", nil, consensusVersionHeader(block.Version()), block, nil)
+}
+
+func (b *beaconAPI) subscribeBeaconCommittees(ctx context.Context, subscriptions []*cltypes.BeaconCommitteeSubscription) error {
+	return b.do(ctx, http.MethodPost, "/eth/v1/validator/beacon_committee_subscriptions", nil, nil, subscriptions, nil)
+}
+
+func (b *beaconAPI) subscribeSyncCommittees(ctx context.Context, subscriptions []syncCommitteeSubscription) error {
+	return b.do(ctx, http.MethodPost, "/eth/v1/validator/sync_committee_subscriptions", nil, nil, subscriptions, nil)
+}
+
+func (b *beaconAPI) prepareBeaconProposers(ctx context.Context, preparations []proposerPreparation) error {
+	return b.do(ctx, http.MethodPost, "/eth/v1/validator/prepare_beacon_proposer", nil, nil, preparations, nil)
+}
+
+func (b *beaconAPI) registerValidators(ctx context.Context, registrations []*cltypes.ValidatorRegistration) error {
+	return b.do(ctx, http.MethodPost, "/eth/v1/validator/register_validator", nil, nil, registrations, nil)
+}
+
+func (b *beaconAPI) submitSyncCommitteeMessages(ctx context.Context, messages []*cltypes.SyncCommitteeMessage) error {
+	return b.do(ctx, http.MethodPost, "/eth/v1/beacon/pool/sync_committees", nil, nil, messages, nil)
+}
+
+func (b *beaconAPI) syncCommitteeContribution(ctx context.Context, slot, subcommitteeIndex uint64, beaconBlockRoot common.Hash) (*cltypes.Contribution, error) {
+	query := url.Values{
+		"slot":               []string{strconv.FormatUint(slot, 10)},
+		"subcommittee_index": []string{strconv.FormatUint(subcommitteeIndex, 10)},
+		"beacon_block_root":  []string{beaconBlockRoot.Hex()},
+	}
+	var resp apiResponse[*cltypes.Contribution]
+	if err := b.do(ctx, http.MethodGet, "/eth/v1/validator/sync_committee_contribution", query, nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+func (b *beaconAPI) submitContributionAndProofs(ctx context.Context, contributions []*cltypes.SignedContributionAndProof) error {
+	return b.do(ctx, http.MethodPost, "/eth/v1/validator/contribution_and_proofs", nil, nil, contributions, nil)
+}
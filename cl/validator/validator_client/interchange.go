@@ -0,0 +1,196 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package validator_client
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/length"
+	"github.com/erigontech/erigon-lib/kv"
+)
+
+// InterchangeFormatVersion is the version of the EIP-3076 interchange format, the only one supported.
+const InterchangeFormatVersion = "5"
+
+// Interchange is the EIP-3076 slashing protection interchange format.
+type Interchange struct {
+	Metadata InterchangeMetadata `json:"metadata"`
+	Data     []InterchangeData   `json:"data"`
+}
+
+type InterchangeMetadata struct {
+	InterchangeFormatVersion string      `json:"interchange_format_version"`
+	GenesisValidatorsRoot    common.Hash `json:"genesis_validators_root"`
+}
+
+type InterchangeData struct {
+	Pubkey             common.Bytes48           `json:"pubkey"`
+	SignedBlocks       []InterchangeBlock       `json:"signed_blocks"`
+	SignedAttestations []InterchangeAttestation `json:"signed_attestations"`
+}
+
+type InterchangeBlock struct {
+	Slot        uint64       `json:"slot,string"`
+	SigningRoot *common.Hash `json:"signing_root,omitempty"`
+}
+
+type InterchangeAttestation struct {
+	SourceEpoch uint64       `json:"source_epoch,string"`
+	TargetEpoch uint64       `json:"target_epoch,string"`
+	SigningRoot *common.Hash `json:"signing_root,omitempty"`
+}
+
+func signingRootOrZero(root *common.Hash) common.Hash {
+	if root == nil {
+		return common.Hash{}
+	}
+	return *root
+}
+
+// ImportInterchange merges the records of an interchange file of the same network. Imported records only ever make
+// the protection stricter: a record already present for the same slot or target is kept.
+func (s *SlashingProtection) ImportInterchange(ctx context.Context, r io.Reader, genesisValidatorsRoot common.Hash) error {
+	var interchange Interchange
+	if err := json.NewDecoder(r).Decode(&interchange); err != nil {
+		return fmt.Errorf("could not decode interchange file: %w", err)
+	}
+	if interchange.Metadata.InterchangeFormatVersion != InterchangeFormatVersion {
+		return fmt.Errorf("unsupported interchange format version %s", interchange.Metadata.InterchangeFormatVersion)
+	}
+	if interchange.Metadata.GenesisValidatorsRoot != genesisValidatorsRoot {
+		return fmt.Errorf("interchange file is for genesis validators root %s, expected %s", interchange.Metadata.GenesisValidatorsRoot, genesisValidatorsRoot)
+	}
+	return s.db.Update(ctx, func(tx kv.RwTx) error {
+		for _, data := range interchange.Data {
+			for _, block := range data.SignedBlocks {
+				key := blockKey(data.Pubkey, block.Slot)
+				has, err := tx.Has(kv.SlashingProtectionBlocks, key)
+				if err != nil {
+					return err
+				}
+				if has {
+					continue
+				}
+				root := signingRootOrZero(block.SigningRoot)
+				if err := tx.Put(kv.SlashingProtectionBlocks, key, root[:]); err != nil {
+					return err
+				}
+			}
+			for _, attestation := range data.SignedAttestations {
+				if attestation.SourceEpoch > attestation.TargetEpoch {
+					return fmt.Errorf("invalid attestation of %s: source %d after target %d", data.Pubkey, attestation.SourceEpoch, attestation.TargetEpoch)
+				}
+				has, err := tx.Has(kv.SlashingProtectionAttestations, attestationKey(data.Pubkey, attestation.TargetEpoch))
+				if err != nil {
+					return err
+				}
+				if has {
+					continue
+				}
+				if err := putAttestation(tx, data.Pubkey, attestation.SourceEpoch, attestation.TargetEpoch, signingRootOrZero(attestation.SigningRoot)); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// ExportInterchange writes the records of pubkeys, or all the records if none is given, as an interchange file in the
+// complete format.
+func (s *SlashingProtection) ExportInterchange(ctx context.Context, w io.Writer, genesisValidatorsRoot common.Hash, pubkeys ...common.Bytes48) error {
+	interchange := Interchange{
+		Metadata: InterchangeMetadata{
+			InterchangeFormatVersion: InterchangeFormatVersion,
+			GenesisValidatorsRoot:    genesisValidatorsRoot,
+		},
+		Data: []InterchangeData{},
+	}
+	dataByPubkey := map[common.Bytes48]*InterchangeData{}
+	getData := func(pubkey common.Bytes48) *InterchangeData {
+		if data, ok := dataByPubkey[pubkey]; ok {
+			return data
+		}
+		data := &InterchangeData{Pubkey: pubkey, SignedBlocks: []InterchangeBlock{}, SignedAttestations: []InterchangeAttestation{}}
+		dataByPubkey[pubkey] = data
+		return data
+	}
+	var wanted map[common.Bytes48]struct{}
+	if len(pubkeys) > 0 {
+		wanted = make(map[common.Bytes48]struct{}, len(pubkeys))
+		for _, pubkey := range pubkeys {
+			wanted[pubkey] = struct{}{}
+		}
+	}
+	skip := func(pubkey common.Bytes48) bool {
+		_, ok := wanted[pubkey]
+		return wanted != nil && !ok
+	}
+	var exported []common.Bytes48
+	if err := s.db.View(ctx, func(tx kv.Tx) error {
+		if err := tx.ForEach(kv.SlashingProtectionBlocks, nil, func(k, v []byte) error {
+			pubkey := common.Bytes48(k[:length.Bytes48])
+			if skip(pubkey) {
+				return nil
+			}
+			if _, ok := dataByPubkey[pubkey]; !ok {
+				exported = append(exported, pubkey)
+			}
+			data := getData(pubkey)
+			block := InterchangeBlock{Slot: binary.BigEndian.Uint64(k[length.Bytes48:])}
+			if root := common.BytesToHash(v); root != (common.Hash{}) {
+				block.SigningRoot = &root
+			}
+			data.SignedBlocks = append(data.SignedBlocks, block)
+			return nil
+		}); err != nil {
+			return err
+		}
+		return tx.ForEach(kv.SlashingProtectionAttestations, nil, func(k, v []byte) error {
+			pubkey := common.Bytes48(k[:length.Bytes48])
+			if skip(pubkey) {
+				return nil
+			}
+			if _, ok := dataByPubkey[pubkey]; !ok {
+				exported = append(exported, pubkey)
+			}
+			data := getData(pubkey)
+			attestation := InterchangeAttestation{
+				SourceEpoch: binary.BigEndian.Uint64(v[:8]),
+				TargetEpoch: binary.BigEndian.Uint64(k[length.Bytes48:]),
+			}
+			if root := common.BytesToHash(v[8:]); root != (common.Hash{}) {
+				attestation.SigningRoot = &root
+			}
+			data.SignedAttestations = append(data.SignedAttestations, attestation)
+			return nil
+		})
+	}); err != nil {
+		return err
+	}
+	for _, pubkey := range exported {
+		interchange.Data = append(interchange.Data, *dataByPubkey[pubkey])
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(interchange)
+}
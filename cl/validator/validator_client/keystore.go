@@ -0,0 +1,269 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package validator_client
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon/cl/utils/bls"
+)
+
+var ErrWrongPassword = errors.New("keystore: wrong password")
+
+// Keystore is an EIP-2335 keystore, holding an encrypted BLS secret key.
+type Keystore struct {
+	Crypto      keystoreCrypto `json:"crypto"`
+	Description string         `json:"description,omitempty"`
+	Pubkey      string         `json:"pubkey"`
+	Path        string         `json:"path"`
+	UUID        string         `json:"uuid"`
+	Version     int            `json:"version"`
+}
+
+type keystoreCrypto struct {
+	Kdf      keystoreModule `json:"kdf"`
+	Checksum keystoreModule `json:"checksum"`
+	Cipher   keystoreModule `json:"cipher"`
+}
+
+type keystoreModule struct {
+	Function string          `json:"function"`
+	Params   json.RawMessage `json:"params"`
+	Message  string          `json:"message"`
+}
+
+type scryptParams struct {
+	Dklen int    `json:"dklen"`
+	N     int    `json:"n"`
+	P     int    `json:"p"`
+	R     int    `json:"r"`
+	Salt  string `json:"salt"`
+}
+
+type pbkdf2Params struct {
+	Dklen int    `json:"dklen"`
+	C     int    `json:"c"`
+	Prf   string `json:"prf"`
+	Salt  string `json:"salt"`
+}
+
+type aesParams struct {
+	IV string `json:"iv"`
+}
+
+// ParseKeystore decodes an EIP-2335 keystore, without decrypting it.
+func ParseKeystore(data []byte) (*Keystore, error) {
+	k := &Keystore{}
+	if err := json.Unmarshal(data, k); err != nil {
+		return nil, err
+	}
+	if k.Version != 4 {
+		return nil, fmt.Errorf("keystore: unsupported version %d", k.Version)
+	}
+	return k, nil
+}
+
+// PublicKey returns the public key the keystore declares to hold.
+func (k *Keystore) PublicKey() (common.Bytes48, error) {
+	var pk common.Bytes48
+	b, err := hex.DecodeString(strings.TrimPrefix(k.Pubkey, "0x"))
+	if err != nil {
+		return pk, err
+	}
+	if len(b) != len(pk) {
+		return pk, fmt.Errorf("keystore: invalid public key length %d", len(b))
+	}
+	copy(pk[:], b)
+	return pk, nil
+}
+
+// Decrypt returns the secret key of the keystore, ErrWrongPassword if the password does not match its checksum.
+func (k *Keystore) Decrypt(password string) (*bls.PrivateKey, error) {
+	decryptionKey, err := k.deriveKey(processPassword(password))
+	if err != nil {
+		return nil, err
+	}
+	cipherMessage, err := hex.DecodeString(k.Crypto.Cipher.Message)
+	if err != nil {
+		return nil, err
+	}
+	// checksum = SHA256(decryption_key[16:32] | cipher_message)
+	if k.Crypto.Checksum.Function != "sha256" {
+		return nil, fmt.Errorf("keystore: unsupported checksum function %s", k.Crypto.Checksum.Function)
+	}
+	checksum, err := hex.DecodeString(k.Crypto.Checksum.Message)
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	h.Write(decryptionKey[16:32])
+	h.Write(cipherMessage)
+	if !bytes.Equal(h.Sum(nil), checksum) {
+		return nil, ErrWrongPassword
+	}
+
+	if k.Crypto.Cipher.Function != "aes-128-ctr" {
+		return nil, fmt.Errorf("keystore: unsupported cipher function %s", k.Crypto.Cipher.Function)
+	}
+	var params aesParams
+	if err := json.Unmarshal(k.Crypto.Cipher.Params, &params); err != nil {
+		return nil, err
+	}
+	iv, err := hex.DecodeString(params.IV)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(decryptionKey[:16])
+	if err != nil {
+		return nil, err
+	}
+	secret := make([]byte, len(cipherMessage))
+	cipher.NewCTR(block, iv).XORKeyStream(secret, cipherMessage)
+
+	privateKey, err := bls.NewPrivateKeyFromBytes(secret)
+	if err != nil {
+		return nil, err
+	}
+	// do not trust the declared public key blindly, it is the one the validator signs with
+	pk, err := k.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(bls.CompressPublicKey(privateKey.PublicKey()), pk[:]) {
+		return nil, fmt.Errorf("keystore: secret key does not match public key %s", k.Pubkey)
+	}
+	return privateKey, nil
+}
+
+func (k *Keystore) deriveKey(password []byte) ([]byte, error) {
+	switch k.Crypto.Kdf.Function {
+	case "scrypt":
+		var params scryptParams
+		if err := json.Unmarshal(k.Crypto.Kdf.Params, &params); err != nil {
+			return nil, err
+		}
+		salt, err := hex.DecodeString(params.Salt)
+		if err != nil {
+			return nil, err
+		}
+		if params.Dklen < 32 {
+			return nil, fmt.Errorf("keystore: derived key too short: %d", params.Dklen)
+		}
+		return scrypt.Key(password, salt, params.N, params.R, params.P, params.Dklen)
+	case "pbkdf2":
+		var params pbkdf2Params
+		if err := json.Unmarshal(k.Crypto.Kdf.Params, &params); err != nil {
+			return nil, err
+		}
+		if params.Prf != "hmac-sha256" {
+			return nil, fmt.Errorf("keystore: unsupported pbkdf2 prf %s", params.Prf)
+		}
+		salt, err := hex.DecodeString(params.Salt)
+		if err != nil {
+			return nil, err
+		}
+		if params.Dklen < 32 {
+			return nil, fmt.Errorf("keystore: derived key too short: %d", params.Dklen)
+		}
+		return pbkdf2.Key(password, salt, params.C, params.Dklen, sha256.New), nil
+	default:
+		return nil, fmt.Errorf("keystore: unsupported kdf function %s", k.Crypto.Kdf.Function)
+	}
+}
+
+// processPassword normalizes the password as EIP-2335 mandates: NFKD, then the control codes are stripped.
+func processPassword(password string) []byte {
+	normalized := norm.NFKD.String(password)
+	out := make([]rune, 0, len(normalized))
+	for _, r := range normalized {
+		if r < 0x20 || (r >= 0x7f && r <= 0x9f) {
+			continue
+		}
+		out = append(out, r)
+	}
+	return []byte(string(out))
+}
+
+// LoadKeystores decrypts all the keystores of keystoresDir. passwordsPath is either a file holding the password of
+// all of them, or a directory holding <keystore file name>.txt for each of them.
+func LoadKeystores(keystoresDir, passwordsPath string) ([]*bls.PrivateKey, error) {
+	entries, err := os.ReadDir(keystoresDir)
+	if err != nil {
+		return nil, err
+	}
+	passwordsInfo, err := os.Stat(passwordsPath)
+	if err != nil {
+		return nil, err
+	}
+	var sharedPassword string
+	if !passwordsInfo.IsDir() {
+		if sharedPassword, err = readPassword(passwordsPath); err != nil {
+			return nil, err
+		}
+	}
+
+	keys := []*bls.PrivateKey{}
+	for _, entry := range entries {
+		// deposit data files live along the keystores, skip them
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" || strings.HasPrefix(entry.Name(), "deposit_data") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(keystoresDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		keystore, err := ParseKeystore(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		password := sharedPassword
+		if passwordsInfo.IsDir() {
+			if password, err = readPassword(filepath.Join(passwordsPath, strings.TrimSuffix(entry.Name(), ".json")+".txt")); err != nil {
+				return nil, err
+			}
+		}
+		key, err := keystore.Decrypt(password)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func readPassword(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
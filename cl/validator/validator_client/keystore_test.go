@@ -0,0 +1,139 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package validator_client
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// test vectors of EIP-2335
+const (
+	testKeystorePassword = "𝔱𝔢𝔰𝔱𝔭𝔞𝔰𝔰𝔴𝔬𝔯𝔡🔑"
+	testKeystoreSecret   = "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f"
+
+	testScryptKeystore = `{
+    "crypto": {
+        "kdf": {
+            "function": "scrypt",
+            "params": {
+                "dklen": 32,
+                "n": 262144,
+                "p": 1,
+                "r": 8,
+                "salt": "d4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3"
+            },
+            "message": ""
+        },
+        "checksum": {
+            "function": "sha256",
+            "params": {},
+            "message": "d2217fe5f3e9a1e34581ef8a78f7c9928e436d36dacc5e846690a5581e8ea484"
+        },
+        "cipher": {
+            "function": "aes-128-ctr",
+            "params": {
+                "iv": "264daa3f303d7259501c93d997d84fe6"
+            },
+            "message": "06ae90d55fe0a6e9c5c3bc5b170827b2e5cce3929ed3f116c2811e6366dfe20f"
+        }
+    },
+    "description": "This is a test keystore that uses scrypt to secure the secret.",
+    "pubkey": "9612d7a727c9d0a22e185a1c768478dfe919cada9266988cb32359c11f2b7b27f4ae4040902382ae2910c15e2b420d07",
+    "path": "m/12381/60/3141592653/589793238",
+    "uuid": "1d85ae20-35c5-4611-98e8-aa14a633906f",
+    "version": 4
+}`
+
+	testPbkdf2Keystore = `{
+    "crypto": {
+        "kdf": {
+            "function": "pbkdf2",
+            "params": {
+                "dklen": 32,
+                "c": 262144,
+                "prf": "hmac-sha256",
+                "salt": "d4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3"
+            },
+            "message": ""
+        },
+        "checksum": {
+            "function": "sha256",
+            "params": {},
+            "message": "8a9f5d9912ed7e75ea794bc5a89bca5f193721d30868ade6f73043c6ea6febf1"
+        },
+        "cipher": {
+            "function": "aes-128-ctr",
+            "params": {
+                "iv": "264daa3f303d7259501c93d997d84fe6"
+            },
+            "message": "cee03fde2af33149775b7223e7845e4fb2c8ae1792e5f99fe9ecf474cc8c16ad"
+        }
+    },
+    "description": "This is a test keystore that uses PBKDF2 to secure the secret.",
+    "pubkey": "9612d7a727c9d0a22e185a1c768478dfe919cada9266988cb32359c11f2b7b27f4ae4040902382ae2910c15e2b420d07",
+    "path": "m/12381/60/0/0",
+    "uuid": "64625def-3331-4eea-ab6f-782f3ed16a83",
+    "version": 4
+}`
+)
+
+func TestKeystoreDecrypt(t *testing.T) {
+	for name, keystoreJson := range map[string]string{"scrypt": testScryptKeystore, "pbkdf2": testPbkdf2Keystore} {
+		t.Run(name, func(t *testing.T) {
+			keystore, err := ParseKeystore([]byte(keystoreJson))
+			require.NoError(t, err)
+
+			key, err := keystore.Decrypt(testKeystorePassword)
+			require.NoError(t, err)
+			require.Equal(t, testKeystoreSecret, hex.EncodeToString(key.Bytes()))
+
+			_, err = keystore.Decrypt("wrong password")
+			require.ErrorIs(t, err, ErrWrongPassword)
+		})
+	}
+}
+
+func TestLoadKeystores(t *testing.T) {
+	keystoresDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(keystoresDir, "keystore-0.json"), []byte(testPbkdf2Keystore), 0600))
+	// deposit data is not a keystore
+	require.NoError(t, os.WriteFile(filepath.Join(keystoresDir, "deposit_data-0.json"), []byte("[]"), 0600))
+
+	// one password for all the keystores
+	passwordFile := filepath.Join(t.TempDir(), "password.txt")
+	require.NoError(t, os.WriteFile(passwordFile, []byte(testKeystorePassword+"\n"), 0600))
+	keys, err := LoadKeystores(keystoresDir, passwordFile)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Equal(t, testKeystoreSecret, hex.EncodeToString(keys[0].Bytes()))
+
+	// one password per keystore
+	passwordsDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(passwordsDir, "keystore-0.txt"), []byte(testKeystorePassword), 0600))
+	keys, err = LoadKeystores(keystoresDir, passwordsDir)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+
+	require.NoError(t, os.WriteFile(passwordFile, []byte("wrong password"), 0600))
+	_, err = LoadKeystores(keystoresDir, passwordFile)
+	require.ErrorIs(t, err, ErrWrongPassword)
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package validator_client
+
+import (
+	"context"
+	"errors"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/utils/bls"
+)
+
+var errUnknownSigner = errors.New("no signer for this validator")
+
+// SigningType is the kind of message a validator signs.
+type SigningType string
+
+const (
+	SigningTypeBlock                       SigningType = "BLOCK_V2"
+	SigningTypeAttestation                 SigningType = "ATTESTATION"
+	SigningTypeAggregationSlot             SigningType = "AGGREGATION_SLOT"
+	SigningTypeAggregateAndProof           SigningType = "AGGREGATE_AND_PROOF"
+	SigningTypeRandaoReveal                SigningType = "RANDAO_REVEAL"
+	SigningTypeSyncCommitteeMessage        SigningType = "SYNC_COMMITTEE_MESSAGE"
+	SigningTypeSyncCommitteeSelectionProof SigningType = "SYNC_COMMITTEE_SELECTION_PROOF"
+	SigningTypeContributionAndProof        SigningType = "SYNC_COMMITTEE_CONTRIBUTION_AND_PROOF"
+	SigningTypeValidatorRegistration       SigningType = "VALIDATOR_REGISTRATION"
+)
+
+// SigningRequest carries everything a signer may need: local signers only use the signing root, remote ones may
+// want to check the message itself.
+type SigningRequest struct {
+	Type        SigningType
+	SigningRoot common.Hash
+	// Fork and GenesisValidatorsRoot are the ones the signing domain was computed with.
+	Fork                  *cltypes.Fork
+	GenesisValidatorsRoot common.Hash
+	// Slot is the slot of the message, or the first slot of its epoch for the epoch bound ones.
+	Slot uint64
+	// Message is the signed object: the block, the attestation data, the aggregate and proof... or the epoch, slot
+	// or block root for the messages signing a bare value.
+	Message any
+}
+
+// Signer signs messages on behalf of one validator.
+type Signer interface {
+	PublicKey() common.Bytes48
+	Sign(ctx context.Context, req *SigningRequest) (common.Bytes96, error)
+}
+
+// RemoteSigner is a Signer whose key is held by a remote signer.
+type RemoteSigner interface {
+	Signer
+	URL() string
+}
+
+type localSigner struct {
+	key       *bls.PrivateKey
+	publicKey common.Bytes48
+}
+
+// NewLocalSigner creates a signer holding the secret key in memory.
+func NewLocalSigner(key *bls.PrivateKey) Signer {
+	return &localSigner{
+		key:       key,
+		publicKey: common.Bytes48(bls.CompressPublicKey(key.PublicKey())),
+	}
+}
+
+func (s *localSigner) PublicKey() common.Bytes48 {
+	return s.publicKey
+}
+
+func (s *localSigner) Sign(_ context.Context, req *SigningRequest) (common.Bytes96, error) {
+	return common.Bytes96(s.key.Sign(req.SigningRoot[:]).Bytes()), nil
+}
@@ -0,0 +1,192 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package validator_client
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/length"
+	"github.com/erigontech/erigon-lib/kv"
+)
+
+var (
+	ErrSlashableBlock       = errors.New("slashing protection: block is slashable or older than the last signed one")
+	ErrSlashableAttestation = errors.New("slashing protection: attestation is slashable or older than the last signed one")
+)
+
+/*
+SlashingProtection keeps track of everything the validators signed, and refuses to sign anything that could get them
+slashed. It follows the minimal rules of EIP-3076, which honest validators never break:
+  - a block is signed only at a slot higher than all the previously signed blocks, or for the same signing root.
+  - an attestation is signed only with a target higher than all the previously signed ones and a source no lower
+    than all the previously signed ones, or for the same signing root.
+
+Signing the same message twice is allowed, as it is harmless and happens on restarts.
+*/
+type SlashingProtection struct {
+	db kv.RwDB
+}
+
+// NewSlashingProtection creates the slashing protection on top of db, which must never be wiped.
+func NewSlashingProtection(db kv.RwDB) *SlashingProtection {
+	return &SlashingProtection{db: db}
+}
+
+// HasRecords returns whether anything signed by pubkey was recorded.
+func (s *SlashingProtection) HasRecords(ctx context.Context, pubkey common.Bytes48) (found bool, err error) {
+	err = s.db.View(ctx, func(tx kv.Tx) error {
+		for _, table := range []string{kv.SlashingProtectionBlocks, kv.SlashingProtectionAttestations} {
+			if _, _, found, err = lastEntry(tx, table, pubkey); err != nil || found {
+				return err
+			}
+		}
+		return nil
+	})
+	return found, err
+}
+
+// CheckAndRecordBlock records the block, if it is safe to sign it.
+func (s *SlashingProtection) CheckAndRecordBlock(ctx context.Context, pubkey common.Bytes48, slot uint64, signingRoot common.Hash) error {
+	return s.db.Update(ctx, func(tx kv.RwTx) error {
+		lastSlot, lastRoot, found, err := lastSignedBlock(tx, pubkey)
+		if err != nil {
+			return err
+		}
+		if found && slot < lastSlot {
+			return fmt.Errorf("%w: slot %d, last signed slot %d", ErrSlashableBlock, slot, lastSlot)
+		}
+		if found && slot == lastSlot {
+			if !isSameSigningRoot(lastRoot, signingRoot) {
+				return fmt.Errorf("%w: double proposal at slot %d", ErrSlashableBlock, slot)
+			}
+			return nil
+		}
+		return tx.Put(kv.SlashingProtectionBlocks, blockKey(pubkey, slot), signingRoot[:])
+	})
+}
+
+// CheckAndRecordAttestation records the attestation, if it is safe to sign it.
+func (s *SlashingProtection) CheckAndRecordAttestation(ctx context.Context, pubkey common.Bytes48, sourceEpoch, targetEpoch uint64, signingRoot common.Hash) error {
+	return s.db.Update(ctx, func(tx kv.RwTx) error {
+		lastTarget, lastSource, lastRoot, found, err := lastSignedAttestation(tx, pubkey)
+		if err != nil {
+			return err
+		}
+		if found && targetEpoch == lastTarget && sourceEpoch == lastSource && isSameSigningRoot(lastRoot, signingRoot) {
+			return nil
+		}
+		if found && targetEpoch <= lastTarget {
+			return fmt.Errorf("%w: target %d, last signed target %d", ErrSlashableAttestation, targetEpoch, lastTarget)
+		}
+		highestSource, hasSource, err := highestSourceEpoch(tx, pubkey)
+		if err != nil {
+			return err
+		}
+		if hasSource && sourceEpoch < highestSource {
+			return fmt.Errorf("%w: source %d, highest signed source %d", ErrSlashableAttestation, sourceEpoch, highestSource)
+		}
+		return putAttestation(tx, pubkey, sourceEpoch, targetEpoch, signingRoot)
+	})
+}
+
+// the zero signing root stands for an unknown one, as imported interchange files may omit them.
+func isSameSigningRoot(recorded, root common.Hash) bool {
+	return recorded != (common.Hash{}) && recorded == root
+}
+
+func blockKey(pubkey common.Bytes48, slot uint64) []byte {
+	return binary.BigEndian.AppendUint64(common.CopyBytes(pubkey[:]), slot)
+}
+
+func attestationKey(pubkey common.Bytes48, targetEpoch uint64) []byte {
+	return binary.BigEndian.AppendUint64(common.CopyBytes(pubkey[:]), targetEpoch)
+}
+
+func putAttestation(tx kv.RwTx, pubkey common.Bytes48, sourceEpoch, targetEpoch uint64, signingRoot common.Hash) error {
+	if err := tx.Put(kv.SlashingProtectionAttestations, attestationKey(pubkey, targetEpoch), append(binary.BigEndian.AppendUint64(nil, sourceEpoch), signingRoot[:]...)); err != nil {
+		return err
+	}
+	highestSource, hasSource, err := highestSourceEpoch(tx, pubkey)
+	if err != nil {
+		return err
+	}
+	if hasSource && highestSource >= sourceEpoch {
+		return nil
+	}
+	return tx.Put(kv.SlashingProtectionSources, pubkey[:], binary.BigEndian.AppendUint64(nil, sourceEpoch))
+}
+
+// lastEntry returns the entry of table with the highest key starting with the public key.
+func lastEntry(tx kv.Tx, table string, pubkey common.Bytes48) (uint64, []byte, bool, error) {
+	c, err := tx.Cursor(table)
+	if err != nil {
+		return 0, nil, false, err
+	}
+	defer c.Close()
+	seekKey := binary.BigEndian.AppendUint64(common.CopyBytes(pubkey[:]), math.MaxUint64)
+	k, v, err := c.Seek(seekKey)
+	if err != nil {
+		return 0, nil, false, err
+	}
+	if !bytes.Equal(k, seekKey) {
+		if k == nil {
+			k, v, err = c.Last()
+		} else {
+			k, v, err = c.Prev()
+		}
+		if err != nil {
+			return 0, nil, false, err
+		}
+	}
+	if len(k) != length.Bytes48+8 || !bytes.HasPrefix(k, pubkey[:]) {
+		return 0, nil, false, nil
+	}
+	return binary.BigEndian.Uint64(k[length.Bytes48:]), v, true, nil
+}
+
+func lastSignedBlock(tx kv.Tx, pubkey common.Bytes48) (slot uint64, signingRoot common.Hash, found bool, err error) {
+	slot, v, found, err := lastEntry(tx, kv.SlashingProtectionBlocks, pubkey)
+	if err != nil || !found {
+		return 0, common.Hash{}, false, err
+	}
+	return slot, common.BytesToHash(v), true, nil
+}
+
+func lastSignedAttestation(tx kv.Tx, pubkey common.Bytes48) (targetEpoch, sourceEpoch uint64, signingRoot common.Hash, found bool, err error) {
+	targetEpoch, v, found, err := lastEntry(tx, kv.SlashingProtectionAttestations, pubkey)
+	if err != nil || !found {
+		return 0, 0, common.Hash{}, false, err
+	}
+	if len(v) != 8+length.Hash {
+		return 0, 0, common.Hash{}, false, fmt.Errorf("slashing protection: corrupted attestation record of %x", pubkey)
+	}
+	return targetEpoch, binary.BigEndian.Uint64(v[:8]), common.BytesToHash(v[8:]), true, nil
+}
+
+func highestSourceEpoch(tx kv.Tx, pubkey common.Bytes48) (uint64, bool, error) {
+	v, err := tx.GetOne(kv.SlashingProtectionSources, pubkey[:])
+	if err != nil || len(v) != 8 {
+		return 0, false, err
+	}
+	return binary.BigEndian.Uint64(v), true, nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package validator_client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/kv/memdb"
+)
+
+func TestSlashingProtectionBlocks(t *testing.T) {
+	ctx := context.Background()
+	s := NewSlashingProtection(memdb.NewTestDB(t, kv.ValidatorDB))
+	pubkey := common.Bytes48{1}
+	other := common.Bytes48{2}
+
+	require.NoError(t, s.CheckAndRecordBlock(ctx, pubkey, 10, common.Hash{1}))
+	// signing the same block again is fine
+	require.NoError(t, s.CheckAndRecordBlock(ctx, pubkey, 10, common.Hash{1}))
+	// double proposal
+	require.ErrorIs(t, s.CheckAndRecordBlock(ctx, pubkey, 10, common.Hash{2}), ErrSlashableBlock)
+	// older than the last signed block
+	require.ErrorIs(t, s.CheckAndRecordBlock(ctx, pubkey, 9, common.Hash{3}), ErrSlashableBlock)
+	require.NoError(t, s.CheckAndRecordBlock(ctx, pubkey, 11, common.Hash{4}))
+	// other validators are not affected
+	require.NoError(t, s.CheckAndRecordBlock(ctx, other, 5, common.Hash{5}))
+}
+
+func TestSlashingProtectionAttestations(t *testing.T) {
+	ctx := context.Background()
+	s := NewSlashingProtection(memdb.NewTestDB(t, kv.ValidatorDB))
+	pubkey := common.Bytes48{1}
+
+	require.NoError(t, s.CheckAndRecordAttestation(ctx, pubkey, 2, 3, common.Hash{1}))
+	require.NoError(t, s.CheckAndRecordAttestation(ctx, pubkey, 2, 3, common.Hash{1}))
+	// double vote
+	require.ErrorIs(t, s.CheckAndRecordAttestation(ctx, pubkey, 2, 3, common.Hash{2}), ErrSlashableAttestation)
+	// older target
+	require.ErrorIs(t, s.CheckAndRecordAttestation(ctx, pubkey, 1, 2, common.Hash{3}), ErrSlashableAttestation)
+	require.NoError(t, s.CheckAndRecordAttestation(ctx, pubkey, 3, 5, common.Hash{4}))
+	// surrounding vote
+	require.ErrorIs(t, s.CheckAndRecordAttestation(ctx, pubkey, 2, 6, common.Hash{5}), ErrSlashableAttestation)
+	require.NoError(t, s.CheckAndRecordAttestation(ctx, pubkey, 5, 6, common.Hash{6}))
+}
+
+func TestSlashingProtectionInterchange(t *testing.T) {
+	ctx := context.Background()
+	genesisValidatorsRoot := common.Hash{0xaa}
+	s := NewSlashingProtection(memdb.NewTestDB(t, kv.ValidatorDB))
+	pubkey := common.Bytes48{1}
+
+	interchange := `{
+		"metadata": {"interchange_format_version": "5", "genesis_validators_root": "0xaa00000000000000000000000000000000000000000000000000000000000000"},
+		"data": [{
+			"pubkey": "0x010000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
+			"signed_blocks": [{"slot": "81952"}, {"slot": "81951", "signing_root": "0x0100000000000000000000000000000000000000000000000000000000000000"}],
+			"signed_attestations": [{"source_epoch": "2290", "target_epoch": "3007"}]
+		}]
+	}`
+	require.Error(t, s.ImportInterchange(ctx, bytes.NewBufferString(interchange), common.Hash{0xbb}))
+	require.NoError(t, s.ImportInterchange(ctx, bytes.NewBufferString(interchange), genesisValidatorsRoot))
+
+	// the records without signing root can not be signed again
+	require.ErrorIs(t, s.CheckAndRecordBlock(ctx, pubkey, 81952, common.Hash{}), ErrSlashableBlock)
+	require.ErrorIs(t, s.CheckAndRecordAttestation(ctx, pubkey, 2290, 3007, common.Hash{}), ErrSlashableAttestation)
+	require.ErrorIs(t, s.CheckAndRecordAttestation(ctx, pubkey, 2289, 3008, common.Hash{1}), ErrSlashableAttestation)
+	require.NoError(t, s.CheckAndRecordAttestation(ctx, pubkey, 2290, 3008, common.Hash{1}))
+
+	var out bytes.Buffer
+	require.NoError(t, s.ExportInterchange(ctx, &out, genesisValidatorsRoot))
+	var exported Interchange
+	require.NoError(t, json.Unmarshal(out.Bytes(), &exported))
+	require.Equal(t, "5", exported.Metadata.InterchangeFormatVersion)
+	require.Equal(t, genesisValidatorsRoot, exported.Metadata.GenesisValidatorsRoot)
+	require.Len(t, exported.Data, 1)
+	require.Equal(t, pubkey, exported.Data[0].Pubkey)
+	require.Len(t, exported.Data[0].SignedBlocks, 2)
+	require.Equal(t, uint64(81951), exported.Data[0].SignedBlocks[0].Slot)
+	require.Equal(t, common.Hash{1}, *exported.Data[0].SignedBlocks[0].SigningRoot)
+	require.Nil(t, exported.Data[0].SignedBlocks[1].SigningRoot)
+	require.Len(t, exported.Data[0].SignedAttestations, 2)
+
+	// importing again into a fresh database gives the same records
+	s2 := NewSlashingProtection(memdb.NewTestDB(t, kv.ValidatorDB))
+	require.NoError(t, s2.ImportInterchange(ctx, bytes.NewReader(out.Bytes()), genesisValidatorsRoot))
+	var out2 bytes.Buffer
+	require.NoError(t, s2.ExportInterchange(ctx, &out2, genesisValidatorsRoot))
+	require.JSONEq(t, out.String(), out2.String())
+}
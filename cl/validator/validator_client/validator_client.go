@@ -0,0 +1,860 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package validator_client
+
+import (
+	"context"
+	"encoding/binary"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/fork"
+	"github.com/erigontech/erigon/cl/merkle_tree"
+	"github.com/erigontech/erigon/cl/phase1/core/state"
+	"github.com/erigontech/erigon/cl/utils"
+	"github.com/erigontech/erigon/cl/utils/eth_clock"
+)
+
+// DefaultGasLimit is the gas limit the validators ask the builders for, unless set otherwise.
+const DefaultGasLimit = 36_000_000
+
+/*
+ValidatorClient performs the duties of the validators whose keys it holds, without any external validator client.
+It talks to the node through its beacon API handler, in process, so it takes the same paths as any other validator
+client does, and it refuses to sign anything the slashing protection deems unsafe.
+
+Duties are performed at the usual times of the slot:
+  - at the start of the slot, the block is proposed.
+  - at 1/3 of the slot, attestations and sync committee messages are sent.
+  - at 2/3 of the slot, aggregates and sync committee contributions are sent.
+*/
+type ValidatorClient struct {
+	ctx                context.Context
+	logger             log.Logger
+	beaconCfg          *clparams.BeaconChainConfig
+	ethClock           eth_clock.EthereumClock
+	api                *beaconAPI
+	slashingProtection *SlashingProtection
+
+	feeRecipient common.Address
+	graffiti     common.Hash
+	builder      bool
+
+	mu       sync.RWMutex
+	signers  map[common.Bytes48]Signer
+	settings map[common.Bytes48]ValidatorSettings
+	// indicies of the validators known to the beacon state, keyed by public key
+	indicies         map[common.Bytes48]uint64
+	indiciesEpoch    uint64
+	indiciesStale    bool
+	proposerDuties   map[uint64][]proposerDuty // by epoch
+	attesterDuties   map[uint64][]attesterDuty // by epoch
+	syncDuties       map[uint64][]syncDuty     // by epoch
+	subscribedEpochs map[uint64]struct{}
+	syncSubscribed   map[uint64]struct{} // by sync committee period
+	registrations    map[common.Bytes48]*cltypes.ValidatorRegistration
+}
+
+// NewValidatorClient creates the validator client. handler must be the beacon API handler of the node, with the
+// validator, beacon and node routes enabled, and it must be the same instance for producing and publishing blocks.
+func NewValidatorClient(
+	ctx context.Context,
+	logger log.Logger,
+	beaconCfg *clparams.BeaconChainConfig,
+	ethClock eth_clock.EthereumClock,
+	handler http.Handler,
+	slashingProtection *SlashingProtection,
+	signers []Signer,
+	feeRecipient common.Address,
+	graffiti common.Hash,
+	builder bool,
+) *ValidatorClient {
+	v := &ValidatorClient{
+		ctx:                ctx,
+		logger:             logger,
+		beaconCfg:          beaconCfg,
+		ethClock:           ethClock,
+		api:                &beaconAPI{handler: handler},
+		slashingProtection: slashingProtection,
+		feeRecipient:       feeRecipient,
+		graffiti:           graffiti,
+		builder:            builder,
+		signers:            make(map[common.Bytes48]Signer),
+		settings:           make(map[common.Bytes48]ValidatorSettings),
+		indicies:           make(map[common.Bytes48]uint64),
+		indiciesStale:      true,
+		proposerDuties:     make(map[uint64][]proposerDuty),
+		attesterDuties:     make(map[uint64][]attesterDuty),
+		syncDuties:         make(map[uint64][]syncDuty),
+		subscribedEpochs:   make(map[uint64]struct{}),
+		syncSubscribed:     make(map[uint64]struct{}),
+		registrations:      make(map[common.Bytes48]*cltypes.ValidatorRegistration),
+	}
+	for _, signer := range signers {
+		v.signers[signer.PublicKey()] = signer
+	}
+	return v
+}
+
+// AddSigner starts performing the duties of the validator of signer, from the next epoch on.
+func (v *ValidatorClient) AddSigner(signer Signer) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.signers[signer.PublicKey()] = signer
+	v.indiciesStale = true
+}
+
+// RemoveSigner stops performing the duties of the validator of pubkey, returns whether it was known.
+func (v *ValidatorClient) RemoveSigner(pubkey common.Bytes48) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	_, ok := v.signers[pubkey]
+	delete(v.signers, pubkey)
+	delete(v.indicies, pubkey)
+	delete(v.registrations, pubkey)
+	return ok
+}
+
+// Signers returns the signers of all the validators of the client.
+func (v *ValidatorClient) Signers() []Signer {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	out := make([]Signer, 0, len(v.signers))
+	for _, signer := range v.signers {
+		out = append(out, signer)
+	}
+	return out
+}
+
+// SetSettings overrides the defaults for the validator of pubkey, from the next epoch on.
+func (v *ValidatorClient) SetSettings(pubkey common.Bytes48, settings ValidatorSettings) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if settings.empty() {
+		delete(v.settings, pubkey)
+	} else {
+		v.settings[pubkey] = settings
+	}
+	// the registration must be signed again with the new fee recipient and gas limit
+	delete(v.registrations, pubkey)
+}
+
+// Settings returns the overrides of the validator of pubkey.
+func (v *ValidatorClient) Settings(pubkey common.Bytes48) ValidatorSettings {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.settings[pubkey]
+}
+
+// FeeRecipient returns the fee recipient of the validator of pubkey.
+func (v *ValidatorClient) FeeRecipient(pubkey common.Bytes48) common.Address {
+	if feeRecipient := v.Settings(pubkey).FeeRecipient; feeRecipient != nil {
+		return *feeRecipient
+	}
+	return v.feeRecipient
+}
+
+// GasLimit returns the gas limit the validator of pubkey asks the builders for.
+func (v *ValidatorClient) GasLimit(pubkey common.Bytes48) uint64 {
+	if gasLimit := v.Settings(pubkey).GasLimit; gasLimit != nil {
+		return *gasLimit
+	}
+	return DefaultGasLimit
+}
+
+// Graffiti returns the graffiti of the blocks proposed by the validator of pubkey.
+func (v *ValidatorClient) Graffiti(pubkey common.Bytes48) common.Hash {
+	graffiti := v.Settings(pubkey).Graffiti
+	if graffiti == nil {
+		return v.graffiti
+	}
+	var out common.Hash
+	copy(out[:], *graffiti)
+	return out
+}
+
+func (v *ValidatorClient) signer(pubkey common.Bytes48) (Signer, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	signer, ok := v.signers[pubkey]
+	return signer, ok
+}
+
+// Start runs the duties of every slot until the context is done.
+func (v *ValidatorClient) Start() {
+	v.logger.Info("[Validator Client] Started", "validators", len(v.Signers()))
+	for {
+		nextSlot := v.ethClock.GetCurrentSlot() + 1
+		if !v.waitUntil(v.ethClock.GetSlotTime(nextSlot)) {
+			return
+		}
+		go v.onSlot(nextSlot)
+	}
+}
+
+func (v *ValidatorClient) waitUntil(t time.Time) bool {
+	timer := time.NewTimer(time.Until(t))
+	defer timer.Stop()
+	select {
+	case <-v.ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func (v *ValidatorClient) onSlot(slot uint64) {
+	epoch := slot / v.beaconCfg.SlotsPerEpoch
+	if err := v.updateDuties(epoch); err != nil {
+		v.logger.Warn("[Validator Client] Could not update duties", "epoch", epoch, "err", err)
+		return
+	}
+	slotTime := v.ethClock.GetSlotTime(slot)
+	slotDuration := time.Duration(v.beaconCfg.SecondsPerSlot) * time.Second
+
+	v.propose(slot)
+
+	if !v.waitUntil(slotTime.Add(slotDuration / 3)) {
+		return
+	}
+	attestationData := v.attest(slot)
+	headRoot := v.sendSyncCommitteeMessages(slot)
+
+	if !v.waitUntil(slotTime.Add(2 * slotDuration / 3)) {
+		return
+	}
+	v.aggregate(slot, attestationData)
+	v.sendSyncCommitteeContributions(slot, headRoot)
+}
+
+// updateDuties refreshes the duties of the epoch and of the next one, as they may change with reorgs, and
+// subscribes to the subnets of the duties the first time they are seen.
+func (v *ValidatorClient) updateDuties(epoch uint64) error {
+	if err := v.updateIndicies(epoch); err != nil {
+		return err
+	}
+	v.mu.RLock()
+	indicies := make([]uint64, 0, len(v.indicies))
+	for _, idx := range v.indicies {
+		indicies = append(indicies, idx)
+	}
+	v.mu.RUnlock()
+	if len(indicies) == 0 {
+		return nil
+	}
+
+	proposerDuties, err := v.api.proposerDuties(v.ctx, epoch)
+	if err != nil {
+		return err
+	}
+	attesterDuties := make(map[uint64][]attesterDuty, 2)
+	syncDuties := make(map[uint64][]syncDuty, 2)
+	for _, e := range []uint64{epoch, epoch + 1} {
+		if attesterDuties[e], err = v.api.attesterDuties(v.ctx, e, indicies); err != nil {
+			return err
+		}
+		if v.beaconCfg.GetCurrentStateVersion(e) < clparams.AltairVersion {
+			continue
+		}
+		if syncDuties[e], err = v.api.syncDuties(v.ctx, e, indicies); err != nil {
+			return err
+		}
+	}
+
+	v.mu.Lock()
+	v.proposerDuties[epoch] = proposerDuties
+	for e, duties := range attesterDuties {
+		v.attesterDuties[e] = duties
+	}
+	for e, duties := range syncDuties {
+		v.syncDuties[e] = duties
+	}
+	// forget about the past
+	for e := range v.attesterDuties {
+		if e+1 < epoch {
+			delete(v.proposerDuties, e)
+			delete(v.attesterDuties, e)
+			delete(v.syncDuties, e)
+			delete(v.subscribedEpochs, e)
+		}
+	}
+	_, subscribed := v.subscribedEpochs[epoch+1]
+	v.subscribedEpochs[epoch+1] = struct{}{}
+	_, subscribedCurrent := v.subscribedEpochs[epoch]
+	v.subscribedEpochs[epoch] = struct{}{}
+	v.mu.Unlock()
+
+	if !subscribedCurrent {
+		v.subscribe(attesterDuties[epoch], syncDuties[epoch], epoch)
+		v.prepareProposers()
+	}
+	if !subscribed {
+		v.subscribe(attesterDuties[epoch+1], syncDuties[epoch+1], epoch+1)
+	}
+	return nil
+}
+
+// updateIndicies looks up the indicies of the validators once per epoch, to pick up the newly deposited ones.
+func (v *ValidatorClient) updateIndicies(epoch uint64) error {
+	v.mu.RLock()
+	upToDate := !v.indiciesStale && v.indiciesEpoch == epoch
+	pubkeys := make([]common.Bytes48, 0, len(v.signers))
+	for pubkey := range v.signers {
+		pubkeys = append(pubkeys, pubkey)
+	}
+	v.mu.RUnlock()
+	// an empty list of ids would return all the validators
+	if upToDate || len(pubkeys) == 0 {
+		return nil
+	}
+	validators, err := v.api.validators(v.ctx, pubkeys)
+	if err != nil {
+		return err
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for _, validator := range validators {
+		if _, ok := v.signers[validator.Validator.Pubkey]; ok {
+			v.indicies[validator.Validator.Pubkey] = validator.Index
+		}
+	}
+	v.indiciesEpoch = epoch
+	v.indiciesStale = false
+	if len(v.indicies) < len(pubkeys) {
+		v.logger.Debug("[Validator Client] Some validators are not deposited yet", "known", len(v.indicies), "validators", len(pubkeys))
+	}
+	return nil
+}
+
+func (v *ValidatorClient) subscribe(attesterDuties []attesterDuty, syncDuties []syncDuty, epoch uint64) {
+	subscriptions := make([]*cltypes.BeaconCommitteeSubscription, 0, len(attesterDuties))
+	for _, duty := range attesterDuties {
+		isAggregator, _, err := v.isAttestationAggregator(duty)
+		if err != nil {
+			v.logger.Warn("[Validator Client] Could not compute selection proof", "slot", duty.Slot, "err", err)
+			continue
+		}
+		subscriptions = append(subscriptions, &cltypes.BeaconCommitteeSubscription{
+			ValidatorIndex:   duty.ValidatorIndex,
+			CommitteeIndex:   duty.CommitteeIndex,
+			CommitteesAtSlot: duty.CommitteesAtSlot,
+			Slot:             duty.Slot,
+			IsAggregator:     isAggregator,
+		})
+	}
+	if len(subscriptions) > 0 {
+		if err := v.api.subscribeBeaconCommittees(v.ctx, subscriptions); err != nil {
+			v.logger.Warn("[Validator Client] Could not subscribe to beacon committees", "epoch", epoch, "err", err)
+		}
+	}
+
+	if len(syncDuties) == 0 {
+		return
+	}
+	period := epoch / v.beaconCfg.EpochsPerSyncCommitteePeriod
+	v.mu.Lock()
+	_, subscribed := v.syncSubscribed[period]
+	v.syncSubscribed[period] = struct{}{}
+	v.mu.Unlock()
+	if subscribed {
+		return
+	}
+	syncSubscriptions := make([]syncCommitteeSubscription, 0, len(syncDuties))
+	for _, duty := range syncDuties {
+		syncSubscriptions = append(syncSubscriptions, syncCommitteeSubscription{
+			ValidatorIndex:        duty.ValidatorIndex,
+			SyncCommitteeIndicies: duty.ValidatorSyncCommitteeIndicies,
+			UntilEpoch:            (period + 1) * v.beaconCfg.EpochsPerSyncCommitteePeriod,
+		})
+	}
+	if err := v.api.subscribeSyncCommittees(v.ctx, syncSubscriptions); err != nil {
+		v.logger.Warn("[Validator Client] Could not subscribe to sync committees", "period", period, "err", err)
+	}
+}
+
+// prepareProposers sets the fee recipient of the validators, and registers them to the builders.
+func (v *ValidatorClient) prepareProposers() {
+	v.mu.RLock()
+	indicies := make(map[common.Bytes48]uint64, len(v.indicies))
+	for pubkey, idx := range v.indicies {
+		indicies[pubkey] = idx
+	}
+	v.mu.RUnlock()
+	preparations := make([]proposerPreparation, 0, len(indicies))
+	pubkeys := make([]common.Bytes48, 0, len(indicies))
+	for pubkey, idx := range indicies {
+		preparations = append(preparations, proposerPreparation{ValidatorIndex: idx, FeeRecipient: v.FeeRecipient(pubkey)})
+		pubkeys = append(pubkeys, pubkey)
+	}
+	if len(preparations) == 0 {
+		return
+	}
+	if err := v.api.prepareBeaconProposers(v.ctx, preparations); err != nil {
+		v.logger.Warn("[Validator Client] Could not prepare proposers", "err", err)
+	}
+	if !v.builder {
+		return
+	}
+
+	registrations := make([]*cltypes.ValidatorRegistration, 0, len(pubkeys))
+	for _, pubkey := range pubkeys {
+		registration, err := v.validatorRegistration(pubkey)
+		if err != nil {
+			v.logger.Warn("[Validator Client] Could not sign validator registration", "pubkey", pubkey, "err", err)
+			continue
+		}
+		registrations = append(registrations, registration)
+	}
+	if len(registrations) == 0 {
+		return
+	}
+	if err := v.api.registerValidators(v.ctx, registrations); err != nil {
+		v.logger.Warn("[Validator Client] Could not register validators to the builder", "err", err)
+	}
+}
+
+// validatorRegistration signs the registration once, with a stable timestamp, as builders re-validate it every
+// epoch.
+func (v *ValidatorClient) validatorRegistration(pubkey common.Bytes48) (*cltypes.ValidatorRegistration, error) {
+	v.mu.RLock()
+	registration, ok := v.registrations[pubkey]
+	v.mu.RUnlock()
+	if ok {
+		return registration, nil
+	}
+	signer, ok := v.signer(pubkey)
+	if !ok {
+		return nil, errUnknownSigner
+	}
+	timestamp := uint64(time.Now().Unix())
+	gasLimit := v.GasLimit(pubkey)
+	message := cltypes.ValidatorRegistrationMessage{
+		FeeRecipient: v.FeeRecipient(pubkey),
+		GasLimit:     strconv.FormatUint(gasLimit, 10),
+		Timestamp:    strconv.FormatUint(timestamp, 10),
+		PubKey:       pubkey,
+	}
+	root, err := merkle_tree.HashTreeRoot(message.FeeRecipient[:], gasLimit, timestamp, pubkey[:])
+	if err != nil {
+		return nil, err
+	}
+	// registrations are not bound to any fork
+	domain, err := fork.ComputeDomain(v.beaconCfg.DomainApplicationBuilder[:], utils.Uint32ToBytes4(uint32(v.beaconCfg.GenesisForkVersion)), [32]byte{})
+	if err != nil {
+		return nil, err
+	}
+	signature, err := signer.Sign(v.ctx, &SigningRequest{
+		Type:        SigningTypeValidatorRegistration,
+		SigningRoot: utils.Sha256(root[:], domain),
+		Message:     &message,
+	})
+	if err != nil {
+		return nil, err
+	}
+	registration = &cltypes.ValidatorRegistration{Message: message, Signature: signature}
+	v.mu.Lock()
+	v.registrations[pubkey] = registration
+	v.mu.Unlock()
+	return registration, nil
+}
+
+// forkAt returns the fork the messages of epoch are signed with.
+func (v *ValidatorClient) forkAt(epoch uint64) *cltypes.Fork {
+	version := v.beaconCfg.GetCurrentStateVersion(epoch)
+	f := &cltypes.Fork{
+		CurrentVersion: utils.Uint32ToBytes4(v.beaconCfg.GetForkVersionByVersion(version)),
+		Epoch:          v.beaconCfg.GetForkEpochByVersion(version),
+	}
+	f.PreviousVersion = f.CurrentVersion
+	if version > clparams.Phase0Version {
+		f.PreviousVersion = utils.Uint32ToBytes4(v.beaconCfg.GetForkVersionByVersion(version - 1))
+	}
+	return f
+}
+
+// signingRequest computes the signing root of the object of root objRoot for the domain of domainType at epoch.
+func (v *ValidatorClient) signingRequest(signingType SigningType, domainType common.Bytes4, slot uint64, objRoot [32]byte, message any) (*SigningRequest, error) {
+	f := v.forkAt(slot / v.beaconCfg.SlotsPerEpoch)
+	genesisValidatorsRoot := v.ethClock.GenesisValidatorsRoot()
+	domain, err := fork.ComputeDomain(domainType[:], f.CurrentVersion, genesisValidatorsRoot)
+	if err != nil {
+		return nil, err
+	}
+	return &SigningRequest{
+		Type:                  signingType,
+		SigningRoot:           utils.Sha256(objRoot[:], domain),
+		Fork:                  f,
+		GenesisValidatorsRoot: genesisValidatorsRoot,
+		Slot:                  slot,
+		Message:               message,
+	}, nil
+}
+
+func (v *ValidatorClient) sign(pubkey common.Bytes48, signingType SigningType, domainType common.Bytes4, slot uint64, objRoot [32]byte, message any) (common.Bytes96, error) {
+	signer, ok := v.signer(pubkey)
+	if !ok {
+		return common.Bytes96{}, errUnknownSigner
+	}
+	req, err := v.signingRequest(signingType, domainType, slot, objRoot, message)
+	if err != nil {
+		return common.Bytes96{}, err
+	}
+	return signer.Sign(v.ctx, req)
+}
+
+func (v *ValidatorClient) propose(slot uint64) {
+	epoch := slot / v.beaconCfg.SlotsPerEpoch
+	v.mu.RLock()
+	var duty *proposerDuty
+	for i, d := range v.proposerDuties[epoch] {
+		if _, ok := v.signers[d.Pubkey]; ok && d.Slot == slot {
+			duty = &v.proposerDuties[epoch][i]
+		}
+	}
+	v.mu.RUnlock()
+	if duty == nil {
+		return
+	}
+	if v.beaconCfg.GetCurrentStateVersion(epoch) < clparams.DenebVersion {
+		v.logger.Warn("[Validator Client] Block proposals are only supported from Deneb on", "slot", slot)
+		return
+	}
+	if err := v.proposeBlock(slot, duty.Pubkey); err != nil {
+		v.logger.Error("[Validator Client] Could not propose block", "slot", slot, "validator", duty.ValidatorIndex, "err", err)
+		return
+	}
+	v.logger.Info("[Validator Client] Proposed block", "slot", slot, "validator", duty.ValidatorIndex)
+}
+
+func (v *ValidatorClient) proposeBlock(slot uint64, pubkey common.Bytes48) error {
+	epoch := slot / v.beaconCfg.SlotsPerEpoch
+	randaoReveal, err := v.sign(pubkey, SigningTypeRandaoReveal, v.beaconCfg.DomainRandao, epoch*v.beaconCfg.SlotsPerEpoch, merkle_tree.Uint64Root(epoch), epoch)
+	if err != nil {
+		return err
+	}
+	block, blindedBlock, err := v.api.produceBlock(v.ctx, v.beaconCfg, slot, randaoReveal, v.Graffiti(pubkey))
+	if err != nil {
+		return err
+	}
+
+	var (
+		root    [32]byte
+		message any
+	)
+	if blindedBlock != nil {
+		root, err = blindedBlock.HashSSZ()
+		message = blindedBlock
+	} else {
+		root, err = block.Block.HashSSZ()
+		message = block.Block
+	}
+	if err != nil {
+		return err
+	}
+	req, err := v.signingRequest(SigningTypeBlock, v.beaconCfg.DomainBeaconProposer, slot, root, message)
+	if err != nil {
+		return err
+	}
+	if err := v.slashingProtection.CheckAndRecordBlock(v.ctx, pubkey, slot, req.SigningRoot); err != nil {
+		return err
+	}
+	signer, ok := v.signer(pubkey)
+	if !ok {
+		return errUnknownSigner
+	}
+	signature, err := signer.Sign(v.ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if blindedBlock != nil {
+		return v.api.publishBlindedBlock(v.ctx, &cltypes.SignedBlindedBeaconBlock{Block: blindedBlock, Signature: signature})
+	}
+	return v.api.publishBlock(v.ctx, &cltypes.DenebSignedBeaconBlock{
+		SignedBlock: &cltypes.SignedBeaconBlock{Block: block.Block, Signature: signature},
+		KZGProofs:   block.KZGProofs,
+		Blobs:       block.Blobs,
+	})
+}
+
+func (v *ValidatorClient) dutiesAtSlot(slot uint64) []attesterDuty {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	var duties []attesterDuty
+	for _, duty := range v.attesterDuties[slot/v.beaconCfg.SlotsPerEpoch] {
+		if _, ok := v.signers[duty.Pubkey]; ok && duty.Slot == slot {
+			duties = append(duties, duty)
+		}
+	}
+	return duties
+}
+
+// attest sends the attestations of the slot, and returns the attestation data they were made of, by committee index.
+func (v *ValidatorClient) attest(slot uint64) map[uint64]*solid.AttestationData {
+	duties := v.dutiesAtSlot(slot)
+	attestationData := make(map[uint64]*solid.AttestationData)
+	if len(duties) == 0 {
+		return attestationData
+	}
+	version := v.beaconCfg.GetCurrentStateVersion(slot / v.beaconCfg.SlotsPerEpoch)
+
+	var (
+		attestations       []*solid.Attestation
+		singleAttestations []*solid.SingleAttestation
+	)
+	for _, duty := range duties {
+		data, ok := attestationData[duty.CommitteeIndex]
+		if !ok {
+			var err error
+			if data, err = v.api.attestationData(v.ctx, slot, duty.CommitteeIndex); err != nil {
+				v.logger.Warn("[Validator Client] Could not get attestation data", "slot", slot, "committee", duty.CommitteeIndex, "err", err)
+				continue
+			}
+			attestationData[duty.CommitteeIndex] = data
+		}
+		dataRoot, err := data.HashSSZ()
+		if err != nil {
+			v.logger.Warn("[Validator Client] Could not hash attestation data", "err", err)
+			continue
+		}
+		req, err := v.signingRequest(SigningTypeAttestation, v.beaconCfg.DomainBeaconAttester, data.Target.Epoch*v.beaconCfg.SlotsPerEpoch, dataRoot, data)
+		if err != nil {
+			v.logger.Warn("[Validator Client] Could not compute attestation signing root", "err", err)
+			continue
+		}
+		if err := v.slashingProtection.CheckAndRecordAttestation(v.ctx, duty.Pubkey, data.Source.Epoch, data.Target.Epoch, req.SigningRoot); err != nil {
+			v.logger.Error("[Validator Client] Refused to attest", "slot", slot, "validator", duty.ValidatorIndex, "err", err)
+			continue
+		}
+		signer, ok := v.signer(duty.Pubkey)
+		if !ok {
+			continue
+		}
+		signature, err := signer.Sign(v.ctx, req)
+		if err != nil {
+			v.logger.Warn("[Validator Client] Could not sign attestation", "slot", slot, "validator", duty.ValidatorIndex, "err", err)
+			continue
+		}
+
+		if version >= clparams.ElectraVersion {
+			singleAttestations = append(singleAttestations, &solid.SingleAttestation{
+				CommitteeIndex: duty.CommitteeIndex,
+				AttesterIndex:  duty.ValidatorIndex,
+				Data:           data,
+				Signature:      signature,
+			})
+			continue
+		}
+		// flip the bit of the validator and the one marking the length of the committee
+		bits := make([]byte, duty.CommitteeLength/8+1)
+		bits[duty.ValidatorCommitteeIndex/8] |= 1 << (duty.ValidatorCommitteeIndex % 8)
+		bits[duty.CommitteeLength/8] |= 1 << (duty.CommitteeLength % 8)
+		attestations = append(attestations, &solid.Attestation{
+			AggregationBits: solid.BitlistFromBytes(bits, int(v.beaconCfg.MaxValidatorsPerCommittee)),
+			Data:            data,
+			Signature:       signature,
+		})
+	}
+
+	var err error
+	if version >= clparams.ElectraVersion && len(singleAttestations) > 0 {
+		err = v.api.submitSingleAttestations(v.ctx, version, singleAttestations)
+	} else if len(attestations) > 0 {
+		err = v.api.submitAttestations(v.ctx, version, attestations)
+	}
+	if err != nil {
+		v.logger.Warn("[Validator Client] Could not submit attestations", "slot", slot, "err", err)
+		return attestationData
+	}
+	v.logger.Debug("[Validator Client] Attested", "slot", slot, "count", len(attestations)+len(singleAttestations))
+	return attestationData
+}
+
+// isAttestationAggregator returns whether the validator of duty aggregates its committee, along with its selection
+// proof.
+func (v *ValidatorClient) isAttestationAggregator(duty attesterDuty) (bool, common.Bytes96, error) {
+	selectionProof, err := v.sign(duty.Pubkey, SigningTypeAggregationSlot, v.beaconCfg.DomainSelectionProof, duty.Slot, merkle_tree.Uint64Root(duty.Slot), duty.Slot)
+	if err != nil {
+		return false, common.Bytes96{}, err
+	}
+	return state.IsAggregator(v.beaconCfg, duty.CommitteeLength, duty.CommitteeIndex, selectionProof), selectionProof, nil
+}
+
+func (v *ValidatorClient) aggregate(slot uint64, attestationData map[uint64]*solid.AttestationData) {
+	var aggregates []*cltypes.SignedAggregateAndProof
+	for _, duty := range v.dutiesAtSlot(slot) {
+		data, ok := attestationData[duty.CommitteeIndex]
+		if !ok {
+			continue
+		}
+		isAggregator, selectionProof, err := v.isAttestationAggregator(duty)
+		if err != nil {
+			v.logger.Warn("[Validator Client] Could not compute selection proof", "slot", slot, "err", err)
+			continue
+		}
+		if !isAggregator {
+			continue
+		}
+		dataRoot, err := data.HashSSZ()
+		if err != nil {
+			continue
+		}
+		aggregate, err := v.api.aggregateAttestation(v.ctx, dataRoot, slot, duty.CommitteeIndex)
+		if err != nil {
+			v.logger.Debug("[Validator Client] Could not get aggregate attestation", "slot", slot, "committee", duty.CommitteeIndex, "err", err)
+			continue
+		}
+		aggregateAndProof := &cltypes.AggregateAndProof{
+			AggregatorIndex: duty.ValidatorIndex,
+			Aggregate:       aggregate,
+			SelectionProof:  selectionProof,
+		}
+		root, err := aggregateAndProof.HashSSZ()
+		if err != nil {
+			continue
+		}
+		signature, err := v.sign(duty.Pubkey, SigningTypeAggregateAndProof, v.beaconCfg.DomainAggregateAndProof, slot, root, aggregateAndProof)
+		if err != nil {
+			v.logger.Warn("[Validator Client] Could not sign aggregate and proof", "slot", slot, "err", err)
+			continue
+		}
+		aggregates = append(aggregates, &cltypes.SignedAggregateAndProof{Message: aggregateAndProof, Signature: signature})
+	}
+	if len(aggregates) == 0 {
+		return
+	}
+	if err := v.api.submitAggregateAndProofs(v.ctx, aggregates); err != nil {
+		v.logger.Warn("[Validator Client] Could not submit aggregates", "slot", slot, "err", err)
+	}
+}
+
+// syncDutiesAtSlot returns the sync committee duties of the slot, which are the ones of the committee in charge at
+// the next slot, as the messages are included in the next block.
+func (v *ValidatorClient) syncDutiesAtSlot(slot uint64) []syncDuty {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	var duties []syncDuty
+	for _, duty := range v.syncDuties[(slot+1)/v.beaconCfg.SlotsPerEpoch] {
+		if _, ok := v.signers[duty.Pubkey]; ok {
+			duties = append(duties, duty)
+		}
+	}
+	return duties
+}
+
+// sendSyncCommitteeMessages sends the sync committee messages of the slot, and returns the head root they vote for.
+func (v *ValidatorClient) sendSyncCommitteeMessages(slot uint64) common.Hash {
+	duties := v.syncDutiesAtSlot(slot)
+	if len(duties) == 0 {
+		return common.Hash{}
+	}
+	headRoot, err := v.api.headBlockRoot(v.ctx)
+	if err != nil {
+		v.logger.Warn("[Validator Client] Could not get head block root", "err", err)
+		return common.Hash{}
+	}
+	messages := make([]*cltypes.SyncCommitteeMessage, 0, len(duties))
+	for _, duty := range duties {
+		signature, err := v.sign(duty.Pubkey, SigningTypeSyncCommitteeMessage, v.beaconCfg.DomainSyncCommittee, slot, headRoot, headRoot)
+		if err != nil {
+			v.logger.Warn("[Validator Client] Could not sign sync committee message", "slot", slot, "err", err)
+			continue
+		}
+		messages = append(messages, &cltypes.SyncCommitteeMessage{
+			Slot:            slot,
+			BeaconBlockRoot: headRoot,
+			ValidatorIndex:  duty.ValidatorIndex,
+			Signature:       signature,
+		})
+	}
+	if len(messages) == 0 {
+		return headRoot
+	}
+	if err := v.api.submitSyncCommitteeMessages(v.ctx, messages); err != nil {
+		v.logger.Warn("[Validator Client] Could not submit sync committee messages", "slot", slot, "err", err)
+	}
+	return headRoot
+}
+
+func (v *ValidatorClient) sendSyncCommitteeContributions(slot uint64, headRoot common.Hash) {
+	if headRoot == (common.Hash{}) {
+		return
+	}
+	subcommitteeSize := v.beaconCfg.SyncCommitteeSize / v.beaconCfg.SyncCommitteeSubnetCount
+	modulo := max(1, subcommitteeSize/v.beaconCfg.TargetAggregatorsPerSyncSubcommittee)
+
+	var contributions []*cltypes.SignedContributionAndProof
+	for _, duty := range v.syncDutiesAtSlot(slot) {
+		subcommittees := make(map[uint64]struct{})
+		for _, idxStr := range duty.ValidatorSyncCommitteeIndicies {
+			idx, err := strconv.ParseUint(idxStr, 10, 64)
+			if err != nil {
+				continue
+			}
+			subcommittees[idx/subcommitteeSize] = struct{}{}
+		}
+		for subcommittee := range subcommittees {
+			selectionData := &cltypes.SyncAggregatorSelectionData{Slot: slot, SubcommitteeIndex: subcommittee}
+			selectionRoot, err := selectionData.HashSSZ()
+			if err != nil {
+				continue
+			}
+			selectionProof, err := v.sign(duty.Pubkey, SigningTypeSyncCommitteeSelectionProof, v.beaconCfg.DomainSyncCommitteeSelectionProof, slot, selectionRoot, selectionData)
+			if err != nil {
+				v.logger.Warn("[Validator Client] Could not sign sync committee selection proof", "slot", slot, "err", err)
+				continue
+			}
+			hash := utils.Sha256(selectionProof[:])
+			if binary.LittleEndian.Uint64(hash[:8])%modulo != 0 {
+				continue
+			}
+			contribution, err := v.api.syncCommitteeContribution(v.ctx, slot, subcommittee, headRoot)
+			if err != nil || contribution == nil {
+				v.logger.Debug("[Validator Client] Could not get sync committee contribution", "slot", slot, "subcommittee", subcommittee, "err", err)
+				continue
+			}
+			contributionAndProof := &cltypes.ContributionAndProof{
+				AggregatorIndex: duty.ValidatorIndex,
+				Contribution:    contribution,
+				SelectionProof:  selectionProof,
+			}
+			root, err := contributionAndProof.HashSSZ()
+			if err != nil {
+				continue
+			}
+			signature, err := v.sign(duty.Pubkey, SigningTypeContributionAndProof, v.beaconCfg.DomainContributionAndProof, slot, root, contributionAndProof)
+			if err != nil {
+				v.logger.Warn("[Validator Client] Could not sign contribution and proof", "slot", slot, "err", err)
+				continue
+			}
+			contributions = append(contributions, &cltypes.SignedContributionAndProof{Message: contributionAndProof, Signature: signature})
+		}
+	}
+	if len(contributions) == 0 {
+		return
+	}
+	if err := v.api.submitContributionAndProofs(v.ctx, contributions); err != nil {
+		v.logger.Warn("[Validator Client] Could not submit sync committee contributions", "slot", slot, "err", err)
+	}
+}
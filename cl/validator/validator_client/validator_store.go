@@ -0,0 +1,198 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package validator_client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/dir"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon/cl/clparams"
+)
+
+// ValidatorSettings overrides the defaults of the validator client for one validator, unset fields keep the default.
+type ValidatorSettings struct {
+	FeeRecipient *common.Address `json:"fee_recipient,omitempty"`
+	GasLimit     *uint64         `json:"gas_limit,omitempty"`
+	Graffiti     *string         `json:"graffiti,omitempty"`
+}
+
+func (s ValidatorSettings) empty() bool {
+	return s.FeeRecipient == nil && s.GasLimit == nil && s.Graffiti == nil
+}
+
+// ValidatorStore persists the validators added at runtime, along with the settings of all the validators, so that
+// they survive restarts. Remote keys and settings live in the database of the slashing protection, while imported
+// keystores are kept out of it: they are written to dir with their passwords, in the layout LoadKeystores reads.
+type ValidatorStore struct {
+	db           kv.RwDB
+	keystoresDir string
+	passwordsDir string
+}
+
+func NewValidatorStore(db kv.RwDB, dir string) *ValidatorStore {
+	return &ValidatorStore{db: db, keystoresDir: filepath.Join(dir, "keystores"), passwordsDir: filepath.Join(dir, "passwords")}
+}
+
+func keystoreFileName(pubkey common.Bytes48) string {
+	return pubkey.Hex()
+}
+
+// PutKeystore stores the keystore and its password, both readable by the owner only.
+func (s *ValidatorStore) PutKeystore(pubkey common.Bytes48, keystore []byte, password string) error {
+	for _, d := range []string{s.keystoresDir, s.passwordsDir} {
+		if err := os.MkdirAll(d, 0700); err != nil {
+			return err
+		}
+	}
+	// the password goes first, a keystore without one would fail the next start
+	if err := dir.WriteFileWithFsync(filepath.Join(s.passwordsDir, keystoreFileName(pubkey)+".txt"), []byte(password), 0600); err != nil {
+		return err
+	}
+	return dir.WriteFileWithFsync(filepath.Join(s.keystoresDir, keystoreFileName(pubkey)+".json"), keystore, 0600)
+}
+
+func (s *ValidatorStore) DeleteKeystore(pubkey common.Bytes48) error {
+	if err := os.Remove(filepath.Join(s.keystoresDir, keystoreFileName(pubkey)+".json")); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Remove(filepath.Join(s.passwordsDir, keystoreFileName(pubkey)+".txt")); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Keystores returns the stored keystores, by public key.
+func (s *ValidatorStore) Keystores() (map[common.Bytes48]*Keystore, error) {
+	out := make(map[common.Bytes48]*Keystore)
+	entries, err := os.ReadDir(s.keystoresDir)
+	if os.IsNotExist(err) {
+		return out, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.keystoresDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		keystore, err := ParseKeystore(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid stored keystore %s: %w", entry.Name(), err)
+		}
+		pubkey, err := keystore.PublicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid stored keystore %s: %w", entry.Name(), err)
+		}
+		out[pubkey] = keystore
+	}
+	return out, nil
+}
+
+func (s *ValidatorStore) PutRemoteKey(ctx context.Context, pubkey common.Bytes48, url string) error {
+	return s.db.Update(ctx, func(tx kv.RwTx) error {
+		return tx.Put(kv.ValidatorRemoteKeys, pubkey[:], []byte(url))
+	})
+}
+
+func (s *ValidatorStore) DeleteRemoteKey(ctx context.Context, pubkey common.Bytes48) error {
+	return s.db.Update(ctx, func(tx kv.RwTx) error {
+		return tx.Delete(kv.ValidatorRemoteKeys, pubkey[:])
+	})
+}
+
+// RemoteKeys returns the urls of the remote signers, by public key.
+func (s *ValidatorStore) RemoteKeys(ctx context.Context) (map[common.Bytes48]string, error) {
+	out := make(map[common.Bytes48]string)
+	if err := s.db.View(ctx, func(tx kv.Tx) error {
+		return tx.ForEach(kv.ValidatorRemoteKeys, nil, func(k, v []byte) error {
+			out[common.Bytes48(k)] = string(v)
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PutSettings stores the settings of the validator, empty settings are removed.
+func (s *ValidatorStore) PutSettings(ctx context.Context, pubkey common.Bytes48, settings ValidatorSettings) error {
+	return s.db.Update(ctx, func(tx kv.RwTx) error {
+		if settings.empty() {
+			return tx.Delete(kv.ValidatorSettings, pubkey[:])
+		}
+		value, err := json.Marshal(settings)
+		if err != nil {
+			return err
+		}
+		return tx.Put(kv.ValidatorSettings, pubkey[:], value)
+	})
+}
+
+func (s *ValidatorStore) Settings(ctx context.Context) (map[common.Bytes48]ValidatorSettings, error) {
+	out := make(map[common.Bytes48]ValidatorSettings)
+	if err := s.db.View(ctx, func(tx kv.Tx) error {
+		return tx.ForEach(kv.ValidatorSettings, nil, func(k, v []byte) error {
+			var settings ValidatorSettings
+			if err := json.Unmarshal(v, &settings); err != nil {
+				return fmt.Errorf("invalid stored settings of %x: %w", k, err)
+			}
+			out[common.Bytes48(k)] = settings
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Signers decrypts the stored keystores and connects to the stored remote signers.
+func (s *ValidatorStore) Signers(ctx context.Context, beaconCfg *clparams.BeaconChainConfig) ([]Signer, error) {
+	remoteKeys, err := s.RemoteKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var signers []Signer
+	if _, err := os.Stat(s.keystoresDir); err == nil {
+		keys, err := LoadKeystores(s.keystoresDir, s.passwordsDir)
+		if err != nil {
+			return nil, fmt.Errorf("stored keystores: %w", err)
+		}
+		for _, key := range keys {
+			signers = append(signers, NewLocalSigner(key))
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	for pubkey, url := range remoteKeys {
+		signer, err := NewWeb3Signer(url, beaconCfg, pubkey)
+		if err != nil {
+			return nil, err
+		}
+		signers = append(signers, signer)
+	}
+	return signers, nil
+}
@@ -0,0 +1,375 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package validator_client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+)
+
+// ref: https://consensys.github.io/web3signer/web3signer-eth2.html
+const (
+	web3SignerPublicKeysPath = "/api/v1/eth2/publicKeys"
+	web3SignerSignPath       = "/api/v1/eth2/sign/"
+
+	// web3SignerTimeout bounds a signing round trip, a signature coming later than that is of no use for the duty.
+	web3SignerTimeout = 5 * time.Second
+)
+
+// web3SignerAggregateAndProofV2 is the type used for the aggregates, the legacy AGGREGATE_AND_PROOF one can not carry the
+// electra attestations.
+const web3SignerAggregateAndProofV2 SigningType = "AGGREGATE_AND_PROOF_V2"
+
+type web3SignerForkInfo struct {
+	Fork                  *cltypes.Fork `json:"fork"`
+	GenesisValidatorsRoot common.Hash   `json:"genesis_validators_root"`
+}
+
+type web3SignerBlock struct {
+	Version     string                     `json:"version"`
+	BlockHeader *cltypes.BeaconBlockHeader `json:"block_header"`
+}
+
+type web3SignerVersioned[T any] struct {
+	Version string `json:"version"`
+	Data    T      `json:"data"`
+}
+
+type web3SignerAggregationSlot struct {
+	Slot uint64 `json:"slot,string"`
+}
+
+type web3SignerRandaoReveal struct {
+	Epoch uint64 `json:"epoch,string"`
+}
+
+type web3SignerSyncCommitteeMessage struct {
+	BeaconBlockRoot common.Hash `json:"beacon_block_root"`
+	Slot            uint64      `json:"slot,string"`
+}
+
+// web3SignerRequest is the body of the sign endpoint: the signing root along with the object it was computed from,
+// so that the signer can apply its own slashing protection.
+type web3SignerRequest struct {
+	Type        SigningType         `json:"type"`
+	ForkInfo    *web3SignerForkInfo `json:"fork_info,omitempty"`
+	SigningRoot common.Hash         `json:"signingRoot"`
+
+	BeaconBlock                 *web3SignerBlock                                 `json:"beacon_block,omitempty"`
+	Attestation                 *solid.AttestationData                           `json:"attestation,omitempty"`
+	AggregationSlot             *web3SignerAggregationSlot                       `json:"aggregation_slot,omitempty"`
+	AggregateAndProof           *web3SignerVersioned[*cltypes.AggregateAndProof] `json:"aggregate_and_proof,omitempty"`
+	RandaoReveal                *web3SignerRandaoReveal                          `json:"randao_reveal,omitempty"`
+	SyncCommitteeMessage        *web3SignerSyncCommitteeMessage                  `json:"sync_committee_message,omitempty"`
+	SyncAggregatorSelectionData *cltypes.SyncAggregatorSelectionData             `json:"sync_aggregator_selection_data,omitempty"`
+	ContributionAndProof        *cltypes.ContributionAndProof                    `json:"contribution_and_proof,omitempty"`
+	ValidatorRegistration       *cltypes.ValidatorRegistrationMessage            `json:"validator_registration,omitempty"`
+}
+
+type web3SignerResponse struct {
+	Signature common.Bytes96 `json:"signature"`
+}
+
+var _ RemoteSigner = &web3Signer{}
+
+type web3Signer struct {
+	httpClient   *http.Client
+	url          *url.URL
+	beaconConfig *clparams.BeaconChainConfig
+	publicKey    common.Bytes48
+}
+
+// NewWeb3Signer creates a signer delegating the signatures of one validator to a Web3Signer compatible remote
+// signer: the secret key never reaches this host.
+func NewWeb3Signer(baseUrl string, beaconConfig *clparams.BeaconChainConfig, publicKey common.Bytes48) (Signer, error) {
+	u, err := url.Parse(baseUrl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid web3signer url %s: %w", baseUrl, err)
+	}
+	return &web3Signer{
+		httpClient:   &http.Client{Timeout: web3SignerTimeout},
+		url:          u,
+		beaconConfig: beaconConfig,
+		publicKey:    publicKey,
+	}, nil
+}
+
+func (s *web3Signer) PublicKey() common.Bytes48 {
+	return s.publicKey
+}
+
+func (s *web3Signer) URL() string {
+	return s.url.String()
+}
+
+func (s *web3Signer) Sign(ctx context.Context, req *SigningRequest) (common.Bytes96, error) {
+	body, err := s.signingBody(req)
+	if err != nil {
+		return common.Bytes96{}, err
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return common.Bytes96{}, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url.JoinPath(web3SignerSignPath, s.publicKey.Hex()).String(), bytes.NewReader(payload))
+	if err != nil {
+		return common.Bytes96{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return common.Bytes96{}, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return common.Bytes96{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		// 412 is the remote slashing protection refusing to sign
+		return common.Bytes96{}, fmt.Errorf("web3signer refused to sign %s for %s: status %d: %s", req.Type, s.publicKey, resp.StatusCode, bytes.TrimSpace(respBody))
+	}
+	return parseWeb3SignerSignature(resp.Header.Get("Content-Type"), respBody)
+}
+
+// parseWeb3SignerSignature handles both the json response and the plain text one of the older versions.
+func parseWeb3SignerSignature(contentType string, body []byte) (common.Bytes96, error) {
+	if strings.HasPrefix(contentType, "application/json") {
+		var resp web3SignerResponse
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return common.Bytes96{}, fmt.Errorf("invalid web3signer response: %w", err)
+		}
+		return resp.Signature, nil
+	}
+	var signature common.Bytes96
+	if err := signature.UnmarshalText(bytes.TrimSpace(body)); err != nil {
+		return common.Bytes96{}, fmt.Errorf("invalid web3signer response: %w", err)
+	}
+	return signature, nil
+}
+
+func (s *web3Signer) signingBody(req *SigningRequest) (*web3SignerRequest, error) {
+	body := &web3SignerRequest{Type: req.Type, SigningRoot: req.SigningRoot}
+	if req.Fork != nil {
+		body.ForkInfo = &web3SignerForkInfo{Fork: req.Fork, GenesisValidatorsRoot: req.GenesisValidatorsRoot}
+	}
+	invalid := func() error {
+		return fmt.Errorf("unexpected message %T for signing type %s", req.Message, req.Type)
+	}
+	switch req.Type {
+	case SigningTypeBlock:
+		var (
+			header  *cltypes.BeaconBlockHeader
+			version clparams.StateVersion
+		)
+		switch block := req.Message.(type) {
+		case *cltypes.BeaconBlock:
+			bodyRoot, err := block.Body.HashSSZ()
+			if err != nil {
+				return nil, err
+			}
+			header = &cltypes.BeaconBlockHeader{Slot: block.Slot, ProposerIndex: block.ProposerIndex, ParentRoot: block.ParentRoot, Root: block.StateRoot, BodyRoot: bodyRoot}
+			version = block.Version()
+		case *cltypes.BlindedBeaconBlock:
+			bodyRoot, err := block.Body.HashSSZ()
+			if err != nil {
+				return nil, err
+			}
+			header = &cltypes.BeaconBlockHeader{Slot: block.Slot, ProposerIndex: block.ProposerIndex, ParentRoot: block.ParentRoot, Root: block.StateRoot, BodyRoot: bodyRoot}
+			version = block.Version()
+		default:
+			return nil, invalid()
+		}
+		body.BeaconBlock = &web3SignerBlock{Version: strings.ToUpper(version.String()), BlockHeader: header}
+	case SigningTypeAttestation:
+		data, ok := req.Message.(*solid.AttestationData)
+		if !ok {
+			return nil, invalid()
+		}
+		body.Attestation = data
+	case SigningTypeAggregationSlot:
+		slot, ok := req.Message.(uint64)
+		if !ok {
+			return nil, invalid()
+		}
+		body.AggregationSlot = &web3SignerAggregationSlot{Slot: slot}
+	case SigningTypeAggregateAndProof:
+		aggregate, ok := req.Message.(*cltypes.AggregateAndProof)
+		if !ok {
+			return nil, invalid()
+		}
+		version := s.beaconConfig.GetCurrentStateVersion(req.Slot / s.beaconConfig.SlotsPerEpoch)
+		body.Type = web3SignerAggregateAndProofV2
+		body.AggregateAndProof = &web3SignerVersioned[*cltypes.AggregateAndProof]{Version: strings.ToUpper(version.String()), Data: aggregate}
+	case SigningTypeRandaoReveal:
+		epoch, ok := req.Message.(uint64)
+		if !ok {
+			return nil, invalid()
+		}
+		body.RandaoReveal = &web3SignerRandaoReveal{Epoch: epoch}
+	case SigningTypeSyncCommitteeMessage:
+		root, ok := req.Message.(common.Hash)
+		if !ok {
+			return nil, invalid()
+		}
+		body.SyncCommitteeMessage = &web3SignerSyncCommitteeMessage{BeaconBlockRoot: root, Slot: req.Slot}
+	case SigningTypeSyncCommitteeSelectionProof:
+		data, ok := req.Message.(*cltypes.SyncAggregatorSelectionData)
+		if !ok {
+			return nil, invalid()
+		}
+		body.SyncAggregatorSelectionData = data
+	case SigningTypeContributionAndProof:
+		contribution, ok := req.Message.(*cltypes.ContributionAndProof)
+		if !ok {
+			return nil, invalid()
+		}
+		body.ContributionAndProof = contribution
+	case SigningTypeValidatorRegistration:
+		registration, ok := req.Message.(*cltypes.ValidatorRegistrationMessage)
+		if !ok {
+			return nil, invalid()
+		}
+		// registrations are signed with the builder domain, which is not bound to any fork
+		body.ForkInfo = nil
+		body.ValidatorRegistration = registration
+	default:
+		return nil, fmt.Errorf("unsupported signing type %s", req.Type)
+	}
+	return body, nil
+}
+
+// ListWeb3SignerKeys returns the public keys the remote signer holds.
+func ListWeb3SignerKeys(ctx context.Context, baseUrl string) ([]common.Bytes48, error) {
+	u, err := url.Parse(baseUrl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid web3signer url %s: %w", baseUrl, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.JoinPath(web3SignerPublicKeysPath).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := (&http.Client{Timeout: web3SignerTimeout}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("could not list web3signer keys: status %d: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+	}
+	var keys []common.Bytes48
+	if err := json.NewDecoder(resp.Body).Decode(&keys); err != nil {
+		return nil, fmt.Errorf("invalid web3signer keys: %w", err)
+	}
+	return keys, nil
+}
+
+// Web3SignerServer serves the signing endpoints of Web3Signer out of local signers. It is a stand-in for a real
+// remote signer in tests and local setups, so it signs the given signing root without any protection of its own.
+type Web3SignerServer struct {
+	signers map[common.Bytes48]Signer
+	keys    []common.Bytes48
+}
+
+func NewWeb3SignerServer(signers []Signer) *Web3SignerServer {
+	s := &Web3SignerServer{signers: make(map[common.Bytes48]Signer, len(signers))}
+	for _, signer := range signers {
+		s.signers[signer.PublicKey()] = signer
+		s.keys = append(s.keys, signer.PublicKey())
+	}
+	return s
+}
+
+func (s *Web3SignerServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == web3SignerPublicKeysPath:
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(s.keys); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, web3SignerSignPath):
+		s.sign(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Web3SignerServer) sign(w http.ResponseWriter, r *http.Request) {
+	var pubkey common.Bytes48
+	if err := pubkey.UnmarshalText([]byte(strings.TrimPrefix(r.URL.Path, web3SignerSignPath))); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	signer, ok := s.signers[pubkey]
+	if !ok {
+		http.Error(w, "public key not found", http.StatusNotFound)
+		return
+	}
+	var req web3SignerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.ForkInfo == nil && req.Type != SigningTypeValidatorRegistration {
+		http.Error(w, "missing fork info", http.StatusBadRequest)
+		return
+	}
+	signature, err := signer.Sign(r.Context(), &SigningRequest{Type: req.Type, SigningRoot: req.SigningRoot})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if strings.Contains(r.Header.Get("Accept"), "application/json") {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(web3SignerResponse{Signature: signature})
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain")
+	w.Write([]byte(signature.Hex()))
+}
+
+// ParseWeb3SignerKeys parses a comma separated list of public keys.
+func ParseWeb3SignerKeys(list string) ([]common.Bytes48, error) {
+	var keys []common.Bytes48
+	for _, s := range strings.Split(list, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		var key common.Bytes48
+		if err := key.UnmarshalText([]byte(s)); err != nil {
+			return nil, fmt.Errorf("invalid public key %s: %w", s, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
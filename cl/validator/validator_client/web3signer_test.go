@@ -0,0 +1,136 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package validator_client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon/cl/clparams"
+	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/cltypes/solid"
+	"github.com/erigontech/erigon/cl/utils/bls"
+)
+
+func testSigningRequests() []*SigningRequest {
+	fork := &cltypes.Fork{PreviousVersion: common.Bytes4{3}, CurrentVersion: common.Bytes4{4}, Epoch: 10}
+	gvr := common.Hash{0xaa}
+	return []*SigningRequest{
+		{Type: SigningTypeRandaoReveal, SigningRoot: common.Hash{1}, Fork: fork, GenesisValidatorsRoot: gvr, Slot: 320, Message: uint64(10)},
+		{Type: SigningTypeAggregationSlot, SigningRoot: common.Hash{2}, Fork: fork, GenesisValidatorsRoot: gvr, Slot: 321, Message: uint64(321)},
+		{Type: SigningTypeAttestation, SigningRoot: common.Hash{3}, Fork: fork, GenesisValidatorsRoot: gvr, Slot: 320, Message: &solid.AttestationData{
+			Slot:   321,
+			Source: solid.Checkpoint{Epoch: 9},
+			Target: solid.Checkpoint{Epoch: 10},
+		}},
+		{Type: SigningTypeSyncCommitteeMessage, SigningRoot: common.Hash{4}, Fork: fork, GenesisValidatorsRoot: gvr, Slot: 321, Message: common.Hash{5}},
+		{Type: SigningTypeSyncCommitteeSelectionProof, SigningRoot: common.Hash{5}, Fork: fork, GenesisValidatorsRoot: gvr, Slot: 321, Message: &cltypes.SyncAggregatorSelectionData{Slot: 321, SubcommitteeIndex: 2}},
+		{Type: SigningTypeValidatorRegistration, SigningRoot: common.Hash{6}, Message: &cltypes.ValidatorRegistrationMessage{GasLimit: "36000000", Timestamp: "1"}},
+	}
+}
+
+func TestWeb3SignerMatchesLocalSigner(t *testing.T) {
+	ctx := context.Background()
+	key, err := bls.GenerateKey()
+	require.NoError(t, err)
+	local := NewLocalSigner(key)
+	server := httptest.NewServer(NewWeb3SignerServer([]Signer{local}))
+	defer server.Close()
+
+	keys, err := ListWeb3SignerKeys(ctx, server.URL)
+	require.NoError(t, err)
+	require.Equal(t, []common.Bytes48{local.PublicKey()}, keys)
+
+	remote, err := NewWeb3Signer(server.URL, &clparams.MainnetBeaconConfig, local.PublicKey())
+	require.NoError(t, err)
+	for _, req := range testSigningRequests() {
+		expected, err := local.Sign(ctx, req)
+		require.NoError(t, err)
+		signature, err := remote.Sign(ctx, req)
+		require.NoError(t, err, req.Type)
+		require.Equal(t, expected, signature, req.Type)
+	}
+
+	// keys the remote signer does not hold
+	unknown, err := NewWeb3Signer(server.URL, &clparams.MainnetBeaconConfig, common.Bytes48{1})
+	require.NoError(t, err)
+	_, err = unknown.Sign(ctx, testSigningRequests()[0])
+	require.Error(t, err)
+}
+
+func TestWeb3SignerRequestFormat(t *testing.T) {
+	ctx := context.Background()
+	pubkey := common.Bytes48{1}
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/eth2/sign/"+pubkey.Hex(), r.URL.Path)
+		raw, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		body = nil
+		require.NoError(t, json.Unmarshal(raw, &body))
+		// answer the way the older versions do
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(common.Bytes96{7}.Hex()))
+	}))
+	defer server.Close()
+
+	signer, err := NewWeb3Signer(server.URL, &clparams.MainnetBeaconConfig, pubkey)
+	require.NoError(t, err)
+	requests := testSigningRequests()
+
+	signature, err := signer.Sign(ctx, requests[0])
+	require.NoError(t, err)
+	require.Equal(t, common.Bytes96{7}, signature)
+	require.Equal(t, "RANDAO_REVEAL", body["type"])
+	require.Equal(t, common.Hash{1}.Hex(), body["signingRoot"])
+	require.Equal(t, map[string]any{
+		"fork": map[string]any{
+			"previous_version": "0x03000000",
+			"current_version":  "0x04000000",
+			"epoch":            "10",
+		},
+		"genesis_validators_root": common.Hash{0xaa}.Hex(),
+	}, body["fork_info"])
+	require.Equal(t, map[string]any{"epoch": "10"}, body["randao_reveal"])
+
+	_, err = signer.Sign(ctx, requests[2])
+	require.NoError(t, err)
+	require.Equal(t, "ATTESTATION", body["type"])
+	require.Equal(t, "321", body["attestation"].(map[string]any)["slot"])
+
+	_, err = signer.Sign(ctx, requests[3])
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"beacon_block_root": common.Hash{5}.Hex(), "slot": "321"}, body["sync_committee_message"])
+
+	// registrations are not bound to a fork
+	_, err = signer.Sign(ctx, requests[5])
+	require.NoError(t, err)
+	require.Equal(t, "VALIDATOR_REGISTRATION", body["type"])
+	require.NotContains(t, body, "fork_info")
+	require.Contains(t, body, "validator_registration")
+
+	// the message must match the signing type
+	_, err = signer.Sign(ctx, &SigningRequest{Type: SigningTypeAttestation, Message: uint64(1)})
+	require.Error(t, err)
+}
@@ -52,6 +52,7 @@ import (
 	"github.com/erigontech/erigon/cl/persistence/beacon_indicies"
 	"github.com/erigontech/erigon/cl/persistence/format/snapshot_format"
 	"github.com/erigontech/erigon/cl/persistence/format/snapshot_format/getters"
+	"github.com/erigontech/erigon/cl/persistence/genesisdb"
 	state_accessors "github.com/erigontech/erigon/cl/persistence/state"
 	"github.com/erigontech/erigon/cl/persistence/state/historical_states_reader"
 	"github.com/erigontech/erigon/cl/phase1/core/checkpoint_sync"
@@ -60,6 +61,7 @@ import (
 	"github.com/erigontech/erigon/cl/phase1/stages"
 	"github.com/erigontech/erigon/cl/rpc"
 	"github.com/erigontech/erigon/cl/utils/eth_clock"
+	"github.com/erigontech/erigon/cl/validator/validator_client"
 	"github.com/erigontech/erigon/cmd/caplin/caplin1"
 	"github.com/erigontech/erigon/eth/ethconfig"
 	"github.com/erigontech/erigon/eth/ethconfig/estimate"
@@ -83,6 +85,9 @@ var CLI struct {
 	CheckBlobsSnapshotsCount  CheckBlobsSnapshotsCount  `cmd:"" help:"check blobs snapshots count"`
 	DumpBlobsSnapshotsToStore DumpBlobsSnapshotsToStore `cmd:"" help:"dump blobs snapshots to store"`
 	DumpStateSnapshots        DumpStateSnapshots        `cmd:"" help:"dump state snapshots"`
+	ImportSlashingProtection  ImportSlashingProtection  `cmd:"" help:"import an EIP-3076 slashing protection interchange file into the built-in validator client"`
+	ExportSlashingProtection  ExportSlashingProtection  `cmd:"" help:"export the slashing protection of the built-in validator client as an EIP-3076 interchange file"`
+	StateDiff                 StateDiff                 `cmd:"" help:"diff the archived states of two slots"`
 }
 
 type chainCfg struct {
@@ -184,7 +189,7 @@ func (c *Chain) Run(ctx *Context) error {
 	}
 
 	downloader := network.NewBackwardBeaconDownloader(ctx, beacon, nil, nil, db)
-	cfg := stages.StageHistoryReconstruction(downloader, antiquary.NewAntiquary(ctx, nil, nil, nil, nil, dirs, nil, nil, nil, nil, nil, nil, nil, false, false, false, false, nil), csn, db, nil, beaconConfig, clparams.CaplinConfig{}, true, bRoot, bs.Slot(), "/tmp", 300*time.Millisecond, nil, nil, blobStorage, log.Root())
+	cfg := stages.StageHistoryReconstruction(downloader, antiquary.NewAntiquary(ctx, nil, nil, nil, nil, dirs, nil, nil, nil, nil, nil, nil, nil, false, false, false, false, nil), csn, db, nil, beaconConfig, clparams.CaplinConfig{}, true, bRoot, bs.Slot(), "/tmp", 300*time.Millisecond, nil, nil, blobStorage, nil, log.Root())
 	return stages.SpawnStageHistoryDownload(cfg, ctx, log.Root())
 }
 
@@ -550,33 +555,29 @@ type RetrieveHistoricalState struct {
 	Out         string `help:"output file" default:""`
 }
 
-func (r *RetrieveHistoricalState) Run(ctx *Context) error {
+// openHistoricalStatesReader opens the caplin database of the datadir and a reader of its archived states.
+func openHistoricalStatesReader(ctx context.Context, chain, datadirPath string) (kv.RwDB, *historical_states_reader.HistoricalStatesReader, error) {
 	vt := state_accessors.NewStaticValidatorTable()
-	_, beaconConfig, t, err := clparams.GetConfigsByNetworkName(r.Chain)
+	_, beaconConfig, t, err := clparams.GetConfigsByNetworkName(chain)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-	dirs := datadir.New(r.Datadir)
+	dirs := datadir.New(datadirPath)
 	db, _, err := caplin1.OpenCaplinDatabase(ctx, beaconConfig, nil, dirs.CaplinIndexing, dirs.CaplinBlobs, nil, false, 0)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-	log.Root().SetHandler(log.LvlFilterHandler(log.LvlDebug, log.StderrHandler))
-
-	tx, err := db.BeginRo(ctx)
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
 
 	freezingCfg := ethconfig.Defaults.Snapshot
-	freezingCfg.ChainName = r.Chain
+	freezingCfg.ChainName = chain
 	allSnapshots := freezeblocks.NewRoSnapshots(freezingCfg, dirs.Snap, 0, log.Root())
 	if err := allSnapshots.OpenFolder(); err != nil {
-		return err
+		return nil, nil, err
 	}
-	if err := state_accessors.ReadValidatorsTable(tx, vt); err != nil {
-		return err
+	if err := db.View(ctx, func(tx kv.Tx) error {
+		return state_accessors.ReadValidatorsTable(tx, vt)
+	}); err != nil {
+		return nil, nil, err
 	}
 
 	blockReader := freezeblocks.NewBlockReader(allSnapshots, nil, nil, nil)
@@ -584,32 +585,51 @@ func (r *RetrieveHistoricalState) Run(ctx *Context) error {
 	eth1Getter.SetBeaconChainConfig(beaconConfig)
 	csn := freezeblocks.NewCaplinSnapshots(freezingCfg, beaconConfig, dirs, log.Root())
 	if err := csn.OpenFolder(); err != nil {
-		return err
+		return nil, nil, err
 	}
 	snr := freezeblocks.NewBeaconSnapshotReader(csn, eth1Getter, beaconConfig)
 	gSpot, err := initial_state.GetGenesisState(t)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	snTypes := snapshotsync.MakeCaplinStateSnapshotsTypes(db)
 	stateSn := snapshotsync.NewCaplinStateSnapshots(freezingCfg, beaconConfig, dirs, snTypes, log.Root())
 	if err := stateSn.OpenFolder(); err != nil {
-		return err
+		return nil, nil, err
 	}
 	if _, err := antiquary.FillStaticValidatorsTableIfNeeded(ctx, log.Root(), stateSn, vt); err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	bs, err := checkpoint_sync.NewRemoteCheckpointSync(beaconConfig, t).GetLatestBeaconState(ctx)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 	sn := synced_data.NewSyncedDataManager(beaconConfig, true)
 	sn.OnHeadState(bs)
 
+	return db, historical_states_reader.NewHistoricalStatesReader(beaconConfig, snr, vt, gSpot, stateSn, sn), nil
+}
+
+func (r *RetrieveHistoricalState) Run(ctx *Context) error {
+	_, beaconConfig, _, err := clparams.GetConfigsByNetworkName(r.Chain)
+	if err != nil {
+		return err
+	}
+	log.Root().SetHandler(log.LvlFilterHandler(log.LvlDebug, log.StderrHandler))
+	db, hr, err := openHistoricalStatesReader(ctx, r.Chain, r.Datadir)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginRo(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	r.withPPROF.withProfile()
-	hr := historical_states_reader.NewHistoricalStatesReader(beaconConfig, snr, vt, gSpot, stateSn, sn)
 	start := time.Now()
 	haveState, err := hr.ReadHistoricalState(ctx, tx, r.CompareSlot)
 	if err != nil {
@@ -1294,3 +1314,125 @@ func (c *DumpStateSnapshots) Run(ctx *Context) error {
 
 	return nil
 }
+
+// slashingProtectionGenesisValidatorsRoot reads the genesis validators root of the chain Caplin synced in dirs, which
+// the interchange files are bound to.
+func slashingProtectionGenesisValidatorsRoot(beaconConfig *clparams.BeaconChainConfig, dirs datadir.Dirs) (common.Hash, error) {
+	genesisState, err := genesisdb.NewGenesisDB(beaconConfig, dirs.CaplinGenesis).ReadGenesisState()
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("could not read genesis state, has caplin been run on this datadir? %w", err)
+	}
+	return genesisState.GenesisValidatorsRoot(), nil
+}
+
+type ImportSlashingProtection struct {
+	chainCfg
+	outputFolder
+
+	File string `name:"file" help:"EIP-3076 interchange file" type:"existingfile" required:""`
+}
+
+func (c *ImportSlashingProtection) Run(ctx *Context) error {
+	beaconConfig, err := c.configs()
+	if err != nil {
+		return err
+	}
+	dirs := datadir.New(c.Datadir)
+	genesisValidatorsRoot, err := slashingProtectionGenesisValidatorsRoot(beaconConfig, dirs)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(c.File)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	db, err := caplin1.OpenSlashingProtectionDatabase(ctx, dirs.CaplinValidator)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	if err := validator_client.NewSlashingProtection(db).ImportInterchange(ctx, f, genesisValidatorsRoot); err != nil {
+		return err
+	}
+	log.Info("Imported slashing protection", "file", c.File)
+	return nil
+}
+
+type ExportSlashingProtection struct {
+	chainCfg
+	outputFolder
+
+	File string `name:"file" help:"EIP-3076 interchange file to write" required:""`
+}
+
+func (c *ExportSlashingProtection) Run(ctx *Context) error {
+	beaconConfig, err := c.configs()
+	if err != nil {
+		return err
+	}
+	dirs := datadir.New(c.Datadir)
+	genesisValidatorsRoot, err := slashingProtectionGenesisValidatorsRoot(beaconConfig, dirs)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(c.File)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	db, err := caplin1.OpenSlashingProtectionDatabase(ctx, dirs.CaplinValidator)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	if err := validator_client.NewSlashingProtection(db).ExportInterchange(ctx, f, genesisValidatorsRoot); err != nil {
+		return err
+	}
+	log.Info("Exported slashing protection", "file", c.File)
+	return nil
+}
+
+type StateDiff struct {
+	chainCfg
+	outputFolder
+
+	From uint64 `name:"from" help:"slot of the source state" required:""`
+	To   uint64 `name:"to" help:"slot of the target state" required:""`
+	Out  string `name:"out" help:"output file, the diff is printed when empty" default:""`
+}
+
+func (c *StateDiff) Run(ctx *Context) error {
+	db, hr, err := openHistoricalStatesReader(ctx, c.Chain, c.Datadir)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.BeginRo(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	diff, err := hr.ReadStateDiff(ctx, tx, c.From, c.To)
+	if err != nil {
+		return err
+	}
+	out := os.Stdout
+	if c.Out != "" {
+		if out, err = os.Create(c.Out); err != nil {
+			return err
+		}
+		defer out.Close()
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(diff); err != nil {
+		return err
+	}
+	log.Info("State diff", "from", c.From, "to", c.To, "fields", len(diff.Fields), "validators", len(diff.Validators), "balances", len(diff.Balances))
+	return nil
+}
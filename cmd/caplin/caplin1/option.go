@@ -17,6 +17,9 @@
 package caplin1
 
 import (
+	"fmt"
+	"math/big"
+
 	"github.com/erigontech/erigon/cl/beacon/builder"
 	"github.com/erigontech/erigon/cl/clparams"
 )
@@ -25,10 +28,22 @@ type option struct {
 	builderClient builder.BuilderClient
 }
 
-type CaplinOption func(*option)
+type CaplinOption func(*option) error
 
-func WithBuilder(mevRelayUrl string, beaconConfig *clparams.BeaconChainConfig) CaplinOption {
-	return func(o *option) {
-		o.builderClient = builder.NewBlockBuilderClient(mevRelayUrl, beaconConfig)
+func WithBuilder(config clparams.CaplinConfig, beaconConfig *clparams.BeaconChainConfig) CaplinOption {
+	return func(o *option) error {
+		var minBid *big.Int
+		if config.MevMinBid != "" {
+			var err error
+			if minBid, err = builder.ParseEther(config.MevMinBid); err != nil {
+				return fmt.Errorf("invalid mev min bid: %w", err)
+			}
+		}
+		builderClient, err := builder.NewMultiRelayClient(config.RelayUrls(), beaconConfig, config.MevRelayTimeout, minBid)
+		if err != nil {
+			return err
+		}
+		o.builderClient = builderClient
+		return nil
 	}
 }
@@ -23,6 +23,7 @@ import (
 	"math"
 	"os"
 	"path"
+	"path/filepath"
 	"time"
 
 	"google.golang.org/grpc/credentials"
@@ -35,20 +36,25 @@ import (
 	"github.com/erigontech/erigon/cl/aggregation"
 	"github.com/erigontech/erigon/cl/antiquary"
 	"github.com/erigontech/erigon/cl/beacon"
+	"github.com/erigontech/erigon/cl/beacon/beacon_router_configuration"
 	"github.com/erigontech/erigon/cl/beacon/beaconevents"
 	"github.com/erigontech/erigon/cl/beacon/handler"
 	"github.com/erigontech/erigon/cl/beacon/synced_data"
 	"github.com/erigontech/erigon/cl/clparams/initial_state"
 	"github.com/erigontech/erigon/cl/cltypes"
+	"github.com/erigontech/erigon/cl/das"
 	"github.com/erigontech/erigon/cl/rpc"
 	"github.com/erigontech/erigon/cl/sentinel"
 	"github.com/erigontech/erigon/cl/sentinel/service"
 	"github.com/erigontech/erigon/cl/utils/eth_clock"
 	"github.com/erigontech/erigon/cl/validator/attestation_producer"
 	"github.com/erigontech/erigon/cl/validator/committee_subscription"
+	"github.com/erigontech/erigon/cl/validator/keymanager"
 	"github.com/erigontech/erigon/cl/validator/sync_contribution_pool"
+	"github.com/erigontech/erigon/cl/validator/validator_client"
 	"github.com/erigontech/erigon/cl/validator/validator_params"
 	"github.com/erigontech/erigon/eth/ethconfig"
+	"github.com/erigontech/erigon/p2p/enode"
 	"github.com/erigontech/erigon/params"
 	"github.com/erigontech/erigon/turbo/snapshotsync"
 	"github.com/erigontech/erigon/turbo/snapshotsync/freezeblocks"
@@ -57,11 +63,13 @@ import (
 
 	"github.com/erigontech/erigon/cl/persistence/beacon_indicies"
 	"github.com/erigontech/erigon/cl/persistence/blob_storage"
+	"github.com/erigontech/erigon/cl/persistence/depositdb"
 	"github.com/erigontech/erigon/cl/persistence/format/snapshot_format"
 	"github.com/erigontech/erigon/cl/persistence/genesisdb"
 	state_accessors "github.com/erigontech/erigon/cl/persistence/state"
 	"github.com/erigontech/erigon/cl/persistence/state/historical_states_reader"
 	"github.com/erigontech/erigon/cl/phase1/core/checkpoint_sync"
+	"github.com/erigontech/erigon/cl/phase1/core/deposit_tree"
 	"github.com/erigontech/erigon/cl/phase1/core/state"
 	"github.com/erigontech/erigon/cl/phase1/execution_client"
 	"github.com/erigontech/erigon/cl/phase1/forkchoice"
@@ -74,7 +82,9 @@ import (
 
 	"github.com/erigontech/erigon/cl/utils/bls"
 
+	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/common/datadir"
+	sentinelrpc "github.com/erigontech/erigon-lib/gointerfaces/sentinelproto"
 	"github.com/erigontech/erigon-lib/kv"
 	"github.com/erigontech/erigon-lib/kv/mdbx"
 	"github.com/erigontech/erigon/cl/clparams"
@@ -128,6 +138,98 @@ func OpenCaplinDatabase(ctx context.Context,
 	return db, blob_storage.NewBlobStore(blobDB, afero.NewBasePathFs(afero.NewOsFs(), blobDir), blobPruneDistance, beaconConfig, ethClock), nil
 }
 
+// OpenSlashingProtectionDatabase opens the slashing protection database of the built-in validator client. Unlike the
+// other databases of Caplin, it must never be wiped.
+func OpenSlashingProtectionDatabase(ctx context.Context, dir string) (kv.RwDB, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	db, err := mdbx.New(kv.ValidatorDB, log.New()).Path(dir).Open(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not open the slashing protection database: %w", err)
+	}
+	go func() {
+		<-ctx.Done()
+		db.Close()
+	}()
+	return db, nil
+}
+
+func startValidatorClient(ctx context.Context, logger log.Logger, config clparams.CaplinConfig, dirs datadir.Dirs,
+	beaconConfig *clparams.BeaconChainConfig, ethClock eth_clock.EthereumClock, apiHandler *handler.ApiHandler) error {
+	var signers []validator_client.Signer
+	if config.ValidatorKeystoresDir != "" {
+		keys, err := validator_client.LoadKeystores(config.ValidatorKeystoresDir, config.ValidatorPasswordsPath)
+		if err != nil {
+			return fmt.Errorf("could not load validator keystores: %w", err)
+		}
+		for _, key := range keys {
+			signers = append(signers, validator_client.NewLocalSigner(key))
+		}
+	}
+	if config.ValidatorWeb3SignerUrl != "" {
+		pubkeys, err := validator_client.ParseWeb3SignerKeys(config.ValidatorWeb3SignerKeys)
+		if err != nil {
+			return err
+		}
+		if len(pubkeys) == 0 {
+			if pubkeys, err = validator_client.ListWeb3SignerKeys(ctx, config.ValidatorWeb3SignerUrl); err != nil {
+				return fmt.Errorf("could not list the keys of the remote signer: %w", err)
+			}
+		}
+		for _, pubkey := range pubkeys {
+			signer, err := validator_client.NewWeb3Signer(config.ValidatorWeb3SignerUrl, beaconConfig, pubkey)
+			if err != nil {
+				return err
+			}
+			signers = append(signers, signer)
+		}
+		log.Info("[Validator Client] Using remote signer", "url", config.ValidatorWeb3SignerUrl, "keys", len(pubkeys))
+	}
+	if config.ValidatorFeeRecipient == (common.Address{}) {
+		log.Warn("[Validator Client] Fee recipient not set, the rewards of the proposed blocks will be burnt")
+	}
+	var graffiti common.Hash
+	copy(graffiti[:], config.ValidatorGraffiti)
+
+	validatorDB, err := OpenSlashingProtectionDatabase(ctx, dirs.CaplinValidator)
+	if err != nil {
+		return err
+	}
+	slashingProtection := validator_client.NewSlashingProtection(validatorDB)
+	// the validators and settings added through the keymanager API
+	store := validator_client.NewValidatorStore(validatorDB, dirs.CaplinValidator)
+	storedSigners, err := store.Signers(ctx, beaconConfig)
+	if err != nil {
+		return fmt.Errorf("could not load the stored validators: %w", err)
+	}
+	signers = append(signers, storedSigners...)
+	settings, err := store.Settings(ctx)
+	if err != nil {
+		return fmt.Errorf("could not load the stored validator settings: %w", err)
+	}
+
+	vc := validator_client.NewValidatorClient(ctx, logger, beaconConfig, ethClock, apiHandler, slashingProtection, signers,
+		config.ValidatorFeeRecipient, graffiti, config.BeaconAPIRouter.Builder)
+	for pubkey, s := range settings {
+		vc.SetSettings(pubkey, s)
+	}
+	if config.KeymanagerAddr != "" {
+		jwtSecretPath := config.KeymanagerJwtSecretPath
+		if jwtSecretPath == "" {
+			jwtSecretPath = filepath.Join(dirs.CaplinValidator, "keymanager-jwt.hex")
+		}
+		jwtSecret, err := keymanager.ObtainJWTSecret(jwtSecretPath)
+		if err != nil {
+			return err
+		}
+		keymanagerAPI := keymanager.NewKeymanagerAPI(logger, beaconConfig, ethClock.GenesisValidatorsRoot(), vc, slashingProtection, store, config.ValidatorWeb3SignerUrl)
+		go beacon.ListenAndServeKeymanager(keymanagerAPI, config.KeymanagerAddr, jwtSecret)
+	}
+	go vc.Start()
+	return nil
+}
+
 func RunCaplinService(ctx context.Context, engine execution_client.ExecutionEngine, config clparams.CaplinConfig,
 	dirs datadir.Dirs, eth1Getter snapshot_format.ExecutionBlockReaderByNumber,
 	snDownloader proto_downloader.DownloaderClient, creds credentials.TransportCredentials, snBuildSema *semaphore.Weighted) error {
@@ -200,7 +302,12 @@ func RunCaplinService(ctx context.Context, engine execution_client.ExecutionEngi
 		}
 	}
 
-	state, err := checkpoint_sync.ReadOrFetchLatestBeaconState(ctx, dirs, beaconConfig, config, genesisDb)
+	depositDb := depositdb.NewDepositDB(dirs.CaplinLatest)
+	state, err := checkpoint_sync.ReadOrFetchLatestBeaconState(ctx, dirs, beaconConfig, config, genesisDb, depositDb)
+	if err != nil {
+		return err
+	}
+	depositTracker, err := deposit_tree.NewTracker(depositDb)
 	if err != nil {
 		return err
 	}
@@ -219,7 +326,7 @@ func RunCaplinService(ctx context.Context, engine execution_client.ExecutionEngi
 	caplinOptions := []CaplinOption{}
 	if config.BeaconAPIRouter.Builder {
 		if config.RelayUrlExist() {
-			caplinOptions = append(caplinOptions, WithBuilder(config.MevRelayUrl, beaconConfig))
+			caplinOptions = append(caplinOptions, WithBuilder(config, beaconConfig))
 		} else {
 			log.Warn("builder api enable but relay url not set. Skipping builder mode")
 			config.BeaconAPIRouter.Builder = false
@@ -236,7 +343,9 @@ func RunCaplinService(ctx context.Context, engine execution_client.ExecutionEngi
 
 	option := &option{}
 	for _, opt := range caplinOptions {
-		opt(option)
+		if err := opt(option); err != nil {
+			return err
+		}
 	}
 
 	logger := log.New("app", "caplin")
@@ -312,12 +421,29 @@ func RunCaplinService(ctx context.Context, engine execution_client.ExecutionEngi
 	if err != nil {
 		return err
 	}
+	if beaconConfig.FuluForkEpoch != math.MaxUint64 {
+		// the columns to custody depend on the node id, which is only known once the sentinel is up.
+		identity, err := sentinel.Identity(ctx, &sentinelrpc.EmptyMessage{})
+		if err != nil {
+			return err
+		}
+		node, err := enode.Parse(enode.ValidSchemes, identity.Enr)
+		if err != nil {
+			return err
+		}
+		custodyColumns, err := das.CustodyColumns(beaconConfig, node.ID(), das.CustodyGroupCount(beaconConfig, config.SubscribeAllTopics))
+		if err != nil {
+			return err
+		}
+		forkChoice.SetCustodyColumns(custodyColumns)
+	}
 	beaconRpc := rpc.NewBeaconRpcP2P(ctx, sentinel, beaconConfig, ethClock)
 	committeeSub := committee_subscription.NewCommitteeSubscribeManagement(ctx, indexDB, beaconConfig, networkConfig, ethClock, sentinel, aggregationPool, syncedDataManager)
 	batchSignatureVerifier := services.NewBatchSignatureVerifier(ctx, sentinel)
 	// Define gossip services
 	blockService := services.NewBlockService(ctx, indexDB, forkChoice, syncedDataManager, ethClock, beaconConfig, emitters)
 	blobService := services.NewBlobSidecarService(ctx, beaconConfig, forkChoice, syncedDataManager, ethClock, emitters, false)
+	dataColumnService := services.NewDataColumnSidecarService(beaconConfig, forkChoice, syncedDataManager, ethClock, false)
 	syncCommitteeMessagesService := services.NewSyncCommitteeMessagesService(beaconConfig, ethClock, syncedDataManager, syncContributionPool, batchSignatureVerifier, false)
 	attestationService := services.NewAttestationService(ctx, forkChoice, committeeSub, ethClock, syncedDataManager, beaconConfig, networkConfig, emitters, batchSignatureVerifier)
 	syncContributionService := services.NewSyncContributionService(syncedDataManager, beaconConfig, syncContributionPool, ethClock, emitters, batchSignatureVerifier, false)
@@ -332,7 +458,7 @@ func RunCaplinService(ctx context.Context, engine execution_client.ExecutionEngi
 
 	// Create the gossip manager
 	gossipManager := network.NewGossipReceiver(sentinel, forkChoice, beaconConfig, networkConfig, ethClock, emitters, committeeSub,
-		blockService, blobService, syncCommitteeMessagesService, syncContributionService, aggregateAndProofService,
+		blockService, blobService, dataColumnService, syncCommitteeMessagesService, syncContributionService, aggregateAndProofService,
 		attestationService, voluntaryExitService, blsToExecutionChangeService, proposerSlashingService)
 	{ // start ticking forkChoice
 		go func() {
@@ -413,8 +539,8 @@ func RunCaplinService(ctx context.Context, engine execution_client.ExecutionEngi
 
 	statesReader := historical_states_reader.NewHistoricalStatesReader(beaconConfig, rcsn, vTables, genesisState, stateSnapshots, syncedDataManager)
 	validatorParameters := validator_params.NewValidatorParams()
-	if config.BeaconAPIRouter.Active {
-		apiHandler := handler.NewApiHandler(
+	newApiHandler := func(routerCfg *beacon_router_configuration.RouterConfiguration) *handler.ApiHandler {
+		return handler.NewApiHandler(
 			logger,
 			networkConfig,
 			ethClock,
@@ -427,7 +553,7 @@ func RunCaplinService(ctx context.Context, engine execution_client.ExecutionEngi
 			statesReader,
 			sentinel,
 			params.GitTag,
-			&config.BeaconAPIRouter,
+			routerCfg,
 			emitters,
 			blobStorage,
 			csn,
@@ -447,12 +573,30 @@ func RunCaplinService(ctx context.Context, engine execution_client.ExecutionEngi
 			option.builderClient,
 			stateSnapshots,
 			true,
+			depositTracker,
 		)
+	}
+	if config.BeaconAPIRouter.Active {
+		apiHandler := newApiHandler(&config.BeaconAPIRouter)
 		go beacon.ListenAndServe(&beacon.LayeredBeaconHandler{
 			ArchiveApi: apiHandler,
 		}, config.BeaconAPIRouter)
 		log.Info("Beacon API started", "addr", config.BeaconAPIRouter.Address)
 	}
+	if config.ValidatorClientEnabled() {
+		// the validator client has its own handler, never served over the network, so that it does not depend on
+		// which endpoints are exposed.
+		validatorRouterCfg := &beacon_router_configuration.RouterConfiguration{
+			Beacon:    true,
+			Builder:   config.BeaconAPIRouter.Builder,
+			Config:    true,
+			Node:      true,
+			Validator: true,
+		}
+		if err := startValidatorClient(ctx, logger, config, dirs, beaconConfig, ethClock, newApiHandler(validatorRouterCfg)); err != nil {
+			return err
+		}
+	}
 
 	stageCfg := stages.ClStagesCfg(
 		beaconRpc,
@@ -473,6 +617,7 @@ func RunCaplinService(ctx context.Context, engine execution_client.ExecutionEngi
 		emitters,
 		blobStorage,
 		attestationProducer,
+		depositTracker,
 	)
 	sync := stages.ConsensusClStages(ctx, stageCfg)
 
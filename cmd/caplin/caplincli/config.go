@@ -49,11 +49,26 @@ type CaplinCliCfg struct {
 	EngineAPIAddr         string        `json:"engine_api_addr"`
 	EngineAPIPort         int           `json:"engine_api_port"`
 	MevRelayUrl           string        `json:"mev_relay_url"`
+	MevRelayTimeout       time.Duration `json:"mev_relay_timeout"`
+	MevMinBid             string        `json:"mev_min_bid"`
 	CustomConfig          string        `json:"custom_config"`
 	CustomGenesisState    string        `json:"custom_genesis_state"`
 	MaxPeerCount          uint64        `json:"max_peer_count"`
 	JwtSecret             []byte
 
+	ValidatorKeystoresDir   string         `json:"validator_keystores_dir"`
+	ValidatorPasswordsPath  string         `json:"validator_passwords_path"`
+	ValidatorWeb3SignerUrl  string         `json:"validator_web3signer_url"`
+	ValidatorWeb3SignerKeys string         `json:"validator_web3signer_keys"`
+	ValidatorFeeRecipient   common.Address `json:"validator_fee_recipient"`
+	ValidatorGraffiti       string         `json:"validator_graffiti"`
+	KeymanagerAddr          string         `json:"keymanager_addr"`
+	KeymanagerJwtSecretPath string         `json:"keymanager_jwt_secret_path"`
+
+	CheckpointSyncStateFile string      `json:"checkpoint_sync_state_file"`
+	CheckpointSyncBlockFile string      `json:"checkpoint_sync_block_file"`
+	CheckpointSyncBlockRoot common.Hash `json:"checkpoint_sync_block_root"`
+
 	AllowedMethods   []string `json:"allowed_methods"`
 	AllowedOrigins   []string `json:"allowed_origins"`
 	AllowCredentials bool     `json:"allow_credentials"`
@@ -100,10 +115,25 @@ func SetupCaplinCli(ctx *cli.Context) (cfg *CaplinCliCfg, err error) {
 	if checkpointUrls := ctx.StringSlice(utils.CaplinCheckpointSyncUrlFlag.Name); len(checkpointUrls) > 0 {
 		clparams.ConfigurableCheckpointsURLs = checkpointUrls
 	}
+	cfg.CheckpointSyncStateFile = ctx.String(utils.CaplinCheckpointSyncStateFileFlag.Name)
+	cfg.CheckpointSyncBlockFile = ctx.String(utils.CaplinCheckpointSyncBlockFileFlag.Name)
+	cfg.CheckpointSyncBlockRoot = common.HexToHash(ctx.String(utils.CaplinCheckpointSyncBlockRootFlag.Name))
 
 	cfg.Chaindata = ctx.String(caplinflags.ChaindataFlag.Name)
 
 	cfg.MevRelayUrl = ctx.String(caplinflags.MevRelayUrl.Name)
+	cfg.MevRelayTimeout = ctx.Duration(utils.CaplinMevRelayTimeoutFlag.Name)
+	cfg.MevMinBid = ctx.String(utils.CaplinMevMinBidFlag.Name)
+
+	// Built-in validator client
+	cfg.ValidatorKeystoresDir = ctx.String(utils.CaplinValidatorKeystoresDirFlag.Name)
+	cfg.ValidatorPasswordsPath = ctx.String(utils.CaplinValidatorPasswordsFlag.Name)
+	cfg.ValidatorWeb3SignerUrl = ctx.String(utils.CaplinValidatorWeb3SignerUrlFlag.Name)
+	cfg.ValidatorWeb3SignerKeys = ctx.String(utils.CaplinValidatorWeb3SignerKeysFlag.Name)
+	cfg.ValidatorFeeRecipient = common.HexToAddress(ctx.String(utils.CaplinValidatorFeeRecipientFlag.Name))
+	cfg.ValidatorGraffiti = ctx.String(utils.CaplinValidatorGraffitiFlag.Name)
+	cfg.KeymanagerAddr = ctx.String(utils.CaplinKeymanagerAddrFlag.Name)
+	cfg.KeymanagerJwtSecretPath = ctx.String(utils.CaplinKeymanagerJwtSecretFlag.Name)
 
 	// Custom Chain
 	cfg.CustomConfig = ctx.String(caplinflags.CustomConfig.Name)
@@ -33,6 +33,8 @@ var CliFlags = []cli.Flag{
 	&EngineApiHostFlag,
 	&EngineApiPortFlag,
 	&MevRelayUrl,
+	&utils.CaplinMevRelayTimeoutFlag,
+	&utils.CaplinMevMinBidFlag,
 	&JwtSecret,
 	&CustomConfig,
 	&CustomGenesisState,
@@ -41,7 +43,18 @@ var CliFlags = []cli.Flag{
 	&utils.BeaconApiAllowMethodsFlag,
 	&utils.BeaconApiAllowOriginsFlag,
 	&utils.CaplinCheckpointSyncUrlFlag,
+	&utils.CaplinCheckpointSyncStateFileFlag,
+	&utils.CaplinCheckpointSyncBlockFileFlag,
+	&utils.CaplinCheckpointSyncBlockRootFlag,
 	&utils.CaplinMaxPeerCount,
+	&utils.CaplinValidatorKeystoresDirFlag,
+	&utils.CaplinValidatorPasswordsFlag,
+	&utils.CaplinValidatorWeb3SignerUrlFlag,
+	&utils.CaplinValidatorWeb3SignerKeysFlag,
+	&utils.CaplinValidatorFeeRecipientFlag,
+	&utils.CaplinValidatorGraffitiFlag,
+	&utils.CaplinKeymanagerAddrFlag,
+	&utils.CaplinKeymanagerJwtSecretFlag,
 }
 
 var (
@@ -104,7 +117,7 @@ var (
 	}
 	MevRelayUrl = cli.StringFlag{
 		Name:  "mev-relay-url",
-		Usage: "Http URL of the MEV relay",
+		Usage: "Http URL of the MEV relay, or comma separated list of URLs",
 		Value: "",
 	}
 	CustomConfig = cli.StringFlag{
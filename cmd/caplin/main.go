@@ -112,9 +112,22 @@ func runCaplinNode(cliCtx *cli.Context) error {
 		BeaconAPIRouter:           rcfg,
 		NetworkId:                 networkId,
 		MevRelayUrl:               cfg.MevRelayUrl,
+		CheckpointSyncStateFile:   cfg.CheckpointSyncStateFile,
+		CheckpointSyncBlockFile:   cfg.CheckpointSyncBlockFile,
+		CheckpointSyncBlockRoot:   cfg.CheckpointSyncBlockRoot,
+		MevRelayTimeout:           cfg.MevRelayTimeout,
+		MevMinBid:                 cfg.MevMinBid,
 		CustomConfigPath:          cfg.CustomConfig,
 		CustomGenesisStatePath:    cfg.CustomGenesisState,
 		MaxPeerCount:              cfg.MaxPeerCount,
+		ValidatorKeystoresDir:     cfg.ValidatorKeystoresDir,
+		ValidatorPasswordsPath:    cfg.ValidatorPasswordsPath,
+		ValidatorWeb3SignerUrl:    cfg.ValidatorWeb3SignerUrl,
+		ValidatorWeb3SignerKeys:   cfg.ValidatorWeb3SignerKeys,
+		ValidatorFeeRecipient:     cfg.ValidatorFeeRecipient,
+		ValidatorGraffiti:         cfg.ValidatorGraffiti,
+		KeymanagerAddr:            cfg.KeymanagerAddr,
+		KeymanagerJwtSecretPath:   cfg.KeymanagerJwtSecretPath,
 		MaxInboundTrafficPerPeer:  datasize.MB,
 		MaxOutboundTrafficPerPeer: datasize.MB,
 	}, cfg.Dirs, nil, nil, nil, blockSnapBuildSema)
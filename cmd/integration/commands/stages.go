@@ -1153,6 +1153,13 @@ func stageCustomTrace(db kv.TemporalRwDB, ctx context.Context, logger log.Logger
 		if cfg.Produce.TraceTo {
 			tables = append(tables, db.Debug().InvertedIdxTables(kv.TracesToIdx)...)
 		}
+		if cfg.Produce.TokenTransferFrom {
+			tables = append(tables, db.Debug().InvertedIdxTables(kv.TokenTransfersFromIdx)...)
+		}
+		if cfg.Produce.TokenTransferTo {
+			tables = append(tables, db.Debug().InvertedIdxTables(kv.TokenTransfersToIdx)...)
+		}
+		tables = append(tables, db.Debug().InvertedIdxTables(cfg.Produce.Custom...)...)
 		if err := backup.ClearTables(ctx, tx, tables...); err != nil {
 			return err
 		}
@@ -66,11 +66,13 @@ type HttpCfg struct {
 	WebsocketCompression              bool
 	WebsocketSubscribeLogsChannelSize int
 	RpcAllowListFilePath              string
+	RpcRateLimitsFilePath             string
 	RpcBatchConcurrency               uint
 	RpcStreamingDisable               bool
 	RpcFiltersConfig                  rpchelper.FiltersConfig
 	DBReadConcurrency                 int
-	TraceCompatibility                bool // Bug for bug compatibility for trace_ routines with OpenEthereum
+	TraceCompatibility                bool   // Bug for bug compatibility for trace_ routines with OpenEthereum
+	TraceDir                          string // Where debug_standardTrace*ToFile dumps are written, <datadir>/traces if empty
 	TxPoolApiAddr                     string
 	StateCache                        kvcache.CoherentConfig
 	Snap                              ethconfig.BlocksFreezing
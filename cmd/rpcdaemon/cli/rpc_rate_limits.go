@@ -0,0 +1,65 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon/rpc"
+)
+
+// parseRateLimitsForRPC reads the rate limits file, e.g.
+//
+//	{
+//	  "identity": "header",
+//	  "header": "X-Api-Key",
+//	  "limits": [
+//	    {"method": "debug_trace*", "rate": 1, "burst": 2},
+//	    {"method": "eth_getLogs", "rate": 10, "burst": 20}
+//	  ]
+//	}
+func parseRateLimitsForRPC(path string) (*rpc.RateLimits, error) {
+	path = strings.TrimSpace(path)
+	if path == "" { // no file is provided
+		return nil, nil
+	}
+
+	fileContents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rateLimits rpc.RateLimits
+	if err := json.Unmarshal(fileContents, &rateLimits); err != nil {
+		return nil, err
+	}
+	if rateLimits.JwtSecretPath != "" {
+		data, err := os.ReadFile(rateLimits.JwtSecretPath)
+		if err != nil {
+			return nil, err
+		}
+		rateLimits.JwtSecret = common.FromHex(strings.TrimSpace(string(data)))
+		if len(rateLimits.JwtSecret) == 0 {
+			return nil, fmt.Errorf("invalid JWT secret in %s", rateLimits.JwtSecretPath)
+		}
+	}
+	return &rateLimits, nil
+}
@@ -3,8 +3,10 @@ package graph
 import (
 	"encoding/hex"
 	"fmt"
+	"math"
 	"reflect"
 	"strconv"
+	"strings"
 
 	"github.com/erigontech/erigon-lib/common"
 	hexutil2 "github.com/erigontech/erigon-lib/common/hexutil"
@@ -14,6 +16,8 @@ import (
 	"github.com/erigontech/erigon-lib/common/hexutil"
 
 	"github.com/erigontech/erigon-lib/types"
+	"github.com/erigontech/erigon/cmd/rpcdaemon/graphql/graph/model"
+	"github.com/erigontech/erigon/rpc"
 )
 
 func convertDataToStringP(abstractMap map[string]interface{}, field string) *string {
@@ -135,3 +139,72 @@ func convertStrHexToDec(hexString *string) *string {
 
 	return &result
 }
+
+func parseAddress(address string) (common.Address, error) {
+	if !common.IsHexAddress(address) {
+		return common.Address{}, fmt.Errorf("invalid address %q", address)
+	}
+	return common.HexToAddress(address), nil
+}
+
+// longToBlockNumber converts a block number of the schema, the ones above MaxInt64 would turn into the negative tags
+func longToBlockNumber(number uint64) (rpc.BlockNumber, error) {
+	if number > math.MaxInt64 {
+		return 0, fmt.Errorf("invalid block number %d", number)
+	}
+	return rpc.BlockNumber(number), nil
+}
+
+// accountBlockNumber returns the block the state of the account is read at
+func accountBlockNumber(account *model.Account) (rpc.BlockNumber, error) {
+	if account.Block == nil {
+		return rpc.LatestBlockNumber, nil
+	}
+	return longToBlockNumber(*account.Block)
+}
+
+func convertLog(rlog *types.Log) *model.Log {
+	txIndex := int(rlog.TxIndex)
+	tlog := &model.Log{
+		Index:   int(rlog.Index),
+		Account: &model.Account{Address: strings.ToLower(rlog.Address.String()), Block: &rlog.BlockNumber},
+		Data:    "0x" + hex.EncodeToString(rlog.Data),
+		Transaction: &model.Transaction{
+			Hash:  rlog.TxHash.String(),
+			Index: &txIndex,
+			Block: &model.Block{Number: rlog.BlockNumber, Hash: rlog.BlockHash.String()},
+		},
+	}
+	for _, rtopic := range rlog.Topics {
+		tlog.Topics = append(tlog.Topics, rtopic.String())
+	}
+	return tlog
+}
+
+func convertPendingTransaction(txn types.Transaction, signer *types.Signer) *model.Transaction {
+	txType := int(txn.Type())
+	trans := &model.Transaction{
+		Hash:      txn.Hash().String(),
+		Nonce:     hexutil.EncodeUint64(txn.GetNonce()),
+		Value:     txn.GetValue().Hex(),
+		GasPrice:  txn.GetFeeCap().Hex(),
+		Gas:       txn.GetGasLimit(),
+		InputData: hexutil.Encode(txn.GetData()),
+		Type:      &txType,
+	}
+	if txn.Type() != types.LegacyTxType && txn.Type() != types.AccessListTxType {
+		maxFeePerGas, maxPriorityFeePerGas := txn.GetFeeCap().Hex(), txn.GetTipCap().Hex()
+		trans.MaxFeePerGas, trans.MaxPriorityFeePerGas = &maxFeePerGas, &maxPriorityFeePerGas
+	}
+
+	from, ok := txn.GetSender()
+	if !ok {
+		from, _ = txn.Sender(*signer)
+	}
+	// pending transactions aren't in a block yet, their accounts are read at the latest one
+	trans.From = &model.Account{Address: strings.ToLower(from.String())}
+	if to := txn.GetTo(); to != nil {
+		trans.To = &model.Account{Address: strings.ToLower(to.String())}
+	}
+	return trans
+}
@@ -7,14 +7,6 @@ type AccessTuple struct {
 	StorageKeys []string `json:"storageKeys"`
 }
 
-type Account struct {
-	Address          string `json:"address"`
-	Balance          string `json:"balance"`
-	TransactionCount uint64 `json:"transactionCount"`
-	Code             string `json:"code"`
-	Storage          string `json:"storage"`
-}
-
 type Block struct {
 	Number            uint64         `json:"number"`
 	Hash              string         `json:"hash"`
@@ -100,6 +92,9 @@ type Pending struct {
 type Query struct {
 }
 
+type Subscription struct {
+}
+
 type SyncState struct {
 	StartingBlock uint64 `json:"startingBlock"`
 	CurrentBlock  uint64 `json:"currentBlock"`
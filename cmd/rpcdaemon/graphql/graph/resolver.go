@@ -1,6 +1,11 @@
 package graph
 
 import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+
 	"github.com/erigontech/erigon-lib/kv"
 	"github.com/erigontech/erigon/rpc/jsonrpc"
 	"github.com/erigontech/erigon/rpc/rpchelper"
@@ -17,3 +22,19 @@ type Resolver struct {
 	filters     *rpchelper.Filters
 	blockReader services.FullBlockReader
 }
+
+// RequestTransaction makes the resolvers of a query or mutation read with one transaction of api.
+// Subscriptions outlive the request, their events are read with a transaction each.
+func RequestTransaction(api jsonrpc.GraphQLAPI) graphql.OperationMiddleware {
+	return func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+		if graphql.GetOperationContext(ctx).Operation.Operation == ast.Subscription {
+			return next(ctx)
+		}
+		ctx, release := api.BeginRequest(ctx)
+		responses := next(ctx)
+		return func(ctx context.Context) *graphql.Response {
+			defer release()
+			return responses(ctx)
+		}
+	}
+}
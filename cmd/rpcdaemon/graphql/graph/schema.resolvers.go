@@ -13,11 +13,85 @@ import (
 
 	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon-lib/log/v3"
 	"github.com/erigontech/erigon-lib/types"
 	"github.com/erigontech/erigon/cmd/rpcdaemon/graphql/graph/model"
+	"github.com/erigontech/erigon/eth/filters"
 	"github.com/erigontech/erigon/rpc"
 )
 
+// Balance is the resolver for the balance field.
+func (r *accountResolver) Balance(ctx context.Context, obj *model.Account) (string, error) {
+	address, err := parseAddress(obj.Address)
+	if err != nil {
+		return "", err
+	}
+	number, err := accountBlockNumber(obj)
+	if err != nil {
+		return "", err
+	}
+	acc, err := r.GraphQLAPI.GetAccount(ctx, address, number)
+	if err != nil || acc == nil {
+		return "0x0", err
+	}
+	return acc.Balance.Hex(), nil
+}
+
+// TransactionCount is the resolver for the transactionCount field.
+func (r *accountResolver) TransactionCount(ctx context.Context, obj *model.Account) (uint64, error) {
+	address, err := parseAddress(obj.Address)
+	if err != nil {
+		return 0, err
+	}
+	number, err := accountBlockNumber(obj)
+	if err != nil {
+		return 0, err
+	}
+	acc, err := r.GraphQLAPI.GetAccount(ctx, address, number)
+	if err != nil || acc == nil {
+		return 0, err
+	}
+	return acc.Nonce, nil
+}
+
+// Code is the resolver for the code field.
+func (r *accountResolver) Code(ctx context.Context, obj *model.Account) (string, error) {
+	address, err := parseAddress(obj.Address)
+	if err != nil {
+		return "", err
+	}
+	number, err := accountBlockNumber(obj)
+	if err != nil {
+		return "", err
+	}
+	code, err := r.GraphQLAPI.GetCode(ctx, address, number)
+	if err != nil {
+		return "", err
+	}
+	return hexutil.Encode(code), nil
+}
+
+// Storage is the resolver for the storage field.
+func (r *accountResolver) Storage(ctx context.Context, obj *model.Account, slot string) (string, error) {
+	address, err := parseAddress(obj.Address)
+	if err != nil {
+		return "", err
+	}
+	number, err := accountBlockNumber(obj)
+	if err != nil {
+		return "", err
+	}
+	slotBytes, err := hexutil.Decode(slot)
+	if err != nil || len(slotBytes) > 32 {
+		return "", fmt.Errorf("invalid storage slot %q", slot)
+	}
+	value, err := r.GraphQLAPI.GetStorageAt(ctx, address, common.BytesToHash(slotBytes), number)
+	if err != nil {
+		return "", err
+	}
+	return hexutil.Encode(common.LeftPadBytes(value, 32)), nil
+}
+
 // SendRawTransaction is the resolver for the sendRawTransaction field.
 func (r *mutationResolver) SendRawTransaction(ctx context.Context, data string) (string, error) {
 	panic("not implemented: SendRawTransaction - sendRawTransaction")
@@ -32,12 +106,16 @@ func (r *queryResolver) Block(ctx context.Context, number *string, hash *string)
 		bNum, err := strconv.ParseUint(*number, 10, 64)
 		if err == nil {
 			// Positive integer, go ahead
-			blockNumber = rpc.BlockNumber(bNum)
+			if blockNumber, err = longToBlockNumber(bNum); err != nil {
+				return nil, err
+			}
 		} else {
 			bNum, err := hexutil.DecodeUint64(*number)
 			if err == nil {
 				// Hexadecimal, 0x prefixed
-				blockNumber = rpc.BlockNumber(bNum)
+				if blockNumber, err = longToBlockNumber(bNum); err != nil {
+					return nil, err
+				}
 			} else {
 				var err error
 				return nil, err
@@ -79,7 +157,10 @@ func (r *queryResolver) Block(ctx context.Context, number *string, hash *string)
 		block.GasLimit = uint64(*convertDataToUint64P(blk, "gasLimit"))
 		block.GasUsed = *convertDataToUint64P(blk, "gasUsed")
 		block.Hash = *convertDataToStringP(blk, "hash")
-		block.Miner = &model.Account{}
+		block.Number = *convertDataToUint64P(blk, "number")
+		// the state of the accounts of the block is read at the block
+		stateBlock := &block.Number
+		block.Miner = &model.Account{Block: stateBlock}
 		address := convertDataToStringP(blk, "miner")
 		if address != nil {
 			block.Miner.Address = strings.ToLower(*address)
@@ -92,7 +173,6 @@ func (r *queryResolver) Block(ctx context.Context, number *string, hash *string)
 		if blockNonce != nil {
 			block.Nonce = *blockNonce
 		}
-		block.Number = *convertDataToUint64P(blk, "number")
 		block.Parent = &model.Block{}
 		block.Parent.Hash = *convertDataToStringP(blk, "parentHash")
 		block.ReceiptsRoot = *convertDataToStringP(blk, "receiptsRoot")
@@ -141,7 +221,7 @@ func (r *queryResolver) Block(ctx context.Context, number *string, hash *string)
 					Index: int(rlog.Index),
 					Data:  "0x" + hex.EncodeToString(rlog.Data),
 				}
-				tlog.Account = &model.Account{}
+				tlog.Account = &model.Account{Block: stateBlock}
 				tlog.Account.Address = strings.ToLower(rlog.Address.String())
 
 				for _, rtopic := range rlog.Topics {
@@ -151,14 +231,12 @@ func (r *queryResolver) Block(ctx context.Context, number *string, hash *string)
 				trans.Logs = append(trans.Logs, &tlog)
 			}
 
-			trans.From = &model.Account{}
+			trans.From = &model.Account{Block: stateBlock}
 			trans.From.Address = strings.ToLower(*convertDataToStringP(transReceipt, "from"))
 
-			trans.To = &model.Account{}
-			address := convertDataToStringP(transReceipt, "to")
-			// To address could be nil in case of contract creation
-			if address != nil {
-				trans.To.Address = strings.ToLower(*convertDataToStringP(transReceipt, "to"))
+			// To address is nil in case of contract creation
+			if address := convertDataToStringP(transReceipt, "to"); address != nil {
+				trans.To = &model.Account{Address: strings.ToLower(*address), Block: stateBlock}
 			}
 
 			block.Transactions = append(block.Transactions, trans)
@@ -242,11 +320,143 @@ func (r *queryResolver) ChainID(ctx context.Context) (string, error) {
 	return "0x" + strconv.FormatUint(chainID.Uint64(), 16), err
 }
 
+// Account is the resolver for the account field.
+func (r *queryResolver) Account(ctx context.Context, address string, block *uint64) (*model.Account, error) {
+	addr, err := parseAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Account{Address: strings.ToLower(addr.String()), Block: block}, nil
+}
+
+// NewBlocks is the resolver for the newBlocks field.
+func (r *subscriptionResolver) NewBlocks(ctx context.Context) (<-chan *model.Block, error) {
+	headers, err := r.GraphQLAPI.SubscribeNewHeads(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan *model.Block)
+	go func() {
+		defer close(ch)
+		for {
+			select {
+			case header, ok := <-headers:
+				if !ok {
+					return
+				}
+				number := header.Number.String()
+				block, err := r.Query().Block(ctx, &number, nil)
+				if err != nil {
+					log.Debug("[graphql] failed to read new block", "number", number, "err", err)
+					continue
+				}
+				select {
+				case ch <- block:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// Logs is the resolver for the logs field.
+func (r *subscriptionResolver) Logs(ctx context.Context, filter model.BlockFilterCriteria) (<-chan *model.Log, error) {
+	var crit filters.FilterCriteria
+	for _, address := range filter.Addresses {
+		addr, err := parseAddress(address)
+		if err != nil {
+			return nil, err
+		}
+		crit.Addresses = append(crit.Addresses, addr)
+	}
+	for _, topics := range filter.Topics {
+		hashes := make([]common.Hash, 0, len(topics))
+		for _, topic := range topics {
+			hashes = append(hashes, common.HexToHash(topic))
+		}
+		crit.Topics = append(crit.Topics, hashes)
+	}
+	logs, err := r.GraphQLAPI.SubscribeLogs(ctx, crit)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan *model.Log)
+	go func() {
+		defer close(ch)
+		for {
+			select {
+			case rlog, ok := <-logs:
+				if !ok {
+					return
+				}
+				select {
+				case ch <- convertLog(rlog):
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// PendingTransactions is the resolver for the pendingTransactions field.
+func (r *subscriptionResolver) PendingTransactions(ctx context.Context) (<-chan *model.Transaction, error) {
+	chainID, err := r.GraphQLAPI.GetChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	signer := types.LatestSignerForChainID(chainID)
+	txns, err := r.GraphQLAPI.SubscribePendingTxs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan *model.Transaction)
+	go func() {
+		defer close(ch)
+		for {
+			select {
+			case batch, ok := <-txns:
+				if !ok {
+					return
+				}
+				for _, txn := range batch {
+					if txn == nil {
+						continue
+					}
+					select {
+					case ch <- convertPendingTransaction(txn, signer):
+					case <-ctx.Done():
+						return
+					}
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// Account returns AccountResolver implementation.
+func (r *Resolver) Account() AccountResolver { return &accountResolver{r} }
+
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
+// Subscription returns SubscriptionResolver implementation.
+func (r *Resolver) Subscription() SubscriptionResolver { return &subscriptionResolver{r} }
+
+type accountResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
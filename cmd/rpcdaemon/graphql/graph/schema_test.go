@@ -0,0 +1,253 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"sync/atomic"
+	"testing"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/chain"
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/types"
+	"github.com/erigontech/erigon-lib/types/accounts"
+	"github.com/erigontech/erigon/eth/ethutils"
+	"github.com/erigontech/erigon/eth/filters"
+	"github.com/erigontech/erigon/rpc"
+	"github.com/erigontech/erigon/rpc/ethapi"
+	"github.com/erigontech/erigon/rpc/jsonrpc"
+)
+
+type testRequestKey struct{}
+
+// testAPI serves the state of a single account, whose balance is its block number, blocks
+// mined by it and the logs pushed to the logs channel
+type testAPI struct {
+	jsonrpc.GraphQLAPI
+	address common.Address
+	logs    chan *types.Log
+	crit    chan filters.FilterCriteria
+
+	requests, released, readsOutsideRequest atomic.Int32
+}
+
+func (api *testAPI) BeginRequest(ctx context.Context) (context.Context, func()) {
+	api.requests.Add(1)
+	return context.WithValue(ctx, testRequestKey{}, true), func() { api.released.Add(1) }
+}
+
+func (api *testAPI) read(ctx context.Context) {
+	if ctx.Value(testRequestKey{}) == nil {
+		api.readsOutsideRequest.Add(1)
+	}
+}
+
+// GetBlockDetails returns a block with a transfer and a contract creation, both sent by the account
+func (api *testAPI) GetBlockDetails(ctx context.Context, number rpc.BlockNumber) (map[string]interface{}, error) {
+	api.read(ctx)
+	header := &types.Header{Number: big.NewInt(int64(number)), Coinbase: api.address, Difficulty: big.NewInt(0), BaseFee: big.NewInt(1)}
+	txns := []types.Transaction{
+		types.NewTransaction(0, common.HexToAddress("0x5678"), uint256.NewInt(1), 21000, uint256.NewInt(1), nil),
+		types.NewContractCreation(1, uint256.NewInt(0), 53000, uint256.NewInt(1), []byte{0x60}),
+	}
+	block := types.NewBlock(header, txns, nil, nil, nil)
+	blk, err := ethapi.RPCMarshalBlock(block, false, false, nil)
+	if err != nil {
+		return nil, err
+	}
+	blk["transactionCount"] = len(txns)
+
+	receipts := make([]map[string]interface{}, 0, len(txns))
+	for i, txn := range txns {
+		receipt := &types.Receipt{TransactionIndex: uint(i), BlockNumber: header.Number, Logs: types.Logs{{Address: api.address, Index: uint(i)}}}
+		fields := ethutils.MarshalReceipt(receipt, txn, chain.TestChainConfig, header, txn.Hash(), false)
+		fields["from"] = api.address
+		fields["nonce"] = txn.GetNonce()
+		fields["value"] = txn.GetValue()
+		fields["data"] = txn.GetData()
+		fields["logs"] = receipt.Logs
+		receipts = append(receipts, fields)
+	}
+	return map[string]interface{}{"block": blk, "receipts": receipts, "withdrawals": []map[string]interface{}{}}, nil
+}
+
+func (api *testAPI) GetAccount(ctx context.Context, address common.Address, number rpc.BlockNumber) (*accounts.Account, error) {
+	api.read(ctx)
+	if address != api.address {
+		return nil, nil
+	}
+	return &accounts.Account{Nonce: 7, Balance: *uint256.NewInt(uint64(number))}, nil
+}
+
+func (api *testAPI) GetStorageAt(ctx context.Context, address common.Address, slot common.Hash, number rpc.BlockNumber) ([]byte, error) {
+	api.read(ctx)
+	if address != api.address {
+		return nil, nil
+	}
+	return []byte{slot[31], byte(number)}, nil
+}
+
+func (api *testAPI) SubscribeLogs(_ context.Context, crit filters.FilterCriteria) (<-chan *types.Log, error) {
+	api.crit <- crit
+	return api.logs, nil
+}
+
+func newTestClient(api jsonrpc.GraphQLAPI) *client.Client {
+	srv := handler.New(NewExecutableSchema(Config{Resolvers: &Resolver{GraphQLAPI: api}}))
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.Websocket{})
+	srv.AroundOperations(RequestTransaction(api))
+	return client.New(srv)
+}
+
+func TestAccountAtBlock(t *testing.T) {
+	api := &testAPI{address: common.HexToAddress("0x1234")}
+	c := newTestClient(api)
+
+	var resp struct {
+		Account struct {
+			Balance          string
+			TransactionCount uint64
+			Storage          string
+		}
+		Empty struct {
+			Balance string
+		}
+	}
+	c.MustPost(`{
+		account(address: "0x0000000000000000000000000000000000001234", block: 5) {
+			balance
+			transactionCount
+			storage(slot: "0x0000000000000000000000000000000000000000000000000000000000000002")
+		}
+		empty: account(address: "0x0000000000000000000000000000000000005678") { balance }
+	}`, &resp)
+	require.Equal(t, "0x5", resp.Account.Balance)
+	require.Equal(t, uint64(7), resp.Account.TransactionCount)
+	require.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000205", resp.Account.Storage)
+	require.Equal(t, "0x0", resp.Empty.Balance)
+
+	err := c.Post(`{ account(address: "0x12") { balance } }`, &resp)
+	require.ErrorContains(t, err, "invalid address")
+
+	// above MaxInt64 the number would turn into a block tag
+	err = c.Post(`query($block: Long) { account(address: "0x0000000000000000000000000000000000001234", block: $block) { balance } }`, &resp,
+		client.Var("block", uint64(math.MaxInt64)+1))
+	require.ErrorContains(t, err, "invalid block number")
+}
+
+func TestBlockAccountsAtBlock(t *testing.T) {
+	api := &testAPI{address: common.HexToAddress("0x1234")}
+	c := newTestClient(api)
+
+	type account struct {
+		Address string
+		Balance string
+	}
+	var resp struct {
+		Block struct {
+			Miner        account
+			Transactions []struct {
+				From account
+				To   *account
+				Logs []struct {
+					Account account
+				}
+			}
+		}
+	}
+	c.MustPost(`{
+		block(number: "5") {
+			miner { balance }
+			transactions {
+				from { balance }
+				to { address balance }
+				logs { account { balance } }
+			}
+		}
+	}`, &resp)
+	require.Equal(t, "0x5", resp.Block.Miner.Balance)
+	require.Len(t, resp.Block.Transactions, 2)
+	for _, txn := range resp.Block.Transactions {
+		require.Equal(t, "0x5", txn.From.Balance)
+		require.Equal(t, "0x5", txn.Logs[0].Account.Balance)
+	}
+	require.Equal(t, &account{Address: "0x0000000000000000000000000000000000005678", Balance: "0x0"}, resp.Block.Transactions[0].To)
+	// contract creation
+	require.Nil(t, resp.Block.Transactions[1].To)
+}
+
+func TestRequestTransaction(t *testing.T) {
+	api := &testAPI{address: common.HexToAddress("0x1234")}
+	c := newTestClient(api)
+
+	var resp struct {
+		Block struct {
+			Miner struct {
+				Balance          string
+				TransactionCount uint64
+			}
+		}
+		Account struct {
+			Balance string
+		}
+	}
+	c.MustPost(`{
+		block(number: "5") { miner { balance transactionCount } }
+		account(address: "0x0000000000000000000000000000000000001234") { balance }
+	}`, &resp)
+	require.Equal(t, "0x5", resp.Block.Miner.Balance)
+	require.Equal(t, int32(1), api.requests.Load())
+	require.Equal(t, int32(1), api.released.Load())
+	require.Zero(t, api.readsOutsideRequest.Load())
+}
+
+func TestLogsSubscription(t *testing.T) {
+	api := &testAPI{logs: make(chan *types.Log, 1), crit: make(chan filters.FilterCriteria, 1)}
+	c := newTestClient(api)
+
+	sub := c.Websocket(`subscription {
+		logs(filter: {addresses: ["0x0000000000000000000000000000000000001234"]}) { index topics account { address } }
+	}`)
+	defer sub.Close()
+
+	crit := <-api.crit
+	require.Equal(t, []common.Address{common.HexToAddress("0x1234")}, crit.Addresses)
+
+	api.logs <- &types.Log{Address: common.HexToAddress("0x1234"), Topics: []common.Hash{{0x01}}, Index: 3}
+	var resp struct {
+		Logs struct {
+			Index   int
+			Topics  []string
+			Account struct {
+				Address string
+			}
+		}
+	}
+	require.NoError(t, sub.Next(&resp))
+	require.Equal(t, 3, resp.Logs.Index)
+	require.Equal(t, []string{common.Hash{0x01}.String()}, resp.Logs.Topics)
+	require.Equal(t, "0x0000000000000000000000000000000000001234", resp.Logs.Account.Address)
+}
@@ -49,7 +49,11 @@ func CreateHandler(api []rpc.API) *handler.Server {
 	resolver := graph.Resolver{}
 	resolver.GraphQLAPI = graphqlAPI
 
-	return handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{Resolvers: &resolver})) // TODO : init resolver.DB here !!!
+	srv := handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{Resolvers: &resolver})) // TODO : init resolver.DB here !!!
+	if graphqlAPI != nil {
+		srv.AroundOperations(graph.RequestTransaction(graphqlAPI))
+	}
+	return srv
 }
 
 func ProcessGraphQLcheckIfNeeded(
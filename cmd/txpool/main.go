@@ -75,6 +75,10 @@ var (
 	mdbxWriteMap bool
 
 	commitEvery time.Duration
+
+	locals         []string
+	journal        string
+	rejournalEvery time.Duration
 )
 
 func init() {
@@ -103,6 +107,9 @@ func init() {
 	rootCmd.PersistentFlags().BoolVar(&noTxGossip, utils.TxPoolGossipDisableFlag.Name, utils.TxPoolGossipDisableFlag.Value, utils.TxPoolGossipDisableFlag.Usage)
 	rootCmd.PersistentFlags().BoolVar(&mdbxWriteMap, utils.DbWriteMapFlag.Name, utils.DbWriteMapFlag.Value, utils.DbWriteMapFlag.Usage)
 	rootCmd.Flags().StringSliceVar(&traceSenders, utils.TxPoolTraceSendersFlag.Name, []string{}, utils.TxPoolTraceSendersFlag.Usage)
+	rootCmd.Flags().StringSliceVar(&locals, utils.TxPoolLocalsFlag.Name, []string{}, utils.TxPoolLocalsFlag.Usage)
+	rootCmd.PersistentFlags().StringVar(&journal, utils.TxPoolJournalFlag.Name, utils.TxPoolJournalFlag.Value, utils.TxPoolJournalFlag.Usage)
+	rootCmd.PersistentFlags().DurationVar(&rejournalEvery, utils.TxPoolRejournalFlag.Name, utils.TxPoolRejournalFlag.Value, utils.TxPoolRejournalFlag.Usage)
 }
 
 var rootCmd = &cobra.Command{
@@ -181,6 +188,18 @@ func doTxpool(ctx context.Context, logger log.Logger) error {
 		sender := common.HexToAddress(senderHex)
 		cfg.TracedSenders[i] = string(sender[:])
 	}
+	cfg.Locals = make([]string, len(locals))
+	for i, senderHex := range locals {
+		sender := common.HexToAddress(senderHex)
+		cfg.Locals[i] = string(sender[:])
+	}
+	if journal != "" {
+		if !filepath.IsAbs(journal) {
+			journal = filepath.Join(dirs.TxPool, journal)
+		}
+		cfg.Journal = journal
+	}
+	cfg.RejournalEvery = rejournalEvery
 
 	notifyMiner := func() {}
 	txPool, txpoolGrpcServer, err := txpool.Assemble(
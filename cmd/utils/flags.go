@@ -48,7 +48,9 @@ import (
 	libkzg "github.com/erigontech/erigon-lib/crypto/kzg"
 	"github.com/erigontech/erigon-lib/direct"
 	downloadercfg2 "github.com/erigontech/erigon-lib/downloader/downloadercfg"
+	"github.com/erigontech/erigon-lib/downloader/snaptype"
 	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon-lib/seg"
 	"github.com/erigontech/erigon-lib/state"
 	"github.com/erigontech/erigon-lib/types"
 	"github.com/erigontech/erigon/cl/clparams"
@@ -217,6 +219,21 @@ var (
 		Usage: "How often transactions should be committed to the storage",
 		Value: txpoolcfg.DefaultConfig.CommitEvery,
 	}
+	TxPoolLocalsFlag = cli.StringFlag{
+		Name:  "txpool.locals",
+		Usage: "Comma separated list of addresses, whose transactions are treated as local (exempt from eviction, journaled and re-broadcast)",
+		Value: "",
+	}
+	TxPoolJournalFlag = cli.StringFlag{
+		Name:  "txpool.journal",
+		Usage: "Disk journal for local transactions to survive node restarts, relative to the txpool dir (empty disables it)",
+		Value: "transactions.rlp",
+	}
+	TxPoolRejournalFlag = cli.DurationFlag{
+		Name:  "txpool.rejournal",
+		Usage: "Time interval to regenerate the local transaction journal (0 disables the regeneration)",
+		Value: txpoolcfg.DefaultConfig.RejournalEvery,
+	}
 	// Miner settings
 	MiningEnabledFlag = cli.BoolFlag{
 		Name:  "mine",
@@ -406,6 +423,10 @@ var (
 		Name:  "rpc.accessList",
 		Usage: "Specify granular (method-by-method) API allowlist",
 	}
+	RpcRateLimitsFlag = cli.StringFlag{
+		Name:  "rpc.ratelimits",
+		Usage: "Specify per-method, per-client rate limits (json file)",
+	}
 
 	RpcGasCapFlag = cli.UintFlag{
 		Name:  "rpc.gascap",
@@ -416,6 +437,10 @@ var (
 		Name:  "trace.compat",
 		Usage: "Bug for bug compatibility with OE for trace_ routines",
 	}
+	RpcTraceDirFlag = cli.StringFlag{
+		Name:  "rpc.tracedir",
+		Usage: "Directory where debug_standardTrace*ToFile writes its EIP-3155 dumps (default: <datadir>/traces)",
+	}
 
 	TxpoolApiAddrFlag = cli.StringFlag{
 		Name:  "txpool.api.addr",
@@ -525,6 +550,10 @@ var (
 		Usage: "Version of eth p2p protocol",
 		Value: cli.NewUintSlice(nodecfg.DefaultConfig.P2P.ProtocolVersion...),
 	}
+	P2pSnapServeFlag = cli.BoolFlag{
+		Name:  "p2p.snap",
+		Usage: "Serve the snap/1 state sync protocol to peers, answering from the latest state",
+	}
 	P2pProtocolAllowedPorts = cli.UintSliceFlag{
 		Name:  "p2p.allowed-ports",
 		Usage: "Allowed ports to pick for different eth p2p protocol versions as follows <porta>,<portb>,..,<porti>",
@@ -674,6 +703,11 @@ var (
 		Name:  ethconfig.FlagSnapStateStop,
 		Usage: "Workaround to stop producing new state files, if you meet some state-related critical bug. It will stop aggregate DB history in a state files. DB will grow and may slightly slow-down - and removing this flag in future will not fix this effect (db size will not greatly reduce).",
 	}
+	SnapCodecFlag = cli.StringFlag{
+		Name:  "snap.codec",
+		Usage: "Codec of the new block snapshot files, comma-separated type=codec pairs. Codecs: huffman (default), zstd. Example: transactions=zstd,bodies=zstd. Codecs other than huffman require --no-downloader, the files keep the canonical names and must not be seeded",
+		Value: "",
+	}
 	SnapSkipStateSnapshotDownloadFlag = cli.BoolFlag{
 		Name:  "snap.skip-state-snapshot-download",
 		Usage: "Skip state download and start from genesis block",
@@ -872,14 +906,64 @@ var (
 	}
 	CaplinMevRelayUrl = cli.StringFlag{
 		Name:  "caplin.mev-relay-url",
-		Usage: "MEV relay endpoint. Caplin runs in builder mode if this is set",
+		Usage: "MEV relay endpoint, or comma separated list of endpoints. Caplin runs in builder mode if this is set",
 		Value: "",
 	}
+	CaplinMevRelayTimeoutFlag = cli.DurationFlag{
+		Name:  "caplin.mev-relay-timeout",
+		Usage: "Time the MEV relays have to serve a bid, later bids are ignored",
+		Value: 950 * time.Millisecond,
+	}
+	CaplinMevMinBidFlag = cli.StringFlag{
+		Name:  "caplin.mev-min-bid",
+		Usage: "Minimum bid (in ether) to take over the locally built payload",
+		Value: "0",
+	}
 	CaplinValidatorMonitorFlag = cli.BoolFlag{
 		Name:  "caplin.validator-monitor",
 		Usage: "Enable caplin validator monitoring metrics",
 		Value: false,
 	}
+	CaplinValidatorKeystoresDirFlag = cli.StringFlag{
+		Name:  "caplin.validator.keystores-dir",
+		Usage: "Directory of EIP-2335 keystores. Caplin runs a built-in validator client for them if this is set",
+		Value: "",
+	}
+	CaplinValidatorPasswordsFlag = cli.StringFlag{
+		Name:  "caplin.validator.passwords",
+		Usage: "Password file of all the keystores, or directory holding a <keystore name>.txt password file for each of them",
+		Value: "",
+	}
+	CaplinValidatorWeb3SignerUrlFlag = cli.StringFlag{
+		Name:  "caplin.validator.web3signer-url",
+		Usage: "Web3Signer compatible remote signer. Caplin runs a built-in validator client for its keys if this is set",
+		Value: "",
+	}
+	CaplinValidatorWeb3SignerKeysFlag = cli.StringFlag{
+		Name:  "caplin.validator.web3signer-keys",
+		Usage: "Comma separated public keys to validate with through the remote signer, all the keys it holds if empty",
+		Value: "",
+	}
+	CaplinValidatorFeeRecipientFlag = cli.StringFlag{
+		Name:  "caplin.validator.fee-recipient",
+		Usage: "Fee recipient of the blocks proposed by the built-in validator client",
+		Value: "",
+	}
+	CaplinValidatorGraffitiFlag = cli.StringFlag{
+		Name:  "caplin.validator.graffiti",
+		Usage: "Graffiti of the blocks proposed by the built-in validator client",
+		Value: "",
+	}
+	CaplinKeymanagerAddrFlag = cli.StringFlag{
+		Name:  "caplin.keymanager.addr",
+		Usage: "Address (host:port) to serve the keymanager API of the built-in validator client on, disabled if empty",
+		Value: "",
+	}
+	CaplinKeymanagerJwtSecretFlag = cli.StringFlag{
+		Name:  "caplin.keymanager.jwtsecret",
+		Usage: "Path to the token that secures the keymanager API, generated in <datadir>/caplin/validator if not set",
+		Value: "",
+	}
 	CaplinMaxPeerCount = cli.Uint64Flag{
 		Name:  "caplin.max-peer-count",
 		Usage: "Max number of peers to connect",
@@ -1054,6 +1138,21 @@ var (
 		Usage: "disable checkpoint sync in caplin",
 		Value: false,
 	}
+	CaplinCheckpointSyncStateFileFlag = cli.StringFlag{
+		Name:  "caplin.checkpoint-sync.state-file",
+		Usage: "Checkpoint state file (.ssz or .ssz_snappy), the post-state of the checkpoint block, to start from instead of a checkpoint sync endpoint",
+		Value: "",
+	}
+	CaplinCheckpointSyncBlockFileFlag = cli.StringFlag{
+		Name:  "caplin.checkpoint-sync.block-file",
+		Usage: "Block file (.ssz or .ssz_snappy) of the checkpoint state",
+		Value: "",
+	}
+	CaplinCheckpointSyncBlockRootFlag = cli.StringFlag{
+		Name:  "caplin.checkpoint-sync.block-root",
+		Usage: "Trusted root of the checkpoint block, the checkpoint files and the backfilled chain are verified against it",
+		Value: "",
+	}
 
 	CaplinEnableSnapshotGeneration = cli.BoolFlag{
 		Name:  "caplin.snapgen",
@@ -1287,6 +1386,8 @@ func NewP2PConfig(
 		enodeDBPath = filepath.Join(dirs.Nodes, "eth67")
 	case direct.ETH68:
 		enodeDBPath = filepath.Join(dirs.Nodes, "eth68")
+	case direct.ETH69:
+		enodeDBPath = filepath.Join(dirs.Nodes, "eth69")
 	default:
 		return nil, fmt.Errorf("unknown protocol: %v", protocol)
 	}
@@ -1353,6 +1454,7 @@ func setListenAddress(ctx *cli.Context, cfg *p2p.Config) {
 	if ctx.IsSet(P2pProtocolVersionFlag.Name) {
 		cfg.ProtocolVersion = ctx.UintSlice(P2pProtocolVersionFlag.Name)
 	}
+	cfg.SnapServe = ctx.Bool(P2pSnapServeFlag.Name)
 	if ctx.IsSet(SentryAddrFlag.Name) {
 		cfg.SentryAddr = common.CliString2Array(ctx.String(SentryAddrFlag.Name))
 	}
@@ -1391,6 +1493,31 @@ func setNAT(ctx *cli.Context, cfg *p2p.Config) {
 	}
 }
 
+// setSnapCodecs selects the codecs of the new block snapshot files from the command line flags.
+func setSnapCodecs(ctx *cli.Context) {
+	if !ctx.IsSet(SnapCodecFlag.Name) {
+		return
+	}
+	for _, pair := range common.CliString2Array(ctx.String(SnapCodecFlag.Name)) {
+		name, codecName, ok := strings.Cut(pair, "=")
+		if !ok {
+			Fatalf("Option %s: expected type=codec, got %q", SnapCodecFlag.Name, pair)
+		}
+		codec, err := seg.ParseCodec(codecName)
+		if err != nil {
+			Fatalf("Option %s: %v", SnapCodecFlag.Name, err)
+		}
+		// the files keep the names and the version of the huffman ones, peers would download them under the hashes of
+		// the canonical files
+		if codec != seg.CodecHuffman && !ctx.Bool(NoDownloaderFlag.Name) {
+			Fatalf("Option %s: %s files can't be seeded, it requires --%s", SnapCodecFlag.Name, codec, NoDownloaderFlag.Name)
+		}
+		if err := snaptype.SetCodec(name, codec); err != nil {
+			Fatalf("Option %s: %v", SnapCodecFlag.Name, err)
+		}
+	}
+}
+
 // setEtherbase retrieves the etherbase from the directly specified
 // command line flags.
 func setEtherbase(ctx *cli.Context, cfg *ethconfig.Config) {
@@ -1607,6 +1734,23 @@ func setTxPool(ctx *cli.Context, dbDir string, fullCfg *ethconfig.Config) {
 			cfg.TracedSenders[i] = string(sender[:])
 		}
 	}
+	if ctx.IsSet(TxPoolLocalsFlag.Name) {
+		senderHexes := common.CliString2Array(ctx.String(TxPoolLocalsFlag.Name))
+		cfg.Locals = make([]string, len(senderHexes))
+		for i, senderHex := range senderHexes {
+			sender := common.HexToAddress(senderHex)
+			cfg.Locals[i] = string(sender[:])
+		}
+	}
+	if journal := ctx.String(TxPoolJournalFlag.Name); journal != "" {
+		if !filepath.IsAbs(journal) {
+			journal = filepath.Join(dbDir, journal)
+		}
+		cfg.Journal = journal
+	}
+	if ctx.IsSet(TxPoolRejournalFlag.Name) {
+		cfg.RejournalEvery = ctx.Duration(TxPoolRejournalFlag.Name)
+	}
 	if ctx.IsSet(TxPoolBlobPriceBumpFlag.Name) {
 		cfg.BlobPriceBump = ctx.Uint64(TxPoolBlobPriceBumpFlag.Name)
 	}
@@ -1861,9 +2005,22 @@ func setCaplin(ctx *cli.Context, cfg *ethconfig.Config) {
 	cfg.CaplinConfig.ImmediateBlobsBackfilling = ctx.Bool(CaplinImmediateBlobBackfillFlag.Name)
 	cfg.CaplinConfig.SnapshotGenerationEnabled = ctx.Bool(CaplinEnableSnapshotGeneration.Name)
 	cfg.CaplinConfig.DisabledCheckpointSync = ctx.Bool(CaplinDisableCheckpointSyncFlag.Name)
+	cfg.CaplinConfig.CheckpointSyncStateFile = ctx.String(CaplinCheckpointSyncStateFileFlag.Name)
+	cfg.CaplinConfig.CheckpointSyncBlockFile = ctx.String(CaplinCheckpointSyncBlockFileFlag.Name)
+	cfg.CaplinConfig.CheckpointSyncBlockRoot = common.HexToHash(ctx.String(CaplinCheckpointSyncBlockRootFlag.Name))
 	// bunch of extra stuff
 	cfg.CaplinConfig.MevRelayUrl = ctx.String(CaplinMevRelayUrl.Name)
+	cfg.CaplinConfig.MevRelayTimeout = ctx.Duration(CaplinMevRelayTimeoutFlag.Name)
+	cfg.CaplinConfig.MevMinBid = ctx.String(CaplinMevMinBidFlag.Name)
 	cfg.CaplinConfig.EnableValidatorMonitor = ctx.Bool(CaplinValidatorMonitorFlag.Name)
+	cfg.CaplinConfig.ValidatorKeystoresDir = ctx.String(CaplinValidatorKeystoresDirFlag.Name)
+	cfg.CaplinConfig.ValidatorPasswordsPath = ctx.String(CaplinValidatorPasswordsFlag.Name)
+	cfg.CaplinConfig.ValidatorWeb3SignerUrl = ctx.String(CaplinValidatorWeb3SignerUrlFlag.Name)
+	cfg.CaplinConfig.ValidatorWeb3SignerKeys = ctx.String(CaplinValidatorWeb3SignerKeysFlag.Name)
+	cfg.CaplinConfig.ValidatorFeeRecipient = common.HexToAddress(ctx.String(CaplinValidatorFeeRecipientFlag.Name))
+	cfg.CaplinConfig.ValidatorGraffiti = ctx.String(CaplinValidatorGraffitiFlag.Name)
+	cfg.CaplinConfig.KeymanagerAddr = ctx.String(CaplinKeymanagerAddrFlag.Name)
+	cfg.CaplinConfig.KeymanagerJwtSecretPath = ctx.String(CaplinKeymanagerJwtSecretFlag.Name)
 	if checkpointUrls := ctx.StringSlice(CaplinCheckpointSyncUrlFlag.Name); len(checkpointUrls) > 0 {
 		clparams.ConfigurableCheckpointsURLs = checkpointUrls
 	}
@@ -1981,6 +2138,7 @@ func SetEthConfig(ctx *cli.Context, nodeConfig *nodecfg.Config, cfg *ethconfig.C
 	cfg.Snapshot.ProduceE2 = !ctx.Bool(SnapStopFlag.Name)
 	cfg.Snapshot.ProduceE3 = !ctx.Bool(SnapStateStopFlag.Name)
 	cfg.Snapshot.DisableDownloadE3 = ctx.Bool(SnapSkipStateSnapshotDownloadFlag.Name)
+	setSnapCodecs(ctx)
 	cfg.Snapshot.NoDownloader = ctx.Bool(NoDownloaderFlag.Name)
 	cfg.Snapshot.Verify = ctx.Bool(DownloaderVerifyFlag.Name)
 	cfg.Snapshot.DownloaderAddr = strings.TrimSpace(ctx.String(DownloaderAddrFlag.Name))
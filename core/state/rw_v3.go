@@ -241,6 +241,14 @@ func (rs *ParallelExecutionState) ApplyLogsAndTraces(txTask *TxTask, domains *li
 				return err
 			}
 		}
+		if from, to, ok := lg.TokenTransferParticipants(); ok {
+			if err := domains.IndexAdd(kv.TokenTransfersFromIdx, from[:]); err != nil {
+				return err
+			}
+			if err := domains.IndexAdd(kv.TokenTransfersToIdx, to[:]); err != nil {
+				return err
+			}
+		}
 	}
 
 	if rs.syncCfg.PersistReceiptsCacheV2 {
@@ -1256,6 +1256,33 @@ func WriteDBCommitmentHistoryEnabled(tx kv.RwTx, enabled bool) error {
 	return nil
 }
 
+// ReadTokenTransfersStart returns the first block from which the token transfers indices are complete, ok is false
+// if nothing has been indexed yet
+func ReadTokenTransfersStart(tx kv.Getter) (blockNum uint64, ok bool, err error) {
+	v, err := tx.GetOne(kv.DatabaseInfo, kv.TokenTransfersStartKey)
+	if err != nil {
+		return 0, false, fmt.Errorf("reading token transfers start: %w", err)
+	}
+	if len(v) == 0 {
+		return 0, false, nil
+	}
+	if len(v) != 8 {
+		return 0, false, fmt.Errorf("incorrect length of token transfers start: %d", len(v))
+	}
+	return binary.BigEndian.Uint64(v), true, nil
+}
+func WriteTokenTransfersStart(tx kv.Putter, blockNum uint64) error {
+	var v [8]byte
+	binary.BigEndian.PutUint64(v[:], blockNum)
+	if err := tx.Put(kv.DatabaseInfo, kv.TokenTransfersStartKey, v[:]); err != nil {
+		return fmt.Errorf("writing token transfers start: %w", err)
+	}
+	return nil
+}
+func DeleteTokenTransfersStart(tx kv.Putter) error {
+	return tx.Delete(kv.DatabaseInfo, kv.TokenTransfersStartKey)
+}
+
 func ReadReceiptCacheV2(tx kv.TemporalTx, blockNum uint64, blockHash common.Hash, txnHash common.Hash, txNum uint64) (*types.Receipt, bool, error) {
 	v, ok, err := tx.HistorySeek(kv.RCacheDomain, receiptCacheKey, txNum+1 /*history storing value BEFORE-change*/)
 	if err != nil {
@@ -0,0 +1,240 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package commitment
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/erigontech/erigon-lib/common/length"
+)
+
+// ErrNoBranch is returned by IterateLeaves when the subtrie has no branch node at its root,
+// which happens when all of its keys share the first nibble (e.g. a single leaf). Such
+// subtries are tiny, callers are expected to fall back to iterating plain state.
+var ErrNoBranch = errors.New("no branch node at the root of the subtrie")
+
+// BranchReader returns the (dereferenced) branch stored under the given compacted prefix,
+// or nil if there is none.
+type BranchReader func(compactPrefix []byte) ([]byte, error)
+
+// IterateLeaves walks the leaves of the committed hex patricia trie in ascending order of
+// their hashed keys, starting from the first leaf whose hashed key is >= from.
+//
+// To walk accounts, base is empty and from is a hashed account key. To walk the storage of
+// an account, base is the hashed account key (see KeyToHexNibbleHash) and from is a hashed
+// storage slot. fn receives the plain key of every visited leaf and stops the walk by
+// returning false.
+//
+// Returns the plain key of the closest leaf preceding from (nil if there is none). Together
+// with the first visited leaf it covers all trie nodes on the path to from, which makes it
+// possible to prove the left boundary of a range that doesn't start at an existing key.
+func IterateLeaves(branch BranchReader, base []byte, from []byte, fn func(plainKey []byte) (bool, error)) (prev []byte, err error) {
+	it := &leafIterator{
+		branch:  branch,
+		base:    base,
+		storage: len(base) > 0,
+		from:    make([]byte, 2*len(from)),
+		fn:      fn,
+	}
+	for i, b := range from {
+		it.from[i*2], it.from[i*2+1] = b>>4, b&0xf
+	}
+	row, err := it.row(base)
+	if err != nil {
+		return nil, err
+	}
+	if row == nil {
+		return nil, ErrNoBranch
+	}
+	if _, err = it.walk(base, row, true); err != nil {
+		return nil, err
+	}
+	if it.prev == nil {
+		return nil, nil
+	}
+	return it.lastLeaf(it.prev)
+}
+
+type leafIterator struct {
+	branch  BranchReader
+	base    []byte // nibbles of the subtrie root
+	storage bool   // walk storage leaves of the account at base
+	from    []byte // nibbles of the hashed key to start from, relative to base
+	fn      func(plainKey []byte) (bool, error)
+
+	prev *childRef // closest child preceding from seen so far
+}
+
+// childRef points either to a leaf (by its plain key) or to a branch (by its nibble path).
+type childRef struct {
+	key  []byte
+	path []byte
+}
+
+func (it *leafIterator) ref(path []byte, nibble int, c *cell) *childRef {
+	if key := it.leafKey(c); key != nil {
+		return &childRef{key: key}
+	}
+	return &childRef{path: childPath(path, nibble, c)}
+}
+
+// row decodes the branch stored at the given nibble path, returns nil row if there is no branch.
+func (it *leafIterator) row(path []byte) (*[16]*cell, error) {
+	data, err := it.branch(hexNibblesToCompactBytes(path))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 4 {
+		return nil, nil
+	}
+	// same layout as read by HexPatriciaHashed.unfoldBranchNode: touch map, after map, cells
+	var row [16]*cell
+	bitmap := binary.BigEndian.Uint16(data[2:])
+	pos := 4
+	for bitset := bitmap; bitset != 0; {
+		bit := bitset & -bitset
+		nibble := bits.TrailingZeros16(bit)
+		if pos >= len(data) {
+			return nil, fmt.Errorf("branch [%x] is truncated at nibble %x", path, nibble)
+		}
+		fields := cellFields(data[pos])
+		pos++
+		row[nibble] = new(cell)
+		if pos, err = row[nibble].fillFromFields(data, pos, fields); err != nil {
+			return nil, fmt.Errorf("branch [%x] nibble %x: %w", path, nibble, err)
+		}
+		bitset ^= bit
+	}
+	return &row, nil
+}
+
+func (it *leafIterator) leafKey(c *cell) []byte {
+	if it.storage {
+		if c.storageAddrLen == length.Addr+length.Hash {
+			return c.storageAddr[:c.storageAddrLen]
+		}
+		return nil
+	}
+	if c.accountAddrLen == length.Addr {
+		return c.accountAddr[:c.accountAddrLen]
+	}
+	return nil
+}
+
+// hashedLeafKey returns the nibbles of the hashed key of the leaf, relative to base.
+func (it *leafIterator) hashedLeafKey(plainKey []byte) []byte {
+	hashed := KeyToHexNibbleHash(plainKey)
+	if it.storage {
+		return hashed[64:]
+	}
+	return hashed
+}
+
+func childPath(path []byte, nibble int, c *cell) []byte {
+	child := make([]byte, 0, len(path)+1+c.hashedExtLen)
+	child = append(child, path...)
+	child = append(child, byte(nibble))
+	return append(child, c.hashedExtension[:c.hashedExtLen]...)
+}
+
+// walk visits the children of the branch at path in nibble order. onPath tells that path is
+// a prefix of from, so children preceding from have to be skipped.
+func (it *leafIterator) walk(path []byte, row *[16]*cell, onPath bool) (bool, error) {
+	depth := len(path) - len(it.base)
+	start := 0
+	if onPath {
+		start = int(it.from[depth])
+	}
+	for nibble, c := range row {
+		if c == nil {
+			continue
+		}
+		if nibble < start {
+			it.prev = it.ref(path, nibble, c)
+			continue
+		}
+		if key := it.leafKey(c); key != nil {
+			if onPath && nibble == start && bytes.Compare(it.hashedLeafKey(key), it.from) < 0 {
+				it.prev = &childRef{key: key}
+				continue
+			}
+			if ok, err := it.fn(key); err != nil || !ok {
+				return false, err
+			}
+			continue
+		}
+		if c.hashLen == 0 {
+			continue
+		}
+		child := childPath(path, nibble, c)
+		childOnPath := false
+		if onPath && nibble == start {
+			rel := child[len(it.base):]
+			if len(rel) >= len(it.from) {
+				return false, fmt.Errorf("branch [%x] nibble %x: path is deeper than a hashed key", path, nibble)
+			}
+			switch bytes.Compare(rel, it.from[:len(rel)]) {
+			case -1:
+				it.prev = &childRef{path: child}
+				continue
+			case 0:
+				childOnPath = true
+			}
+		}
+		childRow, err := it.row(child)
+		if err != nil {
+			return false, err
+		}
+		if childRow == nil {
+			return false, fmt.Errorf("missing branch [%x]", child)
+		}
+		if ok, err := it.walk(child, childRow, childOnPath); err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// lastLeaf returns the plain key of the rightmost leaf of the given child.
+func (it *leafIterator) lastLeaf(ref *childRef) ([]byte, error) {
+	path := ref.path
+	for ref.key == nil {
+		row, err := it.row(path)
+		if err != nil {
+			return nil, err
+		}
+		if row == nil {
+			return nil, fmt.Errorf("missing branch [%x]", path)
+		}
+		nibble := 15
+		for nibble >= 0 && row[nibble] == nil {
+			nibble--
+		}
+		if nibble < 0 {
+			return nil, fmt.Errorf("empty branch [%x]", path)
+		}
+		c := row[nibble]
+		if ref.key = it.leafKey(c); ref.key == nil {
+			path = childPath(path, nibble, c)
+		}
+	}
+	return ref.key, nil
+}
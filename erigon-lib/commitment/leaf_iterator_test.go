@@ -0,0 +1,129 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package commitment
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/length"
+	"github.com/erigontech/erigon-lib/crypto"
+)
+
+func collectLeaves(t *testing.T, ms *MockState, base, from []byte) (keys [][]byte, prev []byte) {
+	t.Helper()
+	branch := func(prefix []byte) ([]byte, error) {
+		data, _, err := ms.Branch(prefix)
+		return data, err
+	}
+	prev, err := IterateLeaves(branch, base, from, func(plainKey []byte) (bool, error) {
+		keys = append(keys, common.Copy(plainKey))
+		return true, nil
+	})
+	require.NoError(t, err)
+	return keys, prev
+}
+
+func TestIterateLeaves(t *testing.T) {
+	t.Parallel()
+
+	ms := NewMockState(t)
+	hph := NewHexPatriciaHashed(length.Addr, ms)
+
+	const accounts, slots = 300, 100
+	contract := fmt.Sprintf("%040x", accounts+1)
+	builder := NewUpdateBuilder().Balance(contract, 1)
+	for i := 0; i < accounts; i++ {
+		builder.Balance(fmt.Sprintf("%040x", i*7919+1), uint64(i+1))
+	}
+	for i := 0; i < slots; i++ {
+		builder.Storage(contract, fmt.Sprintf("%064x", i+1), "01")
+	}
+	plainKeys, updates := builder.Build()
+	require.NoError(t, ms.applyPlainUpdates(plainKeys, updates))
+
+	upds := WrapKeyUpdates(t, ModeDirect, KeyToHexNibbleHash, plainKeys, updates)
+	defer upds.Close()
+	_, err := hph.Process(context.Background(), upds, "")
+	require.NoError(t, err)
+
+	byHash := func(keys [][]byte, hash func([]byte) []byte) {
+		sort.Slice(keys, func(i, j int) bool { return bytes.Compare(hash(keys[i]), hash(keys[j])) < 0 })
+	}
+
+	t.Run("accounts", func(t *testing.T) {
+		var expected [][]byte
+		for _, k := range plainKeys {
+			if len(k) == length.Addr {
+				expected = append(expected, k)
+			}
+		}
+		byHash(expected, func(k []byte) []byte { return crypto.Keccak256(k) })
+
+		keys, prev := collectLeaves(t, ms, nil, make([]byte, length.Hash))
+		require.Equal(t, expected, keys)
+		require.Nil(t, prev)
+
+		// start from an existing key
+		from := crypto.Keccak256(expected[50])
+		keys, prev = collectLeaves(t, ms, nil, from)
+		require.Equal(t, expected[50:], keys)
+		require.Equal(t, expected[49], prev)
+
+		// start right after an existing key
+		next := new(uint256.Int).SetBytes(from)
+		next.AddUint64(next, 1)
+		nextHash := next.Bytes32()
+		keys, prev = collectLeaves(t, ms, nil, nextHash[:])
+		require.Equal(t, expected[51:], keys)
+		require.Equal(t, expected[50], prev)
+	})
+
+	t.Run("storage", func(t *testing.T) {
+		var expected [][]byte
+		for _, k := range plainKeys {
+			if len(k) == length.Addr+length.Hash {
+				expected = append(expected, k)
+			}
+		}
+		require.Len(t, expected, slots)
+		slotHash := func(k []byte) []byte { return crypto.Keccak256(k[length.Addr:]) }
+		byHash(expected, slotHash)
+
+		base := KeyToHexNibbleHash(decodeHex(contract))
+		keys, prev := collectLeaves(t, ms, base, make([]byte, length.Hash))
+		require.Equal(t, expected, keys)
+		require.Nil(t, prev)
+
+		keys, prev = collectLeaves(t, ms, base, slotHash(expected[10]))
+		require.Equal(t, expected[10:], keys)
+		require.Equal(t, expected[9], prev)
+	})
+
+	t.Run("no branch", func(t *testing.T) {
+		branch := func(prefix []byte) ([]byte, error) { return nil, nil }
+		_, err := IterateLeaves(branch, nil, make([]byte, length.Hash), func([]byte) (bool, error) { return true, nil })
+		require.ErrorIs(t, err, ErrNoBranch)
+	})
+}
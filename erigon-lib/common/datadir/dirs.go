@@ -52,6 +52,7 @@ type Dirs struct {
 	CaplinIndexing  string
 	CaplinLatest    string
 	CaplinGenesis   string
+	CaplinValidator string
 }
 
 func New(datadir string) Dirs {
@@ -83,11 +84,12 @@ func New(datadir string) Dirs {
 		CaplinIndexing:  filepath.Join(datadir, "caplin", "indexing"),
 		CaplinLatest:    filepath.Join(datadir, "caplin", "latest"),
 		CaplinGenesis:   filepath.Join(datadir, "caplin", "genesis"),
+		CaplinValidator: filepath.Join(datadir, "caplin", "validator"),
 	}
 
 	dir.MustExist(dirs.Chaindata, dirs.Tmp,
 		dirs.SnapIdx, dirs.SnapHistory, dirs.SnapDomain, dirs.SnapAccessors, dirs.SnapCaplin,
-		dirs.Downloader, dirs.TxPool, dirs.Nodes, dirs.CaplinBlobs, dirs.CaplinIndexing, dirs.CaplinLatest, dirs.CaplinGenesis)
+		dirs.Downloader, dirs.TxPool, dirs.Nodes, dirs.CaplinBlobs, dirs.CaplinIndexing, dirs.CaplinLatest, dirs.CaplinGenesis, dirs.CaplinValidator)
 	err := dirs.RenameOldVersions()
 	if err != nil {
 		panic(err)
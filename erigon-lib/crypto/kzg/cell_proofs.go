@@ -0,0 +1,170 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package kzg
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	goethkzg "github.com/crate-crypto/go-eth-kzg"
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+)
+
+// CellsPerBlob is the number of cells of an extended blob, every blob txn wrapped with cell
+// proofs (EIP-7594) carries that many proofs per blob.
+const CellsPerBlob = goethkzg.CellsPerExtBlob
+
+var (
+	goethkzgCtx    *goethkzg.Context
+	initCellCtx    sync.Once
+	errInvalidBlob = fmt.Errorf("invalid blob length, expected %d", len(goethkzg.Blob{}))
+)
+
+// InitCellCtx initializes the global context object returned via CellCtx
+func InitCellCtx() {
+	initCellCtx.Do(func() {
+		if trustedSetupFile != "" {
+			file, err := os.ReadFile(trustedSetupFile)
+			if err != nil {
+				panic(fmt.Sprintf("could not read file, err: %v", err))
+			}
+
+			setup := new(goethkzg.JSONTrustedSetup)
+			if err = json.Unmarshal(file, setup); err != nil {
+				panic(fmt.Sprintf("could not unmarshal, err: %v", err))
+			}
+
+			goethkzgCtx, err = goethkzg.NewContext4096(setup)
+			if err != nil {
+				panic(fmt.Sprintf("could not create cell KZG context, err: %v", err))
+			}
+		} else {
+			var err error
+			goethkzgCtx, err = goethkzg.NewContext4096Secure()
+			if err != nil {
+				panic(fmt.Sprintf("could not create cell KZG context, err : %v", err))
+			}
+		}
+	})
+}
+
+// CellCtx returns the context used to compute and verify cell proofs (PeerDAS). Same as Ctx,
+// it is expensive to initialize, so production services should call InitCellCtx upfront.
+func CellCtx() *goethkzg.Context {
+	InitCellCtx()
+	return goethkzgCtx
+}
+
+func toCellBlob(blob []byte) (*goethkzg.Blob, error) {
+	if len(blob) != len(goethkzg.Blob{}) {
+		return nil, errInvalidBlob
+	}
+	return (*goethkzg.Blob)(blob), nil
+}
+
+// ComputeCellProofs computes the CellsPerBlob cell proofs of the blob.
+func ComputeCellProofs(blob []byte) ([]gokzg4844.KZGProof, error) {
+	b, err := toCellBlob(blob)
+	if err != nil {
+		return nil, err
+	}
+	_, cellProofs, err := CellCtx().ComputeCellsAndKZGProofs(b, 0)
+	if err != nil {
+		return nil, err
+	}
+	proofs := make([]gokzg4844.KZGProof, len(cellProofs))
+	for i := range cellProofs {
+		proofs[i] = gokzg4844.KZGProof(cellProofs[i])
+	}
+	return proofs, nil
+}
+
+// ComputeCellsAndProofs computes the CellsPerBlob cells of the extended blob along with their proofs, which is
+// what the columns of PeerDAS are made of.
+func ComputeCellsAndProofs(blob []byte) ([][]byte, []gokzg4844.KZGProof, error) {
+	b, err := toCellBlob(blob)
+	if err != nil {
+		return nil, nil, err
+	}
+	cells, cellProofs, err := CellCtx().ComputeCellsAndKZGProofs(b, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	outCells := make([][]byte, len(cells))
+	proofs := make([]gokzg4844.KZGProof, len(cellProofs))
+	for i := range cells {
+		outCells[i] = cells[i][:]
+		proofs[i] = gokzg4844.KZGProof(cellProofs[i])
+	}
+	return outCells, proofs, nil
+}
+
+// VerifyCellProofBatch verifies the cell proofs of the blobs against their commitments. The
+// proofs are expected to be grouped by blob, CellsPerBlob proofs per blob.
+func VerifyCellProofBatch(blobs [][]byte, commitments []gokzg4844.KZGCommitment, proofs []gokzg4844.KZGProof) error {
+	if len(blobs) != len(commitments) || len(proofs) != len(blobs)*CellsPerBlob {
+		return fmt.Errorf("mismatched lengths: blobs=%d commitments=%d proofs=%d", len(blobs), len(commitments), len(proofs))
+	}
+	cellCtx := CellCtx()
+	allCommitments := make([]goethkzg.KZGCommitment, 0, len(proofs))
+	allIndices := make([]uint64, 0, len(proofs))
+	allCells := make([]*goethkzg.Cell, 0, len(proofs))
+	allProofs := make([]goethkzg.KZGProof, len(proofs))
+	for i, blob := range blobs {
+		b, err := toCellBlob(blob)
+		if err != nil {
+			return err
+		}
+		// cells aren't transmitted over the network, they are recomputed from the blob
+		cells, err := cellCtx.ComputeCells(b, 0)
+		if err != nil {
+			return err
+		}
+		for j := range cells {
+			allCommitments = append(allCommitments, goethkzg.KZGCommitment(commitments[i]))
+			allIndices = append(allIndices, uint64(j))
+			allCells = append(allCells, cells[j])
+		}
+	}
+	for i := range proofs {
+		allProofs[i] = goethkzg.KZGProof(proofs[i])
+	}
+	return cellCtx.VerifyCellKZGProofBatch(allCommitments, allIndices, allCells, allProofs)
+}
+
+// VerifyCells verifies cells against the commitments of their blobs. cells[i] is the cell at index cellIndices[i]
+// of the extended blob committed to by commitments[i], proven by proofs[i]. This is how columns received through
+// PeerDAS are checked, as the blobs themselves aren't available.
+func VerifyCells(commitments []gokzg4844.KZGCommitment, cellIndices []uint64, cells [][]byte, proofs []gokzg4844.KZGProof) error {
+	if len(commitments) != len(cells) || len(cellIndices) != len(cells) || len(proofs) != len(cells) {
+		return fmt.Errorf("mismatched lengths: commitments=%d indices=%d cells=%d proofs=%d", len(commitments), len(cellIndices), len(cells), len(proofs))
+	}
+	allCommitments := make([]goethkzg.KZGCommitment, len(cells))
+	allCells := make([]*goethkzg.Cell, len(cells))
+	allProofs := make([]goethkzg.KZGProof, len(cells))
+	for i, cell := range cells {
+		if len(cell) != len(goethkzg.Cell{}) {
+			return fmt.Errorf("invalid cell length %d, expected %d", len(cell), len(goethkzg.Cell{}))
+		}
+		allCommitments[i] = goethkzg.KZGCommitment(commitments[i])
+		allCells[i] = (*goethkzg.Cell)(cell)
+		allProofs[i] = goethkzg.KZGProof(proofs[i])
+	}
+	return CellCtx().VerifyCellKZGProofBatch(allCommitments, cellIndices, allCells, allProofs)
+}
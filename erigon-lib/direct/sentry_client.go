@@ -36,6 +36,7 @@ const (
 	ETH66 = 66
 	ETH67 = 67
 	ETH68 = 68
+	ETH69 = 69
 )
 
 //go:generate mockgen -typed=true -destination=./sentry_client_mock.go -package=direct . SentryClient
@@ -89,7 +90,7 @@ func (c *SentryClientRemote) HandShake(ctx context.Context, in *emptypb.Empty, o
 	c.Lock()
 	defer c.Unlock()
 	switch reply.Protocol {
-	case sentryproto.Protocol_ETH67, sentryproto.Protocol_ETH68:
+	case sentryproto.Protocol_ETH67, sentryproto.Protocol_ETH68, sentryproto.Protocol_ETH69:
 		c.protocol = reply.Protocol
 	default:
 		return nil, fmt.Errorf("unexpected protocol: %d", reply.Protocol)
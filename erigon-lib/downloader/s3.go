@@ -0,0 +1,681 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package downloader
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5" //nolint:gosec
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/c2h5oh/datasize"
+	"github.com/spaolacci/murmur3"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/erigontech/erigon-lib/common/dir"
+	"github.com/erigontech/erigon-lib/downloader/snaptype"
+	"github.com/erigontech/erigon-lib/log/v3"
+)
+
+const (
+	s3DefaultPartSize = 32 * datasize.MB
+	s3MinPartSize     = 5 * datasize.MB // S3 limits
+	s3MaxParts        = 10_000
+
+	s3Attempts    = 3
+	s3Parallelism = 4
+
+	// sha256 of the whole object, S3 etags are md5 of it only for single part uploads
+	s3Sha256Header     = "X-Amz-Meta-Sha256"
+	s3EmptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// S3Config points to a folder of a bucket in an S3-compatible object store (AWS, R2, MinIO, ...). Requests use
+// path-style addressing: Endpoint/Bucket/Prefix/file.
+type S3Config struct {
+	Endpoint string // scheme and host, like https://s3.us-east-1.amazonaws.com or http://127.0.0.1:9000
+	Region   string
+	Bucket   string
+	Prefix   string // without leading and trailing '/'
+
+	// AccessKey, SecretKey - requests are not signed if empty, which is enough to read public buckets
+	AccessKey, SecretKey string
+
+	// PartSize - size of multipart upload parts and of ranged download requests
+	PartSize datasize.ByteSize
+}
+
+// ParseS3Url parses s3://bucket/prefix?region=auto&endpoint=https://<account>.r2.cloudflarestorage.com. The region
+// defaults to AWS_REGION or us-east-1, the endpoint to the AWS one of the region and the credentials are taken from
+// AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY.
+func ParseS3Url(s3Url string) (S3Config, error) {
+	u, err := url.Parse(s3Url)
+	if err != nil {
+		return S3Config{}, err
+	}
+	if u.Scheme != "s3" || u.Host == "" {
+		return S3Config{}, fmt.Errorf("invalid s3 url %q, expected s3://bucket/prefix", s3Url)
+	}
+	cfg := S3Config{
+		Bucket:    u.Host,
+		Prefix:    strings.Trim(u.Path, "/"),
+		Region:    u.Query().Get("region"),
+		Endpoint:  strings.TrimSuffix(u.Query().Get("endpoint"), "/"),
+		AccessKey: os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		PartSize:  s3DefaultPartSize,
+	}
+	if cfg.Region == "" {
+		if cfg.Region = os.Getenv("AWS_REGION"); cfg.Region == "" {
+			cfg.Region = "us-east-1"
+		}
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+	}
+	return cfg, nil
+}
+
+// S3Error is an error response of the object store.
+type S3Error struct {
+	StatusCode int
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+func (e *S3Error) Error() string {
+	return fmt.Sprintf("s3: status=%d, code=%s, message=%s", e.StatusCode, e.Code, e.Message)
+}
+
+func isS3RecoverableError(err error) bool {
+	var s3Err *S3Error
+	if errors.As(err, &s3Err) {
+		return s3Err.StatusCode >= 500 || s3Err.StatusCode == http.StatusTooManyRequests
+	}
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// S3Client talks the S3 API with plain http requests signed with AWS signature v4, no rclone needed.
+type S3Client struct {
+	cfg    S3Config
+	client *http.Client
+	logger log.Logger
+	now    func() time.Time
+}
+
+func NewS3Client(cfg S3Config, logger log.Logger) *S3Client {
+	if cfg.PartSize == 0 {
+		cfg.PartSize = s3DefaultPartSize
+	}
+	cfg.PartSize = max(cfg.PartSize, s3MinPartSize)
+	return &S3Client{cfg: cfg, client: &http.Client{}, logger: logger, now: time.Now}
+}
+
+func (c *S3Client) key(name string) string {
+	if c.cfg.Prefix == "" {
+		return name
+	}
+	return c.cfg.Prefix + "/" + name
+}
+
+// ObjectUrl returns the http url of the file, it's a webseed url of the file if the bucket is public.
+func (c *S3Client) ObjectUrl(name string) string {
+	return c.cfg.Endpoint + "/" + s3Encode(c.cfg.Bucket, false) + "/" + s3Encode(c.key(name), false)
+}
+
+// do sends a signed request for the object name, the bucket if name is empty, and returns the response if its status
+// is 2xx. The body of size bytes must have the sha256 payloadHash.
+func (c *S3Client) do(ctx context.Context, method, name string, query url.Values, header http.Header, body io.Reader, size int64, payloadHash string) (*http.Response, error) {
+	u := c.cfg.Endpoint + "/" + s3Encode(c.cfg.Bucket, false)
+	if name != "" {
+		u = c.ObjectUrl(name)
+	}
+	if len(query) > 0 {
+		u += "?" + s3CanonicalQuery(query)
+	}
+	if size == 0 {
+		body = http.NoBody // otherwise the length counts as unknown
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = size
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	c.sign(req, payloadHash, c.now())
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	s3Err := &S3Error{StatusCode: resp.StatusCode}
+	if b, err := io.ReadAll(resp.Body); err == nil && len(b) > 0 {
+		_ = xml.Unmarshal(b, s3Err)
+	}
+	return nil, s3Err
+}
+
+// withRetry retries op on network errors and 5xx responses, op must be restartable.
+func (c *S3Client) withRetry(ctx context.Context, op func() error) (err error) {
+	for attempt := 1; ; attempt++ {
+		if err = op(); err == nil || attempt == s3Attempts || !isS3RecoverableError(err) {
+			return err
+		}
+		c.logger.Debug("[s3] retrying", "attempt", attempt, "err", err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * time.Second):
+		}
+	}
+}
+
+// sign adds the AWS signature v4 of the request, see
+// https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html
+func (c *S3Client) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if c.cfg.AccessKey == "" {
+		return
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if name == "range" || name == "content-md5" || name == "content-type" || strings.HasPrefix(name, "x-amz-") {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := slices.Sorted(maps.Keys(headers))
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+	canonicalRequest := strings.Join([]string{
+		req.Method, req.URL.EscapedPath(), s3CanonicalQuery(req.URL.Query()), canonicalHeaders.String(), signedHeaders, payloadHash,
+	}, "\n")
+
+	scope := amzDate[:8] + "/" + c.cfg.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+	key := hmacSha256([]byte("AWS4"+c.cfg.SecretKey), amzDate[:8])
+	for _, part := range []string{c.cfg.Region, "s3", "aws4_request"} {
+		key = hmacSha256(key, part)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.cfg.AccessKey, scope, signedHeaders, hex.EncodeToString(hmacSha256(key, stringToSign))))
+}
+
+func hmacSha256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// s3Encode is the uri encoding of the signature: everything but the unreserved characters, and '/' if encodeSlash
+// is not set, is percent-encoded.
+func s3Encode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if ('A' <= ch && ch <= 'Z') || ('a' <= ch && ch <= 'z') || ('0' <= ch && ch <= '9') ||
+			ch == '-' || ch == '_' || ch == '.' || ch == '~' || (ch == '/' && !encodeSlash) {
+			b.WriteByte(ch)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", ch)
+	}
+	return b.String()
+}
+
+func s3CanonicalQuery(query url.Values) string {
+	params := make([]string, 0, len(query))
+	for k, values := range query {
+		for _, v := range values {
+			params = append(params, s3Encode(k, true)+"="+s3Encode(v, true))
+		}
+	}
+	slices.Sort(params)
+	return strings.Join(params, "&")
+}
+
+// List returns the files of the folder, sub-folders are not listed.
+func (c *S3Client) List(ctx context.Context) ([]remoteInfo, error) {
+	prefix := c.key("")
+	var infos []remoteInfo
+	var continuationToken string
+	for {
+		query := url.Values{"list-type": {"2"}, "delimiter": {"/"}}
+		if prefix != "" {
+			query.Set("prefix", prefix)
+		}
+		if continuationToken != "" {
+			query.Set("continuation-token", continuationToken)
+		}
+		var result struct {
+			IsTruncated           bool
+			NextContinuationToken string
+			Contents              []struct {
+				Key          string
+				Size         uint64
+				LastModified time.Time
+			}
+		}
+		if err := c.withRetry(ctx, func() error {
+			resp, err := c.do(ctx, http.MethodGet, "", query, nil, nil, 0, s3EmptyPayloadHash)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			return xml.NewDecoder(resp.Body).Decode(&result)
+		}); err != nil {
+			return nil, fmt.Errorf("s3 list %s: %w", c.ObjectUrl(""), err)
+		}
+
+		for _, obj := range result.Contents {
+			if name := strings.TrimPrefix(obj.Key, prefix); name != "" {
+				infos = append(infos, remoteInfo{Name: name, Size: obj.Size, ModTime: obj.LastModified})
+			}
+		}
+		if !result.IsTruncated {
+			return infos, nil
+		}
+		continuationToken = result.NextContinuationToken
+	}
+}
+
+// WebSeeds lists the folder in the format of the webseed providers: file name -> url. The urls are downloadable
+// only from public buckets.
+func (c *S3Client) WebSeeds(ctx context.Context) (snaptype.WebSeedsFromProvider, error) {
+	infos, err := c.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	response := snaptype.WebSeedsFromProvider{}
+	for _, info := range infos {
+		switch info.Name {
+		case "manifest.txt", "node.txt":
+		default:
+			response[info.Name] = c.ObjectUrl(info.Name)
+		}
+	}
+	return response, nil
+}
+
+// Get reads the whole file, for small files like manifest.txt.
+func (c *S3Client) Get(ctx context.Context, name string) (data []byte, err error) {
+	err = c.withRetry(ctx, func() error {
+		resp, err := c.do(ctx, http.MethodGet, name, nil, nil, nil, 0, s3EmptyPayloadHash)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		data, err = io.ReadAll(resp.Body)
+		return err
+	})
+	return data, err
+}
+
+// Download downloads the file into localPath with ranged requests of PartSize, every range is retried on its own.
+// The file is checked against the sha256 set by Upload, or against the etag if it's the md5 of the file.
+func (c *S3Client) Download(ctx context.Context, name, localPath string) error {
+	var size int64
+	var sum, etag string
+	if err := c.withRetry(ctx, func() error {
+		resp, err := c.do(ctx, http.MethodHead, name, nil, nil, nil, 0, s3EmptyPayloadHash)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		size, sum, etag = resp.ContentLength, resp.Header.Get(s3Sha256Header), strings.Trim(resp.Header.Get("ETag"), `"`)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("s3 head %s: %w", name, err)
+	}
+
+	tmpPath := localPath + ".tmp"
+	defer os.Remove(tmpPath)
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	sha256Hash, md5Hash := sha256.New(), md5.New() //nolint:gosec
+	w := io.MultiWriter(f, sha256Hash, md5Hash)
+	buf := make([]byte, min(int64(c.cfg.PartSize), size))
+	for offset := int64(0); offset < size; offset += int64(len(buf)) {
+		chunk := buf[:min(int64(len(buf)), size-offset)]
+		if err := c.withRetry(ctx, func() error {
+			header := http.Header{"Range": {fmt.Sprintf("bytes=%d-%d", offset, offset+int64(len(chunk))-1)}}
+			resp, err := c.do(ctx, http.MethodGet, name, nil, header, nil, 0, s3EmptyPayloadHash)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusPartialContent && !(resp.StatusCode == http.StatusOK && len(chunk) == int(size)) {
+				return fmt.Errorf("unexpected status %d of a range request", resp.StatusCode)
+			}
+			_, err = io.ReadFull(resp.Body, chunk)
+			return err
+		}); err != nil {
+			return fmt.Errorf("s3 get %s at %d: %w", name, offset, err)
+		}
+		if _, err := w.Write(chunk); err != nil {
+			return err
+		}
+	}
+
+	if err := verifyS3Checksum(name, sum, etag, sha256Hash, md5Hash); err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, localPath)
+}
+
+func verifyS3Checksum(name, sum, etag string, sha256Hash, md5Hash hash.Hash) error {
+	if sum != "" {
+		if got := hex.EncodeToString(sha256Hash.Sum(nil)); got != sum {
+			return fmt.Errorf("s3 get %s: sha256 mismatch: %s, expected %s", name, got, sum)
+		}
+		return nil
+	}
+	if etag != "" && !strings.Contains(etag, "-") { // multipart etags are not the md5 of the file
+		if got := hex.EncodeToString(md5Hash.Sum(nil)); got != etag {
+			return fmt.Errorf("s3 get %s: md5 mismatch: %s, expected %s", name, got, etag)
+		}
+	}
+	return nil
+}
+
+// Upload uploads the file at localPath as name, in one request up to PartSize or with a multipart upload. Every
+// request carries the sha256 of its payload, which the object store checks, and the sha256 of the whole file is
+// kept in the object metadata for Download.
+func (c *S3Client) Upload(ctx context.Context, localPath, name string) error {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	size := st.Size()
+	sum, err := sectionSha256(io.NewSectionReader(f, 0, size))
+	if err != nil {
+		return err
+	}
+	header := http.Header{s3Sha256Header: {sum}}
+
+	if size <= int64(c.cfg.PartSize) {
+		return c.withRetry(ctx, func() error {
+			resp, err := c.do(ctx, http.MethodPut, name, nil, header, io.NewSectionReader(f, 0, size), size, sum)
+			if err != nil {
+				return fmt.Errorf("s3 put %s: %w", name, err)
+			}
+			return resp.Body.Close()
+		})
+	}
+	return c.multipartUpload(ctx, f, size, name, header)
+}
+
+type s3CompletedPart struct {
+	PartNumber int
+	ETag       string
+}
+
+func (c *S3Client) multipartUpload(ctx context.Context, f *os.File, size int64, name string, header http.Header) (err error) {
+	partSize := max(int64(c.cfg.PartSize), (size+s3MaxParts-1)/s3MaxParts)
+
+	var uploadId string
+	if err := c.withRetry(ctx, func() error {
+		resp, err := c.do(ctx, http.MethodPost, name, url.Values{"uploads": {""}}, header, nil, 0, s3EmptyPayloadHash)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		var result struct{ UploadId string }
+		if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return err
+		}
+		uploadId = result.UploadId
+		return nil
+	}); err != nil {
+		return fmt.Errorf("s3 create multipart upload %s: %w", name, err)
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		// parts of unfinished uploads are stored (and billed) until aborted
+		resp, abortErr := c.do(context.WithoutCancel(ctx), http.MethodDelete, name, url.Values{"uploadId": {uploadId}}, nil, nil, 0, s3EmptyPayloadHash)
+		if abortErr != nil {
+			c.logger.Warn("[s3] abort multipart upload", "file", name, "err", abortErr)
+			return
+		}
+		resp.Body.Close()
+	}()
+
+	var parts []s3CompletedPart
+	for offset := int64(0); offset < size; offset += partSize {
+		n := min(partSize, size-offset)
+		part := s3CompletedPart{PartNumber: len(parts) + 1}
+		partSum, err := sectionSha256(io.NewSectionReader(f, offset, n))
+		if err != nil {
+			return err
+		}
+		query := url.Values{"partNumber": {strconv.Itoa(part.PartNumber)}, "uploadId": {uploadId}}
+		if err := c.withRetry(ctx, func() error {
+			resp, err := c.do(ctx, http.MethodPut, name, query, nil, io.NewSectionReader(f, offset, n), n, partSum)
+			if err != nil {
+				return err
+			}
+			part.ETag = resp.Header.Get("ETag")
+			return resp.Body.Close()
+		}); err != nil {
+			return fmt.Errorf("s3 upload part %d of %s: %w", part.PartNumber, name, err)
+		}
+		parts = append(parts, part)
+	}
+
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name          `xml:"CompleteMultipartUpload"`
+		Parts   []s3CompletedPart `xml:"Part"`
+	}{Parts: parts})
+	if err != nil {
+		return err
+	}
+	return c.withRetry(ctx, func() error {
+		resp, err := c.do(ctx, http.MethodPost, name, url.Values{"uploadId": {uploadId}}, nil, bytes.NewReader(body), int64(len(body)), sha256Hex(body))
+		if err != nil {
+			return fmt.Errorf("s3 complete multipart upload %s: %w", name, err)
+		}
+		defer resp.Body.Close()
+		// the completion can fail after the 200 status is sent
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		if bytes.Contains(b, []byte("<Error>")) {
+			s3Err := &S3Error{StatusCode: http.StatusInternalServerError}
+			_ = xml.Unmarshal(b, s3Err)
+			return fmt.Errorf("s3 complete multipart upload %s: %w", name, s3Err)
+		}
+		return nil
+	})
+}
+
+func sectionSha256(r io.Reader) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// RemoteSession syncs the files of a local folder with a remote one, with rclone or with the S3 API.
+type RemoteSession interface {
+	RemoteFsRoot() string
+	LocalFsRoot() string
+	Label() string
+	Upload(ctx context.Context, files ...string) error
+	Download(ctx context.Context, files ...string) error
+	Cat(ctx context.Context, file string) (io.Reader, error)
+	ReadLocalDir(ctx context.Context) ([]fs.DirEntry, error)
+	ReadRemoteDir(ctx context.Context, refresh bool) ([]fs.DirEntry, error)
+	Stop()
+}
+
+var (
+	_ RemoteSession = (*RCloneSession)(nil)
+	_ RemoteSession = (*S3Session)(nil)
+)
+
+// S3Session syncs a local folder with the S3 folder, the same way RCloneSession does it with rclone.
+type S3Session struct {
+	*S3Client
+	sync.Mutex
+	localFs string
+	files   map[string]*rcloneInfo
+}
+
+func (c *S3Client) NewSession(localFs string) *S3Session {
+	return &S3Session{S3Client: c, localFs: localFs, files: map[string]*rcloneInfo{}}
+}
+
+func (s *S3Session) RemoteFsRoot() string {
+	return "s3://" + s.cfg.Bucket + "/" + s.cfg.Prefix
+}
+
+func (s *S3Session) LocalFsRoot() string {
+	return s.localFs
+}
+
+func (s *S3Session) Label() string {
+	return strconv.FormatUint(murmur3.Sum64([]byte(s.localFs+"<->"+s.RemoteFsRoot())), 36)
+}
+
+func (s *S3Session) Stop() {}
+
+func (s *S3Session) Upload(ctx context.Context, files ...string) error {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(s3Parallelism)
+	for _, file := range files {
+		g.Go(func() error {
+			return s.S3Client.Upload(ctx, filepath.Join(s.localFs, file), file)
+		})
+	}
+	return g.Wait()
+}
+
+func (s *S3Session) Download(ctx context.Context, files ...string) error {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(s3Parallelism)
+	for _, file := range files {
+		g.Go(func() error {
+			return s.S3Client.Download(ctx, file, filepath.Join(s.localFs, file))
+		})
+	}
+	return g.Wait()
+}
+
+func (s *S3Session) Cat(ctx context.Context, file string) (io.Reader, error) {
+	data, err := s.Get(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
+
+func (s *S3Session) ReadLocalDir(ctx context.Context) ([]fs.DirEntry, error) {
+	return dir.ReadDir(s.localFs)
+}
+
+// ReadRemoteDir lists the remote folder, the previous listing is reused unless refresh is set.
+func (s *S3Session) ReadRemoteDir(ctx context.Context, refresh bool) ([]fs.DirEntry, error) {
+	s.Lock()
+	listed := len(s.files) > 0
+	s.Unlock()
+
+	if !listed || refresh {
+		remoteInfos, err := s.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		files := make(map[string]*rcloneInfo, len(remoteInfos))
+		for _, ri := range remoteInfos {
+			localInfo, _ := os.Stat(filepath.Join(s.localFs, ri.Name))
+			info := &rcloneInfo{file: ri.Name, remoteInfo: ri, localInfo: localInfo}
+			if snapInfo, isStateFile, ok := snaptype.ParseFileName(s.localFs, ri.Name); ok && !isStateFile {
+				info.snapInfo = &snapInfo
+			}
+			files[ri.Name] = info
+		}
+		s.Lock()
+		s.files = files
+		s.Unlock()
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	entries := make([]fs.DirEntry, 0, len(s.files))
+	for _, info := range s.files {
+		if info.remoteInfo.Size > 0 {
+			entries = append(entries, &dirEntry{&fileInfo{info}})
+		}
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return entries, nil
+}
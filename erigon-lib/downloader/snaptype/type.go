@@ -211,6 +211,24 @@ func RegisterType(enum Enum, name string, versions Versions, rangeExtractor Rang
 	return t
 }
 
+// codecs - seg codec of the types which don't use seg.CodecHuffman, set once during program initialization as well
+var codecs = map[Enum]seg.Codec{}
+
+// SetCodec selects the codec of the new .seg files of the type named name. Existing files keep their codec, the
+// readers recognize it from the file header. The file names don't depend on the codec, so nodes which seed their
+// files must stay on seg.CodecHuffman.
+func SetCodec(name string, codec seg.Codec) error {
+	e, ok := ParseEnum(name)
+	if !ok {
+		return fmt.Errorf("unknown snapshot type %q", name)
+	}
+	codecs[e] = codec
+	return nil
+}
+
+// CodecOf returns the seg codec of the new .seg files of the type.
+func CodecOf(e Enum) seg.Codec { return codecs[e] }
+
 func (s snapType) Enum() Enum {
 	return s.enum
 }
@@ -534,7 +552,9 @@ func BuildIndexWithSnapName(ctx context.Context, info FileInfo, cfg recsplit.Rec
 func ExtractRange(ctx context.Context, f FileInfo, extractor RangeExtractor, indexBuilder IndexBuilder, firstKey FirstKeyGetter, chainDB kv.RoDB, chainConfig *chain.Config, tmpDir string, workers int, lvl log.Lvl, logger log.Logger) (uint64, error) {
 	var lastKeyValue uint64
 
-	sn, err := seg.NewCompressor(ctx, "Snapshot "+f.Type.Name(), f.Path, tmpDir, seg.DefaultCfg, lvl, logger)
+	compressCfg := seg.DefaultCfg
+	compressCfg.Codec = CodecOf(f.Type.Enum())
+	sn, err := seg.NewCompressor(ctx, "Snapshot "+f.Type.Name(), f.Path, tmpDir, compressCfg, lvl, logger)
 
 	if err != nil {
 		return lastKeyValue, err
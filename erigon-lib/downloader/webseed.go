@@ -389,6 +389,14 @@ func (d *WebSeeds) ByFileName(name string) (metainfo.UrlList, bool) {
 }
 
 func (d *WebSeeds) retrieveManifest(ctx context.Context, webSeedProviderUrl *url.URL) (snaptype.WebSeedsFromProvider, error) {
+	if webSeedProviderUrl.Scheme == "s3" { // allow: s3://bucket/v2, listed with the S3 API instead of manifest.txt
+		s3Cfg, err := ParseS3Url(webSeedProviderUrl.String())
+		if err != nil {
+			return nil, err
+		}
+		return NewS3Client(s3Cfg, d.logger).WebSeeds(ctx)
+	}
+
 	// allow: host.com/v2/manifest.txt
 	u := webSeedProviderUrl.JoinPath("manifest.txt")
 	{ //do HEAD request with small timeout first
@@ -62,6 +62,10 @@ const (
 	MessageId_POOLED_TRANSACTIONS_66     MessageId = 31
 	// ======= eth 68 protocol ===========
 	MessageId_NEW_POOLED_TRANSACTION_HASHES_68 MessageId = 32
+	// ======= eth 69 protocol ===========
+	MessageId_BLOCK_RANGE_UPDATE_69 MessageId = 33
+	MessageId_GET_RECEIPTS_69       MessageId = 34
+	MessageId_RECEIPTS_69           MessageId = 35
 )
 
 // Enum value maps for MessageId.
@@ -99,6 +103,9 @@ var (
 		30: "RECEIPTS_66",
 		31: "POOLED_TRANSACTIONS_66",
 		32: "NEW_POOLED_TRANSACTION_HASHES_68",
+		33: "BLOCK_RANGE_UPDATE_69",
+		34: "GET_RECEIPTS_69",
+		35: "RECEIPTS_69",
 	}
 	MessageId_value = map[string]int32{
 		"STATUS_65":                        0,
@@ -133,6 +140,9 @@ var (
 		"RECEIPTS_66":                      30,
 		"POOLED_TRANSACTIONS_66":           31,
 		"NEW_POOLED_TRANSACTION_HASHES_68": 32,
+		"BLOCK_RANGE_UPDATE_69":            33,
+		"GET_RECEIPTS_69":                  34,
+		"RECEIPTS_69":                      35,
 	}
 )
 
@@ -213,6 +223,7 @@ const (
 	Protocol_ETH66 Protocol = 1
 	Protocol_ETH67 Protocol = 2
 	Protocol_ETH68 Protocol = 3
+	Protocol_ETH69 Protocol = 4
 )
 
 // Enum value maps for Protocol.
@@ -222,12 +233,14 @@ var (
 		1: "ETH66",
 		2: "ETH67",
 		3: "ETH68",
+		4: "ETH69",
 	}
 	Protocol_value = map[string]int32{
 		"ETH65": 0,
 		"ETH66": 1,
 		"ETH67": 2,
 		"ETH68": 3,
+		"ETH69": 4,
 	}
 )
 
@@ -834,15 +847,16 @@ func (x *Forks) GetTimeForks() []uint64 {
 }
 
 type StatusData struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	NetworkId       uint64                 `protobuf:"varint,1,opt,name=network_id,json=networkId,proto3" json:"network_id,omitempty"`
-	TotalDifficulty *typesproto.H256       `protobuf:"bytes,2,opt,name=total_difficulty,json=totalDifficulty,proto3" json:"total_difficulty,omitempty"`
-	BestHash        *typesproto.H256       `protobuf:"bytes,3,opt,name=best_hash,json=bestHash,proto3" json:"best_hash,omitempty"`
-	ForkData        *Forks                 `protobuf:"bytes,4,opt,name=fork_data,json=forkData,proto3" json:"fork_data,omitempty"`
-	MaxBlockHeight  uint64                 `protobuf:"varint,5,opt,name=max_block_height,json=maxBlockHeight,proto3" json:"max_block_height,omitempty"`
-	MaxBlockTime    uint64                 `protobuf:"varint,6,opt,name=max_block_time,json=maxBlockTime,proto3" json:"max_block_time,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	NetworkId          uint64                 `protobuf:"varint,1,opt,name=network_id,json=networkId,proto3" json:"network_id,omitempty"`
+	TotalDifficulty    *typesproto.H256       `protobuf:"bytes,2,opt,name=total_difficulty,json=totalDifficulty,proto3" json:"total_difficulty,omitempty"`
+	BestHash           *typesproto.H256       `protobuf:"bytes,3,opt,name=best_hash,json=bestHash,proto3" json:"best_hash,omitempty"`
+	ForkData           *Forks                 `protobuf:"bytes,4,opt,name=fork_data,json=forkData,proto3" json:"fork_data,omitempty"`
+	MaxBlockHeight     uint64                 `protobuf:"varint,5,opt,name=max_block_height,json=maxBlockHeight,proto3" json:"max_block_height,omitempty"`
+	MaxBlockTime       uint64                 `protobuf:"varint,6,opt,name=max_block_time,json=maxBlockTime,proto3" json:"max_block_time,omitempty"`
+	MinimumBlockHeight uint64                 `protobuf:"varint,7,opt,name=minimum_block_height,json=minimumBlockHeight,proto3" json:"minimum_block_height,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *StatusData) Reset() {
@@ -917,6 +931,13 @@ func (x *StatusData) GetMaxBlockTime() uint64 {
 	return 0
 }
 
+func (x *StatusData) GetMinimumBlockHeight() uint64 {
+	if x != nil {
+		return x.MinimumBlockHeight
+	}
+	return 0
+}
+
 type SetStatusReply struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -1481,7 +1502,7 @@ const file_p2psentry_sentry_proto_rawDesc = "" +
 	"\agenesis\x18\x01 \x01(\v2\v.types.H256R\agenesis\x12!\n" +
 	"\fheight_forks\x18\x02 \x03(\x04R\vheightForks\x12\x1d\n" +
 	"\n" +
-	"time_forks\x18\x03 \x03(\x04R\ttimeForks\"\x89\x02\n" +
+	"time_forks\x18\x03 \x03(\x04R\ttimeForks\"\xbb\x02\n" +
 	"\n" +
 	"StatusData\x12\x1d\n" +
 	"\n" +
@@ -1490,7 +1511,8 @@ const file_p2psentry_sentry_proto_rawDesc = "" +
 	"\tbest_hash\x18\x03 \x01(\v2\v.types.H256R\bbestHash\x12*\n" +
 	"\tfork_data\x18\x04 \x01(\v2\r.sentry.ForksR\bforkData\x12(\n" +
 	"\x10max_block_height\x18\x05 \x01(\x04R\x0emaxBlockHeight\x12$\n" +
-	"\x0emax_block_time\x18\x06 \x01(\x04R\fmaxBlockTime\"\x10\n" +
+	"\x0emax_block_time\x18\x06 \x01(\x04R\fmaxBlockTime\x120\n" +
+	"\x14minimum_block_height\x18\a \x01(\x04R\x12minimumBlockHeight\"\x10\n" +
 	"\x0eSetStatusReply\">\n" +
 	"\x0eHandShakeReply\x12,\n" +
 	"\bprotocol\x18\x01 \x01(\x0e2\x10.sentry.ProtocolR\bprotocol\"6\n" +
@@ -1520,7 +1542,7 @@ const file_p2psentry_sentry_proto_rawDesc = "" +
 	"\n" +
 	"Disconnect\x10\x01\"(\n" +
 	"\fAddPeerReply\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess*\x80\x06\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess*\xc1\x06\n" +
 	"\tMessageId\x12\r\n" +
 	"\tSTATUS_65\x10\x00\x12\x18\n" +
 	"\x14GET_BLOCK_HEADERS_65\x10\x01\x12\x14\n" +
@@ -1554,14 +1576,18 @@ const file_p2psentry_sentry_proto_rawDesc = "" +
 	"\fNODE_DATA_66\x10\x1d\x12\x0f\n" +
 	"\vRECEIPTS_66\x10\x1e\x12\x1a\n" +
 	"\x16POOLED_TRANSACTIONS_66\x10\x1f\x12$\n" +
-	" NEW_POOLED_TRANSACTION_HASHES_68\x10 *\x17\n" +
+	" NEW_POOLED_TRANSACTION_HASHES_68\x10 \x12\x19\n" +
+	"\x15BLOCK_RANGE_UPDATE_69\x10!\x12\x13\n" +
+	"\x0fGET_RECEIPTS_69\x10\"\x12\x0f\n" +
+	"\vRECEIPTS_69\x10#*\x17\n" +
 	"\vPenaltyKind\x12\b\n" +
-	"\x04Kick\x10\x00*6\n" +
+	"\x04Kick\x10\x00*A\n" +
 	"\bProtocol\x12\t\n" +
 	"\x05ETH65\x10\x00\x12\t\n" +
 	"\x05ETH66\x10\x01\x12\t\n" +
 	"\x05ETH67\x10\x02\x12\t\n" +
-	"\x05ETH68\x10\x032\xdc\a\n" +
+	"\x05ETH68\x10\x03\x12\t\n" +
+	"\x05ETH69\x10\x042\xdc\a\n" +
 	"\x06Sentry\x127\n" +
 	"\tSetStatus\x12\x12.sentry.StatusData\x1a\x16.sentry.SetStatusReply\x12C\n" +
 	"\fPenalizePeer\x12\x1b.sentry.PenalizePeerRequest\x1a\x16.google.protobuf.Empty\x12C\n" +
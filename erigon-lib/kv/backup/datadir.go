@@ -0,0 +1,485 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/c2h5oh/datasize"
+
+	"github.com/erigontech/erigon-lib/common/datadir"
+	"github.com/erigontech/erigon-lib/common/dir"
+	"github.com/erigontech/erigon-lib/downloader/snaptype"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/log/v3"
+)
+
+/*
+A datadir backup is a folder of the backup root named by its UTC creation time:
+
+	<root>/20250102-030405/manifest.json
+	<root>/20250102-030405/chaindata/mdbx.dat
+	<root>/20250102-030405/snapshots/...
+
+Every backup is complete on its own: snapshot files which are already in the previous backup of the root are
+hardlinked from there, so only the files produced since then are copied (or hardlinked from the datadir if it's on
+the same filesystem). Deleting an old backup never affects the newer ones.
+
+Only immutable snapshot files (segments, domain and history files, their indices) are hardlinked: the other files
+of the snapshots folder (locks, preverified.toml, salt files, ...) are rewritten in place, which would change the
+backup through the shared inode. They are always copied.
+
+Chaindata is copied first, in one read transaction: the snapshot files collected after it can only extend what the
+db copy covers, while the db pruning done meanwhile is not visible in the copy.
+
+Backups are built in a ".tmp" folder and renamed at the end, folders without manifest are ignored.
+*/
+
+const (
+	ManifestFileName = "manifest.json"
+	manifestVersion  = 1
+
+	backupNameLayout = "20060102-150405"
+
+	chaindataDir  = "chaindata"        // relative to the datadir, see datadir.New
+	snapCaplinDir = "snapshots/caplin" // relative to the datadir, see datadir.New
+)
+
+// Manifest lists the files of a datadir backup. Paths are relative to the backup folder and to the datadir.
+type Manifest struct {
+	Version int            `json:"version"`
+	Created time.Time      `json:"created"`
+	DataDir string         `json:"datadir"`
+	Files   []ManifestFile `json:"files"`
+}
+
+type ManifestFile struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+	// ModTime - of the datadir file, together with the size it tells if the file of the previous backup can be reused
+	ModTime time.Time `json:"modTime"`
+	Sha256  string    `json:"sha256"`
+}
+
+func (m *Manifest) Size() (size int64) {
+	for _, f := range m.Files {
+		size += f.Size
+	}
+	return size
+}
+
+func ReadManifest(backupDir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(backupDir, ManifestFileName))
+	if err != nil {
+		return nil, err
+	}
+	m := &Manifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("%s: %w", ManifestFileName, err)
+	}
+	if m.Version != manifestVersion {
+		return nil, fmt.Errorf("%s: unsupported version %d", ManifestFileName, m.Version)
+	}
+	return m, nil
+}
+
+func writeManifest(backupDir string, m *Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return dir.WriteFileWithFsync(filepath.Join(backupDir, ManifestFileName), data, 0644)
+}
+
+// LatestBackup returns the folder of the newest complete backup in root, "" if there is none.
+func LatestBackup(root string) (string, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", nil
+		}
+		return "", err
+	}
+	for _, e := range slices.Backward(entries) { // ReadDir sorts by name, names sort by time
+		if !e.IsDir() || strings.HasSuffix(e.Name(), ".tmp") {
+			continue
+		}
+		if exists, err := dir.FileExist(filepath.Join(root, e.Name(), ManifestFileName)); err != nil {
+			return "", err
+		} else if exists {
+			return filepath.Join(root, e.Name()), nil
+		}
+	}
+	return "", nil
+}
+
+// Datadir makes a new backup of the chaindata and snapshot files of dirs in root and returns its folder. It's safe
+// to run on the datadir of a running node.
+func Datadir(ctx context.Context, dirs datadir.Dirs, root string, logger log.Logger) (string, error) {
+	var prev *Manifest
+	prevDir, err := LatestBackup(root)
+	if err != nil {
+		return "", err
+	}
+	if prevDir != "" {
+		if prev, err = ReadManifest(prevDir); err != nil {
+			return "", fmt.Errorf("previous backup %s: %w", prevDir, err)
+		}
+	}
+
+	m := &Manifest{Version: manifestVersion, Created: time.Now().UTC(), DataDir: dirs.DataDir}
+	backupDir := filepath.Join(root, m.Created.Format(backupNameLayout))
+	if exists, err := dir.Exist(backupDir); err != nil {
+		return "", err
+	} else if exists {
+		return "", fmt.Errorf("backup %s already exists", backupDir)
+	}
+	tmpDir := backupDir + ".tmp"
+	if err := os.RemoveAll(tmpDir); err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmpDir)
+	logger.Info("[backup] start", "datadir", dirs.DataDir, "to", backupDir, "previous", prevDir)
+
+	chaindata, err := backupChaindata(ctx, dirs, tmpDir, logger)
+	if err != nil {
+		return "", fmt.Errorf("chaindata: %w", err)
+	}
+	m.Files = append(m.Files, chaindata)
+
+	snapshots, err := backupSnapshots(ctx, dirs, tmpDir, prev, prevDir, logger)
+	if err != nil {
+		return "", fmt.Errorf("snapshots: %w", err)
+	}
+	m.Files = append(m.Files, snapshots...)
+
+	if err := writeManifest(tmpDir, m); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmpDir, backupDir); err != nil {
+		return "", err
+	}
+	logger.Info("[backup] done", "dir", backupDir, "files", len(m.Files), "size", datasize.ByteSize(m.Size()).HR())
+	return backupDir, nil
+}
+
+func backupChaindata(ctx context.Context, dirs datadir.Dirs, backupDir string, logger log.Logger) (ManifestFile, error) {
+	to := filepath.Join(backupDir, chaindataDir)
+	if err := func() error {
+		src, dst := OpenPair(dirs.Chaindata, to, kv.ChainDB, 0, logger)
+		defer src.Close()
+		defer dst.Close()
+		return Kv2kv(ctx, src, dst, nil, ReadAheadThreads, logger)
+	}(); err != nil {
+		return ManifestFile{}, err
+	}
+	// mdbx.lck is re-created on open
+	if err := os.Remove(filepath.Join(to, "mdbx.lck")); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return ManifestFile{}, err
+	}
+
+	path := filepath.Join(chaindataDir, "mdbx.dat")
+	st, err := os.Stat(filepath.Join(backupDir, path))
+	if err != nil {
+		return ManifestFile{}, err
+	}
+	hash, err := hashFile(filepath.Join(backupDir, path))
+	if err != nil {
+		return ManifestFile{}, err
+	}
+	return ManifestFile{Path: path, Size: st.Size(), ModTime: st.ModTime(), Sha256: hash}, nil
+}
+
+// backupSnapshots puts the snapshot files of dirs into backupDir, reusing the files of prev. A file can be removed
+// by a merge while it's walked, the folder is walked again until it brings nothing new: the merged file covers it.
+func backupSnapshots(ctx context.Context, dirs datadir.Dirs, backupDir string, prev *Manifest, prevDir string, logger log.Logger) ([]ManifestFile, error) {
+	logEvery := time.NewTicker(20 * time.Second)
+	defer logEvery.Stop()
+
+	prevFiles := map[string]ManifestFile{}
+	if prev != nil {
+		for _, f := range prev.Files {
+			prevFiles[f.Path] = f
+		}
+	}
+
+	var files []ManifestFile
+	done := map[string]bool{}
+	var reused, copied int
+	for {
+		paths, err := snapshotFiles(dirs)
+		if err != nil {
+			return nil, err
+		}
+		var added bool
+		for _, path := range paths {
+			if done[path] {
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-logEvery.C:
+				logger.Info("[backup] snapshots", "files", len(files), "reused", reused, "copied", copied)
+			default:
+			}
+
+			src := filepath.Join(dirs.DataDir, path)
+			st, err := os.Stat(src)
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					continue
+				}
+				return nil, err
+			}
+			f := ManifestFile{Path: path, Size: st.Size(), ModTime: st.ModTime()}
+			dst := filepath.Join(backupDir, path)
+			if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+				return nil, err
+			}
+
+			immutable := isImmutableSnapshotFile(path)
+			if p, ok := prevFiles[path]; ok && immutable && p.Size == f.Size && p.ModTime.Equal(f.ModTime) && os.Link(filepath.Join(prevDir, path), dst) == nil {
+				f.Sha256 = p.Sha256
+				reused++
+			} else {
+				if immutable {
+					f.Sha256, err = linkOrCopy(src, dst, st)
+				} else {
+					f.Sha256, err = copyFile(src, dst, st)
+				}
+				if err != nil {
+					if errors.Is(err, fs.ErrNotExist) {
+						continue
+					}
+					return nil, err
+				}
+				copied++
+			}
+			files = append(files, f)
+			done[path] = true
+			added = true
+		}
+		if !added {
+			break
+		}
+	}
+	slices.SortFunc(files, func(a, b ManifestFile) int { return strings.Compare(a.Path, b.Path) })
+	logger.Info("[backup] snapshots", "files", len(files), "reused", reused, "copied", copied)
+	return files, nil
+}
+
+// snapshotFiles returns the paths, relative to the datadir, of the files under dirs.Snap. Unfinished ".tmp" files
+// are skipped.
+func snapshotFiles(dirs datadir.Dirs) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(dirs.Snap, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) { // removed after listing its folder
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() || strings.HasPrefix(d.Name(), ".") || strings.HasSuffix(d.Name(), ".tmp") {
+			return nil
+		}
+		paths = append(paths, relPath(dirs.DataDir, path))
+		return nil
+	})
+	return paths, err
+}
+
+// isImmutableSnapshotFile tells whether the file, relative to the datadir, is a snapshot file which is never modified
+// once written, so it can be shared by hardlinks.
+func isImmutableSnapshotFile(path string) bool {
+	name := filepath.Base(path)
+	ext := filepath.Ext(name)
+	if ext == ".txt" || // salt files
+		!slices.Contains(snaptype.AllV2Extensions(), ext) && !slices.Contains(snaptype.AllV3Extensions(), ext) && !slices.Contains(snaptype.SeedableV3Extensions(), ext) {
+		return false
+	}
+	// caplin files are named after caplin types, which snaptype doesn't parse
+	if strings.HasPrefix(filepath.ToSlash(path), snapCaplinDir+"/") {
+		return true
+	}
+	// block file types are registered outside of erigon-lib, their range is parsed all the same
+	info, _, ok := snaptype.ParseFileName(filepath.Dir(path), name)
+	return ok || info.To > info.From
+}
+
+// Verify checks that the files of the backup match its manifest, and returns the manifest.
+func Verify(ctx context.Context, backupDir string, logger log.Logger) (*Manifest, error) {
+	m, err := ReadManifest(backupDir)
+	if err != nil {
+		return nil, err
+	}
+	logEvery := time.NewTicker(20 * time.Second)
+	defer logEvery.Stop()
+	for i, f := range m.Files {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-logEvery.C:
+			logger.Info("[backup] verify", "progress", fmt.Sprintf("%d/%d", i, len(m.Files)))
+		default:
+		}
+		if err := verifyFile(filepath.Join(backupDir, f.Path), f); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func verifyFile(path string, f ManifestFile) error {
+	st, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if st.Size() != f.Size {
+		return fmt.Errorf("%s: size %d, expected %d", f.Path, st.Size(), f.Size)
+	}
+	hash, err := hashFile(path)
+	if err != nil {
+		return err
+	}
+	if hash != f.Sha256 {
+		return fmt.Errorf("%s: sha256 %s, expected %s", f.Path, hash, f.Sha256)
+	}
+	return nil
+}
+
+// Restore puts the files of the backup into the datadir, which must not have any of them yet. Every file is checked
+// against the manifest. Immutable snapshot files are hardlinked when the datadir is on the same filesystem, chaindata
+// and the other files are always copied: they are modified in place.
+func Restore(ctx context.Context, backupDir, dataDir string, logger log.Logger) error {
+	m, err := ReadManifest(backupDir)
+	if err != nil {
+		return err
+	}
+	for _, f := range m.Files {
+		if exists, err := dir.FileExist(filepath.Join(dataDir, f.Path)); err != nil {
+			return err
+		} else if exists {
+			return fmt.Errorf("%s already exists in %s", f.Path, dataDir)
+		}
+	}
+	logger.Info("[restore] start", "from", backupDir, "datadir", dataDir, "files", len(m.Files), "size", datasize.ByteSize(m.Size()).HR())
+
+	logEvery := time.NewTicker(20 * time.Second)
+	defer logEvery.Stop()
+	for i, f := range m.Files {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-logEvery.C:
+			logger.Info("[restore] progress", "files", fmt.Sprintf("%d/%d", i, len(m.Files)))
+		default:
+		}
+		src, dst := filepath.Join(backupDir, f.Path), filepath.Join(dataDir, f.Path)
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return err
+		}
+		st, err := os.Stat(src)
+		if err != nil {
+			return err
+		}
+		var hash string
+		if isImmutableSnapshotFile(f.Path) {
+			hash, err = linkOrCopy(src, dst, st)
+		} else {
+			hash, err = copyFile(src, dst, st)
+		}
+		if err != nil {
+			return err
+		}
+		if hash != f.Sha256 || st.Size() != f.Size {
+			return fmt.Errorf("%s doesn't match the manifest, the backup is corrupted", f.Path)
+		}
+	}
+	logger.Info("[restore] done", "datadir", dataDir)
+	return nil
+}
+
+// linkOrCopy hardlinks src to dst, or copies it if they are on different filesystems, and returns the sha256 of it.
+func linkOrCopy(src, dst string, st os.FileInfo) (string, error) {
+	if err := os.Link(src, dst); err == nil {
+		return hashFile(dst)
+	}
+	return copyFile(src, dst, st)
+}
+
+// copyFile copies src to dst, keeping its mod time, and returns the sha256 of it.
+func copyFile(src, dst string, st os.FileInfo) (string, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, st.Mode().Perm())
+	if err != nil {
+		return "", err
+	}
+	defer out.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(out, h), in); err != nil {
+		return "", err
+	}
+	if err := out.Sync(); err != nil {
+		return "", err
+	}
+	if err := out.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Chtimes(dst, st.ModTime(), st.ModTime()); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func relPath(base, path string) string {
+	rel, err := filepath.Rel(base, path)
+	if err != nil {
+		panic(err) // paths of datadir.Dirs are all under DataDir
+	}
+	return rel
+}
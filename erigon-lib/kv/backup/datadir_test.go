@@ -0,0 +1,114 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/common/datadir"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/kv/mdbx"
+	"github.com/erigontech/erigon-lib/log/v3"
+)
+
+func TestDatadirBackup(t *testing.T) {
+	ctx, logger := context.Background(), log.New()
+	dirs := datadir.New(t.TempDir())
+	root := t.TempDir()
+
+	db := mdbx.New(kv.ChainDB, logger).Path(dirs.Chaindata).MustOpen()
+	require.NoError(t, db.Update(ctx, func(tx kv.RwTx) error {
+		return tx.Put(kv.Headers, []byte{1}, []byte("header"))
+	}))
+	db.Close()
+
+	writeFile := func(path, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dirs.DataDir, path), []byte(content), 0644))
+	}
+	writeFile("snapshots/v1.0-000000-000500-headers.seg", "headers")
+	writeFile("snapshots/domain/v1.0-accounts.0-32.kv", "accounts")
+	writeFile("snapshots/domain/v1.0-accounts.0-32.kv.tmp", "unfinished")
+	writeFile("snapshots/preverified.toml", "preverified")
+	writeFile("snapshots/salt-blocks.txt", "salt")
+
+	first, err := Datadir(ctx, dirs, root, logger)
+	require.NoError(t, err)
+	m, err := Verify(ctx, first, logger)
+	require.NoError(t, err)
+	require.Equal(t, dirs.DataDir, m.DataDir)
+	require.Len(t, m.Files, 5)
+	require.Equal(t, "chaindata/mdbx.dat", m.Files[0].Path)
+	require.Equal(t, "snapshots/domain/v1.0-accounts.0-32.kv", m.Files[1].Path)
+
+	// files rewritten in place are never shared with the datadir
+	sameFile := func(a, b string) bool {
+		st1, err := os.Stat(a)
+		require.NoError(t, err)
+		st2, err := os.Stat(b)
+		require.NoError(t, err)
+		return os.SameFile(st1, st2)
+	}
+	for _, path := range []string{"snapshots/preverified.toml", "snapshots/salt-blocks.txt"} {
+		require.False(t, sameFile(filepath.Join(dirs.DataDir, path), filepath.Join(first, path)), path)
+	}
+	require.True(t, sameFile(filepath.Join(dirs.DataDir, "snapshots/domain/v1.0-accounts.0-32.kv"), filepath.Join(first, "snapshots/domain/v1.0-accounts.0-32.kv")))
+
+	// backups are named by time, pretend the first one is older to not wait for the next second
+	older := filepath.Join(root, "20250101-000000")
+	require.NoError(t, os.Rename(first, older))
+	first = older
+
+	writeFile("snapshots/v1.0-000500-001000-headers.seg", "more headers")
+	second, err := Datadir(ctx, dirs, root, logger)
+	require.NoError(t, err)
+	latest, err := LatestBackup(root)
+	require.NoError(t, err)
+	require.Equal(t, second, latest)
+	m, err = Verify(ctx, second, logger)
+	require.NoError(t, err)
+	require.Len(t, m.Files, 6)
+
+	// unchanged files of the first backup are reused
+	require.True(t, sameFile(filepath.Join(first, "snapshots/v1.0-000000-000500-headers.seg"), filepath.Join(second, "snapshots/v1.0-000000-000500-headers.seg")))
+	require.False(t, sameFile(filepath.Join(first, "snapshots/preverified.toml"), filepath.Join(second, "snapshots/preverified.toml")))
+
+	restored := t.TempDir()
+	require.NoError(t, Restore(ctx, second, restored, logger))
+	for _, f := range m.Files {
+		require.NoError(t, verifyFile(filepath.Join(restored, f.Path), f))
+	}
+	require.Error(t, Restore(ctx, second, restored, logger), "restore must not overwrite files")
+	require.False(t, sameFile(filepath.Join(second, "snapshots/preverified.toml"), filepath.Join(restored, "snapshots/preverified.toml")))
+	require.True(t, sameFile(filepath.Join(second, "snapshots/domain/v1.0-accounts.0-32.kv"), filepath.Join(restored, "snapshots/domain/v1.0-accounts.0-32.kv")))
+
+	restoredDB := mdbx.New(kv.ChainDB, logger).Path(filepath.Join(restored, "chaindata")).MustOpen()
+	defer restoredDB.Close()
+	require.NoError(t, restoredDB.View(ctx, func(tx kv.Tx) error {
+		v, err := tx.GetOne(kv.Headers, []byte{1})
+		require.Equal(t, []byte("header"), v)
+		return err
+	}))
+
+	require.NoError(t, os.WriteFile(filepath.Join(second, "snapshots/v1.0-000500-001000-headers.seg"), []byte("corrupted!!!"), 0644))
+	_, err = Verify(ctx, second, logger)
+	require.ErrorContains(t, err, "sha256")
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package kv
+
+import (
+	"fmt"
+	"regexp"
+	"sync/atomic"
+)
+
+// FirstCustomInvertedIdx is the id of the first inverted index declared with RegisterCustomInvertedIdx, the built-in
+// indices stay below it.
+const FirstCustomInvertedIdx InvertedIdx = 128
+
+// CustomInvertedIdx is an application-specific inverted index. Its name is also the filename base of its snapshot
+// files, so it has to stay the same for the lifetime of a datadir.
+type CustomInvertedIdx struct {
+	Idx         InvertedIdx
+	Name        string
+	KeysTable   string
+	ValuesTable string
+}
+
+var customInvertedIdxs []CustomInvertedIdx
+
+// chaindataTablesInUse is set once a db got the chaindata schema, the tables of a later index would be missing from it
+var chaindataTablesInUse atomic.Bool
+
+// names end up in snapshot filenames, which are split on '-' and '.'
+var customInvertedIdxName = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
+// RegisterCustomInvertedIdx declares an inverted index on top of the built-in ones and adds its tables to the
+// chaindata schema. It's not thread-safe and must be called before the chaindata db is opened, usually from an init
+// function of the embedding program, it fails afterwards.
+func RegisterCustomInvertedIdx(name string) (CustomInvertedIdx, error) {
+	if chaindataTablesInUse.Load() {
+		return CustomInvertedIdx{}, fmt.Errorf("custom inverted index %s: registered after the chaindata db was opened", name)
+	}
+	if !customInvertedIdxName.MatchString(name) {
+		return CustomInvertedIdx{}, fmt.Errorf("custom inverted index name %q: only lowercase letters and digits are allowed", name)
+	}
+	if _, err := String2InvertedIdx(name); err == nil {
+		return CustomInvertedIdx{}, fmt.Errorf("inverted index %s already registered", name)
+	}
+
+	ii := CustomInvertedIdx{
+		Idx:         FirstCustomInvertedIdx + InvertedIdx(len(customInvertedIdxs)),
+		Name:        name,
+		KeysTable:   "CustomKeys_" + name,
+		ValuesTable: "CustomIdx_" + name,
+	}
+	customInvertedIdxs = append(customInvertedIdxs, ii)
+
+	ChaindataTables = append(ChaindataTables, ii.KeysTable, ii.ValuesTable)
+	ChaindataTablesCfg[ii.KeysTable] = TableCfgItem{Flags: DupSort}
+	ChaindataTablesCfg[ii.ValuesTable] = TableCfgItem{Flags: DupSort}
+	sortBuckets()
+	return ii, nil
+}
+
+// CustomInvertedIdxs returns the inverted indices declared with RegisterCustomInvertedIdx, in registration order.
+func CustomInvertedIdxs() []CustomInvertedIdx {
+	return customInvertedIdxs
+}
+
+func customInvertedIdx(idx InvertedIdx) (CustomInvertedIdx, bool) {
+	if idx < FirstCustomInvertedIdx || int(idx-FirstCustomInvertedIdx) >= len(customInvertedIdxs) {
+		return CustomInvertedIdx{}, false
+	}
+	return customInvertedIdxs[idx-FirstCustomInvertedIdx], true
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package kv
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterCustomInvertedIdx(t *testing.T) {
+	tables := slices.Clone(ChaindataTables)
+	t.Cleanup(func() {
+		for _, ii := range customInvertedIdxs {
+			delete(ChaindataTablesCfg, ii.KeysTable)
+			delete(ChaindataTablesCfg, ii.ValuesTable)
+		}
+		customInvertedIdxs = nil
+		ChaindataTables = tables
+	})
+
+	transfers, err := RegisterCustomInvertedIdx("nfttransfers")
+	require.NoError(t, err)
+	require.Equal(t, FirstCustomInvertedIdx, transfers.Idx)
+	swaps, err := RegisterCustomInvertedIdx("dexswaps")
+	require.NoError(t, err)
+	require.Equal(t, FirstCustomInvertedIdx+1, swaps.Idx)
+	require.Equal(t, []CustomInvertedIdx{transfers, swaps}, CustomInvertedIdxs())
+
+	require.Equal(t, "dexswaps", swaps.Idx.String())
+	idx, err := String2InvertedIdx("nfttransfers")
+	require.NoError(t, err)
+	require.Equal(t, transfers.Idx, idx)
+	require.Equal(t, "unknown index", (FirstCustomInvertedIdx + 2).String())
+
+	for _, table := range []string{transfers.KeysTable, transfers.ValuesTable, swaps.KeysTable, swaps.ValuesTable} {
+		require.Contains(t, ChaindataTables, table)
+		require.Equal(t, DupSort, ChaindataTablesCfg[table].Flags)
+	}
+	require.True(t, slices.IsSorted(ChaindataTables))
+
+	_, err = RegisterCustomInvertedIdx("dexswaps")
+	require.Error(t, err)
+	_, err = RegisterCustomInvertedIdx("logtopics")
+	require.Error(t, err)
+	_, err = RegisterCustomInvertedIdx("dex-swaps")
+	require.Error(t, err)
+	_, err = RegisterCustomInvertedIdx("")
+	require.Error(t, err)
+	require.Len(t, CustomInvertedIdxs(), 2)
+
+	// the schema is fixed once a db is opened with it
+	TablesCfgByLabel(ChainDB)
+	t.Cleanup(func() { chaindataTablesInUse.Store(false) })
+	_, err = RegisterCustomInvertedIdx("nftmints")
+	require.ErrorContains(t, err, "after the chaindata db was opened")
+	require.Len(t, CustomInvertedIdxs(), 2)
+}
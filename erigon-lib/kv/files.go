@@ -30,4 +30,7 @@ const (
 	FileLogTopicsIdx  = "logtopics"
 	FileTracesFromIdx = "tracesfrom"
 	FileTracesToIdx   = "tracesto"
+
+	FileTokenTransfersFromIdx = "tokentransfersfrom"
+	FileTokenTransfersToIdx   = "tokentransfersto"
 )
@@ -227,6 +227,7 @@ const (
 	DiagnosticsDB   = "diagnostics"
 	PolygonBridgeDB = "polygon-bridge"
 	CaplinDB        = "caplin"
+	ValidatorDB     = "validator"
 	TemporaryDB     = "temporary"
 )
 
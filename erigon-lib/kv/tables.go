@@ -199,6 +199,12 @@ const (
 	TblTracesToKeys   = "TracesToKeys"
 	TblTracesToIdx    = "TracesToIdx"
 
+	// ERC-20/721/1155 transfer events by sender and by recipient
+	TblTokenTransfersFromKeys = "TokenTransfersFromKeys"
+	TblTokenTransfersFromIdx  = "TokenTransfersFromIdx"
+	TblTokenTransfersToKeys   = "TokenTransfersToKeys"
+	TblTokenTransfersToIdx    = "TokenTransfersToIdx"
+
 	// Prune progress of execution: tableName -> [8bytes of invStep]latest pruned key
 	// Could use table constants `Tbl{Account,Storage,Code,Commitment}Keys` for domains
 	// corresponding history tables `Tbl{Account,Storage,Code,Commitment}HistoryKeys` for history
@@ -285,6 +291,18 @@ const (
 
 	StatesProcessingProgress = "StatesProcessingProgress"
 
+	// Light client
+	LightClientUpdates = "LightClientUpdates" // sync_committee_period => [version + ssz light client update]
+
+	// Validator client slashing protection (EIP-3076)
+	SlashingProtectionBlocks       = "SlashingProtectionBlocks"       // [pubkey + slot] => [signing root]
+	SlashingProtectionAttestations = "SlashingProtectionAttestations" // [pubkey + target epoch] => [source epoch + signing root]
+	SlashingProtectionSources      = "SlashingProtectionSources"      // [pubkey] => [highest attested source epoch]
+
+	// Validators managed through the keymanager API, the imported keystores are kept out of the database
+	ValidatorRemoteKeys = "ValidatorRemoteKeys" // [pubkey] => [remote signer url]
+	ValidatorSettings   = "ValidatorSettings"   // [pubkey] => [json: fee recipient, gas limit, graffiti]
+
 	//Diagnostics tables
 	DiagSystemInfo = "DiagSystemInfo"
 	DiagSyncStages = "DiagSyncStages"
@@ -295,6 +313,9 @@ var (
 	// ExperimentalGetProofsLayout is used to keep track whether we store indecies to facilitate eth_getProof
 	CommitmentLayoutFlagKey = []byte("CommitmentLayouFlag")
 
+	// TokenTransfersStartKey is the first block from which the token transfers indices are complete
+	TokenTransfersStartKey = []byte("TokenTransfersStart")
+
 	PruneTypeOlder = []byte("older")
 	PruneHistory   = []byte("pruneHistory")
 	PruneBlocks    = []byte("pruneBlocks")
@@ -401,6 +422,11 @@ var ChaindataTables = []string{
 	TblTracesToKeys,
 	TblTracesToIdx,
 
+	TblTokenTransfersFromKeys,
+	TblTokenTransfersFromIdx,
+	TblTokenTransfersToKeys,
+	TblTokenTransfersToIdx,
+
 	TblPruningProgress,
 
 	MaxTxNum,
@@ -450,6 +476,7 @@ var ChaindataTables = []string{
 	ActiveValidatorIndicies,
 	EffectiveBalancesDump,
 	BalancesDump,
+	LightClientUpdates,
 	AccountChangeSetDeprecated,
 	StorageChangeSetDeprecated,
 	HashedAccountsDeprecated,
@@ -483,6 +510,13 @@ var DownloaderTables = []string{
 	BittorrentCompletion,
 	BittorrentInfo,
 }
+var ValidatorTables = []string{
+	SlashingProtectionBlocks,
+	SlashingProtectionAttestations,
+	SlashingProtectionSources,
+	ValidatorRemoteKeys,
+	ValidatorSettings,
+}
 var ReconTables = []string{
 	PlainStateR,
 	PlainStateD,
@@ -584,6 +618,11 @@ var ChaindataTablesCfg = TableCfg{
 	TblTracesFromIdx:  {Flags: DupSort},
 	TblTracesToKeys:   {Flags: DupSort},
 	TblTracesToIdx:    {Flags: DupSort},
+
+	TblTokenTransfersFromKeys: {Flags: DupSort},
+	TblTokenTransfersFromIdx:  {Flags: DupSort},
+	TblTokenTransfersToKeys:   {Flags: DupSort},
+	TblTokenTransfersToIdx:    {Flags: DupSort},
 }
 
 var AuRaTablesCfg = TableCfg{
@@ -613,6 +652,7 @@ var DownloaderTablesCfg = TableCfg{}
 var DiagnosticsTablesCfg = TableCfg{}
 var HeimdallTablesCfg = TableCfg{}
 var PolygonBridgeTablesCfg = TableCfg{}
+var ValidatorTablesCfg = TableCfg{}
 var ReconTablesCfg = TableCfg{
 	PlainStateD:    {Flags: DupSort},
 	CodeD:          {Flags: DupSort},
@@ -622,6 +662,7 @@ var ReconTablesCfg = TableCfg{
 func TablesCfgByLabel(label Label) TableCfg {
 	switch label {
 	case ChainDB, TemporaryDB, CaplinDB: //TODO: move caplindb tables to own table config
+		chaindataTablesInUse.Store(true)
 		return ChaindataTablesCfg
 	case TxPoolDB:
 		return TxpoolTablesCfg
@@ -637,6 +678,8 @@ func TablesCfgByLabel(label Label) TableCfg {
 		return PolygonBridgeTablesCfg
 	case ConsensusDB:
 		return ConsensusTablesCfg
+	case ValidatorDB:
+		return ValidatorTablesCfg
 	default:
 		panic(fmt.Sprintf("unexpected label: %s", label))
 	}
@@ -725,6 +768,12 @@ func reinit() {
 			PolygonBridgeTablesCfg[name] = TableCfgItem{}
 		}
 	}
+	for _, name := range ValidatorTables {
+		_, ok := ValidatorTablesCfg[name]
+		if !ok {
+			ValidatorTablesCfg[name] = TableCfgItem{}
+		}
+	}
 }
 
 // Temporal
@@ -753,6 +802,9 @@ const (
 	LogAddrIdx    InvertedIdx = 7
 	TracesFromIdx InvertedIdx = 8
 	TracesToIdx   InvertedIdx = 9
+
+	TokenTransfersFromIdx InvertedIdx = 10
+	TokenTransfersToIdx   InvertedIdx = 11
 )
 
 func (idx InvertedIdx) String() string {
@@ -777,7 +829,14 @@ func (idx InvertedIdx) String() string {
 		return "tracesfrom"
 	case TracesToIdx:
 		return "tracesto"
+	case TokenTransfersFromIdx:
+		return "tokentransfersfrom"
+	case TokenTransfersToIdx:
+		return "tokentransfersto"
 	default:
+		if ii, ok := customInvertedIdx(idx); ok {
+			return ii.Name
+		}
 		return "unknown index"
 	}
 }
@@ -804,7 +863,16 @@ func String2InvertedIdx(in string) (InvertedIdx, error) {
 		return TracesFromIdx, nil
 	case "tracesto":
 		return TracesToIdx, nil
+	case "tokentransfersfrom":
+		return TokenTransfersFromIdx, nil
+	case "tokentransfersto":
+		return TokenTransfersToIdx, nil
 	default:
+		for _, ii := range customInvertedIdxs {
+			if ii.Name == in {
+				return ii.Idx, nil
+			}
+		}
 		return InvertedIdx(MaxUint16), fmt.Errorf("unknown inverted index name: %s", in)
 	}
 }
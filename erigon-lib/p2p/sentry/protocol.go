@@ -5,7 +5,7 @@ import (
 )
 
 func MinProtocol(m sentryproto.MessageId) sentryproto.Protocol {
-	for p := sentryproto.Protocol_ETH67; p <= sentryproto.Protocol_ETH68; p++ {
+	for p := sentryproto.Protocol_ETH67; p <= sentryproto.Protocol_ETH69; p++ {
 		if ids, ok := ProtoIds[p]; ok {
 			if _, ok := ids[m]; ok {
 				return p
@@ -49,4 +49,22 @@ var ProtoIds = map[sentryproto.Protocol]map[sentryproto.MessageId]struct{}{
 		sentryproto.MessageId_GET_POOLED_TRANSACTIONS_66:       struct{}{},
 		sentryproto.MessageId_POOLED_TRANSACTIONS_66:           struct{}{},
 	},
+	// eth/69 sentries keep serving eth/68 peers, so the eth/68 receipt messages are accepted too
+	sentryproto.Protocol_ETH69: {
+		sentryproto.MessageId_GET_BLOCK_HEADERS_66:             struct{}{},
+		sentryproto.MessageId_BLOCK_HEADERS_66:                 struct{}{},
+		sentryproto.MessageId_GET_BLOCK_BODIES_66:              struct{}{},
+		sentryproto.MessageId_BLOCK_BODIES_66:                  struct{}{},
+		sentryproto.MessageId_GET_RECEIPTS_66:                  struct{}{},
+		sentryproto.MessageId_RECEIPTS_66:                      struct{}{},
+		sentryproto.MessageId_GET_RECEIPTS_69:                  struct{}{},
+		sentryproto.MessageId_RECEIPTS_69:                      struct{}{},
+		sentryproto.MessageId_NEW_BLOCK_HASHES_66:              struct{}{},
+		sentryproto.MessageId_NEW_BLOCK_66:                     struct{}{},
+		sentryproto.MessageId_TRANSACTIONS_66:                  struct{}{},
+		sentryproto.MessageId_NEW_POOLED_TRANSACTION_HASHES_68: struct{}{},
+		sentryproto.MessageId_GET_POOLED_TRANSACTIONS_66:       struct{}{},
+		sentryproto.MessageId_POOLED_TRANSACTIONS_66:           struct{}{},
+		sentryproto.MessageId_BLOCK_RANGE_UPDATE_69:            struct{}{},
+	},
 }
@@ -73,6 +73,13 @@ type Cfg struct {
 	SamplingFactor uint64
 
 	Workers int
+
+	// Codec - how words are encoded. All the pattern options above apply only to CodecHuffman
+	Codec Codec
+	// ZstdLevel - zstd compression level (1..22) of CodecZstd, 0 means the zstd default
+	ZstdLevel int
+	// ZstdDictSize - max size of the dictionary CodecZstd trains on the words, 0 means defaultZstdDictSize
+	ZstdDictSize int
 }
 
 var DefaultCfg = Cfg{
@@ -138,14 +145,17 @@ func NewCompressor(ctx context.Context, logPrefix, outputFile, tmpDir string, cf
 	// Collector for dictionary superstrings (sorted by their score)
 	superstrings := make(chan []byte, workers*2)
 	wg := &sync.WaitGroup{}
+	var suffixCollectors []*etl.Collector
+	if cfg.Codec != CodecHuffman { // no pattern dictionary to build
+		workers = 0
+	}
 	wg.Add(workers)
-	suffixCollectors := make([]*etl.Collector, workers)
 	for i := 0; i < workers; i++ {
 		collector := etl.NewCollector(logPrefix+"_dict", tmpDir, etl.NewSortableBuffer(etl.BufferOptimalSize/4), logger) //nolint:gocritic
 		collector.SortAndFlushInBackground(true)
 		collector.LogLvl(lvl)
 
-		suffixCollectors[i] = collector
+		suffixCollectors = append(suffixCollectors, collector)
 		go extractPatternsInSuperstrings(ctx, superstrings, collector, cfg, wg, logger)
 	}
 
@@ -197,6 +207,9 @@ func (c *Compressor) AddWord(word []byte) error {
 	}
 
 	c.wordsCount++
+	if c.Codec != CodecHuffman {
+		return c.uncompressedFile.Append(word)
+	}
 	l := 2*len(word) + 2
 	if c.superstringLen+l > superstringLimit {
 		if c.superstringCount%c.SamplingFactor == 0 {
@@ -242,18 +255,26 @@ func (c *Compressor) Compress() error {
 	close(c.superstrings)
 	c.wg.Wait()
 
-	if c.lvl < log.LvlTrace {
-		c.logger.Log(c.lvl, fmt.Sprintf("[%s] BuildDict start", c.logPrefix), "workers", c.Workers)
-	}
-	db, err := DictionaryBuilderFromCollectors(c.ctx, c.Cfg, c.logPrefix, c.tmpDir, c.suffixCollectors, c.lvl, c.logger)
-	if err != nil {
-		return err
-	}
-	if c.trace {
-		_, fileName := filepath.Split(c.outputFile)
-		if err := PersistDictionary(filepath.Join(c.tmpDir, fileName)+".dictionary.txt", db); err != nil {
+	var db *DictionaryBuilder
+	switch c.Codec {
+	case CodecHuffman:
+		if c.lvl < log.LvlTrace {
+			c.logger.Log(c.lvl, fmt.Sprintf("[%s] BuildDict start", c.logPrefix), "workers", c.Workers)
+		}
+		var err error
+		db, err = DictionaryBuilderFromCollectors(c.ctx, c.Cfg, c.logPrefix, c.tmpDir, c.suffixCollectors, c.lvl, c.logger)
+		if err != nil {
 			return err
 		}
+		if c.trace {
+			_, fileName := filepath.Split(c.outputFile)
+			if err := PersistDictionary(filepath.Join(c.tmpDir, fileName)+".dictionary.txt", db); err != nil {
+				return err
+			}
+		}
+	case CodecZstd:
+	default:
+		return fmt.Errorf("unsupported codec %s", c.Codec)
 	}
 	defer os.Remove(c.tmpOutFilePath)
 
@@ -263,7 +284,12 @@ func (c *Compressor) Compress() error {
 	}
 	defer cf.Close()
 	t := time.Now()
-	if err := compressWithPatternCandidates(c.ctx, c.trace, c.Cfg, c.logPrefix, c.tmpOutFilePath, cf, c.uncompressedFile, db, c.lvl, c.logger); err != nil {
+	if c.Codec == CodecZstd {
+		err = compressWithZstd(c.ctx, c.Cfg, c.logPrefix, cf, c.uncompressedFile, c.lvl, c.logger)
+	} else {
+		err = compressWithPatternCandidates(c.ctx, c.trace, c.Cfg, c.logPrefix, c.tmpOutFilePath, cf, c.uncompressedFile, db, c.lvl, c.logger)
+	}
+	if err != nil {
 		return err
 	}
 	if err = c.fsync(cf); err != nil {
@@ -283,7 +309,7 @@ func (c *Compressor) Compress() error {
 
 	_, fName := filepath.Split(c.outputFile)
 	if c.lvl < log.LvlTrace {
-		c.logger.Log(c.lvl, fmt.Sprintf("[%s] Compress", c.logPrefix), "took", time.Since(t), "ratio", c.Ratio, "file", fName)
+		c.logger.Log(c.lvl, fmt.Sprintf("[%s] Compress", c.logPrefix), "took", time.Since(t), "ratio", c.Ratio, "codec", c.Codec, "file", fName)
 	}
 	return nil
 }
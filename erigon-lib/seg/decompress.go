@@ -30,6 +30,7 @@ import (
 	"unsafe"
 
 	"github.com/c2h5oh/datasize"
+	"github.com/klauspost/compress/zstd"
 
 	"github.com/erigontech/erigon-lib/common/assert"
 	"github.com/erigontech/erigon-lib/common/dbg"
@@ -135,6 +136,9 @@ type Decompressor struct {
 	serializedDictSize uint64
 	dictWords          int
 
+	codec       Codec
+	zstdDecoder *zstd.Decoder // CodecZstd only
+
 	filePath, fileName string
 
 	readAheadRefcnt atomic.Int32 // ref-counter: allow enable/disable read-ahead from goroutines. only when refcnt=0 - disable read-ahead once
@@ -221,6 +225,18 @@ func NewDecompressor(compressedFilePath string) (*Decompressor, error) {
 	d.data = d.mmapHandle1[:d.size]
 	defer d.MadvNormal().DisableReadAhead() //speedup opening on slow drives
 
+	switch d.codec = fileCodec(d.data); d.codec {
+	case CodecHuffman:
+	case CodecZstd:
+		if err = d.openZstd(); err != nil {
+			return nil, err
+		}
+		validationPassed = true
+		return d, nil
+	default:
+		return nil, &ErrCompressedFileCorrupted{FileName: fName, Reason: fmt.Sprintf("unsupported codec %s", d.codec)}
+	}
+
 	d.wordsCount = binary.BigEndian.Uint64(d.data[:8])
 	d.emptyWordsCount = binary.BigEndian.Uint64(d.data[8:16])
 
@@ -482,6 +498,11 @@ func (d *Decompressor) Close() {
 		log.Log(dbg.FileCloseLogLevel, "close", "err", err, "file", d.FileName(), "stack", dbg.Stack())
 	}
 
+	if d.zstdDecoder != nil {
+		d.zstdDecoder.Close()
+		d.zstdDecoder = nil
+	}
+
 	d.f = nil
 	d.data = nil
 	d.posDict = nil
@@ -554,6 +575,9 @@ type Getter struct {
 	dataP       uint64
 	dataBit     int // Value 0..7 - position of the bit
 	trace       bool
+
+	zstdDecoder *zstd.Decoder // set for CodecZstd files, the Huffman tables are nil then
+	zstdBuf     []byte
 }
 
 func (g *Getter) Trace(t bool)     { g.trace = t }
@@ -663,6 +687,7 @@ func (d *Decompressor) MakeGetter() *Getter {
 		data:        d.data[d.wordsStart:],
 		patternDict: d.dict,
 		fName:       d.FileName(),
+		zstdDecoder: d.zstdDecoder,
 	}
 }
 
@@ -679,6 +704,9 @@ func (g *Getter) HasNext() bool {
 // and appends it to the given buf, returning the result of appending
 // After extracting next word, it moves to the beginning of the next one
 func (g *Getter) Next(buf []byte) ([]byte, uint64) {
+	if g.zstdDecoder != nil {
+		return g.zstdNext(buf)
+	}
 	savePos := g.dataP
 	wordLen := g.nextPos(true)
 	wordLen-- // because when create huffman tree we do ++ , because 0 is terminator
@@ -745,6 +773,9 @@ func (g *Getter) Next(buf []byte) ([]byte, uint64) {
 }
 
 func (g *Getter) NextUncompressed() ([]byte, uint64) {
+	if g.zstdDecoder != nil {
+		return g.zstdNextUncompressed()
+	}
 	wordLen := g.nextPos(true)
 	wordLen-- // because when create huffman tree we do ++ , because 0 is terminator
 	if wordLen == 0 {
@@ -766,6 +797,9 @@ func (g *Getter) NextUncompressed() ([]byte, uint64) {
 
 // Skip moves offset to the next word and returns the new offset and the length of the word.
 func (g *Getter) Skip() (uint64, int) {
+	if g.zstdDecoder != nil {
+		return g.zstdSkip()
+	}
 	l := g.nextPos(true)
 	l-- // because when create huffman tree we do ++ , because 0 is terminator
 	if l == 0 {
@@ -803,6 +837,9 @@ func (g *Getter) Skip() (uint64, int) {
 }
 
 func (g *Getter) SkipUncompressed() (uint64, int) {
+	if g.zstdDecoder != nil {
+		return g.zstdSkip()
+	}
 	wordLen := g.nextPos(true)
 	wordLen-- // because when create huffman tree we do ++ , because 0 is terminator
 	if wordLen == 0 {
@@ -823,6 +860,9 @@ func (g *Getter) SkipUncompressed() (uint64, int) {
 
 // MatchPrefix only checks if the word at the current offset has a buf prefix. Does not move offset to the next word.
 func (g *Getter) MatchPrefix(prefix []byte) bool {
+	if g.zstdDecoder != nil {
+		return g.zstdMatchPrefix(prefix)
+	}
 	savePos := g.dataP
 	defer func() {
 		g.dataP, g.dataBit = savePos, 0
@@ -906,6 +946,9 @@ func (g *Getter) MatchPrefix(prefix []byte) bool {
 // MatchCmp lexicographically compares given buf with the word at the current offset in the file.
 // returns 0 if buf == word, -1 if buf < word, 1 if buf > word
 func (g *Getter) MatchCmp(buf []byte) int {
+	if g.zstdDecoder != nil {
+		return g.zstdMatchCmp(buf, true)
+	}
 	savePos := g.dataP
 	wordLen := g.nextPos(true)
 	wordLen-- // because when create huffman tree we do ++ , because 0 is terminator
@@ -966,6 +1009,9 @@ func (g *Getter) MatchCmp(buf []byte) int {
 }
 
 func (g *Getter) MatchPrefixUncompressed(prefix []byte) bool {
+	if g.zstdDecoder != nil {
+		return g.zstdMatchPrefix(prefix)
+	}
 	savePos := g.dataP
 	defer func() {
 		g.dataP, g.dataBit = savePos, 0
@@ -987,6 +1033,9 @@ func (g *Getter) MatchPrefixUncompressed(prefix []byte) bool {
 }
 
 func (g *Getter) MatchCmpUncompressed(buf []byte) int {
+	if g.zstdDecoder != nil {
+		return g.zstdMatchCmp(buf, false)
+	}
 	savePos := g.dataP
 	defer func() {
 		g.dataP, g.dataBit = savePos, 0
@@ -1012,6 +1061,9 @@ func (g *Getter) MatchCmpUncompressed(buf []byte) int {
 // It is important to allocate enough buf size. Could throw an error if word in file is larger then the buf size.
 // After extracting next word, it moves to the beginning of the next one
 func (g *Getter) FastNext(buf []byte) ([]byte, uint64) {
+	if g.zstdDecoder != nil {
+		return g.zstdNext(buf[:0])
+	}
 	savePos := g.dataP
 	wordLen := g.nextPos(true)
 	wordLen-- // because when create huffman tree we do ++ , because 0 is terminator
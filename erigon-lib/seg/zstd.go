@@ -0,0 +1,346 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package seg
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/c2h5oh/datasize"
+	"github.com/klauspost/compress/dict"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/erigontech/erigon-lib/etl"
+	"github.com/erigontech/erigon-lib/log/v3"
+)
+
+// Codec is the compression scheme of a .seg file. It's recorded in the file header, Decompressor and Getter handle all
+// of them transparently. It's not part of the file name: a file of another codec has the name of the canonical one but
+// not its hash, so such files are for local use and must not be seeded.
+type Codec uint8
+
+const (
+	// CodecHuffman - dictionary of patterns, patterns and their positions are Huffman coded. The original format.
+	CodecHuffman Codec = iota
+	// CodecZstd - every word is a separate zstd frame, compressed with a dictionary trained on a sample of the words.
+	// Words stay independently addressable, so offsets and indices work exactly like with CodecHuffman.
+	CodecZstd
+)
+
+func (c Codec) String() string {
+	switch c {
+	case CodecHuffman:
+		return "huffman"
+	case CodecZstd:
+		return "zstd"
+	default:
+		return fmt.Sprintf("codec(%d)", uint8(c))
+	}
+}
+
+func ParseCodec(s string) (Codec, error) {
+	switch s {
+	case CodecHuffman.String():
+		return CodecHuffman, nil
+	case CodecZstd.String():
+		return CodecZstd, nil
+	default:
+		return 0, fmt.Errorf("unknown seg codec %q, expected %s or %s", s, CodecHuffman, CodecZstd)
+	}
+}
+
+/*
+Files of codecs other than CodecHuffman start with 7 bytes of codecMarker followed by the codec. CodecHuffman files
+store the words count there, which never gets close to it, so they are recognized by the marker's absence.
+
+CodecZstd file layout:
+
+	codecMarker [7]byte, codec byte
+	wordsCount, emptyWordsCount, dictSize uint64 (big-endian)
+	dict [dictSize]byte - zstd dictionary, empty if there were too few words to train one
+	words:
+	  uvarint(len(word) << 1), word                          - stored as is
+	  uvarint(len(frame) << 1 | 1), uvarint(len(word)), frame - zstd frame of the word
+*/
+var codecMarker = [7]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+
+const (
+	zstdHeaderSize = 32
+
+	// default size of the trained dictionary, same as the zstd cli
+	defaultZstdDictSize = 110 * 1024
+	// the dictionary is trained on a sample ~100 times its size, as zstd recommends
+	zstdDictSamplesFactor = 100
+	// no dictionary is trained on less input, it would not pay off
+	zstdMinDictSamples = 64 * 1024
+)
+
+// fileCodec reads the codec from the header of data.
+func fileCodec(data []byte) Codec {
+	if !bytes.Equal(data[:len(codecMarker)], codecMarker[:]) {
+		return CodecHuffman
+	}
+	return Codec(data[len(codecMarker)])
+}
+
+func (d *Decompressor) Codec() Codec { return d.codec }
+
+func (d *Decompressor) openZstd() error {
+	d.wordsCount = binary.BigEndian.Uint64(d.data[8:16])
+	d.emptyWordsCount = binary.BigEndian.Uint64(d.data[16:24])
+	dictSize := binary.BigEndian.Uint64(d.data[24:zstdHeaderSize])
+	if zstdHeaderSize+dictSize > uint64(d.size) {
+		return &ErrCompressedFileCorrupted{
+			FileName: d.fileName,
+			Reason: fmt.Sprintf("invalid zstd dictSize=%s while file size is just %s",
+				datasize.ByteSize(dictSize).HR(), datasize.ByteSize(d.size).HR())}
+	}
+	d.serializedDictSize = dictSize
+	d.wordsStart = zstdHeaderSize + dictSize
+	if d.wordsCount == 0 && d.wordsStart < uint64(d.size) {
+		return &ErrCompressedFileCorrupted{
+			FileName: d.fileName, Reason: fmt.Sprintf("size %v but no words in it", datasize.ByteSize(d.size).HR())}
+	}
+
+	// default concurrency: at most 4 getters of the file decode at the same time, others wait
+	var opts []zstd.DOption
+	if dictSize > 0 {
+		// the decoder keeps the dictionary content, it must not point into the mapping
+		opts = append(opts, zstd.WithDecoderDicts(bytes.Clone(d.data[zstdHeaderSize:d.wordsStart])))
+	}
+	var err error
+	if d.zstdDecoder, err = zstd.NewReader(nil, opts...); err != nil {
+		return &ErrCompressedFileCorrupted{FileName: d.fileName, Reason: err.Error()}
+	}
+	return nil
+}
+
+func (g *Getter) zstdWord() (payload []byte, compressed bool, wordLen, next uint64) {
+	h, n := binary.Uvarint(g.data[g.dataP:])
+	if n <= 0 {
+		panic(fmt.Sprintf("invalid zstd word header at %d, likely .idx is invalid: %s", g.dataP, g.fName))
+	}
+	start := g.dataP + uint64(n)
+	compressed = h&1 == 1
+	wordLen = h >> 1
+	if compressed {
+		if wordLen, n = binary.Uvarint(g.data[start:]); n <= 0 {
+			panic(fmt.Sprintf("invalid zstd word length at %d: %s", g.dataP, g.fName))
+		}
+		start += uint64(n)
+	}
+	next = start + h>>1
+	return g.data[start:next], compressed, wordLen, next
+}
+
+// zstdAppend appends the word at the current offset to buf, it does not move the offset.
+func (g *Getter) zstdAppend(buf []byte) ([]byte, uint64) {
+	payload, compressed, wordLen, next := g.zstdWord()
+	if !compressed {
+		return append(buf, payload...), next
+	}
+	buf = slices.Grow(buf, int(wordLen))
+	buf, err := g.zstdDecoder.DecodeAll(payload, buf)
+	if err != nil {
+		panic(fmt.Sprintf("zstd word at %d: %s, %s", g.dataP, g.fName, err))
+	}
+	return buf, next
+}
+
+func (g *Getter) zstdNext(buf []byte) ([]byte, uint64) {
+	buf, g.dataP = g.zstdAppend(buf)
+	if buf == nil { // empty word is a valid record, nil is the marker of "something not found"
+		buf = []byte{}
+	}
+	return buf, g.dataP
+}
+
+func (g *Getter) zstdNextUncompressed() ([]byte, uint64) {
+	payload, compressed, _, next := g.zstdWord()
+	if compressed {
+		return g.zstdNext(nil)
+	}
+	g.dataP = next
+	return payload, next
+}
+
+func (g *Getter) zstdSkip() (uint64, int) {
+	_, _, wordLen, next := g.zstdWord()
+	g.dataP = next
+	return next, int(wordLen)
+}
+
+func (g *Getter) zstdMatchPrefix(prefix []byte) bool {
+	payload, compressed, wordLen, _ := g.zstdWord()
+	if !compressed {
+		return bytes.HasPrefix(payload, prefix)
+	}
+	if int(wordLen) < len(prefix) {
+		return false
+	}
+	g.zstdBuf, _ = g.zstdAppend(g.zstdBuf[:0])
+	return bytes.HasPrefix(g.zstdBuf, prefix)
+}
+
+// zstdMatchCmp compares buf with the word at the current offset, moving to the next word on equality if advance is set.
+func (g *Getter) zstdMatchCmp(buf []byte, advance bool) int {
+	payload, compressed, _, next := g.zstdWord()
+	if compressed {
+		g.zstdBuf, _ = g.zstdAppend(g.zstdBuf[:0])
+		payload = g.zstdBuf
+	}
+	cmp := bytes.Compare(buf, payload)
+	if cmp == 0 && advance {
+		g.dataP = next
+	}
+	return cmp
+}
+
+func zstdEncoderLevel(level int) zstd.EncoderLevel {
+	if level == 0 {
+		return zstd.SpeedDefault
+	}
+	return zstd.EncoderLevelFromZstd(level)
+}
+
+// trainZstdDict trains a dictionary on an evenly spread sample of the words to be compressed. It returns nil if
+// there are too few of them.
+func trainZstdDict(cfg Cfg, logPrefix string, uncompressedFile *RawWordsFile, lvl log.Lvl, logger log.Logger) ([]byte, error) {
+	dictSize := cfg.ZstdDictSize
+	if dictSize == 0 {
+		dictSize = defaultZstdDictSize
+	}
+	st, err := uncompressedFile.f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	samplesLimit := dictSize * zstdDictSamplesFactor
+	step := max(1, uint64(st.Size())/uint64(samplesLimit))
+
+	var samples [][]byte
+	var samplesSize, i uint64
+	if err := uncompressedFile.ForEach(func(v []byte, compressed bool) error {
+		i++
+		if compressed && len(v) > 0 && i%step == 0 && samplesSize < uint64(samplesLimit) {
+			samples = append(samples, bytes.Clone(v))
+			samplesSize += uint64(len(v))
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if samplesSize < zstdMinDictSamples {
+		return nil, nil
+	}
+
+	zstdDict, err := dict.BuildZstdDict(samples, dict.Options{
+		MaxDictSize: dictSize,
+		HashBytes:   6,
+		ZstdLevel:   zstdEncoderLevel(cfg.ZstdLevel),
+	})
+	if err != nil {
+		// a file without dictionary is still valid, just bigger
+		logger.Warn(fmt.Sprintf("[%s] zstd dictionary training failed, compressing without it", logPrefix), "err", err)
+		return nil, nil
+	}
+	if lvl < log.LvlTrace {
+		logger.Log(lvl, fmt.Sprintf("[%s] zstd dictionary trained", logPrefix), "size", datasize.ByteSize(len(zstdDict)).HR(), "samples", len(samples))
+	}
+	return zstdDict, nil
+}
+
+func compressWithZstd(ctx context.Context, cfg Cfg, logPrefix string, cf *os.File, uncompressedFile *RawWordsFile, lvl log.Lvl, logger log.Logger) error {
+	logEvery := time.NewTicker(60 * time.Second)
+	defer logEvery.Stop()
+
+	zstdDict, err := trainZstdDict(cfg, logPrefix, uncompressedFile, lvl, logger)
+	if err != nil {
+		return err
+	}
+	opts := []zstd.EOption{zstd.WithEncoderLevel(zstdEncoderLevel(cfg.ZstdLevel)), zstd.WithEncoderCRC(false), zstd.WithEncoderConcurrency(1)}
+	if zstdDict != nil {
+		opts = append(opts, zstd.WithEncoderDict(zstdDict))
+	}
+	enc, err := zstd.NewWriter(nil, opts...)
+	if err != nil {
+		return err
+	}
+	defer enc.Close()
+
+	w := bufio.NewWriterSize(cf, 8*etl.BufIOSize)
+	var header [zstdHeaderSize]byte
+	copy(header[:], codecMarker[:])
+	header[len(codecMarker)] = byte(CodecZstd)
+	binary.BigEndian.PutUint64(header[8:16], uncompressedFile.count)
+	binary.BigEndian.PutUint64(header[24:32], uint64(len(zstdDict)))
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	if _, err := w.Write(zstdDict); err != nil {
+		return err
+	}
+
+	var emptyWordsCount, inCount uint64
+	var numBuf [2 * binary.MaxVarintLen64]byte
+	var frame []byte
+	if err := uncompressedFile.ForEach(func(v []byte, compressed bool) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-logEvery.C:
+			logger.Info(fmt.Sprintf("[%s] Compressing", logPrefix), "progress", fmt.Sprintf("%.2f%%", 100*float64(inCount)/float64(uncompressedFile.count)))
+		default:
+		}
+		inCount++
+		if len(v) == 0 {
+			emptyWordsCount++
+		}
+
+		if compressed && len(v) > 0 {
+			frame = enc.EncodeAll(v, frame[:0])
+			n := binary.PutUvarint(numBuf[:], uint64(len(frame))<<1|1)
+			n += binary.PutUvarint(numBuf[n:], uint64(len(v)))
+			if n+len(frame) < len(v) { // otherwise zstd doesn't help, the word is kept as is
+				if _, err := w.Write(numBuf[:n]); err != nil {
+					return err
+				}
+				_, err := w.Write(frame)
+				return err
+			}
+		}
+		n := binary.PutUvarint(numBuf[:], uint64(len(v))<<1)
+		if _, err := w.Write(numBuf[:n]); err != nil {
+			return err
+		}
+		_, err := w.Write(v)
+		return err
+	}); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	binary.BigEndian.PutUint64(header[16:24], emptyWordsCount)
+	_, err = cf.WriteAt(header[16:24], 16)
+	return err
+}
@@ -0,0 +1,145 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package seg
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/log/v3"
+)
+
+// zstdTestWords - every 3rd word is added uncompressed and every 7th is empty
+func zstdTestWords(n int) [][]byte {
+	words := make([][]byte, n)
+	for i := range words {
+		if i%7 == 0 {
+			words[i] = []byte{}
+			continue
+		}
+		words[i] = []byte(fmt.Sprintf("%08d %s %s", i, loremStrings[i%len(loremStrings)], strings.Repeat(loremStrings[(i*3)%len(loremStrings)], 1+i%4)))
+	}
+	return words
+}
+
+func prepareZstd(t *testing.T, words [][]byte) *Decompressor {
+	t.Helper()
+	tmpDir := t.TempDir()
+	file := filepath.Join(tmpDir, "compressed")
+	cfg := DefaultCfg
+	cfg.Codec = CodecZstd
+	c, err := NewCompressor(context.Background(), t.Name(), file, tmpDir, cfg, log.LvlDebug, log.New())
+	require.NoError(t, err)
+	defer c.Close()
+	c.DisableFsync()
+	for i, w := range words {
+		if i%3 == 0 {
+			err = c.AddUncompressedWord(w)
+		} else {
+			err = c.AddWord(w)
+		}
+		require.NoError(t, err)
+	}
+	require.NoError(t, c.Compress())
+
+	d, err := NewDecompressor(file)
+	require.NoError(t, err)
+	t.Cleanup(d.Close)
+	return d
+}
+
+func TestZstdCodec(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		words    int
+		withDict bool
+	}{
+		{name: "without dict", words: 100},
+		{name: "with dict", words: 20_000, withDict: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			words := zstdTestWords(tc.words)
+			d := prepareZstd(t, words)
+			require.Equal(t, CodecZstd, d.Codec())
+			require.Equal(t, len(words), d.Count())
+			require.Equal(t, (len(words)+6)/7, d.EmptyWordsCount())
+			require.Equal(t, tc.withDict, d.SerializedDictSize() > 0)
+
+			g := d.MakeGetter()
+			offsets := make([]uint64, 0, len(words))
+			var buf []byte
+			var offset uint64
+			for i, w := range words {
+				offsets = append(offsets, offset)
+				require.True(t, g.HasNext())
+				buf, offset = g.Next(buf[:0])
+				require.Equal(t, w, buf, i)
+			}
+			require.False(t, g.HasNext())
+
+			g.Reset(0)
+			for i, w := range words {
+				if i%3 == 0 {
+					v, _ := g.NextUncompressed()
+					require.Equal(t, w, v, i)
+					continue
+				}
+				_, l := g.Skip()
+				require.Equal(t, len(w), l, i)
+			}
+			require.False(t, g.HasNext())
+
+			for i := len(words) - 1; i >= 0; i -= 5 {
+				w := words[i]
+				g.Reset(offsets[i])
+				require.True(t, g.MatchPrefix(w))
+				if len(w) > 0 {
+					require.True(t, g.MatchPrefix(w[:len(w)/2]))
+					require.False(t, g.MatchPrefix(append(bytes.Clone(w), 'x')))
+					require.Equal(t, -1, g.MatchCmp(w[:len(w)-1]))
+				}
+				require.Equal(t, offsets[i], g.dataP, "a failed match must not move the getter")
+				require.Equal(t, 0, g.MatchCmp(w))
+				if i+1 < len(offsets) {
+					require.Equal(t, offsets[i+1], g.dataP)
+				}
+			}
+		})
+	}
+}
+
+func TestCodecOfHuffmanFile(t *testing.T) {
+	d := prepareLoremDict(t)
+	defer d.Close()
+	require.Equal(t, CodecHuffman, d.Codec())
+}
+
+func TestParseCodec(t *testing.T) {
+	for _, codec := range []Codec{CodecHuffman, CodecZstd} {
+		parsed, err := ParseCodec(codec.String())
+		require.NoError(t, err)
+		require.Equal(t, codec, parsed)
+	}
+	_, err := ParseCodec("lz4")
+	require.Error(t, err)
+}
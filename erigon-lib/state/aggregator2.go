@@ -10,6 +10,7 @@ import (
 	"github.com/erigontech/erigon-lib/kv"
 	"github.com/erigontech/erigon-lib/log/v3"
 	"github.com/erigontech/erigon-lib/seg"
+	"github.com/erigontech/erigon-lib/version"
 )
 
 // this is supposed to register domains/iis
@@ -57,6 +58,17 @@ func NewAggregator2(ctx context.Context, dirs datadir.Dirs, aggregationStep uint
 	if err := a.registerII(kv.TracesToIdx, salt, dirs, logger); err != nil {
 		return nil, err
 	}
+	if err := a.registerII(kv.TokenTransfersFromIdx, salt, dirs, logger); err != nil {
+		return nil, err
+	}
+	if err := a.registerII(kv.TokenTransfersToIdx, salt, dirs, logger); err != nil {
+		return nil, err
+	}
+	for _, ii := range kv.CustomInvertedIdxs() {
+		if err := a.registerII(ii.Idx, salt, dirs, logger); err != nil {
+			return nil, err
+		}
+	}
 	a.KeepRecentTxnsOfHistoriesWithDisabledSnapshots(100_000) // ~1k blocks of history
 
 	a.dirtyFilesLock.Lock()
@@ -87,6 +99,9 @@ type SchemaGen struct {
 	LogTopicIdx      iiCfg
 	TracesFromIdx    iiCfg
 	TracesToIdx      iiCfg
+
+	TokenTransfersFromIdx iiCfg
+	TokenTransfersToIdx   iiCfg
 }
 
 type Versioned interface {
@@ -101,13 +116,17 @@ func (s *SchemaGen) GetVersioned(name string) (Versioned, error) {
 			return nil, err
 		}
 		return s.GetDomainCfg(domain), nil
-	case kv.LogTopicIdx.String(), kv.LogAddrIdx.String(), kv.TracesFromIdx.String(), kv.TracesToIdx.String():
+	case kv.LogTopicIdx.String(), kv.LogAddrIdx.String(), kv.TracesFromIdx.String(), kv.TracesToIdx.String(),
+		kv.TokenTransfersFromIdx.String(), kv.TokenTransfersToIdx.String():
 		ii, err := kv.String2InvertedIdx(name)
 		if err != nil {
 			return nil, err
 		}
 		return s.GetIICfg(ii), nil
 	default:
+		if ii, err := kv.String2InvertedIdx(name); err == nil && ii >= kv.FirstCustomInvertedIdx {
+			return s.GetIICfg(ii), nil
+		}
 		return nil, fmt.Errorf("unknown schema version '%s'", name)
 	}
 }
@@ -145,13 +164,37 @@ func (s *SchemaGen) GetIICfg(name kv.InvertedIdx) iiCfg {
 		v = s.TracesFromIdx
 	case kv.TracesToIdx:
 		v = s.TracesToIdx
+	case kv.TokenTransfersFromIdx:
+		v = s.TokenTransfersFromIdx
+	case kv.TokenTransfersToIdx:
+		v = s.TokenTransfersToIdx
 	default:
-		v = iiCfg{}
+		v = customIICfg(name)
 	}
 	v.salt = new(atomic.Pointer[uint32])
 	return v
 }
 
+// customIICfg is the schema of an inverted index declared with kv.RegisterCustomInvertedIdx, it's laid out like the
+// log and trace indices.
+func customIICfg(idx kv.InvertedIdx) iiCfg {
+	for _, ii := range kv.CustomInvertedIdxs() {
+		if ii.Idx != idx {
+			continue
+		}
+		cfg := iiCfg{
+			filenameBase: ii.Name, keysTable: ii.KeysTable, valuesTable: ii.ValuesTable,
+
+			Compression: seg.CompressNone,
+			name:        ii.Idx,
+		}
+		cfg.version.DataEF = version.V2_0_standart
+		cfg.version.AccessorEFI = version.V1_1_standart
+		return cfg
+	}
+	return iiCfg{}
+}
+
 var ExperimentalConcurrentCommitment = false // set true to use concurrent commitment by default
 
 var Schema = SchemaGen{
@@ -307,6 +350,18 @@ var Schema = SchemaGen{
 		Compression: seg.CompressNone,
 		name:        kv.TracesToIdx,
 	},
+	TokenTransfersFromIdx: iiCfg{
+		filenameBase: kv.FileTokenTransfersFromIdx, keysTable: kv.TblTokenTransfersFromKeys, valuesTable: kv.TblTokenTransfersFromIdx,
+
+		Compression: seg.CompressNone,
+		name:        kv.TokenTransfersFromIdx,
+	},
+	TokenTransfersToIdx: iiCfg{
+		filenameBase: kv.FileTokenTransfersToIdx, keysTable: kv.TblTokenTransfersToKeys, valuesTable: kv.TblTokenTransfersToIdx,
+
+		Compression: seg.CompressNone,
+		name:        kv.TokenTransfersToIdx,
+	},
 }
 
 func EnableHistoricalCommitment() {
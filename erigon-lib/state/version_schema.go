@@ -65,6 +65,12 @@ func InitSchemas() {
 
 	Schema.TracesToIdx.version.DataEF = version.V2_0_standart
 	Schema.TracesToIdx.version.AccessorEFI = version.V1_1_standart
+
+	Schema.TokenTransfersFromIdx.version.DataEF = version.V2_0_standart
+	Schema.TokenTransfersFromIdx.version.AccessorEFI = version.V1_1_standart
+
+	Schema.TokenTransfersToIdx.version.DataEF = version.V2_0_standart
+	Schema.TokenTransfersToIdx.version.AccessorEFI = version.V1_1_standart
 }
 
 type DomainVersionTypes struct {
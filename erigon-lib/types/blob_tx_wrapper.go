@@ -38,6 +38,13 @@ const (
 	LEN_48 = 48 // KZGCommitment & KZGProof sizes
 )
 
+const (
+	// BlobWrapperVersion0 is the EIP-4844 network form of blob txns: one KZG proof per blob
+	BlobWrapperVersion0 byte = 0
+	// BlobWrapperVersion1 is the EIP-7594 network form of blob txns: libkzg.CellsPerBlob cell proofs per blob
+	BlobWrapperVersion1 byte = 1
+)
+
 type KZGCommitment [LEN_48]byte // Compressed BLS12-381 G1 element
 type KZGProof [LEN_48]byte
 type Blob [params.BlobSize]byte
@@ -47,10 +54,11 @@ type KZGProofs []KZGProof
 type Blobs []Blob
 
 type BlobTxWrapper struct {
-	Tx          BlobTx
-	Commitments BlobKzgs
-	Blobs       Blobs
-	Proofs      KZGProofs
+	Tx             BlobTx
+	WrapperVersion byte // BlobWrapperVersion1 is encoded explicitly, BlobWrapperVersion0 is implied by its absence
+	Commitments    BlobKzgs
+	Blobs          Blobs
+	Proofs         KZGProofs
 }
 
 /* Blob methods */
@@ -279,11 +287,25 @@ func (txw *BlobTxWrapper) ValidateBlobTransactionWrapper() error {
 	l2 := len(txw.Commitments)
 	l3 := len(txw.Blobs)
 	l4 := len(txw.Proofs)
-	if l1 != l2 || l1 != l3 || l1 != l4 {
-		return fmt.Errorf("lengths don't match %v %v %v %v", l1, l2, l3, l4)
+	var err error
+	switch txw.WrapperVersion {
+	case BlobWrapperVersion0:
+		if l1 != l2 || l1 != l3 || l1 != l4 {
+			return fmt.Errorf("lengths don't match %v %v %v %v", l1, l2, l3, l4)
+		}
+		err = libkzg.Ctx().VerifyBlobKZGProofBatch(toBlobs(txw.Blobs), toComms(txw.Commitments), toProofs(txw.Proofs))
+	case BlobWrapperVersion1:
+		if l1 != l2 || l1 != l3 || l1*libkzg.CellsPerBlob != l4 {
+			return fmt.Errorf("lengths don't match %v %v %v %v", l1, l2, l3, l4)
+		}
+		blobs := make([][]byte, len(txw.Blobs))
+		for i := range txw.Blobs {
+			blobs[i] = txw.Blobs[i][:]
+		}
+		err = libkzg.VerifyCellProofBatch(blobs, toComms(txw.Commitments), toProofs(txw.Proofs))
+	default:
+		return fmt.Errorf("unsupported blob wrapper version %d", txw.WrapperVersion)
 	}
-	kzgCtx := libkzg.Ctx()
-	err := kzgCtx.VerifyBlobKZGProofBatch(toBlobs(txw.Blobs), toComms(txw.Commitments), toProofs(txw.Proofs))
 	if err != nil {
 		return fmt.Errorf("error during proof verification: %w", err)
 	}
@@ -357,6 +379,22 @@ func (txw *BlobTxWrapper) DecodeRLP(s *rlp.Stream) error {
 		return err
 	}
 
+	// EIP-7594: the version is absent in the original EIP-4844 form
+	kind, _, err := s.Kind()
+	if err != nil {
+		return err
+	}
+	if kind != rlp.List {
+		version, err := s.Uint()
+		if err != nil {
+			return fmt.Errorf("read WrapperVersion: %w", err)
+		}
+		if version != uint64(BlobWrapperVersion1) {
+			return fmt.Errorf("unsupported blob wrapper version %d", version)
+		}
+		txw.WrapperVersion = byte(version)
+	}
+
 	if err := txw.Blobs.DecodeRLP(s); err != nil {
 		return err
 	}
@@ -380,6 +418,9 @@ func (txw *BlobTxWrapper) EncodingSize() int {
 func (txw *BlobTxWrapper) payloadSize() (payloadSize int) {
 	l, _, _, _, _ := txw.Tx.payloadSize()
 	payloadSize += l + rlp.ListPrefixLen(l)
+	if txw.WrapperVersion != BlobWrapperVersion0 {
+		payloadSize += rlp.U64Len(uint64(txw.WrapperVersion))
+	}
 	l = txw.Blobs.payloadSize()
 	payloadSize += l + rlp.ListPrefixLen(l)
 	l = txw.Commitments.payloadSize()
@@ -408,6 +449,11 @@ func (txw *BlobTxWrapper) MarshalBinaryWrapped(w io.Writer) error {
 	if _, err := w.Write(bw.Bytes()[1:]); err != nil {
 		return err
 	}
+	if txw.WrapperVersion != BlobWrapperVersion0 {
+		if err := rlp.EncodeInt(uint64(txw.WrapperVersion), w, b[:]); err != nil {
+			return err
+		}
+	}
 
 	if err := txw.Blobs.encodePayload(w, b[:], txw.Blobs.payloadSize()); err != nil {
 		return err
@@ -418,6 +418,54 @@ func (r *ReceiptForStorage) DecodeRLP(s *rlp.Stream) error {
 	return nil
 }
 
+// receipt69RLP is the eth/69 network encoding of a receipt.
+type receipt69RLP struct {
+	Type              uint8
+	PostStateOrStatus []byte
+	CumulativeGasUsed uint64
+	Logs              []*Log
+}
+
+// ReceiptForNetwork69 is a wrapper around a Receipt with the eth/69 RLP serialization:
+// the transaction type is a plain list element instead of a typed envelope, and the
+// Bloom field is omitted and re-computed on decode.
+type ReceiptForNetwork69 Receipt
+
+// EncodeRLP implements rlp.Encoder.
+func (r *ReceiptForNetwork69) EncodeRLP(w io.Writer) error {
+	return rlp.Encode(w, &receipt69RLP{
+		Type:              r.Type,
+		PostStateOrStatus: (*Receipt)(r).statusEncoding(),
+		CumulativeGasUsed: r.CumulativeGasUsed,
+		Logs:              r.Logs,
+	})
+}
+
+// DecodeRLP implements rlp.Decoder.
+func (r *ReceiptForNetwork69) DecodeRLP(s *rlp.Stream) error {
+	var dec receipt69RLP
+	if err := s.Decode(&dec); err != nil {
+		return err
+	}
+	if err := (*Receipt)(r).setStatus(dec.PostStateOrStatus); err != nil {
+		return err
+	}
+	r.Type = dec.Type
+	r.CumulativeGasUsed = dec.CumulativeGasUsed
+	r.Logs = dec.Logs
+	r.Bloom = LogsBloom(r.Logs)
+	return nil
+}
+
+// ReceiptsForNetwork69 converts receipts into their eth/69 network representation.
+func ReceiptsForNetwork69(receipts Receipts) []*ReceiptForNetwork69 {
+	res := make([]*ReceiptForNetwork69, len(receipts))
+	for i, r := range receipts {
+		res[i] = (*ReceiptForNetwork69)(r)
+	}
+	return res
+}
+
 // Receipts implements DerivableList for receipts.
 type Receipts []*Receipt
 
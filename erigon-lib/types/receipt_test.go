@@ -559,3 +559,44 @@ func TestReceiptUnmarshalBinary(t *testing.T) {
 		}
 	})
 }
+
+func TestReceiptForNetwork69EncodingDecoding(t *testing.T) {
+	t.Parallel()
+	receipt := &Receipt{
+		Type:              DynamicFeeTxType,
+		Status:            ReceiptStatusSuccessful,
+		CumulativeGasUsed: 42000,
+		Logs: []*Log{
+			{
+				Address: common.BytesToAddress([]byte{0x11}),
+				Topics:  []common.Hash{common.HexToHash("dead"), common.HexToHash("beef")},
+				Data:    []byte{0x01, 0x00, 0xff},
+			},
+		},
+	}
+	receipt.Bloom = CreateBloom(Receipts{receipt})
+
+	enc, err := rlp.EncodeToBytes((*ReceiptForNetwork69)(receipt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy, err := rlp.EncodeToBytes(receipt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(enc) >= len(legacy) {
+		t.Fatalf("eth/69 encoding should be smaller than with bloom: %d >= %d", len(enc), len(legacy))
+	}
+
+	var dec ReceiptForNetwork69
+	if err := rlp.DecodeBytes(enc, &dec); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, receipt.Type, dec.Type)
+	assert.Equal(t, receipt.Status, dec.Status)
+	assert.Equal(t, receipt.CumulativeGasUsed, dec.CumulativeGasUsed)
+	assert.Equal(t, receipt.Bloom, dec.Bloom)
+	assert.Equal(t, receipt.Logs[0].Address, dec.Logs[0].Address)
+	assert.Equal(t, receipt.Logs[0].Topics, dec.Logs[0].Topics)
+	assert.Equal(t, receipt.Logs[0].Data, dec.Logs[0].Data)
+}
@@ -54,14 +54,14 @@ const (
 	**/
 	ValidSetCodeTxn2 string = "0x04f902d483aa36a705830f5cc785014c75f17383060731948c2451ae6edf47e9e17bcfa14062fc783df37d2f80b901a4a6d0ad6100000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000e000000000000000000000000000000000000000000000000000000000000000600000000000000000000000007b79995e5f793a07bc00c21412e50ecae098e7f9000000000000000000000000000000000000000000000000002386f26fc100000000000000000000000000000000000000000000000000000000000000000004d0e30db0000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000060000000000000000000000000009f61deb7909675f1330257499ac0c2428e2e1b00000000000000000000000000000000000000000000000000470de4df8200000000000000000000000000000000000000000000000000000000000000000000c0f8bef85d83aa36a7946a06b2b7f83514f42764d412e54afaf4bb0265c80680a0079cfbeb0f7c2fc5beb3cc3e27cd5f1f8634bad0aed4452821f4374dd535476da00ba4e0362a8ad80242fbc3045c7ed0da06019c33ba74c9e0d0349ab18112ba08f85d83aa36a7947b79995e5f793a07bc00c21412e50ecae098e7f90680a073672ac9dadcd09ad9ac4d1f62ed1c3b2953dd4e93c8e0a6e72360198fe39ea6a06f5fdf8c0a69393b1ebb5b6ce93d7448c1b6d209c7bcac8349a07cf194fbb82580a08bc900325e062da5fac432e019c9c3a8b6c08c01f8a4b86101fd68882e4c3e1ca040c00a3aab34a2a0c9e2744ecd759525e1a82b4dfcc0fc65758842cdd25b682a"
 
-	// Some arbitrary hardcoded example
+	// Some arbitrary hardcoded example, its blob versioned hashes are the ones of ValidBlob1Hex and ValidBlob2Hex
 	BodyRlpHex string = "f9012705078502540be4008506fc23ac008357b58494811a752c8cd697e3cb27" +
 		"279c330ed1ada745a8d7808204f7f872f85994de0b295669a9fd93d5f28d9ec85e40f4cb697b" +
 		"aef842a00000000000000000000000000000000000000000000000000000000000000003a000" +
 		"00000000000000000000000000000000000000000000000000000000000007d694bb9bc244d7" +
-		"98123fde783fcc1c72d3bb8c189413c07bf842a0c6bdd1de713471bd6cfa62dd8b5a5b42969e" +
-		"d09e26212d3377f3f8426d8ec210a08aaeccaf3873d07cef005aca28c39f8a9f8bdb1ec8d79f" +
-		"fc25afc0a4fa2ab73601a036b241b061a36a32ab7fe86c7aa9eb592dd59018cd0443adc09035" +
+		"98123fde783fcc1c72d3bb8c189413c07bf842a0015645ccf91bdb34dbc42e4248b914b776a8" +
+		"c3435287135371554daaac78ed6da001466f7b14f0722bd581cf49418cd43fa8f085ce16e09c" +
+		"d3cdf65b3dfbbcb8c001a036b241b061a36a32ab7fe86c7aa9eb592dd59018cd0443adc09035" +
 		"90c16b02b0a05edcc541b4741c5cc6dd347c5ed9577ef293a62787b4510465fadbfe39ee4094"
 
 	// Just an encoded valid blob txn
@@ -0,0 +1,52 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"github.com/erigontech/erigon-lib/common"
+)
+
+// Topics of the token transfer events of the ERC-20, ERC-721 and ERC-1155 standards.
+var (
+	// Transfer(address indexed from, address indexed to, uint256 value), ERC-721 indexes the value too: it is the token id.
+	TransferEventTopic = common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	// TransferSingle(address indexed operator, address indexed from, address indexed to, uint256 id, uint256 value)
+	TransferSingleEventTopic = common.HexToHash("0xc3d58168c5ae7397731d063d5bbf3d657854427343f4c083240f7aacaa2d0f62")
+	// TransferBatch(address indexed operator, address indexed from, address indexed to, uint256[] ids, uint256[] values)
+	TransferBatchEventTopic = common.HexToHash("0x4a39dc06d4c0dbc64b70af90fd698a233a518aa5d07e595d983b8c0526c8f7fb")
+)
+
+// TokenTransferParticipants returns the sender and the recipient of a token transfer event, ok is false if the log
+// is not one.
+func (l *Log) TokenTransferParticipants() (from, to common.Address, ok bool) {
+	if len(l.Topics) == 0 {
+		return from, to, false
+	}
+	switch l.Topics[0] {
+	case TransferEventTopic:
+		if len(l.Topics) != 3 && len(l.Topics) != 4 {
+			return from, to, false
+		}
+		return common.BytesToAddress(l.Topics[1][:]), common.BytesToAddress(l.Topics[2][:]), true
+	case TransferSingleEventTopic, TransferBatchEventTopic:
+		if len(l.Topics) != 4 {
+			return from, to, false
+		}
+		return common.BytesToAddress(l.Topics[2][:]), common.BytesToAddress(l.Topics[3][:]), true
+	}
+	return from, to, false
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/crypto"
+)
+
+func TestTokenTransferTopics(t *testing.T) {
+	require.Equal(t, crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)")), TransferEventTopic)
+	require.Equal(t, crypto.Keccak256Hash([]byte("TransferSingle(address,address,address,uint256,uint256)")), TransferSingleEventTopic)
+	require.Equal(t, crypto.Keccak256Hash([]byte("TransferBatch(address,address,address,uint256[],uint256[])")), TransferBatchEventTopic)
+}
+
+func TestTokenTransferParticipants(t *testing.T) {
+	operator := common.HexToAddress("0x01")
+	from := common.HexToAddress("0x02")
+	to := common.HexToAddress("0x03")
+	word := func(addr common.Address) common.Hash { return common.BytesToHash(addr[:]) }
+
+	tests := []struct {
+		name   string
+		topics []common.Hash
+		ok     bool
+	}{
+		{"erc20", []common.Hash{TransferEventTopic, word(from), word(to)}, true},
+		{"erc721", []common.Hash{TransferEventTopic, word(from), word(to), common.HexToHash("0x2a")}, true},
+		{"erc1155 single", []common.Hash{TransferSingleEventTopic, word(operator), word(from), word(to)}, true},
+		{"erc1155 batch", []common.Hash{TransferBatchEventTopic, word(operator), word(from), word(to)}, true},
+		{"unindexed transfer", []common.Hash{TransferEventTopic}, false},
+		{"erc1155 missing recipient", []common.Hash{TransferSingleEventTopic, word(operator), word(from)}, false},
+		{"other event", []common.Hash{common.HexToHash("0x1234"), word(from), word(to)}, false},
+		{"anonymous", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFrom, gotTo, ok := (&Log{Topics: tt.topics}).TokenTransferParticipants()
+			require.Equal(t, tt.ok, ok)
+			if tt.ok {
+				require.Equal(t, from, gotFrom)
+				require.Equal(t, to, gotTo)
+			}
+		})
+	}
+}
@@ -531,6 +531,9 @@ func New(ctx context.Context, stack *node.Node, config *ethconfig.Config, logger
 
 			cfg.ListenAddr = fmt.Sprintf("%s:%d", listenHost, listenPort)
 			server := sentry.NewGrpcServer(backend.sentryCtx, nil, readNodeInfo, &cfg, protocol, logger)
+			if cfg.SnapServe {
+				server.EnableSnap(backend.chainDB)
+			}
 			backend.sentryServers = append(backend.sentryServers, server)
 			sentries = append(sentries, direct.NewSentryClientDirect(protocol, server))
 		}
@@ -31,7 +31,11 @@ func E3EfFiles(ctx context.Context, db kv.TemporalRwDB, failFast bool, fromStep
 	logEvery := time.NewTicker(20 * time.Second)
 	defer logEvery.Stop()
 	g := &errgroup.Group{}
-	for _, idx := range []kv.InvertedIdx{kv.AccountsHistoryIdx, kv.StorageHistoryIdx, kv.CodeHistoryIdx, kv.CommitmentHistoryIdx, kv.ReceiptHistoryIdx, kv.LogTopicIdx, kv.LogAddrIdx, kv.TracesFromIdx, kv.TracesToIdx} {
+	indices := []kv.InvertedIdx{kv.AccountsHistoryIdx, kv.StorageHistoryIdx, kv.CodeHistoryIdx, kv.CommitmentHistoryIdx, kv.ReceiptHistoryIdx, kv.LogTopicIdx, kv.LogAddrIdx, kv.TracesFromIdx, kv.TracesToIdx, kv.TokenTransfersFromIdx, kv.TokenTransfersToIdx}
+	for _, ii := range kv.CustomInvertedIdxs() {
+		indices = append(indices, ii.Idx)
+	}
+	for _, idx := range indices {
 		idx := idx
 		g.Go(func() error {
 			tx, err := db.BeginTemporalRo(ctx)
@@ -0,0 +1,92 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package stagedsync
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/erigontech/erigon-lib/kv"
+	state2 "github.com/erigontech/erigon-lib/state"
+	"github.com/erigontech/erigon/core/state"
+)
+
+// CustomIndexExtractor derives the keys of a custom inverted index from an executed txn: every key passed to add
+// gets the txNum of txTask. It's called for every txn of the block, including the system ones (txTask.TxIndex == -1
+// and txTask.Final), from the goroutine applying the execution results, so it must not block nor retain txTask.
+type CustomIndexExtractor func(txTask *state.TxTask, add func(key []byte) error) error
+
+type customIndex struct {
+	idx     kv.InvertedIdx
+	name    string
+	extract CustomIndexExtractor
+}
+
+var customIndices []customIndex
+
+// RegisterCustomIndex declares an application-specific inverted index filled by extract during execution. It gets
+// the same steps, merges, snapshot files and pruning as the built-in log and trace indices, and is read with
+// kv.TemporalTx.IndexRange using the returned id. History executed before the registration is backfilled by adding
+// name to the custom trace stage produce list.
+//
+// It must be called before the node opens its db, usually from an init function of the embedding program, it fails
+// afterwards.
+func RegisterCustomIndex(name string, extract CustomIndexExtractor) (kv.InvertedIdx, error) {
+	if extract == nil {
+		return 0, errors.New("custom index extractor is nil")
+	}
+	ii, err := kv.RegisterCustomInvertedIdx(name)
+	if err != nil {
+		return 0, err
+	}
+	customIndices = append(customIndices, customIndex{idx: ii.Idx, name: ii.Name, extract: extract})
+	return ii.Idx, nil
+}
+
+func findCustomIndex(name string) (customIndex, bool) {
+	for _, c := range customIndices {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return customIndex{}, false
+}
+
+func applyCustomIndex(idx kv.InvertedIdx, txTask *state.TxTask, doms *state2.SharedDomains) error {
+	for _, c := range customIndices {
+		if c.idx == idx {
+			return c.apply(txTask, doms)
+		}
+	}
+	return fmt.Errorf("custom index %s has no extractor", idx)
+}
+
+func (c customIndex) apply(txTask *state.TxTask, doms *state2.SharedDomains) error {
+	if err := c.extract(txTask, func(key []byte) error { return doms.IndexAdd(c.idx, key) }); err != nil {
+		return fmt.Errorf("custom index %s: %w", c.name, err)
+	}
+	return nil
+}
+
+func applyCustomIndices(txTask *state.TxTask, doms *state2.SharedDomains) error {
+	for _, c := range customIndices {
+		if err := c.apply(txTask, doms); err != nil {
+			return err
+		}
+	}
+	return nil
+}
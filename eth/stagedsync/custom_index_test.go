@@ -0,0 +1,209 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package stagedsync_test
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/chain"
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/datadir"
+	"github.com/erigontech/erigon-lib/crypto"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/kv/memdb"
+	"github.com/erigontech/erigon-lib/kv/order"
+	"github.com/erigontech/erigon-lib/kv/rawdbv3"
+	"github.com/erigontech/erigon-lib/kv/stream"
+	"github.com/erigontech/erigon-lib/kv/temporal"
+	"github.com/erigontech/erigon-lib/log/v3"
+	libstate "github.com/erigontech/erigon-lib/state"
+	"github.com/erigontech/erigon-lib/types"
+	"github.com/erigontech/erigon-lib/types/accounts"
+	"github.com/erigontech/erigon/core"
+	"github.com/erigontech/erigon/core/state"
+	"github.com/erigontech/erigon/eth/stagedsync"
+	"github.com/erigontech/erigon/turbo/stages/mock"
+)
+
+// recipientsIdx indexes the recipient of every txn, it has to be registered before the dbs of the tests are opened
+var recipientsIdx kv.InvertedIdx
+
+func init() {
+	var err error
+	recipientsIdx, err = stagedsync.RegisterCustomIndex("testrecipients", func(txTask *state.TxTask, add func(key []byte) error) error {
+		if txTask.Tx == nil || txTask.Tx.GetTo() == nil {
+			return nil
+		}
+		return add(txTask.Tx.GetTo().Bytes())
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func indexedTxNums(t *testing.T, tx kv.TemporalTx, key common.Address) []uint64 {
+	t.Helper()
+	it, err := tx.IndexRange(recipientsIdx, key[:], -1, -1, order.Asc, -1)
+	require.NoError(t, err)
+	txNums, err := stream.ToArrayU64(it)
+	require.NoError(t, err)
+	return txNums
+}
+
+// TestCustomIndexExecution runs the extractor through exec3, the serial and the parallel executors share
+// applyCustomIndices but ExecV3 only runs the serial one for now.
+func TestCustomIndexExecution(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	alice, bob := common.HexToAddress("0xa11ce"), common.HexToAddress("0xb0b")
+	m := mock.MockWithGenesis(t, &types.Genesis{
+		Config: chain.TestChainConfig,
+		Alloc:  types.GenesisAlloc{sender: {Balance: big.NewInt(1_000_000_000_000_000)}},
+	}, key, false)
+
+	signer := types.LatestSignerForChainID(nil)
+	generate := func(blocks int, toAlice func(i int) bool) *core.ChainPack {
+		chain, err := core.GenerateChain(m.ChainConfig, m.Genesis, m.Engine, m.DB, blocks, func(i int, block *core.BlockGen) {
+			recipients := []common.Address{bob}
+			if toAlice(i) {
+				recipients = append(recipients, alice)
+			}
+			for _, recipient := range recipients {
+				txn, err := types.SignTx(types.NewTransaction(block.TxNonce(sender), recipient, uint256.NewInt(1), 21_000, uint256.NewInt(1), nil), *signer, key)
+				require.NoError(t, err)
+				block.AddTx(txn)
+			}
+		})
+		require.NoError(t, err)
+		return chain
+	}
+	// txNums of the txns of the canonical blocks sent to the recipient, bob gets the first txn of every block
+	expected := func(tx kv.TemporalTx, blocks int, txIndex int, toAlice func(i int) bool) []uint64 {
+		var txNums []uint64
+		for i := 0; i < blocks; i++ {
+			if txIndex == 1 && !toAlice(i) {
+				continue
+			}
+			minTxNum, err := rawdbv3.TxNums.Min(tx, uint64(i+1))
+			require.NoError(t, err)
+			txNums = append(txNums, minTxNum+1+uint64(txIndex))
+		}
+		return txNums
+	}
+
+	// a longer fork sharing the first 2 blocks unwinds the last 2 before executing its own, both are generated from the
+	// genesis state
+	everyBlock, firstTwo := func(int) bool { return true }, func(i int) bool { return i < 2 }
+	canonical, fork := generate(4, everyBlock), generate(5, firstTwo)
+
+	require.NoError(t, m.InsertChain(canonical))
+	tx, err := m.DB.BeginTemporalRo(m.Ctx)
+	require.NoError(t, err)
+	require.Equal(t, expected(tx, 4, 0, everyBlock), indexedTxNums(t, tx, bob))
+	require.Equal(t, expected(tx, 4, 1, everyBlock), indexedTxNums(t, tx, alice))
+	tx.Rollback()
+
+	require.NoError(t, m.InsertChain(fork))
+	tx, err = m.DB.BeginTemporalRo(m.Ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	require.Equal(t, expected(tx, 5, 0, firstTwo), indexedTxNums(t, tx, bob))
+	require.Equal(t, expected(tx, 5, 1, firstTwo), indexedTxNums(t, tx, alice))
+}
+
+func TestCustomIndexFiles(t *testing.T) {
+	ctx := context.Background()
+	logger := log.New()
+	dirs := datadir.New(t.TempDir())
+	const stepSize, txs = 2, 20
+
+	rawDB := memdb.NewTestDB(t, kv.ChainDB)
+	salt, err := libstate.GetStateIndicesSalt(dirs, true, logger)
+	require.NoError(t, err)
+	agg, err := libstate.NewAggregator2(ctx, dirs, stepSize, salt, rawDB, logger)
+	require.NoError(t, err)
+	t.Cleanup(agg.Close)
+	require.NoError(t, agg.OpenFolder())
+	agg.DisableFsync()
+	db, err := temporal.New(rawDB, agg)
+	require.NoError(t, err)
+	t.Cleanup(db.Close)
+
+	alice, bob := common.HexToAddress("0xa11ce"), common.HexToAddress("0xb0b")
+	var aliceTxNums, bobTxNums []uint64
+	tx, err := db.BeginTemporalRw(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	domains, err := libstate.NewSharedDomains(tx, logger)
+	require.NoError(t, err)
+	defer domains.Close()
+	for txNum := uint64(0); txNum < txs; txNum++ {
+		domains.SetTxNum(txNum)
+		recipient := bob
+		if txNum%3 == 0 {
+			recipient, aliceTxNums = alice, append(aliceTxNums, txNum)
+		} else {
+			bobTxNums = append(bobTxNums, txNum)
+		}
+		require.NoError(t, domains.IndexAdd(recipientsIdx, recipient[:]))
+		// the files are built only for the steps the accounts domain has data for
+		account := accounts.SerialiseV3(&accounts.Account{Nonce: txNum})
+		require.NoError(t, domains.DomainPut(kv.AccountsDomain, recipient[:], nil, account, nil, 0))
+	}
+	require.NoError(t, domains.Flush(ctx, tx))
+	domains.Close()
+	require.NoError(t, tx.Commit())
+
+	require.NoError(t, agg.BuildFiles(txs))
+	require.NoError(t, agg.MergeLoop(ctx))
+	rwTx, err := db.BeginTemporalRw(ctx)
+	require.NoError(t, err)
+	defer rwTx.Rollback()
+	ac := agg.BeginFilesRo()
+	for haveMore := true; haveMore; {
+		haveMore, err = ac.PruneSmallBatches(ctx, time.Minute, rwTx)
+		require.NoError(t, err)
+	}
+	ac.Close()
+	require.NoError(t, rwTx.Commit())
+
+	// the steps were built and merged into files, and pruned from the db but the last one which isn't in files yet
+	files, err := os.ReadDir(dirs.SnapIdx)
+	require.NoError(t, err)
+	var merged bool
+	for _, f := range files {
+		merged = merged || strings.HasSuffix(f.Name(), "-testrecipients.0-8.ef")
+	}
+	require.True(t, merged)
+	roTx, err := db.BeginTemporalRo(ctx)
+	require.NoError(t, err)
+	defer roTx.Rollback()
+	count, err := roTx.Count(kv.CustomInvertedIdxs()[0].ValuesTable)
+	require.NoError(t, err)
+	require.Equal(t, uint64(stepSize), count)
+
+	require.Equal(t, aliceTxNums, indexedTxNums(t, roTx, alice))
+	require.Equal(t, bobTxNums, indexedTxNums(t, roTx, bob))
+}
@@ -435,6 +435,16 @@ func ExecV3(ctx context.Context,
 		return nil
 	}
 
+	if !inMemExec && !isMining {
+		firstIndexedBlock := blockNum
+		if offsetFromBlockBeginning > 0 {
+			firstIndexedBlock++
+		}
+		if err := markTokenTransfersStart(executor.tx(), firstIndexedBlock); err != nil {
+			return err
+		}
+	}
+
 	if maxBlockNum > blockNum+16 {
 		log.Info(fmt.Sprintf("[%s] starting", execStage.LogPrefix()),
 			"from", blockNum, "to", maxBlockNum, "fromTxNum", executor.domains().TxNum(), "offsetFromBlockBeginning", offsetFromBlockBeginning, "initialCycle", initialCycle, "useExternalTx", useExternalTx)
@@ -829,6 +839,19 @@ Loop:
 	return nil
 }
 
+// markTokenTransfersStart records the first block from which the token transfers indices are complete. They are
+// filled by the execution, the blocks executed before the indices existed are only indexed by stage_custom_trace.
+func markTokenTransfersStart(tx kv.RwTx, firstExecutedBlock uint64) error {
+	start, ok, err := rawdb.ReadTokenTransfersStart(tx)
+	if err != nil {
+		return err
+	}
+	if ok && start <= firstExecutedBlock {
+		return nil
+	}
+	return rawdb.WriteTokenTransfersStart(tx, firstExecutedBlock)
+}
+
 // nolint
 func dumpPlainStateDebug(tx kv.TemporalRwTx, doms *state2.SharedDomains) {
 	if doms != nil {
@@ -448,6 +448,9 @@ func (pe *parallelExecutor) processResultQueue(ctx context.Context, inputTxNum u
 		if err := pe.rs.ApplyLogsAndTraces(txTask, pe.rs.Domains()); err != nil {
 			return outputTxNum, conflicts, triggers, processedBlockNum, false, fmt.Errorf("ParallelExecutionState.Apply: %w", err)
 		}
+		if err := applyCustomIndices(txTask, pe.rs.Domains()); err != nil {
+			return outputTxNum, conflicts, triggers, processedBlockNum, false, err
+		}
 		processedBlockNum = txTask.BlockNum
 		if !stopedAtBlockEnd {
 			stopedAtBlockEnd = txTask.Final
@@ -144,6 +144,11 @@ func (se *serialExecutor) execute(ctx context.Context, tasks []*state.TxTask, gp
 		if err := se.rs.ApplyState(ctx, txTask); err != nil {
 			return false, err
 		}
+		if !txTask.HistoryExecution {
+			if err := applyCustomIndices(txTask, se.rs.Domains()); err != nil {
+				return false, err
+			}
+		}
 
 		se.outputTxNum.Add(1)
 	}
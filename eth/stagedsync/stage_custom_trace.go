@@ -59,6 +59,11 @@ type Produce struct {
 	LogTopic      bool
 	TraceFrom     bool
 	TraceTo       bool
+
+	TokenTransferFrom bool
+	TokenTransferTo   bool
+
+	Custom []kv.InvertedIdx // see RegisterCustomIndex
 }
 
 func NewProduce(produceList []string) Produce {
@@ -78,8 +83,16 @@ func NewProduce(produceList []string) Produce {
 			produce.TraceFrom = true
 		case kv.TracesToIdx.String():
 			produce.TraceTo = true
+		case kv.TokenTransfersFromIdx.String():
+			produce.TokenTransferFrom = true
+		case kv.TokenTransfersToIdx.String():
+			produce.TokenTransferTo = true
 		default:
-			panic(fmt.Errorf("assert: unknown Produce %#v", p))
+			custom, ok := findCustomIndex(p)
+			if !ok {
+				panic(fmt.Errorf("assert: unknown Produce %#v", p))
+			}
+			produce.Custom = append(produce.Custom, custom.idx)
 		}
 	}
 	return produce
@@ -141,6 +154,15 @@ func SpawnCustomTrace(cfg CustomTraceCfg, ctx context.Context, logger log.Logger
 		if cfg.Produce.TraceTo {
 			txNum = min(txNum, ac.ProgressII(kv.TracesToIdx, tx))
 		}
+		if cfg.Produce.TokenTransferFrom {
+			txNum = min(txNum, ac.ProgressII(kv.TokenTransfersFromIdx, tx))
+		}
+		if cfg.Produce.TokenTransferTo {
+			txNum = min(txNum, ac.ProgressII(kv.TokenTransfersToIdx, tx))
+		}
+		for _, idx := range cfg.Produce.Custom {
+			txNum = min(txNum, ac.ProgressII(idx, tx))
+		}
 		fromTxNum := txNum
 		var ok bool
 		ok, startBlock, err = txNumsReader.FindBlockNum(tx, fromTxNum)
@@ -166,6 +188,7 @@ func SpawnCustomTrace(cfg CustomTraceCfg, ctx context.Context, logger log.Logger
 		producingDomain = kv.RCacheDomain
 	}
 
+	firstBlock := startBlock
 	batchSize := uint64(50_000)
 	for ; startBlock < endBlock; startBlock += batchSize {
 		to := min(endBlock+1, startBlock+batchSize)
@@ -173,6 +196,14 @@ func SpawnCustomTrace(cfg CustomTraceCfg, ctx context.Context, logger log.Logger
 			return err
 		}
 	}
+	if cfg.Produce.TokenTransferFrom && cfg.Produce.TokenTransferTo {
+		// the execution keeps the indices complete from endBlock on
+		if err := cfg.db.Update(ctx, func(tx kv.RwTx) error {
+			return markTokenTransfersStart(tx, firstBlock)
+		}); err != nil {
+			return err
+		}
+	}
 
 	logEvery := time.NewTicker(20 * time.Second)
 	defer logEvery.Stop()
@@ -461,6 +492,29 @@ func customTraceBatch(ctx context.Context, produce Produce, cfg *exec3.ExecArgs,
 					}
 				}
 			}
+			if produce.TokenTransferFrom || produce.TokenTransferTo {
+				for _, lg := range txTask.Logs {
+					from, to, ok := lg.TokenTransferParticipants()
+					if !ok {
+						continue
+					}
+					if produce.TokenTransferFrom {
+						if err := doms.IndexAdd(kv.TokenTransfersFromIdx, from[:]); err != nil {
+							return err
+						}
+					}
+					if produce.TokenTransferTo {
+						if err := doms.IndexAdd(kv.TokenTransfersToIdx, to[:]); err != nil {
+							return err
+						}
+					}
+				}
+			}
+			for _, idx := range produce.Custom {
+				if err := applyCustomIndex(idx, txTask, doms); err != nil {
+					return err
+				}
+			}
 
 			select {
 			case <-logEvery.C:
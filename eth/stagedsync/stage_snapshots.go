@@ -557,7 +557,7 @@ type snapshotUploader struct {
 	files           map[string]*uploadState
 	uploadFs        string
 	rclone          *downloader.RCloneClient
-	uploadSession   *downloader.RCloneSession
+	uploadSession   downloader.RemoteSession
 	uploadScheduled atomic.Bool
 	uploading       atomic.Bool
 	manifestMutex   sync.Mutex
@@ -953,6 +953,19 @@ func isLocalFs(ctx context.Context, rclient *downloader.RCloneClient, fs string)
 func (u *snapshotUploader) start(ctx context.Context, logger log.Logger) {
 	var err error
 
+	if strings.HasPrefix(u.uploadFs, "s3://") {
+		s3Cfg, err := downloader.ParseS3Url(u.uploadFs)
+
+		if err != nil {
+			logger.Warn("[uploader] Uploading disabled: invalid upload fs", "err", err, "fs", u.uploadFs)
+			return
+		}
+
+		u.uploadSession = downloader.NewS3Client(s3Cfg, logger).NewSession(u.cfg.dirs.Snap)
+		u.run(ctx, logger)
+		return
+	}
+
 	u.rclone, err = downloader.NewRCloneClient(logger)
 
 	if err != nil {
@@ -985,7 +998,12 @@ func (u *snapshotUploader) start(ctx context.Context, logger log.Logger) {
 		return
 	}
 
+	u.run(ctx, logger)
+}
+
+func (u *snapshotUploader) run(ctx context.Context, logger log.Logger) {
 	go func() {
+		var err error
 
 		remoteFiles, _ := u.downloadManifest(ctx)
 		refreshFromRemote := false
@@ -1246,7 +1264,7 @@ func (u *snapshotUploader) upload(ctx context.Context, logger log.Logger) {
 			err := func() error {
 				state.Lock()
 				defer state.Unlock()
-				if !state.remote && state.torrent != nil && len(state.uploads) == 0 && u.rclone != nil {
+				if !state.remote && state.torrent != nil && len(state.uploads) == 0 && u.uploadSession != nil {
 					state.uploads = []string{state.file, state.file + ".torrent"}
 					uploadList = append(uploadList, state)
 				}
@@ -22,6 +22,7 @@ package config
 import (
 	"encoding/json"
 
+	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/common/hexutil"
 	"github.com/erigontech/erigon/eth/tracers/logger"
 	"github.com/erigontech/erigon/rpc/ethapi"
@@ -40,3 +41,10 @@ type TraceConfig struct {
 	BorTraceEnabled *bool
 	TxIndex         *hexutil.Uint
 }
+
+// StdTraceConfig holds extra parameters to standard-json trace functions.
+type StdTraceConfig struct {
+	logger.LogConfig
+	Reexec *uint64
+	TxHash common.Hash // Only trace this transaction of the block, if set
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package tracetest
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon/core/vm"
+)
+
+// erc7562Trace is the result of an erc7562Tracer run.
+type erc7562Trace struct {
+	Type          string `json:"type"`
+	AccessedSlots struct {
+		Reads  map[string][]string `json:"reads"`
+		Writes map[string]uint64   `json:"writes"`
+	} `json:"accessedSlots"`
+	UsedOpcodes  map[vm.OpCode]uint64 `json:"usedOpcodes"`
+	ContractSize map[common.Address]struct {
+		ContractSize int       `json:"contractSize"`
+		Opcode       vm.OpCode `json:"opcode"`
+	} `json:"contractSize"`
+	Keccak []hexutil.Bytes `json:"keccak"`
+	Calls  []erc7562Trace  `json:"calls"`
+}
+
+func TestErc7562Tracer(t *testing.T) {
+	code := append([]byte{
+		byte(vm.PUSH1), 0x20, byte(vm.PUSH1), 0x0, byte(vm.KECCAK256), byte(vm.POP), // hash 32 zero bytes
+		byte(vm.PUSH1), 0x07, byte(vm.SLOAD), byte(vm.POP), // read slot 7
+		byte(vm.PUSH1), 0x01, byte(vm.PUSH1), 0x08, byte(vm.SSTORE), // write slot 8
+	}, callCode(0xff)...)
+
+	res, _ := traceContractCall(t, "erc7562Tracer", nil, code)
+	var trace erc7562Trace
+	require.NoError(t, json.Unmarshal(res, &trace))
+
+	require.Equal(t, "CALL", trace.Type)
+	require.Equal(t, uint64(1), trace.UsedOpcodes[vm.KECCAK256])
+	require.Equal(t, uint64(1), trace.UsedOpcodes[vm.SLOAD])
+	require.Equal(t, uint64(1), trace.UsedOpcodes[vm.SSTORE])
+	require.Equal(t, uint64(1), trace.UsedOpcodes[vm.CALL])
+	// PUSHx and POP are ignored by default, and GAS right before CALL is allowed
+	require.NotContains(t, trace.UsedOpcodes, vm.PUSH1)
+	require.NotContains(t, trace.UsedOpcodes, vm.POP)
+	require.NotContains(t, trace.UsedOpcodes, vm.GAS)
+
+	slot7 := common.HexToHash("0x07").Hex()
+	slot8 := common.HexToHash("0x08").Hex()
+	require.Equal(t, []string{common.Hash{}.Hex()}, trace.AccessedSlots.Reads[slot7])
+	require.Equal(t, uint64(1), trace.AccessedSlots.Writes[slot8])
+
+	callee := common.HexToAddress("0xff")
+	require.Contains(t, trace.ContractSize, callee)
+	require.Equal(t, 0, trace.ContractSize[callee].ContractSize)
+	require.Equal(t, vm.CALL, trace.ContractSize[callee].Opcode)
+
+	require.Equal(t, []hexutil.Bytes{make([]byte, 32)}, trace.Keccak)
+	require.Len(t, trace.Calls, 1)
+}
@@ -0,0 +1,190 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package tracetest
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon-lib/crypto"
+	"github.com/erigontech/erigon-lib/types"
+	"github.com/erigontech/erigon/core"
+	"github.com/erigontech/erigon/core/vm"
+	"github.com/erigontech/erigon/core/vm/evmtypes"
+	"github.com/erigontech/erigon/eth/tracers"
+	"github.com/erigontech/erigon/execution/consensus"
+	"github.com/erigontech/erigon/params"
+	"github.com/erigontech/erigon/tests"
+	"github.com/erigontech/erigon/turbo/stages/mock"
+)
+
+// flatCallTrace is the result of a flatCallTracer run.
+type flatCallTrace struct {
+	Action struct {
+		CallType string          `json:"callType"`
+		From     common.Address  `json:"from"`
+		To       common.Address  `json:"to"`
+		Value    *hexutil.Big    `json:"value"`
+		Gas      *hexutil.Uint64 `json:"gas"`
+	} `json:"action"`
+	BlockNumber         uint64       `json:"blockNumber"`
+	Error               string       `json:"error"`
+	Subtraces           int          `json:"subtraces"`
+	TraceAddress        []int        `json:"traceAddress"`
+	TransactionHash     *common.Hash `json:"transactionHash"`
+	TransactionPosition uint64       `json:"transactionPosition"`
+	Type                string       `json:"type"`
+}
+
+// traceContractCall deploys the given code at a fixed address, sends a
+// transaction to it and returns the result of the named tracer.
+func traceContractCall(t *testing.T, tracerName string, tracerConfig json.RawMessage, code []byte) (json.RawMessage, types.Transaction) {
+	t.Helper()
+	var to = common.HexToAddress("0x00000000000000000000000000000000deadbeef")
+	privkey, err := crypto.HexToECDSA("0000000000000000deadbeef00000000000000000000000000000000deadbeef")
+	require.NoError(t, err)
+	signer := types.LatestSigner(params.MainnetChainConfig)
+	tx, err := types.SignNewTx(privkey, *signer, &types.LegacyTx{
+		GasPrice: uint256.NewInt(0),
+		CommonTx: types.CommonTx{
+			GasLimit: 100000,
+			To:       &to,
+		},
+	})
+	require.NoError(t, err)
+	origin, _ := signer.Sender(tx)
+	txContext := evmtypes.TxContext{
+		Origin:   origin,
+		GasPrice: uint256.NewInt(1),
+	}
+	context := evmtypes.BlockContext{
+		CanTransfer: core.CanTransfer,
+		Transfer:    consensus.Transfer,
+		Coinbase:    common.Address{},
+		BlockNumber: 8000000,
+		Time:        5,
+		Difficulty:  big.NewInt(0x30000),
+		GasLimit:    uint64(6000000),
+	}
+	var alloc = types.GenesisAlloc{
+		to: types.GenesisAccount{
+			Nonce: 1,
+			Code:  code,
+		},
+		origin: types.GenesisAccount{
+			Nonce:   0,
+			Balance: big.NewInt(500000000000000),
+		},
+	}
+	rules := params.MainnetChainConfig.Rules(context.BlockNumber, context.Time)
+	m := mock.Mock(t)
+	dbTx, err := m.DB.BeginRw(m.Ctx)
+	require.NoError(t, err)
+	defer dbTx.Rollback()
+
+	statedb, err := tests.MakePreState(rules, dbTx, alloc, context.BlockNumber)
+	require.NoError(t, err)
+	tracer, err := tracers.New(tracerName, &tracers.Context{TxIndex: 3, TxHash: tx.Hash()}, tracerConfig)
+	require.NoError(t, err)
+	statedb.SetHooks(tracer.Hooks)
+	evm := vm.NewEVM(context, txContext, statedb, params.MainnetChainConfig, vm.Config{Tracer: tracer.Hooks})
+	msg, err := tx.AsMessage(*signer, nil, rules)
+	require.NoError(t, err)
+	tracer.OnTxStart(evm.GetVMContext(), tx, msg.From())
+	st := core.NewStateTransition(evm, msg, new(core.GasPool).AddGas(tx.GetGasLimit()).AddBlobGas(tx.GetBlobGas()))
+	vmRet, err := st.TransitionDb(true /* refunds */, false /* gasBailout */)
+	require.NoError(t, err)
+	tracer.OnTxEnd(&types.Receipt{GasUsed: vmRet.UsedGas}, err)
+	res, err := tracer.GetResult()
+	require.NoError(t, err)
+	return res, tx
+}
+
+// callCode returns code which calls the given address with zero value and
+// all of the available gas.
+func callCode(addr byte) []byte {
+	return []byte{
+		byte(vm.PUSH1), 0x0, byte(vm.DUP1), byte(vm.DUP1), byte(vm.DUP1), // in and outs zero
+		byte(vm.DUP1), byte(vm.PUSH1), addr, byte(vm.GAS), // value=0,address=addr, gas=GAS
+		byte(vm.CALL),
+	}
+}
+
+func TestFlatCallTracer(t *testing.T) {
+	res, tx := traceContractCall(t, "flatCallTracer", nil, callCode(0xff))
+
+	var traces []flatCallTrace
+	require.NoError(t, json.Unmarshal(res, &traces))
+	require.Len(t, traces, 2)
+
+	top := traces[0]
+	require.Equal(t, "call", top.Type)
+	require.Equal(t, "call", top.Action.CallType)
+	require.Equal(t, common.HexToAddress("0x00000000000000000000000000000000deadbeef"), top.Action.To)
+	require.Equal(t, hexutil.Uint64(100000), *top.Action.Gas)
+	require.Empty(t, top.TraceAddress)
+	require.Equal(t, 1, top.Subtraces)
+	require.Equal(t, uint64(8000000), top.BlockNumber)
+	require.Equal(t, tx.Hash(), *top.TransactionHash)
+	require.Equal(t, uint64(3), top.TransactionPosition)
+
+	inner := traces[1]
+	require.Equal(t, "call", inner.Type)
+	require.Equal(t, common.HexToAddress("0x00000000000000000000000000000000000000ff"), inner.Action.To)
+	require.Equal(t, []int{0}, inner.TraceAddress)
+	require.Equal(t, 0, inner.Subtraces)
+	require.Zero(t, inner.Action.Value.ToInt().Sign())
+}
+
+func TestFlatCallTracerPrecompiles(t *testing.T) {
+	// Calls to precompiles are dropped by default, as parity does.
+	res, _ := traceContractCall(t, "flatCallTracer", nil, callCode(0x02))
+	var traces []flatCallTrace
+	require.NoError(t, json.Unmarshal(res, &traces))
+	require.Len(t, traces, 1)
+	require.Equal(t, 0, traces[0].Subtraces)
+
+	res, _ = traceContractCall(t, "flatCallTracer", json.RawMessage(`{"includePrecompiles":true}`), callCode(0x02))
+	traces = nil
+	require.NoError(t, json.Unmarshal(res, &traces))
+	require.Len(t, traces, 2)
+	require.Equal(t, 1, traces[0].Subtraces)
+	require.Equal(t, common.HexToAddress("0x02"), traces[1].Action.To)
+	require.Equal(t, []int{0}, traces[1].TraceAddress)
+}
+
+func TestFlatCallTracerParityErrors(t *testing.T) {
+	code := []byte{byte(vm.PUSH1), 0x0, byte(vm.JUMP)}
+
+	res, _ := traceContractCall(t, "flatCallTracer", nil, code)
+	var traces []flatCallTrace
+	require.NoError(t, json.Unmarshal(res, &traces))
+	require.Len(t, traces, 1)
+	require.Equal(t, vm.ErrInvalidJump.Error(), traces[0].Error)
+
+	res, _ = traceContractCall(t, "flatCallTracer", json.RawMessage(`{"convertParityErrors":true}`), code)
+	traces = nil
+	require.NoError(t, json.Unmarshal(res, &traces))
+	require.Len(t, traces, 1)
+	require.Equal(t, "Bad jump destination", traces[0].Error)
+}
@@ -25,6 +25,7 @@ import (
 	jsoniter "github.com/json-iterator/go"
 
 	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/hexutil"
 	"github.com/erigontech/erigon-lib/types"
 	"github.com/erigontech/erigon/core/tracing"
 	"github.com/erigontech/erigon/core/vm"
@@ -49,6 +50,9 @@ type JsonStreamLogger struct {
 	output    []byte //nolint
 	err       error  //nolint
 	env       *tracing.VMContext
+
+	eip3155 bool // write one EIP-3155 json object per line instead of a json array
+	steps   int  // number of EIP-3155 steps written, bounded by cfg.Limit
 }
 
 // NewStructLogger returns a new logger
@@ -65,13 +69,24 @@ func NewJsonStreamLogger(cfg *LogConfig, ctx context.Context, stream *jsoniter.S
 	return logger
 }
 
+// NewEIP3155StreamLogger returns a logger which writes every execution step as
+// a separate EIP-3155 json object followed by a newline (JSONL), and a summary
+// line once the transaction is done.
+func NewEIP3155StreamLogger(cfg *LogConfig, ctx context.Context, stream *jsoniter.Stream) *JsonStreamLogger {
+	logger := NewJsonStreamLogger(cfg, ctx, stream)
+	logger.eip3155 = true
+	return logger
+}
+
 func (l *JsonStreamLogger) Tracer() *tracers.Tracer {
-	return &tracers.Tracer{
-		Hooks: &tracing.Hooks{
-			OnTxStart: l.OnTxStart,
-			OnOpcode:  l.OnOpcode,
-		},
+	hooks := &tracing.Hooks{
+		OnTxStart: l.OnTxStart,
+		OnOpcode:  l.OnOpcode,
 	}
+	if l.eip3155 {
+		hooks.OnExit = l.OnExit
+	}
+	return &tracers.Tracer{Hooks: hooks}
 }
 
 func (l *JsonStreamLogger) OnTxStart(env *tracing.VMContext, tx types.Transaction, from common.Address) {
@@ -94,6 +109,14 @@ func (l *JsonStreamLogger) OnOpcode(pc uint64, typ byte, gas, cost uint64, scope
 	if l.cfg.Limit != 0 && l.cfg.Limit <= len(l.logs) {
 		return
 	}
+	if l.eip3155 {
+		if l.cfg.Limit != 0 && l.cfg.Limit <= l.steps {
+			return
+		}
+		l.steps++
+		l.writeEIP3155(pc, op, gas, cost, memory, stack, depth, err)
+		return
+	}
 	if !l.firstCapture {
 		l.stream.WriteMore()
 	} else {
@@ -201,3 +224,77 @@ func (l *JsonStreamLogger) OnOpcode(pc uint64, typ byte, gas, cost uint64, scope
 	l.stream.WriteObjectEnd()
 	_ = l.stream.Flush()
 }
+
+// writeEIP3155 writes a single execution step in the EIP-3155 format.
+func (l *JsonStreamLogger) writeEIP3155(pc uint64, op vm.OpCode, gas, cost uint64, memory []byte, stack []uint256.Int, depth int, err error) {
+	l.stream.WriteObjectStart()
+	l.stream.WriteObjectField("pc")
+	l.stream.WriteUint64(pc)
+	l.stream.WriteMore()
+	l.stream.WriteObjectField("op")
+	l.stream.WriteUint8(byte(op))
+	l.stream.WriteMore()
+	l.stream.WriteObjectField("gas")
+	l.stream.WriteString(hexutil.EncodeUint64(gas))
+	l.stream.WriteMore()
+	l.stream.WriteObjectField("gasCost")
+	l.stream.WriteString(hexutil.EncodeUint64(cost))
+	l.stream.WriteMore()
+	l.stream.WriteObjectField("memSize")
+	l.stream.WriteInt(len(memory))
+	if !l.cfg.DisableStack {
+		l.stream.WriteMore()
+		l.stream.WriteObjectField("stack")
+		l.stream.WriteArrayStart()
+		for i, stackValue := range stack {
+			if i > 0 {
+				l.stream.WriteMore()
+			}
+			l.stream.WriteString(stackValue.Hex())
+		}
+		l.stream.WriteArrayEnd()
+	}
+	if !l.cfg.DisableMemory {
+		l.stream.WriteMore()
+		l.stream.WriteObjectField("memory")
+		l.stream.WriteString(hexutil.Encode(memory))
+	}
+	l.stream.WriteMore()
+	l.stream.WriteObjectField("depth")
+	l.stream.WriteInt(depth)
+	l.stream.WriteMore()
+	l.stream.WriteObjectField("refund")
+	l.stream.WriteUint64(l.env.IntraBlockState.GetRefund())
+	l.stream.WriteMore()
+	l.stream.WriteObjectField("opName")
+	l.stream.WriteString(op.String())
+	if err != nil {
+		l.stream.WriteMore()
+		l.stream.WriteObjectField("error")
+		l.stream.WriteString(err.Error())
+	}
+	l.stream.WriteObjectEnd()
+	l.stream.WriteRaw("\n")
+	_ = l.stream.Flush()
+}
+
+// OnExit writes the EIP-3155 summary line at the end of the top-level call.
+func (l *JsonStreamLogger) OnExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	if depth > 0 {
+		return
+	}
+	l.stream.WriteObjectStart()
+	l.stream.WriteObjectField("output")
+	l.stream.WriteString(hex.EncodeToString(output))
+	l.stream.WriteMore()
+	l.stream.WriteObjectField("gasUsed")
+	l.stream.WriteString(hexutil.EncodeUint64(gasUsed))
+	if err != nil {
+		l.stream.WriteMore()
+		l.stream.WriteObjectField("error")
+		l.stream.WriteString(err.Error())
+	}
+	l.stream.WriteObjectEnd()
+	l.stream.WriteRaw("\n")
+	_ = l.stream.Flush()
+}
@@ -20,10 +20,15 @@
 package logger
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"math/big"
+	"strings"
 	"testing"
 
 	"github.com/holiman/uint256"
+	jsoniter "github.com/json-iterator/go"
 
 	"github.com/erigontech/erigon-lib/chain"
 	"github.com/erigontech/erigon-lib/common"
@@ -112,3 +117,69 @@ func TestStoreCapture(t *testing.T) {
 //		t.Errorf("expected %x, got %x", exp, logger.storage[contract.Address()][index])
 //	}
 //}
+
+func TestEIP3155StreamLogger(t *testing.T) {
+	c := vm.NewJumpDestCache(128)
+	var (
+		buf      bytes.Buffer
+		stream   = jsoniter.NewStream(jsoniter.ConfigDefault, &buf, 4096)
+		logger   = NewEIP3155StreamLogger(&LogConfig{DisableMemory: true}, context.Background(), stream)
+		tracer   = logger.Tracer()
+		evm      = vm.NewEVM(evmtypes.BlockContext{}, evmtypes.TxContext{}, &dummyStatedb{}, chain.TestChainConfig, vm.Config{Tracer: tracer.Hooks})
+		contract = vm.NewContract(&dummyContractRef{}, common.Address{}, new(uint256.Int), 100000, false /* skipAnalysis */, c)
+	)
+	contract.Code = []byte{byte(vm.PUSH1), 0x1, byte(vm.PUSH1), 0x0, byte(vm.SSTORE)}
+	tracer.OnTxStart(evm.GetVMContext(), nil, common.Address{})
+	if _, err := evm.Interpreter().Run(contract, []byte{}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// as in geth, reading past the end of the code yields a STOP which is traced like any other step. There is no
+	// summary line, it's written on the exit of the top-level call which the interpreter doesn't make.
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines, got %d: %s", len(lines), buf.String())
+	}
+	type step struct {
+		Pc     uint64   `json:"pc"`
+		Op     byte     `json:"op"`
+		Gas    string   `json:"gas"`
+		Stack  []string `json:"stack"`
+		Refund uint64   `json:"refund"`
+		OpName string   `json:"opName"`
+		Memory *string  `json:"memory"`
+	}
+	var sstore step
+	if err := json.Unmarshal([]byte(lines[2]), &sstore); err != nil {
+		t.Fatalf("invalid json line %q: %v", lines[2], err)
+	}
+	if sstore.Pc != 4 || sstore.Op != byte(vm.SSTORE) || sstore.OpName != "SSTORE" {
+		t.Errorf("unexpected step %+v", sstore)
+	}
+	if len(sstore.Stack) != 2 || sstore.Stack[0] != "0x1" || sstore.Stack[1] != "0x0" {
+		t.Errorf("unexpected stack %v", sstore.Stack)
+	}
+	if sstore.Refund != 1337 || sstore.Memory != nil || !strings.HasPrefix(sstore.Gas, "0x") {
+		t.Errorf("unexpected step %+v", sstore)
+	}
+	var stop step
+	if err := json.Unmarshal([]byte(lines[3]), &stop); err != nil {
+		t.Fatalf("invalid json line %q: %v", lines[3], err)
+	}
+	if stop.Pc != 5 || stop.Op != byte(vm.STOP) || stop.OpName != "STOP" {
+		t.Errorf("unexpected step %+v", stop)
+	}
+
+	// the steps are limited as with the json array
+	buf.Reset()
+	logger = NewEIP3155StreamLogger(&LogConfig{DisableMemory: true, Limit: 2}, context.Background(), stream)
+	tracer = logger.Tracer()
+	evm = vm.NewEVM(evmtypes.BlockContext{}, evmtypes.TxContext{}, &dummyStatedb{}, chain.TestChainConfig, vm.Config{Tracer: tracer.Hooks})
+	tracer.OnTxStart(evm.GetVMContext(), nil, common.Address{})
+	if _, err := evm.Interpreter().Run(contract, []byte{}, false); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n"); len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %s", len(lines), buf.String())
+	}
+}
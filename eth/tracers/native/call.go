@@ -132,15 +132,10 @@ type callTracerConfig struct {
 // newCallTracer returns a native go tracer which tracks
 // call frames of a tx, and implements vm.EVMLogger.
 func newCallTracer(ctx *tracers.Context, cfg json.RawMessage) (*tracers.Tracer, error) {
-	config := defaultCallTracerConfig()
-	if cfg != nil {
-		if err := json.Unmarshal(cfg, &config); err != nil {
-			return nil, err
-		}
+	t, err := newCallTracerObject(ctx, cfg)
+	if err != nil {
+		return nil, err
 	}
-	// First callframe contains txn context info
-	// and is populated on start and end.
-	t := &callTracer{callstack: make([]callFrame, 0, 1), config: config}
 	return &tracers.Tracer{
 		Hooks: &tracing.Hooks{
 			OnTxStart: t.OnTxStart,
@@ -154,6 +149,18 @@ func newCallTracer(ctx *tracers.Context, cfg json.RawMessage) (*tracers.Tracer,
 	}, nil
 }
 
+func newCallTracerObject(ctx *tracers.Context, cfg json.RawMessage) (*callTracer, error) {
+	config := defaultCallTracerConfig()
+	if cfg != nil {
+		if err := json.Unmarshal(cfg, &config); err != nil {
+			return nil, err
+		}
+	}
+	// First callframe contains txn context info
+	// and is populated on start and end.
+	return &callTracer{callstack: make([]callFrame, 0, 1), config: config}, nil
+}
+
 // CaptureStart implements the EVMLogger interface to initialize the tracing operation.
 func (t *callTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, precompile bool, create bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
 	t.precompiles = append(t.precompiles, precompile)
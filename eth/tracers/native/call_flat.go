@@ -0,0 +1,382 @@
+// Copyright 2022 The go-ethereum Authors
+// (original work)
+// Copyright 2025 The Erigon Authors
+// (modifications)
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package native
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync/atomic"
+
+	"github.com/holiman/uint256"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon-lib/types"
+	"github.com/erigontech/erigon/core/tracing"
+	"github.com/erigontech/erigon/core/vm"
+	"github.com/erigontech/erigon/eth/tracers"
+)
+
+//go:generate gencodec -type flatCallAction -field-override flatCallActionMarshaling -out gen_flatcallaction_json.go
+//go:generate gencodec -type flatCallResult -field-override flatCallResultMarshaling -out gen_flatcallresult_json.go
+
+func init() {
+	register("flatCallTracer", newFlatCallTracer)
+}
+
+var parityErrorMapping = map[string]string{
+	"contract creation code storage out of gas": "Out of gas",
+	"out of gas":                "Out of gas",
+	"gas uint64 overflow":       "Out of gas",
+	"max code size exceeded":    "Out of gas",
+	"invalid jump destination":  "Bad jump destination",
+	"execution reverted":        "Reverted",
+	"return data out of bounds": "Out of bounds",
+	"max call depth exceeded":   "Out of stack",
+	"precompiled failed":        "Built-in failed",
+	"invalid input length":      "Built-in failed",
+}
+
+var parityErrorMappingStartingWith = map[string]string{
+	"invalid opcode:": "Bad instruction",
+	"stack underflow": "Stack underflow",
+}
+
+// flatCallFrame is a standalone callframe.
+type flatCallFrame struct {
+	Action              flatCallAction  `json:"action"`
+	BlockHash           *common.Hash    `json:"blockHash"`
+	BlockNumber         uint64          `json:"blockNumber"`
+	Error               string          `json:"error,omitempty"`
+	Result              *flatCallResult `json:"result,omitempty"`
+	Subtraces           int             `json:"subtraces"`
+	TraceAddress        []int           `json:"traceAddress"`
+	TransactionHash     *common.Hash    `json:"transactionHash"`
+	TransactionPosition uint64          `json:"transactionPosition"`
+	Type                string          `json:"type"`
+}
+
+type flatCallAction struct {
+	Author         *common.Address `json:"author,omitempty"`
+	RewardType     string          `json:"rewardType,omitempty"`
+	SelfDestructed *common.Address `json:"address,omitempty"`
+	Balance        *big.Int        `json:"balance,omitempty"`
+	CallType       string          `json:"callType,omitempty"`
+	CreationMethod string          `json:"creationMethod,omitempty"`
+	From           *common.Address `json:"from,omitempty"`
+	Gas            *uint64         `json:"gas,omitempty"`
+	Init           *[]byte         `json:"init,omitempty"`
+	Input          *[]byte         `json:"input,omitempty"`
+	RefundAddress  *common.Address `json:"refundAddress,omitempty"`
+	To             *common.Address `json:"to,omitempty"`
+	Value          *big.Int        `json:"value,omitempty"`
+}
+
+type flatCallActionMarshaling struct {
+	Balance *hexutil.Big
+	Gas     *hexutil.Uint64
+	Init    *hexutil.Bytes
+	Input   *hexutil.Bytes
+	Value   *hexutil.Big
+}
+
+type flatCallResult struct {
+	Address *common.Address `json:"address,omitempty"`
+	Code    *[]byte         `json:"code,omitempty"`
+	GasUsed *uint64         `json:"gasUsed,omitempty"`
+	Output  *[]byte         `json:"output,omitempty"`
+}
+
+type flatCallResultMarshaling struct {
+	Code    *hexutil.Bytes
+	GasUsed *hexutil.Uint64
+	Output  *hexutil.Bytes
+}
+
+// flatCallTracer reports call frame information of a txn in a flat format, i.e.
+// as opposed to the nested format of `callTracer`.
+type flatCallTracer struct {
+	tracer      *callTracer
+	config      flatCallTracerConfig
+	ctx         *tracers.Context // Holds tracer context data
+	blockNumber uint64
+	interrupt   atomic.Bool // Atomic flag to signal execution interruption
+}
+
+type flatCallTracerConfig struct {
+	ConvertParityErrors bool `json:"convertParityErrors"` // If true, call tracer converts errors to parity format
+	IncludePrecompiles  bool `json:"includePrecompiles"`  // If true, call tracer includes calls to precompiled contracts
+}
+
+// newFlatCallTracer returns a new flatCallTracer.
+func newFlatCallTracer(ctx *tracers.Context, cfg json.RawMessage) (*tracers.Tracer, error) {
+	var config flatCallTracerConfig
+	if cfg != nil {
+		if err := json.Unmarshal(cfg, &config); err != nil {
+			return nil, err
+		}
+	}
+
+	// Create inner call tracer with default configuration, don't forward
+	// the OnlyTopCall or WithLog to inner for now. Precompile filtering is
+	// done by the flat tracer itself, as parity only drops plain calls.
+	t, err := newCallTracerObject(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ft := &flatCallTracer{tracer: t, ctx: ctx, config: config}
+	return &tracers.Tracer{
+		Hooks: &tracing.Hooks{
+			OnTxStart: ft.OnTxStart,
+			OnTxEnd:   ft.OnTxEnd,
+			OnEnter:   ft.OnEnter,
+			OnExit:    ft.OnExit,
+		},
+		GetResult: ft.GetResult,
+		Stop:      ft.Stop,
+	}, nil
+}
+
+// OnEnter is called when EVM enters a new scope (via call, create or selfdestruct).
+func (t *flatCallTracer) OnEnter(depth int, typ byte, from common.Address, to common.Address, precompile bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
+	if t.interrupt.Load() {
+		return
+	}
+	t.tracer.OnEnter(depth, typ, from, to, precompile, input, gas, value, code)
+
+	if depth == 0 {
+		return
+	}
+	// Child calls must have a value, even if it's zero.
+	// Practically speaking, only STATICCALL has nil value. Set it to zero.
+	if call := &t.tracer.callstack[len(t.tracer.callstack)-1]; call.Value == nil {
+		call.Value = big.NewInt(0)
+	}
+}
+
+// OnExit is called when EVM exits a scope, even if the scope didn't
+// execute any code.
+func (t *flatCallTracer) OnExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	if t.interrupt.Load() {
+		return
+	}
+	if depth == 0 {
+		t.tracer.OnExit(depth, output, gasUsed, err, reverted)
+		return
+	}
+	var (
+		size    = len(t.tracer.callstack)
+		dropped = false
+	)
+	// Parity traces don't include CALL/STATICCALLs to precompiles.
+	// By default we remove them from the callstack.
+	if !t.config.IncludePrecompiles && size > 1 && len(t.tracer.precompiles) > 0 {
+		typ := t.tracer.callstack[size-1].Type
+		dropped = t.tracer.precompiles[len(t.tracer.precompiles)-1] && (typ == vm.CALL || typ == vm.STATICCALL)
+	}
+	t.tracer.OnExit(depth, output, gasUsed, err, reverted)
+	if !dropped {
+		return
+	}
+	// The exited frame has just been nested into its parent; drop it again.
+	parent := &t.tracer.callstack[len(t.tracer.callstack)-1]
+	parent.Calls = parent.Calls[:len(parent.Calls)-1]
+}
+
+func (t *flatCallTracer) OnTxStart(env *tracing.VMContext, tx types.Transaction, from common.Address) {
+	if t.interrupt.Load() {
+		return
+	}
+	t.tracer.OnTxStart(env, tx, from)
+	t.blockNumber = env.BlockNumber
+}
+
+func (t *flatCallTracer) OnTxEnd(receipt *types.Receipt, err error) {
+	if t.interrupt.Load() {
+		return
+	}
+	t.tracer.OnTxEnd(receipt, err)
+}
+
+// GetResult returns the json-encoded list of flat call traces, and any
+// error arising from the encoding or forceful termination (via `Stop`).
+func (t *flatCallTracer) GetResult() (json.RawMessage, error) {
+	if len(t.tracer.callstack) < 1 {
+		return nil, errors.New("invalid number of calls")
+	}
+
+	flat, err := flatFromNested(&t.tracer.callstack[0], []int{}, t.config.ConvertParityErrors, t.ctx, t.blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := json.Marshal(flat)
+	if err != nil {
+		return nil, err
+	}
+	return res, t.tracer.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *flatCallTracer) Stop(err error) {
+	t.tracer.Stop(err)
+	t.interrupt.Store(true)
+}
+
+func flatFromNested(input *callFrame, traceAddress []int, convertErrs bool, ctx *tracers.Context, blockNumber uint64) (output []flatCallFrame, err error) {
+	var frame *flatCallFrame
+	switch input.Type {
+	case vm.CREATE, vm.CREATE2:
+		frame = newFlatCreate(input)
+	case vm.SELFDESTRUCT:
+		frame = newFlatSelfdestruct(input)
+	case vm.CALL, vm.STATICCALL, vm.CALLCODE, vm.DELEGATECALL:
+		frame = newFlatCall(input)
+	default:
+		return nil, fmt.Errorf("unrecognized call frame type: %s", input.Type)
+	}
+
+	frame.Error = input.Error
+	if convertErrs {
+		convertErrorToParity(frame)
+	}
+
+	// Revert output contains useful information (revert reason).
+	// Otherwise discard result.
+	if input.Error != "" && input.Error != vm.ErrExecutionReverted.Error() {
+		frame.Result = nil
+	}
+
+	frame.TraceAddress = traceAddress
+	frame.Subtraces = len(input.Calls)
+	fillCallFrameFromContext(frame, ctx, blockNumber)
+	output = append(output, *frame)
+
+	// Recursively generate flat frames for child calls.
+	for i, childCall := range input.Calls {
+		childAddr := childTraceAddress(traceAddress, i)
+		childCallCopy := childCall
+		flat, err := flatFromNested(&childCallCopy, childAddr, convertErrs, ctx, blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		output = append(output, flat...)
+	}
+
+	return output, nil
+}
+
+func newFlatCreate(input *callFrame) *flatCallFrame {
+	var (
+		actionInit = input.Input[:]
+		resultCode = input.Output[:]
+	)
+
+	return &flatCallFrame{
+		Type: strings.ToLower(vm.CREATE.String()),
+		Action: flatCallAction{
+			From:           &input.From,
+			Gas:            &input.Gas,
+			Value:          input.Value,
+			Init:           &actionInit,
+			CreationMethod: strings.ToLower(input.Type.String()),
+		},
+		Result: &flatCallResult{
+			GasUsed: &input.GasUsed,
+			Address: &input.To,
+			Code:    &resultCode,
+		},
+	}
+}
+
+func newFlatCall(input *callFrame) *flatCallFrame {
+	var (
+		actionInput  = input.Input[:]
+		resultOutput = input.Output[:]
+	)
+
+	return &flatCallFrame{
+		Type: strings.ToLower(vm.CALL.String()),
+		Action: flatCallAction{
+			From:     &input.From,
+			To:       &input.To,
+			Gas:      &input.Gas,
+			Value:    input.Value,
+			CallType: strings.ToLower(input.Type.String()),
+			Input:    &actionInput,
+		},
+		Result: &flatCallResult{
+			GasUsed: &input.GasUsed,
+			Output:  &resultOutput,
+		},
+	}
+}
+
+func newFlatSelfdestruct(input *callFrame) *flatCallFrame {
+	return &flatCallFrame{
+		Type: "suicide",
+		Action: flatCallAction{
+			SelfDestructed: &input.From,
+			Balance:        input.Value,
+			RefundAddress:  &input.To,
+		},
+	}
+}
+
+func fillCallFrameFromContext(callFrame *flatCallFrame, ctx *tracers.Context, blockNumber uint64) {
+	callFrame.BlockNumber = blockNumber
+	if ctx == nil {
+		return
+	}
+	if ctx.BlockHash != (common.Hash{}) {
+		callFrame.BlockHash = &ctx.BlockHash
+	}
+	if ctx.TxHash != (common.Hash{}) {
+		callFrame.TransactionHash = &ctx.TxHash
+	}
+	callFrame.TransactionPosition = uint64(ctx.TxIndex)
+}
+
+func convertErrorToParity(call *flatCallFrame) {
+	if call.Error == "" {
+		return
+	}
+
+	if parityError, ok := parityErrorMapping[call.Error]; ok {
+		call.Error = parityError
+	} else {
+		for gethError, parityError := range parityErrorMappingStartingWith {
+			if strings.HasPrefix(call.Error, gethError) {
+				call.Error = parityError
+			}
+		}
+	}
+}
+
+func childTraceAddress(a []int, i int) []int {
+	child := make([]int, 0, len(a)+1)
+	child = append(child, a...)
+	child = append(child, i)
+	return child
+}
@@ -172,13 +172,26 @@ type ReceiptsGetter interface {
 	GetCachedReceipts(ctx context.Context, blockHash common.Hash) (types.Receipts, bool)
 }
 
+// ReceiptsEncoder encodes the receipts of a single block for a receipts response.
+type ReceiptsEncoder func(receipts types.Receipts) ([]byte, error)
+
+// EncodeReceipts66 encodes receipts as in eth/66..eth/68, bloom included.
+func EncodeReceipts66(receipts types.Receipts) ([]byte, error) {
+	return rlp.EncodeToBytes(receipts)
+}
+
+// EncodeReceipts69 encodes receipts as in eth/69, without the bloom.
+func EncodeReceipts69(receipts types.Receipts) ([]byte, error) {
+	return rlp.EncodeToBytes(types.ReceiptsForNetwork69(receipts))
+}
+
 type cachedReceipts struct {
 	EncodedReceipts []rlp.RawValue
 	Bytes           int // total size of the encoded receipts
 	PendingIndex    int // index of the first not-found receipt in the query
 }
 
-func AnswerGetReceiptsQueryCacheOnly(ctx context.Context, receiptsGetter ReceiptsGetter, query GetReceiptsPacket) (*cachedReceipts, bool, error) {
+func AnswerGetReceiptsQueryCacheOnly(ctx context.Context, receiptsGetter ReceiptsGetter, query GetReceiptsPacket, encode ReceiptsEncoder) (*cachedReceipts, bool, error) {
 	var (
 		bytes        int
 		receiptsList []rlp.RawValue
@@ -193,7 +206,7 @@ func AnswerGetReceiptsQueryCacheOnly(ctx context.Context, receiptsGetter Receipt
 			break
 		}
 		if receipts, ok := receiptsGetter.GetCachedReceipts(ctx, hash); ok {
-			if encoded, err := rlp.EncodeToBytes(receipts); err != nil {
+			if encoded, err := encode(receipts); err != nil {
 				return nil, needMore, fmt.Errorf("failed to encode receipt: %w", err)
 			} else {
 				receiptsList = append(receiptsList, encoded)
@@ -214,7 +227,7 @@ func AnswerGetReceiptsQueryCacheOnly(ctx context.Context, receiptsGetter Receipt
 	}, needMore, nil
 }
 
-func AnswerGetReceiptsQuery(ctx context.Context, cfg *chain.Config, receiptsGetter ReceiptsGetter, br services.FullBlockReader, db kv.TemporalTx, query GetReceiptsPacket, cachedReceipts *cachedReceipts) ([]rlp.RawValue, error) { //nolint:unparam
+func AnswerGetReceiptsQuery(ctx context.Context, cfg *chain.Config, receiptsGetter ReceiptsGetter, br services.FullBlockReader, db kv.TemporalTx, query GetReceiptsPacket, cachedReceipts *cachedReceipts, encode ReceiptsEncoder) ([]rlp.RawValue, error) { //nolint:unparam
 	// Gather state data until the fetch or network limits is reached
 	var (
 		bytes        int
@@ -268,7 +281,7 @@ func AnswerGetReceiptsQuery(ctx context.Context, cfg *chain.Config, receiptsGett
 		//}
 
 		// If known, encode and queue for response packet
-		if encoded, err := rlp.EncodeToBytes(results); err != nil {
+		if encoded, err := encode(results); err != nil {
 			return nil, fmt.Errorf("failed to encode receipt: %w", err)
 		} else {
 			receipts = append(receipts, encoded)
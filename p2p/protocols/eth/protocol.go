@@ -35,12 +35,30 @@ import (
 var ProtocolToString = map[uint]string{
 	direct.ETH67: "eth67",
 	direct.ETH68: "eth68",
+	direct.ETH69: "eth69",
 }
 
 // ProtocolName is the official short name of the `eth` protocol used during
 // devp2p capability negotiation.
 const ProtocolName = "eth"
 
+// ProtocolLengths are the number of implemented message codes of each `eth` protocol version.
+var ProtocolLengths = map[uint]uint64{
+	direct.ETH67: 17,
+	direct.ETH68: 17,
+	direct.ETH69: 18,
+}
+
+// ProtocolVersions returns the `eth` protocol versions a sentry running the given version
+// advertises during devp2p capability negotiation, highest first. An eth/69 sentry keeps
+// serving eth/68 peers.
+func ProtocolVersions(version uint) []uint {
+	if version == direct.ETH69 {
+		return []uint{direct.ETH69, direct.ETH68}
+	}
+	return []uint{version}
+}
+
 // maxMessageSize is the maximum cap on the size of a protocol message.
 const maxMessageSize = 10 * 1024 * 1024
 const ProtocolMaxMsgSize = maxMessageSize
@@ -62,6 +80,9 @@ const (
 	NewPooledTransactionHashesMsg = 0x08
 	GetPooledTransactionsMsg      = 0x09
 	PooledTransactionsMsg         = 0x0a
+
+	// Protocol messages introduced in eth/69
+	BlockRangeUpdateMsg = 0x11
 )
 
 var ToProto = map[uint]map[uint64]proto_sentry.MessageId{
@@ -93,6 +114,19 @@ var ToProto = map[uint]map[uint64]proto_sentry.MessageId{
 		GetPooledTransactionsMsg:      proto_sentry.MessageId_GET_POOLED_TRANSACTIONS_66,
 		PooledTransactionsMsg:         proto_sentry.MessageId_POOLED_TRANSACTIONS_66,
 	},
+	direct.ETH69: {
+		GetBlockHeadersMsg:            proto_sentry.MessageId_GET_BLOCK_HEADERS_66,
+		BlockHeadersMsg:               proto_sentry.MessageId_BLOCK_HEADERS_66,
+		GetBlockBodiesMsg:             proto_sentry.MessageId_GET_BLOCK_BODIES_66,
+		BlockBodiesMsg:                proto_sentry.MessageId_BLOCK_BODIES_66,
+		GetReceiptsMsg:                proto_sentry.MessageId_GET_RECEIPTS_69, // Modified in eth/69
+		ReceiptsMsg:                   proto_sentry.MessageId_RECEIPTS_69,     // Modified in eth/69
+		TransactionsMsg:               proto_sentry.MessageId_TRANSACTIONS_66,
+		NewPooledTransactionHashesMsg: proto_sentry.MessageId_NEW_POOLED_TRANSACTION_HASHES_68,
+		GetPooledTransactionsMsg:      proto_sentry.MessageId_GET_POOLED_TRANSACTIONS_66,
+		PooledTransactionsMsg:         proto_sentry.MessageId_POOLED_TRANSACTIONS_66,
+		BlockRangeUpdateMsg:           proto_sentry.MessageId_BLOCK_RANGE_UPDATE_69,
+	},
 }
 
 var FromProto = map[uint]map[proto_sentry.MessageId]uint64{
@@ -124,6 +158,19 @@ var FromProto = map[uint]map[proto_sentry.MessageId]uint64{
 		proto_sentry.MessageId_GET_POOLED_TRANSACTIONS_66:       GetPooledTransactionsMsg,
 		proto_sentry.MessageId_POOLED_TRANSACTIONS_66:           PooledTransactionsMsg,
 	},
+	direct.ETH69: {
+		proto_sentry.MessageId_GET_BLOCK_HEADERS_66:             GetBlockHeadersMsg,
+		proto_sentry.MessageId_BLOCK_HEADERS_66:                 BlockHeadersMsg,
+		proto_sentry.MessageId_GET_BLOCK_BODIES_66:              GetBlockBodiesMsg,
+		proto_sentry.MessageId_BLOCK_BODIES_66:                  BlockBodiesMsg,
+		proto_sentry.MessageId_GET_RECEIPTS_69:                  GetReceiptsMsg,
+		proto_sentry.MessageId_RECEIPTS_69:                      ReceiptsMsg,
+		proto_sentry.MessageId_TRANSACTIONS_66:                  TransactionsMsg,
+		proto_sentry.MessageId_NEW_POOLED_TRANSACTION_HASHES_68: NewPooledTransactionHashesMsg,
+		proto_sentry.MessageId_GET_POOLED_TRANSACTIONS_66:       GetPooledTransactionsMsg,
+		proto_sentry.MessageId_POOLED_TRANSACTIONS_66:           PooledTransactionsMsg,
+		proto_sentry.MessageId_BLOCK_RANGE_UPDATE_69:            BlockRangeUpdateMsg,
+	},
 }
 
 // Packet represents a p2p message in the `eth` protocol.
@@ -142,6 +189,38 @@ type StatusPacket struct {
 	ForkID          forkid.ID
 }
 
+// StatusPacket69 is the network packet for the status message for eth/69 and later.
+// It drops the total difficulty and head hash in favour of the range of blocks the
+// node is able to serve.
+type StatusPacket69 struct {
+	ProtocolVersion uint32
+	NetworkID       uint64
+	Genesis         common.Hash
+	ForkID          forkid.ID
+	EarliestBlock   uint64
+	LatestBlock     uint64
+	LatestBlockHash common.Hash
+}
+
+// BlockRangeUpdatePacket is the eth/69 announcement of a change in the range of
+// blocks the node is able to serve.
+type BlockRangeUpdatePacket struct {
+	EarliestBlock   uint64
+	LatestBlock     uint64
+	LatestBlockHash common.Hash
+}
+
+// Validate checks that the announced block range is well-formed.
+func (p *BlockRangeUpdatePacket) Validate() error {
+	if p.EarliestBlock > p.LatestBlock {
+		return fmt.Errorf("invalid block range: earliest %d > latest %d", p.EarliestBlock, p.LatestBlock)
+	}
+	if p.LatestBlockHash == (common.Hash{}) {
+		return fmt.Errorf("invalid block range: zero latest block hash")
+	}
+	return nil
+}
+
 // NewBlockHashesPacket is the network packet for the block announcements.
 type NewBlockHashesPacket []struct {
 	Hash   common.Hash // Hash of one particular block being announced
@@ -354,6 +433,13 @@ type ReceiptsPacket66 struct {
 	ReceiptsPacket
 }
 
+// ReceiptsPacket69 is the network packet for block receipts distribution over eth/69,
+// where receipts are sent without the bloom.
+type ReceiptsPacket69 struct {
+	RequestId uint64
+	Receipts  [][]*types.ReceiptForNetwork69
+}
+
 // ReceiptsRLPPacket is used for receipts, when we already have it encoded
 type ReceiptsRLPPacket []rlp.RawValue
 
@@ -366,6 +452,9 @@ type ReceiptsRLPPacket66 struct {
 func (*StatusPacket) Name() string { return "Status" }
 func (*StatusPacket) Kind() byte   { return StatusMsg }
 
+func (*StatusPacket69) Name() string { return "Status" }
+func (*StatusPacket69) Kind() byte   { return StatusMsg }
+
 func (*NewBlockHashesPacket) Name() string { return "NewBlockHashes" }
 func (*NewBlockHashesPacket) Kind() byte   { return NewBlockHashesMsg }
 
@@ -389,3 +478,6 @@ func (*GetReceiptsPacket) Kind() byte   { return GetReceiptsMsg }
 
 func (*ReceiptsPacket) Name() string { return "Receipts" }
 func (*ReceiptsPacket) Kind() byte   { return ReceiptsMsg }
+
+func (*BlockRangeUpdatePacket) Name() string { return "BlockRangeUpdate" }
+func (*BlockRangeUpdatePacket) Kind() byte   { return BlockRangeUpdateMsg }
@@ -25,8 +25,10 @@ import (
 	"testing"
 
 	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/direct"
 	"github.com/erigontech/erigon-lib/rlp"
 	"github.com/erigontech/erigon-lib/types"
+	"github.com/erigontech/erigon/p2p/forkid"
 )
 
 // Tests that the custom union field encoder and decoder works correctly.
@@ -230,3 +232,108 @@ func TestEth66Messages(t *testing.T) {
 		}
 	}
 }
+
+// TestEth69Messages tests the encoding of the messages introduced or redefined in eth69
+func TestEth69Messages(t *testing.T) {
+	receipts := []*types.Receipt{
+		{
+			Status:            types.ReceiptStatusFailed,
+			CumulativeGasUsed: 1,
+			Logs: []*types.Log{
+				{
+					Address: common.BytesToAddress([]byte{0x11}),
+					Topics:  []common.Hash{common.HexToHash("dead"), common.HexToHash("beef")},
+					Data:    []byte{0x01, 0x00, 0xff},
+				},
+			},
+		},
+	}
+	receiptsRlp, err := EncodeReceipts69(receipts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, tc := range []struct {
+		message interface{}
+		want    []byte
+	}{
+		{
+			ReceiptsPacket69{1111, [][]*types.ReceiptForNetwork69{types.ReceiptsForNetwork69(receipts)}},
+			common.FromHex("f86d820457f868f866f864808001f85ff85d940000000000000000000000000000000000000011f842a0000000000000000000000000000000000000000000000000000000000000deada0000000000000000000000000000000000000000000000000000000000000beef830100ff"),
+		},
+		{ // Identical to non-rlp-shortcut version
+			ReceiptsRLPPacket66{1111, ReceiptsRLPPacket([]rlp.RawValue{receiptsRlp})},
+			common.FromHex("f86d820457f868f866f864808001f85ff85d940000000000000000000000000000000000000011f842a0000000000000000000000000000000000000000000000000000000000000deada0000000000000000000000000000000000000000000000000000000000000beef830100ff"),
+		},
+		{
+			&BlockRangeUpdatePacket{EarliestBlock: 1, LatestBlock: 2, LatestBlockHash: common.HexToHash("deadc0de")},
+			common.FromHex("e30102a000000000000000000000000000000000000000000000000000000000deadc0de"),
+		},
+	} {
+		if have, _ := rlp.EncodeToBytes(tc.message); !bytes.Equal(have, tc.want) {
+			t.Errorf("test %d, type %T, have\n\t%x\nwant\n\t%x", i, tc.message, have, tc.want)
+		}
+	}
+}
+
+func TestEth69MessageIds(t *testing.T) {
+	// eth/69 removed the block announcements, which are only mapped for the older versions
+	for _, code := range []uint64{NewBlockHashesMsg, NewBlockMsg} {
+		if _, ok := ToProto[direct.ETH69][code]; ok {
+			t.Errorf("message %d is mapped in eth/69", code)
+		}
+		if _, ok := ToProto[direct.ETH68][code]; !ok {
+			t.Errorf("message %d is not mapped in eth/68", code)
+		}
+	}
+	for code, id := range ToProto[direct.ETH69] {
+		if have := FromProto[direct.ETH69][id]; have != code {
+			t.Errorf("message %s maps back to %d, want %d", id, have, code)
+		}
+	}
+	if len(FromProto[direct.ETH69]) != len(ToProto[direct.ETH69]) {
+		t.Errorf("eth/69 maps differ: %d to proto, %d from proto", len(ToProto[direct.ETH69]), len(FromProto[direct.ETH69]))
+	}
+}
+
+func TestStatusPacket69EncodeDecode(t *testing.T) {
+	status := &StatusPacket69{
+		ProtocolVersion: 69,
+		NetworkID:       1,
+		Genesis:         common.HexToHash("deadc0de"),
+		ForkID:          forkid.ID{Hash: [4]byte{1, 2, 3, 4}, Next: 5},
+		EarliestBlock:   100,
+		LatestBlock:     200,
+		LatestBlockHash: common.HexToHash("feedbeef"),
+	}
+	enc, err := rlp.EncodeToBytes(status)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dec StatusPacket69
+	if err := rlp.DecodeBytes(enc, &dec); err != nil {
+		t.Fatal(err)
+	}
+	if dec != *status {
+		t.Fatalf("encode decode mismatch: have %+v, want %+v", dec, status)
+	}
+
+	// eth/68 status doesn't decode as eth/69 one
+	var old StatusPacket
+	if err := rlp.DecodeBytes(enc, &old); err == nil {
+		t.Fatalf("eth69 status decoded as eth68 one: %+v", old)
+	}
+}
+
+func TestBlockRangeUpdateValidate(t *testing.T) {
+	hash := common.HexToHash("deadc0de")
+	if err := (&BlockRangeUpdatePacket{EarliestBlock: 1, LatestBlock: 1, LatestBlockHash: hash}).Validate(); err != nil {
+		t.Fatalf("valid range rejected: %v", err)
+	}
+	if err := (&BlockRangeUpdatePacket{EarliestBlock: 2, LatestBlock: 1, LatestBlockHash: hash}).Validate(); err == nil {
+		t.Fatal("earliest > latest accepted")
+	}
+	if err := (&BlockRangeUpdatePacket{EarliestBlock: 0, LatestBlock: 1}).Validate(); err == nil {
+		t.Fatal("zero latest block hash accepted")
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package snap
+
+import (
+	"context"
+	"sync"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/crypto"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/kv/order"
+)
+
+// codeIndexBatch is the number of code domain entries scanned per read transaction.
+const codeIndexBatch = 10_000
+
+// codeIndex resolves code hashes to an account holding the code. The code domain is keyed
+// by address while snap asks for bytecodes by hash, so the domain is scanned once and then
+// followed through its history. Every distinct code takes one entry, a few hundred MB on
+// mainnet. Entries may go stale when accounts self-destruct, they are checked on use.
+type codeIndex struct {
+	mu     sync.Mutex
+	byHash map[common.Hash]common.Address
+	ready  bool   // the whole domain has been scanned
+	txNum  uint64 // the changes of the domain before txNum are indexed
+}
+
+func newCodeIndex() *codeIndex {
+	return &codeIndex{byHash: make(map[common.Hash]common.Address)}
+}
+
+func (c *codeIndex) get(hash common.Hash) (common.Address, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	addr, ok := c.byHash[hash]
+	return addr, ok
+}
+
+func (c *codeIndex) add(hash common.Hash, addr common.Address) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.byHash[hash] = addr
+}
+
+func (c *codeIndex) remove(hash common.Hash) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.byHash, hash)
+}
+
+// update indexes the codes changed since the last update, once the domain has been scanned.
+func (c *codeIndex) update(state *stateView) error {
+	c.mu.Lock()
+	from, ready := c.txNum, c.ready
+	c.mu.Unlock()
+	to := state.domains.TxNum() + 1
+	if !ready || from >= to {
+		return nil
+	}
+	it, err := state.tx.HistoryRange(kv.CodeDomain, int(from), int(to), order.Asc, -1)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for it.HasNext() {
+		addr, _, err := it.Next()
+		if err != nil {
+			return err
+		}
+		code, _, err := state.domains.GetLatest(kv.CodeDomain, addr)
+		if err != nil {
+			return err
+		}
+		if len(code) > 0 {
+			c.add(crypto.Keccak256Hash(code), common.BytesToAddress(addr))
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.txNum = max(c.txNum, to)
+	return nil
+}
+
+// indexCodes scans the code domain in batches, so that no read transaction is held for long.
+// The changes made while scanning are caught up from the history by the next update.
+func (s *Server) indexCodes(ctx context.Context) error {
+	var startTxNum uint64
+	if err := s.withState(ctx, func(state *stateView) error {
+		startTxNum = state.domains.TxNum() + 1
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	var from []byte
+	for {
+		var last []byte
+		var scanned int
+		if err := s.db.ViewTemporal(ctx, func(tx kv.TemporalTx) error {
+			it, err := tx.Debug().RangeLatest(kv.CodeDomain, from, nil, codeIndexBatch)
+			if err != nil {
+				return err
+			}
+			defer it.Close()
+			for it.HasNext() {
+				addr, code, err := it.Next()
+				if err != nil {
+					return err
+				}
+				if len(code) > 0 {
+					s.codes.add(crypto.Keccak256Hash(code), common.BytesToAddress(addr))
+				}
+				last = common.Copy(addr)
+				scanned++
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		if scanned < codeIndexBatch || last == nil {
+			break
+		}
+		from = append(last, 0)
+	}
+
+	s.codes.mu.Lock()
+	defer s.codes.mu.Unlock()
+	s.codes.ready = true
+	s.codes.txNum = startTxNum
+	return nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package snap
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/time/rate"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon/p2p"
+)
+
+const (
+	// peerRequestRate and peerRequestBurst limit the requests served to each peer, the
+	// requests above the limit wait for their turn.
+	peerRequestRate  = rate.Limit(10)
+	peerRequestBurst = 20
+)
+
+// Handle serves the snap requests of a peer until reading from the peer fails, the peer
+// misbehaves or ctx is cancelled.
+func (s *Server) Handle(ctx context.Context, rw p2p.MsgReadWriter) error {
+	limiter := rate.NewLimiter(peerRequestRate, peerRequestBurst)
+	for {
+		if err := common.Stopped(ctx.Done()); err != nil {
+			return err
+		}
+		msg, err := rw.ReadMsg()
+		if err != nil {
+			return err
+		}
+		if err := limiter.Wait(ctx); err != nil {
+			msg.Discard()
+			return err
+		}
+		if err := s.handleMessage(ctx, msg, rw); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handleMessage(ctx context.Context, msg p2p.Msg, w p2p.MsgWriter) error {
+	if msg.Size > ProtocolMaxMsgSize {
+		msg.Discard()
+		return fmt.Errorf("message is too large %d, limit %d", msg.Size, ProtocolMaxMsgSize)
+	}
+	switch msg.Code {
+	case GetAccountRangeMsg:
+		var query GetAccountRangePacket
+		if err := msg.Decode(&query); err != nil {
+			return err
+		}
+		resp, err := s.AnswerGetAccountRangeQuery(ctx, &query)
+		if err != nil {
+			s.logger.Debug("[snap] failed to serve account range", "origin", query.Origin, "err", err)
+			resp = &AccountRangePacket{ID: query.ID}
+		}
+		return p2p.Send(w, AccountRangeMsg, resp)
+	case GetStorageRangesMsg:
+		var query GetStorageRangesPacket
+		if err := msg.Decode(&query); err != nil {
+			return err
+		}
+		resp, err := s.AnswerGetStorageRangesQuery(ctx, &query)
+		if err != nil {
+			s.logger.Debug("[snap] failed to serve storage ranges", "accounts", len(query.Accounts), "err", err)
+			resp = &StorageRangesPacket{ID: query.ID}
+		}
+		return p2p.Send(w, StorageRangesMsg, resp)
+	case GetByteCodesMsg:
+		var query GetByteCodesPacket
+		if err := msg.Decode(&query); err != nil {
+			return err
+		}
+		resp, err := s.AnswerGetByteCodesQuery(ctx, &query)
+		if err != nil {
+			s.logger.Debug("[snap] failed to serve bytecodes", "hashes", len(query.Hashes), "err", err)
+			resp = &ByteCodesPacket{ID: query.ID}
+		}
+		return p2p.Send(w, ByteCodesMsg, resp)
+	case GetTrieNodesMsg:
+		var query GetTrieNodesPacket
+		if err := msg.Decode(&query); err != nil {
+			return err
+		}
+		resp, err := s.AnswerGetTrieNodesQuery(ctx, &query)
+		if err != nil {
+			s.logger.Debug("[snap] failed to serve trie nodes", "paths", len(query.Paths), "err", err)
+			resp = &TrieNodesPacket{ID: query.ID}
+		}
+		return p2p.Send(w, TrieNodesMsg, resp)
+	case AccountRangeMsg, StorageRangesMsg, ByteCodesMsg, TrieNodesMsg:
+		// state is never requested from peers over snap
+		msg.Discard()
+		return fmt.Errorf("unsolicited snap response %#x", msg.Code)
+	default:
+		msg.Discard()
+		return fmt.Errorf("invalid snap message code %#x", msg.Code)
+	}
+}
@@ -0,0 +1,687 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package snap
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/erigontech/erigon-lib/commitment"
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/empty"
+	"github.com/erigontech/erigon-lib/common/length"
+	"github.com/erigontech/erigon-lib/crypto"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon-lib/rlp"
+	libstate "github.com/erigontech/erigon-lib/state"
+	"github.com/erigontech/erigon-lib/trie"
+)
+
+var maxHash = common.HexToHash("0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+
+// stateRefreshInterval is how long the shared view of the latest state is kept open, requests
+// arriving later are served from a fresh one.
+const stateRefreshInterval = 4 * time.Second
+
+// Server answers snap requests from the latest state. Accounts and storage slots are
+// iterated in hashed key order by walking the branches of the commitment domain, and
+// range proofs and trie nodes are built from the witness of the touched keys.
+//
+// Building the view of the state and its commitment is costly, so requests share one view
+// and are served one at a time, on top of the rate limit of each peer.
+type Server struct {
+	db     kv.TemporalRoDB
+	logger log.Logger
+
+	mu    sync.Mutex
+	state *stateView // nil until requested, dropped after stateRefreshInterval
+	codes *codeIndex
+}
+
+// NewServer creates the server and starts indexing the code domain by code hash, which
+// lasts until the whole domain is scanned or ctx is cancelled.
+func NewServer(ctx context.Context, db kv.TemporalRoDB, logger log.Logger) *Server {
+	s := &Server{db: db, logger: logger, codes: newCodeIndex()}
+	go func() {
+		if err := s.indexCodes(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			s.logger.Warn("[snap] failed to index bytecodes", "err", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.closeState()
+	}()
+	return s
+}
+
+// responseLimits returns the soft and hard size limits of a response requested with the given limit.
+func responseLimits(requested uint64) (soft, hard uint64) {
+	soft = min(requested, softResponseLimit)
+	return soft, uint64(float64(soft) * (1 + stateLookupSlack))
+}
+
+// stateView is a read-only view on the latest state and its commitment.
+type stateView struct {
+	tx      kv.TemporalTx
+	domains *libstate.SharedDomains
+	root    common.Hash
+}
+
+func (s *Server) openState(ctx context.Context) (*stateView, error) {
+	tx, err := s.db.BeginTemporalRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	domains, err := libstate.NewSharedDomains(tx, s.logger)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	root, err := domains.ComputeCommitment(ctx, false, domains.BlockNum(), "[snap]")
+	if err != nil {
+		domains.Close()
+		tx.Rollback()
+		return nil, err
+	}
+	return &stateView{tx: tx, domains: domains, root: common.BytesToHash(root)}, nil
+}
+
+// withState runs fn on the shared view of the latest state, opening it if needed.
+func (s *Server) withState(ctx context.Context, fn func(state *stateView) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := common.Stopped(ctx.Done()); err != nil {
+		return err
+	}
+	if s.state == nil {
+		state, err := s.openState(ctx)
+		if err != nil {
+			return err
+		}
+		s.state = state
+		time.AfterFunc(stateRefreshInterval, func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.state == state {
+				s.closeState()
+			}
+		})
+		if err := s.codes.update(state); err != nil {
+			s.logger.Debug("[snap] failed to index new bytecodes", "err", err)
+		}
+	}
+	if err := fn(s.state); err != nil {
+		// the commitment may be left halfway through a witness, start over from a fresh view
+		s.closeState()
+		return err
+	}
+	return nil
+}
+
+func (s *Server) closeState() {
+	if s.state != nil {
+		s.state.Close()
+		s.state = nil
+	}
+}
+
+func (v *stateView) Close() {
+	v.domains.Close()
+	v.tx.Rollback()
+}
+
+func (v *stateView) branch(compactPrefix []byte) ([]byte, error) {
+	data, _, err := v.domains.GetCommitmentContext().Branch(compactPrefix)
+	return data, err
+}
+
+func accountHash(key []byte) []byte { return crypto.Keccak256(key) }
+func slotHash(key []byte) []byte    { return crypto.Keccak256(key[length.Addr:]) }
+
+// iterateAccounts visits plain keys of accounts in hashed key order starting from origin,
+// returns the plain key of the account preceding origin.
+func (v *stateView) iterateAccounts(origin []byte, fn func(key []byte) (bool, error)) ([]byte, error) {
+	prev, err := commitment.IterateLeaves(v.branch, nil, origin, fn)
+	if errors.Is(err, commitment.ErrNoBranch) {
+		return v.scanLeaves(kv.AccountsDomain, nil, accountHash, origin, fn)
+	}
+	return prev, err
+}
+
+// iterateStorage visits plain keys of the storage slots of an account in hashed key order
+// starting from origin, returns the plain key of the slot preceding origin.
+func (v *stateView) iterateStorage(addr common.Address, origin []byte, fn func(key []byte) (bool, error)) ([]byte, error) {
+	prev, err := commitment.IterateLeaves(v.branch, commitment.KeyToHexNibbleHash(addr[:]), origin, fn)
+	if errors.Is(err, commitment.ErrNoBranch) {
+		return v.scanLeaves(kv.StorageDomain, addr[:], slotHash, origin, fn)
+	}
+	return prev, err
+}
+
+// scanLeaves is the fallback for tries without a branch node at the root, such tries hold
+// very few keys, so they are read from the plain state and sorted in memory.
+func (v *stateView) scanLeaves(domain kv.Domain, prefix []byte, hash func([]byte) []byte, origin []byte, fn func(key []byte) (bool, error)) ([]byte, error) {
+	var to []byte
+	if len(prefix) > 0 {
+		to, _ = kv.NextSubtree(prefix)
+	}
+	it, err := v.tx.Debug().RangeLatest(domain, prefix, to, -1)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	type leaf struct{ hash, key []byte }
+	var leaves []leaf
+	for it.HasNext() {
+		k, val, err := it.Next()
+		if err != nil {
+			return nil, err
+		}
+		if len(val) == 0 || (domain == kv.StorageDomain && len(k) != length.Addr+length.Hash) {
+			continue
+		}
+		key := common.Copy(k)
+		leaves = append(leaves, leaf{hash: hash(key), key: key})
+	}
+	sort.Slice(leaves, func(i, j int) bool { return bytes.Compare(leaves[i].hash, leaves[j].hash) < 0 })
+
+	var prev []byte
+	for _, l := range leaves {
+		if bytes.Compare(l.hash, origin) < 0 {
+			prev = l.key
+			continue
+		}
+		if ok, err := fn(l.key); err != nil || !ok {
+			return prev, err
+		}
+	}
+	return prev, nil
+}
+
+// resolveAccount finds the address of the account with the given hash.
+func (v *stateView) resolveAccount(hash common.Hash) (addr common.Address, found bool, err error) {
+	_, err = v.iterateAccounts(hash[:], func(key []byte) (bool, error) {
+		if found = bytes.Equal(accountHash(key), hash[:]); found {
+			addr = common.BytesToAddress(key)
+		}
+		return false, nil
+	})
+	return addr, found, err
+}
+
+// proofSet collects unique trie nodes of several proofs.
+type proofSet struct {
+	seen  map[string]struct{}
+	nodes [][]byte
+}
+
+func (p *proofSet) add(nodes [][]byte) {
+	if p.seen == nil {
+		p.seen = make(map[string]struct{})
+	}
+	for _, n := range nodes {
+		if _, ok := p.seen[string(n)]; !ok {
+			p.seen[string(n)] = struct{}{}
+			p.nodes = append(p.nodes, n)
+		}
+	}
+}
+
+// AnswerGetAccountRangeQuery returns the accounts starting from the origin hash, up to
+// the first account at or beyond the limit hash or until the response size limit is
+// reached, with the proofs of the origin and of the last returned account.
+func (s *Server) AnswerGetAccountRangeQuery(ctx context.Context, query *GetAccountRangePacket) (resp *AccountRangePacket, err error) {
+	err = s.withState(ctx, func(state *stateView) error {
+		resp, err = s.accountRange(ctx, state, query)
+		return err
+	})
+	return resp, err
+}
+
+func (s *Server) accountRange(ctx context.Context, state *stateView, query *GetAccountRangePacket) (*AccountRangePacket, error) {
+	resp := &AccountRangePacket{ID: query.ID}
+	if state.root != query.Root {
+		// only the latest state is served, reply empty for unavailable roots
+		return resp, nil
+	}
+	soft, hard := responseLimits(query.Bytes)
+
+	// a typical (externally owned) slim account takes the hash and ~40 bytes of body, collect
+	// keys by that estimate and cut the response once the actual bodies are known
+	const accountSizeEstimate = length.Hash + 40
+	var keys [][]byte
+	prev, err := state.iterateAccounts(query.Origin[:], func(key []byte) (bool, error) {
+		keys = append(keys, common.Copy(key))
+		return bytes.Compare(accountHash(key), query.Limit[:]) < 0 && uint64(len(keys)*accountSizeEstimate) < hard, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 && prev == nil {
+		return resp, nil
+	}
+
+	sdCtx := state.domains.GetCommitmentContext()
+	if prev != nil {
+		sdCtx.TouchKey(kv.AccountsDomain, string(prev), nil)
+	}
+	for _, key := range keys {
+		sdCtx.TouchKey(kv.AccountsDomain, string(key), nil)
+	}
+	proofTrie, _, err := sdCtx.Witness(ctx, state.root[:], "[snap]")
+	if err != nil {
+		return nil, err
+	}
+
+	var size uint64
+	for _, key := range keys {
+		hash := accountHash(key)
+		acc, _ := proofTrie.GetAccount(hash)
+		if acc == nil {
+			continue
+		}
+		body, err := EncodeSlimAccount(acc)
+		if err != nil {
+			return nil, err
+		}
+		if acc.CodeHash != empty.CodeHash {
+			s.codes.add(acc.CodeHash, common.BytesToAddress(key))
+		}
+		resp.Accounts = append(resp.Accounts, &AccountData{Hash: common.BytesToHash(hash), Body: body})
+		if size += uint64(length.Hash + len(body)); size >= soft {
+			break
+		}
+	}
+
+	var proof proofSet
+	for _, hash := range [][]byte{query.Origin[:], lastAccountHash(resp.Accounts)} {
+		if hash == nil {
+			continue
+		}
+		nodes, err := proofTrie.Prove(hash, 0, false)
+		if err != nil {
+			return nil, err
+		}
+		proof.add(nodes)
+	}
+	resp.Proof = proof.nodes
+	return resp, nil
+}
+
+func lastAccountHash(accounts []*AccountData) []byte {
+	if len(accounts) == 0 {
+		return nil
+	}
+	return accounts[len(accounts)-1].Hash[:]
+}
+
+// AnswerGetStorageRangesQuery returns the storage slots of the requested accounts. The
+// origin applies to the first account and the limit to the last one. Proofs are attached
+// only if the last returned range doesn't cover the whole storage of its account.
+func (s *Server) AnswerGetStorageRangesQuery(ctx context.Context, query *GetStorageRangesPacket) (resp *StorageRangesPacket, err error) {
+	err = s.withState(ctx, func(state *stateView) error {
+		resp, err = s.storageRanges(ctx, state, query)
+		return err
+	})
+	return resp, err
+}
+
+func (s *Server) storageRanges(ctx context.Context, state *stateView, query *GetStorageRangesPacket) (*StorageRangesPacket, error) {
+	resp := &StorageRangesPacket{ID: query.ID}
+	if state.root != query.Root {
+		return resp, nil
+	}
+	soft, hard := responseLimits(query.Bytes)
+
+	var size uint64
+	for i, hash := range query.Accounts {
+		if size >= soft {
+			break
+		}
+		var origin, limit common.Hash
+		if i == 0 && len(query.Origin) > 0 {
+			origin = common.BytesToHash(query.Origin)
+		}
+		if i == len(query.Accounts)-1 && len(query.Limit) > 0 {
+			limit = common.BytesToHash(query.Limit)
+		} else {
+			limit = maxHash
+		}
+
+		addr, found, err := state.resolveAccount(hash)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			// unknown accounts can't be proven, stop here like for a pruned state
+			break
+		}
+
+		var (
+			keys  [][]byte
+			slots []*StorageData
+			abort bool
+		)
+		prev, err := state.iterateStorage(addr, origin[:], func(key []byte) (bool, error) {
+			if size >= hard {
+				abort = true
+				return false, nil
+			}
+			val, _, err := state.domains.GetLatest(kv.StorageDomain, key)
+			if err != nil {
+				return false, err
+			}
+			body, err := rlp.EncodeToBytes(val)
+			if err != nil {
+				return false, err
+			}
+			slotKey := slotHash(key)
+			keys = append(keys, common.Copy(key))
+			slots = append(slots, &StorageData{Hash: common.BytesToHash(slotKey), Body: body})
+			size += uint64(length.Hash + len(body))
+			return bytes.Compare(slotKey, limit[:]) < 0, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(slots) > 0 {
+			resp.Slots = append(resp.Slots, slots)
+		}
+		// the range is partial, prove its boundaries and stop
+		if origin != (common.Hash{}) || (abort && len(slots) > 0) {
+			if resp.Proof, err = state.storageProof(ctx, addr, prev, keys, origin[:]); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	return resp, nil
+}
+
+// storageProof proves the origin and the last of the given slots in the storage trie of addr.
+func (v *stateView) storageProof(ctx context.Context, addr common.Address, prev []byte, keys [][]byte, origin []byte) ([][]byte, error) {
+	sdCtx := v.domains.GetCommitmentContext()
+	sdCtx.TouchKey(kv.AccountsDomain, string(addr[:]), nil)
+	if prev != nil {
+		sdCtx.TouchKey(kv.StorageDomain, string(prev), nil)
+	}
+	if len(keys) > 0 {
+		sdCtx.TouchKey(kv.StorageDomain, string(keys[0]), nil)
+		sdCtx.TouchKey(kv.StorageDomain, string(keys[len(keys)-1]), nil)
+	}
+	proofTrie, _, err := sdCtx.Witness(ctx, v.root[:], "[snap]")
+	if err != nil {
+		return nil, err
+	}
+	addrHash := accountHash(addr[:])
+	accountProof, err := proofTrie.Prove(addrHash, 0, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var proof proofSet
+	boundaries := [][]byte{origin}
+	if len(keys) > 0 {
+		boundaries = append(boundaries, slotHash(keys[len(keys)-1]))
+	}
+	for _, hash := range boundaries {
+		nodes, err := proofTrie.Prove(append(common.Copy(addrHash), hash...), len(accountProof), true)
+		if err != nil {
+			return nil, err
+		}
+		proof.add(nodes)
+	}
+	return proof.nodes, nil
+}
+
+// AnswerGetByteCodesQuery returns the requested bytecodes which are known, skipping the rest.
+func (s *Server) AnswerGetByteCodesQuery(ctx context.Context, query *GetByteCodesPacket) (*ByteCodesPacket, error) {
+	resp := &ByteCodesPacket{ID: query.ID}
+	soft, _ := responseLimits(query.Bytes)
+	hashes := query.Hashes
+	if len(hashes) > maxCodeLookups {
+		hashes = hashes[:maxCodeLookups]
+	}
+
+	tx, err := s.db.BeginTemporalRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var size uint64
+	for _, hash := range hashes {
+		if hash == empty.CodeHash {
+			// peers should not request the empty code, but if they do, serve it
+			resp.Codes = append(resp.Codes, []byte{})
+			continue
+		}
+		addr, ok := s.codes.get(hash)
+		if !ok {
+			continue
+		}
+		code, _, err := tx.GetLatest(kv.CodeDomain, addr[:])
+		if err != nil {
+			return nil, err
+		}
+		if len(code) == 0 || crypto.Keccak256Hash(code) != hash {
+			// the account got a different code since
+			s.codes.remove(hash)
+			continue
+		}
+		resp.Codes = append(resp.Codes, code)
+		if size += uint64(len(code)); size >= soft {
+			break
+		}
+	}
+	return resp, nil
+}
+
+// AnswerGetTrieNodesQuery returns the requested trie nodes, an empty node for each path
+// that doesn't lead to a node. The commitment domain keeps branches in its own (hex patricia)
+// format, so the Merkle-Patricia nodes are taken from the witness of a leaf under each path.
+func (s *Server) AnswerGetTrieNodesQuery(ctx context.Context, query *GetTrieNodesPacket) (resp *TrieNodesPacket, err error) {
+	err = s.withState(ctx, func(state *stateView) error {
+		resp, err = s.trieNodes(ctx, state, query)
+		return err
+	})
+	return resp, err
+}
+
+// trieNodeLookup is a requested trie node, found under the leaf key if any.
+type trieNodeLookup struct {
+	addr    common.Address // storage trie owner, zero for account trie nodes
+	storage bool
+	path    []byte // nibbles
+	leaf    []byte // plain key of a leaf under path, nil if there is none
+}
+
+func (s *Server) trieNodes(ctx context.Context, state *stateView, query *GetTrieNodesPacket) (*TrieNodesPacket, error) {
+	resp := &TrieNodesPacket{ID: query.ID}
+	if state.root != query.Root {
+		return resp, nil
+	}
+	soft, _ := responseLimits(query.Bytes)
+
+	var lookups []*trieNodeLookup
+	for _, pathset := range query.Paths {
+		if len(lookups) >= maxTrieNodeLookups || len(pathset) == 0 {
+			break
+		}
+		if len(pathset) == 1 {
+			path := compactToNibbles(pathset[0])
+			leaf, err := state.leafUnder(path, state.iterateAccounts, accountHash)
+			if err != nil {
+				return nil, err
+			}
+			lookups = append(lookups, &trieNodeLookup{path: path, leaf: leaf})
+			continue
+		}
+		addr, found, err := state.resolveAccount(common.BytesToHash(pathset[0]))
+		if err != nil {
+			return nil, err
+		}
+		iterateStorage := func(origin []byte, fn func(key []byte) (bool, error)) ([]byte, error) {
+			return state.iterateStorage(addr, origin, fn)
+		}
+		for _, compact := range pathset[1:] {
+			if len(lookups) >= maxTrieNodeLookups {
+				break
+			}
+			lookup := &trieNodeLookup{addr: addr, storage: true, path: compactToNibbles(compact)}
+			if found {
+				if lookup.leaf, err = state.leafUnder(lookup.path, iterateStorage, slotHash); err != nil {
+					return nil, err
+				}
+			}
+			lookups = append(lookups, lookup)
+		}
+	}
+
+	sdCtx := state.domains.GetCommitmentContext()
+	var touched bool
+	for _, lookup := range lookups {
+		if lookup.leaf == nil {
+			continue
+		}
+		touched = true
+		if lookup.storage {
+			sdCtx.TouchKey(kv.AccountsDomain, string(lookup.addr[:]), nil)
+			sdCtx.TouchKey(kv.StorageDomain, string(lookup.leaf), nil)
+		} else {
+			sdCtx.TouchKey(kv.AccountsDomain, string(lookup.leaf), nil)
+		}
+	}
+	if !touched {
+		resp.Nodes = make([][]byte, len(lookups))
+		return resp, nil
+	}
+	proofTrie, _, err := sdCtx.Witness(ctx, state.root[:], "[snap]")
+	if err != nil {
+		return nil, err
+	}
+
+	var size uint64
+	for _, lookup := range lookups {
+		var node []byte
+		if lookup.leaf != nil {
+			var proof [][]byte
+			if lookup.storage {
+				addrHash := accountHash(lookup.addr[:])
+				accountProof, err := proofTrie.Prove(addrHash, 0, false)
+				if err != nil {
+					return nil, err
+				}
+				if proof, err = proofTrie.Prove(append(addrHash, slotHash(lookup.leaf)...), len(accountProof), true); err != nil {
+					return nil, err
+				}
+			} else if proof, err = proofTrie.Prove(accountHash(lookup.leaf), 0, false); err != nil {
+				return nil, err
+			}
+			if node, err = nodeAtDepth(proof, len(lookup.path)); err != nil {
+				return nil, err
+			}
+		}
+		resp.Nodes = append(resp.Nodes, node)
+		if size += uint64(len(node)); size >= soft {
+			break
+		}
+	}
+	return resp, nil
+}
+
+// leafUnder returns the plain key of the first leaf whose hashed key starts with the nibbles of path.
+func (v *stateView) leafUnder(path []byte, iterate func(origin []byte, fn func(key []byte) (bool, error)) ([]byte, error), hash func([]byte) []byte) ([]byte, error) {
+	if len(path) > 2*length.Hash {
+		return nil, nil
+	}
+	origin := make([]byte, length.Hash)
+	for i, nibble := range path {
+		origin[i/2] |= nibble << (4 * (1 - i%2))
+	}
+	var leaf []byte
+	_, err := iterate(origin, func(key []byte) (bool, error) {
+		if hasNibblePrefix(hash(key), path) {
+			leaf = common.Copy(key)
+		}
+		return false, nil
+	})
+	return leaf, err
+}
+
+func hasNibblePrefix(key, nibbles []byte) bool {
+	for i, nibble := range nibbles {
+		if key[i/2]>>(4*(1-i%2))&0x0f != nibble {
+			return false
+		}
+	}
+	return true
+}
+
+func compactToNibbles(compact []byte) []byte {
+	if len(compact) == 0 {
+		return nil
+	}
+	keybytes := trie.CompactToKeybytes(compact)
+	nibbles := keybytes.ToHex()
+	if len(nibbles) > 0 && nibbles[len(nibbles)-1] == 16 {
+		nibbles = nibbles[:len(nibbles)-1]
+	}
+	return nibbles
+}
+
+// nodeAtDepth returns the node of the proof which starts depth nibbles below the first one,
+// nil if the path ends inside the key of a short node.
+func nodeAtDepth(proof [][]byte, depth int) ([]byte, error) {
+	var at int
+	for _, node := range proof {
+		if at == depth {
+			return node, nil
+		}
+		if at > depth {
+			break
+		}
+		elems, _, err := rlp.SplitList(node)
+		if err != nil {
+			return nil, err
+		}
+		count, err := rlp.CountValues(elems)
+		if err != nil {
+			return nil, err
+		}
+		if count != 2 {
+			// full node
+			at++
+			continue
+		}
+		compact, _, err := rlp.SplitString(elems)
+		if err != nil {
+			return nil, err
+		}
+		keybytes := trie.CompactToKeybytes(compact)
+		at += keybytes.Nibbles()
+	}
+	return nil, nil
+}
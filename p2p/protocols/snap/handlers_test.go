@@ -0,0 +1,209 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package snap_test
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/chain"
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/crypto"
+	"github.com/erigontech/erigon-lib/types"
+	"github.com/erigontech/erigon/p2p"
+	"github.com/erigontech/erigon/p2p/protocols/snap"
+	"github.com/erigontech/erigon/turbo/stages/mock"
+)
+
+var (
+	testKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	contract   = common.HexToAddress("0x00000000000000000000000000000000c0de")
+	code       = common.FromHex("0x6001600055")
+)
+
+func mockSnapServer(t *testing.T) (*mock.MockSentry, *snap.Server, []common.Hash) {
+	alloc := types.GenesisAlloc{
+		crypto.PubkeyToAddress(testKey.PublicKey): {Balance: big.NewInt(1000000)},
+		contract: {Balance: big.NewInt(1), Code: code, Storage: map[common.Hash]common.Hash{
+			common.HexToHash("0x01"): common.HexToHash("0x01"),
+			common.HexToHash("0x02"): common.HexToHash("0x0200"),
+			common.HexToHash("0x03"): common.HexToHash("0x030000"),
+		}},
+	}
+	for i := 0; i < 300; i++ {
+		alloc[common.BigToAddress(big.NewInt(int64(i+1)*7919))] = types.GenesisAccount{Balance: big.NewInt(int64(i + 1))}
+	}
+	m := mock.MockWithGenesis(t, &types.Genesis{Config: chain.TestChainConfig, Alloc: alloc}, testKey, false)
+
+	hashes := make([]common.Hash, 0, len(alloc))
+	for addr := range alloc {
+		hashes = append(hashes, crypto.Keccak256Hash(addr[:]))
+	}
+	sort.Slice(hashes, func(i, j int) bool { return bytes.Compare(hashes[i][:], hashes[j][:]) < 0 })
+	return m, snap.NewServer(m.Ctx, m.DB, m.Log), hashes
+}
+
+func accountHashes(accounts []*snap.AccountData) []common.Hash {
+	hashes := make([]common.Hash, len(accounts))
+	for i, acc := range accounts {
+		hashes[i] = acc.Hash
+	}
+	return hashes
+}
+
+func TestAnswerGetAccountRangeQuery(t *testing.T) {
+	m, server, hashes := mockSnapServer(t)
+	root := m.Genesis.Root()
+	maxHash := common.HexToHash("0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+
+	resp, err := server.AnswerGetAccountRangeQuery(m.Ctx, &snap.GetAccountRangePacket{ID: 1, Root: root, Limit: maxHash, Bytes: 1 << 20})
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), resp.ID)
+	require.Equal(t, hashes, accountHashes(resp.Accounts))
+	require.NotEmpty(t, resp.Proof)
+
+	contractHash := crypto.Keccak256Hash(contract[:])
+	for _, acc := range resp.Accounts {
+		dec, err := snap.DecodeSlimAccount(acc.Body)
+		require.NoError(t, err)
+		if acc.Hash == contractHash {
+			require.Equal(t, crypto.Keccak256Hash(code), dec.CodeHash)
+		}
+	}
+
+	// the range stops at the first account at or beyond the limit
+	resp, err = server.AnswerGetAccountRangeQuery(m.Ctx, &snap.GetAccountRangePacket{ID: 2, Root: root, Origin: hashes[100], Limit: hashes[150], Bytes: 1 << 20})
+	require.NoError(t, err)
+	require.Equal(t, hashes[100:151], accountHashes(resp.Accounts))
+
+	// the response is capped by the requested size
+	resp, err = server.AnswerGetAccountRangeQuery(m.Ctx, &snap.GetAccountRangePacket{ID: 3, Root: root, Limit: maxHash, Bytes: 1000})
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.Accounts)
+	require.Less(t, len(resp.Accounts), len(hashes))
+	require.Equal(t, hashes[:len(resp.Accounts)], accountHashes(resp.Accounts))
+
+	// unknown roots are not served
+	resp, err = server.AnswerGetAccountRangeQuery(m.Ctx, &snap.GetAccountRangePacket{ID: 4, Root: common.HexToHash("0x01"), Limit: maxHash, Bytes: 1 << 20})
+	require.NoError(t, err)
+	require.Empty(t, resp.Accounts)
+	require.Empty(t, resp.Proof)
+}
+
+func TestAnswerGetStorageRangesQuery(t *testing.T) {
+	m, server, _ := mockSnapServer(t)
+	root := m.Genesis.Root()
+
+	resp, err := server.AnswerGetStorageRangesQuery(m.Ctx, &snap.GetStorageRangesPacket{
+		ID:       1,
+		Root:     root,
+		Accounts: []common.Hash{crypto.Keccak256Hash(contract[:])},
+		Bytes:    1 << 20,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Slots, 1)
+	require.Len(t, resp.Slots[0], 3)
+	for i := 1; i < len(resp.Slots[0]); i++ {
+		require.Negative(t, bytes.Compare(resp.Slots[0][i-1].Hash[:], resp.Slots[0][i].Hash[:]))
+	}
+	// the whole storage is returned, no proof is needed
+	require.Empty(t, resp.Proof)
+
+	// a range starting in the middle of the storage is proven
+	resp, err = server.AnswerGetStorageRangesQuery(m.Ctx, &snap.GetStorageRangesPacket{
+		ID:       2,
+		Root:     root,
+		Accounts: []common.Hash{crypto.Keccak256Hash(contract[:])},
+		Origin:   common.HexToHash("0x01").Bytes(),
+		Bytes:    1 << 20,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.Proof)
+}
+
+func TestAnswerGetByteCodesQuery(t *testing.T) {
+	m, server, _ := mockSnapServer(t)
+	codeHash := crypto.Keccak256Hash(code)
+
+	// bytecodes are served by hash once the code domain is indexed
+	require.Eventually(t, func() bool {
+		resp, err := server.AnswerGetByteCodesQuery(m.Ctx, &snap.GetByteCodesPacket{ID: 1, Hashes: []common.Hash{codeHash}, Bytes: 1 << 20})
+		require.NoError(t, err)
+		return len(resp.Codes) == 1
+	}, 10*time.Second, 10*time.Millisecond)
+
+	resp, err := server.AnswerGetByteCodesQuery(m.Ctx, &snap.GetByteCodesPacket{ID: 2, Hashes: []common.Hash{{1}, codeHash}, Bytes: 1 << 20})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), resp.ID)
+	require.Equal(t, [][]byte{code}, resp.Codes)
+}
+
+func TestAnswerGetTrieNodesQuery(t *testing.T) {
+	m, server, hashes := mockSnapServer(t)
+	root := m.Genesis.Root()
+	contractHash := crypto.Keccak256Hash(contract[:])
+
+	resp, err := server.AnswerGetAccountRangeQuery(m.Ctx, &snap.GetAccountRangePacket{ID: 1, Root: root, Origin: contractHash, Limit: contractHash, Bytes: 1 << 20})
+	require.NoError(t, err)
+	require.Equal(t, contractHash, resp.Accounts[0].Hash)
+	contractAccount, err := snap.DecodeSlimAccount(resp.Accounts[0].Body)
+	require.NoError(t, err)
+
+	nodes, err := server.AnswerGetTrieNodesQuery(m.Ctx, &snap.GetTrieNodesPacket{
+		ID:   2,
+		Root: root,
+		Paths: []snap.TrieNodePathSet{
+			{{}},                       // account trie root
+			{{0x10 | hashes[0][0]>>4}}, // the child of the root holding the first account
+			{contractHash[:], {}},      // storage trie root of the contract
+			{{0x00, 0xff, 0xff}},       // below a leaf
+		},
+		Bytes: 1 << 20,
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), nodes.ID)
+	require.Len(t, nodes.Nodes, 4)
+	require.Equal(t, root, crypto.Keccak256Hash(nodes.Nodes[0]))
+	require.True(t, bytes.Contains(nodes.Nodes[0], crypto.Keccak256(nodes.Nodes[1])), "child is referenced by the root")
+	require.Equal(t, contractAccount.Root, crypto.Keccak256Hash(nodes.Nodes[2]))
+	require.Empty(t, nodes.Nodes[3])
+
+	// unknown roots are not served
+	nodes, err = server.AnswerGetTrieNodesQuery(m.Ctx, &snap.GetTrieNodesPacket{ID: 3, Root: common.HexToHash("0x01"), Paths: []snap.TrieNodePathSet{{{}}}, Bytes: 1 << 20})
+	require.NoError(t, err)
+	require.Empty(t, nodes.Nodes)
+}
+
+func TestHandleUnsolicitedResponse(t *testing.T) {
+	m, server, _ := mockSnapServer(t)
+	peer, local := p2p.MsgPipe()
+	defer peer.Close()
+
+	errc := make(chan error, 1)
+	go func() { errc <- server.Handle(m.Ctx, local) }()
+
+	require.NoError(t, p2p.Send(peer, snap.GetTrieNodesMsg, &snap.GetTrieNodesPacket{ID: 1, Root: m.Genesis.Root(), Bytes: 1 << 20}))
+	require.NoError(t, p2p.ExpectMsg(peer, snap.TrieNodesMsg, &snap.TrieNodesPacket{ID: 1}))
+
+	require.NoError(t, p2p.Send(peer, snap.AccountRangeMsg, &snap.AccountRangePacket{ID: 2}))
+	require.ErrorContains(t, <-errc, "unsolicited")
+}
@@ -0,0 +1,185 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+// Package snap implements the serving side of the `snap` state synchronisation
+// protocol (https://github.com/ethereum/devp2p/blob/master/caps/snap.md).
+package snap
+
+import (
+	"github.com/holiman/uint256"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/empty"
+	"github.com/erigontech/erigon-lib/rlp"
+	"github.com/erigontech/erigon-lib/types/accounts"
+)
+
+// ProtocolName is the official short name of the `snap` protocol used during
+// devp2p capability negotiation.
+const ProtocolName = "snap"
+
+// SNAP1 is the only version of the `snap` protocol.
+const SNAP1 = 1
+
+// ProtocolLength is the number of implemented message codes of the `snap/1` protocol.
+const ProtocolLength = 8
+
+// ProtocolMaxMsgSize is the maximum cap on the size of a protocol message.
+const ProtocolMaxMsgSize = 10 * 1024 * 1024
+
+const (
+	GetAccountRangeMsg  = 0x00
+	AccountRangeMsg     = 0x01
+	GetStorageRangesMsg = 0x02
+	StorageRangesMsg    = 0x03
+	GetByteCodesMsg     = 0x04
+	ByteCodesMsg        = 0x05
+	GetTrieNodesMsg     = 0x06
+	TrieNodesMsg        = 0x07
+)
+
+const (
+	// softResponseLimit is the target maximum size of replies to data retrievals,
+	// requesters may ask for less but never for more.
+	softResponseLimit = 2 * 1024 * 1024
+
+	// maxCodeLookups is the maximum number of bytecodes to serve in one request.
+	maxCodeLookups = 1024
+
+	// maxTrieNodeLookups is the maximum number of state trie nodes to serve in one request.
+	maxTrieNodeLookups = 1024
+
+	// stateLookupSlack is the share of the response size limit allowed to be
+	// exceeded by the last item, so that items are not split at the boundary.
+	stateLookupSlack = 0.1
+)
+
+// GetAccountRangePacket represents an account query.
+type GetAccountRangePacket struct {
+	ID     uint64      // Request ID to match up responses with
+	Root   common.Hash // Root hash of the account trie to serve
+	Origin common.Hash // Hash of the first account to retrieve
+	Limit  common.Hash // Hash of the last account to retrieve
+	Bytes  uint64      // Soft limit at which to stop returning data
+}
+
+// AccountRangePacket represents an account query response.
+type AccountRangePacket struct {
+	ID       uint64         // ID of the request this is a response for
+	Accounts []*AccountData // List of consecutive accounts from the trie
+	Proof    [][]byte       // List of trie nodes proving the account range
+}
+
+// AccountData represents a single account in a query response.
+type AccountData struct {
+	Hash common.Hash  // Hash of the account
+	Body rlp.RawValue // Account body in slim format
+}
+
+// GetStorageRangesPacket represents a storage slot query.
+type GetStorageRangesPacket struct {
+	ID       uint64        // Request ID to match up responses with
+	Root     common.Hash   // Root hash of the account trie to serve
+	Accounts []common.Hash // Account hashes of the storage tries to serve
+	Origin   []byte        // Hash of the first storage slot to retrieve (large contract mode)
+	Limit    []byte        // Hash of the last storage slot to retrieve (large contract mode)
+	Bytes    uint64        // Soft limit at which to stop returning data
+}
+
+// StorageRangesPacket represents a storage slot query response.
+type StorageRangesPacket struct {
+	ID    uint64           // ID of the request this is a response for
+	Slots [][]*StorageData // Lists of consecutive storage slots for the requested accounts
+	Proof [][]byte         // Merkle proofs for the *last* slot range, if it's incomplete
+}
+
+// StorageData represents a single storage slot in a query response.
+type StorageData struct {
+	Hash common.Hash // Hash of the storage slot
+	Body []byte      // Data content of the slot
+}
+
+// GetByteCodesPacket represents a contract bytecode query.
+type GetByteCodesPacket struct {
+	ID     uint64        // Request ID to match up responses with
+	Hashes []common.Hash // Code hashes to retrieve the code for
+	Bytes  uint64        // Soft limit at which to stop returning data
+}
+
+// ByteCodesPacket represents a contract bytecode query response.
+type ByteCodesPacket struct {
+	ID    uint64   // ID of the request this is a response for
+	Codes [][]byte // Requested contract bytecodes
+}
+
+// GetTrieNodesPacket represents a state trie node query.
+type GetTrieNodesPacket struct {
+	ID    uint64            // Request ID to match up responses with
+	Root  common.Hash       // Root hash of the account trie to serve
+	Paths []TrieNodePathSet // Trie node hashes to retrieve the nodes for
+	Bytes uint64            // Soft limit at which to stop returning data
+}
+
+// TrieNodePathSet is a list of trie node paths to retrieve. A single element is
+// the compact path of an account trie node, otherwise the first element is the
+// account hash and the rest are the compact paths of its storage trie nodes.
+type TrieNodePathSet [][]byte
+
+// TrieNodesPacket represents a state trie node query response.
+type TrieNodesPacket struct {
+	ID    uint64   // ID of the request this is a response for
+	Nodes [][]byte // Requested state trie nodes
+}
+
+// slimAccount is the consensus account encoding with the empty storage root and
+// the empty code hash replaced by empty byte slices.
+type slimAccount struct {
+	Nonce    uint64
+	Balance  *uint256.Int
+	Root     []byte
+	CodeHash []byte
+}
+
+// EncodeSlimAccount encodes the account in the slim format used by the protocol.
+func EncodeSlimAccount(acc *accounts.Account) ([]byte, error) {
+	slim := slimAccount{Nonce: acc.Nonce, Balance: &acc.Balance}
+	if acc.Root != empty.RootHash {
+		slim.Root = acc.Root[:]
+	}
+	if acc.CodeHash != empty.CodeHash {
+		slim.CodeHash = acc.CodeHash[:]
+	}
+	return rlp.EncodeToBytes(&slim)
+}
+
+// DecodeSlimAccount decodes an account in the slim format used by the protocol.
+func DecodeSlimAccount(data []byte) (*accounts.Account, error) {
+	var slim slimAccount
+	if err := rlp.DecodeBytes(data, &slim); err != nil {
+		return nil, err
+	}
+	acc := &accounts.Account{Nonce: slim.Nonce, Root: empty.RootHash, CodeHash: empty.CodeHash}
+	if slim.Balance != nil {
+		acc.Balance = *slim.Balance
+	}
+	if len(slim.Root) > 0 {
+		acc.Root = common.BytesToHash(slim.Root)
+	}
+	if len(slim.CodeHash) > 0 {
+		acc.CodeHash = common.BytesToHash(slim.CodeHash)
+	}
+	return acc, nil
+}
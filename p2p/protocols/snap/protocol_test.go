@@ -0,0 +1,74 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package snap
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/empty"
+	"github.com/erigontech/erigon-lib/rlp"
+	"github.com/erigontech/erigon-lib/types/accounts"
+)
+
+func TestSlimAccountEncodeDecode(t *testing.T) {
+	for _, acc := range []*accounts.Account{
+		{Nonce: 0, Balance: *uint256.NewInt(0), Root: empty.RootHash, CodeHash: empty.CodeHash},
+		{Nonce: 7, Balance: *uint256.NewInt(1e18), Root: common.HexToHash("0x01"), CodeHash: common.HexToHash("0x02")},
+	} {
+		enc, err := EncodeSlimAccount(acc)
+		require.NoError(t, err)
+		dec, err := DecodeSlimAccount(enc)
+		require.NoError(t, err)
+		require.Equal(t, acc.Nonce, dec.Nonce)
+		require.Equal(t, acc.Balance, dec.Balance)
+		require.Equal(t, acc.Root, dec.Root)
+		require.Equal(t, acc.CodeHash, dec.CodeHash)
+	}
+
+	// empty root and code hash are encoded as empty strings
+	enc, err := EncodeSlimAccount(&accounts.Account{Nonce: 1, Root: empty.RootHash, CodeHash: empty.CodeHash})
+	require.NoError(t, err)
+	require.Equal(t, common.FromHex("0xc401808080"), enc)
+}
+
+func TestPacketsEncodeDecode(t *testing.T) {
+	body, err := EncodeSlimAccount(&accounts.Account{Nonce: 1, Root: empty.RootHash, CodeHash: empty.CodeHash})
+	require.NoError(t, err)
+
+	packets := []interface{}{
+		&GetAccountRangePacket{ID: 1, Root: common.HexToHash("0x01"), Origin: common.HexToHash("0x02"), Limit: common.HexToHash("0x03"), Bytes: 512},
+		&AccountRangePacket{ID: 2, Accounts: []*AccountData{{Hash: common.HexToHash("0x04"), Body: body}}, Proof: [][]byte{{0xc0}}},
+		&GetStorageRangesPacket{ID: 3, Root: common.HexToHash("0x01"), Accounts: []common.Hash{common.HexToHash("0x05")}, Origin: []byte{0x06}, Limit: []byte{0x07}, Bytes: 512},
+		&StorageRangesPacket{ID: 4, Slots: [][]*StorageData{{{Hash: common.HexToHash("0x08"), Body: []byte{0x09}}}}, Proof: [][]byte{{0xc0}}},
+		&GetByteCodesPacket{ID: 5, Hashes: []common.Hash{common.HexToHash("0x0a")}, Bytes: 512},
+		&ByteCodesPacket{ID: 6, Codes: [][]byte{{0x60, 0x00}}},
+		&GetTrieNodesPacket{ID: 7, Root: common.HexToHash("0x01"), Paths: []TrieNodePathSet{{{0x00}, {0x01, 0x02}}}, Bytes: 512},
+		&TrieNodesPacket{ID: 8, Nodes: [][]byte{{0xc0}}},
+	}
+	for _, packet := range packets {
+		enc, err := rlp.EncodeToBytes(packet)
+		require.NoError(t, err)
+		dec := reflect.New(reflect.TypeOf(packet).Elem()).Interface()
+		require.NoError(t, rlp.DecodeBytes(enc, dec))
+		require.Equal(t, packet, dec)
+	}
+}
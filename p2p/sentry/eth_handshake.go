@@ -19,6 +19,7 @@ package sentry
 import (
 	"fmt"
 
+	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/gointerfaces"
 	proto_sentry "github.com/erigontech/erigon-lib/gointerfaces/sentryproto"
 	"github.com/erigontech/erigon/p2p"
@@ -37,7 +38,7 @@ func readAndValidatePeerStatusMessage(
 		return nil, p2p.NewPeerError(p2p.PeerErrorStatusReceive, p2p.DiscNetworkError, err, "readAndValidatePeerStatusMessage rw.ReadMsg error")
 	}
 
-	reply, err := tryDecodeStatusMessage(&msg)
+	reply, err := tryDecodeStatusMessage[eth.StatusPacket](&msg)
 	msg.Discard()
 	if err != nil {
 		return nil, p2p.NewPeerError(p2p.PeerErrorStatusDecode, p2p.DiscProtocolError, err, "readAndValidatePeerStatusMessage tryDecodeStatusMessage error")
@@ -51,7 +52,32 @@ func readAndValidatePeerStatusMessage(
 	return reply, nil
 }
 
-func tryDecodeStatusMessage(msg *p2p.Msg) (*eth.StatusPacket, error) {
+func readAndValidatePeerStatus69Message(
+	rw p2p.MsgReadWriter,
+	status *proto_sentry.StatusData,
+	version uint,
+	minVersion uint,
+) (*eth.StatusPacket69, *p2p.PeerError) {
+	msg, err := rw.ReadMsg()
+	if err != nil {
+		return nil, p2p.NewPeerError(p2p.PeerErrorStatusReceive, p2p.DiscNetworkError, err, "readAndValidatePeerStatus69Message rw.ReadMsg error")
+	}
+
+	reply, err := tryDecodeStatusMessage[eth.StatusPacket69](&msg)
+	msg.Discard()
+	if err != nil {
+		return nil, p2p.NewPeerError(p2p.PeerErrorStatusDecode, p2p.DiscProtocolError, err, "readAndValidatePeerStatus69Message tryDecodeStatusMessage error")
+	}
+
+	err = checkPeerStatus69Compatibility(reply, status, version, minVersion)
+	if err != nil {
+		return nil, p2p.NewPeerError(p2p.PeerErrorStatusIncompatible, p2p.DiscUselessPeer, err, "readAndValidatePeerStatus69Message checkPeerStatus69Compatibility error")
+	}
+
+	return reply, nil
+}
+
+func tryDecodeStatusMessage[T eth.StatusPacket | eth.StatusPacket69](msg *p2p.Msg) (*T, error) {
 	if msg.Code != eth.StatusMsg {
 		return nil, fmt.Errorf("first msg has code %x (!= %x)", msg.Code, eth.StatusMsg)
 	}
@@ -60,7 +86,7 @@ func tryDecodeStatusMessage(msg *p2p.Msg) (*eth.StatusPacket, error) {
 		return nil, fmt.Errorf("message is too large %d, limit %d", msg.Size, eth.ProtocolMaxMsgSize)
 	}
 
-	var reply eth.StatusPacket
+	var reply T
 	if err := msg.Decode(&reply); err != nil {
 		return nil, fmt.Errorf("decode message %v: %w", msg, err)
 	}
@@ -73,24 +99,53 @@ func checkPeerStatusCompatibility(
 	status *proto_sentry.StatusData,
 	version uint,
 	minVersion uint,
+) error {
+	return checkStatusCompatibility(reply.ProtocolVersion, reply.NetworkID, reply.Genesis, reply.ForkID, status, version, minVersion)
+}
+
+func checkPeerStatus69Compatibility(
+	reply *eth.StatusPacket69,
+	status *proto_sentry.StatusData,
+	version uint,
+	minVersion uint,
+) error {
+	if err := checkStatusCompatibility(reply.ProtocolVersion, reply.NetworkID, reply.Genesis, reply.ForkID, status, version, minVersion); err != nil {
+		return err
+	}
+	blockRange := eth.BlockRangeUpdatePacket{
+		EarliestBlock:   reply.EarliestBlock,
+		LatestBlock:     reply.LatestBlock,
+		LatestBlockHash: reply.LatestBlockHash,
+	}
+	return blockRange.Validate()
+}
+
+func checkStatusCompatibility(
+	protocolVersion uint32,
+	replyNetworkID uint64,
+	replyGenesis common.Hash,
+	replyForkID forkid.ID,
+	status *proto_sentry.StatusData,
+	version uint,
+	minVersion uint,
 ) error {
 	networkID := status.NetworkId
-	if reply.NetworkID != networkID {
-		return fmt.Errorf("network id does not match: theirs %d, ours %d", reply.NetworkID, networkID)
+	if replyNetworkID != networkID {
+		return fmt.Errorf("network id does not match: theirs %d, ours %d", replyNetworkID, networkID)
 	}
 
-	if uint(reply.ProtocolVersion) > version {
-		return fmt.Errorf("version is more than what this senty supports: theirs %d, max %d", reply.ProtocolVersion, version)
+	if uint(protocolVersion) > version {
+		return fmt.Errorf("version is more than what this senty supports: theirs %d, max %d", protocolVersion, version)
 	}
-	if uint(reply.ProtocolVersion) < minVersion {
-		return fmt.Errorf("version is less than allowed minimum: theirs %d, min %d", reply.ProtocolVersion, minVersion)
+	if uint(protocolVersion) < minVersion {
+		return fmt.Errorf("version is less than allowed minimum: theirs %d, min %d", protocolVersion, minVersion)
 	}
 
 	genesisHash := gointerfaces.ConvertH256ToHash(status.ForkData.Genesis)
-	if reply.Genesis != genesisHash {
-		return fmt.Errorf("genesis hash does not match: theirs %x, ours %x", reply.Genesis, genesisHash)
+	if replyGenesis != genesisHash {
+		return fmt.Errorf("genesis hash does not match: theirs %x, ours %x", replyGenesis, genesisHash)
 	}
 
 	forkFilter := forkid.NewFilterFromForks(status.ForkData.HeightForks, status.ForkData.TimeForks, genesisHash, status.MaxBlockHeight, status.MaxBlockTime)
-	return forkFilter(reply.ForkID)
+	return forkFilter(replyForkID)
 }
@@ -28,6 +28,7 @@ import (
 	"math"
 	"math/rand"
 	"net"
+	"slices"
 	"sort"
 	"sync"
 	"sync/atomic"
@@ -50,6 +51,7 @@ import (
 	"github.com/erigontech/erigon-lib/gointerfaces/grpcutil"
 	proto_sentry "github.com/erigontech/erigon-lib/gointerfaces/sentryproto"
 	proto_types "github.com/erigontech/erigon-lib/gointerfaces/typesproto"
+	"github.com/erigontech/erigon-lib/kv"
 	"github.com/erigontech/erigon-lib/log/v3"
 	"github.com/erigontech/erigon-lib/rlp"
 	"github.com/erigontech/erigon/cmd/utils"
@@ -58,6 +60,7 @@ import (
 	"github.com/erigontech/erigon/p2p/enode"
 	"github.com/erigontech/erigon/p2p/forkid"
 	"github.com/erigontech/erigon/p2p/protocols/eth"
+	"github.com/erigontech/erigon/p2p/protocols/snap"
 	"github.com/erigontech/erigon/params"
 )
 
@@ -66,6 +69,9 @@ const (
 	// complete before dropping the connection.= as malicious.
 	handshakeTimeout  = 5 * time.Second
 	maxPermitsPerPeer = 4 // How many outstanding requests per peer we may have
+	// blockRangeUpdateInterval is how many new blocks are needed before eth/69 peers
+	// are told about our new block range.
+	blockRangeUpdateInterval = 32
 )
 
 // PeerInfo collects various extra bits of information about the peer,
@@ -76,6 +82,7 @@ type PeerInfo struct {
 	deadlines     []time.Time // Request deadlines
 	latestDealine time.Time
 	height        uint64
+	earliestBlock uint64 // earliest block the peer serves, announced over eth/69
 	rw            p2p.MsgReadWriter
 	protocol      uint
 
@@ -196,6 +203,16 @@ func (pi *PeerInfo) SetIncreasedHeight(newHeight uint64) {
 	}
 }
 
+func (pi *PeerInfo) EarliestBlock() uint64 {
+	return atomic.LoadUint64(&pi.earliestBlock)
+}
+
+// SetBlockRange records the range of blocks announced by an eth/69 peer
+func (pi *PeerInfo) SetBlockRange(earliest, latest uint64) {
+	atomic.StoreUint64(&pi.earliestBlock, earliest)
+	pi.SetIncreasedHeight(latest)
+}
+
 // ClearDeadlines goes through the deadlines of
 // given peers and removes the ones that have passed
 // Optionally, it also clears one extra deadline - this is used when response is received
@@ -295,32 +312,53 @@ func makeP2PServer(
 	return &p2p.Server{Config: p2pConfig}, nil
 }
 
+// peerStatus is what the sentry keeps from the peer's eth Status message
+type peerStatus struct {
+	head          common.Hash
+	earliestBlock uint64 // eth/69 only
+	latestBlock   uint64 // eth/69 only
+}
+
 func handShake(
 	ctx context.Context,
 	status *proto_sentry.StatusData,
 	rw p2p.MsgReadWriter,
 	version uint,
 	minVersion uint,
-) (*common.Hash, *p2p.PeerError) {
+) (*peerStatus, *p2p.PeerError) {
 	// Send out own handshake in a new thread
 	errChan := make(chan *p2p.PeerError, 2)
-	resultChan := make(chan *eth.StatusPacket, 1)
+	resultChan := make(chan *peerStatus, 1)
 
 	ourTD := gointerfaces.ConvertH256ToUint256Int(status.TotalDifficulty)
 	// Convert proto status data into the one required by devp2p
 	genesisHash := gointerfaces.ConvertH256ToHash(status.ForkData.Genesis)
+	forkID := forkid.NewIDFromForks(status.ForkData.HeightForks, status.ForkData.TimeForks, genesisHash, status.MaxBlockHeight, status.MaxBlockTime)
 
 	go func() {
 		defer debug.LogPanic()
-		status := &eth.StatusPacket{
-			ProtocolVersion: uint32(version),
-			NetworkID:       status.NetworkId,
-			TD:              ourTD.ToBig(),
-			Head:            gointerfaces.ConvertH256ToHash(status.BestHash),
-			Genesis:         genesisHash,
-			ForkID:          forkid.NewIDFromForks(status.ForkData.HeightForks, status.ForkData.TimeForks, genesisHash, status.MaxBlockHeight, status.MaxBlockTime),
+		var packet eth.Packet
+		if version >= direct.ETH69 {
+			packet = &eth.StatusPacket69{
+				ProtocolVersion: uint32(version),
+				NetworkID:       status.NetworkId,
+				Genesis:         genesisHash,
+				ForkID:          forkID,
+				EarliestBlock:   min(status.MinimumBlockHeight, status.MaxBlockHeight),
+				LatestBlock:     status.MaxBlockHeight,
+				LatestBlockHash: gointerfaces.ConvertH256ToHash(status.BestHash),
+			}
+		} else {
+			packet = &eth.StatusPacket{
+				ProtocolVersion: uint32(version),
+				NetworkID:       status.NetworkId,
+				TD:              ourTD.ToBig(),
+				Head:            gointerfaces.ConvertH256ToHash(status.BestHash),
+				Genesis:         genesisHash,
+				ForkID:          forkID,
+			}
 		}
-		err := p2p.Send(rw, eth.StatusMsg, status)
+		err := p2p.Send(rw, eth.StatusMsg, packet)
 
 		if err == nil {
 			errChan <- nil
@@ -331,10 +369,22 @@ func handShake(
 
 	go func() {
 		defer debug.LogPanic()
-		status, err := readAndValidatePeerStatusMessage(rw, status, version, minVersion)
+		var result *peerStatus
+		var err *p2p.PeerError
+		if version >= direct.ETH69 {
+			var reply *eth.StatusPacket69
+			if reply, err = readAndValidatePeerStatus69Message(rw, status, version, minVersion); err == nil {
+				result = &peerStatus{head: reply.LatestBlockHash, earliestBlock: reply.EarliestBlock, latestBlock: reply.LatestBlock}
+			}
+		} else {
+			var reply *eth.StatusPacket
+			if reply, err = readAndValidatePeerStatusMessage(rw, status, version, minVersion); err == nil {
+				result = &peerStatus{head: reply.Head}
+			}
+		}
 
 		if err == nil {
-			resultChan <- status
+			resultChan <- result
 			errChan <- nil
 		} else {
 			errChan <- err
@@ -356,8 +406,7 @@ func handShake(
 		}
 	}
 
-	peerStatus := <-resultChan
-	return &peerStatus.Head, nil
+	return <-resultChan, nil
 }
 
 func runPeer(
@@ -474,6 +523,10 @@ func runPeer(
 			send(eth.ToProto[protocol][msg.Code], peerID, b)
 			//log.Info(fmt.Sprintf("[%s] ReceiptsMsg", peerID))
 		case eth.NewBlockHashesMsg:
+			if protocol >= direct.ETH69 {
+				msg.Discard()
+				return p2p.NewPeerError(p2p.PeerErrorInvalidMessage, p2p.DiscProtocolError, nil, "sentry.runPeer: NewBlockHashes after eth/68")
+			}
 			if !hasSubscribers(eth.ToProto[protocol][msg.Code]) {
 				continue
 			}
@@ -484,6 +537,10 @@ func runPeer(
 			//log.Debug("NewBlockHashesMsg from", "peerId", fmt.Sprintf("%x", peerID)[:20], "name", peerInfo.peer.Name())
 			send(eth.ToProto[protocol][msg.Code], peerID, b)
 		case eth.NewBlockMsg:
+			if protocol >= direct.ETH69 {
+				msg.Discard()
+				return p2p.NewPeerError(p2p.PeerErrorInvalidMessage, p2p.DiscProtocolError, nil, "sentry.runPeer: NewBlock after eth/68")
+			}
 			if !hasSubscribers(eth.ToProto[protocol][msg.Code]) {
 				continue
 			}
@@ -533,6 +590,29 @@ func runPeer(
 				logger.Error(fmt.Sprintf("%s: reading msg into bytes: %v", peerID, err))
 			}
 			send(eth.ToProto[protocol][msg.Code], peerID, b)
+		case eth.BlockRangeUpdateMsg:
+			if protocol < direct.ETH69 {
+				msg.Discard()
+				return p2p.NewPeerError(p2p.PeerErrorInvalidMessage, p2p.DiscProtocolError, nil, "sentry.runPeer: BlockRangeUpdate before eth/69")
+			}
+			b := make([]byte, msg.Size)
+			if _, err := io.ReadFull(msg.Payload, b); err != nil {
+				msg.Discard()
+				return p2p.NewPeerError(p2p.PeerErrorMessageReceive, p2p.DiscNetworkError, err, "sentry.runPeer: BlockRangeUpdate read error")
+			}
+			var blockRange eth.BlockRangeUpdatePacket
+			if err := rlp.DecodeBytes(b, &blockRange); err != nil {
+				msg.Discard()
+				return p2p.NewPeerError(p2p.PeerErrorInvalidMessage, p2p.DiscProtocolError, err, "sentry.runPeer: BlockRangeUpdate decode error")
+			}
+			if err := blockRange.Validate(); err != nil {
+				msg.Discard()
+				return p2p.NewPeerError(p2p.PeerErrorInvalidMessage, p2p.DiscProtocolError, err, "sentry.runPeer: invalid BlockRangeUpdate")
+			}
+			peerInfo.SetBlockRange(blockRange.EarliestBlock, blockRange.LatestBlock)
+			if hasSubscribers(eth.ToProto[protocol][msg.Code]) {
+				send(eth.ToProto[protocol][msg.Code], peerID, b)
+			}
 		case 11:
 			// Ignore
 			// TODO: Investigate why BSC peers for eth/67 send these messages
@@ -614,10 +694,21 @@ func NewGrpcServer(ctx context.Context, dialCandidates func() enode.Iterator, re
 		disc, _ = setupDiscovery(ss.p2p.DiscoveryDNS)
 	}
 
-	ss.Protocols = append(ss.Protocols, p2p.Protocol{
+	for i, version := range eth.ProtocolVersions(protocol) {
+		if i > 0 {
+			disc = nil // discovery is shared by all versions, feed it to the dialer only once
+		}
+		ss.Protocols = append(ss.Protocols, ss.protocol(ctx, version, disc, readNodeInfo, logger))
+	}
+
+	return ss
+}
+
+func (ss *GrpcServer) protocol(ctx context.Context, version uint, disc enode.Iterator, readNodeInfo func() *eth.NodeInfo, logger log.Logger) p2p.Protocol {
+	return p2p.Protocol{
 		Name:           eth.ProtocolName,
-		Version:        protocol,
-		Length:         17,
+		Version:        version,
+		Length:         eth.ProtocolLengths[version],
 		DialCandidates: disc,
 		Run: func(peer *p2p.Peer, rw p2p.MsgReadWriter) *p2p.PeerError {
 			peerID := peer.Pubkey()
@@ -628,7 +719,7 @@ func NewGrpcServer(ctx context.Context, dialCandidates func() enode.Iterator, re
 			logger.Trace("[p2p] start with peer", "peerId", printablePeerID)
 
 			peerInfo := NewPeerInfo(peer, rw)
-			peerInfo.protocol = protocol
+			peerInfo.protocol = version
 			defer peerInfo.Close()
 
 			defer ss.GoodPeers.Delete(peerID)
@@ -639,23 +730,26 @@ func NewGrpcServer(ctx context.Context, dialCandidates func() enode.Iterator, re
 				return p2p.NewPeerError(p2p.PeerErrorLocalStatusNeeded, p2p.DiscProtocolError, nil, "could not get status message from core")
 			}
 
-			peerBestHash, err := handShake(ctx, status, rw, protocol, protocol)
+			peerStatus, err := handShake(ctx, status, rw, version, version)
 			if err != nil {
 				return err
 			}
 
 			// handshake is successful
+			if version >= direct.ETH69 {
+				peerInfo.SetBlockRange(peerStatus.earliestBlock, peerStatus.latestBlock)
+			}
 			logger.Trace("[p2p] Received status message OK", "peerId", printablePeerID, "name", peer.Name())
 
 			ss.GoodPeers.Store(peerID, peerInfo)
 			ss.sendNewPeerToClients(gointerfaces.ConvertHashToH512(peerID))
 			defer ss.sendGonePeerToClients(gointerfaces.ConvertHashToH512(peerID))
-			getBlockHeadersErr := ss.getBlockHeaders(ctx, *peerBestHash, peerID)
+			getBlockHeadersErr := ss.getBlockHeaders(ctx, peerStatus.head, peerID)
 			if getBlockHeadersErr != nil {
 				return p2p.NewPeerError(p2p.PeerErrorFirstMessageSend, p2p.DiscNetworkError, getBlockHeadersErr, "p2p.Protocol.Run getBlockHeaders failure")
 			}
 
-			cap := p2p.Cap{Name: eth.ProtocolName, Version: protocol}
+			cap := p2p.Cap{Name: eth.ProtocolName, Version: version}
 
 			return runPeer(
 				ctx,
@@ -676,9 +770,39 @@ func NewGrpcServer(ctx context.Context, dialCandidates func() enode.Iterator, re
 			return nil
 		},
 		//Attributes: []enr.Entry{eth.CurrentENREntry(chainConfig, genesisHash, headHeight)},
-	})
+	}
+}
 
-	return ss
+// EnableSnap makes the sentry serve the `snap/1` protocol next to `eth`, answering from
+// the latest state in db. Must be called before the p2p server is started.
+func (ss *GrpcServer) EnableSnap(db kv.TemporalRoDB) {
+	ss.snap = snap.NewServer(ss.ctx, db, ss.logger)
+}
+
+func (ss *GrpcServer) snapProtocol() p2p.Protocol {
+	return p2p.Protocol{
+		Name:    snap.ProtocolName,
+		Version: snap.SNAP1,
+		Length:  snap.ProtocolLength,
+		Run: func(peer *p2p.Peer, rw p2p.MsgReadWriter) *p2p.PeerError {
+			err := ss.snap.Handle(ss.ctx, rw)
+			var peerErr *p2p.PeerError
+			switch {
+			case errors.As(err, &peerErr):
+				return peerErr
+			case errors.Is(err, context.Canceled):
+				return p2p.NewPeerError(p2p.PeerErrorDiscReason, p2p.DiscQuitting, err, "sentry.snap: context stopped")
+			default:
+				return p2p.NewPeerError(p2p.PeerErrorInvalidMessage, p2p.DiscSubprotocolError, err, "sentry.snap: serving failed")
+			}
+		},
+		NodeInfo: func() interface{} {
+			return nil
+		},
+		PeerInfo: func(peerID [64]byte) interface{} {
+			return nil
+		},
+	}
 }
 
 // Sentry creates and runs standalone sentry
@@ -716,11 +840,13 @@ type GrpcServer struct {
 	p2pServerLock        sync.RWMutex
 	statusData           *proto_sentry.StatusData
 	statusDataLock       sync.RWMutex
+	lastBlockRange       *eth.BlockRangeUpdatePacket // last block range announced to eth/69 peers
 	messageStreams       map[proto_sentry.MessageId]map[uint64]chan *proto_sentry.InboundMessage
 	messagesSubscriberID uint64
 	messageStreamsLock   sync.RWMutex
 	peersStreams         *PeersStreams
 	p2p                  *p2p.Config
+	snap                 *snap.Server // serves snap/1 if enabled
 	logger               log.Logger
 }
 
@@ -857,7 +983,8 @@ func (ss *GrpcServer) findPeerByMinBlock(minBlock uint64) (*PeerInfo, bool) {
 	var maxPermits int
 	now := time.Now()
 	ss.rangePeers(func(peerInfo *PeerInfo) bool {
-		if peerInfo.Height() >= minBlock {
+		// eth/69 peers announce the earliest block they serve, don't ask pruned peers for older history
+		if peerInfo.Height() >= minBlock && peerInfo.EarliestBlock() <= minBlock {
 			deadlines := peerInfo.ClearDeadlines(now, false /* givePermit */)
 			//fmt.Printf("%d deadlines for peer %s\n", deadlines, peerID)
 			if deadlines < maxPermitsPerPeer {
@@ -999,6 +1126,8 @@ func (ss *GrpcServer) HandShake(context.Context, *emptypb.Empty) (*proto_sentry.
 		reply.Protocol = proto_sentry.Protocol_ETH67
 	case direct.ETH68:
 		reply.Protocol = proto_sentry.Protocol_ETH68
+	case direct.ETH69:
+		reply.Protocol = proto_sentry.Protocol_ETH69
 	}
 	return reply, nil
 }
@@ -1020,7 +1149,11 @@ func (ss *GrpcServer) startP2PServer(genesisHash common.Hash) (*p2p.Server, erro
 		}
 	}
 
-	srv, err := makeP2PServer(*ss.p2p, genesisHash, ss.Protocols)
+	protocols := ss.Protocols
+	if ss.snap != nil {
+		protocols = append(slices.Clip(protocols), ss.snapProtocol())
+	}
+	srv, err := makeP2PServer(*ss.p2p, genesisHash, protocols)
 	if err != nil {
 		return nil, err
 	}
@@ -1061,10 +1194,46 @@ func (ss *GrpcServer) SetStatus(ctx context.Context, statusData *proto_sentry.St
 	if ss.statusData == nil || statusData.MaxBlockHeight != 0 {
 		// Not overwrite statusData if the message contains zero MaxBlock (comes from standalone transaction pool)
 		ss.statusData = statusData
+		ss.announceBlockRange(statusData)
 	}
 	return reply, nil
 }
 
+// announceBlockRange sends BlockRangeUpdate to the eth/69 peers once the served block range
+// has moved by at least blockRangeUpdateInterval blocks since the last announcement.
+// Must be called with statusDataLock held.
+func (ss *GrpcServer) announceBlockRange(statusData *proto_sentry.StatusData) {
+	if len(ss.Protocols) == 0 || ss.Protocols[0].Version < direct.ETH69 || statusData.BestHash == nil {
+		return
+	}
+	blockRange := eth.BlockRangeUpdatePacket{
+		EarliestBlock:   min(statusData.MinimumBlockHeight, statusData.MaxBlockHeight),
+		LatestBlock:     statusData.MaxBlockHeight,
+		LatestBlockHash: gointerfaces.ConvertH256ToHash(statusData.BestHash),
+	}
+	if ss.lastBlockRange != nil &&
+		blockRange.LatestBlock < ss.lastBlockRange.LatestBlock+blockRangeUpdateInterval &&
+		blockRange.EarliestBlock == ss.lastBlockRange.EarliestBlock {
+		return
+	}
+	if blockRange.Validate() != nil {
+		return
+	}
+	ss.lastBlockRange = &blockRange
+
+	b, err := rlp.EncodeToBytes(&blockRange)
+	if err != nil {
+		ss.logger.Error("[sentry] failed to encode BlockRangeUpdate", "err", err)
+		return
+	}
+	ss.rangePeers(func(peerInfo *PeerInfo) bool {
+		if peerInfo.protocol >= direct.ETH69 {
+			ss.writePeer("[sentry] announceBlockRange", peerInfo, eth.BlockRangeUpdateMsg, b, 0)
+		}
+		return true
+	})
+}
+
 func (ss *GrpcServer) Peers(_ context.Context, _ *emptypb.Empty) (*proto_sentry.PeersReply, error) {
 	p2pServer := ss.getP2PServer()
 	if p2pServer == nil {
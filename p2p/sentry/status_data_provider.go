@@ -30,6 +30,7 @@ import (
 	"github.com/erigontech/erigon-lib/gointerfaces"
 	proto_sentry "github.com/erigontech/erigon-lib/gointerfaces/sentryproto"
 	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/kv/rawdbv3"
 	"github.com/erigontech/erigon-lib/log/v3"
 	"github.com/erigontech/erigon-lib/types"
 	"github.com/erigontech/erigon/p2p/forkid"
@@ -126,7 +127,17 @@ func (s *StatusDataProvider) GetStatusData(ctx context.Context) (*proto_sentry.S
 		}
 		return nil, err
 	}
-	return s.makeStatusData(chainHead), err
+	statusData := s.makeStatusData(chainHead)
+
+	var earliestBlock uint64
+	if err = s.db.View(ctx, func(tx kv.Tx) (err error) {
+		earliestBlock, err = ReadEarliestAvailableBlock(tx)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	statusData.MinimumBlockHeight = min(earliestBlock, chainHead.HeadHeight)
+	return statusData, nil
 }
 
 func ReadChainHeadWithTx(tx kv.Tx) (ChainHead, error) {
@@ -151,6 +162,27 @@ func ReadChainHeadWithTx(tx kv.Tx) (ChainHead, error) {
 	return ChainHead{height, time, hash, td256}, nil
 }
 
+// ReadEarliestAvailableBlock returns the first block the node still keeps the state history for,
+// that is the first block it can serve receipts of. Nodes without history pruning return 0.
+func ReadEarliestAvailableBlock(tx kv.Tx) (uint64, error) {
+	ttx, ok := tx.(kv.TemporalTx)
+	if !ok {
+		return 0, nil
+	}
+	historyStart := ttx.HistoryStartFrom(kv.AccountsDomain)
+	if historyStart == 0 {
+		return 0, nil
+	}
+	ok, blockNum, err := rawdbv3.TxNums.FindBlockNum(tx, historyStart)
+	if err != nil {
+		return 0, fmt.Errorf("ReadEarliestAvailableBlock: FindBlockNum error at txNum %d: %w", historyStart, err)
+	}
+	if !ok {
+		return 0, nil
+	}
+	return blockNum, nil
+}
+
 func ReadChainHead(ctx context.Context, db kv.RoDB) (ChainHead, error) {
 	var head ChainHead
 	var err error
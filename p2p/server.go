@@ -152,6 +152,9 @@ type Config struct {
 	// eth/66, eth/67, etc
 	ProtocolVersion []uint
 
+	// SnapServe enables serving the snap/1 protocol next to eth
+	SnapServe bool
+
 	SentryAddr []string
 
 	// If set to a non-nil value, the given NAT port mapper
@@ -81,6 +81,7 @@ type Client struct {
 	isHTTP          bool
 	services        *serviceRegistry
 	methodAllowList AllowList
+	rateLimiter     *rateLimiter
 
 	idCounter uint32
 
@@ -117,7 +118,7 @@ type clientConn struct {
 func (c *Client) newClientConn(conn ServerCodec) *clientConn {
 	ctx := context.WithValue(context.Background(), clientContextKey{}, c)
 	ctx = context.WithValue(ctx, peerInfoContextKey{}, conn.peerInfo())
-	handler := newHandler(ctx, conn, c.idgen, c.services, c.methodAllowList, 50, false /* traceRequests */, c.logger, 0)
+	handler := newHandler(ctx, conn, c.idgen, c.services, c.methodAllowList, c.rateLimiter, 50, false /* traceRequests */, c.logger, 0)
 	return &clientConn{conn, handler}
 }
 
@@ -202,17 +203,18 @@ func newClient(initctx context.Context, connect reconnectFunc, logger log.Logger
 	if err != nil {
 		return nil, err
 	}
-	c := initClient(conn, randomIDGenerator(), &serviceRegistry{logger: logger}, logger)
+	c := initClient(conn, randomIDGenerator(), &serviceRegistry{logger: logger}, nil, logger)
 	c.reconnectFunc = connect
 	return c, nil
 }
 
-func initClient(conn ServerCodec, idgen func() ID, services *serviceRegistry, logger log.Logger) *Client {
+func initClient(conn ServerCodec, idgen func() ID, services *serviceRegistry, rateLimiter *rateLimiter, logger log.Logger) *Client {
 	_, isHTTP := conn.(*httpConn)
 	c := &Client{
 		idgen:       idgen,
 		isHTTP:      isHTTP,
 		services:    services,
+		rateLimiter: rateLimiter,
 		writeConn:   conn,
 		close:       make(chan struct{}),
 		closing:     make(chan struct{}),
@@ -28,6 +28,7 @@ var (
 	_ Error = new(invalidRequestError)
 	_ Error = new(invalidMessageError)
 	_ Error = new(InvalidParamsError)
+	_ Error = new(rateLimitedError)
 	_ Error = new(CustomError)
 )
 
@@ -77,6 +78,15 @@ func (e *InvalidParamsError) ErrorCode() int { return -32602 }
 
 func (e *InvalidParamsError) Error() string { return e.Message }
 
+// the client exceeded the rate limit of the method (EIP-1474 "limit exceeded")
+type rateLimitedError struct{ method string }
+
+func (e *rateLimitedError) ErrorCode() int { return -32005 }
+
+func (e *rateLimitedError) Error() string {
+	return fmt.Sprintf("rate limit exceeded for method %s", e.method)
+}
+
 // mismatch between the Engine API method version and the fork
 type UnsupportedForkError struct{ Message string }
 
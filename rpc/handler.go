@@ -72,6 +72,7 @@ type handler struct {
 
 	allowList     AllowList // a list of explicitly allowed methods, if empty -- everything is allowed
 	forbiddenList ForbiddenList
+	rateLimiter   *rateLimiter // per-method, per-client rate limits, nil if unlimited
 
 	subLock             sync.Mutex
 	serverSubs          map[ID]*Subscription
@@ -119,7 +120,7 @@ func HandleError(err error, stream *jsoniter.Stream) {
 	}
 }
 
-func newHandler(connCtx context.Context, conn jsonWriter, idgen func() ID, reg *serviceRegistry, allowList AllowList, maxBatchConcurrency uint, traceRequests bool, logger log.Logger, rpcSlowLogThreshold time.Duration) *handler {
+func newHandler(connCtx context.Context, conn jsonWriter, idgen func() ID, reg *serviceRegistry, allowList AllowList, rateLimiter *rateLimiter, maxBatchConcurrency uint, traceRequests bool, logger log.Logger, rpcSlowLogThreshold time.Duration) *handler {
 	rootCtx, cancelRoot := context.WithCancel(connCtx)
 	forbiddenList := newForbiddenList()
 
@@ -136,6 +137,7 @@ func newHandler(connCtx context.Context, conn jsonWriter, idgen func() ID, reg *
 		logger:         logger,
 		allowList:      allowList,
 		forbiddenList:  forbiddenList,
+		rateLimiter:    rateLimiter,
 
 		maxBatchConcurrency: maxBatchConcurrency,
 		traceRequests:       traceRequests,
@@ -472,6 +474,10 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage, stream *jsoniter
 	if callb == nil {
 		return msg.errorResponse(&methodNotFoundError{method: msg.Method})
 	}
+	if h.rateLimiter != nil && callb != h.unsubscribeCb && !h.rateLimiter.allow(cp.ctx, msg.Method) {
+		newRateLimitedCounter(msg.Method).Inc()
+		return msg.errorResponse(&rateLimitedError{method: msg.Method})
+	}
 	args, err := parsePositionalArguments(msg.Params, callb.argTypes)
 	if err != nil {
 		return msg.errorResponse(&InvalidParamsError{err.Error()})
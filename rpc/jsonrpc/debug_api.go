@@ -21,6 +21,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
 
 	jsoniter "github.com/json-iterator/go"
 
@@ -66,21 +67,28 @@ type PrivateDebugAPI interface {
 	GetRawReceipts(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]hexutil.Bytes, error)
 	GetBadBlocks(ctx context.Context) ([]map[string]interface{}, error)
 	GetRawTransaction(ctx context.Context, hash common.Hash) (hexutil.Bytes, error)
+	StandardTraceBlockToFile(ctx context.Context, hash common.Hash, config *tracersConfig.StdTraceConfig) ([]string, error)
+	StandardTraceBadBlockToFile(ctx context.Context, hash common.Hash, config *tracersConfig.StdTraceConfig) ([]string, error)
 }
 
 // PrivateDebugAPIImpl is implementation of the PrivateDebugAPI interface based on remote Db access
 type PrivateDebugAPIImpl struct {
 	*BaseAPI
-	db     kv.TemporalRoDB
-	GasCap uint64
+	db       kv.TemporalRoDB
+	GasCap   uint64
+	traceDir string
 }
 
 // NewPrivateDebugAPI returns PrivateDebugAPIImpl instance
-func NewPrivateDebugAPI(base *BaseAPI, db kv.TemporalRoDB, gascap uint64) *PrivateDebugAPIImpl {
+func NewPrivateDebugAPI(base *BaseAPI, db kv.TemporalRoDB, gascap uint64, traceDir string) *PrivateDebugAPIImpl {
+	if traceDir == "" {
+		traceDir = filepath.Join(base.dirs.DataDir, "traces")
+	}
 	return &PrivateDebugAPIImpl{
-		BaseAPI: base,
-		db:      db,
-		GasCap:  gascap,
+		BaseAPI:  base,
+		db:       db,
+		GasCap:   gascap,
+		traceDir: traceDir,
 	}
 }
 
@@ -77,7 +77,7 @@ func TestTraceBlockByNumber(t *testing.T) {
 	stateCache := kvcache.New(kvcache.DefaultCoherentConfig)
 	baseApi := NewBaseApi(nil, stateCache, m.BlockReader, false, rpccfg.DefaultEvmCallTimeout, m.Engine, m.Dirs, nil)
 	ethApi := NewEthAPI(baseApi, m.DB, nil, nil, nil, 5000000, ethconfig.Defaults.RPCTxFeeCap, 100_000, false, 100_000, 128, log.New())
-	api := NewPrivateDebugAPI(baseApi, m.DB, 0)
+	api := NewPrivateDebugAPI(baseApi, m.DB, 0, "")
 	for _, tt := range debugTraceTransactionTests {
 		var buf bytes.Buffer
 		stream := jsoniter.NewStream(jsoniter.ConfigDefault, &buf, 4096)
@@ -128,7 +128,7 @@ func TestTraceBlockByNumber(t *testing.T) {
 func TestTraceBlockByHash(t *testing.T) {
 	m, _, _ := rpcdaemontest.CreateTestSentry(t)
 	ethApi := NewEthAPI(newBaseApiForTest(m), m.DB, nil, nil, nil, 5000000, ethconfig.Defaults.RPCTxFeeCap, 100_000, false, 100_000, 128, log.New())
-	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0, "")
 	for _, tt := range debugTraceTransactionTests {
 		var buf bytes.Buffer
 		stream := jsoniter.NewStream(jsoniter.ConfigDefault, &buf, 4096)
@@ -159,7 +159,7 @@ func TestTraceBlockByHash(t *testing.T) {
 
 func TestTraceTransaction(t *testing.T) {
 	m, _, _ := rpcdaemontest.CreateTestSentry(t)
-	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0, "")
 	for _, tt := range debugTraceTransactionTests {
 		var buf bytes.Buffer
 		stream := jsoniter.NewStream(jsoniter.ConfigDefault, &buf, 4096)
@@ -188,7 +188,7 @@ func TestTraceTransaction(t *testing.T) {
 
 func TestTraceTransactionNoRefund(t *testing.T) {
 	m, _, _ := rpcdaemontest.CreateTestSentry(t)
-	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0, "")
 	for _, tt := range debugTraceTransactionNoRefundTests {
 		var buf bytes.Buffer
 		stream := jsoniter.NewStream(jsoniter.ConfigDefault, &buf, 4096)
@@ -218,7 +218,7 @@ func TestTraceTransactionNoRefund(t *testing.T) {
 
 func TestStorageRangeAt(t *testing.T) {
 	m, _, _ := rpcdaemontest.CreateTestSentry(t)
-	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0, "")
 	t.Run("invalid addr", func(t *testing.T) {
 		var block4 *types.Block
 		var err error
@@ -312,7 +312,7 @@ func TestStorageRangeAt(t *testing.T) {
 
 func TestAccountRange(t *testing.T) {
 	m, _, _ := rpcdaemontest.CreateTestSentry(t)
-	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0, "")
 
 	t.Run("valid account", func(t *testing.T) {
 		addr := common.HexToAddress("0x537e697c7ab75a26f9ecf0ce810e3154dfcaaf55")
@@ -371,7 +371,7 @@ func TestAccountRange(t *testing.T) {
 
 func TestGetModifiedAccountsByNumber(t *testing.T) {
 	m, _, _ := rpcdaemontest.CreateTestSentry(t)
-	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0, "")
 
 	t.Run("correct input", func(t *testing.T) {
 		n, n2 := rpc.BlockNumber(1), rpc.BlockNumber(2)
@@ -470,7 +470,7 @@ func TestMapTxNum2BlockNum(t *testing.T) {
 
 func TestAccountAt(t *testing.T) {
 	m, _, _ := rpcdaemontest.CreateTestSentry(t)
-	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0, "")
 
 	var blockHash0, blockHash1, blockHash3, blockHash10, blockHash12 common.Hash
 	_ = m.DB.View(m.Ctx, func(tx kv.Tx) error {
@@ -533,7 +533,7 @@ func TestAccountAt(t *testing.T) {
 
 func TestGetBadBlocks(t *testing.T) {
 	m, _, _ := rpcdaemontest.CreateTestSentry(t)
-	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 5000000)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 5000000, "")
 	ctx := context.Background()
 
 	require := require.New(t)
@@ -598,7 +598,7 @@ func TestGetBadBlocks(t *testing.T) {
 
 func TestGetRawTransaction(t *testing.T) {
 	m, _, _ := rpcdaemontest.CreateTestSentry(t)
-	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 5000000)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 5000000, "")
 	ctx := context.Background()
 
 	require := require.New(t)
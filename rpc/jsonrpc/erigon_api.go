@@ -49,6 +49,9 @@ type ErigonAPI interface {
 	// Gets cannonical block receipt through hash. If the block is not cannonical returns error
 	GetBlockReceiptsByBlockHash(ctx context.Context, cannonicalBlockHash common.Hash) ([]map[string]interface{}, error)
 
+	// Token transfers related (see ./erigon_token_transfers.go)
+	GetTokenTransfers(ctx context.Context, addr common.Address, fromBlock, toBlock rpc.BlockNumber, pageSize uint16) (*TokenTransfersPage, error)
+
 	// NodeInfo returns a collection of metadata known about the host.
 	NodeInfo(ctx context.Context) ([]p2p.NodeInfo, error)
 }
@@ -0,0 +1,282 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/erigontech/erigon-db/rawdb"
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/kv/order"
+	"github.com/erigontech/erigon-lib/kv/rawdbv3"
+	"github.com/erigontech/erigon-lib/kv/stream"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon-lib/types"
+	"github.com/erigontech/erigon/rpc"
+	"github.com/erigontech/erigon/rpc/rpchelper"
+)
+
+const maxTokenTransfersPageSize = 1000
+
+// TokenTransfer is an ERC-20, ERC-721 or ERC-1155 transfer event.
+type TokenTransfer struct {
+	Standard    string          `json:"standard"`
+	Token       common.Address  `json:"token"`
+	Operator    *common.Address `json:"operator,omitempty"`
+	From        common.Address  `json:"from"`
+	To          common.Address  `json:"to"`
+	TokenIds    []*hexutil.Big  `json:"tokenIds,omitempty"`
+	Values      []*hexutil.Big  `json:"values,omitempty"`
+	BlockNumber hexutil.Uint64  `json:"blockNumber"`
+	BlockHash   common.Hash     `json:"blockHash"`
+	Timestamp   hexutil.Uint64  `json:"timestamp"`
+	TxHash      common.Hash     `json:"transactionHash"`
+	TxIndex     hexutil.Uint    `json:"transactionIndex"`
+	LogIndex    hexutil.Uint    `json:"logIndex"`
+}
+
+// TokenTransfersPage is a page of token transfers, NextBlock is where the next page starts and is nil on the last one.
+type TokenTransfersPage struct {
+	Transfers []*TokenTransfer `json:"transfers"`
+	NextBlock *hexutil.Uint64  `json:"nextBlock"`
+}
+
+// GetTokenTransfers implements erigon_getTokenTransfers. Returns the token transfers sent or received by addr between
+// fromBlock and toBlock (both inclusive), oldest first.
+//
+// A page never splits a block, so it may hold a little more than pageSize transfers. The next page is fetched by
+// passing the returned nextBlock as fromBlock.
+//
+// The transfers are indexed by the execution, the blocks executed before the indices existed are rejected until
+// they are indexed by `integration stage_custom_trace --domain=tokentransfersfrom,tokentransfersto`.
+func (api *ErigonImpl) GetTokenTransfers(ctx context.Context, addr common.Address, fromBlock, toBlock rpc.BlockNumber, pageSize uint16) (*TokenTransfersPage, error) {
+	if pageSize == 0 || pageSize > maxTokenTransfersPageSize {
+		return nil, fmt.Errorf("page size must be between 1 and %d", maxTokenTransfersPageSize)
+	}
+
+	tx, err := api.db.BeginTemporalRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	from, _, _, err := rpchelper.GetBlockNumber(ctx, rpc.BlockNumberOrHashWithNumber(fromBlock), tx, api._blockReader, api.filters)
+	if err != nil {
+		return nil, err
+	}
+	to, _, _, err := rpchelper.GetBlockNumber(ctx, rpc.BlockNumberOrHashWithNumber(toBlock), tx, api._blockReader, api.filters)
+	if err != nil {
+		return nil, err
+	}
+	if from > to {
+		return nil, fmt.Errorf("fromBlock %d is after toBlock %d", from, to)
+	}
+	start, err := tokenTransfersStart(tx)
+	if err != nil {
+		return nil, err
+	}
+	if from < start {
+		return nil, errTokenTransfersNotIndexed(start)
+	}
+	fromTxNum, err := api._txNumReader.Min(tx, from)
+	if err != nil {
+		return nil, err
+	}
+	toTxNum, err := api._txNumReader.Max(tx, to)
+	if err != nil {
+		return nil, err
+	}
+
+	transfers, nextBlock, err := api.tokenTransfers(ctx, tx, addr, int(fromTxNum), int(toTxNum)+1, order.Asc, int(pageSize))
+	if err != nil {
+		return nil, err
+	}
+	page := &TokenTransfersPage{Transfers: transfers}
+	if nextBlock != nil {
+		page.NextBlock = (*hexutil.Uint64)(nextBlock)
+	}
+	return page, nil
+}
+
+// tokenTransfersStart returns the first block from which the token transfers indices are complete.
+func tokenTransfersStart(tx kv.Tx) (uint64, error) {
+	start, ok, err := rawdb.ReadTokenTransfersStart(tx)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, errors.New("token transfers are not indexed yet")
+	}
+	return start, nil
+}
+
+func errTokenTransfersNotIndexed(start uint64) error {
+	return fmt.Errorf("token transfers are indexed from block %d, the earlier blocks are indexed by `integration stage_custom_trace --domain=%s,%s`",
+		start, kv.TokenTransfersFromIdx, kv.TokenTransfersToIdx)
+}
+
+// tokenTransfers collects the token transfers touching addr between the txNums fromTxNum and toTxNum, walked in the
+// given order (for order.Desc fromTxNum is the upper bound, -1 leaves a bound open). The collection stops at the
+// first block boundary after pageSize transfers, that block is returned as nextBlock.
+func (api *BaseAPI) tokenTransfers(ctx context.Context, tx kv.TemporalTx, addr common.Address, fromTxNum, toTxNum int, asc order.By, pageSize int) (transfers []*TokenTransfer, nextBlock *uint64, err error) {
+	chainConfig, err := api.chainConfig(ctx, tx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// unbounded limit on purpose, the transfers of addr are filtered out of the receipts later
+	itFrom, err := tx.IndexRange(kv.TokenTransfersFromIdx, addr[:], fromTxNum, toTxNum, asc, kv.Unlim)
+	if err != nil {
+		return nil, nil, err
+	}
+	itTo, err := tx.IndexRange(kv.TokenTransfersToIdx, addr[:], fromTxNum, toTxNum, asc, kv.Unlim)
+	if err != nil {
+		return nil, nil, err
+	}
+	it := rawdbv3.TxNums2BlockNums(tx, api._txNumReader, stream.Union[uint64](itFrom, itTo, asc, kv.Unlim), asc)
+	defer it.Close()
+
+	transfers = []*TokenTransfer{}
+	var header *types.Header
+	for it.HasNext() {
+		if err = ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		txNum, blockNum, txIndex, isFinalTxn, blockNumChanged, err := it.Next()
+		if err != nil {
+			return nil, nil, err
+		}
+		if blockNumChanged && len(transfers) >= pageSize {
+			return transfers, &blockNum, nil
+		}
+		if isFinalTxn || txIndex < 0 {
+			continue
+		}
+
+		if blockNumChanged || header == nil {
+			if header, err = api._blockReader.HeaderByNumber(ctx, tx, blockNum); err != nil {
+				return nil, nil, err
+			}
+			if header == nil {
+				log.Warn("[rpc] header is nil", "blockNum", blockNum)
+				continue
+			}
+		}
+		txn, err := api._txnReader.TxnByIdxInBlock(ctx, tx, blockNum, txIndex)
+		if err != nil {
+			return nil, nil, err
+		}
+		if txn == nil {
+			log.Warn("[rpc] txn not found", "blockNum", blockNum, "txIndex", txIndex)
+			continue
+		}
+		receipt, err := api.receiptsGenerator.GetReceipt(ctx, chainConfig, tx, header, txn, txIndex, txNum)
+		if err != nil {
+			return nil, nil, err
+		}
+		for i := range receipt.Logs {
+			lg := receipt.Logs[i]
+			if asc == order.Desc {
+				lg = receipt.Logs[len(receipt.Logs)-1-i]
+			}
+			if transfer := newTokenTransfer(lg, header.Time); transfer != nil && (transfer.From == addr || transfer.To == addr) {
+				transfers = append(transfers, transfer)
+			}
+		}
+	}
+	return transfers, nil, nil
+}
+
+// newTokenTransfer decodes a transfer event, it returns nil if lg is not one. Amounts and token ids which do not
+// follow the standard encoding are left out.
+func newTokenTransfer(lg *types.Log, timestamp uint64) *TokenTransfer {
+	from, to, ok := lg.TokenTransferParticipants()
+	if !ok {
+		return nil
+	}
+	transfer := &TokenTransfer{
+		Token:       lg.Address,
+		From:        from,
+		To:          to,
+		BlockNumber: hexutil.Uint64(lg.BlockNumber),
+		BlockHash:   lg.BlockHash,
+		Timestamp:   hexutil.Uint64(timestamp),
+		TxHash:      lg.TxHash,
+		TxIndex:     hexutil.Uint(lg.TxIndex),
+		LogIndex:    hexutil.Uint(lg.Index),
+	}
+	switch lg.Topics[0] {
+	case types.TransferEventTopic:
+		if len(lg.Topics) == 4 {
+			transfer.Standard = "erc721"
+			transfer.TokenIds = []*hexutil.Big{wordToBig(lg.Topics[3][:])}
+			break
+		}
+		transfer.Standard = "erc20"
+		if len(lg.Data) >= 32 {
+			transfer.Values = []*hexutil.Big{wordToBig(lg.Data[:32])}
+		}
+	case types.TransferSingleEventTopic:
+		transfer.Standard = "erc1155"
+		operator := common.BytesToAddress(lg.Topics[1][:])
+		transfer.Operator = &operator
+		if len(lg.Data) >= 64 {
+			transfer.TokenIds = []*hexutil.Big{wordToBig(lg.Data[:32])}
+			transfer.Values = []*hexutil.Big{wordToBig(lg.Data[32:64])}
+		}
+	case types.TransferBatchEventTopic:
+		transfer.Standard = "erc1155"
+		operator := common.BytesToAddress(lg.Topics[1][:])
+		transfer.Operator = &operator
+		ids, idsOk := decodeUint256Array(lg.Data, 0)
+		values, valuesOk := decodeUint256Array(lg.Data, 32)
+		if idsOk && valuesOk && len(ids) == len(values) {
+			transfer.TokenIds, transfer.Values = ids, values
+		}
+	}
+	return transfer
+}
+
+func wordToBig(word []byte) *hexutil.Big {
+	return (*hexutil.Big)(new(big.Int).SetBytes(word))
+}
+
+// decodeUint256Array decodes the abi encoded uint256[] whose offset is stored at head in data.
+func decodeUint256Array(data []byte, head int) ([]*hexutil.Big, bool) {
+	if len(data) < head+32 {
+		return nil, false
+	}
+	offset := new(big.Int).SetBytes(data[head : head+32])
+	if !offset.IsUint64() || offset.Uint64() > uint64(len(data)-32) {
+		return nil, false
+	}
+	start := int(offset.Uint64()) + 32
+	length := new(big.Int).SetBytes(data[start-32 : start])
+	if !length.IsUint64() || length.Uint64() > uint64(len(data)-start)/32 {
+		return nil, false
+	}
+	out := make([]*hexutil.Big, length.Uint64())
+	for i := range out {
+		out[i] = wordToBig(data[start+32*i : start+32*(i+1)])
+	}
+	return out, true
+}
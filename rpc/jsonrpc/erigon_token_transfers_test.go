@@ -0,0 +1,195 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package jsonrpc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-db/rawdb"
+	"github.com/erigontech/erigon-lib/chain"
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/types"
+	"github.com/erigontech/erigon/core"
+	"github.com/erigontech/erigon/rpc"
+	"github.com/erigontech/erigon/turbo/stages/mock"
+)
+
+func TestNewTokenTransfer(t *testing.T) {
+	token := common.HexToAddress("0xaa")
+	operator := common.HexToAddress("0x01")
+	from := common.HexToAddress("0x02")
+	to := common.HexToAddress("0x03")
+	word := func(v uint64) []byte { return common.BigToHash(new(big.Int).SetUint64(v)).Bytes() }
+	hexBig := func(v int64) *hexutil.Big { return (*hexutil.Big)(big.NewInt(v)) }
+	concat := func(words ...[]byte) (out []byte) {
+		for _, w := range words {
+			out = append(out, w...)
+		}
+		return out
+	}
+
+	erc20 := newTokenTransfer(&types.Log{
+		Address: token,
+		Topics:  []common.Hash{types.TransferEventTopic, common.BytesToHash(from[:]), common.BytesToHash(to[:])},
+		Data:    word(1000),
+	}, 0)
+	require.Equal(t, "erc20", erc20.Standard)
+	require.Equal(t, token, erc20.Token)
+	require.Equal(t, from, erc20.From)
+	require.Equal(t, to, erc20.To)
+	require.Nil(t, erc20.Operator)
+	require.Equal(t, []*hexutil.Big{hexBig(1000)}, erc20.Values)
+
+	erc721 := newTokenTransfer(&types.Log{
+		Topics: []common.Hash{types.TransferEventTopic, common.BytesToHash(from[:]), common.BytesToHash(to[:]), common.BytesToHash(word(42))},
+	}, 0)
+	require.Equal(t, "erc721", erc721.Standard)
+	require.Equal(t, []*hexutil.Big{hexBig(42)}, erc721.TokenIds)
+	require.Nil(t, erc721.Values)
+
+	batchTopics := []common.Hash{types.TransferBatchEventTopic, common.BytesToHash(operator[:]), common.BytesToHash(from[:]), common.BytesToHash(to[:])}
+	batch := newTokenTransfer(&types.Log{
+		Topics: batchTopics,
+		// ids at offset 64, values at offset 160
+		Data: concat(word(64), word(160), word(2), word(7), word(8), word(2), word(70), word(80)),
+	}, 0)
+	require.Equal(t, "erc1155", batch.Standard)
+	require.Equal(t, &operator, batch.Operator)
+	require.Equal(t, []*hexutil.Big{hexBig(7), hexBig(8)}, batch.TokenIds)
+	require.Equal(t, []*hexutil.Big{hexBig(70), hexBig(80)}, batch.Values)
+
+	// out of bounds array length, the amounts are left out but the transfer itself is kept
+	malformed := newTokenTransfer(&types.Log{
+		Topics: batchTopics,
+		Data:   concat(word(64), word(160), word(1000), word(7)),
+	}, 0)
+	require.Equal(t, from, malformed.From)
+	require.Nil(t, malformed.TokenIds)
+	require.Nil(t, malformed.Values)
+
+	require.Nil(t, newTokenTransfer(&types.Log{Topics: []common.Hash{types.TransferEventTopic}}, 0))
+}
+
+// mockWithTokenTransfers returns a mock of the given number of blocks, each holding a token transfer from testAddr
+// to each of the recipients.
+func mockWithTokenTransfers(t *testing.T, blocks int, recipients ...common.Address) *mock.MockSentry {
+	token := common.HexToAddress("0x70ce")
+	// emits Transfer(caller, calldata[0:32], 1)
+	code := common.FromHex("0x600160005260003533" + "7f" + types.TransferEventTopic.Hex()[2:] + "60206000a300")
+	m := mock.MockWithGenesis(t, &types.Genesis{
+		Config: chain.TestChainConfig,
+		Alloc: types.GenesisAlloc{
+			testAddr: {Balance: big.NewInt(1000000)},
+			token:    {Code: code, Balance: new(big.Int)},
+		},
+	}, testKey, false)
+
+	signer := types.LatestSignerForChainID(nil)
+	chain, err := core.GenerateChain(m.ChainConfig, m.Genesis, m.Engine, m.DB, blocks, func(i int, block *core.BlockGen) {
+		for _, recipient := range recipients {
+			txn, err := types.SignTx(types.NewTransaction(block.TxNonce(testAddr), token, uint256.NewInt(0), 100_000, nil, common.LeftPadBytes(recipient[:], 32)), *signer, testKey)
+			require.NoError(t, err)
+			block.AddTx(txn)
+		}
+	})
+	require.NoError(t, err)
+	require.NoError(t, m.InsertChain(chain))
+	return m
+}
+
+func TestGetTokenTransfers(t *testing.T) {
+	alice, bob := common.HexToAddress("0xa11ce"), common.HexToAddress("0xb0b")
+	m := mockWithTokenTransfers(t, 6, alice, bob)
+	api := NewErigonAPI(newBaseApiForTest(m), m.DB, nil)
+	otsApi := NewOtterscanAPI(newBaseApiForTest(m), m.DB, 25)
+
+	// the mock executes the chain from genesis
+	tx, err := m.DB.BeginRo(m.Ctx)
+	require.NoError(t, err)
+	start, ok, err := rawdb.ReadTokenTransfersStart(tx)
+	tx.Rollback()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Zero(t, start)
+
+	// pages never split a block: 2 transfers per block with a page size of 3
+	var blocks []uint64
+	from := rpc.BlockNumber(0)
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 3)
+		page, err := api.GetTokenTransfers(m.Ctx, testAddr, from, rpc.LatestBlockNumber, 3)
+		require.NoError(t, err)
+		require.Len(t, page.Transfers, 4)
+		for _, transfer := range page.Transfers {
+			require.Equal(t, "erc20", transfer.Standard)
+			require.Equal(t, testAddr, transfer.From)
+			blocks = append(blocks, uint64(transfer.BlockNumber))
+		}
+		if page.NextBlock == nil {
+			break
+		}
+		from = rpc.BlockNumber(*page.NextBlock)
+	}
+	require.Equal(t, []uint64{1, 1, 2, 2, 3, 3, 4, 4, 5, 5, 6, 6}, blocks)
+
+	page, err := api.GetTokenTransfers(m.Ctx, alice, 0, rpc.LatestBlockNumber, 100)
+	require.NoError(t, err)
+	require.Len(t, page.Transfers, 6)
+	for _, transfer := range page.Transfers {
+		require.Equal(t, alice, transfer.To)
+	}
+	require.Nil(t, page.NextBlock)
+
+	blocks = nil
+	blockNum := uint64(0)
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 3)
+		page, err := otsApi.SearchTokenTransfers(m.Ctx, testAddr, blockNum, 3)
+		require.NoError(t, err)
+		for _, transfer := range page.Transfers {
+			blocks = append(blocks, uint64(transfer.BlockNumber))
+		}
+		if page.NextBlock == nil {
+			break
+		}
+		blockNum = uint64(*page.NextBlock)
+	}
+	require.Equal(t, []uint64{6, 6, 5, 5, 4, 4, 3, 3, 2, 2, 1, 1}, blocks)
+
+	// a node upgraded at block 3 has no index of the earlier blocks
+	require.NoError(t, m.DB.Update(m.Ctx, func(tx kv.RwTx) error {
+		return rawdb.WriteTokenTransfersStart(tx, 3)
+	}))
+	_, err = api.GetTokenTransfers(m.Ctx, testAddr, 2, rpc.LatestBlockNumber, 3)
+	require.ErrorContains(t, err, "indexed from block 3")
+	page, err = api.GetTokenTransfers(m.Ctx, testAddr, 3, 3, 3)
+	require.NoError(t, err)
+	require.Len(t, page.Transfers, 2)
+
+	page, err = otsApi.SearchTokenTransfers(m.Ctx, testAddr, 5, 3)
+	require.NoError(t, err)
+	require.Len(t, page.Transfers, 4)
+	require.Equal(t, hexutil.Uint64(3), *page.NextBlock)
+	_, err = otsApi.SearchTokenTransfers(m.Ctx, testAddr, 3, 3)
+	require.ErrorContains(t, err, "indexed from block 3")
+}
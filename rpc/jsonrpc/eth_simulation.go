@@ -0,0 +1,681 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/holiman/uint256"
+
+	"github.com/erigontech/erigon-lib/chain"
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/empty"
+	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon-lib/crypto"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/kv/temporal"
+	"github.com/erigontech/erigon-lib/log/v3"
+	libstate "github.com/erigontech/erigon-lib/state"
+	"github.com/erigontech/erigon-lib/types"
+	"github.com/erigontech/erigon/core"
+	"github.com/erigontech/erigon/core/state"
+	"github.com/erigontech/erigon/core/tracing"
+	"github.com/erigontech/erigon/core/vm"
+	"github.com/erigontech/erigon/eth/consensuschain"
+	"github.com/erigontech/erigon/execution/consensus"
+	"github.com/erigontech/erigon/execution/consensus/misc"
+	"github.com/erigontech/erigon/rpc"
+	"github.com/erigontech/erigon/rpc/ethapi"
+	"github.com/erigontech/erigon/rpc/rpchelper"
+	"github.com/erigontech/erigon/turbo/shards"
+	"github.com/erigontech/erigon/turbo/transactions"
+)
+
+const (
+	// maxSimulateBlocks is the maximum number of blocks (including the empty
+	// blocks used to fill number gaps) that a single eth_simulateV1 call may produce.
+	maxSimulateBlocks = 256
+	// simulateTimestampIncrement is the default time difference between two consecutive simulated blocks.
+	simulateTimestampIncrement = 12
+)
+
+// Error codes of eth_simulateV1, see https://github.com/ethereum/execution-apis
+const (
+	simErrCodeNonceTooHigh            = -38011
+	simErrCodeNonceTooLow             = -38010
+	simErrCodeIntrinsicGas            = -38013
+	simErrCodeInsufficientFunds       = -38014
+	simErrCodeBlockGasLimitReached    = -38015
+	simErrCodeBlockNumberInvalid      = -38020
+	simErrCodeBlockTimestampInvalid   = -38021
+	simErrCodeSenderIsNotEOA          = -38024
+	simErrCodeMaxInitCodeSizeExceeded = -38025
+	simErrCodeClientLimitExceeded     = -38026
+	simErrCodeInternalError           = -32603
+	simErrCodeInvalidParams           = -32602
+	simErrCodeReverted                = -32000
+	simErrCodeVMError                 = -32015
+)
+
+// transferAddress is the pseudo-address emitting the synthetic ETH transfer logs when traceTransfers is requested.
+var transferAddress = common.HexToAddress("0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE")
+
+// transferTopic is the keccak256 of the ERC-20 `Transfer(address,address,uint256)` event.
+var transferTopic = common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+
+// SimulationOpts is the input of eth_simulateV1.
+type SimulationOpts struct {
+	BlockStateCalls        []SimulatedBlock `json:"blockStateCalls"`
+	TraceTransfers         bool             `json:"traceTransfers"`
+	Validation             bool             `json:"validation"`
+	ReturnFullTransactions bool             `json:"returnFullTransactions"`
+}
+
+// SimulatedBlock is a batch of calls executed on top of the given state and block overrides.
+type SimulatedBlock struct {
+	BlockOverrides *SimulatedBlockOverrides `json:"blockOverrides"`
+	StateOverrides *ethapi.StateOverrides   `json:"stateOverrides"`
+	Calls          []ethapi.CallArgs        `json:"calls"`
+}
+
+// SimulatedBlockOverrides are the header fields which can be overridden for a simulated block.
+type SimulatedBlockOverrides struct {
+	Number        *hexutil.Big        `json:"number"`
+	Time          *hexutil.Uint64     `json:"time"`
+	GasLimit      *hexutil.Uint64     `json:"gasLimit"`
+	FeeRecipient  *common.Address     `json:"feeRecipient"`
+	PrevRandao    *common.Hash        `json:"prevRandao"`
+	BaseFeePerGas *hexutil.Big        `json:"baseFeePerGas"`
+	BlobBaseFee   *hexutil.Big        `json:"blobBaseFee"`
+	Withdrawals   []*types.Withdrawal `json:"withdrawals"`
+}
+
+// SimulatedCallResult is the outcome of a single call of a simulated block.
+type SimulatedCallResult struct {
+	ReturnValue hexutil.Bytes       `json:"returnData"`
+	Logs        []*types.Log        `json:"logs"`
+	GasUsed     hexutil.Uint64      `json:"gasUsed"`
+	Status      hexutil.Uint64      `json:"status"`
+	Error       *SimulatedCallError `json:"error,omitempty"`
+}
+
+// SimulatedCallError describes why a call of a simulated block failed.
+type SimulatedCallError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data,omitempty"`
+}
+
+// SimulateV1 implements eth_simulateV1. It executes a sequence of simulated blocks on top of the
+// state of the given base block and returns the resulting blocks, each of them extended with the
+// results of its calls. The state roots of the blocks are only computed on top of the latest block,
+// as the commitment of older blocks can't be updated. They are left empty otherwise.
+func (api *APIImpl) SimulateV1(ctx context.Context, opts SimulationOpts, blockNrOrHash *rpc.BlockNumberOrHash) ([]map[string]interface{}, error) {
+	if len(opts.BlockStateCalls) == 0 {
+		return nil, &rpc.CustomError{Code: simErrCodeInvalidParams, Message: "empty input"}
+	}
+	if len(opts.BlockStateCalls) > maxSimulateBlocks {
+		return nil, &rpc.CustomError{Code: simErrCodeClientLimitExceeded, Message: "too many blocks"}
+	}
+	if blockNrOrHash == nil {
+		latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		blockNrOrHash = &latest
+	}
+
+	tx, err := api.db.BeginTemporalRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	chainConfig, err := api.chainConfig(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	baseHeader, _, err := headerByNumberOrHash(ctx, tx, *blockNrOrHash, api)
+	if err != nil {
+		return nil, err
+	}
+	if baseHeader == nil {
+		return nil, fmt.Errorf("block %v not found", blockNrOrHash)
+	}
+
+	defer func(start time.Time) { log.Trace("Executing EVM simulateV1 finished", "runtime", time.Since(start)) }(time.Now())
+
+	stateReader, err := rpchelper.CreateStateReader(ctx, tx, api._blockReader, *blockNrOrHash, 0, api.filters, api.stateCache, chainConfig.ChainName)
+	if err != nil {
+		return nil, err
+	}
+
+	var cancel context.CancelFunc
+	if api.evmCallTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, api.evmCallTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	sim := &simulator{
+		api:         api,
+		tx:          tx,
+		chainConfig: chainConfig,
+		base:        baseHeader,
+		opts:        opts,
+		stateCache:  shards.NewStateCache(32, 0 /* no limit */), // this cache living only during current RPC call, but required to store state writes
+		hashes:      map[uint64]common.Hash{},
+	}
+	if sim.domains, err = newSimulationDomains(ctx, tx, baseHeader); err != nil {
+		return nil, err
+	}
+	var writer state.StateWriter = state.NewNoopWriter()
+	if sim.domains != nil {
+		defer sim.domains.Close()
+		writer = state.NewWriter(sim.domains, nil)
+	}
+	sim.stateReader = state.NewCachedReader(stateReader, sim.stateCache)
+	sim.stateWriter = state.NewCachedWriter(writer, sim.stateCache)
+	return sim.execute(ctx)
+}
+
+// newSimulationDomains opens the shared domains the simulated state is written to, so that the state roots of the
+// simulated blocks are computed from the commitment of the base block. Only the latest commitment can be updated, so
+// it returns nil if the base block is an older one, or if the rpcdaemon has no access to the state files.
+func newSimulationDomains(ctx context.Context, tx kv.TemporalTx, base *types.Header) (*libstate.SharedDomains, error) {
+	if _, ok := tx.(*temporal.Tx); !ok {
+		return nil, nil
+	}
+	domains, err := libstate.NewSharedDomains(tx, log.New())
+	if err != nil {
+		return nil, err
+	}
+	root, err := domains.ComputeCommitment(ctx, false /* saveStateAfter */, base.Number.Uint64(), "eth_simulateV1")
+	if err != nil {
+		domains.Close()
+		return nil, err
+	}
+	if common.BytesToHash(root) != base.Root {
+		domains.Close()
+		return nil, nil
+	}
+	return domains, nil
+}
+
+// simulator holds the state shared by the blocks of one eth_simulateV1 call.
+type simulator struct {
+	api         *APIImpl
+	tx          kv.TemporalTx
+	chainConfig *chain.Config
+	base        *types.Header
+	opts        SimulationOpts
+
+	stateCache  *shards.StateCache
+	stateReader state.StateReader
+	stateWriter state.StateWriter
+	domains     *libstate.SharedDomains // holds the simulated state on top of the base block, nil if it is not the latest
+
+	// hashes of already simulated blocks, used by BLOCKHASH
+	hashes map[uint64]common.Hash
+}
+
+func (s *simulator) execute(ctx context.Context) ([]map[string]interface{}, error) {
+	blocks, err := s.sanitizeChain(s.opts.BlockStateCalls)
+	if err != nil {
+		return nil, err
+	}
+	headers, err := s.makeHeaders(blocks)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]map[string]interface{}, 0, len(blocks))
+	parent := s.base
+	for i, block := range blocks {
+		simulated, callResults, err := s.processBlock(ctx, &block, headers[i], parent)
+		if err != nil {
+			return nil, err
+		}
+		s.hashes[simulated.NumberU64()] = simulated.Hash()
+		fields, err := ethapi.RPCMarshalBlock(simulated, true, s.opts.ReturnFullTransactions, map[string]interface{}{"calls": callResults})
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, fields)
+		parent = simulated.HeaderNoCopy()
+	}
+	return results, nil
+}
+
+// sanitizeChain checks the block number and timestamp overrides and fills the
+// gaps between requested block numbers with empty blocks.
+func (s *simulator) sanitizeChain(blocks []SimulatedBlock) ([]SimulatedBlock, error) {
+	res := make([]SimulatedBlock, 0, len(blocks))
+	prevNumber := s.base.Number.Uint64()
+	prevTime := s.base.Time
+	for _, block := range blocks {
+		if block.BlockOverrides == nil {
+			block.BlockOverrides = &SimulatedBlockOverrides{}
+		}
+		if block.BlockOverrides.Number == nil {
+			n := new(big.Int).SetUint64(prevNumber + 1)
+			block.BlockOverrides.Number = (*hexutil.Big)(n)
+		}
+		number := block.BlockOverrides.Number.ToInt()
+		if !number.IsUint64() || number.Uint64() <= prevNumber {
+			return nil, &rpc.CustomError{Code: simErrCodeBlockNumberInvalid, Message: fmt.Sprintf("block numbers must be in order: %d <= %d", number, prevNumber)}
+		}
+		// Fill the gap with empty blocks
+		if gap := number.Uint64() - prevNumber; gap > 1 {
+			// compared in uint64: the gap can be anything up to 2^64-1
+			if gap > maxSimulateBlocks || uint64(len(res))+gap > maxSimulateBlocks {
+				return nil, &rpc.CustomError{Code: simErrCodeClientLimitExceeded, Message: "too many blocks"}
+			}
+			for n := prevNumber + 1; n < number.Uint64(); n++ {
+				prevTime += simulateTimestampIncrement
+				t := hexutil.Uint64(prevTime)
+				res = append(res, SimulatedBlock{BlockOverrides: &SimulatedBlockOverrides{
+					Number: (*hexutil.Big)(new(big.Int).SetUint64(n)),
+					Time:   &t,
+				}})
+			}
+		}
+		if block.BlockOverrides.Time == nil {
+			t := prevTime + simulateTimestampIncrement
+			block.BlockOverrides.Time = (*hexutil.Uint64)(&t)
+		} else if uint64(*block.BlockOverrides.Time) <= prevTime {
+			return nil, &rpc.CustomError{Code: simErrCodeBlockTimestampInvalid, Message: fmt.Sprintf("block timestamps must be in order: %d <= %d", *block.BlockOverrides.Time, prevTime)}
+		}
+		prevNumber = number.Uint64()
+		prevTime = uint64(*block.BlockOverrides.Time)
+		res = append(res, block)
+	}
+	if len(res) > maxSimulateBlocks {
+		return nil, &rpc.CustomError{Code: simErrCodeClientLimitExceeded, Message: "too many blocks"}
+	}
+	return res, nil
+}
+
+// makeHeaders builds the skeleton headers of the simulated blocks. Fields which depend on the
+// parent (base fee, excess blob gas) are filled in by processBlock.
+func (s *simulator) makeHeaders(blocks []SimulatedBlock) ([]*types.Header, error) {
+	headers := make([]*types.Header, len(blocks))
+	for i, block := range blocks {
+		overrides := block.BlockOverrides
+		header := &types.Header{
+			UncleHash:  empty.UncleHash,
+			Coinbase:   s.base.Coinbase,
+			Difficulty: new(big.Int).Set(s.base.Difficulty),
+			Number:     new(big.Int).Set(overrides.Number.ToInt()),
+			GasLimit:   s.base.GasLimit,
+			Time:       uint64(*overrides.Time),
+			MixDigest:  s.base.MixDigest,
+		}
+		if overrides.GasLimit != nil {
+			header.GasLimit = uint64(*overrides.GasLimit)
+		}
+		if overrides.FeeRecipient != nil {
+			header.Coinbase = *overrides.FeeRecipient
+		}
+		if overrides.PrevRandao != nil {
+			header.MixDigest = *overrides.PrevRandao
+		}
+		if s.chainConfig.IsCancun(header.Time) {
+			header.ParentBeaconBlockRoot = &common.Hash{}
+		}
+		if s.chainConfig.IsPrague(header.Time) {
+			header.RequestsHash = &empty.RequestsHash
+		}
+		headers[i] = header
+	}
+	return headers, nil
+}
+
+func (s *simulator) processBlock(ctx context.Context, block *SimulatedBlock, header, parent *types.Header) (*types.Block, []SimulatedCallResult, error) {
+	header.ParentHash = parent.Hash()
+	overrides := block.BlockOverrides
+	if s.chainConfig.IsLondon(header.Number.Uint64()) {
+		switch {
+		case overrides.BaseFeePerGas != nil:
+			header.BaseFee = new(big.Int).Set(overrides.BaseFeePerGas.ToInt())
+		case s.opts.Validation:
+			header.BaseFee = misc.CalcBaseFee(s.chainConfig, parent)
+		default:
+			header.BaseFee = new(big.Int)
+		}
+	}
+	if s.chainConfig.IsCancun(header.Time) {
+		excessBlobGas := misc.CalcExcessBlobGas(s.chainConfig, parent, header.Time)
+		header.ExcessBlobGas = &excessBlobGas
+	}
+
+	rules := s.chainConfig.Rules(header.Number.Uint64(), header.Time)
+	ibs := state.New(s.stateReader)
+	if block.StateOverrides != nil {
+		if err := block.StateOverrides.Override(ibs); err != nil {
+			return nil, nil, &rpc.CustomError{Code: simErrCodeInvalidParams, Message: err.Error()}
+		}
+	}
+
+	var tracer *transferTracer
+	vmConfig := vm.Config{NoBaseFee: !s.opts.Validation}
+	if s.opts.TraceTransfers {
+		tracer = newTransferTracer()
+		vmConfig.Tracer = tracer.Hooks()
+		ibs.SetHooks(vmConfig.Tracer)
+	}
+
+	engine := s.api.engine()
+	blockCtx := core.NewEVMBlockContext(header, s.getHashFn(), engine, &header.Coinbase, s.chainConfig)
+	if overrides.BlobBaseFee != nil {
+		blobBaseFee, overflow := uint256.FromBig(overrides.BlobBaseFee.ToInt())
+		if overflow {
+			return nil, nil, &rpc.CustomError{Code: simErrCodeInvalidParams, Message: "blobBaseFee higher than 2^256-1"}
+		}
+		blockCtx.BlobBaseFee = blobBaseFee
+	}
+	evm := vm.NewEVM(blockCtx, core.NewEVMTxContext(&types.Message{}), ibs, s.chainConfig, vmConfig)
+
+	// Wait for the context to be done and cancel the evm. Even if the
+	// EVM has finished, cancelling may be done (repeatedly)
+	go func() {
+		<-ctx.Done()
+		evm.Cancel()
+	}()
+
+	if consensusEngine, ok := engine.(consensus.Engine); ok {
+		chainReader := consensuschain.NewReader(s.chainConfig, s.tx, s.api._blockReader, log.Root())
+		if err := core.InitializeBlockExecution(consensusEngine, chainReader, header, s.chainConfig, ibs, nil, log.Root(), nil); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var (
+		gp          = new(core.GasPool).AddGas(header.GasLimit).AddBlobGas(s.chainConfig.GetMaxBlobGasPerBlock(header.Time))
+		gasUsed     uint64
+		blobGasUsed uint64
+		txns        = make(types.Transactions, 0, len(block.Calls))
+		receipts    = make(types.Receipts, 0, len(block.Calls))
+		callResults = make([]SimulatedCallResult, 0, len(block.Calls))
+		callLogs    = make([][]*types.Log, 0, len(block.Calls))
+	)
+	for i := range block.Calls {
+		args := block.Calls[i]
+		if err := s.sanitizeCall(&args, ibs, header, gasUsed); err != nil {
+			return nil, nil, err
+		}
+		txn, err := args.ToTransaction(s.api.GasCap, blockCtx.BaseFee)
+		if err != nil {
+			return nil, nil, &rpc.CustomError{Code: simErrCodeInvalidParams, Message: err.Error()}
+		}
+		txn.SetSender(*args.From)
+		msg, err := args.ToMessage(s.api.GasCap, blockCtx.BaseFee)
+		if err != nil {
+			return nil, nil, &rpc.CustomError{Code: simErrCodeInvalidParams, Message: err.Error()}
+		}
+		msg.SetNonce(uint64(*args.Nonce)) // ToMessage leaves it out, sanitizeCall has filled it in
+		msg.SetCheckNonce(s.opts.Validation)
+
+		ibs.SetTxContext(i)
+		if tracer != nil {
+			tracer.reset()
+		}
+		evm.Reset(core.NewEVMTxContext(msg), ibs)
+		result, err := core.ApplyMessage(evm, msg, gp, true /* refunds */, false /* gasBailout */, engine)
+		if err != nil {
+			return nil, nil, txValidationError(err)
+		}
+		if evm.Cancelled() {
+			return nil, nil, fmt.Errorf("execution aborted (timeout = %v)", s.api.evmCallTimeout)
+		}
+		if err = ibs.FinalizeTx(rules, state.NewNoopWriter()); err != nil {
+			return nil, nil, err
+		}
+		gasUsed += result.UsedGas
+		blobGasUsed += msg.BlobGas()
+
+		receipt := &types.Receipt{
+			Type:              txn.Type(),
+			CumulativeGasUsed: gasUsed,
+			TxHash:            txn.Hash(),
+			GasUsed:           result.UsedGas,
+			BlockNumber:       header.Number,
+			TransactionIndex:  uint(i),
+			Logs:              ibs.GetRawLogs(i),
+		}
+		if result.Failed() {
+			receipt.Status = types.ReceiptStatusFailed
+		} else {
+			receipt.Status = types.ReceiptStatusSuccessful
+		}
+		if msg.To() == nil {
+			receipt.ContractAddress = crypto.CreateAddress(msg.From(), msg.Nonce())
+		}
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+
+		callResult := SimulatedCallResult{
+			ReturnValue: common.CopyBytes(result.Return()),
+			GasUsed:     hexutil.Uint64(result.UsedGas),
+			Status:      hexutil.Uint64(receipt.Status),
+		}
+		if result.Failed() {
+			if errors.Is(result.Err, vm.ErrExecutionReverted) {
+				revertErr := ethapi.NewRevertError(result)
+				callResult.Error = &SimulatedCallError{Code: simErrCodeReverted, Message: revertErr.Error(), Data: revertErr.ErrorData().(string)}
+			} else {
+				callResult.Error = &SimulatedCallError{Code: simErrCodeVMError, Message: result.Err.Error()}
+			}
+		}
+		logs := receipt.Logs
+		if tracer != nil {
+			logs = tracer.Logs()
+		}
+
+		txns = append(txns, txn)
+		receipts = append(receipts, receipt)
+		callResults = append(callResults, callResult)
+		callLogs = append(callLogs, logs)
+	}
+
+	var withdrawals []*types.Withdrawal
+	if s.chainConfig.IsShanghai(header.Time) {
+		withdrawals = overrides.Withdrawals
+		if withdrawals == nil {
+			withdrawals = make([]*types.Withdrawal, 0)
+		}
+		for _, w := range withdrawals {
+			amountInWei := new(uint256.Int).Mul(uint256.NewInt(w.Amount), uint256.NewInt(common.GWei))
+			if err := ibs.AddBalance(w.Address, amountInWei, tracing.BalanceIncreaseWithdrawal); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	if err := ibs.CommitBlock(rules, s.stateWriter); err != nil {
+		return nil, nil, err
+	}
+
+	if s.domains != nil {
+		root, err := s.domains.ComputeCommitment(ctx, false /* saveStateAfter */, header.Number.Uint64(), "eth_simulateV1")
+		if err != nil {
+			return nil, nil, err
+		}
+		header.Root = common.BytesToHash(root)
+	}
+
+	header.GasUsed = gasUsed
+	if s.chainConfig.IsCancun(header.Time) {
+		header.BlobGasUsed = &blobGasUsed
+	}
+	simulated := types.NewBlock(header, txns, nil, receipts, withdrawals)
+
+	blockHash := simulated.Hash()
+	var logIndex uint
+	for i, logs := range callLogs {
+		callResults[i].Logs = make([]*types.Log, 0, len(logs))
+		for _, l := range logs {
+			l.TxHash = txns[i].Hash()
+			l.TxIndex = uint(i)
+			l.Index = logIndex
+			l.BlockNumber = header.Number.Uint64()
+			l.BlockHash = blockHash
+			logIndex++
+			callResults[i].Logs = append(callResults[i].Logs, l)
+		}
+	}
+	return simulated, callResults, nil
+}
+
+// sanitizeCall fills the call fields which were not set by the user with the values of the
+// current simulated state, and checks the remaining block gas.
+func (s *simulator) sanitizeCall(args *ethapi.CallArgs, ibs *state.IntraBlockState, header *types.Header, gasUsed uint64) error {
+	if args.From == nil {
+		args.From = &common.Address{}
+	}
+	if args.Nonce == nil {
+		nonce, err := ibs.GetNonce(*args.From)
+		if err != nil {
+			return err
+		}
+		args.Nonce = (*hexutil.Uint64)(&nonce)
+	}
+	if args.ChainID == nil {
+		args.ChainID = (*hexutil.Big)(s.chainConfig.ChainID)
+	}
+	remaining := header.GasLimit - gasUsed
+	if args.Gas == nil {
+		args.Gas = (*hexutil.Uint64)(&remaining)
+	}
+	if uint64(*args.Gas) > remaining {
+		return &rpc.CustomError{Code: simErrCodeBlockGasLimitReached, Message: fmt.Sprintf("block gas limit reached: %d >= %d", gasUsed, header.GasLimit)}
+	}
+	return nil
+}
+
+// getHashFn resolves BLOCKHASH to the simulated blocks first and to the canonical chain otherwise.
+func (s *simulator) getHashFn() func(uint64) common.Hash {
+	canonical := transactions.MakeHeaderGetter(true /* requireCanonical */, s.tx, s.api._blockReader)
+	return func(n uint64) common.Hash {
+		if hash, ok := s.hashes[n]; ok {
+			return hash
+		}
+		if n > s.base.Number.Uint64() {
+			return common.Hash{}
+		}
+		return canonical(n)
+	}
+}
+
+// txValidationError converts the errors of the pre-execution checks into eth_simulateV1 errors.
+func txValidationError(err error) error {
+	code := simErrCodeInternalError
+	switch {
+	case errors.Is(err, core.ErrNonceTooHigh):
+		code = simErrCodeNonceTooHigh
+	case errors.Is(err, core.ErrNonceTooLow):
+		code = simErrCodeNonceTooLow
+	case errors.Is(err, core.ErrSenderNoEOA):
+		code = simErrCodeSenderIsNotEOA
+	case errors.Is(err, core.ErrFeeCapVeryHigh), errors.Is(err, core.ErrTipVeryHigh),
+		errors.Is(err, core.ErrTipAboveFeeCap), errors.Is(err, core.ErrFeeCapTooLow):
+		code = simErrCodeInvalidParams
+	case errors.Is(err, core.ErrInsufficientFunds):
+		code = simErrCodeInsufficientFunds
+	case errors.Is(err, core.ErrIntrinsicGas):
+		code = simErrCodeIntrinsicGas
+	case errors.Is(err, core.ErrMaxInitCodeSizeExceeded):
+		code = simErrCodeMaxInitCodeSizeExceeded
+	case errors.Is(err, core.ErrGasLimitReached), errors.Is(err, core.ErrBlobGasLimitReached):
+		code = simErrCodeBlockGasLimitReached
+	}
+	return &rpc.CustomError{Code: code, Message: err.Error()}
+}
+
+// transferTracer collects the logs of a call together with synthetic ERC-20 like
+// `Transfer` logs for every ETH value transfer. Logs of reverted frames are dropped.
+type transferTracer struct {
+	// logs[i] holds the logs emitted by the frame at depth i
+	logs [][]*types.Log
+}
+
+func newTransferTracer() *transferTracer {
+	return &transferTracer{}
+}
+
+func (t *transferTracer) Hooks() *tracing.Hooks {
+	return &tracing.Hooks{
+		OnEnter: t.onEnter,
+		OnExit:  t.onExit,
+		OnLog:   t.onLog,
+	}
+}
+
+func (t *transferTracer) reset() {
+	t.logs = nil
+}
+
+func (t *transferTracer) onEnter(depth int, typ byte, from common.Address, to common.Address, precompile bool, input []byte, gas uint64, value *uint256.Int, code []byte) {
+	t.logs = append(t.logs, make([]*types.Log, 0))
+	if vm.OpCode(typ) == vm.DELEGATECALL || vm.OpCode(typ) == vm.STATICCALL || value == nil || value.IsZero() {
+		return
+	}
+	t.captureTransfer(from, to, value)
+}
+
+func (t *transferTracer) onExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	if len(t.logs) == 0 {
+		return
+	}
+	frame := t.logs[len(t.logs)-1]
+	t.logs = t.logs[:len(t.logs)-1]
+	if reverted {
+		return
+	}
+	if len(t.logs) == 0 {
+		// keep the logs of the top-level frame until the next call
+		t.logs = append(t.logs, frame)
+		return
+	}
+	t.logs[len(t.logs)-1] = append(t.logs[len(t.logs)-1], frame...)
+}
+
+func (t *transferTracer) onLog(l *types.Log) {
+	if len(t.logs) == 0 {
+		return
+	}
+	cpy := &types.Log{Address: l.Address, Topics: l.Topics, Data: common.CopyBytes(l.Data)}
+	t.logs[len(t.logs)-1] = append(t.logs[len(t.logs)-1], cpy)
+}
+
+func (t *transferTracer) captureTransfer(from, to common.Address, value *uint256.Int) {
+	topics := []common.Hash{
+		transferTopic,
+		common.BytesToHash(from.Bytes()),
+		common.BytesToHash(to.Bytes()),
+	}
+	data := value.Bytes32()
+	t.logs[len(t.logs)-1] = append(t.logs[len(t.logs)-1], &types.Log{Address: transferAddress, Topics: topics, Data: data[:]})
+}
+
+// Logs returns the logs of the last traced call.
+func (t *transferTracer) Logs() []*types.Log {
+	if len(t.logs) == 0 {
+		return nil
+	}
+	return t.logs[0]
+}
@@ -0,0 +1,233 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package jsonrpc
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/chain"
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/datadir"
+	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon-lib/crypto"
+	"github.com/erigontech/erigon-lib/kv/kvcache"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon-lib/types"
+	"github.com/erigontech/erigon/eth/ethconfig"
+	"github.com/erigontech/erigon/execution/abi/bind/backends"
+	"github.com/erigontech/erigon/rpc"
+	"github.com/erigontech/erigon/rpc/ethapi"
+	"github.com/erigontech/erigon/rpc/rpccfg"
+)
+
+var simulateTestReceiver = common.HexToAddress("0x1000000000000000000000000000000000000001")
+
+// newSimulateTestAPI returns an api over a chain with one block and a funded address.
+func newSimulateTestAPI(t *testing.T, config *chain.Config) (*APIImpl, common.Address) {
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		gspec   = &types.Genesis{
+			Config:   config,
+			Alloc:    types.GenesisAlloc{address: {Balance: big.NewInt(9000000000000000000)}},
+			GasLimit: 10000000,
+		}
+	)
+
+	contractBackend := backends.NewTestSimulatedBackendWithConfig(t, gspec.Alloc, gspec.Config, gspec.GasLimit)
+	t.Cleanup(contractBackend.Close)
+	contractBackend.Commit()
+
+	stateCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	api := NewEthAPI(NewBaseApi(nil, stateCache, contractBackend.BlockReader(), false, rpccfg.DefaultEvmCallTimeout, contractBackend.Engine(), datadir.New(t.TempDir()), nil), contractBackend.DB(), nil, nil, nil, 5000000, ethconfig.Defaults.RPCTxFeeCap, 100_000, false, 100_000, 128, log.New())
+	return api, address
+}
+
+func TestSimulateV1(t *testing.T) {
+	ctx := context.Background()
+	api, address := newSimulateTestAPI(t, chain.TestChainConfig)
+	receiver := simulateTestReceiver
+
+	value := (*hexutil.Big)(big.NewInt(1000))
+	gas := hexutil.Uint64(21000)
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	nextNumber := (*hexutil.Big)(big.NewInt(5))
+
+	res, err := api.SimulateV1(ctx, SimulationOpts{
+		TraceTransfers: true,
+		BlockStateCalls: []SimulatedBlock{
+			{Calls: []ethapi.CallArgs{{From: &address, To: &receiver, Value: value, Gas: &gas}}},
+			{
+				BlockOverrides: &SimulatedBlockOverrides{Number: nextNumber},
+				Calls:          []ethapi.CallArgs{{From: &address, To: &receiver, Value: value, Gas: &gas}},
+			},
+		},
+	}, &latest)
+	require.NoError(t, err)
+
+	// the gap between the first simulated block and block 5 is filled with empty blocks
+	require.Len(t, res, 4)
+	for i, block := range res {
+		require.Equal(t, (*hexutil.Big)(big.NewInt(int64(2+i))), block["number"])
+	}
+	require.Equal(t, res[0]["hash"], res[1]["parentHash"])
+
+	calls := res[0]["calls"].([]SimulatedCallResult)
+	require.Len(t, calls, 1)
+	require.Equal(t, hexutil.Uint64(types.ReceiptStatusSuccessful), calls[0].Status)
+	require.Equal(t, hexutil.Uint64(21000), calls[0].GasUsed)
+	require.Len(t, calls[0].Logs, 1)
+	require.Equal(t, transferAddress, calls[0].Logs[0].Address)
+	require.Equal(t, common.BytesToHash(receiver.Bytes()), calls[0].Logs[0].Topics[2])
+
+	// the last block carries the second transfer
+	txns := res[3]["transactions"].([]interface{})
+	require.Len(t, txns, 1)
+	require.Empty(t, res[1]["calls"])
+}
+
+func TestSimulateV1StateRoot(t *testing.T) {
+	ctx := context.Background()
+	api, address := newSimulateTestAPI(t, chain.TestChainConfig)
+	receiver := simulateTestReceiver
+	value := (*hexutil.Big)(big.NewInt(1000))
+	gas := hexutil.Uint64(21000)
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+
+	tx, err := api.db.BeginTemporalRo(ctx)
+	require.NoError(t, err)
+	base, _, err := headerByNumberOrHash(ctx, tx, latest, api)
+	tx.Rollback()
+	require.NoError(t, err)
+
+	// a transfer without fees, followed by an empty block
+	res, err := api.SimulateV1(ctx, SimulationOpts{BlockStateCalls: []SimulatedBlock{
+		{Calls: []ethapi.CallArgs{{From: &address, To: &receiver, Value: value, Gas: &gas}}},
+		{},
+	}}, &latest)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	transferRoot := res[0]["stateRoot"].(common.Hash)
+	require.NotEqual(t, common.Hash{}, transferRoot)
+	require.NotEqual(t, base.Root, transferRoot)
+	require.Equal(t, transferRoot, res[1]["stateRoot"])
+	require.Equal(t, res[0]["hash"], res[1]["parentHash"])
+
+	// the same state reached through overrides has the same root
+	senderBalance := (*hexutil.Big)(new(big.Int).Sub(big.NewInt(9000000000000000000), value.ToInt()))
+	nonce := hexutil.Uint64(1)
+	res, err = api.SimulateV1(ctx, SimulationOpts{BlockStateCalls: []SimulatedBlock{{
+		StateOverrides: &ethapi.StateOverrides{
+			address:  {Nonce: &nonce, Balance: &senderBalance},
+			receiver: {Balance: &value},
+		},
+	}}}, &latest)
+	require.NoError(t, err)
+	require.Equal(t, transferRoot, res[0]["stateRoot"])
+
+	// a block without state changes keeps the root of the base block
+	res, err = api.SimulateV1(ctx, SimulationOpts{BlockStateCalls: []SimulatedBlock{{}}}, &latest)
+	require.NoError(t, err)
+	require.Equal(t, base.Root, res[0]["stateRoot"])
+}
+
+func TestSimulateV1InvalidBlockOrder(t *testing.T) {
+	sim := &simulator{base: &types.Header{Number: big.NewInt(10), Time: 100}}
+
+	_, err := sim.sanitizeChain([]SimulatedBlock{{BlockOverrides: &SimulatedBlockOverrides{Number: (*hexutil.Big)(big.NewInt(10))}}})
+	var rpcErr *rpc.CustomError
+	require.ErrorAs(t, err, &rpcErr)
+	require.Equal(t, simErrCodeBlockNumberInvalid, rpcErr.Code)
+
+	time := hexutil.Uint64(100)
+	_, err = sim.sanitizeChain([]SimulatedBlock{{BlockOverrides: &SimulatedBlockOverrides{Time: &time}}})
+	require.ErrorAs(t, err, &rpcErr)
+	require.Equal(t, simErrCodeBlockTimestampInvalid, rpcErr.Code)
+
+	_, err = sim.sanitizeChain([]SimulatedBlock{{BlockOverrides: &SimulatedBlockOverrides{Number: (*hexutil.Big)(big.NewInt(10 + maxSimulateBlocks + 1))}}})
+	require.ErrorAs(t, err, &rpcErr)
+	require.Equal(t, simErrCodeClientLimitExceeded, rpcErr.Code)
+
+	// the gap must not overflow the limit check
+	maxNumber := (*hexutil.Big)(new(big.Int).SetUint64(math.MaxUint64))
+	_, err = sim.sanitizeChain([]SimulatedBlock{{}, {BlockOverrides: &SimulatedBlockOverrides{Number: maxNumber}}})
+	require.ErrorAs(t, err, &rpcErr)
+	require.Equal(t, simErrCodeClientLimitExceeded, rpcErr.Code)
+}
+
+func TestSimulateV1StateCarryOver(t *testing.T) {
+	ctx := context.Background()
+	api, address := newSimulateTestAPI(t, chain.TestChainConfig)
+	receiver := simulateTestReceiver
+	value := (*hexutil.Big)(big.NewInt(1000))
+	gas := hexutil.Uint64(21000)
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+
+	// the receiver can only send back what it got in the previous block
+	res, err := api.SimulateV1(ctx, SimulationOpts{BlockStateCalls: []SimulatedBlock{
+		{Calls: []ethapi.CallArgs{{From: &address, To: &receiver, Value: value, Gas: &gas}}},
+		{Calls: []ethapi.CallArgs{{From: &receiver, To: &address, Value: value, Gas: &gas}}},
+	}}, &latest)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	calls := res[1]["calls"].([]SimulatedCallResult)
+	require.Equal(t, hexutil.Uint64(types.ReceiptStatusSuccessful), calls[0].Status)
+
+	_, err = api.SimulateV1(ctx, SimulationOpts{BlockStateCalls: []SimulatedBlock{
+		{Calls: []ethapi.CallArgs{{From: &address, To: &receiver, Value: value, Gas: &gas}}},
+		{Calls: []ethapi.CallArgs{{From: &receiver, To: &address, Value: value, Gas: &gas}}},
+		{Calls: []ethapi.CallArgs{{From: &receiver, To: &address, Value: value, Gas: &gas}}},
+	}}, &latest)
+	var rpcErr *rpc.CustomError
+	require.ErrorAs(t, err, &rpcErr)
+	require.Equal(t, simErrCodeInsufficientFunds, rpcErr.Code)
+}
+
+func TestSimulateV1Validation(t *testing.T) {
+	ctx := context.Background()
+	londonConfig := *chain.TestChainConfig
+	londonConfig.LondonBlock = big.NewInt(0)
+	api, address := newSimulateTestAPI(t, &londonConfig)
+	receiver := simulateTestReceiver
+	gas := hexutil.Uint64(21000)
+	feeCap := (*hexutil.Big)(big.NewInt(1_000_000_000_000))
+	nonce := hexutil.Uint64(0)
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	call := ethapi.CallArgs{From: &address, To: &receiver, Gas: &gas, MaxFeePerGas: feeCap, MaxPriorityFeePerGas: feeCap, Nonce: &nonce}
+
+	// the nonce of the first block is carried over, so repeating it is only accepted without validation
+	twice := []SimulatedBlock{{Calls: []ethapi.CallArgs{call}}, {Calls: []ethapi.CallArgs{call}}}
+	_, err := api.SimulateV1(ctx, SimulationOpts{BlockStateCalls: twice}, &latest)
+	require.NoError(t, err)
+	_, err = api.SimulateV1(ctx, SimulationOpts{BlockStateCalls: twice, Validation: true}, &latest)
+	var rpcErr *rpc.CustomError
+	require.ErrorAs(t, err, &rpcErr)
+	require.Equal(t, simErrCodeNonceTooLow, rpcErr.Code)
+
+	// and so are fee caps below the base fee
+	noFees := []SimulatedBlock{{Calls: []ethapi.CallArgs{{From: &address, To: &receiver, Gas: &gas}}}}
+	_, err = api.SimulateV1(ctx, SimulationOpts{BlockStateCalls: noFees}, &latest)
+	require.NoError(t, err)
+	_, err = api.SimulateV1(ctx, SimulationOpts{BlockStateCalls: noFees, Validation: true}, &latest)
+	require.ErrorAs(t, err, &rpcErr)
+	require.Equal(t, simErrCodeInvalidParams, rpcErr.Code)
+}
@@ -18,22 +18,36 @@ package jsonrpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
+	"sync"
 
 	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/common/hexutil"
 	"github.com/erigontech/erigon-lib/kv"
 	"github.com/erigontech/erigon-lib/types"
+	"github.com/erigontech/erigon-lib/types/accounts"
+	"github.com/erigontech/erigon/core/state"
 	"github.com/erigontech/erigon/eth/ethutils"
+	"github.com/erigontech/erigon/eth/filters"
 	"github.com/erigontech/erigon/rpc"
 	"github.com/erigontech/erigon/rpc/ethapi"
 	"github.com/erigontech/erigon/rpc/rpchelper"
 )
 
 type GraphQLAPI interface {
+	// BeginRequest returns a context whose reads share one transaction until release is called
+	BeginRequest(ctx context.Context) (requestCtx context.Context, release func())
 	GetBlockDetails(ctx context.Context, number rpc.BlockNumber) (map[string]interface{}, error)
 	GetChainID(ctx context.Context) (*big.Int, error)
+	GetAccount(ctx context.Context, address common.Address, number rpc.BlockNumber) (*accounts.Account, error)
+	GetCode(ctx context.Context, address common.Address, number rpc.BlockNumber) ([]byte, error)
+	GetStorageAt(ctx context.Context, address common.Address, slot common.Hash, number rpc.BlockNumber) ([]byte, error)
+	// The subscriptions last until ctx is done
+	SubscribeNewHeads(ctx context.Context) (<-chan *types.Header, error)
+	SubscribeLogs(ctx context.Context, crit filters.FilterCriteria) (<-chan *types.Log, error)
+	SubscribePendingTxs(ctx context.Context) (<-chan []types.Transaction, error)
 }
 
 type GraphQLAPIImpl struct {
@@ -48,28 +62,77 @@ func NewGraphQLAPI(base *BaseAPI, db kv.TemporalRoDB) *GraphQLAPIImpl {
 	}
 }
 
-func (api *GraphQLAPIImpl) GetChainID(ctx context.Context) (*big.Int, error) {
-	tx, err := api.db.BeginTemporalRo(ctx)
-	if err != nil {
-		return nil, err
+type graphQLRequestKey struct{}
+
+// graphQLRequest holds the transaction shared by the resolvers of a request, which may run concurrently
+type graphQLRequest struct {
+	mu       sync.Mutex
+	tx       kv.TemporalTx
+	released bool
+}
+
+func (api *GraphQLAPIImpl) BeginRequest(ctx context.Context) (context.Context, func()) {
+	req := &graphQLRequest{}
+	return context.WithValue(ctx, graphQLRequestKey{}, req), func() {
+		req.mu.Lock()
+		defer req.mu.Unlock()
+		if req.tx != nil {
+			req.tx.Rollback()
+			req.tx = nil
+		}
+		req.released = true
 	}
-	defer tx.Rollback()
+}
 
-	response, err := api.chainConfig(ctx, tx)
-	if err != nil {
-		return nil, err
+// withTx runs f with the transaction of the request of ctx, opened on the first read, or with a
+// transaction of its own outside of a request
+func (api *GraphQLAPIImpl) withTx(ctx context.Context, f func(tx kv.TemporalTx) error) error {
+	req, ok := ctx.Value(graphQLRequestKey{}).(*graphQLRequest)
+	if !ok {
+		tx, err := api.db.BeginTemporalRo(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+		return f(tx)
 	}
 
-	return response.ChainID, nil
+	req.mu.Lock()
+	defer req.mu.Unlock()
+	if req.released {
+		return errors.New("graphql request is already released")
+	}
+	if req.tx == nil {
+		tx, err := api.db.BeginTemporalRo(ctx)
+		if err != nil {
+			return err
+		}
+		req.tx = tx
+	}
+	return f(req.tx)
 }
 
-func (api *GraphQLAPIImpl) GetBlockDetails(ctx context.Context, blockNumber rpc.BlockNumber) (map[string]interface{}, error) {
-	tx, err := api.db.BeginTemporalRo(ctx)
-	if err != nil {
-		return nil, err
-	}
-	defer tx.Rollback()
+func (api *GraphQLAPIImpl) GetChainID(ctx context.Context) (chainID *big.Int, err error) {
+	err = api.withTx(ctx, func(tx kv.TemporalTx) error {
+		response, err := api.chainConfig(ctx, tx)
+		if err != nil {
+			return err
+		}
+		chainID = response.ChainID
+		return nil
+	})
+	return chainID, err
+}
+
+func (api *GraphQLAPIImpl) GetBlockDetails(ctx context.Context, blockNumber rpc.BlockNumber) (response map[string]interface{}, err error) {
+	err = api.withTx(ctx, func(tx kv.TemporalTx) error {
+		response, err = api.getBlockDetails(ctx, tx, blockNumber)
+		return err
+	})
+	return response, err
+}
 
+func (api *GraphQLAPIImpl) getBlockDetails(ctx context.Context, tx kv.TemporalTx, blockNumber rpc.BlockNumber) (map[string]interface{}, error) {
 	block, _, err := api.getBlockWithSenders(ctx, blockNumber, tx)
 	if err != nil {
 		return nil, err
@@ -163,3 +226,87 @@ func (api *GraphQLAPIImpl) delegateGetBlockByNumber(tx kv.Tx, b *types.Block, nu
 
 	return response, err
 }
+
+// readState runs f with a reader of the state at the given block
+func (api *GraphQLAPIImpl) readState(ctx context.Context, number rpc.BlockNumber, f func(reader state.StateReader) error) error {
+	return api.withTx(ctx, func(tx kv.TemporalTx) error {
+		chainConfig, err := api.chainConfig(ctx, tx)
+		if err != nil {
+			return err
+		}
+		reader, err := rpchelper.CreateStateReader(ctx, tx, api._blockReader, rpc.BlockNumberOrHashWithNumber(number), 0, api.filters, api.stateCache, chainConfig.ChainName)
+		if err != nil {
+			return err
+		}
+		return f(reader)
+	})
+}
+
+// GetAccount returns the account at the given block, nil if it doesn't exist
+func (api *GraphQLAPIImpl) GetAccount(ctx context.Context, address common.Address, number rpc.BlockNumber) (acc *accounts.Account, err error) {
+	err = api.readState(ctx, number, func(reader state.StateReader) error {
+		acc, err = reader.ReadAccountData(address)
+		return err
+	})
+	return acc, err
+}
+
+func (api *GraphQLAPIImpl) GetCode(ctx context.Context, address common.Address, number rpc.BlockNumber) (code []byte, err error) {
+	err = api.readState(ctx, number, func(reader state.StateReader) error {
+		acc, err := reader.ReadAccountData(address)
+		if acc == nil || err != nil {
+			return err
+		}
+		code, err = reader.ReadAccountCode(address)
+		return err
+	})
+	return code, err
+}
+
+func (api *GraphQLAPIImpl) GetStorageAt(ctx context.Context, address common.Address, slot common.Hash, number rpc.BlockNumber) (value []byte, err error) {
+	err = api.readState(ctx, number, func(reader state.StateReader) error {
+		acc, err := reader.ReadAccountData(address)
+		if acc == nil || err != nil {
+			return err
+		}
+		value, err = reader.ReadAccountStorage(address, slot)
+		return err
+	})
+	return value, err
+}
+
+func (api *GraphQLAPIImpl) SubscribeNewHeads(ctx context.Context) (<-chan *types.Header, error) {
+	if api.filters == nil {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	headers, id := api.filters.SubscribeNewHeads(32)
+	go func() {
+		<-ctx.Done()
+		api.filters.UnsubscribeHeads(id)
+	}()
+	return headers, nil
+}
+
+func (api *GraphQLAPIImpl) SubscribeLogs(ctx context.Context, crit filters.FilterCriteria) (<-chan *types.Log, error) {
+	if api.filters == nil {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	logs, id := api.filters.SubscribeLogs(128, crit)
+	go func() {
+		<-ctx.Done()
+		api.filters.UnsubscribeLogs(id)
+	}()
+	return logs, nil
+}
+
+func (api *GraphQLAPIImpl) SubscribePendingTxs(ctx context.Context) (<-chan []types.Transaction, error) {
+	if api.filters == nil {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	txs, id := api.filters.SubscribePendingTxs(256)
+	go func() {
+		<-ctx.Done()
+		api.filters.UnsubscribePendingTxs(id)
+	}()
+	return txs, nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package jsonrpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon-lib/kv/order"
+)
+
+// SearchTokenTransfers returns the token transfers sent or received by addr before blockNum (0 means the latest
+// block), newest first. Like SearchTransactionsBefore, blocks are never split between pages, the returned nextBlock
+// is the blockNum of the next call. The search stops at the first indexed block, see GetTokenTransfers.
+func (api *OtterscanAPIImpl) SearchTokenTransfers(ctx context.Context, addr common.Address, blockNum uint64, pageSize uint16) (*TokenTransfersPage, error) {
+	if pageSize == 0 || uint64(pageSize) > api.maxPageSize {
+		return nil, fmt.Errorf("max allowed page size: %v", api.maxPageSize)
+	}
+
+	tx, err := api.db.BeginTemporalRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	start, err := tokenTransfersStart(tx)
+	if err != nil {
+		return nil, err
+	}
+	if blockNum != 0 && blockNum <= start {
+		return nil, errTokenTransfersNotIndexed(start)
+	}
+	// the lower bound is exclusive
+	toTxNum := -1
+	if start > 0 {
+		minTxNum, err := api._txNumReader.Min(tx, start)
+		if err != nil {
+			return nil, err
+		}
+		toTxNum = int(minTxNum) - 1
+	}
+
+	fromTxNum := -1
+	if blockNum != 0 {
+		// blockNum itself is excluded, start from the last txNum of the block before it
+		maxTxNum, err := api._txNumReader.Max(tx, blockNum-1)
+		if err != nil {
+			return nil, err
+		}
+		fromTxNum = int(maxTxNum)
+	}
+
+	transfers, nextBlock, err := api.tokenTransfers(ctx, tx, addr, fromTxNum, toTxNum, order.Desc, int(pageSize))
+	if err != nil {
+		return nil, err
+	}
+	page := &TokenTransfersPage{Transfers: transfers}
+	if nextBlock != nil {
+		next := hexutil.Uint64(*nextBlock + 1)
+		page.NextBlock = &next
+	} else if start > 0 {
+		// the blocks before start aren't indexed, the next call reports it
+		next := hexutil.Uint64(start)
+		page.NextBlock = &next
+	}
+	return page, nil
+}
@@ -0,0 +1,176 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package jsonrpc
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/erigontech/erigon-db/rawdb"
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/kv"
+	"github.com/erigontech/erigon-lib/types"
+	"github.com/erigontech/erigon/core"
+	"github.com/erigontech/erigon/core/state"
+	"github.com/erigontech/erigon/core/vm"
+	tracersConfig "github.com/erigontech/erigon/eth/tracers/config"
+	"github.com/erigontech/erigon/eth/tracers/logger"
+	"github.com/erigontech/erigon/turbo/transactions"
+)
+
+// StandardTraceBlockToFile implements debug_standardTraceBlockToFile. Dumps the EIP-3155
+// traces of the transactions of a canonical block into files, one per transaction, and
+// returns the file names.
+func (api *PrivateDebugAPIImpl) StandardTraceBlockToFile(ctx context.Context, hash common.Hash, config *tracersConfig.StdTraceConfig) ([]string, error) {
+	tx, err := api.db.BeginTemporalRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	block, err := api.blockByHashWithSenders(ctx, tx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %#x not found", hash)
+	}
+	if err := api.BaseAPI.checkPruneHistory(ctx, tx, block.NumberU64()); err != nil {
+		return nil, err
+	}
+	return api.standardTraceBlockToFile(ctx, tx, block, "block", config)
+}
+
+// StandardTraceBadBlockToFile implements debug_standardTraceBadBlockToFile. Same as
+// debug_standardTraceBlockToFile, but for one of the recent bad blocks (see debug_getBadBlocks).
+func (api *PrivateDebugAPIImpl) StandardTraceBadBlockToFile(ctx context.Context, hash common.Hash, config *tracersConfig.StdTraceConfig) ([]string, error) {
+	tx, err := api.db.BeginTemporalRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	blocks, err := rawdb.GetLatestBadBlocks(tx)
+	if err != nil {
+		return nil, err
+	}
+	var block *types.Block
+	for _, b := range blocks {
+		if b != nil && b.Hash() == hash { // the bad blocks cache may outlive their bodies
+			block = b
+			break
+		}
+	}
+	if block == nil {
+		return nil, fmt.Errorf("bad block %#x not found", hash)
+	}
+	if err := api.BaseAPI.checkPruneHistory(ctx, tx, block.NumberU64()); err != nil {
+		return nil, err
+	}
+	return api.standardTraceBlockToFile(ctx, tx, block, "badblock", config)
+}
+
+// standardTraceBlockToFile re-executes the block on top of its parent state and
+// writes the trace of every transaction (or only of config.TxHash) to its own
+// JSONL file in the trace directory.
+func (api *PrivateDebugAPIImpl) standardTraceBlockToFile(ctx context.Context, tx kv.TemporalTx, block *types.Block, prefix string, config *tracersConfig.StdTraceConfig) ([]string, error) {
+	if config == nil {
+		config = &tracersConfig.StdTraceConfig{}
+	}
+	txHash := config.TxHash
+	if txHash != (common.Hash{}) && block.Transaction(txHash) == nil {
+		return nil, fmt.Errorf("transaction %#x not found in block %#x", txHash, block.Hash())
+	}
+
+	chainConfig, err := api.chainConfig(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	engine := api.engine()
+
+	ibs, blockCtx, _, rules, signer, err := transactions.ComputeBlockContext(ctx, engine, block.HeaderNoCopy(), chainConfig, api._blockReader, api._txNumReader, tx, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(api.traceDir, 0o755); err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for txnIndex, txn := range block.Transactions() {
+		select {
+		case <-ctx.Done():
+			return files, ctx.Err()
+		default:
+		}
+		ibs.SetTxContext(txnIndex)
+		msg, err := txn.AsMessage(*signer, block.BaseFee(), rules)
+		if err != nil {
+			return files, err
+		}
+		txCtx := core.NewEVMTxContext(msg)
+		txCtx.TxHash = txn.Hash()
+
+		var (
+			vmConfig = vm.Config{NoBaseFee: true}
+			dump     *os.File
+			writer   *bufio.Writer
+		)
+		traced := txHash == (common.Hash{}) || txHash == txn.Hash()
+		if traced {
+			name := fmt.Sprintf("%s_%#x-%d-%#x-", prefix, block.Hash().Bytes()[:4], txnIndex, txn.Hash().Bytes()[:4])
+			if dump, err = os.CreateTemp(api.traceDir, name+"*.jsonl"); err != nil {
+				return files, err
+			}
+			writer = bufio.NewWriter(dump)
+			stream := jsoniter.NewStream(jsoniter.ConfigDefault, writer, 4096)
+			vmConfig.Tracer = logger.NewEIP3155StreamLogger(&config.LogConfig, ctx, stream).Tracer().Hooks
+			files = append(files, dump.Name())
+		}
+		ibs.SetHooks(vmConfig.Tracer)
+
+		evm := vm.NewEVM(blockCtx, txCtx, ibs, chainConfig, vmConfig)
+		if vmConfig.Tracer != nil && vmConfig.Tracer.OnTxStart != nil {
+			vmConfig.Tracer.OnTxStart(evm.GetVMContext(), txn, msg.From())
+		}
+		gp := new(core.GasPool).AddGas(msg.Gas()).AddBlobGas(msg.BlobGas())
+		_, err = core.ApplyMessage(evm, msg, gp, true /* refunds */, false /* gasBailout */, engine)
+		if traced {
+			if flushErr := writer.Flush(); err == nil {
+				err = flushErr
+			}
+			if closeErr := dump.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		if err != nil {
+			return files, fmt.Errorf("transaction %#x failed: %w", txn.Hash(), err)
+		}
+		if err = ibs.FinalizeTx(rules, state.NewNoopWriter()); err != nil {
+			return files, err
+		}
+		if traced && txHash != (common.Hash{}) {
+			break
+		}
+	}
+	ibs.SetHooks(nil)
+	return files, nil
+}
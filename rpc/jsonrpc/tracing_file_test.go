@@ -0,0 +1,79 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/log/v3"
+	"github.com/erigontech/erigon/cmd/rpcdaemon/rpcdaemontest"
+	"github.com/erigontech/erigon/eth/ethconfig"
+	tracersConfig "github.com/erigontech/erigon/eth/tracers/config"
+)
+
+func TestStandardTraceBlockToFile(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateTestSentry(t)
+	ethApi := NewEthAPI(newBaseApiForTest(m), m.DB, nil, nil, nil, 5000000, ethconfig.Defaults.RPCTxFeeCap, 100_000, false, 100_000, 128, log.New())
+	traceDir := t.TempDir()
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0, traceDir)
+
+	for _, tt := range debugTraceTransactionTests {
+		txn, err := ethApi.GetTransactionByHash(m.Ctx, common.HexToHash(tt.txHash))
+		require.NoError(t, err)
+		require.NotNil(t, txn)
+
+		// the whole block, one file per transaction
+		files, err := api.StandardTraceBlockToFile(m.Ctx, *txn.BlockHash, nil)
+		require.NoError(t, err)
+		txCount, err := ethApi.GetBlockTransactionCountByHash(m.Ctx, *txn.BlockHash)
+		require.NoError(t, err)
+		require.Len(t, files, int(*txCount))
+
+		// a single transaction of the block
+		files, err = api.StandardTraceBlockToFile(m.Ctx, *txn.BlockHash, &tracersConfig.StdTraceConfig{TxHash: txn.Hash})
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		require.Equal(t, traceDir, filepath.Dir(files[0]))
+
+		data, err := os.ReadFile(files[0])
+		require.NoError(t, err)
+		lines := bytes.Split(bytes.TrimSpace(data), []byte("\n"))
+		require.NotEmpty(t, lines)
+		for _, line := range lines {
+			require.True(t, json.Valid(line), "invalid json line %q", line)
+		}
+		var summary struct {
+			GasUsed *string `json:"gasUsed"`
+			Error   string  `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal(lines[len(lines)-1], &summary))
+		require.NotNil(t, summary.GasUsed)
+		require.Equal(t, tt.failed, summary.Error != "")
+	}
+
+	_, err := api.StandardTraceBlockToFile(m.Ctx, common.HexToHash("0x01"), nil)
+	require.Error(t, err)
+	_, err = api.StandardTraceBadBlockToFile(m.Ctx, common.HexToHash("0x01"), nil)
+	require.Error(t, err)
+}
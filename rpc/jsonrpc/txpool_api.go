@@ -19,8 +19,11 @@ package jsonrpc
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strconv"
 
+	"github.com/holiman/uint256"
+
 	"github.com/erigontech/erigon-db/rawdb"
 	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/common/hexutil"
@@ -28,6 +31,7 @@ import (
 	proto_txpool "github.com/erigontech/erigon-lib/gointerfaces/txpoolproto"
 	"github.com/erigontech/erigon-lib/kv"
 	"github.com/erigontech/erigon-lib/types"
+	"github.com/erigontech/erigon/execution/consensus/misc"
 	"github.com/erigontech/erigon/rpc/ethapi"
 )
 
@@ -35,6 +39,8 @@ import (
 type TxPoolAPI interface {
 	Content(ctx context.Context) (map[string]map[string]map[string]*ethapi.RPCTransaction, error)
 	ContentFrom(ctx context.Context, addr common.Address) (map[string]map[string]*ethapi.RPCTransaction, error)
+	ContentFilter(ctx context.Context, filter TxPoolContentFilter) (map[string]map[string]map[string]*ethapi.RPCTransaction, error)
+	Inspect(ctx context.Context) (map[string]map[string]map[string]string, error)
 }
 
 // TxPoolAPIImpl data structure to store things needed for net_ commands
@@ -53,44 +59,89 @@ func NewTxPoolAPI(base *BaseAPI, db kv.TemporalRoDB, pool proto_txpool.TxpoolCli
 	}
 }
 
-func (api *TxPoolAPIImpl) Content(ctx context.Context) (map[string]map[string]map[string]*ethapi.RPCTransaction, error) {
+// TxPoolContentFilter selects the txns returned by txpool_contentFilter, unset fields match everything
+type TxPoolContentFilter struct {
+	From   *common.Address  `json:"from"`
+	Status []string         `json:"status"` // "pending", "baseFee" or "queued"
+	MinTip *hexutil.Uint64  `json:"minTip"` // minimal effective tip at the pending base fee, in wei
+	Types  []hexutil.Uint64 `json:"types"`  // EIP-2718 txn types, e.g. 0x3 for blob and 0x4 for set code txns
+}
+
+func (f *TxPoolContentFilter) validate() error {
+	for _, status := range f.Status {
+		if status != "pending" && status != "baseFee" && status != "queued" {
+			return fmt.Errorf("unknown status %q, expected pending, baseFee or queued", status)
+		}
+	}
+	for _, t := range f.Types {
+		if t > types.AccountAbstractionTxType {
+			return fmt.Errorf("unknown transaction type %d", t)
+		}
+	}
+	return nil
+}
+
+// match reports whether the txn of sender in the given status is selected, its effective tip is the one at the
+// pending base fee
+func (f *TxPoolContentFilter) match(status string, sender common.Address, txn types.Transaction, pendingBaseFee *uint256.Int) bool {
+	if f == nil {
+		return true
+	}
+	if f.From != nil && *f.From != sender {
+		return false
+	}
+	if len(f.Status) > 0 && !slices.Contains(f.Status, status) {
+		return false
+	}
+	if len(f.Types) > 0 && !slices.Contains(f.Types, hexutil.Uint64(txn.Type())) {
+		return false
+	}
+	if f.MinTip != nil && txn.GetEffectiveGasTip(pendingBaseFee).LtUint64(uint64(*f.MinTip)) {
+		return false
+	}
+	return true
+}
+
+// poolContent fetches the pool txns, grouped by status and sender
+func (api *TxPoolAPIImpl) poolContent(ctx context.Context) (map[string]map[common.Address][]types.Transaction, error) {
 	reply, err := api.pool.All(ctx, &proto_txpool.AllRequest{})
 	if err != nil {
 		return nil, err
 	}
 
-	content := map[string]map[string]map[string]*ethapi.RPCTransaction{
-		"pending": make(map[string]map[string]*ethapi.RPCTransaction),
-		"baseFee": make(map[string]map[string]*ethapi.RPCTransaction),
-		"queued":  make(map[string]map[string]*ethapi.RPCTransaction),
+	content := map[string]map[common.Address][]types.Transaction{
+		"pending": make(map[common.Address][]types.Transaction, 8),
+		"baseFee": make(map[common.Address][]types.Transaction, 8),
+		"queued":  make(map[common.Address][]types.Transaction, 8),
 	}
-
-	pending := make(map[common.Address][]types.Transaction, 8)
-	baseFee := make(map[common.Address][]types.Transaction, 8)
-	queued := make(map[common.Address][]types.Transaction, 8)
 	for i := range reply.Txs {
 		txn, err := types.DecodeWrappedTransaction(reply.Txs[i].RlpTx)
 		if err != nil {
 			return nil, fmt.Errorf("decoding transaction from: %x: %w", reply.Txs[i].RlpTx, err)
 		}
 		addr := gointerfaces.ConvertH160toAddress(reply.Txs[i].Sender)
+		var status string
 		switch reply.Txs[i].TxnType {
 		case proto_txpool.AllReply_PENDING:
-			if _, ok := pending[addr]; !ok {
-				pending[addr] = make([]types.Transaction, 0, 4)
-			}
-			pending[addr] = append(pending[addr], txn)
+			status = "pending"
 		case proto_txpool.AllReply_BASE_FEE:
-			if _, ok := baseFee[addr]; !ok {
-				baseFee[addr] = make([]types.Transaction, 0, 4)
-			}
-			baseFee[addr] = append(baseFee[addr], txn)
+			status = "baseFee"
 		case proto_txpool.AllReply_QUEUED:
-			if _, ok := queued[addr]; !ok {
-				queued[addr] = make([]types.Transaction, 0, 4)
-			}
-			queued[addr] = append(queued[addr], txn)
+			status = "queued"
+		default:
+			continue
 		}
+		content[status][addr] = append(content[status][addr], txn)
+	}
+	return content, nil
+}
+
+// rpcContent fetches the pool txns matching the filter in the txpool_content format, a nil filter matches all of
+// them. The txpool All request has no filters, so the whole pool is transferred and filtered here.
+func (api *TxPoolAPIImpl) rpcContent(ctx context.Context, filter *TxPoolContentFilter) (map[string]map[string]map[string]*ethapi.RPCTransaction, error) {
+	poolContent, err := api.poolContent(ctx)
+	if err != nil {
+		return nil, err
 	}
 
 	tx, err := api.db.BeginTemporalRo(ctx)
@@ -107,100 +158,85 @@ func (api *TxPoolAPIImpl) Content(ctx context.Context) (map[string]map[string]ma
 	if curHeader == nil {
 		return nil, nil
 	}
-	// Flatten the pending transactions
-	for account, txs := range pending {
-		dump := make(map[string]*ethapi.RPCTransaction)
-		for _, txn := range txs {
-			dump[strconv.FormatUint(txn.GetNonce(), 10)] = newRPCPendingTransaction(txn, curHeader, cc)
-		}
-		content["pending"][account.Hex()] = dump
-	}
-	// Flatten the baseFee transactions
-	for account, txs := range baseFee {
-		dump := make(map[string]*ethapi.RPCTransaction)
-		for _, txn := range txs {
-			dump[strconv.FormatUint(txn.GetNonce(), 10)] = newRPCPendingTransaction(txn, curHeader, cc)
-		}
-		content["baseFee"][account.Hex()] = dump
+	var pendingBaseFee *uint256.Int
+	if baseFee := misc.CalcBaseFee(cc, curHeader); baseFee != nil {
+		pendingBaseFee = uint256.MustFromBig(baseFee)
 	}
-	// Flatten the queued transactions
-	for account, txs := range queued {
-		dump := make(map[string]*ethapi.RPCTransaction)
-		for _, txn := range txs {
-			dump[strconv.FormatUint(txn.GetNonce(), 10)] = newRPCPendingTransaction(txn, curHeader, cc)
+
+	content := make(map[string]map[string]map[string]*ethapi.RPCTransaction, len(poolContent))
+	for status, bySender := range poolContent {
+		content[status] = make(map[string]map[string]*ethapi.RPCTransaction, len(bySender))
+		for account, txs := range bySender {
+			dump := make(map[string]*ethapi.RPCTransaction, len(txs))
+			for _, txn := range txs {
+				if filter.match(status, account, txn, pendingBaseFee) {
+					dump[strconv.FormatUint(txn.GetNonce(), 10)] = newRPCPendingTransaction(txn, curHeader, cc)
+				}
+			}
+			if len(dump) > 0 {
+				content[status][account.Hex()] = dump
+			}
 		}
-		content["queued"][account.Hex()] = dump
 	}
 	return content, nil
 }
 
+func (api *TxPoolAPIImpl) Content(ctx context.Context) (map[string]map[string]map[string]*ethapi.RPCTransaction, error) {
+	return api.rpcContent(ctx, nil)
+}
+
 func (api *TxPoolAPIImpl) ContentFrom(ctx context.Context, addr common.Address) (map[string]map[string]*ethapi.RPCTransaction, error) {
-	reply, err := api.pool.All(ctx, &proto_txpool.AllRequest{})
-	if err != nil {
+	bySender, err := api.rpcContent(ctx, &TxPoolContentFilter{From: &addr})
+	if err != nil || bySender == nil {
 		return nil, err
 	}
 
-	content := map[string]map[string]*ethapi.RPCTransaction{
-		"pending": make(map[string]*ethapi.RPCTransaction),
-		"baseFee": make(map[string]*ethapi.RPCTransaction),
-		"queued":  make(map[string]*ethapi.RPCTransaction),
-	}
-
-	pending := make([]types.Transaction, 0, 4)
-	baseFee := make([]types.Transaction, 0, 4)
-	queued := make([]types.Transaction, 0, 4)
-	for i := range reply.Txs {
-		txn, err := types.DecodeWrappedTransaction(reply.Txs[i].RlpTx)
-		if err != nil {
-			return nil, fmt.Errorf("decoding transaction from: %x: %w", reply.Txs[i].RlpTx, err)
-		}
-		sender := gointerfaces.ConvertH160toAddress(reply.Txs[i].Sender)
-		if sender != addr {
-			continue
-		}
-
-		switch reply.Txs[i].TxnType {
-		case proto_txpool.AllReply_PENDING:
-			pending = append(pending, txn)
-		case proto_txpool.AllReply_BASE_FEE:
-			baseFee = append(baseFee, txn)
-		case proto_txpool.AllReply_QUEUED:
-			queued = append(queued, txn)
+	content := make(map[string]map[string]*ethapi.RPCTransaction, len(bySender))
+	for status, txs := range bySender {
+		if dump, ok := txs[addr.Hex()]; ok {
+			content[status] = dump
+		} else {
+			content[status] = make(map[string]*ethapi.RPCTransaction)
 		}
 	}
+	return content, nil
+}
 
-	tx, err := api.db.BeginTemporalRo(ctx)
-	if err != nil {
+// ContentFilter returns the transactions in the pool matching the filter, in the txpool_content format.
+func (api *TxPoolAPIImpl) ContentFilter(ctx context.Context, filter TxPoolContentFilter) (map[string]map[string]map[string]*ethapi.RPCTransaction, error) {
+	if err := filter.validate(); err != nil {
 		return nil, err
 	}
-	defer tx.Rollback()
-	cc, err := api.chainConfig(ctx, tx)
+	return api.rpcContent(ctx, &filter)
+}
+
+// Inspect retrieves the content of the transaction pool and flattens it into an
+// easily inspectable list.
+func (api *TxPoolAPIImpl) Inspect(ctx context.Context) (map[string]map[string]map[string]string, error) {
+	poolContent, err := api.poolContent(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	curHeader := rawdb.ReadCurrentHeader(tx)
-	if curHeader == nil {
-		return nil, nil
-	}
-	// Flatten the pending transactions
-	dump := make(map[string]*ethapi.RPCTransaction)
-	for _, txn := range pending {
-		dump[strconv.FormatUint(txn.GetNonce(), 10)] = newRPCPendingTransaction(txn, curHeader, cc)
-	}
-	content["pending"] = dump
-	// Flatten the baseFee transactions
-	dump = make(map[string]*ethapi.RPCTransaction)
-	for _, txn := range baseFee {
-		dump[strconv.FormatUint(txn.GetNonce(), 10)] = newRPCPendingTransaction(txn, curHeader, cc)
+	// Define a formatter to flatten a transaction into a string
+	format := func(txn types.Transaction) string {
+		if to := txn.GetTo(); to != nil {
+			return fmt.Sprintf("%s: %v wei + %v gas × %v wei", to.Hex(), txn.GetValue(), txn.GetGasLimit(), txn.GetFeeCap())
+		}
+		return fmt.Sprintf("contract creation: %v wei + %v gas × %v wei", txn.GetValue(), txn.GetGasLimit(), txn.GetFeeCap())
 	}
-	content["baseFee"] = dump
-	// Flatten the queued transactions
-	dump = make(map[string]*ethapi.RPCTransaction)
-	for _, txn := range queued {
-		dump[strconv.FormatUint(txn.GetNonce(), 10)] = newRPCPendingTransaction(txn, curHeader, cc)
+
+	content := make(map[string]map[string]map[string]string, len(poolContent))
+	for status, bySender := range poolContent {
+		content[status] = make(map[string]map[string]string, len(bySender))
+		for account, txs := range bySender {
+			dump := make(map[string]string, len(txs))
+			for _, txn := range txs {
+				dump[strconv.FormatUint(txn.GetNonce(), 10)] = format(txn)
+			}
+			content[status][account.Hex()] = dump
+		}
 	}
-	content["queued"] = dump
 	return content, nil
 }
 
@@ -216,41 +252,3 @@ func (api *TxPoolAPIImpl) Status(ctx context.Context) (map[string]hexutil.Uint,
 		"queued":  hexutil.Uint(reply.QueuedCount),
 	}, nil
 }
-
-/*
-
-// Inspect retrieves the content of the transaction pool and flattens it into an
-// easily inspectable list.
-func (s *PublicTxPoolAPI) Inspect() map[string]map[string]map[string]string {
-	content := map[string]map[string]map[string]string{
-		"pending": make(map[string]map[string]string),
-		"queued":  make(map[string]map[string]string),
-	}
-	pending, queue := s.b.TxPoolContent()
-
-	// Define a formatter to flatten a transaction into a string
-	var format = func(tx *types.Transaction) string {
-		if to := tx.To(); to != nil {
-			return fmt.Sprintf("%s: %v wei + %v gas × %v wei", tx.To().Hex(), tx.Value(), tx.Gas(), tx.GasPrice())
-		}
-		return fmt.Sprintf("contract creation: %v wei + %v gas × %v wei", tx.Value(), tx.Gas(), tx.GasPrice())
-	}
-	// Flatten the pending transactions
-	for account, txs := range pending {
-		dump := make(map[string]string)
-		for _, txn := range txs {
-			dump[fmt.Sprintf("%d", tx.Nonce())] = format(tx)
-		}
-		content["pending"][account.Hex()] = dump
-	}
-	// Flatten the queued transactions
-	for account, txs := range queue {
-		dump := make(map[string]string)
-		for _, txn := range txs {
-			dump[fmt.Sprintf("%d", tx.Nonce())] = format(tx)
-		}
-		content["queued"][account.Hex()] = dump
-	}
-	return content
-}
-*/
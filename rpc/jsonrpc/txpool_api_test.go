@@ -72,6 +72,39 @@ func TestTxPoolContent(t *testing.T) {
 	require.Len(content["pending"][sender], 1)
 	require.Equal(expectValue, content["pending"][sender]["0"].Value.ToInt().Uint64())
 
+	contentFrom, err := api.ContentFrom(ctx, m.Address)
+	require.NoError(err)
+	require.Len(contentFrom["pending"], 1)
+	require.Empty(contentFrom["queued"])
+
+	inspect, err := api.Inspect(ctx)
+	require.NoError(err)
+	require.Equal(fmt.Sprintf("%s: 1234 wei + 21000 gas × 10000000000 wei", common.Address{1}.Hex()), inspect["pending"][sender]["0"])
+
+	legacyTxType, dynamicFeeTxType := hexutil.Uint64(types.LegacyTxType), hexutil.Uint64(types.DynamicFeeTxType)
+	minTip, maxTip := hexutil.Uint64(1), hexutil.Uint64(10*common.GWei+1)
+	for _, tt := range []struct {
+		name    string
+		filter  TxPoolContentFilter
+		pending int
+	}{
+		{name: "all", filter: TxPoolContentFilter{}, pending: 1},
+		{name: "sender", filter: TxPoolContentFilter{From: &m.Address}, pending: 1},
+		{name: "other sender", filter: TxPoolContentFilter{From: &common.Address{1}}, pending: 0},
+		{name: "status", filter: TxPoolContentFilter{Status: []string{"pending"}}, pending: 1},
+		{name: "other status", filter: TxPoolContentFilter{Status: []string{"baseFee", "queued"}}, pending: 0},
+		{name: "type", filter: TxPoolContentFilter{Types: []hexutil.Uint64{legacyTxType}}, pending: 1},
+		{name: "other type", filter: TxPoolContentFilter{Types: []hexutil.Uint64{dynamicFeeTxType}}, pending: 0},
+		{name: "min tip", filter: TxPoolContentFilter{MinTip: &minTip}, pending: 1},
+		{name: "tip too low", filter: TxPoolContentFilter{MinTip: &maxTip}, pending: 0},
+	} {
+		filtered, err := api.ContentFilter(ctx, tt.filter)
+		require.NoError(err, tt.name)
+		require.Len(filtered["pending"][sender], tt.pending, tt.name)
+	}
+	_, err = api.ContentFilter(ctx, TxPoolContentFilter{Status: []string{"mined"}})
+	require.Error(err)
+
 	status, err := api.Status(ctx)
 	require.NoError(err)
 	require.Len(status, 3)
@@ -96,3 +96,7 @@ func newRPCServingTimerMS(method string, valid bool) metrics.Summary {
 
 	return metrics.GetOrCreateSummary(label)
 }
+
+func newRateLimitedCounter(method string) metrics.Counter {
+	return metrics.GetOrCreateCounter(fmt.Sprintf(`rpc_rate_limited_total{method="%s"}`, method))
+}
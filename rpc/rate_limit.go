@@ -0,0 +1,181 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/hashicorp/golang-lru/v2/simplelru"
+	"golang.org/x/time/rate"
+)
+
+// Identities the rate limits can be accounted to
+const (
+	RateLimitByIP     = "ip"     // remote IP address of the connection, the default
+	RateLimitByJwt    = "jwt"    // subject of the JWT bearer token signed with RateLimits.JwtSecret, falls back to the IP
+	RateLimitByHeader = "header" // value of the RateLimits.Header request header (API key), falls back to the IP
+)
+
+// maxRateLimitBuckets is the number of (rule, client) buckets kept, the least recently used one is
+// dropped beyond it so that the number of tracked clients doesn't grow unbounded
+const maxRateLimitBuckets = 100_000
+
+// RateLimit is a token bucket applied to the calls of the methods matching Method by a single client.
+// Method is either a full method name or a prefix followed by "*", e.g. "debug_trace*", all the
+// methods matching a prefix share the same bucket.
+type RateLimit struct {
+	Method string  `json:"method"`
+	Rate   float64 `json:"rate"`  // requests per second
+	Burst  int     `json:"burst"` // maximum number of requests at once
+}
+
+// RateLimits configures per-method, per-client rate limiting of a Server. The JWT subject is only
+// used if the token is signed with JwtSecret. Note that the header is taken as sent by the client,
+// it's only meaningful if it's validated before reaching the server (e.g. by a proxy).
+type RateLimits struct {
+	Identity      string      `json:"identity"`      // one of RateLimitByIP, RateLimitByJwt, RateLimitByHeader
+	Header        string      `json:"header"`        // header holding the API key, for RateLimitByHeader
+	JwtSecretPath string      `json:"jwtSecretPath"` // hex encoded HS256 secret of the tokens, for RateLimitByJwt
+	Limits        []RateLimit `json:"limits"`
+
+	JwtSecret []byte `json:"-"` // read from JwtSecretPath
+}
+
+func (l *RateLimits) validate() error {
+	switch l.Identity {
+	case "", RateLimitByIP:
+	case RateLimitByJwt:
+		if len(l.JwtSecret) == 0 {
+			return fmt.Errorf("rate limit identity %q requires a JWT secret", l.Identity)
+		}
+	case RateLimitByHeader:
+		if l.Header == "" {
+			return fmt.Errorf("rate limit identity %q requires a header", l.Identity)
+		}
+	default:
+		return fmt.Errorf("unknown rate limit identity %q", l.Identity)
+	}
+	for _, limit := range l.Limits {
+		if limit.Method == "" || strings.Contains(strings.TrimSuffix(limit.Method, "*"), "*") {
+			return fmt.Errorf("invalid rate limit method %q", limit.Method)
+		}
+		if limit.Rate <= 0 || limit.Burst <= 0 {
+			return fmt.Errorf("rate limit of %s: rate and burst must be positive", limit.Method)
+		}
+	}
+	return nil
+}
+
+type rateLimitKey struct {
+	rule     int // index in RateLimits.Limits
+	identity string
+}
+
+// rateLimiter holds a token bucket per rule and client. It's shared by all the connections of a
+// Server.
+type rateLimiter struct {
+	cfg RateLimits
+
+	lock    sync.Mutex
+	buckets *simplelru.LRU[rateLimitKey, *rate.Limiter]
+}
+
+func newRateLimiter(cfg RateLimits) (*rateLimiter, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	buckets, err := simplelru.NewLRU[rateLimitKey, *rate.Limiter](maxRateLimitBuckets, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &rateLimiter{cfg: cfg, buckets: buckets}, nil
+}
+
+// rule returns the index of the first rule matching the method, -1 if the method is not limited
+func (l *rateLimiter) rule(method string) int {
+	for i, limit := range l.cfg.Limits {
+		if prefix, ok := strings.CutSuffix(limit.Method, "*"); ok {
+			if strings.HasPrefix(method, prefix) {
+				return i
+			}
+		} else if limit.Method == method {
+			return i
+		}
+	}
+	return -1
+}
+
+// identify returns the identity of the client sending the request, as configured
+func (l *rateLimiter) identify(r *http.Request) string {
+	switch l.cfg.Identity {
+	case RateLimitByJwt:
+		if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			claims := jwt.RegisteredClaims{}
+			keyFunc := func(token *jwt.Token) (interface{}, error) {
+				return l.cfg.JwtSecret, nil
+			}
+			token, err := jwt.ParseWithClaims(strings.TrimPrefix(auth, "Bearer "), &claims, keyFunc, jwt.WithValidMethods([]string{"HS256"}))
+			if err == nil && token.Valid && claims.Subject != "" {
+				return "jwt:" + claims.Subject
+			}
+		}
+	case RateLimitByHeader:
+		if key := r.Header.Get(l.cfg.Header); key != "" {
+			return "key:" + key
+		}
+	}
+	return ""
+}
+
+// allow takes a token from the bucket of the client calling the method, it returns false if there
+// is none left
+func (l *rateLimiter) allow(ctx context.Context, method string) bool {
+	rule := l.rule(method)
+	if rule < 0 {
+		return true
+	}
+	key := rateLimitKey{rule: rule, identity: clientIdentity(PeerInfoFromContext(ctx))}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	bucket, ok := l.buckets.Get(key)
+	if !ok {
+		limit := l.cfg.Limits[rule]
+		bucket = rate.NewLimiter(rate.Limit(limit.Rate), limit.Burst)
+		l.buckets.Add(key, bucket)
+	}
+	return bucket.AllowN(time.Now(), 1)
+}
+
+// clientIdentity returns the identity resolved when the connection was accepted, or the IP of the
+// client. IPC connections all share the same identity.
+func clientIdentity(info PeerInfo) string {
+	if info.Identity != "" {
+		return info.Identity
+	}
+	if host, _, err := net.SplitHostPort(info.RemoteAddr); err == nil {
+		return "ip:" + host
+	}
+	return info.Transport + ":" + info.RemoteAddr
+}
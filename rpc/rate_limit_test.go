@@ -0,0 +1,163 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/log/v3"
+)
+
+func requireRateLimited(t *testing.T, err error) {
+	t.Helper()
+	var rpcErr Error
+	require.True(t, errors.As(err, &rpcErr), "unexpected error %v", err)
+	require.Equal(t, -32005, rpcErr.ErrorCode())
+}
+
+func TestRateLimitsValidation(t *testing.T) {
+	require.NoError(t, (&RateLimits{Limits: []RateLimit{{Method: "debug_trace*", Rate: 1, Burst: 1}}}).validate())
+	require.Error(t, (&RateLimits{Identity: "cookie"}).validate())
+	require.Error(t, (&RateLimits{Identity: RateLimitByHeader}).validate())
+	require.Error(t, (&RateLimits{Identity: RateLimitByJwt}).validate())
+	require.Error(t, (&RateLimits{Limits: []RateLimit{{Method: "debug_*_block", Rate: 1, Burst: 1}}}).validate())
+	require.Error(t, (&RateLimits{Limits: []RateLimit{{Method: "eth_getLogs", Rate: 1}}}).validate())
+}
+
+func TestRateLimitsHTTP(t *testing.T) {
+	logger := log.New()
+	s := newTestServer(logger)
+	defer s.Stop()
+	require.NoError(t, s.SetRateLimits(RateLimits{
+		Identity: RateLimitByHeader,
+		Header:   "X-Api-Key",
+		Limits:   []RateLimit{{Method: "test_echo*", Rate: 0.001, Burst: 2}},
+	}))
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	dial := func(apiKey string) *Client {
+		c, err := DialHTTP(ts.URL, logger)
+		require.NoError(t, err)
+		c.SetHeader("X-Api-Key", apiKey)
+		return c
+	}
+	c1, c2 := dial("one"), dial("two")
+	defer c1.Close()
+	defer c2.Close()
+
+	var res echoResult
+	require.NoError(t, c1.Call(&res, "test_echo", "x", 1))
+	require.NoError(t, c1.Call(&res, "test_echoWithCtx", "x", 1))
+	// the prefix shares the bucket of the client
+	requireRateLimited(t, c1.Call(&res, "test_echo", "x", 1))
+	requireRateLimited(t, c1.Call(&res, "test_echoWithCtx", "x", 1))
+	// other methods and other clients are not affected
+	var rets string
+	require.NoError(t, c1.Call(&rets, "test_rets"))
+	require.NoError(t, c2.Call(&res, "test_echo", "x", 1))
+
+	// every call of a batch takes a token
+	batch := []BatchElem{
+		{Method: "test_echo", Args: []interface{}{"x", 1}, Result: new(echoResult)},
+		{Method: "test_echo", Args: []interface{}{"x", 1}, Result: new(echoResult)},
+	}
+	require.NoError(t, c2.BatchCall(batch))
+	// the calls of a batch run concurrently, either can be rejected
+	if batch[0].Error == nil {
+		requireRateLimited(t, batch[1].Error)
+	} else {
+		requireRateLimited(t, batch[0].Error)
+		require.NoError(t, batch[1].Error)
+	}
+}
+
+func TestRateLimitsWebsocket(t *testing.T) {
+	logger := log.New()
+	s := newTestServer(logger)
+	defer s.Stop()
+	require.NoError(t, s.SetRateLimits(RateLimits{
+		Limits: []RateLimit{{Method: "test_echo", Rate: 0.001, Burst: 1}},
+	}))
+	ts := httptest.NewServer(s.WebsocketHandler([]string{"*"}, nil, false, logger))
+	defer ts.Close()
+	wsURL := "ws:" + strings.TrimPrefix(ts.URL, "http:")
+
+	c1, err := DialWebsocket(context.Background(), wsURL, "", logger)
+	require.NoError(t, err)
+	defer c1.Close()
+	c2, err := DialWebsocket(context.Background(), wsURL, "", logger)
+	require.NoError(t, err)
+	defer c2.Close()
+
+	// both connections come from the same IP, so share the bucket
+	var res echoResult
+	require.NoError(t, c1.Call(&res, "test_echo", "x", 1))
+	requireRateLimited(t, c1.Call(&res, "test_echo", "x", 1))
+	requireRateLimited(t, c2.Call(&res, "test_echo", "x", 1))
+}
+
+func TestRateLimitsJwt(t *testing.T) {
+	secret := []byte("rate limits secret")
+	l, err := newRateLimiter(RateLimits{Identity: RateLimitByJwt, JwtSecret: secret})
+	require.NoError(t, err)
+
+	token := func(key []byte, subject string) string {
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{Subject: subject}).SignedString(key)
+		require.NoError(t, err)
+		return signed
+	}
+	identify := func(token string) string {
+		r := httptest.NewRequest("POST", "/", nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+		return l.identify(r)
+	}
+	require.Equal(t, "jwt:alice", identify(token(secret, "alice")))
+	// tokens which are not signed with the secret fall back to the IP
+	require.Empty(t, identify(token([]byte("another secret"), "alice")))
+	expired, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   "alice",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+	}).SignedString(secret)
+	require.NoError(t, err)
+	require.Empty(t, identify(expired))
+}
+
+func TestRateLimitsBucketsBounded(t *testing.T) {
+	l, err := newRateLimiter(RateLimits{Limits: []RateLimit{{Method: "test_echo", Rate: 0.001, Burst: 1}}})
+	require.NoError(t, err)
+	client := func(i int) context.Context {
+		return context.WithValue(context.Background(), peerInfoContextKey{}, PeerInfo{Transport: "http", RemoteAddr: fmt.Sprintf("10.0.0.%d:1234", i)})
+	}
+	require.True(t, l.allow(client(0), "test_echo"))
+	require.False(t, l.allow(client(0), "test_echo"))
+	for i := 1; i <= maxRateLimitBuckets; i++ {
+		l.allow(client(i), "test_echo")
+	}
+	require.Equal(t, maxRateLimitBuckets, l.buckets.Len())
+	// the least recently used client was dropped, and starts over with a full bucket
+	require.True(t, l.allow(client(0), "test_echo"))
+}
@@ -51,6 +51,7 @@ const (
 type Server struct {
 	services        serviceRegistry
 	methodAllowList AllowList
+	rateLimiter     *rateLimiter
 	idgen           func() ID
 	run             int32
 	codecs          mapset.Set // mapset.Set[ServerCodec] requires go 1.20
@@ -80,6 +81,16 @@ func (s *Server) SetAllowList(allowList AllowList) {
 	s.methodAllowList = allowList
 }
 
+// SetRateLimits sets the per-method, per-client rate limits of the calls handled by this server
+func (s *Server) SetRateLimits(limits RateLimits) error {
+	rateLimiter, err := newRateLimiter(limits)
+	if err != nil {
+		return err
+	}
+	s.rateLimiter = rateLimiter
+	return nil
+}
+
 // SetBatchLimit sets limit of number of requests in a batch
 func (s *Server) SetBatchLimit(limit int) {
 	s.batchLimit = limit
@@ -110,7 +121,7 @@ func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) {
 	s.codecs.Add(codec)
 	defer s.codecs.Remove(codec)
 
-	c := initClient(codec, s.idgen, &s.services, s.logger)
+	c := initClient(codec, s.idgen, &s.services, s.rateLimiter, s.logger)
 	<-codec.closed()
 	c.Close()
 }
@@ -124,7 +135,7 @@ func (s *Server) serveSingleRequest(ctx context.Context, codec ServerCodec, stre
 		return
 	}
 
-	h := newHandler(ctx, codec, s.idgen, &s.services, s.methodAllowList, s.batchConcurrency, s.traceRequests, s.logger, s.rpcSlowLogThreshold)
+	h := newHandler(ctx, codec, s.idgen, &s.services, s.methodAllowList, s.rateLimiter, s.batchConcurrency, s.traceRequests, s.logger, s.rpcSlowLogThreshold)
 	h.allowSubscribe = false
 	defer h.close(io.EOF, nil)
 
@@ -190,6 +201,10 @@ type PeerInfo struct {
 	// Address of client. This will usually contain the IP address and port.
 	RemoteAddr string
 
+	// Identity of the client the rate limits are accounted to, see RateLimits.
+	// Empty if the identity is the IP address.
+	Identity string
+
 	// Additional information for HTTP and WebSocket connections.
 	HTTP struct {
 		// Protocol version, i.e. "HTTP/1.1". This is not set for WebSocket.
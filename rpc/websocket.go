@@ -68,6 +68,9 @@ func (s *Server) WebsocketHandler(allowedOrigins []string, jwtSecret []byte, com
 			return
 		}
 		codec := NewWebsocketCodec(conn, r.Host, r.Header)
+		if s.rateLimiter != nil {
+			codec.(*websocketCodec).info.Identity = s.rateLimiter.identify(r)
+		}
 		s.ServeCodec(codec, 0)
 	})
 }
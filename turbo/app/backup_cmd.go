@@ -0,0 +1,136 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package app
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/c2h5oh/datasize"
+	"github.com/urfave/cli/v2"
+
+	"github.com/erigontech/erigon-lib/common/datadir"
+	"github.com/erigontech/erigon-lib/common/dir"
+	"github.com/erigontech/erigon-lib/kv/backup"
+	"github.com/erigontech/erigon/cmd/utils"
+	"github.com/erigontech/erigon/turbo/debug"
+)
+
+var BackupDirFlag = cli.PathFlag{
+	Name:  "backup.dir",
+	Usage: "Folder keeping the backups, every backup is a sub-folder of it",
+}
+
+var backupCommand = cli.Command{
+	Action: MigrateFlags(doBackup),
+	Name:   "backup",
+	Usage:  "Incremental backup of chaindata and snapshot files",
+	Flags:  joinFlags([]cli.Flag{&utils.DataDirFlag, &BackupDirFlag}),
+	Description: `
+Copies chaindata and the snapshot files of the datadir into a new sub-folder of --backup.dir, together with a
+manifest of their sizes and hashes. Snapshot files which didn't change since the previous backup are hardlinked
+from it, so only new files are copied. Can run while the node is running.
+
+Other folders of the datadir (txpool, nodes, caplin, downloader) are not backed up, the node re-creates them.`,
+	Subcommands: []*cli.Command{
+		{
+			Name:      "verify",
+			Action:    doBackupVerify,
+			Usage:     "Check the files of a backup against its manifest",
+			ArgsUsage: "<backup folder, or --backup.dir of the backup command for its latest backup>",
+			Flags:     joinFlags([]cli.Flag{}),
+		},
+	},
+}
+
+var restoreCommand = cli.Command{
+	Action:    MigrateFlags(doRestore),
+	Name:      "restore",
+	Usage:     "Restore a backup made by the backup command into a new datadir",
+	ArgsUsage: "<backup folder, or --backup.dir of the backup command for its latest backup>",
+	Flags:     joinFlags([]cli.Flag{&utils.DataDirFlag}),
+	Description: `
+Puts the files of the backup into --datadir, checking every file against the backup manifest. The datadir must not
+have any of them yet. Snapshot files are hardlinked if the backup is on the same filesystem.`,
+}
+
+func doBackup(cliCtx *cli.Context) error {
+	logger, _, _, _, err := debug.Setup(cliCtx, true /* rootLogger */)
+	if err != nil {
+		return err
+	}
+	if !cliCtx.IsSet(BackupDirFlag.Name) {
+		return fmt.Errorf("--%s is required", BackupDirFlag.Name)
+	}
+	dirs := datadir.New(cliCtx.String(utils.DataDirFlag.Name))
+	_, err = backup.Datadir(cliCtx.Context, dirs, cliCtx.String(BackupDirFlag.Name), logger)
+	return err
+}
+
+func doBackupVerify(cliCtx *cli.Context) error {
+	logger, _, _, _, err := debug.Setup(cliCtx, true /* rootLogger */)
+	if err != nil {
+		return err
+	}
+	backupDir, err := backupFolder(cliCtx.Args().First())
+	if err != nil {
+		return err
+	}
+	m, err := backup.Verify(cliCtx.Context, backupDir, logger)
+	if err != nil {
+		return err
+	}
+	logger.Info("[backup] verified", "dir", backupDir, "created", m.Created, "files", len(m.Files), "size", datasize.ByteSize(m.Size()).HR())
+	return nil
+}
+
+func doRestore(cliCtx *cli.Context) error {
+	logger, _, _, _, err := debug.Setup(cliCtx, true /* rootLogger */)
+	if err != nil {
+		return err
+	}
+	backupDir, err := backupFolder(cliCtx.Args().First())
+	if err != nil {
+		return err
+	}
+
+	dirs, l, err := datadir.New(cliCtx.String(utils.DataDirFlag.Name)).MustFlock()
+	if err != nil {
+		return err
+	}
+	defer l.Unlock()
+	return backup.Restore(cliCtx.Context, backupDir, dirs.DataDir, logger)
+}
+
+// backupFolder returns path if it's a backup, or the latest backup in it.
+func backupFolder(path string) (string, error) {
+	if path == "" {
+		return "", errors.New("expecting backup folder as a first argument")
+	}
+	if exists, err := dir.FileExist(filepath.Join(path, backup.ManifestFileName)); err != nil || exists {
+		return path, err
+	}
+	latest, err := backup.LatestBackup(path)
+	if err != nil {
+		return "", err
+	}
+	if latest == "" {
+		return "", fmt.Errorf("no backup in %s", path)
+	}
+	return latest, nil
+}
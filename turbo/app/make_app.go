@@ -82,7 +82,8 @@ func MakeApp(name string, action cli.ActionFunc, cliFlags []cli.Flag) *cli.App {
 		&importCommand,
 		&snapshotCommand,
 		&supportCommand,
-		//&backupCommand,
+		&backupCommand,
+		&restoreCommand,
 	}
 	return app
 }
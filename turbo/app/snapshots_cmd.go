@@ -203,7 +203,10 @@ var snapshotCommand = cli.Command{
 		{
 			Name:   "compress",
 			Action: doCompress,
-			Flags:  joinFlags([]cli.Flag{&utils.DataDirFlag}),
+			Flags: joinFlags([]cli.Flag{
+				&utils.DataDirFlag,
+				&cli.StringFlag{Name: "codec", Value: seg.CodecHuffman.String(), Usage: "huffman or zstd (tuned by ZstdLevel and ZstdDictSize env variables)"},
+			}),
 		},
 		{
 			Name:   "decompress-speed",
@@ -1189,6 +1192,8 @@ func doDecompressSpeed(cliCtx *cli.Context) error {
 		return err
 	}
 	defer decompressor.Close()
+	logger.Info("decompress speed", "file", decompressor.FileName(), "codec", decompressor.Codec(), "size", datasize.ByteSize(decompressor.Size()).HR(),
+		"words", decompressor.Count(), "dict", datasize.ByteSize(decompressor.SerializedDictSize()).HR())
 	func() {
 		defer decompressor.MadvSequential().DisableReadAhead()
 
@@ -1450,6 +1455,11 @@ func doCompress(cliCtx *cli.Context) error {
 	compressCfg.SamplingFactor = uint64(dbg.EnvInt("SamplingFactor", int(compressCfg.SamplingFactor)))
 	compressCfg.DictReducerSoftLimit = dbg.EnvInt("DictReducerSoftLimit", compressCfg.DictReducerSoftLimit)
 	compressCfg.MaxDictPatterns = dbg.EnvInt("MaxDictPatterns", compressCfg.MaxDictPatterns)
+	if compressCfg.Codec, err = seg.ParseCodec(cliCtx.String("codec")); err != nil {
+		return err
+	}
+	compressCfg.ZstdLevel = dbg.EnvInt("ZstdLevel", compressCfg.ZstdLevel)
+	compressCfg.ZstdDictSize = dbg.EnvInt("ZstdDictSize", compressCfg.ZstdDictSize)
 	compression := seg.CompressKeys | seg.CompressVals
 	if dbg.EnvBool("OnlyKeys", false) {
 		compression = seg.CompressKeys
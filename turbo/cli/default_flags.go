@@ -39,6 +39,9 @@ var DefaultFlags = []cli.Flag{
 	&utils.TxPoolGlobalQueueFlag,
 	&utils.TxPoolTraceSendersFlag,
 	&utils.TxPoolCommitEveryFlag,
+	&utils.TxPoolLocalsFlag,
+	&utils.TxPoolJournalFlag,
+	&utils.TxPoolRejournalFlag,
 	&PruneDistanceFlag,
 	&PruneBlocksDistanceFlag,
 	&PruneModeFlag,
@@ -79,7 +82,9 @@ var DefaultFlags = []cli.Flag{
 	&utils.RpcStreamingDisableFlag,
 	&utils.DBReadConcurrencyFlag,
 	&utils.RpcAccessListFlag,
+	&utils.RpcRateLimitsFlag,
 	&utils.RpcTraceCompatFlag,
+	&utils.RpcTraceDirFlag,
 	&utils.RpcGasCapFlag,
 	&utils.RpcBatchLimit,
 	&utils.RpcReturnDataLimit,
@@ -109,6 +114,7 @@ var DefaultFlags = []cli.Flag{
 	&utils.SnapStopFlag,
 	&utils.SnapStateStopFlag,
 	&utils.SnapSkipStateSnapshotDownloadFlag,
+	&utils.SnapCodecFlag,
 	&utils.DbPageSizeFlag,
 	&utils.DbSizeLimitFlag,
 	&utils.DbWriteMapFlag,
@@ -122,6 +128,7 @@ var DefaultFlags = []cli.Flag{
 	&utils.TorrentVerbosityFlag,
 	&utils.ListenPortFlag,
 	&utils.P2pProtocolVersionFlag,
+	&utils.P2pSnapServeFlag,
 	&utils.P2pProtocolAllowedPorts,
 	&utils.NATFlag,
 	&utils.NoDiscoverFlag,
@@ -226,9 +233,22 @@ var DefaultFlags = []cli.Flag{
 
 	&utils.CaplinDisableBlobPruningFlag,
 	&utils.CaplinDisableCheckpointSyncFlag,
+	&utils.CaplinCheckpointSyncStateFileFlag,
+	&utils.CaplinCheckpointSyncBlockFileFlag,
+	&utils.CaplinCheckpointSyncBlockRootFlag,
 	&utils.CaplinEnableSnapshotGeneration,
 	&utils.CaplinMevRelayUrl,
+	&utils.CaplinMevRelayTimeoutFlag,
+	&utils.CaplinMevMinBidFlag,
 	&utils.CaplinValidatorMonitorFlag,
+	&utils.CaplinValidatorKeystoresDirFlag,
+	&utils.CaplinValidatorPasswordsFlag,
+	&utils.CaplinValidatorWeb3SignerUrlFlag,
+	&utils.CaplinValidatorWeb3SignerKeysFlag,
+	&utils.CaplinValidatorFeeRecipientFlag,
+	&utils.CaplinValidatorGraffitiFlag,
+	&utils.CaplinKeymanagerAddrFlag,
+	&utils.CaplinKeymanagerJwtSecretFlag,
 	&utils.CaplinCustomConfigFlag,
 	&utils.CaplinCustomGenesisFlag,
 	&utils.CaplinUseEngineApiFlag,
@@ -28,6 +28,7 @@ var ourCapabilities = []string{
 	"engine_getPayloadBodiesByRangeV1",
 	"engine_getClientVersionV1",
 	"engine_getBlobsV1",
+	"engine_getBlobsV2",
 }
 
 // Returns the most recent version of the payload(for the payloadID) at the time of receiving the call
@@ -172,3 +173,10 @@ func (e *EngineServer) GetBlobsV1(ctx context.Context, blobHashes []common.Hash)
 	e.logger.Debug("[GetBlobsV1] Received Request", "hashes", len(blobHashes))
 	return e.getBlobs(ctx, blobHashes)
 }
+
+// Returns the blobs with their cell proofs, or nil if any of them is missing
+// See https://github.com/ethereum/execution-apis/blob/main/src/engine/osaka.md#engine_getblobsv2
+func (e *EngineServer) GetBlobsV2(ctx context.Context, blobHashes []common.Hash) ([]*engine_types.BlobAndProofV2, error) {
+	e.logger.Debug("[GetBlobsV2] Received Request", "hashes", len(blobHashes))
+	return e.getBlobsV2(ctx, blobHashes)
+}
@@ -25,11 +25,14 @@ import (
 	"sync/atomic"
 	"time"
 
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+
 	"github.com/erigontech/erigon-lib/chain"
 	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/common/empty"
 	"github.com/erigontech/erigon-lib/common/hexutil"
 	"github.com/erigontech/erigon-lib/common/math"
+	"github.com/erigontech/erigon-lib/crypto/kzg"
 	"github.com/erigontech/erigon-lib/gointerfaces"
 	execution "github.com/erigontech/erigon-lib/gointerfaces/executionproto"
 	txpool "github.com/erigontech/erigon-lib/gointerfaces/txpoolproto"
@@ -913,7 +916,8 @@ func (e *EngineServer) SetConsuming(consuming bool) {
 	e.consuming.Store(consuming)
 }
 
-func (e *EngineServer) getBlobs(ctx context.Context, blobHashes []common.Hash) ([]*engine_types.BlobAndProofV1, error) {
+// getBlobsFromTxPool returns nil reply if the txpool answered with an unexpected number of blobs or proofs
+func (e *EngineServer) getBlobsFromTxPool(ctx context.Context, blobHashes []common.Hash) (*txpool.GetBlobsReply, error) {
 	if len(blobHashes) > 128 {
 		return nil, &engine_helpers.TooLargeRequestErr
 	}
@@ -925,24 +929,64 @@ func (e *EngineServer) getBlobs(ctx context.Context, blobHashes []common.Hash) (
 	if err != nil {
 		return nil, err
 	}
-	ret := make([]*engine_types.BlobAndProofV1, len(blobHashes))
 	if len(blobHashes) != len(res.Blobs) || len(blobHashes) != len(res.Proofs) { // Some fault in the underlying txpool, but still return sane resp
-		log.Warn("[GetBlobsV1] txpool returned unexpected number of blobs and proofs in response, returning nil blobs list")
+		log.Warn("[GetBlobs] txpool returned unexpected number of blobs and proofs in response, returning nil blobs list")
+		return nil, nil
+	}
+	return res, nil
+}
+
+func (e *EngineServer) getBlobs(ctx context.Context, blobHashes []common.Hash) ([]*engine_types.BlobAndProofV1, error) {
+	res, err := e.getBlobsFromTxPool(ctx, blobHashes)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*engine_types.BlobAndProofV1, len(blobHashes))
+	if res == nil {
 		return ret, nil
 	}
 	logLine := []string{}
 	for i := range res.Blobs {
-		if res.Blobs[i] != nil {
-			ret[i] = &engine_types.BlobAndProofV1{Blob: res.Blobs[i], Proof: res.Proofs[i]}
-			logLine = append(logLine, fmt.Sprintf(" %d:", i), fmt.Sprintf(" hash=%x len(blob)=%d len(proof)=%d ", blobHashes[i], len(res.Blobs[i]), len(res.Proofs[i])))
-		} else {
+		if res.Blobs[i] == nil {
 			logLine = append(logLine, fmt.Sprintf(" %d:", i), " nil")
+			continue
+		}
+		// blobs of txns wrapped with cell proofs have no blob proof to return, engine_getBlobsV2 serves them
+		if len(res.Proofs[i]) != gokzg4844.CompressedG1Size {
+			return nil, &rpc.UnsupportedForkError{Message: fmt.Sprintf("blob %x has cell proofs, use engine_getBlobsV2", blobHashes[i])}
 		}
+		ret[i] = &engine_types.BlobAndProofV1{Blob: res.Blobs[i], Proof: res.Proofs[i]}
+		logLine = append(logLine, fmt.Sprintf(" %d:", i), fmt.Sprintf(" hash=%x len(blob)=%d len(proof)=%d ", blobHashes[i], len(res.Blobs[i]), len(res.Proofs[i])))
 	}
 	e.logger.Debug("[GetBlobsV1]", "Responses", logLine)
 	return ret, nil
 }
 
+// getBlobsV2 is all or nothing: it returns nil if any of the blobs is missing or has no cell proofs,
+// in which case the CL has to sample the data columns from its peers.
+func (e *EngineServer) getBlobsV2(ctx context.Context, blobHashes []common.Hash) ([]*engine_types.BlobAndProofV2, error) {
+	res, err := e.getBlobsFromTxPool(ctx, blobHashes)
+	if err != nil || res == nil {
+		return nil, err
+	}
+	const proofSize = gokzg4844.CompressedG1Size
+	ret := make([]*engine_types.BlobAndProofV2, len(blobHashes))
+	for i := range res.Blobs {
+		// the txpool concatenates the cell proofs of a blob
+		if res.Blobs[i] == nil || len(res.Proofs[i]) != kzg.CellsPerBlob*proofSize {
+			e.logger.Debug("[GetBlobsV2] blob is missing", "index", i, "hash", blobHashes[i], "len(proof)", len(res.Proofs[i]))
+			return nil, nil
+		}
+		proofs := make([]hexutil.Bytes, kzg.CellsPerBlob)
+		for j := range proofs {
+			proofs[j] = res.Proofs[i][j*proofSize : (j+1)*proofSize]
+		}
+		ret[i] = &engine_types.BlobAndProofV2{Blob: res.Blobs[i], CellProofs: proofs}
+	}
+	e.logger.Debug("[GetBlobsV2]", "Responses", len(ret))
+	return ret, nil
+}
+
 func waitForStuff(maxWait time.Duration, waitCondnF func() (bool, error)) (bool, error) {
 	shouldWait, err := waitCondnF()
 	if err != nil || !shouldWait {
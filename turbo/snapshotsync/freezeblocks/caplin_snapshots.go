@@ -428,6 +428,7 @@ func dumpBeaconBlocksRange(ctx context.Context, db kv.RoDB, fromSlot uint64, toS
 
 	compressCfg := seg.DefaultCfg
 	compressCfg.Workers = workers
+	compressCfg.Codec = snaptype.CodecOf(snaptype.BeaconBlocks.Enum())
 	sn, err := seg.NewCompressor(ctx, "Snapshot BeaconBlocks", f.Path, tmpDir, compressCfg, lvl, logger)
 	if err != nil {
 		return err
@@ -502,6 +503,7 @@ func DumpBlobSidecarsRange(ctx context.Context, db kv.RoDB, storage blob_storage
 
 	compressCfg := seg.DefaultCfg
 	compressCfg.Workers = workers
+	compressCfg.Codec = snaptype.CodecOf(snaptype.BlobSidecars.Enum())
 	sn, err := seg.NewCompressor(ctx, "Snapshot BlobSidecars", f.Path, tmpDir, compressCfg, lvl, logger)
 	if err != nil {
 		return err
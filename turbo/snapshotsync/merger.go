@@ -270,6 +270,7 @@ func (m *Merger) merge(ctx context.Context, v *View, toMerge []*DirtySegment, ta
 
 	compresCfg := seg.DefaultCfg
 	compresCfg.Workers = m.compressWorkers
+	compresCfg.Codec = snaptype.CodecOf(targetFile.Type.Enum())
 	f, err := seg.NewCompressor(ctx, "Snapshots merge", targetFile.Path, m.tmpDir, compresCfg, log.LvlTrace, m.logger)
 	if err != nil {
 		return nil, err
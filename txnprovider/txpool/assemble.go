@@ -68,6 +68,7 @@ func Assemble(
 	if cfg.OverridePragueTime != nil {
 		pragueTime = cfg.OverridePragueTime
 	}
+	osakaTime := chainConfig.OsakaTime
 
 	newTxns := make(chan Announcements, 1024)
 	newSlotsStreams := &NewSlotsStreams{}
@@ -83,6 +84,7 @@ func Assemble(
 		agraBlock,
 		cancunTime,
 		pragueTime,
+		osakaTime,
 		chainConfig.BlobSchedule,
 		sentryClients,
 		stateChangesClient,
@@ -0,0 +1,137 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/erigontech/erigon-lib/common"
+	"github.com/erigontech/erigon-lib/rlp"
+)
+
+// errNoActiveJournal is returned if a txn is attempted to be inserted into the journal before it
+// has been opened by the first rotation.
+var errNoActiveJournal = errors.New("no active journal")
+
+// journalEntry is a local txn as persisted in the journal: the sender and the txn in the same
+// form as in the pool db (without envelope, wrapped with blobs)
+type journalEntry struct {
+	Sender common.Address
+	Rlp    []byte
+}
+
+// localsJournal is an append-only file of local txns. Unlike the pool db, which holds any txn and
+// is flushed periodically, it only holds txns the node owner cares about and every txn is written
+// as soon as it's accepted. It is regenerated from the pool periodically to drop included txns.
+type localsJournal struct {
+	path   string
+	writer *os.File
+}
+
+func newLocalsJournal(path string) *localsJournal {
+	return &localsJournal{path: path}
+}
+
+// load reads all the entries of the journal. A missing journal is not an error. A damaged tail
+// (e.g. a write cut by a crash) stops the loading, the entries read so far are returned along
+// with the error.
+func (j *localsJournal) load() ([]journalEntry, error) {
+	f, err := os.Open(j.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []journalEntry
+	stream := rlp.NewStream(bufio.NewReader(f), 0)
+	for {
+		var entry journalEntry
+		if err := stream.Decode(&entry); err != nil {
+			if errors.Is(err, io.EOF) {
+				return entries, nil
+			}
+			return entries, fmt.Errorf("journal entry %d: %w", len(entries), err)
+		}
+		entries = append(entries, entry)
+	}
+}
+
+// insert appends the txn to the journal
+func (j *localsJournal) insert(entry journalEntry) error {
+	if j.writer == nil {
+		return errNoActiveJournal
+	}
+	return rlp.Encode(j.writer, &entry)
+}
+
+// rotate replaces the journal with the given entries, which are all the local txns currently
+// in the pool, and opens it for appending
+func (j *localsJournal) rotate(entries []journalEntry) error {
+	if j.writer != nil {
+		if err := j.writer.Close(); err != nil {
+			return err
+		}
+		j.writer = nil
+	}
+
+	tmpPath := j.path + ".new"
+	tmp, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(tmp)
+	for i := range entries {
+		if err = rlp.Encode(w, &entries[i]); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err = w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmpPath, j.path); err != nil {
+		return err
+	}
+
+	j.writer, err = os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	return err
+}
+
+func (j *localsJournal) close() error {
+	if j.writer == nil {
+		return nil
+	}
+	err := j.writer.Close()
+	j.writer = nil
+	return err
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 The Erigon Authors
+// This file is part of Erigon.
+//
+// Erigon is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Erigon is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Erigon. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/erigontech/erigon-lib/common"
+)
+
+func TestLocalsJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "transactions.rlp")
+	journal := newLocalsJournal(path)
+
+	// missing journal is empty
+	entries, err := journal.load()
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	entry1 := journalEntry{Sender: common.HexToAddress("0x01"), Rlp: []byte{0x01, 0x02}}
+	entry2 := journalEntry{Sender: common.HexToAddress("0x02"), Rlp: []byte{0x03}}
+	entry3 := journalEntry{Sender: common.HexToAddress("0x03"), Rlp: []byte{0x04, 0x05, 0x06}}
+
+	require.ErrorIs(t, journal.insert(entry1), errNoActiveJournal)
+	require.NoError(t, journal.rotate([]journalEntry{entry1}))
+	require.NoError(t, journal.insert(entry2))
+	require.NoError(t, journal.insert(entry3))
+	require.NoError(t, journal.close())
+
+	entries, err = journal.load()
+	require.NoError(t, err)
+	require.Equal(t, []journalEntry{entry1, entry2, entry3}, entries)
+
+	// rotation drops the entries which are not in the pool anymore
+	require.NoError(t, journal.rotate([]journalEntry{entry3}))
+	require.NoError(t, journal.close())
+	entries, err = journal.load()
+	require.NoError(t, err)
+	require.Equal(t, []journalEntry{entry3}, entries)
+
+	// a torn write at the tail keeps the entries before it
+	require.NoError(t, journal.rotate([]journalEntry{entry1, entry2}))
+	require.NoError(t, journal.close())
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	require.NoError(t, err)
+	_, err = f.Write([]byte{0xf8, 0xff, 0x01})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	entries, err = journal.load()
+	require.Error(t, err)
+	require.Equal(t, []journalEntry{entry1, entry2}, entries)
+}
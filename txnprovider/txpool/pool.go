@@ -84,6 +84,8 @@ type Pool interface {
 	FilterKnownIdHashes(tx kv.Tx, hashes Hashes) (unknownHashes Hashes, err error)
 	Started() bool
 	GetRlp(tx kv.Tx, hash []byte) ([]byte, error)
+	// GetBlobs returns the blobs and their proofs, the proof of a blob of a txn wrapped with cell proofs
+	// is the concatenation of its kzg.CellsPerBlob cell proofs. Blobs missing in the pool are left nil.
 	GetBlobs(blobhashes []common.Hash) ([][]byte, [][]byte)
 	AddNewGoodPeer(peerID PeerID)
 }
@@ -121,6 +123,9 @@ type TxPool struct {
 	minedBlobTxnsByBlock    map[uint64][]*metaTxn            // (blockNum => slice): cache of recently mined blobs
 	minedBlobTxnsByHash     map[string]*metaTxn              // (hash => mt): map of recently mined blobs
 	isLocalLRU              *simplelru.LRU[string, struct{}] // txn_hash => is_local : to restore isLocal flag of unwinded transactions
+	localSenders            map[common.Address]struct{}      // senders whose txns are treated as local, even if received from p2p
+	journal                 *localsJournal                   // persists local txns across restarts, nil if disabled
+	rebroadcastingLocals    atomic.Bool                      // a rebroadcast of the local txns is in progress
 	newPendingTxns          chan Announcements               // notifications about new txns in Pending sub-pool
 	all                     *BySenderAndNonce                // senderID => (sorted map of txn nonce => *metaTxn)
 	deletedTxns             []*metaTxn                       // list of discarded txns since last db commit
@@ -143,6 +148,8 @@ type TxPool struct {
 	isPostCancun            atomic.Bool
 	pragueTime              *uint64
 	isPostPrague            atomic.Bool
+	osakaTime               *uint64
+	isPostOsaka             atomic.Bool
 	blobSchedule            *chain.BlobSchedule
 	feeCalculator           FeeCalculator
 	p2pFetcher              *Fetch
@@ -178,6 +185,7 @@ func New(
 	agraBlock *big.Int,
 	cancunTime *big.Int,
 	pragueTime *big.Int,
+	osakaTime *big.Int,
 	blobSchedule *chain.BlobSchedule,
 	sentryClients []sentryproto.SentryClient,
 	stateChangesClient StateChangesClient,
@@ -207,6 +215,10 @@ func New(
 	for _, sender := range cfg.TracedSenders {
 		tracedSenders[common.BytesToAddress([]byte(sender))] = struct{}{}
 	}
+	localSenders := make(map[common.Address]struct{}, len(cfg.Locals))
+	for _, sender := range cfg.Locals {
+		localSenders[common.BytesToAddress([]byte(sender))] = struct{}{}
+	}
 
 	lock := &sync.Mutex{}
 
@@ -215,6 +227,7 @@ func New(
 		lastSeenCond:            sync.NewCond(lock),
 		byHash:                  map[string]*metaTxn{},
 		isLocalLRU:              localsHistory,
+		localSenders:            localSenders,
 		discardReasonsLRU:       discardHistory,
 		all:                     byNonce,
 		recentlyConnectedPeers:  &recentlyConnectedPeers{},
@@ -273,6 +286,17 @@ func New(
 		pragueTimeU64 := pragueTime.Uint64()
 		res.pragueTime = &pragueTimeU64
 	}
+	if osakaTime != nil {
+		if !osakaTime.IsUint64() {
+			return nil, errors.New("osakaTime overflow")
+		}
+		osakaTimeU64 := osakaTime.Uint64()
+		res.osakaTime = &osakaTimeU64
+	}
+
+	if cfg.Journal != "" {
+		res.journal = newLocalsJournal(cfg.Journal)
+	}
 
 	res.p2pFetcher = NewFetch(ctx, sentryClients, res, stateChangesClient, poolDB, chainID, logger, opts...)
 	res.p2pSender = NewSend(ctx, sentryClients, logger, opts...)
@@ -285,7 +309,10 @@ func (p *TxPool) start(ctx context.Context) error {
 		return nil
 	}
 
-	return p.poolDB.View(ctx, func(tx kv.Tx) error {
+	// journaled txns must be known as local before the pool db is loaded, as they may be there too
+	journaled := p.loadJournal()
+
+	err := p.poolDB.View(ctx, func(tx kv.Tx) error {
 		coreDb, _ := p.chainDB()
 		coreTx, err := coreDb.BeginTemporalRo(ctx)
 		if err != nil {
@@ -304,6 +331,116 @@ func (p *TxPool) start(ctx context.Context) error {
 
 		return nil
 	})
+	if err != nil || p.journal == nil {
+		return err
+	}
+
+	if len(journaled.Txns) > 0 {
+		reasons, err := p.AddLocalTxns(ctx, journaled)
+		if err != nil {
+			return fmt.Errorf("adding journaled txns: %w", err)
+		}
+		var added int
+		for _, reason := range reasons {
+			if reason == txpoolcfg.Success || reason == txpoolcfg.DuplicateHash {
+				added++
+			}
+		}
+		p.logger.Info("[txpool] Loaded local txns journal", "txns", len(journaled.Txns), "added", added)
+	}
+
+	return p.rotateJournal(ctx)
+}
+
+// loadJournal parses the journaled txns and marks them as local
+func (p *TxPool) loadJournal() TxnSlots {
+	var txns TxnSlots
+	if p.journal == nil {
+		return txns
+	}
+
+	entries, err := p.journal.load()
+	if err != nil {
+		// keep what has been read, the journal gets regenerated anyway
+		p.logger.Warn("[txpool] Damaged local txns journal", "path", p.journal.path, "err", err)
+	}
+
+	parseCtx := NewTxnParseContext(p.chainID)
+	parseCtx.WithSender(false)
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for _, entry := range entries {
+		txn := &TxnSlot{}
+		if _, err := parseCtx.ParseTransaction(entry.Rlp, 0, txn, nil, false /* hasEnvelope */, true /* wrappedWithBlobs */, nil); err != nil {
+			p.logger.Warn("[txpool] Skipping journaled txn", "err", err)
+			continue
+		}
+		i := len(txns.Txns)
+		txns.Resize(uint(i + 1))
+		txns.Txns[i] = txn
+		txns.IsLocal[i] = true
+		copy(txns.Senders.At(i), entry.Sender[:])
+		p.isLocalLRU.Add(string(txn.IDHash[:]), struct{}{})
+	}
+	return txns
+}
+
+// rotateJournal regenerates the journal from the local txns in the pool, dropping the included
+// and discarded ones
+func (p *TxPool) rotateJournal(ctx context.Context) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	var entries []journalEntry
+	if err := p.poolDB.View(ctx, func(tx kv.Tx) error {
+		var err error
+		// by sender and nonce, so that txns are re-added in order after a restart
+		p.all.ascendAll(func(mt *metaTxn) bool {
+			if mt.subPool&IsLocal == 0 {
+				return true
+			}
+			var txnRlp []byte
+			var sender common.Address
+			txnRlp, sender, _, err = p.getRlpLocked(tx, mt.TxnSlot.IDHash[:])
+			if err != nil {
+				return false
+			}
+			if len(txnRlp) > 0 {
+				entries = append(entries, journalEntry{Sender: sender, Rlp: common.Copy(txnRlp)})
+			}
+			return true
+		})
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if err := p.journal.rotate(entries); err != nil {
+		return fmt.Errorf("rotating local txns journal: %w", err)
+	}
+	p.logger.Debug("[txpool] Regenerated local txns journal", "txns", len(entries))
+	return nil
+}
+
+// journalLocked appends a newly added local txn to the journal
+func (p *TxPool) journalLocked(txn *TxnSlot, sender []byte) {
+	if p.journal == nil {
+		return
+	}
+	err := p.journal.insert(journalEntry{Sender: common.BytesToAddress(sender), Rlp: txn.Rlp})
+	if err != nil && !errors.Is(err, errNoActiveJournal) {
+		p.logger.Warn("[txpool] Failed to journal local txn", "idHash", fmt.Sprintf("%x", txn.IDHash), "err", err)
+	}
+}
+
+func (p *TxPool) isLocalSender(addr []byte) bool {
+	if len(p.localSenders) == 0 {
+		return false
+	}
+	_, ok := p.localSenders[common.BytesToAddress(addr)]
+	return ok
 }
 
 func (p *TxPool) OnNewBlock(ctx context.Context, stateChanges *remote.StateChangeBatch, unwindTxns, unwindBlobTxns, minedTxns TxnSlots) error {
@@ -502,6 +639,12 @@ func (p *TxPool) processRemoteTxns(ctx context.Context) (err error) {
 		return err
 	}
 
+	for i := range p.unprocessedRemoteTxns.Txns {
+		if p.isLocalSender(p.unprocessedRemoteTxns.Senders.At(i)) {
+			p.unprocessedRemoteTxns.IsLocal[i] = true
+		}
+	}
+
 	_, newTxns, err := p.validateTxns(p.unprocessedRemoteTxns, cacheView)
 	if err != nil {
 		return err
@@ -542,7 +685,7 @@ func (p *TxPool) processRemoteTxns(ctx context.Context) (err error) {
 				AccessListAddrCount: txn.AccessListAddrCount,
 				AccessListStorCount: txn.AccessListStorCount,
 				BlobHashes:          txn.BlobHashes,
-				IsLocal:             false,
+				IsLocal:             newTxns.IsLocal[i],
 				DiscardReason:       reason.String(),
 				Pool:                subpool,
 				OrderMarker:         uint8(orderMarker),
@@ -557,6 +700,9 @@ func (p *TxPool) processRemoteTxns(ctx context.Context) (err error) {
 			if txn.Traced {
 				p.logger.Info(fmt.Sprintf("TX TRACING: processRemoteTxns promotes idHash=%x, senderId=%d", txn.IDHash, txn.SenderID))
 			}
+			if newTxns.IsLocal[i] {
+				p.journalLocked(txn, newTxns.Senders.At(i))
+			}
 			p.promoted.Append(txn.Type, txn.Size, txn.IDHash[:])
 		}
 	}
@@ -743,6 +889,9 @@ func (p *TxPool) IsLocal(idHash []byte) bool {
 	hashS := string(idHash)
 	p.lock.Lock()
 	defer p.lock.Unlock()
+	if mt, ok := p.byHash[hashS]; ok && mt.subPool&IsLocal != 0 {
+		return true
+	}
 	return p.isLocalLRU.Contains(hashS)
 }
 
@@ -951,9 +1100,18 @@ func (p *TxPool) validateTx(txn *TxnSlot, isLocal bool, stateCache kvcache.Cache
 		if blobCount > p.GetMaxBlobsPerBlock() {
 			return txpoolcfg.TooManyBlobs
 		}
+		// EIP-7594: blob proofs are replaced with cell proofs starting from Osaka
+		isOsaka := p.isOsaka()
+		if isOsaka != (txn.BlobWrapperVersion == types.BlobWrapperVersion1) {
+			return txpoolcfg.WrongBlobWrapper
+		}
+		proofsPerBlob := 1
+		if isOsaka {
+			proofsPerBlob = libkzg.CellsPerBlob
+		}
 		equalNumber := len(txn.BlobHashes) == len(txn.Blobs) &&
 			len(txn.Blobs) == len(txn.Commitments) &&
-			len(txn.Commitments) == len(txn.Proofs)
+			len(txn.Commitments)*proofsPerBlob == len(txn.Proofs)
 
 		if !equalNumber {
 			return txpoolcfg.UnequalBlobTxExt
@@ -965,9 +1123,14 @@ func (p *TxPool) validateTx(txn *TxnSlot, isLocal bool, stateCache kvcache.Cache
 			}
 		}
 
-		// https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#verify_blob_kzg_proof_batch
-		kzgCtx := libkzg.Ctx()
-		err := kzgCtx.VerifyBlobKZGProofBatch(toBlobs(txn.Blobs), txn.Commitments, txn.Proofs)
+		var err error
+		if isOsaka {
+			// https://github.com/ethereum/consensus-specs/blob/dev/specs/fulu/polynomial-commitments-sampling.md#verify_cell_kzg_proof_batch
+			err = libkzg.VerifyCellProofBatch(txn.Blobs, txn.Commitments, txn.Proofs)
+		} else {
+			// https://github.com/ethereum/consensus-specs/blob/017a8495f7671f5fff2075a9bfc9238c1a0982f8/specs/deneb/polynomial-commitments.md#verify_blob_kzg_proof_batch
+			err = libkzg.Ctx().VerifyBlobKZGProofBatch(toBlobs(txn.Blobs), txn.Commitments, txn.Proofs)
+		}
 		if err != nil {
 			return txpoolcfg.UnmatchedBlobTxExt
 		}
@@ -1181,6 +1344,10 @@ func (p *TxPool) isPrague() bool {
 	return isTimeBasedForkActivated(&p.isPostPrague, p.pragueTime)
 }
 
+func (p *TxPool) isOsaka() bool {
+	return isTimeBasedForkActivated(&p.isPostOsaka, p.osakaTime)
+}
+
 func (p *TxPool) GetMaxBlobsPerBlock() uint64 {
 	return p.blobSchedule.MaxBlobsPerBlock(p.isPrague())
 }
@@ -1330,6 +1497,19 @@ func (p *TxPool) AddLocalTxns(ctx context.Context, newTxns TxnSlots) ([]txpoolcf
 		return nil, err
 	}
 
+	if p.isOsaka() {
+		// local blob txns may still come with blob proofs, computing cell proofs is expensive so do it outside the lock
+		for _, txn := range newTxns.Txns {
+			if txn.Type != BlobTxnType || txn.BlobWrapperVersion != types.BlobWrapperVersion0 || len(txn.Blobs) == 0 {
+				continue
+			}
+			if err := txn.ConvertToCellProofs(); err != nil {
+				// validateTx rejects it due to the wrapper version
+				p.logger.Debug("[txpool] failed to compute cell proofs", "idHash", fmt.Sprintf("%x", txn.IDHash), "err", err)
+			}
+		}
+	}
+
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
@@ -1363,6 +1543,7 @@ func (p *TxPool) AddLocalTxns(ctx context.Context, newTxns TxnSlots) ([]txpoolcf
 			if txn.Traced {
 				p.logger.Info(fmt.Sprintf("TX TRACING: AddLocalTxns promotes idHash=%x, senderId=%d", txn.IDHash, txn.SenderID))
 			}
+			p.journalLocked(txn, newTxns.Senders.At(i))
 			p.promoted.Append(txn.Type, txn.Size, txn.IDHash[:])
 		}
 	}
@@ -1695,6 +1876,14 @@ func (p *TxPool) getBlobsAndProofByBlobHashLocked(blobHashes []common.Hash) ([][
 			continue
 		}
 		blobs[i] = mt.TxnSlot.Blobs[th.index]
+		if mt.TxnSlot.BlobWrapperVersion == types.BlobWrapperVersion1 {
+			cellProofs := mt.TxnSlot.Proofs[th.index*libkzg.CellsPerBlob : (th.index+1)*libkzg.CellsPerBlob]
+			proofs[i] = make([]byte, 0, len(cellProofs)*len(gokzg4844.KZGProof{}))
+			for _, proof := range cellProofs {
+				proofs[i] = append(proofs[i], proof[:]...)
+			}
+			continue
+		}
 		proofs[i] = mt.TxnSlot.Proofs[th.index][:]
 	}
 	return blobs, proofs
@@ -2038,10 +2227,19 @@ func (p *TxPool) promote(pendingBaseFee uint64, pendingBlobFee uint64, announcem
 	// Discard worst transactions from the queued sub pool if they do not qualify
 	// <FUNCTIONALITY REMOVED>
 
+	// Local transactions are exempt from the overflow eviction as long as they fit in the sub pool: they are
+	// set aside while the worst remote ones are discarded and put back afterwards. Once the locals alone exceed
+	// the limit, the worst of them are discarded too.
+	var locals []*metaTxn
+
 	// Discard worst transactions from pending pool until it is within capacity limit
-	for p.pending.Len() > p.pending.limit {
+	for p.pending.Len() > 0 && p.pending.Len()+len(locals) > p.pending.limit {
 		tx := p.pending.PopWorst()
-		p.discardLocked(p.pending.PopWorst(), txpoolcfg.PendingPoolOverflow)
+		if tx.subPool&IsLocal != 0 && len(locals) < p.pending.limit {
+			locals = append(locals, tx)
+			continue
+		}
+		p.discardLocked(tx, txpoolcfg.PendingPoolOverflow)
 		sendChangeBatchEventToDiagnostics("Pending", "remove", []diagnostics.TxnHashOrder{
 			{
 				OrderMarker: uint8(tx.subPool),
@@ -2049,10 +2247,18 @@ func (p *TxPool) promote(pendingBaseFee uint64, pendingBlobFee uint64, announcem
 			},
 		})
 	}
+	for _, tx := range locals {
+		p.pending.Add(tx, logger)
+	}
 
 	// Discard worst transactions from pending sub pool until it is within capacity limits
-	for p.baseFee.Len() > p.baseFee.limit {
+	locals = locals[:0]
+	for p.baseFee.Len() > 0 && p.baseFee.Len()+len(locals) > p.baseFee.limit {
 		tx := p.baseFee.PopWorst()
+		if tx.subPool&IsLocal != 0 && len(locals) < p.baseFee.limit {
+			locals = append(locals, tx)
+			continue
+		}
 		p.discardLocked(tx, txpoolcfg.BaseFeePoolOverflow)
 		sendChangeBatchEventToDiagnostics("BaseFee", "remove", []diagnostics.TxnHashOrder{
 			{
@@ -2061,10 +2267,18 @@ func (p *TxPool) promote(pendingBaseFee uint64, pendingBlobFee uint64, announcem
 			},
 		})
 	}
+	for _, tx := range locals {
+		p.baseFee.Add(tx, "keep-local", logger)
+	}
 
 	// Discard worst transactions from the queued sub pool until it is within its capacity limits
-	for _ = p.queued.Worst(); p.queued.Len() > p.queued.limit; _ = p.queued.Worst() {
+	locals = locals[:0]
+	for p.queued.Len() > 0 && p.queued.Len()+len(locals) > p.queued.limit {
 		tx := p.queued.PopWorst()
+		if tx.subPool&IsLocal != 0 && len(locals) < p.queued.limit {
+			locals = append(locals, tx)
+			continue
+		}
 		p.discardLocked(tx, txpoolcfg.QueuedPoolOverflow)
 		sendChangeBatchEventToDiagnostics("Queued", "remove", []diagnostics.TxnHashOrder{
 			{
@@ -2073,6 +2287,9 @@ func (p *TxPool) promote(pendingBaseFee uint64, pendingBlobFee uint64, announcem
 			},
 		})
 	}
+	for _, tx := range locals {
+		p.queued.Add(tx, "keep-local", logger)
+	}
 }
 
 // Run - does:
@@ -2097,6 +2314,26 @@ func (p *TxPool) Run(ctx context.Context) error {
 	defer commitEvery.Stop()
 	logEvery := time.NewTicker(p.cfg.LogEvery)
 	defer logEvery.Stop()
+	// a zero interval disables the task
+	var rebroadcastLocalsEvery <-chan time.Time
+	if p.cfg.RebroadcastLocalsEvery > 0 {
+		rebroadcastLocalsTicker := time.NewTicker(p.cfg.RebroadcastLocalsEvery)
+		defer rebroadcastLocalsTicker.Stop()
+		rebroadcastLocalsEvery = rebroadcastLocalsTicker.C
+	}
+	var rejournalEvery <-chan time.Time
+	if p.journal != nil {
+		if p.cfg.RejournalEvery > 0 {
+			rejournalTicker := time.NewTicker(p.cfg.RejournalEvery)
+			defer rejournalTicker.Stop()
+			rejournalEvery = rejournalTicker.C
+		}
+		defer func() {
+			if err := p.journal.close(); err != nil {
+				p.logger.Warn("[txpool] Failed to close local txns journal", "err", err)
+			}
+		}()
+	}
 
 	if err := p.start(ctx); err != nil {
 		p.logger.Error("[txpool] Failed to start", "err", err)
@@ -2114,6 +2351,25 @@ func (p *TxPool) Run(ctx context.Context) error {
 			return err
 		case <-logEvery.C:
 			p.logStats()
+		case <-rejournalEvery:
+			if !p.Started() {
+				continue
+			}
+			if err := p.rotateJournal(ctx); err != nil {
+				p.logger.Error("[txpool] rotate local txns journal", "err", err)
+			}
+		case <-rebroadcastLocalsEvery:
+			if !p.Started() || p.cfg.NoGossip {
+				continue
+			}
+			// skip the round if the previous one is still sending to slow peers
+			if !p.rebroadcastingLocals.CompareAndSwap(false, true) {
+				continue
+			}
+			go func() {
+				defer p.rebroadcastingLocals.Store(false)
+				p.rebroadcastLocals(ctx)
+			}()
 		case <-processRemoteTxnsEvery.C:
 			if !p.Started() {
 				continue
@@ -2263,6 +2519,54 @@ func (p *TxPool) Run(ctx context.Context) error {
 	}
 }
 
+// rebroadcastLocals re-sends the pending local txns to random peers, so that they are not lost
+// if the first propagation didn't make it, until they are included
+func (p *TxPool) rebroadcastLocals(ctx context.Context) {
+	var txnTypes []byte
+	var txnSizes []uint32
+	var txnHashes Hashes
+	p.lock.Lock()
+	for hash, mt := range p.byHash {
+		if mt.subPool&IsLocal == 0 || mt.currentSubPool != PendingSubPool {
+			continue
+		}
+		txnTypes = append(txnTypes, mt.TxnSlot.Type)
+		txnSizes = append(txnSizes, mt.TxnSlot.Size)
+		txnHashes = append(txnHashes, hash...)
+	}
+	p.lock.Unlock()
+	if len(txnTypes) == 0 {
+		return
+	}
+
+	var txnRlps [][]byte
+	if err := p.poolDB.View(ctx, func(tx kv.Tx) error {
+		for i, t := range txnTypes {
+			// "Nodes MUST NOT automatically broadcast blob transactions to their peers" - EIP-4844
+			if t == BlobTxnType {
+				continue
+			}
+			slotRlp, err := p.GetRlp(tx, txnHashes.At(i))
+			if err != nil {
+				return err
+			}
+			if len(slotRlp) == 0 {
+				continue
+			}
+			txnRlps = append(txnRlps, slotRlp)
+		}
+		return nil
+	}); err != nil {
+		p.logger.Error("[txpool] collect local txns to rebroadcast", "err", err)
+		return
+	}
+
+	const localTxnsBroadcastMaxPeers uint64 = 10
+	p.p2pSender.BroadcastPooledTxns(txnRlps, localTxnsBroadcastMaxPeers)
+	p.p2pSender.AnnouncePooledTxns(txnTypes, txnSizes, txnHashes, localTxnsBroadcastMaxPeers*2)
+	p.logger.Debug("[txpool] Rebroadcast local txns", "txns", len(txnTypes))
+}
+
 func (p *TxPool) flushNoFsync(ctx context.Context) (written uint64, err error) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
@@ -2465,7 +2769,7 @@ func (p *TxPool) fromDB(ctx context.Context, tx kv.Tx, coreTx kv.TemporalTx) err
 		txn.Rlp = nil // means that we don't need store it in db anymore
 
 		txn.SenderID, txn.Traced = p.senders.getOrCreateID(addr, p.logger)
-		isLocalTx := p.isLocalLRU.Contains(string(k))
+		isLocalTx := p.isLocalLRU.Contains(string(k)) || p.isLocalSender(addr[:])
 
 		if reason := p.validateTx(txn, isLocalTx, cacheView); reason != txpoolcfg.NotSet && reason != txpoolcfg.Success {
 			return nil // TODO: Clarify - if one of the txns has the wrong reason, no pooled txns!
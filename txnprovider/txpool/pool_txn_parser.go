@@ -37,6 +37,7 @@ import (
 	"github.com/erigontech/erigon-lib/common/length"
 	"github.com/erigontech/erigon-lib/common/u256"
 	"github.com/erigontech/erigon-lib/crypto"
+	libkzg "github.com/erigontech/erigon-lib/crypto/kzg"
 	"github.com/erigontech/erigon-lib/gointerfaces/typesproto"
 	"github.com/erigontech/erigon-lib/rlp"
 	"github.com/erigontech/erigon-lib/types"
@@ -198,6 +199,24 @@ func (ctx *TxnParseContext) ParseTransaction(payload []byte, pos int, slot *TxnS
 			return 0, fmt.Errorf("%w: unexpected leftover after blob txn body", ErrParseTxn)
 		}
 
+		// EIP-7594: the cell proofs form has the wrapper version after the txn body
+		var isList bool
+		_, _, isList, err = rlp.Prefix(payload, p)
+		if err != nil {
+			return 0, fmt.Errorf("%w: blob wrapper: %s", ErrParseTxn, err) //nolint
+		}
+		if !isList {
+			var version uint64
+			p, version, err = rlp.ParseU64(payload, p)
+			if err != nil {
+				return 0, fmt.Errorf("%w: blob wrapper version: %s", ErrParseTxn, err) //nolint
+			}
+			if version != uint64(types.BlobWrapperVersion1) {
+				return 0, fmt.Errorf("%w: unsupported blob wrapper version %d", ErrParseTxn, version)
+			}
+			slot.BlobWrapperVersion = types.BlobWrapperVersion1
+		}
+
 		dataPos, dataLen, err = rlp.ParseList(payload, p)
 		if err != nil {
 			return 0, fmt.Errorf("%w: blobs len: %s", ErrParseTxn, err) //nolint
@@ -845,7 +864,9 @@ type TxnSlot struct {
 	BlobHashes  []common.Hash
 	Blobs       [][]byte
 	Commitments []gokzg4844.KZGCommitment
-	Proofs      []gokzg4844.KZGProof
+	Proofs      []gokzg4844.KZGProof // one per blob, or kzg.CellsPerBlob per blob for BlobWrapperVersion1
+
+	BlobWrapperVersion byte // EIP-7594: types.BlobWrapperVersion0 (blob proofs) or types.BlobWrapperVersion1 (cell proofs)
 
 	AuthAndNonces []AuthAndNonce // Indexed authorization signers + nonces for EIP-7702 txns (type-4)
 
@@ -861,6 +882,81 @@ func (tx *TxnSlot) PrintDebug(prefix string) {
 	//fmt.Printf("%s: senderID=%d,nonce=%d,tip=%d,hash=%x\n", prefix, tx.senderID, tx.nonce, tx.tip, tx.IdHash)
 }
 
+// ConvertToCellProofs replaces the blob proofs of a blob txn wrapped in the EIP-4844 form with
+// the cell proofs of its blobs and re-encodes Rlp in the EIP-7594 form
+// [tx_payload_body, wrapper_version, blobs, commitments, cell_proofs]. The txn hash doesn't change.
+func (tx *TxnSlot) ConvertToCellProofs() error {
+	if tx.Type != BlobTxnType || tx.BlobWrapperVersion != types.BlobWrapperVersion0 {
+		return fmt.Errorf("expected blob txn with blob proofs, got type %d version %d", tx.Type, tx.BlobWrapperVersion)
+	}
+	if len(tx.Blobs) == 0 || len(tx.Blobs) != len(tx.Commitments) {
+		return fmt.Errorf("expected wrapped blob txn, got %d blobs and %d commitments", len(tx.Blobs), len(tx.Commitments))
+	}
+	proofs := make([]gokzg4844.KZGProof, 0, len(tx.Blobs)*libkzg.CellsPerBlob)
+	for _, blob := range tx.Blobs {
+		cellProofs, err := libkzg.ComputeCellProofs(blob)
+		if err != nil {
+			return err
+		}
+		proofs = append(proofs, cellProofs...)
+	}
+
+	// blobs and commitments are kept as is, only the proofs are replaced
+	wrapperPos, _, err := rlp.ParseList(tx.Rlp, 1)
+	if err != nil {
+		return fmt.Errorf("%w: wrapped blob txn: %s", ErrParseTxn, err) //nolint
+	}
+	bodyPos, bodyLen, err := rlp.ParseList(tx.Rlp, wrapperPos)
+	if err != nil {
+		return fmt.Errorf("%w: blob txn body: %s", ErrParseTxn, err) //nolint
+	}
+	blobsPos, blobsLen, err := rlp.ParseList(tx.Rlp, bodyPos+bodyLen)
+	if err != nil {
+		return fmt.Errorf("%w: blobs len: %s", ErrParseTxn, err) //nolint
+	}
+	commitmentsPos, commitmentsLen, err := rlp.ParseList(tx.Rlp, blobsPos+blobsLen)
+	if err != nil {
+		return fmt.Errorf("%w: commitments len: %s", ErrParseTxn, err) //nolint
+	}
+	body := tx.Rlp[wrapperPos : bodyPos+bodyLen]
+	blobsAndCommitments := tx.Rlp[bodyPos+bodyLen : commitmentsPos+commitmentsLen]
+
+	proofsLen := len(proofs) * (1 + len(gokzg4844.KZGProof{}))
+	payloadLen := len(body) + rlp.U64Len(uint64(types.BlobWrapperVersion1)) + len(blobsAndCommitments) + rlp.ListPrefixLen(proofsLen) + proofsLen
+	enc := make([]byte, 1+rlp.ListPrefixLen(payloadLen)+payloadLen)
+	enc[0] = BlobTxnType
+	pos := 1 + rlp.EncodeListPrefix(payloadLen, enc[1:])
+	pos += copy(enc[pos:], body)
+	pos += rlp.EncodeU64(uint64(types.BlobWrapperVersion1), enc[pos:])
+	pos += copy(enc[pos:], blobsAndCommitments)
+	pos += rlp.EncodeListPrefix(proofsLen, enc[pos:])
+	for i := range proofs {
+		pos += rlp.EncodeString2(proofs[i][:], enc[pos:])
+	}
+	if pos != len(enc) {
+		return fmt.Errorf("blob wrapper encoding: wrote %d bytes, expected %d", pos, len(enc))
+	}
+
+	// Blobs alias the previous encoding, re-point them to the new one
+	blobPos, _, err := rlp.ParseList(enc, 1+rlp.ListPrefixLen(payloadLen)+len(body)+rlp.U64Len(uint64(types.BlobWrapperVersion1)))
+	if err != nil {
+		return fmt.Errorf("%w: blobs len: %s", ErrParseTxn, err) //nolint
+	}
+	for i := range tx.Blobs {
+		if blobPos, err = rlp.StringOfLen(enc, blobPos, params.BlobSize); err != nil {
+			return fmt.Errorf("%w: blob: %s", ErrParseTxn, err) //nolint
+		}
+		tx.Blobs[i] = enc[blobPos : blobPos+params.BlobSize]
+		blobPos += params.BlobSize
+	}
+
+	tx.Rlp = enc
+	tx.Size = uint32(len(enc))
+	tx.Proofs = proofs
+	tx.BlobWrapperVersion = types.BlobWrapperVersion1
+	return nil
+}
+
 // ToProtoAccountAbstractionTxn converts a TxnSlot to a typesproto.AccountAbstractionTransaction
 func (tx *TxnSlot) ToProtoAccountAbstractionTxn() *typesproto.AccountAbstractionTransaction {
 	if tx == nil {
@@ -30,6 +30,7 @@ import (
 	"github.com/erigontech/erigon-lib/chain/params"
 	"github.com/erigontech/erigon-lib/common"
 	"github.com/erigontech/erigon-lib/common/hexutil"
+	"github.com/erigontech/erigon-lib/crypto/kzg"
 	"github.com/erigontech/erigon-lib/rlp"
 	"github.com/erigontech/erigon-lib/types"
 	"github.com/erigontech/erigon-lib/types/testdata"
@@ -297,6 +298,53 @@ func TestBlobTxnParsing(t *testing.T) {
 	assert.Equal(t, proof1, fatTxn.Proofs[1])
 }
 
+func TestBlobTxnConvertToCellProofs(t *testing.T) {
+	blobTxn := makeBlobTxn()
+	idHash := blobTxn.IDHash
+	blobs := common.CopyBytes(bytes.Join(blobTxn.Blobs, nil))
+	require.Equal(t, types.BlobWrapperVersion0, blobTxn.BlobWrapperVersion)
+	require.Len(t, blobTxn.Proofs, 2)
+
+	require.NoError(t, blobTxn.ConvertToCellProofs())
+	require.Equal(t, types.BlobWrapperVersion1, blobTxn.BlobWrapperVersion)
+	require.Len(t, blobTxn.Proofs, 2*kzg.CellsPerBlob)
+	require.Equal(t, blobs, bytes.Join(blobTxn.Blobs, nil))
+	require.Equal(t, len(blobTxn.Rlp), int(blobTxn.Size))
+	require.Error(t, blobTxn.ConvertToCellProofs())
+
+	// the new encoding parses back to the same txn
+	ctx := NewTxnParseContext(*uint256.NewInt(5))
+	ctx.WithSender(false)
+	var parsed TxnSlot
+	p, err := ctx.ParseTransaction(blobTxn.Rlp, 0, &parsed, nil, false /* hasEnvelope */, true /* wrappedWithBlobs */, nil)
+	require.NoError(t, err)
+	require.Equal(t, len(blobTxn.Rlp), p)
+	require.Equal(t, idHash, parsed.IDHash)
+	require.Equal(t, types.BlobWrapperVersion1, parsed.BlobWrapperVersion)
+	require.Equal(t, blobTxn.Blobs, parsed.Blobs)
+	require.Equal(t, blobTxn.Commitments, parsed.Commitments)
+	require.Equal(t, blobTxn.Proofs, parsed.Proofs)
+
+	// and is understood by the block builder
+	txn, err := types.DecodeWrappedTransaction(blobTxn.Rlp)
+	require.NoError(t, err)
+	wrapper, ok := txn.(*types.BlobTxWrapper)
+	require.True(t, ok)
+	require.Equal(t, types.BlobWrapperVersion1, wrapper.WrapperVersion)
+	require.NoError(t, wrapper.ValidateBlobTransactionWrapper())
+	var buf bytes.Buffer
+	require.NoError(t, wrapper.MarshalBinaryWrapped(&buf))
+	require.Equal(t, blobTxn.Rlp, buf.Bytes())
+
+	// unsupported wrapper version
+	bad := common.Copy(blobTxn.Rlp)
+	versionPos := bytes.Index(bad, blobTxn.Blobs[0]) - 4 /* blob prefix */ - 4 /* blobs prefix */ - 1
+	require.Equal(t, types.BlobWrapperVersion1, bad[versionPos])
+	bad[versionPos] = 2
+	_, err = ctx.ParseTransaction(bad, 0, &TxnSlot{}, nil, false /* hasEnvelope */, true /* wrappedWithBlobs */, nil)
+	require.ErrorIs(t, err, ErrParseTxn)
+}
+
 func TestSetCodeAuthSignatureRecover(t *testing.T) {
 	txnRlpHex := testdata.ValidSetCodeTxn1
 	// For authorizationList[0] in the above :-
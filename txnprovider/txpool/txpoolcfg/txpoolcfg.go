@@ -33,6 +33,8 @@ type Config struct {
 	Disable             bool
 	DBDir               string
 	TracedSenders       []string // List of senders for which txn pool should print out debugging info
+	Locals              []string // List of senders whose txns are treated as local, same as txns received via RPC
+	Journal             string   // Path of the journal of local txns to survive node restarts, empty disables it
 	PendingSubPoolLimit int
 	BaseFeeSubPoolLimit int
 	QueuedSubPoolLimit  int
@@ -49,6 +51,8 @@ type Config struct {
 	ProcessRemoteTxnsEvery time.Duration
 	CommitEvery            time.Duration
 	LogEvery               time.Duration
+	RejournalEvery         time.Duration // how often the journal is regenerated from the local txns in the pool
+	RebroadcastLocalsEvery time.Duration // how often pending local txns are re-broadcast until they are included
 
 	//txpool db
 	MdbxPageSize    datasize.ByteSize
@@ -67,6 +71,8 @@ var DefaultConfig = Config{
 	ProcessRemoteTxnsEvery: 100 * time.Millisecond,
 	CommitEvery:            15 * time.Second,
 	LogEvery:               30 * time.Second,
+	RejournalEvery:         time.Hour,
+	RebroadcastLocalsEvery: time.Minute,
 
 	PendingSubPoolLimit: 10_000,
 	BaseFeeSubPoolLimit: 30_000,
@@ -123,6 +129,7 @@ const (
 	ErrAuthorityReserved DiscardReason = 34 // EIP-7702 transaction with authority already reserved
 	InvalidAA            DiscardReason = 35 // Invalid RIP-7560 transaction
 	ErrGetCode           DiscardReason = 36 // Error getting code during AA validation
+	WrongBlobWrapper     DiscardReason = 37 // EIP-7594 - blob proofs must be cell proofs after Osaka and blob proofs before
 )
 
 func (r DiscardReason) String() string {
@@ -201,6 +208,8 @@ func (r DiscardReason) String() string {
 		return "RIP-7560 transaction failed validation"
 	case ErrGetCode:
 		return "error getting account code during RIP-7560 validation"
+	case WrongBlobWrapper:
+		return "blob transaction wrapper version doesn't match the active fork"
 	default:
 		panic(fmt.Sprintf("discard reason: %d", r))
 	}